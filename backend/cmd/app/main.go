@@ -5,19 +5,25 @@ import (
 	"log/slog"
 	"os"
 	"os/signal"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
 
 	"github.com/mahcks/serra/config"
+	"github.com/mahcks/serra/internal/db/migrate"
+	"github.com/mahcks/serra/internal/eventbus"
 	"github.com/mahcks/serra/internal/global"
 	"github.com/mahcks/serra/internal/integrations"
 	"github.com/mahcks/serra/internal/jobs"
 	"github.com/mahcks/serra/internal/rest"
+	"github.com/mahcks/serra/internal/secrets"
 	"github.com/mahcks/serra/internal/services/auth"
+	"github.com/mahcks/serra/internal/services/backup"
 	"github.com/mahcks/serra/internal/services/configservice"
 	"github.com/mahcks/serra/internal/services/notifications"
 	"github.com/mahcks/serra/internal/services/sqlite"
+	"github.com/mahcks/serra/migrations"
 	"github.com/mahcks/serra/pkg/structures"
 )
 
@@ -58,6 +64,26 @@ func main() {
 		os.Exit(1)
 	}
 
+	{
+		// Set up encryption for integration secrets stored in the database
+		credentials := bootstrap.Credentials
+		if credentials.EncryptionKey == "" {
+			slog.Error("CREDENTIALS_ENCRYPTION_KEY must be set to encrypt stored secrets")
+			os.Exit(1)
+		}
+		if credentials.EncryptionKey == credentials.JwtSecret {
+			slog.Error("CREDENTIALS_ENCRYPTION_KEY must be different from CREDENTIALS_JWT_SECRET")
+			os.Exit(1)
+		}
+
+		keyring, err := secrets.NewKeyring(credentials.EncryptionKey, strings.Split(credentials.PreviousEncryptionKeys, ",")...)
+		if err != nil {
+			slog.Error("Failed to initialize secrets keyring", "error", err)
+			os.Exit(1)
+		}
+		secrets.SetDefault(keyring)
+	}
+
 	// Create global context with all services
 	gctx, cancel := global.WithCancel(global.New(
 		context.Background(),
@@ -79,6 +105,41 @@ func main() {
 		slog.Info("setup service", "service", "sqlite")
 	}
 
+	{
+		// Initialize backup service
+		gctx.Crate().Backup, err = backup.New(gctx.Crate().Sqlite, gctx.Bootstrap().Backup.Directory)
+		if err != nil {
+			slog.Error("Failed to initialize backup service", "error", err)
+			os.Exit(1)
+		}
+		slog.Info("setup service", "service", "backup")
+	}
+
+	{
+		// Apply pending schema migrations before anything reads the database
+		slog.Info("migrations", "status", "starting")
+		applied, err := migrate.Run(gctx, gctx.Crate().Sqlite.DB(), migrations.FS, func(ctx context.Context) error {
+			_, err := gctx.Crate().Backup.Create(ctx, structures.BackupKindPreMigrate)
+			return err
+		})
+		if err != nil {
+			slog.Error("Failed to migrate database", "error", err, "applied", applied)
+			os.Exit(1)
+		}
+		slog.Info("setup service", "service", "migrations", "applied", len(applied))
+	}
+
+	{
+		// Encrypt secrets stored before encryption existed and re-encrypt any
+		// sealed with a previous key
+		resealed, err := secrets.Reseal(gctx, gctx.Crate().Sqlite.DB(), secrets.Default())
+		if err != nil {
+			slog.Error("Failed to encrypt stored secrets", "error", err)
+			os.Exit(1)
+		}
+		slog.Info("setup service", "service", "secrets", "resealed", resealed)
+	}
+
 	{
 		// Initialize config service
 		slog.Info("config", "status", "starting")
@@ -106,6 +167,23 @@ func main() {
 		slog.Info("setup service", "service", "notifications")
 	}
 
+	{
+		// Connect to the event bus shared with the other instances
+		cluster := gctx.Bootstrap().Cluster
+		gctx.Crate().EventBus, err = eventbus.New(gctx, eventbus.Options{
+			Driver:        cluster.EventBus,
+			NodeID:        cluster.NodeID,
+			RedisAddress:  cluster.RedisAddress,
+			RedisPassword: cluster.RedisPassword,
+			RedisChannel:  cluster.RedisChannel,
+		})
+		if err != nil {
+			slog.Error("Failed to connect to event bus", "error", err)
+			os.Exit(1)
+		}
+		slog.Info("setup service", "service", "event bus", "driver", cluster.EventBus, "node_id", gctx.Crate().EventBus.NodeID())
+	}
+
 	// Initialize integration services
 	ints := integrations.New(gctx)
 	slog.Info("setup service", "service", "integrations")
@@ -119,6 +197,12 @@ func main() {
 		structures.JobLibrarySyncFull,
 		structures.JobLibrarySyncIncremental,
 		structures.JobNotificationCleanup,
+		structures.JobRunCleanup,
+		structures.JobDatabaseBackup,
+		structures.JobNewSeasonRequests,
+		structures.JobReleaseWatcher,
+		structures.JobRequestSLA,
+		structures.JobBandwidthManager,
 	)
 	if err != nil {
 		slog.Error("Failed to register jobs", "error", err)
@@ -154,20 +238,24 @@ func main() {
 
 		wg.Wait()
 
+		// Stop job manager, before the database closes so it can release the job lease
+		if err := jobManager.Stop(gctx); err != nil {
+			slog.Error("Failed to stop job manager", "error", err)
+		}
+
 		if gctx.Crate() != nil && gctx.Crate().Sqlite != nil {
 			if err := gctx.Crate().Sqlite.Close(); err != nil {
 				slog.Error("Error closing sqlite connection", "error", err)
 			}
 		}
 
-		// Stop job manager
-		if err := jobManager.Stop(gctx); err != nil {
-			slog.Error("Failed to stop job manager", "error", err)
-		}
-
 		// Shutdown integrations (including background cache)
 		ints.Shutdown()
 
+		if err := gctx.Crate().EventBus.Close(); err != nil {
+			slog.Error("Failed to close event bus", "error", err)
+		}
+
 		close(done)
 	}()
 
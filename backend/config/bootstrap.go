@@ -20,9 +20,31 @@ type Bootstrap struct {
 		Path string `mapstructure:"path" json:"path"`
 	} `mapstructure:"sqlite" json:"sqlite"`
 
+	Backup struct {
+		// Directory where database backups are written, defaults to a "backups"
+		// directory next to the SQLite file
+		Directory string `mapstructure:"directory" json:"directory"`
+	} `mapstructure:"backup" json:"backup"`
+
 	Credentials struct {
 		JwtSecret string `mapstructure:"jwt_secret" json:"jwt_secret"`
+		// EncryptionKey protects integration secrets stored in the database
+		EncryptionKey string `mapstructure:"encryption_key" json:"encryption_key"`
+		// PreviousEncryptionKeys is a comma-separated list of retired keys that are
+		// still accepted while stored secrets are re-encrypted with EncryptionKey
+		PreviousEncryptionKeys string `mapstructure:"previous_encryption_keys" json:"previous_encryption_keys"`
 	} `mapstructure:"credentials" json:"credentials"`
+
+	Cluster struct {
+		// NodeID identifies this instance among the replicas, defaults to the hostname
+		NodeID string `mapstructure:"node_id" json:"node_id"`
+		// EventBus is "memory" for a single instance or "redis" to share events
+		// between replicas
+		EventBus      string `mapstructure:"event_bus" json:"event_bus"`
+		RedisAddress  string `mapstructure:"redis_address" json:"redis_address"`
+		RedisPassword string `mapstructure:"redis_password" json:"redis_password"`
+		RedisChannel  string `mapstructure:"redis_channel" json:"redis_channel"`
+	} `mapstructure:"cluster" json:"cluster"`
 }
 
 // NewBootstrap loads only what's needed to initialize DB
@@ -51,7 +73,15 @@ func NewBootstrap(version string) (*Bootstrap, error) {
 	v.BindEnv("rest.address")
 	v.BindEnv("rest.port")
 	v.BindEnv("sqlite.path")
+	v.BindEnv("backup.directory")
 	v.BindEnv("credentials.jwt_secret")
+	v.BindEnv("credentials.encryption_key")
+	v.BindEnv("credentials.previous_encryption_keys")
+	v.BindEnv("cluster.node_id")
+	v.BindEnv("cluster.event_bus")
+	v.BindEnv("cluster.redis_address")
+	v.BindEnv("cluster.redis_password")
+	v.BindEnv("cluster.redis_channel")
 
 	c := &Bootstrap{}
 	if err := v.Unmarshal(&c); err != nil {
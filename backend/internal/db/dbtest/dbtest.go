@@ -0,0 +1,50 @@
+// Package dbtest opens throwaway databases for tests, migrated to the current
+// schema.
+package dbtest
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/mahcks/serra/internal/db/migrate"
+	"github.com/mahcks/serra/internal/db/repository"
+	"github.com/mahcks/serra/internal/services/sqlite"
+	"github.com/mahcks/serra/migrations"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Open creates a migrated database in a temporary directory, closed when the
+// test ends
+func Open(t testing.TB) (*sql.DB, *repository.Queries) {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "serra.db")+"?_fk=1&_journal_mode=WAL&_busy_timeout=5000")
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := migrate.Run(context.Background(), db, migrations.FS, nil); err != nil {
+		t.Fatalf("migrate database: %v", err)
+	}
+	return db, repository.New(db)
+}
+
+// Service creates a migrated database like Open, wrapped in the service the
+// crate holds
+func Service(t testing.TB) sqlite.Service {
+	t.Helper()
+
+	service, err := sqlite.NewService(filepath.Join(t.TempDir(), "serra.db") + "?_fk=1&_journal_mode=WAL&_busy_timeout=5000")
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	t.Cleanup(func() { service.Close() })
+
+	if _, err := migrate.Run(context.Background(), service.DB(), migrations.FS, nil); err != nil {
+		t.Fatalf("migrate database: %v", err)
+	}
+	return service
+}
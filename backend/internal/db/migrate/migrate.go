@@ -0,0 +1,412 @@
+// Package migrate applies the embedded schema migrations to the SQLite database
+// and tracks which versions have been applied.
+package migrate
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// ErrDatabaseNewer is returned when the database has migrations applied that
+// this binary does not know about, meaning it was written by a newer release
+var ErrDatabaseNewer = errors.New("database schema is newer than this version of serra")
+
+// ErrUntracked is returned when the database already has tables but no record
+// of which migrations produced them
+var ErrUntracked = errors.New("database has tables but no migration history")
+
+// BackupFunc takes a backup of the database before pending migrations run
+type BackupFunc func(ctx context.Context) error
+
+// Migration is a single versioned schema change
+type Migration struct {
+	Version  string
+	Name     string
+	SQL      string
+	Checksum string
+}
+
+const createMigrationsTable = `CREATE TABLE IF NOT EXISTS schema_migrations (
+    version TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    checksum TEXT NOT NULL,
+    execution_ms INTEGER NOT NULL DEFAULT 0,
+    applied_at DATETIME DEFAULT CURRENT_TIMESTAMP
+)`
+
+// Load reads all migrations from fsys, ordered by version
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	migrations := make([]Migration, 0, len(entries))
+	seen := make(map[string]string)
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".sql" {
+			continue
+		}
+
+		version, name, ok := strings.Cut(strings.TrimSuffix(entry.Name(), ".sql"), "_")
+		if !ok || version == "" {
+			return nil, fmt.Errorf("invalid migration file name: %s", entry.Name())
+		}
+		if other, exists := seen[version]; exists {
+			return nil, fmt.Errorf("duplicate migration version %s: %s and %s", version, other, entry.Name())
+		}
+		seen[version] = entry.Name()
+
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
+		}
+
+		sum := sha256.Sum256(content)
+		migrations = append(migrations, Migration{
+			Version:  version,
+			Name:     name,
+			SQL:      string(content),
+			Checksum: hex.EncodeToString(sum[:]),
+		})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Run applies every pending migration in a single transaction, so a failure
+// leaves the database as it was. If any are pending and the database already
+// holds data, backup is called first. It returns the versions that were
+// applied.
+func Run(ctx context.Context, db *sql.DB, fsys fs.FS, backup BackupFunc) ([]string, error) {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := db.ExecContext(ctx, createMigrationsTable); err != nil {
+		return nil, fmt.Errorf("failed to create migrations table: %w", err)
+	}
+
+	if err := importAtlasRevisions(ctx, db, migrations); err != nil {
+		return nil, err
+	}
+
+	applied, err := appliedVersions(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	pending, err := plan(migrations, applied)
+	if err != nil {
+		return nil, err
+	}
+	if len(pending) == 0 {
+		slog.Debug("Database schema is up to date", "migrations", len(applied))
+		return nil, nil
+	}
+
+	if len(applied) == 0 {
+		untracked, err := hasTables(ctx, db)
+		if err != nil {
+			return nil, err
+		}
+		if untracked {
+			return nil, ErrUntracked
+		}
+	} else if backup != nil {
+		if err := backup(ctx); err != nil {
+			return nil, fmt.Errorf("pre-migration backup failed: %w", err)
+		}
+	}
+
+	if err := apply(ctx, db, pending); err != nil {
+		return nil, err
+	}
+
+	versions := make([]string, 0, len(pending))
+	for _, migration := range pending {
+		versions = append(versions, migration.Version)
+	}
+
+	return versions, nil
+}
+
+// Check verifies that the database at db can be used by this binary, without
+// applying anything
+func Check(ctx context.Context, db *sql.DB, fsys fs.FS) error {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return err
+	}
+
+	var exists int
+	err = db.QueryRowContext(ctx, "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations'").Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if exists == 0 {
+		return nil
+	}
+
+	applied, err := appliedVersions(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	_, err = plan(migrations, applied)
+	return err
+}
+
+// CheckFile opens the database file at path read-only and runs Check against it
+func CheckFile(ctx context.Context, path string, fsys fs.FS) error {
+	db, err := sql.Open("sqlite3", "file:"+filepath.ToSlash(path)+"?mode=ro")
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return Check(ctx, db, fsys)
+}
+
+// plan returns the migrations that still need to run, refusing databases that
+// were migrated by a newer binary
+func plan(migrations []Migration, applied map[string]string) ([]Migration, error) {
+	known := make(map[string]Migration, len(migrations))
+	latest := ""
+	for _, migration := range migrations {
+		known[migration.Version] = migration
+		latest = migration.Version
+	}
+
+	for version, checksum := range applied {
+		migration, ok := known[version]
+		if !ok {
+			if version > latest {
+				return nil, fmt.Errorf("%w: database is at %s, latest known migration is %s", ErrDatabaseNewer, version, latest)
+			}
+			slog.Warn("Database has an applied migration that is not embedded", "version", version)
+			continue
+		}
+		if checksum != "" && checksum != migration.Checksum {
+			slog.Warn("Applied migration has changed since it ran", "version", version, "name", migration.Name)
+		}
+	}
+
+	var pending []Migration
+	for _, migration := range migrations {
+		if _, ok := applied[migration.Version]; !ok {
+			pending = append(pending, migration)
+		}
+	}
+
+	return pending, nil
+}
+
+// apply runs the pending migrations and records them in one transaction.
+// Foreign keys are switched off on the connection first, since SQLite ignores
+// the pragma inside a transaction, so that table rebuilds don't cascade deletes
+// into referencing tables; integrity is checked with foreign_key_check after
+// each migration instead.
+func apply(ctx context.Context, db *sql.DB, pending []Migration) error {
+	start := time.Now()
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "PRAGMA foreign_keys = OFF"); err != nil {
+		return fmt.Errorf("failed to disable foreign keys: %w", err)
+	}
+	defer conn.ExecContext(context.Background(), "PRAGMA foreign_keys = ON")
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Violations already in the database are not the migrations' fault
+	existing, err := foreignKeyViolations(ctx, tx)
+	if err != nil {
+		return err
+	}
+	if len(existing) > 0 {
+		slog.Warn("Database has foreign key violations from before the migrations", "tables", len(existing))
+	}
+
+	for _, migration := range pending {
+		migrationStart := time.Now()
+
+		if _, err := tx.ExecContext(ctx, migration.SQL); err != nil {
+			return fmt.Errorf("migration %s_%s failed: %w", migration.Version, migration.Name, err)
+		}
+
+		if err := checkForeignKeys(ctx, tx, existing); err != nil {
+			return fmt.Errorf("migration %s_%s failed: %w", migration.Version, migration.Name, err)
+		}
+
+		_, err = tx.ExecContext(ctx,
+			"INSERT INTO schema_migrations (version, name, checksum, execution_ms) VALUES (?, ?, ?, ?)",
+			migration.Version, migration.Name, migration.Checksum, time.Since(migrationStart).Milliseconds(),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to record migration %s: %w", migration.Version, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit migrations: %w", err)
+	}
+
+	for _, migration := range pending {
+		slog.Info("Applied migration", "version", migration.Version, "name", migration.Name)
+	}
+	slog.Info("Database schema migrated", "migrations", len(pending), "duration", time.Since(start))
+	return nil
+}
+
+// foreignKey identifies the rows of a table referencing one parent table
+type foreignKey struct {
+	table  string
+	parent string
+}
+
+// foreignKeyViolations counts the rows pointing at missing parents. Rows are
+// counted rather than identified because table rebuilds may renumber them.
+func foreignKeyViolations(ctx context.Context, tx *sql.Tx) (map[foreignKey]int, error) {
+	rows, err := tx.QueryContext(ctx, "PRAGMA foreign_key_check")
+	if err != nil {
+		return nil, fmt.Errorf("failed to check foreign keys: %w", err)
+	}
+	defer rows.Close()
+
+	violations := make(map[foreignKey]int)
+	for rows.Next() {
+		var key foreignKey
+		var rowID sql.NullInt64
+		var fkID int
+		if err := rows.Scan(&key.table, &rowID, &key.parent, &fkID); err != nil {
+			return nil, fmt.Errorf("failed to read foreign key violation: %w", err)
+		}
+		violations[key]++
+	}
+	return violations, rows.Err()
+}
+
+// checkForeignKeys fails if the migration left more rows pointing at missing
+// parents than there were before it ran
+func checkForeignKeys(ctx context.Context, tx *sql.Tx, before map[foreignKey]int) error {
+	after, err := foreignKeyViolations(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	for key, count := range after {
+		if count > before[key] {
+			return fmt.Errorf("foreign key violation: %d new %s rows reference missing %s", count-before[key], key.table, key.parent)
+		}
+	}
+	return nil
+}
+
+// appliedVersions returns the checksum of every applied migration keyed by version
+func appliedVersions(ctx context.Context, db *sql.DB) (map[string]string, error) {
+	rows, err := db.QueryContext(ctx, "SELECT version, checksum FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[string]string)
+	for rows.Next() {
+		var version, checksum string
+		if err := rows.Scan(&version, &checksum); err != nil {
+			return nil, err
+		}
+		applied[version] = checksum
+	}
+
+	return applied, rows.Err()
+}
+
+// importAtlasRevisions records migrations that were applied with the Atlas CLI
+// so databases migrated before the embedded runner existed are not re-migrated
+func importAtlasRevisions(ctx context.Context, db *sql.DB, migrations []Migration) error {
+	var exists int
+	err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'atlas_schema_revisions'").Scan(&exists)
+	if err != nil || exists == 0 {
+		return err
+	}
+
+	rows, err := db.QueryContext(ctx, "SELECT version FROM atlas_schema_revisions WHERE applied = total")
+	if err != nil {
+		return fmt.Errorf("failed to read atlas revisions: %w", err)
+	}
+
+	var versions []string
+	for rows.Next() {
+		var version string
+		if err := rows.Scan(&version); err != nil {
+			rows.Close()
+			return err
+		}
+		versions = append(versions, version)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	known := make(map[string]Migration, len(migrations))
+	for _, migration := range migrations {
+		known[migration.Version] = migration
+	}
+
+	for _, version := range versions {
+		migration, ok := known[version]
+		if !ok {
+			continue
+		}
+		_, err := db.ExecContext(ctx,
+			"INSERT OR IGNORE INTO schema_migrations (version, name, checksum) VALUES (?, ?, ?)",
+			migration.Version, migration.Name, migration.Checksum,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to import atlas revision %s: %w", version, err)
+		}
+	}
+
+	return nil
+}
+
+// hasTables reports whether the database contains any tables besides the
+// migration bookkeeping
+func hasTables(ctx context.Context, db *sql.DB) (bool, error) {
+	var count int
+	err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM sqlite_master
+WHERE type = 'table' AND name NOT LIKE 'sqlite_%' AND name NOT IN ('schema_migrations', 'atlas_schema_revisions')`).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,176 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func openDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "serra.db")+"?_fk=1")
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func migrations(files map[string]string) fstest.MapFS {
+	fsys := fstest.MapFS{}
+	for name, sql := range files {
+		fsys[name] = &fstest.MapFile{Data: []byte(sql)}
+	}
+	return fsys
+}
+
+const initialSchema = `CREATE TABLE users (id TEXT PRIMARY KEY);
+CREATE TABLE requests (id INTEGER PRIMARY KEY, user_id TEXT NOT NULL REFERENCES users(id));`
+
+// seedDanglingRequest leaves a request whose user no longer exists, as older
+// releases could when foreign keys were not enforced
+func seedDanglingRequest(t *testing.T, db *sql.DB) {
+	t.Helper()
+	if _, err := Run(context.Background(), db, migrations(map[string]string{"001_initial.sql": initialSchema}), nil); err != nil {
+		t.Fatalf("initial migration: %v", err)
+	}
+
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Fatalf("conn: %v", err)
+	}
+	defer conn.Close()
+	for _, stmt := range []string{
+		"PRAGMA foreign_keys = OFF",
+		"INSERT INTO requests (id, user_id) VALUES (1, 'deleted')",
+		"PRAGMA foreign_keys = ON",
+	} {
+		if _, err := conn.ExecContext(context.Background(), stmt); err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+}
+
+func TestRunIgnoresExistingForeignKeyViolations(t *testing.T) {
+	db := openDB(t)
+	seedDanglingRequest(t, db)
+
+	fsys := migrations(map[string]string{
+		"001_initial.sql": initialSchema,
+		// Rebuilds the table holding the dangling row
+		"002_request_status.sql": `CREATE TABLE requests_new (id INTEGER PRIMARY KEY, user_id TEXT NOT NULL REFERENCES users(id), status TEXT NOT NULL DEFAULT 'pending');
+INSERT INTO requests_new (id, user_id) SELECT id, user_id FROM requests;
+DROP TABLE requests;
+ALTER TABLE requests_new RENAME TO requests;`,
+	})
+
+	applied, err := Run(context.Background(), db, fsys, nil)
+	if err != nil {
+		t.Fatalf("migration over an existing violation failed: %v", err)
+	}
+	if len(applied) != 1 || applied[0] != "002" {
+		t.Fatalf("applied = %v, want [002]", applied)
+	}
+}
+
+func TestRunRejectsNewForeignKeyViolations(t *testing.T) {
+	db := openDB(t)
+	seedDanglingRequest(t, db)
+
+	fsys := migrations(map[string]string{
+		"001_initial.sql":        initialSchema,
+		"002_orphan_request.sql": `INSERT INTO requests (id, user_id) VALUES (2, 'missing');`,
+	})
+
+	_, err := Run(context.Background(), db, fsys, nil)
+	if err == nil || !strings.Contains(err.Error(), "1 new requests rows reference missing users") {
+		t.Fatalf("err = %v, want a foreign key violation for the new row", err)
+	}
+
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM requests").Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Errorf("failed migration left %d requests, want it rolled back to 1", count)
+	}
+	var recorded int
+	if err := db.QueryRow("SELECT COUNT(*) FROM schema_migrations WHERE version = '002'").Scan(&recorded); err != nil {
+		t.Fatal(err)
+	}
+	if recorded != 0 {
+		t.Error("failed migration was recorded as applied")
+	}
+}
+
+func TestRunRollsBackEveryPendingMigration(t *testing.T) {
+	db := openDB(t)
+	if _, err := Run(context.Background(), db, migrations(map[string]string{"001_initial.sql": initialSchema}), nil); err != nil {
+		t.Fatalf("initial migration: %v", err)
+	}
+
+	fsys := migrations(map[string]string{
+		"001_initial.sql":  initialSchema,
+		"002_tags.sql":     `CREATE TABLE tags (id INTEGER PRIMARY KEY);`,
+		"003_broken.sql":   `ALTER TABLE missing ADD COLUMN name TEXT;`,
+		"004_settings.sql": `CREATE TABLE settings (key TEXT PRIMARY KEY);`,
+	})
+
+	applied, err := Run(context.Background(), db, fsys, nil)
+	if err == nil || !strings.Contains(err.Error(), "migration 003_broken failed") {
+		t.Fatalf("err = %v, want the broken migration to fail", err)
+	}
+	if len(applied) != 0 {
+		t.Errorf("applied = %v, want none", applied)
+	}
+
+	var tables int
+	if err := db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'tags'").Scan(&tables); err != nil {
+		t.Fatal(err)
+	}
+	if tables != 0 {
+		t.Error("migration before the failed one was not rolled back")
+	}
+	var recorded int
+	if err := db.QueryRow("SELECT COUNT(*) FROM schema_migrations").Scan(&recorded); err != nil {
+		t.Fatal(err)
+	}
+	if recorded != 1 {
+		t.Errorf("%d migrations recorded, want only the initial one", recorded)
+	}
+}
+
+func TestRunRebuildDoesNotCascadeDeletes(t *testing.T) {
+	db := openDB(t)
+	schema := `CREATE TABLE users (id TEXT PRIMARY KEY);
+CREATE TABLE requests (id INTEGER PRIMARY KEY, user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE);`
+	if _, err := Run(context.Background(), db, migrations(map[string]string{"001_initial.sql": schema}), nil); err != nil {
+		t.Fatalf("initial migration: %v", err)
+	}
+	if _, err := db.Exec("INSERT INTO users (id) VALUES ('user'); INSERT INTO requests (id, user_id) VALUES (1, 'user');"); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+
+	fsys := migrations(map[string]string{
+		"001_initial.sql": schema,
+		// Dropping the parent table deletes its rows, which cascades with foreign keys on
+		"002_user_name.sql": `CREATE TABLE users_new (id TEXT PRIMARY KEY, name TEXT);
+INSERT INTO users_new (id) SELECT id FROM users;
+DROP TABLE users;
+ALTER TABLE users_new RENAME TO users;`,
+	})
+	if _, err := Run(context.Background(), db, fsys, nil); err != nil {
+		t.Fatalf("rebuild: %v", err)
+	}
+
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM requests").Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Fatalf("rebuilding users left %d requests, want 1", count)
+	}
+}
@@ -37,6 +37,20 @@ WHERE timestamp >= datetime('now', '-' || ? || ' days')
 ORDER BY timestamp DESC
 LIMIT ?;
 
+-- name: GetApprovalDurations :many
+SELECT
+    rm.user_id AS approver_id,
+    u.username,
+    r.media_type,
+    rm.processing_time_seconds,
+    r.created_at,
+    r.fulfilled_at
+FROM request_metrics rm
+JOIN requests r ON r.id = rm.request_id
+LEFT JOIN users u ON u.id = rm.user_id
+WHERE rm.status_change = 'approved' AND rm.timestamp >= ?
+ORDER BY rm.user_id, r.media_type;
+
 -- name: RecordDriveUsage :one
 INSERT INTO drive_usage_history (drive_id, total_size, used_size, available_size, usage_percentage, growth_rate_gb_per_day, projected_full_date)
 VALUES (?, ?, ?, ?, ?, ?, ?)
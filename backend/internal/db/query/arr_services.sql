@@ -5,4 +5,14 @@ VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);
 -- name: GetArrServiceByType :many
 SELECT id, type, name, base_url, api_key, quality_profile, root_folder_path, minimum_availability, is_4k, created_at
 FROM arr_services
-WHERE type = sqlc.arg(service_type);
\ No newline at end of file
+WHERE type = sqlc.arg(service_type);
+
+-- name: GetAllArrServices :many
+SELECT id, type, name, base_url, api_key, quality_profile, root_folder_path, minimum_availability, is_4k, created_at
+FROM arr_services
+ORDER BY type, name;
+
+-- name: GetArrServiceByID :one
+SELECT id, type, name, base_url, api_key, quality_profile, root_folder_path, minimum_availability, is_4k, created_at
+FROM arr_services
+WHERE id = ?;
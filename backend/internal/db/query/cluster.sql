@@ -0,0 +1,46 @@
+-- name: AcquireLease :execrows
+INSERT INTO leases (name, holder, expires_at)
+VALUES (sqlc.arg(name), sqlc.arg(holder), sqlc.arg(expires_at))
+ON CONFLICT (name) DO UPDATE SET
+    holder = excluded.holder,
+    expires_at = excluded.expires_at
+WHERE leases.holder = excluded.holder OR leases.expires_at < sqlc.arg(now);
+
+-- name: ReleaseLease :exec
+DELETE FROM leases
+WHERE name = ? AND holder = ?;
+
+-- name: CreateCSRFToken :exec
+INSERT INTO csrf_tokens (token, expires_at)
+VALUES (?, ?);
+
+-- name: ConsumeCSRFToken :execrows
+DELETE FROM csrf_tokens
+WHERE token = ? AND expires_at > ?;
+
+-- name: DeleteExpiredCSRFTokens :exec
+DELETE FROM csrf_tokens
+WHERE expires_at <= ?;
+
+-- name: RecordRateLimitHit :execrows
+INSERT INTO rate_limit_hits (key, hit_at)
+SELECT sqlc.arg(key), sqlc.arg(hit_at)
+WHERE (
+    SELECT COUNT(*) FROM rate_limit_hits
+    WHERE rate_limit_hits.key = sqlc.arg(key) AND rate_limit_hits.hit_at > sqlc.arg(window_start)
+) < sqlc.arg(max_hits);
+
+-- name: DeleteRateLimitHitsBefore :exec
+DELETE FROM rate_limit_hits
+WHERE hit_at <= ?;
+
+-- name: SetSharedState :exec
+INSERT INTO shared_state (key, value, updated_at)
+VALUES (?, ?, ?)
+ON CONFLICT (key) DO UPDATE SET
+    value = excluded.value,
+    updated_at = excluded.updated_at;
+
+-- name: GetSharedState :one
+SELECT * FROM shared_state
+WHERE key = ?;
@@ -0,0 +1,28 @@
+-- name: AddCollectionRequestItem :exec
+INSERT INTO collection_request_items (request_id, collection_request_id, tmdb_id)
+VALUES (?, ?, ?);
+
+-- name: GetCollectionRequestItems :many
+SELECT i.request_id, i.tmdb_id, c.id AS collection_request_id, c.collection_id, c.name, c.poster_url, c.watch_unreleased
+FROM collection_request_items i
+JOIN collection_requests c ON c.id = i.collection_request_id;
+
+-- name: GetCollectionRequestItemsByCollectionRequestID :many
+SELECT request_id, collection_request_id, tmdb_id
+FROM collection_request_items
+WHERE collection_request_id = ?;
+
+-- name: GetWatchedCollectionRequests :many
+SELECT id, collection_id, user_id, name, poster_url, is_4k, watch_unreleased, created_at, updated_at
+FROM collection_requests
+WHERE watch_unreleased = TRUE;
+
+-- name: UpsertCollectionRequest :one
+INSERT INTO collection_requests (collection_id, user_id, name, poster_url, is_4k, watch_unreleased)
+VALUES (?, ?, ?, ?, ?, ?)
+ON CONFLICT (collection_id, user_id, is_4k) DO UPDATE SET
+    name = excluded.name,
+    poster_url = excluded.poster_url,
+    watch_unreleased = excluded.watch_unreleased,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING id, collection_id, user_id, name, poster_url, is_4k, watch_unreleased, created_at, updated_at;
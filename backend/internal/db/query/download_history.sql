@@ -0,0 +1,36 @@
+-- name: CreateDownloadHistory :exec
+INSERT INTO download_history (
+    download_id, request_id, title, release_name, source, client, tmdb_id, episodes, size_bytes, average_speed, started_at, finished_at, outcome
+) VALUES (
+    ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
+);
+
+-- name: ListDownloadHistory :many
+SELECT id, download_id, request_id, title, release_name, source, client, tmdb_id, episodes, size_bytes, average_speed, started_at, finished_at, outcome
+FROM download_history
+WHERE (sqlc.narg('user_id') IS NULL OR request_id IN (
+        SELECT requests.id FROM requests
+        WHERE requests.user_id = sqlc.narg('user_id') OR requests.on_behalf_of = sqlc.narg('user_id')
+    ))
+    AND (sqlc.narg('request_id') IS NULL OR request_id = sqlc.narg('request_id'))
+ORDER BY finished_at DESC, id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountDownloadHistory :one
+SELECT COUNT(*)
+FROM download_history
+WHERE (sqlc.narg('user_id') IS NULL OR request_id IN (
+        SELECT requests.id FROM requests
+        WHERE requests.user_id = sqlc.narg('user_id') OR requests.on_behalf_of = sqlc.narg('user_id')
+    ))
+    AND (sqlc.narg('request_id') IS NULL OR request_id = sqlc.narg('request_id'));
+
+-- name: ListDownloadHistoryByRequest :many
+SELECT id, download_id, request_id, title, release_name, source, client, tmdb_id, episodes, size_bytes, average_speed, started_at, finished_at, outcome
+FROM download_history
+WHERE request_id = ?
+ORDER BY started_at ASC, id ASC;
+
+-- name: DeleteDownloadHistoryBefore :exec
+DELETE FROM download_history
+WHERE finished_at < ?;
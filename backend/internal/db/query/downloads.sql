@@ -1,8 +1,8 @@
 -- name: UpsertDownloadQueue :exec
 INSERT INTO downloads (
-  id, title, torrent_title, source, tmdb_id, tvdb_id, hash, progress, time_left, status, last_updated
+  id, title, torrent_title, source, tmdb_id, tvdb_id, season_number, request_id, episodes, match_confidence, client, size_bytes, hash, progress, time_left, status, started_at, last_updated
 ) VALUES (
-  ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP
+  ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP
 )
 ON CONFLICT(id) DO UPDATE SET
   title = excluded.title,
@@ -10,10 +10,17 @@ ON CONFLICT(id) DO UPDATE SET
   source = excluded.source,
   tmdb_id = excluded.tmdb_id,
   tvdb_id = excluded.tvdb_id,
+  season_number = excluded.season_number,
+  request_id = excluded.request_id,
+  episodes = excluded.episodes,
+  match_confidence = excluded.match_confidence,
+  client = excluded.client,
+  size_bytes = excluded.size_bytes,
   hash = excluded.hash,
   progress = excluded.progress,
   time_left = excluded.time_left,
   status = excluded.status,
+  started_at = COALESCE(downloads.started_at, excluded.started_at),
   last_updated = CURRENT_TIMESTAMP;
 
 -- name: ListDownloads :many
@@ -28,11 +35,31 @@ SELECT
   progress,
   time_left,
   status,
-  last_updated
+  last_updated,
+  request_id,
+  episodes,
+  match_confidence,
+  client,
+  size_bytes,
+  started_at
 FROM downloads
 WHERE status IS NULL OR status NOT IN ('completed', 'removed')
 ORDER BY last_updated DESC;
 
+-- name: ListDownloadRequesters :many
+SELECT
+  d.id AS download_id,
+  d.season_number,
+  r.user_id,
+  r.on_behalf_of,
+  r.seasons,
+  r.episodes
+FROM downloads d
+JOIN requests r ON r.tmdb_id = d.tmdb_id
+  AND r.media_type = CASE d.source WHEN 'sonarr' THEN 'tv' ELSE 'movie' END
+WHERE r.status != 'denied'
+ORDER BY d.id;
+
 -- name: ListDownloadsBySource :many
 SELECT * FROM downloads WHERE source = ?;
 
@@ -51,7 +78,13 @@ SELECT
   progress,
   time_left,
   status,
-  last_updated
+  last_updated,
+  request_id,
+  episodes,
+  match_confidence,
+  client,
+  size_bytes,
+  started_at
 FROM downloads
 WHERE status = 'missing_from_client'
   AND last_updated < datetime('now', '-24 hours')
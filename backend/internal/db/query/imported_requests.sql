@@ -0,0 +1,19 @@
+-- name: GetImportedRequestIDs :many
+SELECT external_id FROM imported_requests
+WHERE source = ?;
+
+-- name: RecordImportedRequest :exec
+INSERT INTO imported_requests (source, external_id, request_id)
+VALUES (?, ?, ?)
+ON CONFLICT (source, external_id) DO NOTHING;
+
+-- name: HasMatchingRequest :one
+SELECT EXISTS (
+    SELECT 1 FROM requests
+    WHERE user_id = ? AND media_type = ? AND tmdb_id = ? AND is_4k = ? AND seasons IS ? AND episodes IS NULL
+) AS matched;
+
+-- name: ImportRequest :one
+INSERT INTO requests (user_id, media_type, tmdb_id, title, status, notes, poster_url, seasons, season_statuses, is_4k, approver_id, priority, created_at, updated_at, fulfilled_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id;
@@ -0,0 +1,32 @@
+-- name: CreateJobRun :exec
+INSERT INTO job_runs (
+    job_name, trigger_type, started_at, finished_at, duration_ms, outcome, attempts, error, summary
+) VALUES (
+    ?, ?, ?, ?, ?, ?, ?, ?, ?
+);
+
+-- name: ListJobRuns :many
+SELECT id, job_name, trigger_type, started_at, finished_at, duration_ms, outcome, attempts, error, summary, created_at
+FROM job_runs
+WHERE job_name = ?
+ORDER BY started_at DESC, id DESC
+LIMIT ? OFFSET ?;
+
+-- name: CountJobRuns :one
+SELECT COUNT(*)
+FROM job_runs
+WHERE job_name = ?;
+
+-- name: CountConsecutiveJobFailures :one
+SELECT COUNT(*)
+FROM job_runs
+WHERE job_name = ?
+    AND outcome = 'failed'
+    AND id > COALESCE((
+        SELECT MAX(id) FROM job_runs AS successful
+        WHERE successful.job_name = ? AND successful.outcome = 'success'
+    ), 0);
+
+-- name: DeleteJobRunsBefore :exec
+DELETE FROM job_runs
+WHERE started_at < ?;
@@ -1,7 +1,12 @@
 -- name: CheckMediaInLibrary :one
 SELECT COUNT(*) > 0 as in_library 
 FROM library_items 
-WHERE tmdb_id = ?;
+WHERE tmdb_id = ? AND COALESCE(is_4k, FALSE) = FALSE;
+
+-- name: CheckMedia4KInLibrary :one
+SELECT COUNT(*) > 0 as in_library
+FROM library_items
+WHERE tmdb_id = ? AND is_4k = TRUE;
 
 -- name: CheckMultipleMediaInLibrary :many
 SELECT tmdb_id, COUNT(*) > 0 as in_library
@@ -9,6 +14,14 @@ FROM library_items
 WHERE tmdb_id IN (/*SLICE:tmdb_ids*/?)
 GROUP BY tmdb_id;
 
+-- name: GetLibraryAvailability :many
+SELECT tmdb_id,
+       MAX(COALESCE(is_4k, FALSE) = FALSE) as in_library,
+       MAX(COALESCE(is_4k, FALSE) = TRUE) as in_library_4k
+FROM library_items
+WHERE tmdb_id IN (sqlc.slice('tmdb_ids'))
+GROUP BY tmdb_id;
+
 -- name: GetLibraryItemByTMDBID :one
 SELECT id, name, original_title, type, parent_id, series_id, season_number, episode_number, year, premiere_date, end_date, 
        community_rating, critic_rating, official_rating, overview, tagline, genres, studios, people,
@@ -1,69 +1,96 @@
 -- name: CreateRequest :one
-INSERT INTO requests (user_id, media_type, tmdb_id, title, status, notes, poster_url, on_behalf_of, seasons, season_statuses)
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-RETURNING id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses;
+INSERT INTO requests (user_id, media_type, tmdb_id, title, status, notes, poster_url, on_behalf_of, seasons, season_statuses, is_4k, quality_profile_id, root_folder_path, tags, series_type, episodes, follow_show, parent_request_id, priority)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses, is_4k, quality_profile_id, root_folder_path, tags, series_type, episodes, follow_show, parent_request_id, priority, episodes_monitored_at, deny_reason;
 
 -- name: GetRequestByID :one
-SELECT id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses
+SELECT id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses, is_4k, quality_profile_id, root_folder_path, tags, series_type, episodes, follow_show, parent_request_id, priority, episodes_monitored_at, deny_reason
 FROM requests
 WHERE id = ?;
 
 -- name: GetRequestsByUser :many
-SELECT id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses
+SELECT id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses, is_4k, quality_profile_id, root_folder_path, tags, series_type, episodes, follow_show, parent_request_id, priority, episodes_monitored_at, deny_reason
 FROM requests
 WHERE user_id = ?
 ORDER BY created_at DESC;
 
 -- name: GetAllRequests :many
-SELECT id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses
+SELECT id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses, is_4k, quality_profile_id, root_folder_path, tags, series_type, episodes, follow_show, parent_request_id, priority, episodes_monitored_at, deny_reason
 FROM requests
 ORDER BY created_at DESC;
 
 -- name: GetRequestsByStatus :many
-SELECT id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses
+SELECT id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses, is_4k, quality_profile_id, root_folder_path, tags, series_type, episodes, follow_show, parent_request_id, priority, episodes_monitored_at, deny_reason
 FROM requests
 WHERE status = ?
 ORDER BY created_at DESC;
 
+-- name: GetOverdueRequests :many
+SELECT id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses, is_4k, quality_profile_id, root_folder_path, tags, series_type, episodes, follow_show, parent_request_id, priority, episodes_monitored_at, deny_reason
+FROM requests
+WHERE status = 'pending' AND created_at <= ?
+  AND NOT EXISTS (
+    SELECT 1 FROM request_metrics rm
+    WHERE rm.request_id = requests.id AND rm.status_change = 'sla_escalated'
+  )
+ORDER BY created_at ASC;
+
 -- name: GetPendingRequests :many
-SELECT id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses
+SELECT id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses, is_4k, quality_profile_id, root_folder_path, tags, series_type, episodes, follow_show, parent_request_id, priority, episodes_monitored_at, deny_reason
 FROM requests
 WHERE status = 'pending'
-ORDER BY created_at ASC;
+ORDER BY CASE priority WHEN 'urgent' THEN 0 WHEN 'high' THEN 1 WHEN 'normal' THEN 2 ELSE 3 END, created_at ASC;
+
+-- name: UpdateRequestDenyReason :one
+UPDATE requests
+SET deny_reason = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?
+RETURNING id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses, is_4k, quality_profile_id, root_folder_path, tags, series_type, episodes, follow_show, parent_request_id, priority, episodes_monitored_at, deny_reason;
+
+-- name: UpdateRequestOverrides :one
+UPDATE requests
+SET quality_profile_id = ?, root_folder_path = ?, tags = ?, series_type = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?
+RETURNING id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses, is_4k, quality_profile_id, root_folder_path, tags, series_type, episodes, follow_show, parent_request_id, priority, episodes_monitored_at, deny_reason;
 
 -- name: UpdateRequestStatus :one
 UPDATE requests
 SET status = ?, approver_id = ?, updated_at = CURRENT_TIMESTAMP
 WHERE id = ?
-RETURNING id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses;
+RETURNING id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses, is_4k, quality_profile_id, root_folder_path, tags, series_type, episodes, follow_show, parent_request_id, priority, episodes_monitored_at, deny_reason;
 
 -- name: UpdateRequestStatusOnly :one
 UPDATE requests
 SET status = ?, updated_at = CURRENT_TIMESTAMP
 WHERE id = ?
-RETURNING id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses;
+RETURNING id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses, is_4k, quality_profile_id, root_folder_path, tags, series_type, episodes, follow_show, parent_request_id, priority, episodes_monitored_at, deny_reason;
 
 -- name: FulfillRequest :one
 UPDATE requests
 SET status = 'fulfilled', fulfilled_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
 WHERE id = ?
-RETURNING id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses;
+RETURNING id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses, is_4k, quality_profile_id, root_folder_path, tags, series_type, episodes, follow_show, parent_request_id, priority, episodes_monitored_at, deny_reason;
 
 -- name: DeleteRequest :exec
 DELETE FROM requests WHERE id = ?;
 
 -- name: CheckExistingRequest :one
-SELECT id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses
+SELECT id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses, is_4k, quality_profile_id, root_folder_path, tags, series_type, episodes, follow_show, parent_request_id, priority, episodes_monitored_at, deny_reason
+FROM requests
+WHERE media_type = ? AND tmdb_id = ? AND user_id = ? AND seasons = ? AND episodes IS ? AND is_4k = ?;
+
+-- name: CountUserRequestsByMediaType :one
+SELECT COUNT(*)
 FROM requests
-WHERE media_type = ? AND tmdb_id = ? AND user_id = ? AND seasons = ?;
+WHERE user_id = ? AND media_type = ? AND status NOT IN ('denied', 'failed');
 
 -- name: CheckExistingRequestAnySeasons :many
-SELECT id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses
+SELECT id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses, is_4k, quality_profile_id, root_folder_path, tags, series_type, episodes, follow_show, parent_request_id, priority, episodes_monitored_at, deny_reason
 FROM requests
-WHERE media_type = ? AND tmdb_id = ? AND user_id = ?;
+WHERE media_type = ? AND tmdb_id = ? AND user_id = ? AND is_4k = ?;
 
 -- name: GetRequestsForUser :many
-SELECT id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses
+SELECT id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses, is_4k, quality_profile_id, root_folder_path, tags, series_type, episodes, follow_show, parent_request_id, priority, episodes_monitored_at, deny_reason
 FROM requests
 WHERE user_id = ? OR on_behalf_of = ?
 ORDER BY created_at DESC;
@@ -78,7 +105,7 @@ SELECT
 FROM requests;
 
 -- name: GetRecentRequests :many
-SELECT id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses
+SELECT id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses, is_4k, quality_profile_id, root_folder_path, tags, series_type, episodes, follow_show, parent_request_id, priority, episodes_monitored_at, deny_reason
 FROM requests
 WHERE created_at >= datetime('now', '-7 days')
 ORDER BY created_at DESC
@@ -93,9 +120,46 @@ GROUP BY tmdb_id;
 -- name: CheckUserRequestExists :one
 SELECT COUNT(*) > 0 as requested
 FROM requests 
-WHERE tmdb_id = ? AND media_type = ? AND user_id = ?;
+WHERE tmdb_id = ? AND media_type = ? AND user_id = ? AND is_4k = ?;
+
+-- name: GetUserRequestedTitles :many
+SELECT DISTINCT tmdb_id, media_type, is_4k
+FROM requests
+WHERE user_id = ? AND tmdb_id IN (sqlc.slice('tmdb_ids'));
 
 -- name: GetRequestsByTMDBIDAndMediaType :many
-SELECT id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses
+SELECT id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses, is_4k, quality_profile_id, root_folder_path, tags, series_type, episodes, follow_show, parent_request_id, priority, episodes_monitored_at, deny_reason
 FROM requests
-WHERE tmdb_id = ? AND media_type = ?;
\ No newline at end of file
+WHERE tmdb_id = ? AND media_type = ?;
+
+-- name: GetFollowedRequests :many
+SELECT id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses, is_4k, quality_profile_id, root_folder_path, tags, series_type, episodes, follow_show, parent_request_id, priority, episodes_monitored_at, deny_reason
+FROM requests
+WHERE media_type = 'tv' AND follow_show = TRUE AND status IN ('approved', 'processing', 'fulfilled')
+ORDER BY created_at ASC;
+
+-- name: UpdateRequestSeasonStatuses :exec
+UPDATE requests
+SET season_statuses = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+
+-- name: MarkRequestEpisodesMonitored :exec
+UPDATE requests
+SET episodes_monitored_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+
+-- name: GetRequestsByFilter :many
+SELECT id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses, is_4k, quality_profile_id, root_folder_path, tags, series_type, episodes, follow_show, parent_request_id, priority, episodes_monitored_at, deny_reason
+FROM requests
+WHERE (sqlc.narg('status') IS NULL OR status = sqlc.narg('status'))
+  AND (sqlc.narg('media_type') IS NULL OR media_type = sqlc.narg('media_type'))
+  AND (sqlc.narg('user_id') IS NULL OR user_id = sqlc.narg('user_id'))
+  AND (sqlc.narg('created_after') IS NULL OR created_at >= sqlc.narg('created_after'))
+  AND (sqlc.narg('created_before') IS NULL OR created_at < sqlc.narg('created_before'))
+ORDER BY created_at ASC;
+
+-- name: UpdateRequestPriority :one
+UPDATE requests
+SET priority = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?
+RETURNING id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses, is_4k, quality_profile_id, root_folder_path, tags, series_type, episodes, follow_show, parent_request_id, priority, episodes_monitored_at, deny_reason;
@@ -0,0 +1,43 @@
+-- name: CreateRoutingRule :one
+INSERT INTO routing_rules (name, media_type, priority, enabled, is_default, genres, original_languages, keywords, certifications, user_ids, user_types, arr_service_id, root_folder_path, quality_profile_id)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id, name, media_type, priority, enabled, is_default, genres, original_languages, keywords, certifications, user_ids, user_types, arr_service_id, root_folder_path, quality_profile_id, created_at, updated_at;
+
+-- name: DeleteRoutingRule :exec
+DELETE FROM routing_rules WHERE id = ?;
+
+-- name: GetEnabledRoutingRules :many
+SELECT id, name, media_type, priority, enabled, is_default, genres, original_languages, keywords, certifications, user_ids, user_types, arr_service_id, root_folder_path, quality_profile_id, created_at, updated_at
+FROM routing_rules
+WHERE media_type = ? AND enabled = TRUE
+ORDER BY priority, id;
+
+-- name: GetRequestRouting :one
+SELECT request_id, arr_service_id, routing_rule_id, root_folder_path, routed_at
+FROM request_routing
+WHERE request_id = ?;
+
+-- name: GetRoutingRuleByID :one
+SELECT id, name, media_type, priority, enabled, is_default, genres, original_languages, keywords, certifications, user_ids, user_types, arr_service_id, root_folder_path, quality_profile_id, created_at, updated_at
+FROM routing_rules
+WHERE id = ?;
+
+-- name: GetRoutingRules :many
+SELECT id, name, media_type, priority, enabled, is_default, genres, original_languages, keywords, certifications, user_ids, user_types, arr_service_id, root_folder_path, quality_profile_id, created_at, updated_at
+FROM routing_rules
+ORDER BY media_type, priority, id;
+
+-- name: SetRequestRouting :exec
+INSERT INTO request_routing (request_id, arr_service_id, routing_rule_id, root_folder_path, routed_at)
+VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP)
+ON CONFLICT (request_id) DO UPDATE SET
+    arr_service_id = excluded.arr_service_id,
+    routing_rule_id = excluded.routing_rule_id,
+    root_folder_path = excluded.root_folder_path,
+    routed_at = excluded.routed_at;
+
+-- name: UpdateRoutingRule :one
+UPDATE routing_rules
+SET name = ?, media_type = ?, priority = ?, enabled = ?, is_default = ?, genres = ?, original_languages = ?, keywords = ?, certifications = ?, user_ids = ?, user_types = ?, arr_service_id = ?, root_folder_path = ?, quality_profile_id = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?
+RETURNING id, name, media_type, priority, enabled, is_default, genres, original_languages, keywords, certifications, user_ids, user_types, arr_service_id, root_folder_path, quality_profile_id, created_at, updated_at;
@@ -0,0 +1,15 @@
+-- name: DeleteUserRequestPriority :exec
+DELETE FROM user_request_priorities
+WHERE user_id = ?;
+
+-- name: GetUserRequestPriority :one
+SELECT priority
+FROM user_request_priorities
+WHERE user_id = ?;
+
+-- name: SetUserRequestPriority :exec
+INSERT INTO user_request_priorities (user_id, priority)
+VALUES (?, ?)
+ON CONFLICT (user_id) DO UPDATE SET
+    priority = excluded.priority,
+    updated_at = CURRENT_TIMESTAMP;
@@ -0,0 +1,39 @@
+-- name: AddWatchlistItem :one
+INSERT INTO watchlist_items (user_id, media_type, tmdb_id, title, poster_url, auto_request)
+VALUES (?, ?, ?, ?, ?, ?)
+ON CONFLICT (user_id, media_type, tmdb_id) DO UPDATE SET
+    title = excluded.title,
+    poster_url = excluded.poster_url,
+    auto_request = excluded.auto_request
+RETURNING id, user_id, media_type, tmdb_id, title, poster_url, auto_request, request_id, available_notified_at, created_at;
+
+-- name: DeleteWatchlistItem :execrows
+DELETE FROM watchlist_items
+WHERE user_id = ? AND media_type = ? AND tmdb_id = ?;
+
+-- name: GetAutoRequestWatchlistItems :many
+SELECT id, user_id, media_type, tmdb_id, title, poster_url, auto_request, request_id, available_notified_at, created_at
+FROM watchlist_items
+WHERE media_type = 'movie' AND auto_request = TRUE AND request_id IS NULL AND available_notified_at IS NULL;
+
+-- name: GetNewlyAvailableWatchlistItems :many
+SELECT id, user_id, media_type, tmdb_id, title, poster_url, auto_request, request_id, available_notified_at, created_at
+FROM watchlist_items w
+WHERE w.available_notified_at IS NULL
+  AND EXISTS (SELECT 1 FROM library_items l WHERE l.tmdb_id = CAST(w.tmdb_id AS TEXT));
+
+-- name: GetUserWatchlist :many
+SELECT id, user_id, media_type, tmdb_id, title, poster_url, auto_request, request_id, available_notified_at, created_at
+FROM watchlist_items
+WHERE user_id = ?
+ORDER BY created_at DESC;
+
+-- name: MarkWatchlistItemNotified :exec
+UPDATE watchlist_items
+SET available_notified_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+
+-- name: SetWatchlistItemRequest :exec
+UPDATE watchlist_items
+SET request_id = ?
+WHERE id = ?;
@@ -8,6 +8,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"time"
 )
 
 const clearDriveAlerts = `-- name: ClearDriveAlerts :exec
@@ -129,6 +130,60 @@ func (q *Queries) GetActiveDriveAlerts(ctx context.Context) ([]GetActiveDriveAle
 	return items, nil
 }
 
+const getApprovalDurations = `-- name: GetApprovalDurations :many
+SELECT
+    rm.user_id AS approver_id,
+    u.username,
+    r.media_type,
+    rm.processing_time_seconds,
+    r.created_at,
+    r.fulfilled_at
+FROM request_metrics rm
+JOIN requests r ON r.id = rm.request_id
+LEFT JOIN users u ON u.id = rm.user_id
+WHERE rm.status_change = 'approved' AND rm.timestamp >= ?
+ORDER BY rm.user_id, r.media_type
+`
+
+type GetApprovalDurationsRow struct {
+	ApproverID            string         `json:"approver_id"`
+	Username              sql.NullString `json:"username"`
+	MediaType             string         `json:"media_type"`
+	ProcessingTimeSeconds sql.NullInt64  `json:"processing_time_seconds"`
+	CreatedAt             time.Time      `json:"created_at"`
+	FulfilledAt           sql.NullTime   `json:"fulfilled_at"`
+}
+
+func (q *Queries) GetApprovalDurations(ctx context.Context, timestamp sql.NullTime) ([]GetApprovalDurationsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getApprovalDurations, timestamp)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetApprovalDurationsRow
+	for rows.Next() {
+		var i GetApprovalDurationsRow
+		if err := rows.Scan(
+			&i.ApproverID,
+			&i.Username,
+			&i.MediaType,
+			&i.ProcessingTimeSeconds,
+			&i.CreatedAt,
+			&i.FulfilledAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getAverageProcessingTime = `-- name: GetAverageProcessingTime :one
 SELECT 
     AVG(
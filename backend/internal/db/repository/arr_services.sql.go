@@ -7,6 +7,8 @@ package repository
 
 import (
 	"context"
+
+	"github.com/mahcks/serra/internal/secrets"
 )
 
 const createArrService = `-- name: CreateArrService :exec
@@ -15,15 +17,15 @@ VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
 `
 
 type CreateArrServiceParams struct {
-	ID                  string `json:"id"`
-	Type                string `json:"type"`
-	Name                string `json:"name"`
-	BaseUrl             string `json:"base_url"`
-	ApiKey              string `json:"api_key"`
-	QualityProfile      string `json:"quality_profile"`
-	RootFolderPath      string `json:"root_folder_path"`
-	MinimumAvailability string `json:"minimum_availability"`
-	Is4k                bool   `json:"is_4k"`
+	ID                  string         `json:"id"`
+	Type                string         `json:"type"`
+	Name                string         `json:"name"`
+	BaseUrl             string         `json:"base_url"`
+	ApiKey              secrets.String `json:"api_key"`
+	QualityProfile      string         `json:"quality_profile"`
+	RootFolderPath      string         `json:"root_folder_path"`
+	MinimumAvailability string         `json:"minimum_availability"`
+	Is4k                bool           `json:"is_4k"`
 }
 
 func (q *Queries) CreateArrService(ctx context.Context, arg CreateArrServiceParams) error {
@@ -41,6 +43,70 @@ func (q *Queries) CreateArrService(ctx context.Context, arg CreateArrServicePara
 	return err
 }
 
+const getAllArrServices = `-- name: GetAllArrServices :many
+SELECT id, type, name, base_url, api_key, quality_profile, root_folder_path, minimum_availability, is_4k, created_at
+FROM arr_services
+ORDER BY type, name
+`
+
+func (q *Queries) GetAllArrServices(ctx context.Context) ([]ArrService, error) {
+	rows, err := q.db.QueryContext(ctx, getAllArrServices)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ArrService
+	for rows.Next() {
+		var i ArrService
+		if err := rows.Scan(
+			&i.ID,
+			&i.Type,
+			&i.Name,
+			&i.BaseUrl,
+			&i.ApiKey,
+			&i.QualityProfile,
+			&i.RootFolderPath,
+			&i.MinimumAvailability,
+			&i.Is4k,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getArrServiceByID = `-- name: GetArrServiceByID :one
+SELECT id, type, name, base_url, api_key, quality_profile, root_folder_path, minimum_availability, is_4k, created_at
+FROM arr_services
+WHERE id = ?
+`
+
+func (q *Queries) GetArrServiceByID(ctx context.Context, id string) (ArrService, error) {
+	row := q.db.QueryRowContext(ctx, getArrServiceByID, id)
+	var i ArrService
+	err := row.Scan(
+		&i.ID,
+		&i.Type,
+		&i.Name,
+		&i.BaseUrl,
+		&i.ApiKey,
+		&i.QualityProfile,
+		&i.RootFolderPath,
+		&i.MinimumAvailability,
+		&i.Is4k,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const getArrServiceByType = `-- name: GetArrServiceByType :many
 SELECT id, type, name, base_url, api_key, quality_profile, root_folder_path, minimum_availability, is_4k, created_at
 FROM arr_services
@@ -8,6 +8,8 @@ package repository
 import (
 	"context"
 	"database/sql"
+
+	"github.com/mahcks/serra/internal/secrets"
 )
 
 const getDownloadClients = `-- name: GetDownloadClients :many
@@ -64,15 +66,15 @@ ON CONFLICT(id) DO UPDATE SET
 `
 
 type UpsertDownloadClientParams struct {
-	ID       string         `json:"id"`
-	Type     string         `json:"type"`
-	Name     string         `json:"name"`
-	Host     string         `json:"host"`
-	Port     int64          `json:"port"`
-	Username sql.NullString `json:"username"`
-	Password sql.NullString `json:"password"`
-	ApiKey   sql.NullString `json:"api_key"`
-	UseSsl   sql.NullBool   `json:"use_ssl"`
+	ID       string             `json:"id"`
+	Type     string             `json:"type"`
+	Name     string             `json:"name"`
+	Host     string             `json:"host"`
+	Port     int64              `json:"port"`
+	Username sql.NullString     `json:"username"`
+	Password secrets.NullString `json:"password"`
+	ApiKey   secrets.NullString `json:"api_key"`
+	UseSsl   sql.NullBool       `json:"use_ssl"`
 }
 
 func (q *Queries) UpsertDownloadClient(ctx context.Context, arg UpsertDownloadClientParams) error {
@@ -31,7 +31,13 @@ SELECT
   progress,
   time_left,
   status,
-  last_updated
+  last_updated,
+  request_id,
+  episodes,
+  match_confidence,
+  client,
+  size_bytes,
+  started_at
 FROM downloads
 WHERE status = 'missing_from_client'
   AND last_updated < datetime('now', '-24 hours')
@@ -39,17 +45,23 @@ ORDER BY last_updated ASC
 `
 
 type GetOldMissingDownloadsRow struct {
-	ID           string          `json:"id"`
-	Title        string          `json:"title"`
-	TorrentTitle string          `json:"torrent_title"`
-	Source       string          `json:"source"`
-	TmdbID       sql.NullInt64   `json:"tmdb_id"`
-	TvdbID       sql.NullInt64   `json:"tvdb_id"`
-	Hash         sql.NullString  `json:"hash"`
-	Progress     sql.NullFloat64 `json:"progress"`
-	TimeLeft     sql.NullString  `json:"time_left"`
-	Status       sql.NullString  `json:"status"`
-	LastUpdated  sql.NullTime    `json:"last_updated"`
+	ID              string          `json:"id"`
+	Title           string          `json:"title"`
+	TorrentTitle    string          `json:"torrent_title"`
+	Source          string          `json:"source"`
+	TmdbID          sql.NullInt64   `json:"tmdb_id"`
+	TvdbID          sql.NullInt64   `json:"tvdb_id"`
+	Hash            sql.NullString  `json:"hash"`
+	Progress        sql.NullFloat64 `json:"progress"`
+	TimeLeft        sql.NullString  `json:"time_left"`
+	Status          sql.NullString  `json:"status"`
+	LastUpdated     sql.NullTime    `json:"last_updated"`
+	RequestID       sql.NullInt64   `json:"request_id"`
+	Episodes        sql.NullString  `json:"episodes"`
+	MatchConfidence sql.NullString  `json:"match_confidence"`
+	Client          sql.NullString  `json:"client"`
+	SizeBytes       sql.NullInt64   `json:"size_bytes"`
+	StartedAt       sql.NullTime    `json:"started_at"`
 }
 
 func (q *Queries) GetOldMissingDownloads(ctx context.Context) ([]GetOldMissingDownloadsRow, error) {
@@ -73,6 +85,66 @@ func (q *Queries) GetOldMissingDownloads(ctx context.Context) ([]GetOldMissingDo
 			&i.TimeLeft,
 			&i.Status,
 			&i.LastUpdated,
+			&i.RequestID,
+			&i.Episodes,
+			&i.MatchConfidence,
+			&i.Client,
+			&i.SizeBytes,
+			&i.StartedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDownloadRequesters = `-- name: ListDownloadRequesters :many
+SELECT
+  d.id AS download_id,
+  d.season_number,
+  r.user_id,
+  r.on_behalf_of,
+  r.seasons,
+  r.episodes
+FROM downloads d
+JOIN requests r ON r.tmdb_id = d.tmdb_id
+  AND r.media_type = CASE d.source WHEN 'sonarr' THEN 'tv' ELSE 'movie' END
+WHERE r.status != 'denied'
+ORDER BY d.id
+`
+
+type ListDownloadRequestersRow struct {
+	DownloadID   string         `json:"download_id"`
+	SeasonNumber sql.NullInt64  `json:"season_number"`
+	UserID       string         `json:"user_id"`
+	OnBehalfOf   sql.NullString `json:"on_behalf_of"`
+	Seasons      sql.NullString `json:"seasons"`
+	Episodes     sql.NullString `json:"episodes"`
+}
+
+func (q *Queries) ListDownloadRequesters(ctx context.Context) ([]ListDownloadRequestersRow, error) {
+	rows, err := q.db.QueryContext(ctx, listDownloadRequesters)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListDownloadRequestersRow
+	for rows.Next() {
+		var i ListDownloadRequestersRow
+		if err := rows.Scan(
+			&i.DownloadID,
+			&i.SeasonNumber,
+			&i.UserID,
+			&i.OnBehalfOf,
+			&i.Seasons,
+			&i.Episodes,
 		); err != nil {
 			return nil, err
 		}
@@ -99,24 +171,36 @@ SELECT
   progress,
   time_left,
   status,
-  last_updated
+  last_updated,
+  request_id,
+  episodes,
+  match_confidence,
+  client,
+  size_bytes,
+  started_at
 FROM downloads
 WHERE status IS NULL OR status NOT IN ('completed', 'removed')
 ORDER BY last_updated DESC
 `
 
 type ListDownloadsRow struct {
-	ID           string          `json:"id"`
-	Title        string          `json:"title"`
-	TorrentTitle string          `json:"torrent_title"`
-	Source       string          `json:"source"`
-	TmdbID       sql.NullInt64   `json:"tmdb_id"`
-	TvdbID       sql.NullInt64   `json:"tvdb_id"`
-	Hash         sql.NullString  `json:"hash"`
-	Progress     sql.NullFloat64 `json:"progress"`
-	TimeLeft     sql.NullString  `json:"time_left"`
-	Status       sql.NullString  `json:"status"`
-	LastUpdated  sql.NullTime    `json:"last_updated"`
+	ID              string          `json:"id"`
+	Title           string          `json:"title"`
+	TorrentTitle    string          `json:"torrent_title"`
+	Source          string          `json:"source"`
+	TmdbID          sql.NullInt64   `json:"tmdb_id"`
+	TvdbID          sql.NullInt64   `json:"tvdb_id"`
+	Hash            sql.NullString  `json:"hash"`
+	Progress        sql.NullFloat64 `json:"progress"`
+	TimeLeft        sql.NullString  `json:"time_left"`
+	Status          sql.NullString  `json:"status"`
+	LastUpdated     sql.NullTime    `json:"last_updated"`
+	RequestID       sql.NullInt64   `json:"request_id"`
+	Episodes        sql.NullString  `json:"episodes"`
+	MatchConfidence sql.NullString  `json:"match_confidence"`
+	Client          sql.NullString  `json:"client"`
+	SizeBytes       sql.NullInt64   `json:"size_bytes"`
+	StartedAt       sql.NullTime    `json:"started_at"`
 }
 
 func (q *Queries) ListDownloads(ctx context.Context) ([]ListDownloadsRow, error) {
@@ -140,6 +224,12 @@ func (q *Queries) ListDownloads(ctx context.Context) ([]ListDownloadsRow, error)
 			&i.TimeLeft,
 			&i.Status,
 			&i.LastUpdated,
+			&i.RequestID,
+			&i.Episodes,
+			&i.MatchConfidence,
+			&i.Client,
+			&i.SizeBytes,
+			&i.StartedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -155,7 +245,7 @@ func (q *Queries) ListDownloads(ctx context.Context) ([]ListDownloadsRow, error)
 }
 
 const listDownloadsBySource = `-- name: ListDownloadsBySource :many
-SELECT id, title, torrent_title, source, tmdb_id, tvdb_id, hash, progress, time_left, status, last_updated, download_speed, upload_speed, download_size FROM downloads WHERE source = ?
+SELECT id, title, torrent_title, source, tmdb_id, tvdb_id, hash, progress, time_left, status, last_updated, download_speed, upload_speed, download_size, season_number, request_id, episodes, match_confidence, client, size_bytes, started_at FROM downloads WHERE source = ?
 `
 
 func (q *Queries) ListDownloadsBySource(ctx context.Context, source string) ([]Download, error) {
@@ -182,6 +272,13 @@ func (q *Queries) ListDownloadsBySource(ctx context.Context, source string) ([]D
 			&i.DownloadSpeed,
 			&i.UploadSpeed,
 			&i.DownloadSize,
+			&i.SeasonNumber,
+			&i.RequestID,
+			&i.Episodes,
+			&i.MatchConfidence,
+			&i.Client,
+			&i.SizeBytes,
+			&i.StartedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -198,9 +295,9 @@ func (q *Queries) ListDownloadsBySource(ctx context.Context, source string) ([]D
 
 const upsertDownloadQueue = `-- name: UpsertDownloadQueue :exec
 INSERT INTO downloads (
-  id, title, torrent_title, source, tmdb_id, tvdb_id, hash, progress, time_left, status, last_updated
+  id, title, torrent_title, source, tmdb_id, tvdb_id, season_number, request_id, episodes, match_confidence, client, size_bytes, hash, progress, time_left, status, started_at, last_updated
 ) VALUES (
-  ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP
+  ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP
 )
 ON CONFLICT(id) DO UPDATE SET
   title = excluded.title,
@@ -208,24 +305,37 @@ ON CONFLICT(id) DO UPDATE SET
   source = excluded.source,
   tmdb_id = excluded.tmdb_id,
   tvdb_id = excluded.tvdb_id,
+  season_number = excluded.season_number,
+  request_id = excluded.request_id,
+  episodes = excluded.episodes,
+  match_confidence = excluded.match_confidence,
+  client = excluded.client,
+  size_bytes = excluded.size_bytes,
   hash = excluded.hash,
   progress = excluded.progress,
   time_left = excluded.time_left,
   status = excluded.status,
+  started_at = COALESCE(downloads.started_at, excluded.started_at),
   last_updated = CURRENT_TIMESTAMP
 `
 
 type UpsertDownloadQueueParams struct {
-	ID           string          `json:"id"`
-	Title        string          `json:"title"`
-	TorrentTitle string          `json:"torrent_title"`
-	Source       string          `json:"source"`
-	TmdbID       sql.NullInt64   `json:"tmdb_id"`
-	TvdbID       sql.NullInt64   `json:"tvdb_id"`
-	Hash         sql.NullString  `json:"hash"`
-	Progress     sql.NullFloat64 `json:"progress"`
-	TimeLeft     sql.NullString  `json:"time_left"`
-	Status       sql.NullString  `json:"status"`
+	ID              string          `json:"id"`
+	Title           string          `json:"title"`
+	TorrentTitle    string          `json:"torrent_title"`
+	Source          string          `json:"source"`
+	TmdbID          sql.NullInt64   `json:"tmdb_id"`
+	TvdbID          sql.NullInt64   `json:"tvdb_id"`
+	SeasonNumber    sql.NullInt64   `json:"season_number"`
+	RequestID       sql.NullInt64   `json:"request_id"`
+	Episodes        sql.NullString  `json:"episodes"`
+	MatchConfidence sql.NullString  `json:"match_confidence"`
+	Client          sql.NullString  `json:"client"`
+	SizeBytes       sql.NullInt64   `json:"size_bytes"`
+	Hash            sql.NullString  `json:"hash"`
+	Progress        sql.NullFloat64 `json:"progress"`
+	TimeLeft        sql.NullString  `json:"time_left"`
+	Status          sql.NullString  `json:"status"`
 }
 
 func (q *Queries) UpsertDownloadQueue(ctx context.Context, arg UpsertDownloadQueueParams) error {
@@ -236,6 +346,12 @@ func (q *Queries) UpsertDownloadQueue(ctx context.Context, arg UpsertDownloadQue
 		arg.Source,
 		arg.TmdbID,
 		arg.TvdbID,
+		arg.SeasonNumber,
+		arg.RequestID,
+		arg.Episodes,
+		arg.MatchConfidence,
+		arg.Client,
+		arg.SizeBytes,
 		arg.Hash,
 		arg.Progress,
 		arg.TimeLeft,
@@ -8,13 +8,27 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"strings"
 	"time"
 )
 
+const checkMedia4KInLibrary = `-- name: CheckMedia4KInLibrary :one
+SELECT COUNT(*) > 0 as in_library
+FROM library_items
+WHERE tmdb_id = ? AND is_4k = TRUE
+`
+
+func (q *Queries) CheckMedia4KInLibrary(ctx context.Context, tmdbID sql.NullString) (bool, error) {
+	row := q.db.QueryRowContext(ctx, checkMedia4KInLibrary, tmdbID)
+	var in_library bool
+	err := row.Scan(&in_library)
+	return in_library, err
+}
+
 const checkMediaInLibrary = `-- name: CheckMediaInLibrary :one
 SELECT COUNT(*) > 0 as in_library 
 FROM library_items 
-WHERE tmdb_id = ?
+WHERE tmdb_id = ? AND COALESCE(is_4k, FALSE) = FALSE
 `
 
 func (q *Queries) CheckMediaInLibrary(ctx context.Context, tmdbID sql.NullString) (bool, error) {
@@ -442,6 +456,54 @@ func (q *Queries) CreateLibraryItemFull(ctx context.Context, arg CreateLibraryIt
 	return i, err
 }
 
+const getLibraryAvailability = `-- name: GetLibraryAvailability :many
+SELECT tmdb_id,
+       MAX(COALESCE(is_4k, FALSE) = FALSE) as in_library,
+       MAX(COALESCE(is_4k, FALSE) = TRUE) as in_library_4k
+FROM library_items
+WHERE tmdb_id IN (/*SLICE:tmdb_ids*/?)
+GROUP BY tmdb_id
+`
+
+type GetLibraryAvailabilityRow struct {
+	TmdbID      sql.NullString `json:"tmdb_id"`
+	InLibrary   bool           `json:"in_library"`
+	InLibrary4k bool           `json:"in_library_4k"`
+}
+
+func (q *Queries) GetLibraryAvailability(ctx context.Context, tmdbIds []sql.NullString) ([]GetLibraryAvailabilityRow, error) {
+	query := getLibraryAvailability
+	var queryParams []interface{}
+	if len(tmdbIds) > 0 {
+		for _, v := range tmdbIds {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:tmdb_ids*/?", strings.Repeat(",?", len(tmdbIds))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:tmdb_ids*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetLibraryAvailabilityRow
+	for rows.Next() {
+		var i GetLibraryAvailabilityRow
+		if err := rows.Scan(&i.TmdbID, &i.InLibrary, &i.InLibrary4k); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getLibraryItemByTMDBID = `-- name: GetLibraryItemByTMDBID :one
 SELECT id, name, original_title, type, parent_id, series_id, season_number, episode_number, year, premiere_date, end_date, 
        community_rating, critic_rating, official_rating, overview, tagline, genres, studios, people,
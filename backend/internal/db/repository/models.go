@@ -7,19 +7,44 @@ package repository
 import (
 	"database/sql"
 	"time"
+
+	"github.com/mahcks/serra/internal/secrets"
 )
 
 type ArrService struct {
-	ID                  string       `json:"id"`
-	Type                string       `json:"type"`
-	Name                string       `json:"name"`
-	BaseUrl             string       `json:"base_url"`
-	ApiKey              string       `json:"api_key"`
-	QualityProfile      string       `json:"quality_profile"`
-	RootFolderPath      string       `json:"root_folder_path"`
-	MinimumAvailability string       `json:"minimum_availability"`
-	Is4k                bool         `json:"is_4k"`
-	CreatedAt           sql.NullTime `json:"created_at"`
+	ID                  string         `json:"id"`
+	Type                string         `json:"type"`
+	Name                string         `json:"name"`
+	BaseUrl             string         `json:"base_url"`
+	ApiKey              secrets.String `json:"api_key"`
+	QualityProfile      string         `json:"quality_profile"`
+	RootFolderPath      string         `json:"root_folder_path"`
+	MinimumAvailability string         `json:"minimum_availability"`
+	Is4k                bool           `json:"is_4k"`
+	CreatedAt           sql.NullTime   `json:"created_at"`
+}
+
+type CollectionRequest struct {
+	ID              int64          `json:"id"`
+	CollectionID    int64          `json:"collection_id"`
+	UserID          string         `json:"user_id"`
+	Name            string         `json:"name"`
+	PosterUrl       sql.NullString `json:"poster_url"`
+	Is4k            bool           `json:"is_4k"`
+	WatchUnreleased bool           `json:"watch_unreleased"`
+	CreatedAt       sql.NullTime   `json:"created_at"`
+	UpdatedAt       sql.NullTime   `json:"updated_at"`
+}
+
+type CollectionRequestItem struct {
+	RequestID           int64 `json:"request_id"`
+	CollectionRequestID int64 `json:"collection_request_id"`
+	TmdbID              int64 `json:"tmdb_id"`
+}
+
+type CsrfToken struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
 }
 
 type DefaultPermission struct {
@@ -30,33 +55,57 @@ type DefaultPermission struct {
 }
 
 type Download struct {
-	ID            string          `json:"id"`
-	Title         string          `json:"title"`
-	TorrentTitle  string          `json:"torrent_title"`
-	Source        string          `json:"source"`
-	TmdbID        sql.NullInt64   `json:"tmdb_id"`
-	TvdbID        sql.NullInt64   `json:"tvdb_id"`
-	Hash          sql.NullString  `json:"hash"`
-	Progress      sql.NullFloat64 `json:"progress"`
-	TimeLeft      sql.NullString  `json:"time_left"`
-	Status        sql.NullString  `json:"status"`
-	LastUpdated   sql.NullTime    `json:"last_updated"`
-	DownloadSpeed sql.NullInt64   `json:"download_speed"`
-	UploadSpeed   sql.NullInt64   `json:"upload_speed"`
-	DownloadSize  sql.NullInt64   `json:"download_size"`
+	ID              string          `json:"id"`
+	Title           string          `json:"title"`
+	TorrentTitle    string          `json:"torrent_title"`
+	Source          string          `json:"source"`
+	TmdbID          sql.NullInt64   `json:"tmdb_id"`
+	TvdbID          sql.NullInt64   `json:"tvdb_id"`
+	Hash            sql.NullString  `json:"hash"`
+	Progress        sql.NullFloat64 `json:"progress"`
+	TimeLeft        sql.NullString  `json:"time_left"`
+	Status          sql.NullString  `json:"status"`
+	LastUpdated     sql.NullTime    `json:"last_updated"`
+	DownloadSpeed   sql.NullInt64   `json:"download_speed"`
+	UploadSpeed     sql.NullInt64   `json:"upload_speed"`
+	DownloadSize    sql.NullInt64   `json:"download_size"`
+	SeasonNumber    sql.NullInt64   `json:"season_number"`
+	RequestID       sql.NullInt64   `json:"request_id"`
+	Episodes        sql.NullString  `json:"episodes"`
+	MatchConfidence sql.NullString  `json:"match_confidence"`
+	Client          sql.NullString  `json:"client"`
+	SizeBytes       sql.NullInt64   `json:"size_bytes"`
+	StartedAt       sql.NullTime    `json:"started_at"`
 }
 
 type DownloadClient struct {
-	ID        string         `json:"id"`
-	Type      string         `json:"type"`
-	Name      string         `json:"name"`
-	Host      string         `json:"host"`
-	Port      int64          `json:"port"`
-	Username  sql.NullString `json:"username"`
-	Password  sql.NullString `json:"password"`
-	ApiKey    sql.NullString `json:"api_key"`
-	UseSsl    sql.NullBool   `json:"use_ssl"`
-	CreatedAt sql.NullTime   `json:"created_at"`
+	ID        string             `json:"id"`
+	Type      string             `json:"type"`
+	Name      string             `json:"name"`
+	Host      string             `json:"host"`
+	Port      int64              `json:"port"`
+	Username  sql.NullString     `json:"username"`
+	Password  secrets.NullString `json:"password"`
+	ApiKey    secrets.NullString `json:"api_key"`
+	UseSsl    sql.NullBool       `json:"use_ssl"`
+	CreatedAt sql.NullTime       `json:"created_at"`
+}
+
+type DownloadHistory struct {
+	ID           int64          `json:"id"`
+	DownloadID   string         `json:"download_id"`
+	RequestID    sql.NullInt64  `json:"request_id"`
+	Title        string         `json:"title"`
+	ReleaseName  string         `json:"release_name"`
+	Source       string         `json:"source"`
+	Client       sql.NullString `json:"client"`
+	TmdbID       sql.NullInt64  `json:"tmdb_id"`
+	Episodes     sql.NullString `json:"episodes"`
+	SizeBytes    sql.NullInt64  `json:"size_bytes"`
+	AverageSpeed sql.NullInt64  `json:"average_speed"`
+	StartedAt    time.Time      `json:"started_at"`
+	FinishedAt   time.Time      `json:"finished_at"`
+	Outcome      string         `json:"outcome"`
 }
 
 type DriveAlert struct {
@@ -84,6 +133,13 @@ type DriveUsageHistory struct {
 	RecordedAt         sql.NullTime    `json:"recorded_at"`
 }
 
+type ImportedRequest struct {
+	Source     string        `json:"source"`
+	ExternalID int64         `json:"external_id"`
+	RequestID  sql.NullInt64 `json:"request_id"`
+	ImportedAt time.Time     `json:"imported_at"`
+}
+
 type Invitation struct {
 	ID              int64          `json:"id"`
 	Email           string         `json:"email"`
@@ -99,6 +155,26 @@ type Invitation struct {
 	UpdatedAt       sql.NullTime   `json:"updated_at"`
 }
 
+type JobRun struct {
+	ID          int64          `json:"id"`
+	JobName     string         `json:"job_name"`
+	TriggerType string         `json:"trigger_type"`
+	StartedAt   time.Time      `json:"started_at"`
+	FinishedAt  sql.NullTime   `json:"finished_at"`
+	DurationMs  int64          `json:"duration_ms"`
+	Outcome     string         `json:"outcome"`
+	Attempts    int64          `json:"attempts"`
+	Error       sql.NullString `json:"error"`
+	Summary     sql.NullString `json:"summary"`
+	CreatedAt   sql.NullTime   `json:"created_at"`
+}
+
+type Lease struct {
+	Name      string    `json:"name"`
+	Holder    string    `json:"holder"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
 type LibraryItem struct {
 	ID                     string          `json:"id"`
 	Name                   string          `json:"name"`
@@ -214,22 +290,39 @@ type PopularityTrend struct {
 	CreatedAt          sql.NullTime    `json:"created_at"`
 }
 
+type RateLimitHit struct {
+	ID    int64     `json:"id"`
+	Key   string    `json:"key"`
+	HitAt time.Time `json:"hit_at"`
+}
+
 type Request struct {
-	ID             int64          `json:"id"`
-	UserID         string         `json:"user_id"`
-	MediaType      string         `json:"media_type"`
-	TmdbID         sql.NullInt64  `json:"tmdb_id"`
-	Title          sql.NullString `json:"title"`
-	Status         string         `json:"status"`
-	Notes          sql.NullString `json:"notes"`
-	CreatedAt      time.Time      `json:"created_at"`
-	UpdatedAt      time.Time      `json:"updated_at"`
-	FulfilledAt    sql.NullTime   `json:"fulfilled_at"`
-	ApproverID     sql.NullString `json:"approver_id"`
-	OnBehalfOf     sql.NullString `json:"on_behalf_of"`
-	PosterUrl      sql.NullString `json:"poster_url"`
-	Seasons        sql.NullString `json:"seasons"`
-	SeasonStatuses sql.NullString `json:"season_statuses"`
+	ID                  int64          `json:"id"`
+	UserID              string         `json:"user_id"`
+	MediaType           string         `json:"media_type"`
+	TmdbID              sql.NullInt64  `json:"tmdb_id"`
+	Title               sql.NullString `json:"title"`
+	Status              string         `json:"status"`
+	Notes               sql.NullString `json:"notes"`
+	CreatedAt           time.Time      `json:"created_at"`
+	UpdatedAt           time.Time      `json:"updated_at"`
+	FulfilledAt         sql.NullTime   `json:"fulfilled_at"`
+	ApproverID          sql.NullString `json:"approver_id"`
+	OnBehalfOf          sql.NullString `json:"on_behalf_of"`
+	PosterUrl           sql.NullString `json:"poster_url"`
+	Seasons             sql.NullString `json:"seasons"`
+	SeasonStatuses      sql.NullString `json:"season_statuses"`
+	Is4k                bool           `json:"is_4k"`
+	QualityProfileID    sql.NullInt64  `json:"quality_profile_id"`
+	RootFolderPath      sql.NullString `json:"root_folder_path"`
+	Tags                sql.NullString `json:"tags"`
+	SeriesType          sql.NullString `json:"series_type"`
+	Episodes            sql.NullString `json:"episodes"`
+	FollowShow          bool           `json:"follow_show"`
+	ParentRequestID     sql.NullInt64  `json:"parent_request_id"`
+	Priority            string         `json:"priority"`
+	EpisodesMonitoredAt sql.NullTime   `json:"episodes_monitored_at"`
+	DenyReason          sql.NullString `json:"deny_reason"`
 }
 
 type RequestAnalytic struct {
@@ -260,6 +353,34 @@ type RequestMetric struct {
 	Timestamp             sql.NullTime   `json:"timestamp"`
 }
 
+type RequestRouting struct {
+	RequestID      int64          `json:"request_id"`
+	ArrServiceID   sql.NullString `json:"arr_service_id"`
+	RoutingRuleID  sql.NullInt64  `json:"routing_rule_id"`
+	RootFolderPath sql.NullString `json:"root_folder_path"`
+	RoutedAt       sql.NullTime   `json:"routed_at"`
+}
+
+type RoutingRule struct {
+	ID                int64          `json:"id"`
+	Name              string         `json:"name"`
+	MediaType         string         `json:"media_type"`
+	Priority          int64          `json:"priority"`
+	Enabled           bool           `json:"enabled"`
+	IsDefault         bool           `json:"is_default"`
+	Genres            sql.NullString `json:"genres"`
+	OriginalLanguages sql.NullString `json:"original_languages"`
+	Keywords          sql.NullString `json:"keywords"`
+	Certifications    sql.NullString `json:"certifications"`
+	UserIds           sql.NullString `json:"user_ids"`
+	UserTypes         sql.NullString `json:"user_types"`
+	ArrServiceID      string         `json:"arr_service_id"`
+	RootFolderPath    sql.NullString `json:"root_folder_path"`
+	QualityProfileID  sql.NullInt64  `json:"quality_profile_id"`
+	CreatedAt         sql.NullTime   `json:"created_at"`
+	UpdatedAt         sql.NullTime   `json:"updated_at"`
+}
+
 type SeasonAvailability struct {
 	ID                int64         `json:"id"`
 	TmdbID            int64         `json:"tmdb_id"`
@@ -281,6 +402,12 @@ type Setting struct {
 	Value string `json:"value"`
 }
 
+type SharedState struct {
+	Key       string    `json:"key"`
+	Value     string    `json:"value"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
 type SystemMetric struct {
 	ID          int64          `json:"id"`
 	MetricType  string         `json:"metric_type"`
@@ -352,9 +479,28 @@ type UserPermission struct {
 	PermissionID string `json:"permission_id"`
 }
 
+type UserRequestPriority struct {
+	UserID    string       `json:"user_id"`
+	Priority  string       `json:"priority"`
+	UpdatedAt sql.NullTime `json:"updated_at"`
+}
+
 type UserSetting struct {
 	UserID    string       `json:"user_id"`
 	Key       string       `json:"key"`
 	Value     string       `json:"value"`
 	UpdatedAt sql.NullTime `json:"updated_at"`
 }
+
+type WatchlistItem struct {
+	ID                  int64          `json:"id"`
+	UserID              string         `json:"user_id"`
+	MediaType           string         `json:"media_type"`
+	TmdbID              int64          `json:"tmdb_id"`
+	Title               string         `json:"title"`
+	PosterUrl           sql.NullString `json:"poster_url"`
+	AutoRequest         bool           `json:"auto_request"`
+	RequestID           sql.NullInt64  `json:"request_id"`
+	AvailableNotifiedAt sql.NullTime   `json:"available_notified_at"`
+	CreatedAt           sql.NullTime   `json:"created_at"`
+}
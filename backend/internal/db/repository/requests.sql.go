@@ -8,12 +8,14 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"strings"
+	"time"
 )
 
 const checkExistingRequest = `-- name: CheckExistingRequest :one
-SELECT id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses
+SELECT id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses, is_4k, quality_profile_id, root_folder_path, tags, series_type, episodes, follow_show, parent_request_id, priority, episodes_monitored_at, deny_reason
 FROM requests
-WHERE media_type = ? AND tmdb_id = ? AND user_id = ? AND seasons = ?
+WHERE media_type = ? AND tmdb_id = ? AND user_id = ? AND seasons = ? AND episodes IS ? AND is_4k = ?
 `
 
 type CheckExistingRequestParams struct {
@@ -21,6 +23,8 @@ type CheckExistingRequestParams struct {
 	TmdbID    sql.NullInt64  `json:"tmdb_id"`
 	UserID    string         `json:"user_id"`
 	Seasons   sql.NullString `json:"seasons"`
+	Episodes  sql.NullString `json:"episodes"`
+	Is4k      bool           `json:"is_4k"`
 }
 
 func (q *Queries) CheckExistingRequest(ctx context.Context, arg CheckExistingRequestParams) (Request, error) {
@@ -29,6 +33,8 @@ func (q *Queries) CheckExistingRequest(ctx context.Context, arg CheckExistingReq
 		arg.TmdbID,
 		arg.UserID,
 		arg.Seasons,
+		arg.Episodes,
+		arg.Is4k,
 	)
 	var i Request
 	err := row.Scan(
@@ -47,24 +53,41 @@ func (q *Queries) CheckExistingRequest(ctx context.Context, arg CheckExistingReq
 		&i.PosterUrl,
 		&i.Seasons,
 		&i.SeasonStatuses,
+		&i.Is4k,
+		&i.QualityProfileID,
+		&i.RootFolderPath,
+		&i.Tags,
+		&i.SeriesType,
+		&i.Episodes,
+		&i.FollowShow,
+		&i.ParentRequestID,
+		&i.Priority,
+		&i.EpisodesMonitoredAt,
+		&i.DenyReason,
 	)
 	return i, err
 }
 
 const checkExistingRequestAnySeasons = `-- name: CheckExistingRequestAnySeasons :many
-SELECT id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses
+SELECT id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses, is_4k, quality_profile_id, root_folder_path, tags, series_type, episodes, follow_show, parent_request_id, priority, episodes_monitored_at, deny_reason
 FROM requests
-WHERE media_type = ? AND tmdb_id = ? AND user_id = ?
+WHERE media_type = ? AND tmdb_id = ? AND user_id = ? AND is_4k = ?
 `
 
 type CheckExistingRequestAnySeasonsParams struct {
 	MediaType string        `json:"media_type"`
 	TmdbID    sql.NullInt64 `json:"tmdb_id"`
 	UserID    string        `json:"user_id"`
+	Is4k      bool          `json:"is_4k"`
 }
 
 func (q *Queries) CheckExistingRequestAnySeasons(ctx context.Context, arg CheckExistingRequestAnySeasonsParams) ([]Request, error) {
-	rows, err := q.db.QueryContext(ctx, checkExistingRequestAnySeasons, arg.MediaType, arg.TmdbID, arg.UserID)
+	rows, err := q.db.QueryContext(ctx, checkExistingRequestAnySeasons,
+		arg.MediaType,
+		arg.TmdbID,
+		arg.UserID,
+		arg.Is4k,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -88,6 +111,17 @@ func (q *Queries) CheckExistingRequestAnySeasons(ctx context.Context, arg CheckE
 			&i.PosterUrl,
 			&i.Seasons,
 			&i.SeasonStatuses,
+			&i.Is4k,
+			&i.QualityProfileID,
+			&i.RootFolderPath,
+			&i.Tags,
+			&i.SeriesType,
+			&i.Episodes,
+			&i.FollowShow,
+			&i.ParentRequestID,
+			&i.Priority,
+			&i.EpisodesMonitoredAt,
+			&i.DenyReason,
 		); err != nil {
 			return nil, err
 		}
@@ -146,39 +180,72 @@ func (q *Queries) CheckMultipleUserRequests(ctx context.Context, arg CheckMultip
 const checkUserRequestExists = `-- name: CheckUserRequestExists :one
 SELECT COUNT(*) > 0 as requested
 FROM requests 
-WHERE tmdb_id = ? AND media_type = ? AND user_id = ?
+WHERE tmdb_id = ? AND media_type = ? AND user_id = ? AND is_4k = ?
 `
 
 type CheckUserRequestExistsParams struct {
 	TmdbID    sql.NullInt64 `json:"tmdb_id"`
 	MediaType string        `json:"media_type"`
 	UserID    string        `json:"user_id"`
+	Is4k      bool          `json:"is_4k"`
 }
 
 func (q *Queries) CheckUserRequestExists(ctx context.Context, arg CheckUserRequestExistsParams) (bool, error) {
-	row := q.db.QueryRowContext(ctx, checkUserRequestExists, arg.TmdbID, arg.MediaType, arg.UserID)
+	row := q.db.QueryRowContext(ctx, checkUserRequestExists,
+		arg.TmdbID,
+		arg.MediaType,
+		arg.UserID,
+		arg.Is4k,
+	)
 	var requested bool
 	err := row.Scan(&requested)
 	return requested, err
 }
 
+const countUserRequestsByMediaType = `-- name: CountUserRequestsByMediaType :one
+SELECT COUNT(*)
+FROM requests
+WHERE user_id = ? AND media_type = ? AND status NOT IN ('denied', 'failed')
+`
+
+type CountUserRequestsByMediaTypeParams struct {
+	UserID    string `json:"user_id"`
+	MediaType string `json:"media_type"`
+}
+
+func (q *Queries) CountUserRequestsByMediaType(ctx context.Context, arg CountUserRequestsByMediaTypeParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countUserRequestsByMediaType, arg.UserID, arg.MediaType)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createRequest = `-- name: CreateRequest :one
-INSERT INTO requests (user_id, media_type, tmdb_id, title, status, notes, poster_url, on_behalf_of, seasons, season_statuses)
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-RETURNING id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses
+INSERT INTO requests (user_id, media_type, tmdb_id, title, status, notes, poster_url, on_behalf_of, seasons, season_statuses, is_4k, quality_profile_id, root_folder_path, tags, series_type, episodes, follow_show, parent_request_id, priority)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses, is_4k, quality_profile_id, root_folder_path, tags, series_type, episodes, follow_show, parent_request_id, priority, episodes_monitored_at, deny_reason
 `
 
 type CreateRequestParams struct {
-	UserID         string         `json:"user_id"`
-	MediaType      string         `json:"media_type"`
-	TmdbID         sql.NullInt64  `json:"tmdb_id"`
-	Title          sql.NullString `json:"title"`
-	Status         string         `json:"status"`
-	Notes          sql.NullString `json:"notes"`
-	PosterUrl      sql.NullString `json:"poster_url"`
-	OnBehalfOf     sql.NullString `json:"on_behalf_of"`
-	Seasons        sql.NullString `json:"seasons"`
-	SeasonStatuses sql.NullString `json:"season_statuses"`
+	UserID           string         `json:"user_id"`
+	MediaType        string         `json:"media_type"`
+	TmdbID           sql.NullInt64  `json:"tmdb_id"`
+	Title            sql.NullString `json:"title"`
+	Status           string         `json:"status"`
+	Notes            sql.NullString `json:"notes"`
+	PosterUrl        sql.NullString `json:"poster_url"`
+	OnBehalfOf       sql.NullString `json:"on_behalf_of"`
+	Seasons          sql.NullString `json:"seasons"`
+	SeasonStatuses   sql.NullString `json:"season_statuses"`
+	Is4k             bool           `json:"is_4k"`
+	QualityProfileID sql.NullInt64  `json:"quality_profile_id"`
+	RootFolderPath   sql.NullString `json:"root_folder_path"`
+	Tags             sql.NullString `json:"tags"`
+	SeriesType       sql.NullString `json:"series_type"`
+	Episodes         sql.NullString `json:"episodes"`
+	FollowShow       bool           `json:"follow_show"`
+	ParentRequestID  sql.NullInt64  `json:"parent_request_id"`
+	Priority         string         `json:"priority"`
 }
 
 func (q *Queries) CreateRequest(ctx context.Context, arg CreateRequestParams) (Request, error) {
@@ -193,6 +260,15 @@ func (q *Queries) CreateRequest(ctx context.Context, arg CreateRequestParams) (R
 		arg.OnBehalfOf,
 		arg.Seasons,
 		arg.SeasonStatuses,
+		arg.Is4k,
+		arg.QualityProfileID,
+		arg.RootFolderPath,
+		arg.Tags,
+		arg.SeriesType,
+		arg.Episodes,
+		arg.FollowShow,
+		arg.ParentRequestID,
+		arg.Priority,
 	)
 	var i Request
 	err := row.Scan(
@@ -211,6 +287,17 @@ func (q *Queries) CreateRequest(ctx context.Context, arg CreateRequestParams) (R
 		&i.PosterUrl,
 		&i.Seasons,
 		&i.SeasonStatuses,
+		&i.Is4k,
+		&i.QualityProfileID,
+		&i.RootFolderPath,
+		&i.Tags,
+		&i.SeriesType,
+		&i.Episodes,
+		&i.FollowShow,
+		&i.ParentRequestID,
+		&i.Priority,
+		&i.EpisodesMonitoredAt,
+		&i.DenyReason,
 	)
 	return i, err
 }
@@ -228,7 +315,7 @@ const fulfillRequest = `-- name: FulfillRequest :one
 UPDATE requests
 SET status = 'fulfilled', fulfilled_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
 WHERE id = ?
-RETURNING id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses
+RETURNING id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses, is_4k, quality_profile_id, root_folder_path, tags, series_type, episodes, follow_show, parent_request_id, priority, episodes_monitored_at, deny_reason
 `
 
 func (q *Queries) FulfillRequest(ctx context.Context, id int64) (Request, error) {
@@ -250,12 +337,23 @@ func (q *Queries) FulfillRequest(ctx context.Context, id int64) (Request, error)
 		&i.PosterUrl,
 		&i.Seasons,
 		&i.SeasonStatuses,
+		&i.Is4k,
+		&i.QualityProfileID,
+		&i.RootFolderPath,
+		&i.Tags,
+		&i.SeriesType,
+		&i.Episodes,
+		&i.FollowShow,
+		&i.ParentRequestID,
+		&i.Priority,
+		&i.EpisodesMonitoredAt,
+		&i.DenyReason,
 	)
 	return i, err
 }
 
 const getAllRequests = `-- name: GetAllRequests :many
-SELECT id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses
+SELECT id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses, is_4k, quality_profile_id, root_folder_path, tags, series_type, episodes, follow_show, parent_request_id, priority, episodes_monitored_at, deny_reason
 FROM requests
 ORDER BY created_at DESC
 `
@@ -285,6 +383,135 @@ func (q *Queries) GetAllRequests(ctx context.Context) ([]Request, error) {
 			&i.PosterUrl,
 			&i.Seasons,
 			&i.SeasonStatuses,
+			&i.Is4k,
+			&i.QualityProfileID,
+			&i.RootFolderPath,
+			&i.Tags,
+			&i.SeriesType,
+			&i.Episodes,
+			&i.FollowShow,
+			&i.ParentRequestID,
+			&i.Priority,
+			&i.EpisodesMonitoredAt,
+			&i.DenyReason,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFollowedRequests = `-- name: GetFollowedRequests :many
+SELECT id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses, is_4k, quality_profile_id, root_folder_path, tags, series_type, episodes, follow_show, parent_request_id, priority, episodes_monitored_at, deny_reason
+FROM requests
+WHERE media_type = 'tv' AND follow_show = TRUE AND status IN ('approved', 'processing', 'fulfilled')
+ORDER BY created_at ASC
+`
+
+func (q *Queries) GetFollowedRequests(ctx context.Context) ([]Request, error) {
+	rows, err := q.db.QueryContext(ctx, getFollowedRequests)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Request
+	for rows.Next() {
+		var i Request
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.MediaType,
+			&i.TmdbID,
+			&i.Title,
+			&i.Status,
+			&i.Notes,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.FulfilledAt,
+			&i.ApproverID,
+			&i.OnBehalfOf,
+			&i.PosterUrl,
+			&i.Seasons,
+			&i.SeasonStatuses,
+			&i.Is4k,
+			&i.QualityProfileID,
+			&i.RootFolderPath,
+			&i.Tags,
+			&i.SeriesType,
+			&i.Episodes,
+			&i.FollowShow,
+			&i.ParentRequestID,
+			&i.Priority,
+			&i.EpisodesMonitoredAt,
+			&i.DenyReason,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getOverdueRequests = `-- name: GetOverdueRequests :many
+SELECT id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses, is_4k, quality_profile_id, root_folder_path, tags, series_type, episodes, follow_show, parent_request_id, priority, episodes_monitored_at, deny_reason
+FROM requests
+WHERE status = 'pending' AND created_at <= ?
+  AND NOT EXISTS (
+    SELECT 1 FROM request_metrics rm
+    WHERE rm.request_id = requests.id AND rm.status_change = 'sla_escalated'
+  )
+ORDER BY created_at ASC
+`
+
+func (q *Queries) GetOverdueRequests(ctx context.Context, createdAt time.Time) ([]Request, error) {
+	rows, err := q.db.QueryContext(ctx, getOverdueRequests, createdAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Request
+	for rows.Next() {
+		var i Request
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.MediaType,
+			&i.TmdbID,
+			&i.Title,
+			&i.Status,
+			&i.Notes,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.FulfilledAt,
+			&i.ApproverID,
+			&i.OnBehalfOf,
+			&i.PosterUrl,
+			&i.Seasons,
+			&i.SeasonStatuses,
+			&i.Is4k,
+			&i.QualityProfileID,
+			&i.RootFolderPath,
+			&i.Tags,
+			&i.SeriesType,
+			&i.Episodes,
+			&i.FollowShow,
+			&i.ParentRequestID,
+			&i.Priority,
+			&i.EpisodesMonitoredAt,
+			&i.DenyReason,
 		); err != nil {
 			return nil, err
 		}
@@ -300,10 +527,10 @@ func (q *Queries) GetAllRequests(ctx context.Context) ([]Request, error) {
 }
 
 const getPendingRequests = `-- name: GetPendingRequests :many
-SELECT id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses
+SELECT id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses, is_4k, quality_profile_id, root_folder_path, tags, series_type, episodes, follow_show, parent_request_id, priority, episodes_monitored_at, deny_reason
 FROM requests
 WHERE status = 'pending'
-ORDER BY created_at ASC
+ORDER BY CASE priority WHEN 'urgent' THEN 0 WHEN 'high' THEN 1 WHEN 'normal' THEN 2 ELSE 3 END, created_at ASC
 `
 
 func (q *Queries) GetPendingRequests(ctx context.Context) ([]Request, error) {
@@ -331,6 +558,17 @@ func (q *Queries) GetPendingRequests(ctx context.Context) ([]Request, error) {
 			&i.PosterUrl,
 			&i.Seasons,
 			&i.SeasonStatuses,
+			&i.Is4k,
+			&i.QualityProfileID,
+			&i.RootFolderPath,
+			&i.Tags,
+			&i.SeriesType,
+			&i.Episodes,
+			&i.FollowShow,
+			&i.ParentRequestID,
+			&i.Priority,
+			&i.EpisodesMonitoredAt,
+			&i.DenyReason,
 		); err != nil {
 			return nil, err
 		}
@@ -346,7 +584,7 @@ func (q *Queries) GetPendingRequests(ctx context.Context) ([]Request, error) {
 }
 
 const getRecentRequests = `-- name: GetRecentRequests :many
-SELECT id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses
+SELECT id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses, is_4k, quality_profile_id, root_folder_path, tags, series_type, episodes, follow_show, parent_request_id, priority, episodes_monitored_at, deny_reason
 FROM requests
 WHERE created_at >= datetime('now', '-7 days')
 ORDER BY created_at DESC
@@ -378,6 +616,17 @@ func (q *Queries) GetRecentRequests(ctx context.Context, limit int64) ([]Request
 			&i.PosterUrl,
 			&i.Seasons,
 			&i.SeasonStatuses,
+			&i.Is4k,
+			&i.QualityProfileID,
+			&i.RootFolderPath,
+			&i.Tags,
+			&i.SeriesType,
+			&i.Episodes,
+			&i.FollowShow,
+			&i.ParentRequestID,
+			&i.Priority,
+			&i.EpisodesMonitoredAt,
+			&i.DenyReason,
 		); err != nil {
 			return nil, err
 		}
@@ -393,7 +642,7 @@ func (q *Queries) GetRecentRequests(ctx context.Context, limit int64) ([]Request
 }
 
 const getRequestByID = `-- name: GetRequestByID :one
-SELECT id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses
+SELECT id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses, is_4k, quality_profile_id, root_folder_path, tags, series_type, episodes, follow_show, parent_request_id, priority, episodes_monitored_at, deny_reason
 FROM requests
 WHERE id = ?
 `
@@ -417,6 +666,17 @@ func (q *Queries) GetRequestByID(ctx context.Context, id int64) (Request, error)
 		&i.PosterUrl,
 		&i.Seasons,
 		&i.SeasonStatuses,
+		&i.Is4k,
+		&i.QualityProfileID,
+		&i.RootFolderPath,
+		&i.Tags,
+		&i.SeriesType,
+		&i.Episodes,
+		&i.FollowShow,
+		&i.ParentRequestID,
+		&i.Priority,
+		&i.EpisodesMonitoredAt,
+		&i.DenyReason,
 	)
 	return i, err
 }
@@ -452,8 +712,83 @@ func (q *Queries) GetRequestStatistics(ctx context.Context) (GetRequestStatistic
 	return i, err
 }
 
+const getRequestsByFilter = `-- name: GetRequestsByFilter :many
+SELECT id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses, is_4k, quality_profile_id, root_folder_path, tags, series_type, episodes, follow_show, parent_request_id, priority, episodes_monitored_at, deny_reason
+FROM requests
+WHERE (?1 IS NULL OR status = ?1)
+  AND (?2 IS NULL OR media_type = ?2)
+  AND (?3 IS NULL OR user_id = ?3)
+  AND (?4 IS NULL OR created_at >= ?4)
+  AND (?5 IS NULL OR created_at < ?5)
+ORDER BY created_at ASC
+`
+
+type GetRequestsByFilterParams struct {
+	Status        sql.NullString `json:"status"`
+	MediaType     sql.NullString `json:"media_type"`
+	UserID        sql.NullString `json:"user_id"`
+	CreatedAfter  sql.NullTime   `json:"created_after"`
+	CreatedBefore sql.NullTime   `json:"created_before"`
+}
+
+func (q *Queries) GetRequestsByFilter(ctx context.Context, arg GetRequestsByFilterParams) ([]Request, error) {
+	rows, err := q.db.QueryContext(ctx, getRequestsByFilter,
+		arg.Status,
+		arg.MediaType,
+		arg.UserID,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Request
+	for rows.Next() {
+		var i Request
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.MediaType,
+			&i.TmdbID,
+			&i.Title,
+			&i.Status,
+			&i.Notes,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.FulfilledAt,
+			&i.ApproverID,
+			&i.OnBehalfOf,
+			&i.PosterUrl,
+			&i.Seasons,
+			&i.SeasonStatuses,
+			&i.Is4k,
+			&i.QualityProfileID,
+			&i.RootFolderPath,
+			&i.Tags,
+			&i.SeriesType,
+			&i.Episodes,
+			&i.FollowShow,
+			&i.ParentRequestID,
+			&i.Priority,
+			&i.EpisodesMonitoredAt,
+			&i.DenyReason,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getRequestsByStatus = `-- name: GetRequestsByStatus :many
-SELECT id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses
+SELECT id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses, is_4k, quality_profile_id, root_folder_path, tags, series_type, episodes, follow_show, parent_request_id, priority, episodes_monitored_at, deny_reason
 FROM requests
 WHERE status = ?
 ORDER BY created_at DESC
@@ -484,6 +819,17 @@ func (q *Queries) GetRequestsByStatus(ctx context.Context, status string) ([]Req
 			&i.PosterUrl,
 			&i.Seasons,
 			&i.SeasonStatuses,
+			&i.Is4k,
+			&i.QualityProfileID,
+			&i.RootFolderPath,
+			&i.Tags,
+			&i.SeriesType,
+			&i.Episodes,
+			&i.FollowShow,
+			&i.ParentRequestID,
+			&i.Priority,
+			&i.EpisodesMonitoredAt,
+			&i.DenyReason,
 		); err != nil {
 			return nil, err
 		}
@@ -499,7 +845,7 @@ func (q *Queries) GetRequestsByStatus(ctx context.Context, status string) ([]Req
 }
 
 const getRequestsByTMDBIDAndMediaType = `-- name: GetRequestsByTMDBIDAndMediaType :many
-SELECT id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses
+SELECT id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses, is_4k, quality_profile_id, root_folder_path, tags, series_type, episodes, follow_show, parent_request_id, priority, episodes_monitored_at, deny_reason
 FROM requests
 WHERE tmdb_id = ? AND media_type = ?
 `
@@ -534,6 +880,17 @@ func (q *Queries) GetRequestsByTMDBIDAndMediaType(ctx context.Context, arg GetRe
 			&i.PosterUrl,
 			&i.Seasons,
 			&i.SeasonStatuses,
+			&i.Is4k,
+			&i.QualityProfileID,
+			&i.RootFolderPath,
+			&i.Tags,
+			&i.SeriesType,
+			&i.Episodes,
+			&i.FollowShow,
+			&i.ParentRequestID,
+			&i.Priority,
+			&i.EpisodesMonitoredAt,
+			&i.DenyReason,
 		); err != nil {
 			return nil, err
 		}
@@ -549,7 +906,7 @@ func (q *Queries) GetRequestsByTMDBIDAndMediaType(ctx context.Context, arg GetRe
 }
 
 const getRequestsByUser = `-- name: GetRequestsByUser :many
-SELECT id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses
+SELECT id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses, is_4k, quality_profile_id, root_folder_path, tags, series_type, episodes, follow_show, parent_request_id, priority, episodes_monitored_at, deny_reason
 FROM requests
 WHERE user_id = ?
 ORDER BY created_at DESC
@@ -580,6 +937,17 @@ func (q *Queries) GetRequestsByUser(ctx context.Context, userID string) ([]Reque
 			&i.PosterUrl,
 			&i.Seasons,
 			&i.SeasonStatuses,
+			&i.Is4k,
+			&i.QualityProfileID,
+			&i.RootFolderPath,
+			&i.Tags,
+			&i.SeriesType,
+			&i.Episodes,
+			&i.FollowShow,
+			&i.ParentRequestID,
+			&i.Priority,
+			&i.EpisodesMonitoredAt,
+			&i.DenyReason,
 		); err != nil {
 			return nil, err
 		}
@@ -595,7 +963,7 @@ func (q *Queries) GetRequestsByUser(ctx context.Context, userID string) ([]Reque
 }
 
 const getRequestsForUser = `-- name: GetRequestsForUser :many
-SELECT id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses
+SELECT id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses, is_4k, quality_profile_id, root_folder_path, tags, series_type, episodes, follow_show, parent_request_id, priority, episodes_monitored_at, deny_reason
 FROM requests
 WHERE user_id = ? OR on_behalf_of = ?
 ORDER BY created_at DESC
@@ -631,6 +999,17 @@ func (q *Queries) GetRequestsForUser(ctx context.Context, arg GetRequestsForUser
 			&i.PosterUrl,
 			&i.Seasons,
 			&i.SeasonStatuses,
+			&i.Is4k,
+			&i.QualityProfileID,
+			&i.RootFolderPath,
+			&i.Tags,
+			&i.SeriesType,
+			&i.Episodes,
+			&i.FollowShow,
+			&i.ParentRequestID,
+			&i.Priority,
+			&i.EpisodesMonitoredAt,
+			&i.DenyReason,
 		); err != nil {
 			return nil, err
 		}
@@ -645,11 +1024,236 @@ func (q *Queries) GetRequestsForUser(ctx context.Context, arg GetRequestsForUser
 	return items, nil
 }
 
+const getUserRequestedTitles = `-- name: GetUserRequestedTitles :many
+SELECT DISTINCT tmdb_id, media_type, is_4k
+FROM requests
+WHERE user_id = ? AND tmdb_id IN (/*SLICE:tmdb_ids*/?)
+`
+
+type GetUserRequestedTitlesParams struct {
+	UserID  string  `json:"user_id"`
+	TmdbIds []int64 `json:"tmdb_ids"`
+}
+
+type GetUserRequestedTitlesRow struct {
+	TmdbID    sql.NullInt64 `json:"tmdb_id"`
+	MediaType string        `json:"media_type"`
+	Is4k      bool          `json:"is_4k"`
+}
+
+func (q *Queries) GetUserRequestedTitles(ctx context.Context, arg GetUserRequestedTitlesParams) ([]GetUserRequestedTitlesRow, error) {
+	query := getUserRequestedTitles
+	var queryParams []interface{}
+	queryParams = append(queryParams, arg.UserID)
+	if len(arg.TmdbIds) > 0 {
+		for _, v := range arg.TmdbIds {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:tmdb_ids*/?", strings.Repeat(",?", len(arg.TmdbIds))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:tmdb_ids*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUserRequestedTitlesRow
+	for rows.Next() {
+		var i GetUserRequestedTitlesRow
+		if err := rows.Scan(&i.TmdbID, &i.MediaType, &i.Is4k); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markRequestEpisodesMonitored = `-- name: MarkRequestEpisodesMonitored :exec
+UPDATE requests
+SET episodes_monitored_at = CURRENT_TIMESTAMP
+WHERE id = ?
+`
+
+func (q *Queries) MarkRequestEpisodesMonitored(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, markRequestEpisodesMonitored, id)
+	return err
+}
+
+const updateRequestDenyReason = `-- name: UpdateRequestDenyReason :one
+UPDATE requests
+SET deny_reason = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?
+RETURNING id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses, is_4k, quality_profile_id, root_folder_path, tags, series_type, episodes, follow_show, parent_request_id, priority, episodes_monitored_at, deny_reason
+`
+
+type UpdateRequestDenyReasonParams struct {
+	DenyReason sql.NullString `json:"deny_reason"`
+	ID         int64          `json:"id"`
+}
+
+func (q *Queries) UpdateRequestDenyReason(ctx context.Context, arg UpdateRequestDenyReasonParams) (Request, error) {
+	row := q.db.QueryRowContext(ctx, updateRequestDenyReason, arg.DenyReason, arg.ID)
+	var i Request
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.MediaType,
+		&i.TmdbID,
+		&i.Title,
+		&i.Status,
+		&i.Notes,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.FulfilledAt,
+		&i.ApproverID,
+		&i.OnBehalfOf,
+		&i.PosterUrl,
+		&i.Seasons,
+		&i.SeasonStatuses,
+		&i.Is4k,
+		&i.QualityProfileID,
+		&i.RootFolderPath,
+		&i.Tags,
+		&i.SeriesType,
+		&i.Episodes,
+		&i.FollowShow,
+		&i.ParentRequestID,
+		&i.Priority,
+		&i.EpisodesMonitoredAt,
+		&i.DenyReason,
+	)
+	return i, err
+}
+
+const updateRequestOverrides = `-- name: UpdateRequestOverrides :one
+UPDATE requests
+SET quality_profile_id = ?, root_folder_path = ?, tags = ?, series_type = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?
+RETURNING id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses, is_4k, quality_profile_id, root_folder_path, tags, series_type, episodes, follow_show, parent_request_id, priority, episodes_monitored_at, deny_reason
+`
+
+type UpdateRequestOverridesParams struct {
+	QualityProfileID sql.NullInt64  `json:"quality_profile_id"`
+	RootFolderPath   sql.NullString `json:"root_folder_path"`
+	Tags             sql.NullString `json:"tags"`
+	SeriesType       sql.NullString `json:"series_type"`
+	ID               int64          `json:"id"`
+}
+
+func (q *Queries) UpdateRequestOverrides(ctx context.Context, arg UpdateRequestOverridesParams) (Request, error) {
+	row := q.db.QueryRowContext(ctx, updateRequestOverrides,
+		arg.QualityProfileID,
+		arg.RootFolderPath,
+		arg.Tags,
+		arg.SeriesType,
+		arg.ID,
+	)
+	var i Request
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.MediaType,
+		&i.TmdbID,
+		&i.Title,
+		&i.Status,
+		&i.Notes,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.FulfilledAt,
+		&i.ApproverID,
+		&i.OnBehalfOf,
+		&i.PosterUrl,
+		&i.Seasons,
+		&i.SeasonStatuses,
+		&i.Is4k,
+		&i.QualityProfileID,
+		&i.RootFolderPath,
+		&i.Tags,
+		&i.SeriesType,
+		&i.Episodes,
+		&i.FollowShow,
+		&i.ParentRequestID,
+		&i.Priority,
+		&i.EpisodesMonitoredAt,
+		&i.DenyReason,
+	)
+	return i, err
+}
+
+const updateRequestPriority = `-- name: UpdateRequestPriority :one
+UPDATE requests
+SET priority = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?
+RETURNING id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses, is_4k, quality_profile_id, root_folder_path, tags, series_type, episodes, follow_show, parent_request_id, priority, episodes_monitored_at, deny_reason
+`
+
+type UpdateRequestPriorityParams struct {
+	Priority string `json:"priority"`
+	ID       int64  `json:"id"`
+}
+
+func (q *Queries) UpdateRequestPriority(ctx context.Context, arg UpdateRequestPriorityParams) (Request, error) {
+	row := q.db.QueryRowContext(ctx, updateRequestPriority, arg.Priority, arg.ID)
+	var i Request
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.MediaType,
+		&i.TmdbID,
+		&i.Title,
+		&i.Status,
+		&i.Notes,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.FulfilledAt,
+		&i.ApproverID,
+		&i.OnBehalfOf,
+		&i.PosterUrl,
+		&i.Seasons,
+		&i.SeasonStatuses,
+		&i.Is4k,
+		&i.QualityProfileID,
+		&i.RootFolderPath,
+		&i.Tags,
+		&i.SeriesType,
+		&i.Episodes,
+		&i.FollowShow,
+		&i.ParentRequestID,
+		&i.Priority,
+		&i.EpisodesMonitoredAt,
+		&i.DenyReason,
+	)
+	return i, err
+}
+
+const updateRequestSeasonStatuses = `-- name: UpdateRequestSeasonStatuses :exec
+UPDATE requests
+SET season_statuses = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?
+`
+
+type UpdateRequestSeasonStatusesParams struct {
+	SeasonStatuses sql.NullString `json:"season_statuses"`
+	ID             int64          `json:"id"`
+}
+
+func (q *Queries) UpdateRequestSeasonStatuses(ctx context.Context, arg UpdateRequestSeasonStatusesParams) error {
+	_, err := q.db.ExecContext(ctx, updateRequestSeasonStatuses, arg.SeasonStatuses, arg.ID)
+	return err
+}
+
 const updateRequestStatus = `-- name: UpdateRequestStatus :one
 UPDATE requests
 SET status = ?, approver_id = ?, updated_at = CURRENT_TIMESTAMP
 WHERE id = ?
-RETURNING id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses
+RETURNING id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses, is_4k, quality_profile_id, root_folder_path, tags, series_type, episodes, follow_show, parent_request_id, priority, episodes_monitored_at, deny_reason
 `
 
 type UpdateRequestStatusParams struct {
@@ -677,6 +1281,17 @@ func (q *Queries) UpdateRequestStatus(ctx context.Context, arg UpdateRequestStat
 		&i.PosterUrl,
 		&i.Seasons,
 		&i.SeasonStatuses,
+		&i.Is4k,
+		&i.QualityProfileID,
+		&i.RootFolderPath,
+		&i.Tags,
+		&i.SeriesType,
+		&i.Episodes,
+		&i.FollowShow,
+		&i.ParentRequestID,
+		&i.Priority,
+		&i.EpisodesMonitoredAt,
+		&i.DenyReason,
 	)
 	return i, err
 }
@@ -685,7 +1300,7 @@ const updateRequestStatusOnly = `-- name: UpdateRequestStatusOnly :one
 UPDATE requests
 SET status = ?, updated_at = CURRENT_TIMESTAMP
 WHERE id = ?
-RETURNING id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses
+RETURNING id, user_id, media_type, tmdb_id, title, status, notes, created_at, updated_at, fulfilled_at, approver_id, on_behalf_of, poster_url, seasons, season_statuses, is_4k, quality_profile_id, root_folder_path, tags, series_type, episodes, follow_show, parent_request_id, priority, episodes_monitored_at, deny_reason
 `
 
 type UpdateRequestStatusOnlyParams struct {
@@ -712,6 +1327,17 @@ func (q *Queries) UpdateRequestStatusOnly(ctx context.Context, arg UpdateRequest
 		&i.PosterUrl,
 		&i.Seasons,
 		&i.SeasonStatuses,
+		&i.Is4k,
+		&i.QualityProfileID,
+		&i.RootFolderPath,
+		&i.Tags,
+		&i.SeriesType,
+		&i.Episodes,
+		&i.FollowShow,
+		&i.ParentRequestID,
+		&i.Priority,
+		&i.EpisodesMonitoredAt,
+		&i.DenyReason,
 	)
 	return i, err
 }
@@ -55,7 +55,14 @@ CREATE TABLE downloads (
     last_updated TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     download_speed INTEGER, -- bytes per second
     upload_speed INTEGER,   -- bytes per second
-    download_size INTEGER   -- total download size in bytes
+    download_size INTEGER,  -- total download size in bytes
+    season_number INTEGER,  -- for Sonarr, the season being downloaded
+    request_id INTEGER REFERENCES requests(id) ON DELETE SET NULL, -- the request the download fulfils
+    episodes TEXT,          -- for Sonarr, JSON object of season -> episode numbers
+    match_confidence TEXT,  -- "high", "medium" or "low"
+    client TEXT,            -- the download client the *arr sent the release to
+    size_bytes INTEGER,     -- total download size in bytes
+    started_at TIMESTAMP    -- when the download was first seen
 );
 
 CREATE TABLE IF NOT EXISTS service_status (
@@ -163,9 +170,34 @@ CREATE TABLE IF NOT EXISTS requests (
     -- For TV shows - JSON array of season numbers being requested
     season_statuses TEXT DEFAULT NULL,
     -- JSON object tracking individual season statuses
-    UNIQUE (media_type, tmdb_id, user_id, seasons) -- Allow different season combinations per user
+    is_4k BOOLEAN NOT NULL DEFAULT FALSE,
+    -- Whether the request is for the 4K version of the title
+    quality_profile_id INTEGER DEFAULT NULL,
+    -- Optional: Quality profile override, instance default when NULL
+    root_folder_path TEXT DEFAULT NULL,
+    -- Optional: Root folder override, instance default when NULL
+    tags TEXT DEFAULT NULL,
+    -- Optional: JSON array of Radarr/Sonarr tag IDs
+    series_type TEXT DEFAULT NULL CHECK (series_type IN ('standard', 'anime', 'daily')),
+    -- Optional: Sonarr series type override
+    episodes TEXT DEFAULT NULL,
+    -- For TV shows - JSON object of season number to requested episode numbers
+    follow_show BOOLEAN NOT NULL DEFAULT FALSE,
+    -- Monitor future seasons and open child requests when new seasons air
+    parent_request_id INTEGER DEFAULT NULL REFERENCES requests(id) ON DELETE SET NULL,
+    -- The followed request that opened this one
+    priority TEXT NOT NULL DEFAULT 'normal' CHECK (priority IN ('low', 'normal', 'high', 'urgent')),
+    -- How urgently approvers should handle the request
+    episodes_monitored_at DATETIME DEFAULT NULL,
+    -- When the requested episodes were monitored in Sonarr
+    deny_reason TEXT DEFAULT NULL,
+    -- Why an approver denied the request, kept apart from the requester's notes
+    UNIQUE (media_type, tmdb_id, user_id, seasons, episodes, is_4k) -- Allow different season/episode combinations and a 4K copy per user
 );
 
+CREATE INDEX idx_requests_tmdb_id_media_type ON requests(tmdb_id, media_type, is_4k);
+CREATE INDEX idx_requests_parent_request_id ON requests(parent_request_id);
+
 -- Table for tracking availability of TV show seasons in media server
 CREATE TABLE season_availability (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -472,4 +504,170 @@ BEGIN
     SET updated_at = CURRENT_TIMESTAMP 
     WHERE id = NEW.id;
 END;
-CREATE INDEX idx_notifications_expires_at ON notifications(expires_at);
\ No newline at end of file
+CREATE INDEX idx_notifications_expires_at ON notifications(expires_at);
+-- Background job execution history
+CREATE TABLE job_runs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    job_name TEXT NOT NULL,
+    trigger_type TEXT NOT NULL DEFAULT 'scheduled' CHECK (trigger_type IN ('scheduled', 'startup', 'manual')),
+    started_at DATETIME NOT NULL,
+    finished_at DATETIME,
+    duration_ms INTEGER NOT NULL DEFAULT 0,
+    outcome TEXT NOT NULL CHECK (outcome IN ('success', 'failed', 'cancelled')),
+    attempts INTEGER NOT NULL DEFAULT 1,
+    error TEXT,
+    summary TEXT, -- JSON job-specific summary (items synced, requests processed, ...)
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Indexes for job_runs table
+CREATE INDEX idx_job_runs_job_name_started_at ON job_runs(job_name, started_at DESC);
+CREATE INDEX idx_job_runs_started_at ON job_runs(started_at);
+
+-- Rules that pick the Radarr/Sonarr instance and root folder for a request.
+-- Conditions are JSON arrays; NULL means the condition is not checked. Rules
+-- are evaluated by ascending priority and the first match wins. A default rule
+-- is used when no other rule matches.
+CREATE TABLE routing_rules (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    media_type TEXT NOT NULL CHECK (media_type IN ('movie', 'tv')),
+    priority INTEGER NOT NULL DEFAULT 0,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    genres TEXT,             -- JSON array of TMDB genre IDs
+    original_languages TEXT, -- JSON array of ISO 639-1 codes
+    keywords TEXT,           -- JSON array of TMDB keyword IDs
+    certifications TEXT,     -- JSON array of US certifications
+    user_ids TEXT,           -- JSON array of requesting user IDs
+    user_types TEXT,         -- JSON array of user types (media_server, local)
+    arr_service_id TEXT NOT NULL REFERENCES arr_services(id) ON DELETE CASCADE,
+    root_folder_path TEXT,
+    quality_profile_id INTEGER,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_routing_rules_media_type_priority ON routing_rules(media_type, priority);
+
+-- The instance each request was sent to, so status checks and retries use the
+-- same instance even if the rules change afterwards
+CREATE TABLE request_routing (
+    request_id INTEGER PRIMARY KEY REFERENCES requests(id) ON DELETE CASCADE,
+    arr_service_id TEXT REFERENCES arr_services(id) ON DELETE SET NULL,
+    routing_rule_id INTEGER REFERENCES routing_rules(id) ON DELETE SET NULL,
+    root_folder_path TEXT,
+    routed_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+-- A request for every part of a TMDB movie collection. Each part is a normal
+-- movie request linked to the group through collection_request_items.
+CREATE TABLE collection_requests (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    collection_id INTEGER NOT NULL,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    poster_url TEXT,
+    is_4k BOOLEAN NOT NULL DEFAULT FALSE,
+    watch_unreleased BOOLEAN NOT NULL DEFAULT FALSE, -- request unreleased parts once they are released
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (collection_id, user_id, is_4k)
+);
+
+CREATE TABLE collection_request_items (
+    request_id INTEGER PRIMARY KEY REFERENCES requests(id) ON DELETE CASCADE,
+    collection_request_id INTEGER NOT NULL REFERENCES collection_requests(id) ON DELETE CASCADE,
+    tmdb_id INTEGER NOT NULL
+);
+
+CREATE INDEX idx_collection_request_items_collection_request_id ON collection_request_items(collection_request_id);
+
+-- Titles a user is interested in but hasn't requested yet
+CREATE TABLE watchlist_items (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    media_type TEXT NOT NULL CHECK (media_type IN ('movie', 'tv')),
+    tmdb_id INTEGER NOT NULL,
+    title TEXT NOT NULL,
+    poster_url TEXT,
+    auto_request BOOLEAN NOT NULL DEFAULT FALSE, -- movies only: request once a digital release date is reached
+    request_id INTEGER REFERENCES requests(id) ON DELETE SET NULL, -- the request made automatically
+    available_notified_at DATETIME, -- when the user was told the title is in the library
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, media_type, tmdb_id)
+);
+
+CREATE INDEX idx_watchlist_items_tmdb_id ON watchlist_items(tmdb_id, media_type);
+
+-- Default priority of a user's new requests, their request tier. Users without
+-- a row get 'normal'.
+CREATE TABLE user_request_priorities (
+    user_id TEXT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    priority TEXT NOT NULL CHECK (priority IN ('low', 'normal', 'high', 'urgent')),
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Approval analytics and SLA escalation look metrics up by the kind of change
+CREATE INDEX idx_request_metrics_status_change ON request_metrics(status_change, request_id);
+
+-- Downloads that left the queue, kept after the downloads row is removed
+CREATE TABLE IF NOT EXISTS download_history (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    download_id TEXT NOT NULL,
+    request_id INTEGER REFERENCES requests(id) ON DELETE SET NULL,
+    title TEXT NOT NULL,
+    release_name TEXT NOT NULL,
+    source TEXT NOT NULL,
+    client TEXT,
+    tmdb_id INTEGER,
+    episodes TEXT,
+    size_bytes INTEGER,
+    average_speed INTEGER, -- bytes per second
+    started_at TIMESTAMP NOT NULL,
+    finished_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    outcome TEXT NOT NULL CHECK (outcome IN ('imported', 'failed', 'removed'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_download_history_request_id ON download_history(request_id);
+CREATE INDEX IF NOT EXISTS idx_download_history_finished_at ON download_history(finished_at);
+
+-- Leases give one instance at a time a role, e.g. running scheduled jobs
+CREATE TABLE IF NOT EXISTS leases (
+    name TEXT PRIMARY KEY,
+    holder TEXT NOT NULL, -- node id of the instance holding the lease
+    expires_at TIMESTAMP NOT NULL
+);
+
+-- Single-use CSRF tokens, valid on any instance
+CREATE TABLE IF NOT EXISTS csrf_tokens (
+    token TEXT PRIMARY KEY,
+    expires_at TIMESTAMP NOT NULL
+);
+
+-- Requests counted by rate limiters, keyed by limiter and client
+CREATE TABLE IF NOT EXISTS rate_limit_hits (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    key TEXT NOT NULL,
+    hit_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_rate_limit_hits_key ON rate_limit_hits(key, hit_at);
+
+-- State one instance works out and every instance serves, e.g. the bandwidth
+-- limits the job leader applied, stored as JSON by key
+CREATE TABLE IF NOT EXISTS shared_state (
+    key TEXT PRIMARY KEY,
+    value TEXT NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+-- Requests brought over from another request system, so importing the same
+-- export again skips them instead of creating duplicates
+CREATE TABLE IF NOT EXISTS imported_requests (
+    source TEXT NOT NULL, -- e.g. 'overseerr'
+    external_id INTEGER NOT NULL, -- the request's id in the source system
+    request_id INTEGER REFERENCES requests(id) ON DELETE SET NULL, -- NULL once the imported request is deleted
+    imported_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (source, external_id)
+);
@@ -0,0 +1,85 @@
+// Package eventbus carries events between the instances of a Serra
+// deployment. A single instance uses the in-memory bus; replicas behind a load
+// balancer share events through Redis pub/sub.
+package eventbus
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+)
+
+// Handler is called for every event published on a topic, including the
+// events this instance published itself
+type Handler func(event Event)
+
+// Event is a message published on the bus
+type Event struct {
+	Topic   string `json:"topic"`
+	Node    string `json:"node"` // The instance that published the event
+	Payload []byte `json:"payload"`
+}
+
+// Bus publishes events to every instance subscribed to a topic
+type Bus interface {
+	// NodeID identifies this instance
+	NodeID() string
+	// Publish sends an event to the subscribers of a topic on every instance
+	Publish(ctx context.Context, topic string, payload []byte) error
+	// Subscribe registers a handler for a topic. The returned function removes it again.
+	Subscribe(topic string, handler Handler) func()
+	// Close disconnects from the bus
+	Close() error
+}
+
+const (
+	DriverMemory = "memory"
+	DriverRedis  = "redis"
+)
+
+// Options configure the bus
+type Options struct {
+	Driver string // memory or redis, memory when empty
+	NodeID string // Defaults to the hostname
+
+	RedisAddress  string // host:port
+	RedisPassword string
+	RedisChannel  string // Channel all events are published on, "serra" when empty
+}
+
+// New connects to the bus configured in the options
+func New(ctx context.Context, opts Options) (Bus, error) {
+	nodeID := opts.NodeID
+	if nodeID == "" {
+		nodeID = defaultNodeID()
+	}
+
+	switch opts.Driver {
+	case "", DriverMemory:
+		return NewMemory(nodeID), nil
+	case DriverRedis:
+		if opts.RedisAddress == "" {
+			return nil, fmt.Errorf("redis event bus requires an address")
+		}
+		channel := opts.RedisChannel
+		if channel == "" {
+			channel = "serra"
+		}
+		return NewRedis(ctx, nodeID, opts.RedisAddress, opts.RedisPassword, channel)
+	default:
+		return nil, fmt.Errorf("unknown event bus driver %q", opts.Driver)
+	}
+}
+
+// defaultNodeID returns the hostname, or a random id when it isn't available
+func defaultNodeID() string {
+	if hostname, err := os.Hostname(); err == nil && hostname != "" {
+		return hostname
+	}
+
+	bytes := make([]byte, 6)
+	_, _ = rand.Read(bytes)
+	return "serra-" + hex.EncodeToString(bytes)
+}
@@ -0,0 +1,82 @@
+package eventbus
+
+import (
+	"context"
+	"sync"
+)
+
+// subscriptions holds the handlers registered by topic
+type subscriptions struct {
+	mu       sync.RWMutex
+	handlers map[string]map[int]Handler
+	nextID   int
+}
+
+func newSubscriptions() *subscriptions {
+	return &subscriptions{handlers: make(map[string]map[int]Handler)}
+}
+
+// add registers a handler and returns the function removing it
+func (s *subscriptions) add(topic string, handler Handler) func() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := s.nextID
+	s.nextID++
+	if s.handlers[topic] == nil {
+		s.handlers[topic] = make(map[int]Handler)
+	}
+	s.handlers[topic][id] = handler
+
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.handlers[topic], id)
+	}
+}
+
+// dispatch calls the handlers of an event's topic
+func (s *subscriptions) dispatch(event Event) {
+	s.mu.RLock()
+	handlers := make([]Handler, 0, len(s.handlers[event.Topic]))
+	for _, handler := range s.handlers[event.Topic] {
+		handlers = append(handlers, handler)
+	}
+	s.mu.RUnlock()
+
+	for _, handler := range handlers {
+		handler(event)
+	}
+}
+
+// Memory is the bus of a single instance, delivering events in process
+type Memory struct {
+	nodeID        string
+	subscriptions *subscriptions
+}
+
+// NewMemory creates an in-memory bus
+func NewMemory(nodeID string) *Memory {
+	return &Memory{
+		nodeID:        nodeID,
+		subscriptions: newSubscriptions(),
+	}
+}
+
+func (m *Memory) NodeID() string {
+	return m.nodeID
+}
+
+// Publish calls the handlers of the topic before returning
+func (m *Memory) Publish(ctx context.Context, topic string, payload []byte) error {
+	m.subscriptions.dispatch(Event{Topic: topic, Node: m.nodeID, Payload: payload})
+	return nil
+}
+
+func (m *Memory) Subscribe(topic string, handler Handler) func() {
+	return m.subscriptions.add(topic, handler)
+}
+
+func (m *Memory) Close() error {
+	return nil
+}
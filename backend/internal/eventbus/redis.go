@@ -0,0 +1,337 @@
+package eventbus
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Redis shares events between instances through Redis pub/sub. All topics are
+// multiplexed on one channel. Pub/sub keeps no history, so events published
+// while an instance is reconnecting are lost to it.
+type Redis struct {
+	nodeID   string
+	address  string
+	password string
+	channel  string
+
+	subscriptions *subscriptions
+
+	// Connection used to publish, dialed again after it fails
+	pubMutex sync.Mutex
+	pub      *respConn
+
+	// Connection receiving the channel's messages
+	subMutex sync.Mutex
+	sub      *respConn
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewRedis connects to Redis and subscribes to the channel
+func NewRedis(ctx context.Context, nodeID, address, password, channel string) (*Redis, error) {
+	busCtx, cancel := context.WithCancel(context.Background())
+	r := &Redis{
+		nodeID:        nodeID,
+		address:       address,
+		password:      password,
+		channel:       channel,
+		subscriptions: newSubscriptions(),
+		ctx:           busCtx,
+		cancel:        cancel,
+		done:          make(chan struct{}),
+	}
+
+	// Fail at startup when Redis can't be reached rather than on the first event
+	sub, err := r.subscribe(ctx)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	go r.receive(sub)
+	return r, nil
+}
+
+func (r *Redis) NodeID() string {
+	return r.nodeID
+}
+
+// Publish sends an event to every instance, retrying once on a new connection
+func (r *Redis) Publish(ctx context.Context, topic string, payload []byte) error {
+	data, err := json.Marshal(Event{Topic: topic, Node: r.nodeID, Payload: payload})
+	if err != nil {
+		return err
+	}
+
+	r.pubMutex.Lock()
+	defer r.pubMutex.Unlock()
+
+	for attempt := 0; attempt < 2; attempt++ {
+		if r.pub == nil {
+			r.pub, err = dialRESP(ctx, r.address, r.password)
+			if err != nil {
+				return fmt.Errorf("failed to connect to redis: %w", err)
+			}
+		}
+
+		_, err = r.pub.do(ctx, "PUBLISH", r.channel, string(data))
+		if err == nil {
+			return nil
+		}
+
+		var replyErr respError
+		if errors.As(err, &replyErr) {
+			return err
+		}
+		r.pub.close()
+		r.pub = nil
+	}
+
+	return fmt.Errorf("failed to publish to redis: %w", err)
+}
+
+func (r *Redis) Subscribe(topic string, handler Handler) func() {
+	return r.subscriptions.add(topic, handler)
+}
+
+// Close disconnects both connections and stops receiving
+func (r *Redis) Close() error {
+	r.cancel()
+
+	r.subMutex.Lock()
+	if r.sub != nil {
+		r.sub.close()
+	}
+	r.subMutex.Unlock()
+	<-r.done
+
+	r.pubMutex.Lock()
+	defer r.pubMutex.Unlock()
+	if r.pub != nil {
+		r.pub.close()
+		r.pub = nil
+	}
+	return nil
+}
+
+// subscribe dials the receiving connection and subscribes to the channel
+func (r *Redis) subscribe(ctx context.Context) (*respConn, error) {
+	conn, err := dialRESP(ctx, r.address, r.password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to redis: %w", err)
+	}
+
+	// The confirmation is the first reply on the connection
+	if _, err := conn.do(ctx, "SUBSCRIBE", r.channel); err != nil {
+		conn.close()
+		return nil, fmt.Errorf("failed to subscribe to redis channel %s: %w", r.channel, err)
+	}
+
+	r.subMutex.Lock()
+	defer r.subMutex.Unlock()
+	if r.ctx.Err() != nil {
+		// Closed while connecting
+		conn.close()
+		return nil, r.ctx.Err()
+	}
+	r.sub = conn
+	return conn, nil
+}
+
+// receive dispatches the channel's messages, reconnecting with backoff until closed
+func (r *Redis) receive(conn *respConn) {
+	defer close(r.done)
+
+	backoff := time.Second
+	for {
+		if conn != nil {
+			err := r.read(conn)
+			conn.close()
+			if r.ctx.Err() != nil {
+				return
+			}
+			slog.Warn("Lost connection to redis event bus", "address", r.address, "error", err)
+			backoff = time.Second
+		}
+
+		select {
+		case <-r.ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		var err error
+		conn, err = r.subscribe(r.ctx)
+		if err != nil {
+			slog.Warn("Failed to reconnect to redis event bus", "address", r.address, "error", err, "retry_in", backoff)
+			backoff = min(backoff*2, 30*time.Second)
+			continue
+		}
+		slog.Info("Reconnected to redis event bus", "address", r.address)
+	}
+}
+
+// read dispatches messages until the connection fails
+func (r *Redis) read(conn *respConn) error {
+	for {
+		reply, err := conn.read()
+		if err != nil {
+			return err
+		}
+
+		// Messages arrive as ["message", channel, payload]
+		parts, ok := reply.([]interface{})
+		if !ok || len(parts) != 3 || parts[0] != "message" {
+			continue
+		}
+		data, ok := parts[2].(string)
+		if !ok {
+			continue
+		}
+
+		var event Event
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			slog.Warn("Invalid event on redis event bus", "error", err)
+			continue
+		}
+		r.subscriptions.dispatch(event)
+	}
+}
+
+// respError is an error reply from Redis
+type respError string
+
+func (e respError) Error() string {
+	return string(e)
+}
+
+// respConn is a connection speaking the Redis serialization protocol
+type respConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// dialRESP connects to Redis and authenticates when a password is set
+func dialRESP(ctx context.Context, address, password string) (*respConn, error) {
+	dialer := net.Dialer{Timeout: 5 * time.Second}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &respConn{conn: conn, reader: bufio.NewReader(conn)}
+	if password != "" {
+		if _, err := c.do(ctx, "AUTH", password); err != nil {
+			c.close()
+			return nil, fmt.Errorf("redis authentication failed: %w", err)
+		}
+	}
+	return c, nil
+}
+
+// do sends a command and reads its reply. Error replies are returned as respError.
+func (c *respConn) do(ctx context.Context, args ...string) (interface{}, error) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(5 * time.Second)
+	}
+	_ = c.conn.SetDeadline(deadline)
+	defer c.conn.SetDeadline(time.Time{})
+
+	if err := c.write(args...); err != nil {
+		return nil, err
+	}
+	reply, err := c.read()
+	if err != nil {
+		return nil, err
+	}
+	if replyErr, ok := reply.(respError); ok {
+		return nil, replyErr
+	}
+	return reply, nil
+}
+
+// write sends a command as an array of bulk strings
+func (c *respConn) write(args ...string) error {
+	buf := make([]byte, 0, 64)
+	buf = append(buf, '*')
+	buf = strconv.AppendInt(buf, int64(len(args)), 10)
+	buf = append(buf, '\r', '\n')
+	for _, arg := range args {
+		buf = append(buf, '$')
+		buf = strconv.AppendInt(buf, int64(len(arg)), 10)
+		buf = append(buf, '\r', '\n')
+		buf = append(buf, arg...)
+		buf = append(buf, '\r', '\n')
+	}
+
+	_, err := c.conn.Write(buf)
+	return err
+}
+
+// read parses one reply. Simple and bulk strings are returned as string,
+// integers as int64, arrays as []interface{} and nil replies as nil.
+func (c *respConn) read() (interface{}, error) {
+	line, err := c.reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, fmt.Errorf("invalid redis reply %q", line)
+	}
+	kind, value := line[0], line[1:len(line)-2]
+
+	switch kind {
+	case '+':
+		return value, nil
+	case '-':
+		return respError(value), nil
+	case ':':
+		return strconv.ParseInt(value, 10, 64)
+	case '$':
+		size, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redis bulk length %q", value)
+		}
+		if size < 0 {
+			return nil, nil
+		}
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(c.reader, data); err != nil {
+			return nil, err
+		}
+		return string(data[:size]), nil
+	case '*':
+		count, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redis array length %q", value)
+		}
+		if count < 0 {
+			return nil, nil
+		}
+		items := make([]interface{}, count)
+		for i := range items {
+			if items[i], err = c.read(); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("unknown redis reply type %q", kind)
+	}
+}
+
+func (c *respConn) close() {
+	_ = c.conn.Close()
+}
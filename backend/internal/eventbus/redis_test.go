@@ -0,0 +1,287 @@
+package eventbus
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRedis is a stand-in for a Redis server speaking just enough RESP for
+// the bus: AUTH, SUBSCRIBE and PUBLISH
+type fakeRedis struct {
+	t        *testing.T
+	listener net.Listener
+	password string
+
+	mu          sync.Mutex
+	conns       map[net.Conn]bool
+	subscribers map[string]map[net.Conn]bool
+	subscribes  int
+}
+
+func newFakeRedis(t *testing.T, password string) *fakeRedis {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+
+	s := &fakeRedis{
+		t:           t,
+		listener:    listener,
+		password:    password,
+		conns:       make(map[net.Conn]bool),
+		subscribers: make(map[string]map[net.Conn]bool),
+	}
+	t.Cleanup(func() {
+		listener.Close()
+		s.dropConnections()
+	})
+
+	go s.accept()
+	return s
+}
+
+func (s *fakeRedis) address() string {
+	return s.listener.Addr().String()
+}
+
+func (s *fakeRedis) accept() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.conns[conn] = true
+		s.mu.Unlock()
+		go s.serve(conn)
+	}
+}
+
+func (s *fakeRedis) serve(conn net.Conn) {
+	defer s.forget(conn)
+
+	c := &respConn{conn: conn, reader: bufio.NewReader(conn)}
+	authenticated := s.password == ""
+	for {
+		reply, err := c.read()
+		if err != nil {
+			return
+		}
+		parts, _ := reply.([]interface{})
+		args := make([]string, len(parts))
+		for i, part := range parts {
+			args[i], _ = part.(string)
+		}
+		if len(args) == 0 {
+			s.write(conn, "-ERR empty command\r\n")
+			continue
+		}
+
+		switch command := strings.ToUpper(args[0]); {
+		case command == "AUTH":
+			if len(args) != 2 || args[1] != s.password {
+				s.write(conn, "-WRONGPASS invalid username-password pair\r\n")
+				continue
+			}
+			authenticated = true
+			s.write(conn, "+OK\r\n")
+		case !authenticated:
+			s.write(conn, "-NOAUTH Authentication required.\r\n")
+		case command == "SUBSCRIBE" && len(args) == 2:
+			s.mu.Lock()
+			if s.subscribers[args[1]] == nil {
+				s.subscribers[args[1]] = make(map[net.Conn]bool)
+			}
+			s.subscribers[args[1]][conn] = true
+			s.subscribes++
+			s.mu.Unlock()
+			s.write(conn, fmt.Sprintf("*3\r\n$9\r\nsubscribe\r\n%s:1\r\n", bulk(args[1])))
+		case command == "PUBLISH" && len(args) == 3:
+			s.mu.Lock()
+			receivers := make([]net.Conn, 0, len(s.subscribers[args[1]]))
+			for subscriber := range s.subscribers[args[1]] {
+				receivers = append(receivers, subscriber)
+			}
+			s.mu.Unlock()
+
+			message := fmt.Sprintf("*3\r\n$7\r\nmessage\r\n%s%s", bulk(args[1]), bulk(args[2]))
+			for _, receiver := range receivers {
+				s.write(receiver, message)
+			}
+			s.write(conn, fmt.Sprintf(":%d\r\n", len(receivers)))
+		default:
+			s.write(conn, "-ERR unknown command\r\n")
+		}
+	}
+}
+
+func (s *fakeRedis) write(conn net.Conn, data string) {
+	_, _ = conn.Write([]byte(data))
+}
+
+func (s *fakeRedis) forget(conn net.Conn) {
+	conn.Close()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.conns, conn)
+	for _, subscribers := range s.subscribers {
+		delete(subscribers, conn)
+	}
+}
+
+// dropConnections closes every client connection, as a Redis restart would
+func (s *fakeRedis) dropConnections() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for conn := range s.conns {
+		conn.Close()
+	}
+}
+
+func (s *fakeRedis) subscribeCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.subscribes
+}
+
+func bulk(value string) string {
+	return fmt.Sprintf("$%d\r\n%s\r\n", len(value), value)
+}
+
+// collect records the events delivered to a handler
+type collect struct {
+	events chan Event
+}
+
+func newCollect() *collect {
+	return &collect{events: make(chan Event, 16)}
+}
+
+func (c *collect) handle(event Event) {
+	c.events <- event
+}
+
+func (c *collect) next(t *testing.T) Event {
+	t.Helper()
+	select {
+	case event := <-c.events:
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for an event")
+		return Event{}
+	}
+}
+
+func (c *collect) none(t *testing.T) {
+	t.Helper()
+	select {
+	case event := <-c.events:
+		t.Fatalf("unexpected event %+v", event)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func newTestRedis(t *testing.T, server *fakeRedis, nodeID, password string) *Redis {
+	t.Helper()
+	bus, err := NewRedis(context.Background(), nodeID, server.address(), password, "serra")
+	if err != nil {
+		t.Fatalf("connect %s: %v", nodeID, err)
+	}
+	t.Cleanup(func() { bus.Close() })
+	return bus
+}
+
+func TestRedisDeliversAcrossInstances(t *testing.T) {
+	server := newFakeRedis(t, "secret")
+	a := newTestRedis(t, server, "node-a", "secret")
+	b := newTestRedis(t, server, "node-b", "secret")
+
+	downloads, requests := newCollect(), newCollect()
+	b.Subscribe("downloads", downloads.handle)
+	b.Subscribe("requests", requests.handle)
+	own := newCollect()
+	a.Subscribe("downloads", own.handle)
+
+	if err := a.Publish(context.Background(), "downloads", []byte(`{"id":1}`)); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+
+	event := downloads.next(t)
+	if event.Topic != "downloads" || event.Node != "node-a" || string(event.Payload) != `{"id":1}` {
+		t.Fatalf("unexpected event %+v", event)
+	}
+	if event := own.next(t); event.Node != "node-a" {
+		t.Fatalf("publisher should receive its own event, got %+v", event)
+	}
+	requests.none(t)
+}
+
+func TestRedisRejectsWrongPassword(t *testing.T) {
+	server := newFakeRedis(t, "secret")
+
+	_, err := NewRedis(context.Background(), "node-a", server.address(), "wrong", "serra")
+	if err == nil || !strings.Contains(err.Error(), "authentication failed") {
+		t.Fatalf("expected authentication error, got %v", err)
+	}
+
+	_, err = NewRedis(context.Background(), "node-a", server.address(), "", "serra")
+	if err == nil {
+		t.Fatal("expected an error without a password")
+	}
+}
+
+func TestRedisReconnectsAfterConnectionLoss(t *testing.T) {
+	server := newFakeRedis(t, "")
+	a := newTestRedis(t, server, "node-a", "")
+	b := newTestRedis(t, server, "node-b", "")
+
+	events := newCollect()
+	b.Subscribe("downloads", events.handle)
+
+	// Warm up the publishing connection so it's dropped too
+	if err := a.Publish(context.Background(), "downloads", []byte("1")); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	events.next(t)
+
+	server.dropConnections()
+
+	// Both subscribers resubscribe after the backoff
+	deadline := time.Now().Add(5 * time.Second)
+	for server.subscribeCount() < 4 {
+		if time.Now().After(deadline) {
+			t.Fatalf("instances didn't resubscribe, %d subscriptions", server.subscribeCount())
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	// The stale publishing connection is replaced on the retry
+	if err := a.Publish(context.Background(), "downloads", []byte("2")); err != nil {
+		t.Fatalf("publish after reconnect: %v", err)
+	}
+	if event := events.next(t); string(event.Payload) != "2" {
+		t.Fatalf("unexpected event %+v", event)
+	}
+}
+
+func TestMemoryUnsubscribe(t *testing.T) {
+	bus := NewMemory("node-a")
+
+	events := newCollect()
+	unsubscribe := bus.Subscribe("downloads", events.handle)
+	_ = bus.Publish(context.Background(), "downloads", []byte("1"))
+	if event := events.next(t); event.Node != "node-a" || string(event.Payload) != "1" {
+		t.Fatalf("unexpected event %+v", event)
+	}
+
+	unsubscribe()
+	_ = bus.Publish(context.Background(), "downloads", []byte("2"))
+	events.none(t)
+}
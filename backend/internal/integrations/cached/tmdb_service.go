@@ -42,6 +42,12 @@ func (c *TMDBService) getCachedOrFetch(endpoint string, params map[string]interf
 			result = &structures.TMDBWatchProvidersListResponse{}
 		case endpoint == "watch/providers/regions":
 			result = &structures.TMDBWatchProviderRegionsResponse{}
+		case strings.HasPrefix(endpoint, "find/"):
+			result = &structures.TMDBFindResponse{}
+		case strings.HasSuffix(endpoint, "/classification"):
+			result = &structures.TMDBMediaClassification{}
+		case strings.HasSuffix(endpoint, "/details"):
+			result = &structures.TVDetails{}
 		case strings.Contains(endpoint, "/release_dates"):
 			result = &structures.TMDBReleaseDatesResponse{}
 		case strings.Contains(endpoint, "collection/"):
@@ -459,3 +465,63 @@ func (c *TMDBService) DiscoverTV(params structures.DiscoverTVParams) (structures
 
 	return c.tmdb.DiscoverTV(params)
 }
+
+func (c *TMDBService) GetMediaClassification(mediaType, id string) (structures.TMDBMediaClassification, error) {
+	params := map[string]interface{}{"media_type": mediaType, "id": id}
+
+	result, err := c.getCachedOrFetch(fmt.Sprintf("%s/%s/classification", mediaType, id), params, func() (interface{}, error) {
+		return c.tmdb.GetMediaClassification(mediaType, id)
+	})
+	if err != nil {
+		return structures.TMDBMediaClassification{}, err
+	}
+
+	switch response := result.(type) {
+	case *structures.TMDBMediaClassification:
+		return *response, nil
+	case structures.TMDBMediaClassification:
+		return response, nil
+	}
+
+	return c.tmdb.GetMediaClassification(mediaType, id)
+}
+
+func (c *TMDBService) GetTVDetails(seriesID string) (structures.TVDetails, error) {
+	params := map[string]interface{}{"series_id": seriesID}
+
+	result, err := c.getCachedOrFetch(fmt.Sprintf("tv/%s/details", seriesID), params, func() (interface{}, error) {
+		return c.tmdb.GetTVDetails(seriesID)
+	})
+	if err != nil {
+		return structures.TVDetails{}, err
+	}
+
+	switch response := result.(type) {
+	case *structures.TVDetails:
+		return *response, nil
+	case structures.TVDetails:
+		return response, nil
+	}
+
+	return c.tmdb.GetTVDetails(seriesID)
+}
+
+func (c *TMDBService) FindByExternalID(externalID, source string) (structures.TMDBFindResponse, error) {
+	params := map[string]interface{}{"external_id": externalID, "source": source}
+
+	result, err := c.getCachedOrFetch(fmt.Sprintf("find/%s/%s", source, externalID), params, func() (interface{}, error) {
+		return c.tmdb.FindByExternalID(externalID, source)
+	})
+	if err != nil {
+		return structures.TMDBFindResponse{}, err
+	}
+
+	switch response := result.(type) {
+	case *structures.TMDBFindResponse:
+		return *response, nil
+	case structures.TMDBFindResponse:
+		return response, nil
+	}
+
+	return c.tmdb.FindByExternalID(externalID, source)
+}
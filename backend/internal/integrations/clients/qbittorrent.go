@@ -8,7 +8,10 @@ import (
 	"log/slog"
 	"net/http"
 	"net/url"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/mahcks/serra/pkg/downloadclient"
@@ -22,6 +25,11 @@ type QBitTorrentClient struct {
 	sid        string // Session ID for authentication
 	connected  bool
 	lastError  string
+
+	// Torrents synced incrementally through sync/maindata
+	syncMutex sync.Mutex
+	rid       int64
+	torrents  map[string]qbitTorrentInfo
 }
 
 // NewQBitTorrentClient creates a new qBittorrent client
@@ -97,6 +105,10 @@ func (c *QBitTorrentClient) Connect(ctx context.Context) error {
 		return fmt.Errorf("failed to get session ID from qBittorrent")
 	}
 
+	c.syncMutex.Lock()
+	c.resetSync()
+	c.syncMutex.Unlock()
+
 	c.connected = true
 	c.lastError = ""
 	slog.Debug("Connected to qBittorrent", "host", c.config.Host, "port", c.config.Port)
@@ -121,6 +133,10 @@ func (c *QBitTorrentClient) Disconnect(ctx context.Context) error {
 		}
 	}
 
+	c.syncMutex.Lock()
+	c.resetSync()
+	c.syncMutex.Unlock()
+
 	c.connected = false
 	c.sid = ""
 	return nil
@@ -132,14 +148,50 @@ func (c *QBitTorrentClient) GetDownloads(ctx context.Context) ([]downloadclient.
 		return nil, fmt.Errorf("not connected to qBittorrent")
 	}
 
+	torrents, err := c.syncTorrents(ctx)
+	if err != nil {
+		c.lastError = err.Error()
+		return nil, err
+	}
+
+	// Convert to DownloadItem format
+	var downloads []downloadclient.Item
+	for _, torrent := range torrents {
+		if !isDownloadingState(torrent.State) {
+			continue
+		}
+		download := downloadclient.Item{
+			ID:       torrent.Hash,
+			Name:     torrent.Name,
+			Hash:     torrent.Hash,
+			Progress: torrent.Progress * 100, // Convert from 0-1 to 0-100
+			Status:   c.mapQBitTorrentStatus(torrent.State),
+			State:    torrent.State,
+			TimeLeft: formatTimeLeft(torrent.ETA),
+			ETA:      int64(torrent.ETA),
+			AddedOn:  time.Unix(torrent.AddedOn, 0),
+			Seeds:    utils.PtrInt(torrent.NumSeeds),
+			Peers:    utils.PtrInt(torrent.NumLeechs),
+		}
+		downloads = append(downloads, download)
+	}
+
+	return downloads, nil
+}
+
+// syncTorrents brings the torrent list up to date through sync/maindata.
+// qBittorrent only sends what changed since the last response id (rid), and
+// starts over with a full update when it no longer knows the rid.
+func (c *QBitTorrentClient) syncTorrents(ctx context.Context) ([]qbitTorrentInfo, error) {
+	c.syncMutex.Lock()
+	defer c.syncMutex.Unlock()
+
 	scheme := utils.Ternary(c.config.UseSSL, "https", "http")
 	baseURL := fmt.Sprintf("%s://%s:%d", scheme, c.config.Host, c.config.Port)
 
-	// Get torrents info
-	torrentsURL := utils.BuildURL(baseURL, "/api/v2/torrents/info", map[string]string{"filter": "downloading"})
-	req, err := http.NewRequestWithContext(ctx, "GET", torrentsURL, nil)
+	syncURL := utils.BuildURL(baseURL, "/api/v2/sync/maindata", map[string]string{"rid": strconv.FormatInt(c.rid, 10)})
+	req, err := http.NewRequestWithContext(ctx, "GET", syncURL, nil)
 	if err != nil {
-		c.lastError = err.Error()
 		return nil, err
 	}
 
@@ -150,39 +202,67 @@ func (c *QBitTorrentClient) GetDownloads(ctx context.Context) ([]downloadclient.
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		c.lastError = err.Error()
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		c.lastError = fmt.Sprintf("HTTP %d", resp.StatusCode)
 		return nil, fmt.Errorf("failed to get torrents: %s", resp.Status)
 	}
 
-	var torrents []qbitTorrentInfo
-	if err := json.NewDecoder(resp.Body).Decode(&torrents); err != nil {
-		c.lastError = err.Error()
+	var data qbitMainData
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		c.resetSync()
 		return nil, err
 	}
 
-	// Convert to DownloadItem format
-	var downloads []downloadclient.Item
-	for _, torrent := range torrents {
-		download := downloadclient.Item{
-			ID:       torrent.Hash,
-			Name:     torrent.Name,
-			Hash:     torrent.Hash,
-			Progress: torrent.Progress * 100, // Convert from 0-1 to 0-100
-			Status:   c.mapQBitTorrentStatus(torrent.State),
-			TimeLeft: formatTimeLeft(torrent.ETA),
-			ETA:      int64(torrent.ETA),
-			AddedOn:  time.Unix(torrent.AddedOn, 0),
+	if data.FullUpdate || c.torrents == nil {
+		c.torrents = make(map[string]qbitTorrentInfo, len(data.Torrents))
+	}
+	// Changed torrents only carry the fields that changed, so they are decoded
+	// over what is already known
+	for hash, raw := range data.Torrents {
+		torrent := c.torrents[hash]
+		if err := json.Unmarshal(raw, &torrent); err != nil {
+			c.resetSync()
+			return nil, err
 		}
-		downloads = append(downloads, download)
+		torrent.Hash = hash
+		c.torrents[hash] = torrent
 	}
+	for _, hash := range data.TorrentsRemoved {
+		delete(c.torrents, hash)
+	}
+	c.rid = data.Rid
 
-	return downloads, nil
+	torrents := make([]qbitTorrentInfo, 0, len(c.torrents))
+	for _, torrent := range c.torrents {
+		torrents = append(torrents, torrent)
+	}
+	sort.Slice(torrents, func(i, j int) bool {
+		if torrents[i].AddedOn != torrents[j].AddedOn {
+			return torrents[i].AddedOn < torrents[j].AddedOn
+		}
+		return torrents[i].Hash < torrents[j].Hash
+	})
+
+	return torrents, nil
+}
+
+// resetSync forgets the synced torrents so the next sync is a full update
+func (c *QBitTorrentClient) resetSync() {
+	c.rid = 0
+	c.torrents = nil
+}
+
+// isDownloadingState reports whether a torrent state belongs to qBittorrent's
+// "downloading" filter
+func isDownloadingState(state string) bool {
+	switch state {
+	case "downloading", "metaDL", "forcedMetaDL", "stalledDL", "checkingDL", "pausedDL", "stoppedDL", "queuedDL", "forcedDL", "allocating":
+		return true
+	}
+	return false
 }
 
 // GetDownloadProgress retrieves progress for a specific download
@@ -258,16 +338,74 @@ func (c *QBitTorrentClient) GetConnectionInfo() downloadclient.ConnectionInfo {
 	}
 }
 
+// SetRateLimits sets the global download and upload limits
+func (c *QBitTorrentClient) SetRateLimits(ctx context.Context, limits downloadclient.RateLimits) error {
+	if !c.connected {
+		return fmt.Errorf("not connected to qBittorrent")
+	}
+
+	if err := c.setLimit(ctx, "/api/v2/transfer/setDownloadLimit", limits.Download); err != nil {
+		return err
+	}
+	return c.setLimit(ctx, "/api/v2/transfer/setUploadLimit", limits.Upload)
+}
+
+// setLimit posts a global rate limit in bytes per second, 0 removes the limit
+func (c *QBitTorrentClient) setLimit(ctx context.Context, path string, limit int64) error {
+	scheme := utils.Ternary(c.config.UseSSL, "https", "http")
+	baseURL := fmt.Sprintf("%s://%s:%d", scheme, c.config.Host, c.config.Port)
+
+	form := url.Values{}
+	form.Set("limit", strconv.FormatInt(limit, 10))
+
+	req, err := http.NewRequestWithContext(ctx, "POST", utils.BuildURL(baseURL, path, nil), strings.NewReader(form.Encode()))
+	if err != nil {
+		c.lastError = err.Error()
+		return err
+	}
+
+	if c.sid != "" {
+		req.AddCookie(&http.Cookie{Name: "SID", Value: c.sid})
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Referer", baseURL)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		c.lastError = err.Error()
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		c.lastError = fmt.Sprintf("HTTP %d", resp.StatusCode)
+		return fmt.Errorf("failed to set rate limit: %s", resp.Status)
+	}
+
+	return nil
+}
+
 // qbitTorrentInfo represents the qBittorrent API response structure
 type qbitTorrentInfo struct {
-	Hash     string  `json:"hash"`
-	Name     string  `json:"name"`
-	Progress float64 `json:"progress"`
-	State    string  `json:"state"`
-	ETA      int     `json:"eta"`
-	AddedOn  int64   `json:"added_on"`
-	Category string  `json:"category"`
-	Tags     string  `json:"tags"`
+	Hash      string  `json:"hash"`
+	Name      string  `json:"name"`
+	Progress  float64 `json:"progress"`
+	State     string  `json:"state"`
+	ETA       int     `json:"eta"`
+	AddedOn   int64   `json:"added_on"`
+	Category  string  `json:"category"`
+	Tags      string  `json:"tags"`
+	NumSeeds  int     `json:"num_seeds"`
+	NumLeechs int     `json:"num_leechs"`
+}
+
+// qbitMainData represents the qBittorrent sync/maindata response. Torrents are
+// keyed by hash and hold only the fields that changed unless it is a full update.
+type qbitMainData struct {
+	Rid             int64                      `json:"rid"`
+	FullUpdate      bool                       `json:"full_update"`
+	Torrents        map[string]json.RawMessage `json:"torrents"`
+	TorrentsRemoved []string                   `json:"torrents_removed"`
 }
 
 // formatTimeLeft formats ETA in seconds to human-readable format
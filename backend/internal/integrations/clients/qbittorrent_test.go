@@ -0,0 +1,235 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/mahcks/serra/pkg/downloadclient"
+)
+
+// fakeQBittorrent serves the torrent list of a qBittorrent instance where a
+// few torrents make progress between polls
+type fakeQBittorrent struct {
+	mu       sync.Mutex
+	torrents []qbitTorrentInfo
+	changing int // Torrents advancing per poll
+	next     int // First torrent to advance on the next poll
+	rid      int64
+	changed  map[string]bool // Since the last rid
+	removed  []string
+
+	bytes int64 // Response bytes sent
+}
+
+func newFakeQBittorrent(count, changing int) *fakeQBittorrent {
+	f := &fakeQBittorrent{changing: changing, changed: make(map[string]bool)}
+	for i := 0; i < count; i++ {
+		f.torrents = append(f.torrents, qbitTorrentInfo{
+			Hash:      fmt.Sprintf("%040x", i),
+			Name:      fmt.Sprintf("Some.Show.S01E%02d.1080p.WEB.h264-GROUP", i%100),
+			State:     "downloading",
+			ETA:       3600,
+			AddedOn:   1700000000 + int64(i),
+			Category:  "tv-sonarr",
+			NumSeeds:  12,
+			NumLeechs: 3,
+		})
+	}
+	return f
+}
+
+func (f *fakeQBittorrent) start(t testing.TB) downloadclient.Config {
+	server := httptest.NewServer(f)
+	t.Cleanup(server.Close)
+
+	parsed, _ := url.Parse(server.URL)
+	port, _ := strconv.Atoi(parsed.Port())
+	username, password := "admin", "adminadmin"
+	return downloadclient.Config{
+		Name:     "qBittorrent",
+		Type:     "qbittorrent",
+		Host:     parsed.Hostname(),
+		Port:     port,
+		Username: &username,
+		Password: &password,
+	}
+}
+
+// advance moves the next batch of torrents along. The caller holds mu.
+func (f *fakeQBittorrent) advance() {
+	for i := 0; i < f.changing; i++ {
+		torrent := &f.torrents[(f.next+i)%len(f.torrents)]
+		torrent.Progress = min(torrent.Progress+0.01, 1)
+		torrent.ETA -= 10
+		f.changed[torrent.Hash] = true
+	}
+	f.next = (f.next + f.changing) % len(f.torrents)
+}
+
+func (f *fakeQBittorrent) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var response interface{}
+	switch r.URL.Path {
+	case "/api/v2/auth/login":
+		http.SetCookie(w, &http.Cookie{Name: "SID", Value: "session"})
+		return
+	case "/api/v2/torrents/info":
+		f.advance()
+		response = f.torrents
+	case "/api/v2/sync/maindata":
+		f.advance()
+		rid, _ := strconv.ParseInt(r.URL.Query().Get("rid"), 10, 64)
+		data := map[string]interface{}{"rid": f.rid + 1}
+		torrents := make(map[string]interface{})
+		if rid == 0 || rid != f.rid {
+			data["full_update"] = true
+			for _, torrent := range f.torrents {
+				torrents[torrent.Hash] = torrent
+			}
+		} else {
+			// Only the fields that changed
+			for _, torrent := range f.torrents {
+				if f.changed[torrent.Hash] {
+					torrents[torrent.Hash] = map[string]interface{}{"progress": torrent.Progress, "eta": torrent.ETA}
+				}
+			}
+			if len(f.removed) > 0 {
+				data["torrents_removed"] = f.removed
+			}
+		}
+		data["torrents"] = torrents
+		f.rid++
+		f.changed = make(map[string]bool)
+		f.removed = nil
+		response = data
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	body, _ := json.Marshal(response)
+	f.bytes += int64(len(body))
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(body)
+}
+
+// remove deletes a torrent as if it was removed in qBittorrent
+func (f *fakeQBittorrent) remove(hash string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i, torrent := range f.torrents {
+		if torrent.Hash == hash {
+			f.torrents = append(f.torrents[:i], f.torrents[i+1:]...)
+			break
+		}
+	}
+	f.removed = append(f.removed, hash)
+}
+
+func connectQBittorrent(t testing.TB, config downloadclient.Config) *QBitTorrentClient {
+	t.Helper()
+	client, err := NewQBitTorrentClient(config)
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+	if err := client.Connect(context.Background()); err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	return client.(*QBitTorrentClient)
+}
+
+func TestQBittorrentSyncMergesPartialUpdates(t *testing.T) {
+	fake := newFakeQBittorrent(5, 2)
+	client := connectQBittorrent(t, fake.start(t))
+	ctx := context.Background()
+
+	downloads, err := client.GetDownloads(ctx)
+	if err != nil {
+		t.Fatalf("full sync: %v", err)
+	}
+	if len(downloads) != 5 || client.rid != 1 {
+		t.Fatalf("expected 5 downloads at rid 1, got %d at rid %d", len(downloads), client.rid)
+	}
+
+	fake.remove(fake.torrents[4].Hash)
+	downloads, err = client.GetDownloads(ctx)
+	if err != nil {
+		t.Fatalf("partial sync: %v", err)
+	}
+	if len(downloads) != 4 {
+		t.Fatalf("removed torrent should be gone, got %d downloads", len(downloads))
+	}
+
+	// Partial updates keep the fields they don't carry
+	fake.mu.Lock()
+	want := fake.torrents[2]
+	fake.mu.Unlock()
+	got := downloads[2]
+	if got.Progress != want.Progress*100 || got.ETA != int64(want.ETA) {
+		t.Fatalf("expected progress %v and eta %d, got %v and %d", want.Progress*100, want.ETA, got.Progress, got.ETA)
+	}
+	if got.Name != want.Name || got.Seeds == nil || *got.Seeds != 12 {
+		t.Fatalf("unchanged fields were lost: %+v", got)
+	}
+
+	// An unknown rid starts over with a full update
+	client.rid = 42
+	if downloads, err = client.GetDownloads(ctx); err != nil || len(downloads) != 4 {
+		t.Fatalf("expected 4 downloads after a full update, got %d (%v)", len(downloads), err)
+	}
+}
+
+// Each poll of 500 torrents with 20 changing. Run with -bench . -benchmem.
+func BenchmarkQBittorrentTorrentsInfo(b *testing.B) {
+	fake := newFakeQBittorrent(500, 20)
+	client := connectQBittorrent(b, fake.start(b))
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// The whole downloading list on every poll, as before sync/maindata
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("http://%s:%d/api/v2/torrents/info?filter=downloading", client.config.Host, client.config.Port), nil)
+		req.AddCookie(&http.Cookie{Name: "SID", Value: client.sid})
+		resp, err := client.httpClient.Do(req)
+		if err != nil {
+			b.Fatalf("poll: %v", err)
+		}
+		var torrents []qbitTorrentInfo
+		err = json.NewDecoder(resp.Body).Decode(&torrents)
+		resp.Body.Close()
+		if err != nil || len(torrents) != 500 {
+			b.Fatalf("expected 500 torrents, got %d (%v)", len(torrents), err)
+		}
+	}
+	b.ReportMetric(float64(fake.bytes)/float64(b.N), "resp-B/op")
+}
+
+func BenchmarkQBittorrentSyncMainData(b *testing.B) {
+	fake := newFakeQBittorrent(500, 20)
+	client := connectQBittorrent(b, fake.start(b))
+	ctx := context.Background()
+
+	// The first poll is a full update
+	if _, err := client.GetDownloads(ctx); err != nil {
+		b.Fatalf("full sync: %v", err)
+	}
+	fake.bytes = 0
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		downloads, err := client.GetDownloads(ctx)
+		if err != nil || len(downloads) != 500 {
+			b.Fatalf("expected 500 downloads, got %d (%v)", len(downloads), err)
+		}
+	}
+	b.ReportMetric(float64(fake.bytes)/float64(b.N), "resp-B/op")
+}
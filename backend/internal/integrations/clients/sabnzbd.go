@@ -95,6 +95,7 @@ func (c *SABnzbdClient) GetDownloads(ctx context.Context) ([]downloadclient.Item
 			Name:     slot.FileName,
 			Progress: progress,
 			Status:   mappedStatus,
+			State:    slot.Status,
 			TimeLeft: formatSABTimeLeft(slot.TimeLeft),
 			Category: slot.Category,
 			AddedOn:  time.Unix(slot.AddedOn, 0),
@@ -149,6 +150,50 @@ func (c *SABnzbdClient) GetConnectionInfo() downloadclient.ConnectionInfo {
 	}
 }
 
+// SetRateLimits sets the global download limit. Usenet doesn't upload, so the
+// upload limit is ignored.
+func (c *SABnzbdClient) SetRateLimits(ctx context.Context, limits downloadclient.RateLimits) error {
+	if !c.connected {
+		return fmt.Errorf("not connected to SABnzbd")
+	}
+
+	// SABnzbd takes the limit in KB/s with a K suffix, 0 removes it
+	value := "0"
+	if limits.Download > 0 {
+		value = fmt.Sprintf("%dK", max(limits.Download/1024, 1))
+	}
+
+	scheme := utils.Ternary(c.config.UseSSL, "https", "http")
+	baseURL := fmt.Sprintf("%s://%s:%d", scheme, c.config.Host, c.config.Port)
+	url := utils.BuildURL(baseURL, "/api", map[string]string{
+		"mode":   "config",
+		"name":   "speedlimit",
+		"value":  value,
+		"output": "json",
+		"apikey": utils.DerefString(c.config.APIKey),
+	})
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		c.lastError = err.Error()
+		return err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		c.lastError = err.Error()
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		c.lastError = fmt.Sprintf("HTTP %d", resp.StatusCode)
+		return fmt.Errorf("SABnzbd API request failed: %s", resp.Status)
+	}
+
+	return nil
+}
+
 // getQueueInfo fetches queue information from SABnzbd
 func (c *SABnzbdClient) getQueueInfo(ctx context.Context) (*sabnzbdQueueResponse, error) {
 	scheme := utils.Ternary(c.config.UseSSL, "https", "http")
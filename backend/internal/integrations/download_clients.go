@@ -2,6 +2,7 @@ package integrations
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/mahcks/serra/internal/db/repository"
 	"github.com/mahcks/serra/internal/integrations/clients"
@@ -48,8 +49,8 @@ func (f *DownloadClientFactory) CreateClient(dbClient repository.DownloadClient)
 	}
 
 	config.Username = utils.NullableString{NullString: dbClient.Username}.ToPointer()
-	config.Password = utils.NullableString{NullString: dbClient.Password}.ToPointer()
-	config.APIKey = utils.NullableString{NullString: dbClient.ApiKey}.ToPointer()
+	config.Password = utils.NullableString{NullString: dbClient.Password.NullString}.ToPointer()
+	config.APIKey = utils.NullableString{NullString: dbClient.ApiKey.NullString}.ToPointer()
 
 	return constructor(config)
 }
@@ -147,6 +148,20 @@ func (m *DownloadClientManager) GetDownloadProgress(ctx context.Context, downloa
 	return nil, &downloadclient.DownloadNotFoundError{DownloadID: downloadID}
 }
 
+// SetRateLimits sets the global rate limits on every connected client. It
+// returns the result per client id, nil for clients that were updated.
+func (m *DownloadClientManager) SetRateLimits(ctx context.Context, limits downloadclient.RateLimits) map[string]error {
+	results := make(map[string]error, len(m.clients))
+	for clientID, client := range m.clients {
+		if !client.IsConnected() {
+			results[clientID] = fmt.Errorf("not connected to %s", client.GetName())
+			continue
+		}
+		results[clientID] = client.SetRateLimits(ctx, limits)
+	}
+	return results
+}
+
 // CloseAll closes all client connections
 func (m *DownloadClientManager) CloseAll(ctx context.Context) error {
 	for _, client := range m.clients {
@@ -5,12 +5,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
 	"sort"
 	"strings"
 	"time"
 
 	"github.com/mahcks/serra/internal/global"
+	"github.com/mahcks/serra/internal/secrets"
 	"github.com/mahcks/serra/pkg/structures"
 	"github.com/mahcks/serra/utils"
 )
@@ -26,6 +28,7 @@ type Service interface {
 	GetPlaybackMethodStats(days int) ([]structures.JellystatPlaybackMethod, error)
 	GetRecentlyWatched(limit int) ([]structures.JellystatRecentlyWatched, error)
 	GetUserWatchHistory(userID string, limit int) ([]structures.JellystatWatchHistory, error)
+	GetActiveSessions() ([]structures.JellystatSession, error)
 }
 
 type jellystatService struct {
@@ -55,6 +58,10 @@ func (j *jellystatService) getJellystatConfig(ctx context.Context) (enabled bool
 	if err != nil {
 		return false, "", "", fmt.Errorf("failed to get Jellystat API key: %w", err)
 	}
+	apiKey, err = secrets.Open(apiKey)
+	if err != nil {
+		return false, "", "", fmt.Errorf("failed to decrypt Jellystat API key: %w", err)
+	}
 
 	// Check if full URL is provided (overrides host/port/ssl)
 	fullURL, err := j.gctx.Crate().Sqlite.Query().GetSetting(ctx, structures.SettingJellystatURL.String())
@@ -573,3 +580,84 @@ func (j *jellystatService) GetUserWatchHistory(userID string, limit int) ([]stru
 
 	return items, nil
 }
+
+type jellystatSessionItem struct {
+	ID             string `json:"Id"`
+	UserName       string `json:"UserName"`
+	Client         string `json:"Client"`
+	DeviceName     string `json:"DeviceName"`
+	RemoteEndPoint string `json:"RemoteEndPoint"`
+	NowPlayingItem *struct {
+		Name string `json:"Name"`
+	} `json:"NowPlayingItem"`
+	PlayState struct {
+		IsPaused bool `json:"IsPaused"`
+	} `json:"PlayState"`
+}
+
+// GetActiveSessions returns the sessions currently playing something on the media server
+func (j *jellystatService) GetActiveSessions() ([]structures.JellystatSession, error) {
+	ctx := context.Background()
+	enabled, baseURL, apiKey, err := j.getJellystatConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !enabled || baseURL == "" {
+		return utils.EmptyResult[structures.JellystatSession](jellystatNotEnabled.Error())
+	}
+
+	req, err := http.NewRequest("GET", baseURL+"/proxy/getSessions", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-API-Token", apiKey)
+
+	resp, err := j.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Jellystat sessions API returned status %d", resp.StatusCode)
+	}
+
+	var sessions []jellystatSessionItem
+	if err := json.NewDecoder(resp.Body).Decode(&sessions); err != nil {
+		return nil, fmt.Errorf("failed to decode Jellystat sessions response: %w", err)
+	}
+
+	result := []structures.JellystatSession{}
+	for _, session := range sessions {
+		if session.NowPlayingItem == nil {
+			continue
+		}
+		result = append(result, structures.JellystatSession{
+			SessionID:      session.ID,
+			UserName:       session.UserName,
+			Client:         session.Client,
+			DeviceName:     session.DeviceName,
+			RemoteEndPoint: session.RemoteEndPoint,
+			ItemName:       session.NowPlayingItem.Name,
+			IsPaused:       session.PlayState.IsPaused,
+			IsRemote:       isRemoteAddress(session.RemoteEndPoint),
+		})
+	}
+
+	return result, nil
+}
+
+// isRemoteAddress reports whether a client address is outside the local network
+func isRemoteAddress(address string) bool {
+	host := address
+	if h, _, err := net.SplitHostPort(address); err == nil {
+		host = h
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsLinkLocalUnicast()
+}
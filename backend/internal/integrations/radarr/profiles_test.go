@@ -0,0 +1,91 @@
+package radarr
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mahcks/serra/internal/db/repository"
+)
+
+func newFakeRadarr(t *testing.T) repository.ArrService {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Api-Key") != "key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v3/qualityprofile":
+			w.Write([]byte(`[{"id":4,"name":"HD-1080p","upgradeAllowed":true,"cutoff":7,
+				"items":[{"quality":{"id":7,"name":"Bluray-1080p","resolution":1080},"items":[],"allowed":true},
+					{"name":"WEB 1080p","id":1001,"allowed":true,"items":[{"quality":{"id":3,"name":"WEBDL-1080p","source":"webdl","resolution":1080},"items":[],"allowed":true}]}],
+				"formatItems":[{"format":2,"name":"x265","score":-10}],"language":{"id":1,"name":"English"}}]`))
+		case "/api/v3/rootfolder":
+			w.Write([]byte(`[{"path":"/movies","accessible":true,"freeSpace":1073741824,
+				"unmappedFolders":[{"name":"Old","path":"/movies/Old","relativePath":"Old"}]}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return repository.ArrService{Name: "Radarr", BaseUrl: server.URL, ApiKey: "key"}
+}
+
+func TestGetQualityProfiles(t *testing.T) {
+	service := New(nil)
+	profiles, err := service.GetQualityProfiles(context.Background(), newFakeRadarr(t))
+	if err != nil {
+		t.Fatalf("GetQualityProfiles: %v", err)
+	}
+	if len(profiles) != 1 {
+		t.Fatalf("got %d profiles, want 1", len(profiles))
+	}
+
+	profile := profiles[0]
+	if profile.ID != 4 || profile.Name != "HD-1080p" || !profile.UpgradeAllowed || profile.Cutoff != 7 {
+		t.Errorf("profile = %+v", profile)
+	}
+	if len(profile.Items) != 2 || profile.Items[0].Quality.Name != "Bluray-1080p" {
+		t.Fatalf("items = %+v", profile.Items)
+	}
+	group := profile.Items[1]
+	if group.Name != "WEB 1080p" || group.ID != 1001 || len(group.Items) != 1 || group.Items[0].Quality.Source != "webdl" {
+		t.Errorf("grouped item = %+v", group)
+	}
+	if len(profile.FormatItems) != 1 || profile.FormatItems[0].Score != -10 {
+		t.Errorf("format items = %+v", profile.FormatItems)
+	}
+	if profile.Language.Name != "English" {
+		t.Errorf("language = %+v", profile.Language)
+	}
+}
+
+func TestGetRootFolders(t *testing.T) {
+	service := New(nil)
+	folders, err := service.GetRootFolders(context.Background(), newFakeRadarr(t))
+	if err != nil {
+		t.Fatalf("GetRootFolders: %v", err)
+	}
+	if len(folders) != 1 {
+		t.Fatalf("got %d folders, want 1", len(folders))
+	}
+
+	folder := folders[0]
+	if folder.Path != "/movies" || !folder.Accessible || folder.FreeSpace != 1073741824 {
+		t.Errorf("folder = %+v", folder)
+	}
+	if len(folder.UnmappedFolders) != 1 || folder.UnmappedFolders[0].RelativePath != "Old" {
+		t.Errorf("unmapped folders = %+v", folder.UnmappedFolders)
+	}
+}
+
+func TestGetQualityProfilesRejectedKey(t *testing.T) {
+	instance := newFakeRadarr(t)
+	instance.ApiKey = "wrong"
+	if _, err := New(nil).GetQualityProfiles(context.Background(), instance); err == nil {
+		t.Error("expected an error for a rejected API key")
+	}
+}
@@ -17,9 +17,12 @@ import (
 
 type Service interface {
 	GetCalendarItems(ctx context.Context) ([]structures.CalendarItem, error)
-	AddMovie(ctx context.Context, tmdbID int64, qualityProfileID int, rootFolderPath string, minimumAvailability string) (*AddMovieResponse, error)
-	GetMovieByTMDBID(ctx context.Context, tmdbID int64) (*MovieResponse, error)
+	AddMovie(ctx context.Context, instance repository.ArrService, tmdbID int64, qualityProfileID int, rootFolderPath string, minimumAvailability string, tags []int) (*AddMovieResponse, error)
+	GetMovieByTMDBID(ctx context.Context, instance repository.ArrService, tmdbID int64) (*MovieResponse, error)
 	SearchMovie(ctx context.Context, movieID int) error
+	GetQualityProfiles(ctx context.Context, instance repository.ArrService) ([]structures.RadarrQualityProfile, error)
+	GetRootFolders(ctx context.Context, instance repository.ArrService) ([]structures.RadarrRootFolder, error)
+	GetTags(ctx context.Context, instance repository.ArrService) ([]structures.ArrOption, error)
 }
 
 type AddMovieResponse struct {
@@ -54,6 +57,7 @@ type AddMovieRequest struct {
 	MinimumAvailability string `json:"minimumAvailability"`
 	Monitored           bool   `json:"monitored"`
 	SearchForMovie      bool   `json:"searchForMovie"`
+	Tags                []int  `json:"tags,omitempty"`
 }
 
 type radarrService struct {
@@ -167,22 +171,10 @@ func (rs *radarrService) GetCalendarItems(ctx context.Context) ([]structures.Cal
 	return allItems, nil
 }
 
-// AddMovie adds a movie to Radarr
-func (rs *radarrService) AddMovie(ctx context.Context, tmdbID int64, qualityProfileID int, rootFolderPath string, minimumAvailability string) (*AddMovieResponse, error) {
-	instances, err := rs.repo.GetArrServiceByType(ctx, structures.ProviderRadarr.String())
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch radarr instances: %w", err)
-	}
-
-	if len(instances) == 0 {
-		return nil, fmt.Errorf("no Radarr instances configured")
-	}
-
-	// Use the first available instance (or the first 4K if it's a 4K request)
-	instance := instances[0]
-
+// AddMovie adds a movie to the given Radarr instance
+func (rs *radarrService) AddMovie(ctx context.Context, instance repository.ArrService, tmdbID int64, qualityProfileID int, rootFolderPath string, minimumAvailability string, tags []int) (*AddMovieResponse, error) {
 	// First, check if the movie already exists
-	existingMovie, _ := rs.GetMovieByTMDBID(ctx, tmdbID)
+	existingMovie, _ := rs.GetMovieByTMDBID(ctx, instance, tmdbID)
 	if existingMovie != nil {
 		return &AddMovieResponse{
 			ID:                  existingMovie.ID,
@@ -203,6 +195,7 @@ func (rs *radarrService) AddMovie(ctx context.Context, tmdbID int64, qualityProf
 		MinimumAvailability: minimumAvailability,
 		Monitored:           true,
 		SearchForMovie:      true,
+		Tags:                tags,
 	}
 
 	requestBody, err := json.Marshal(addRequest)
@@ -239,8 +232,8 @@ func (rs *radarrService) AddMovie(ctx context.Context, tmdbID int64, qualityProf
 		"monitored", response.Monitored,
 		"root_folder", response.RootFolderPath)
 
-	// Trigger search for the newly added movie
-	if err := rs.SearchMovie(ctx, response.ID); err != nil {
+	// Trigger search for the newly added movie on the instance it was added to
+	if err := rs.searchMovieOnInstance(ctx, instance, response.ID); err != nil {
 		slog.Warn("Failed to trigger automatic search for movie", 
 			"movieID", response.ID, 
 			"title", response.Title, 
@@ -251,19 +244,8 @@ func (rs *radarrService) AddMovie(ctx context.Context, tmdbID int64, qualityProf
 	return &response, nil
 }
 
-// GetMovieByTMDBID retrieves a movie from Radarr by TMDB ID
-func (rs *radarrService) GetMovieByTMDBID(ctx context.Context, tmdbID int64) (*MovieResponse, error) {
-	instances, err := rs.repo.GetArrServiceByType(ctx, structures.ProviderRadarr.String())
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch radarr instances: %w", err)
-	}
-
-	if len(instances) == 0 {
-		return nil, fmt.Errorf("no Radarr instances configured")
-	}
-
-	instance := instances[0]
-
+// GetMovieByTMDBID retrieves a movie from the given Radarr instance by TMDB ID
+func (rs *radarrService) GetMovieByTMDBID(ctx context.Context, instance repository.ArrService, tmdbID int64) (*MovieResponse, error) {
 	url := fmt.Sprintf("%s/api/v3/movie?apikey=%s&tmdbId=%d", instance.BaseUrl, instance.ApiKey, tmdbID)
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
@@ -358,3 +340,45 @@ func (rs *radarrService) searchMovieOnInstance(ctx context.Context, instance rep
 
 	return nil
 }
+
+// GetTags returns the tags defined on the given Radarr instance
+func (rs *radarrService) GetTags(ctx context.Context, instance repository.ArrService) ([]structures.ArrOption, error) {
+	var tags []struct {
+		ID    int    `json:"id"`
+		Label string `json:"label"`
+	}
+	if err := rs.get(ctx, instance, "tag", &tags); err != nil {
+		return nil, err
+	}
+
+	options := make([]structures.ArrOption, 0, len(tags))
+	for _, tag := range tags {
+		options = append(options, structures.ArrOption{ID: tag.ID, Name: tag.Label})
+	}
+	return options, nil
+}
+
+// get fetches an /api/v3 resource from the given instance and decodes it into out
+func (rs *radarrService) get(ctx context.Context, instance repository.ArrService, resource string, out interface{}) error {
+	url := fmt.Sprintf("%s/api/v3/%s", instance.BaseUrl, resource)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("X-Api-Key", instance.ApiKey.String())
+
+	resp, err := rs.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to contact Radarr: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Radarr returned status %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode Radarr response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package sonarr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mahcks/serra/internal/db/repository"
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+// fakeSonarr serves one series whose episodes appear once loaded is set
+type fakeSonarr struct {
+	mu        sync.Mutex
+	loaded    bool
+	episodes  []EpisodeResponse
+	monitored [][]int // Episode IDs of each monitor call
+	searched  [][]int // Episode IDs of each EpisodeSearch command
+}
+
+func newFakeSonarr(t *testing.T) (*fakeSonarr, repository.ArrService) {
+	t.Helper()
+	fake := &fakeSonarr{episodes: []EpisodeResponse{
+		{ID: 101, SeriesID: 7, SeasonNumber: 1, EpisodeNumber: 1},
+		{ID: 102, SeriesID: 7, SeasonNumber: 1, EpisodeNumber: 2},
+		{ID: 103, SeriesID: 7, SeasonNumber: 1, EpisodeNumber: 3, Monitored: true},
+		{ID: 201, SeriesID: 7, SeasonNumber: 2, EpisodeNumber: 1},
+	}}
+	server := httptest.NewServer(http.HandlerFunc(fake.serve))
+	t.Cleanup(server.Close)
+	return fake, repository.ArrService{Name: "Sonarr", BaseUrl: server.URL, ApiKey: "key"}
+}
+
+func (f *fakeSonarr) serve(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v3/series":
+		json.NewEncoder(w).Encode([]SeriesResponse{})
+	case r.Method == http.MethodPost && r.URL.Path == "/api/v3/series":
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(AddSeriesResponse{ID: 7, Title: "Show"})
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v3/episode":
+		if !f.loaded {
+			w.Write([]byte(`[]`))
+			return
+		}
+		json.NewEncoder(w).Encode(f.episodes)
+	case r.Method == http.MethodPut && r.URL.Path == "/api/v3/episode/monitor":
+		var body struct {
+			EpisodeIDs []int `json:"episodeIds"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		f.monitored = append(f.monitored, body.EpisodeIDs)
+		for i := range f.episodes {
+			if slices.Contains(body.EpisodeIDs, f.episodes[i].ID) {
+				f.episodes[i].Monitored = true
+			}
+		}
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(`[]`))
+	case r.Method == http.MethodPost && r.URL.Path == "/api/v3/command":
+		var body struct {
+			EpisodeIDs []int `json:"episodeIds"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		f.searched = append(f.searched, body.EpisodeIDs)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{}`))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (f *fakeSonarr) load() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.loaded = true
+}
+
+func (f *fakeSonarr) calls() (monitored, searched [][]int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Clone(f.monitored), slices.Clone(f.searched)
+}
+
+func TestMonitorEpisodes(t *testing.T) {
+	fake, instance := newFakeSonarr(t)
+	service := New(nil)
+	requested := structures.RequestedEpisodes{1: {2, 3}, 2: {1}}
+
+	if _, err := service.MonitorEpisodes(context.Background(), instance, 7, requested); !errors.Is(err, ErrEpisodesNotLoaded) {
+		t.Fatalf("err before Sonarr loaded the episodes = %v, want ErrEpisodesNotLoaded", err)
+	}
+
+	fake.load()
+	count, err := service.MonitorEpisodes(context.Background(), instance, 7, requested)
+	if err != nil {
+		t.Fatalf("MonitorEpisodes: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("monitored %d episodes, want 2", count)
+	}
+	monitored, searched := fake.calls()
+	if len(monitored) != 1 || !slices.Equal(monitored[0], []int{102, 201}) {
+		t.Errorf("monitor calls = %v, want [[102 201]]", monitored)
+	}
+	if len(searched) != 1 || !slices.Equal(searched[0], []int{102, 201}) {
+		t.Errorf("search commands = %v, want [[102 201]]", searched)
+	}
+
+	// Nothing left to do once every requested episode is monitored
+	count, err = service.MonitorEpisodes(context.Background(), instance, 7, requested)
+	if err != nil || count != 0 {
+		t.Errorf("second MonitorEpisodes = %d, %v, want 0, nil", count, err)
+	}
+	if monitored, _ := fake.calls(); len(monitored) != 1 {
+		t.Errorf("episodes monitored again: %v", monitored)
+	}
+
+	if _, err := service.MonitorEpisodes(context.Background(), instance, 7, structures.RequestedEpisodes{5: {1}}); err == nil {
+		t.Error("expected an error when none of the requested episodes exist")
+	}
+}
+
+func TestAddSeriesWithEpisodesDoesNotWaitForSonarr(t *testing.T) {
+	fake, instance := newFakeSonarr(t)
+	service := New(nil)
+
+	start := time.Now()
+	response, err := service.AddSeriesWithEpisodes(context.Background(), instance, 1399, 1, "/tv", structures.RequestedEpisodes{1: {1}}, "standard", nil, false)
+	if err != nil {
+		t.Fatalf("AddSeriesWithEpisodes: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("adding the series took %s, it should not wait for the episodes", elapsed)
+	}
+	if response.ID != 7 {
+		t.Errorf("series ID = %d, want 7", response.ID)
+	}
+	if response.EpisodesMonitored {
+		t.Error("response reports the episodes monitored before Sonarr loaded them")
+	}
+	if monitored, _ := fake.calls(); len(monitored) != 0 {
+		t.Errorf("episodes monitored before Sonarr loaded them: %v", monitored)
+	}
+
+	// The request processor job monitors them on a later run
+	fake.load()
+	if count, err := service.MonitorEpisodes(context.Background(), instance, 7, structures.RequestedEpisodes{1: {1}}); err != nil || count != 1 {
+		t.Errorf("MonitorEpisodes after loading = %d, %v, want 1, nil", count, err)
+	}
+}
+
+func TestAddSeriesWithEpisodesReportsMonitored(t *testing.T) {
+	fake, instance := newFakeSonarr(t)
+	fake.load()
+	service := New(nil)
+
+	response, err := service.AddSeriesWithEpisodes(context.Background(), instance, 1399, 1, "/tv", structures.RequestedEpisodes{1: {1}}, "standard", nil, false)
+	if err != nil {
+		t.Fatalf("AddSeriesWithEpisodes: %v", err)
+	}
+	if !response.EpisodesMonitored {
+		t.Error("response does not report the loaded episodes as monitored")
+	}
+	if monitored, _ := fake.calls(); len(monitored) != 1 || !slices.Equal(monitored[0], []int{101}) {
+		t.Errorf("monitor calls = %v, want [[101]]", monitored)
+	}
+}
@@ -0,0 +1,155 @@
+package sonarr
+
+import (
+	"context"
+
+	"github.com/mahcks/serra/internal/db/repository"
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+// qualityProfile is a quality profile as returned by the Sonarr API
+type qualityProfile struct {
+	ID                    int                  `json:"id"`
+	Name                  string               `json:"name"`
+	UpgradeAllowed        bool                 `json:"upgradeAllowed"`
+	Cutoff                int                  `json:"cutoff"`
+	Items                 []qualityProfileItem `json:"items"`
+	MinFormatScore        int                  `json:"minFormatScore"`
+	CutoffFormatScore     int                  `json:"cutoffFormatScore"`
+	MinUpgradeFormatScore int                  `json:"minUpgradeFormatScore"`
+	FormatItems           []formatItem         `json:"formatItems"`
+	Language              language             `json:"language"`
+}
+
+type qualityProfileItem struct {
+	Quality quality              `json:"quality"`
+	Items   []qualityProfileItem `json:"items"`
+	Allowed bool                 `json:"allowed"`
+	Name    string               `json:"name,omitempty"` // Only present for grouped items
+	ID      int                  `json:"id,omitempty"`   // Only present for grouped items
+}
+
+type quality struct {
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	Source     string `json:"source"`
+	Resolution int    `json:"resolution"`
+	Modifier   string `json:"modifier"`
+}
+
+type formatItem struct {
+	Format int    `json:"format"`
+	Name   string `json:"name"`
+	Score  int    `json:"score"`
+}
+
+type language struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// rootFolder is a root folder as returned by the Sonarr API
+type rootFolder struct {
+	Path            string           `json:"path"`
+	Accessible      bool             `json:"accessible"`
+	FreeSpace       int64            `json:"freeSpace"`
+	UnmappedFolders []unmappedFolder `json:"unmappedFolders,omitempty"`
+}
+
+type unmappedFolder struct {
+	Name         string `json:"name"`
+	Path         string `json:"path"`
+	RelativePath string `json:"relativePath"`
+}
+
+// GetQualityProfiles returns the quality profiles configured on the given Sonarr instance
+func (ss *sonarrService) GetQualityProfiles(ctx context.Context, instance repository.ArrService) ([]structures.SonarrQualityProfile, error) {
+	var profiles []qualityProfile
+	if err := ss.get(ctx, instance, "qualityprofile", &profiles); err != nil {
+		return nil, err
+	}
+
+	result := make([]structures.SonarrQualityProfile, 0, len(profiles))
+	for _, profile := range profiles {
+		sonarrProfile := structures.SonarrQualityProfile{
+			ID:                    profile.ID,
+			Name:                  profile.Name,
+			UpgradeAllowed:        profile.UpgradeAllowed,
+			Cutoff:                profile.Cutoff,
+			MinFormatScore:        profile.MinFormatScore,
+			CutoffFormatScore:     profile.CutoffFormatScore,
+			MinUpgradeFormatScore: profile.MinUpgradeFormatScore,
+			Items:                 convertProfileItems(profile.Items),
+			FormatItems:           make([]structures.SonarrFormatItem, 0, len(profile.FormatItems)),
+			Language: structures.SonarrLanguage{
+				ID:   profile.Language.ID,
+				Name: profile.Language.Name,
+			},
+		}
+		for _, item := range profile.FormatItems {
+			sonarrProfile.FormatItems = append(sonarrProfile.FormatItems, structures.SonarrFormatItem{
+				Format: item.Format,
+				Name:   item.Name,
+				Score:  item.Score,
+			})
+		}
+		result = append(result, sonarrProfile)
+	}
+	return result, nil
+}
+
+// convertProfileItems converts quality profile items, including the qualities
+// of grouped items
+func convertProfileItems(items []qualityProfileItem) []structures.SonarrQualityProfileItem {
+	converted := make([]structures.SonarrQualityProfileItem, 0, len(items))
+	for _, item := range items {
+		sonarrItem := structures.SonarrQualityProfileItem{
+			Quality: structures.SonarrQuality{
+				ID:         item.Quality.ID,
+				Name:       item.Quality.Name,
+				Source:     item.Quality.Source,
+				Resolution: item.Quality.Resolution,
+				Modifier:   item.Quality.Modifier,
+			},
+			Allowed: item.Allowed,
+			Name:    item.Name,
+			ID:      item.ID,
+		}
+		if len(item.Items) > 0 {
+			sonarrItem.Items = convertProfileItems(item.Items)
+		}
+		converted = append(converted, sonarrItem)
+	}
+	return converted
+}
+
+// GetRootFolders returns the root folders configured on the given Sonarr instance
+func (ss *sonarrService) GetRootFolders(ctx context.Context, instance repository.ArrService) ([]structures.SonarrRootFolder, error) {
+	var folders []rootFolder
+	if err := ss.get(ctx, instance, "rootfolder", &folders); err != nil {
+		return nil, err
+	}
+
+	result := make([]structures.SonarrRootFolder, 0, len(folders))
+	for _, folder := range folders {
+		rf := structures.SonarrRootFolder{
+			Path:       folder.Path,
+			Accessible: folder.Accessible,
+			FreeSpace:  folder.FreeSpace,
+		}
+
+		if len(folder.UnmappedFolders) > 0 {
+			rf.UnmappedFolders = make([]structures.SonarrUnmappedFolder, len(folder.UnmappedFolders))
+			for i, unmapped := range folder.UnmappedFolders {
+				rf.UnmappedFolders[i] = structures.SonarrUnmappedFolder{
+					Name:         unmapped.Name,
+					Path:         unmapped.Path,
+					RelativePath: unmapped.RelativePath,
+				}
+			}
+		}
+
+		result = append(result, rf)
+	}
+	return result, nil
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
@@ -17,10 +18,17 @@ import (
 
 type Service interface {
 	GetUpcomingItems(ctx context.Context) ([]structures.CalendarItem, error)
-	AddSeries(ctx context.Context, tmdbID int64, qualityProfileID int, rootFolderPath string) (*AddSeriesResponse, error)
-	AddSeriesWithSeasons(ctx context.Context, tmdbID int64, qualityProfileID int, rootFolderPath string, seasons []int) (*AddSeriesResponse, error)
-	GetSeriesByTMDBID(ctx context.Context, tmdbID int64) (*SeriesResponse, error)
+	AddSeries(ctx context.Context, instance repository.ArrService, tmdbID int64, qualityProfileID int, rootFolderPath string, seriesType string, tags []int) (*AddSeriesResponse, error)
+	AddSeriesWithSeasons(ctx context.Context, instance repository.ArrService, tmdbID int64, qualityProfileID int, rootFolderPath string, seasons []int, seriesType string, tags []int, followShow bool) (*AddSeriesResponse, error)
+	AddSeriesWithEpisodes(ctx context.Context, instance repository.ArrService, tmdbID int64, qualityProfileID int, rootFolderPath string, episodes structures.RequestedEpisodes, seriesType string, tags []int, followShow bool) (*AddSeriesResponse, error)
+	GetEpisodes(ctx context.Context, instance repository.ArrService, seriesID int) ([]EpisodeResponse, error)
+	MonitorEpisodes(ctx context.Context, instance repository.ArrService, seriesID int, requested structures.RequestedEpisodes) (int, error)
+	SetMonitorNewItems(ctx context.Context, instance repository.ArrService, seriesID int, followShow bool) error
+	GetSeriesByTMDBID(ctx context.Context, instance repository.ArrService, tmdbID int64) (*SeriesResponse, error)
 	SearchSeries(ctx context.Context, seriesID int) error
+	GetQualityProfiles(ctx context.Context, instance repository.ArrService) ([]structures.SonarrQualityProfile, error)
+	GetRootFolders(ctx context.Context, instance repository.ArrService) ([]structures.SonarrRootFolder, error)
+	GetTags(ctx context.Context, instance repository.ArrService) ([]structures.ArrOption, error)
 }
 
 type AddSeriesResponse struct {
@@ -31,6 +39,10 @@ type AddSeriesResponse struct {
 	RootFolderPath   string `json:"rootFolderPath"`
 	Monitored        bool   `json:"monitored"`
 	Added            string `json:"added"`
+
+	// Whether the requested episodes were monitored, false when Sonarr had not
+	// loaded them yet
+	EpisodesMonitored bool `json:"-"`
 }
 
 type SeriesResponse struct {
@@ -58,6 +70,9 @@ type AddSeriesRequest struct {
 	SearchForMissingEpisodes bool `json:"searchForMissingEpisodes"`
 	MonitorType      string `json:"monitorType"`
 	Seasons          []SeasonRequest `json:"seasons,omitempty"`
+	SeriesType       string `json:"seriesType,omitempty"`
+	Tags             []int  `json:"tags,omitempty"`
+	MonitorNewItems  string `json:"monitorNewItems,omitempty"` // "all" monitors seasons added after the series
 }
 
 type SeasonRequest struct {
@@ -65,6 +80,16 @@ type SeasonRequest struct {
 	Monitored    bool `json:"monitored"`
 }
 
+type EpisodeResponse struct {
+	ID            int    `json:"id"`
+	SeriesID      int    `json:"seriesId"`
+	SeasonNumber  int    `json:"seasonNumber"`
+	EpisodeNumber int    `json:"episodeNumber"`
+	Title         string `json:"title"`
+	HasFile       bool   `json:"hasFile"`
+	Monitored     bool   `json:"monitored"`
+}
+
 type sonarrService struct {
 	repo   *repository.Queries
 	client *http.Client
@@ -226,22 +251,10 @@ func (ss *sonarrService) GetUpcomingItems(ctx context.Context) ([]structures.Cal
 	return allItems, nil
 }
 
-// AddSeries adds a TV series to Sonarr
-func (ss *sonarrService) AddSeries(ctx context.Context, tmdbID int64, qualityProfileID int, rootFolderPath string) (*AddSeriesResponse, error) {
-	instances, err := ss.repo.GetArrServiceByType(ctx, structures.ProviderSonarr.String())
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch sonarr instances: %w", err)
-	}
-
-	if len(instances) == 0 {
-		return nil, fmt.Errorf("no Sonarr instances configured")
-	}
-
-	// Use the first available instance
-	instance := instances[0]
-
+// AddSeries adds a TV series to the given Sonarr instance
+func (ss *sonarrService) AddSeries(ctx context.Context, instance repository.ArrService, tmdbID int64, qualityProfileID int, rootFolderPath string, seriesType string, tags []int) (*AddSeriesResponse, error) {
 	// First, check if the series already exists
-	existingSeries, _ := ss.GetSeriesByTMDBID(ctx, tmdbID)
+	existingSeries, _ := ss.GetSeriesByTMDBID(ctx, instance, tmdbID)
 	if existingSeries != nil {
 		return &AddSeriesResponse{
 			ID:               existingSeries.ID,
@@ -261,6 +274,8 @@ func (ss *sonarrService) AddSeries(ctx context.Context, tmdbID int64, qualityPro
 		Monitored:                true,
 		SearchForMissingEpisodes: true,
 		MonitorType:             "all", // Monitor all episodes
+		SeriesType:               seriesType,
+		Tags:                     tags,
 	}
 
 	requestBody, err := json.Marshal(addRequest)
@@ -298,7 +313,7 @@ func (ss *sonarrService) AddSeries(ctx context.Context, tmdbID int64, qualityPro
 		"root_folder", response.RootFolderPath)
 
 	// Trigger search for the newly added series
-	if err := ss.SearchSeries(ctx, response.ID); err != nil {
+	if err := ss.searchSeriesOnInstance(ctx, instance, response.ID); err != nil {
 		slog.Warn("Failed to trigger automatic search for series", 
 			"seriesID", response.ID, 
 			"title", response.Title, 
@@ -309,25 +324,21 @@ func (ss *sonarrService) AddSeries(ctx context.Context, tmdbID int64, qualityPro
 	return &response, nil
 }
 
-// AddSeriesWithSeasons adds a TV series to Sonarr with specific season monitoring
-func (ss *sonarrService) AddSeriesWithSeasons(ctx context.Context, tmdbID int64, qualityProfileID int, rootFolderPath string, seasons []int) (*AddSeriesResponse, error) {
-	instances, err := ss.repo.GetArrServiceByType(ctx, structures.ProviderSonarr.String())
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch sonarr instances: %w", err)
-	}
-
-	if len(instances) == 0 {
-		return nil, fmt.Errorf("no Sonarr instances configured")
-	}
-
-	// Use the first available instance
-	instance := instances[0]
-
+// AddSeriesWithSeasons adds a TV series to the given Sonarr instance with specific season monitoring
+func (ss *sonarrService) AddSeriesWithSeasons(ctx context.Context, instance repository.ArrService, tmdbID int64, qualityProfileID int, rootFolderPath string, seasons []int, seriesType string, tags []int, followShow bool) (*AddSeriesResponse, error) {
 	// First, check if the series already exists
-	existingSeries, _ := ss.GetSeriesByTMDBID(ctx, tmdbID)
+	existingSeries, _ := ss.GetSeriesByTMDBID(ctx, instance, tmdbID)
 	if existingSeries != nil {
 		// Series already exists - we need to update season monitoring
 		// For now, return the existing series (could be enhanced to update monitoring)
+		if followShow {
+			if err := ss.SetMonitorNewItems(ctx, instance, existingSeries.ID, true); err != nil {
+				slog.Warn("Failed to monitor new seasons of existing series",
+					"seriesID", existingSeries.ID,
+					"title", existingSeries.Title,
+					"error", err)
+			}
+		}
 		return &AddSeriesResponse{
 			ID:               existingSeries.ID,
 			Title:            existingSeries.Title,
@@ -365,6 +376,9 @@ func (ss *sonarrService) AddSeriesWithSeasons(ctx context.Context, tmdbID int64,
 		SearchForMissingEpisodes: true,
 		MonitorType:              monitorType,
 		Seasons:                  seasonRequests,
+		SeriesType:               seriesType,
+		Tags:                     tags,
+		MonitorNewItems:          monitorNewItems(followShow),
 	}
 
 	requestBody, err := json.Marshal(addRequest)
@@ -402,7 +416,7 @@ func (ss *sonarrService) AddSeriesWithSeasons(ctx context.Context, tmdbID int64,
 		"root_folder", response.RootFolderPath)
 
 	// Trigger search for the newly added series
-	if err := ss.SearchSeries(ctx, response.ID); err != nil {
+	if err := ss.searchSeriesOnInstance(ctx, instance, response.ID); err != nil {
 		slog.Warn("Failed to trigger automatic search for series with seasons", 
 			"seriesID", response.ID, 
 			"title", response.Title, 
@@ -413,19 +427,158 @@ func (ss *sonarrService) AddSeriesWithSeasons(ctx context.Context, tmdbID int64,
 	return &response, nil
 }
 
-// GetSeriesByTMDBID retrieves a TV series from Sonarr by TMDB ID
-func (ss *sonarrService) GetSeriesByTMDBID(ctx context.Context, tmdbID int64) (*SeriesResponse, error) {
-	instances, err := ss.repo.GetArrServiceByType(ctx, structures.ProviderSonarr.String())
+// AddSeriesWithEpisodes adds a TV series to the given Sonarr instance with only
+// the requested episodes monitored, then searches for them. When Sonarr has not
+// loaded the episodes of a new series yet, the series is added without them and
+// MonitorEpisodes has to be called again later.
+func (ss *sonarrService) AddSeriesWithEpisodes(ctx context.Context, instance repository.ArrService, tmdbID int64, qualityProfileID int, rootFolderPath string, episodes structures.RequestedEpisodes, seriesType string, tags []int, followShow bool) (*AddSeriesResponse, error) {
+	var response AddSeriesResponse
+
+	existingSeries, _ := ss.GetSeriesByTMDBID(ctx, instance, tmdbID)
+	if existingSeries != nil {
+		response = AddSeriesResponse{
+			ID:               existingSeries.ID,
+			Title:            existingSeries.Title,
+			TmdbID:           existingSeries.TmdbID,
+			QualityProfileID: existingSeries.QualityProfileID,
+			RootFolderPath:   existingSeries.RootFolderPath,
+			Monitored:        existingSeries.Monitored,
+		}
+		if followShow {
+			if err := ss.SetMonitorNewItems(ctx, instance, existingSeries.ID, true); err != nil {
+				slog.Warn("Failed to monitor new seasons of existing series",
+					"seriesID", existingSeries.ID,
+					"title", existingSeries.Title,
+					"error", err)
+			}
+		}
+	} else {
+		// Add the series with nothing monitored; the requested episodes are
+		// monitored once Sonarr has loaded the episode list
+		addRequest := AddSeriesRequest{
+			TmdbID:                   tmdbID,
+			QualityProfileID:         qualityProfileID,
+			RootFolderPath:           rootFolderPath,
+			Monitored:                true,
+			SearchForMissingEpisodes: false,
+			MonitorType:              "none",
+			SeriesType:               seriesType,
+			Tags:                     tags,
+			MonitorNewItems:          monitorNewItems(followShow),
+		}
+		if err := ss.send(ctx, instance, http.MethodPost, "series", addRequest, &response); err != nil {
+			return nil, err
+		}
+
+		slog.Info("Series with episodes added to Sonarr successfully",
+			"seriesID", response.ID,
+			"title", response.Title,
+			"root_folder", response.RootFolderPath)
+	}
+
+	// A newly added series has no episodes until Sonarr has refreshed it; the
+	// request processor job monitors them on a later run in that case
+	if _, err := ss.MonitorEpisodes(ctx, instance, response.ID, episodes); err != nil {
+		if !errors.Is(err, ErrEpisodesNotLoaded) {
+			return nil, err
+		}
+		slog.Info("Sonarr has not loaded the episodes yet, monitoring them later",
+			"seriesID", response.ID,
+			"title", response.Title)
+	} else {
+		response.EpisodesMonitored = true
+	}
+
+	return &response, nil
+}
+
+// ErrEpisodesNotLoaded is returned by MonitorEpisodes while Sonarr has not
+// loaded the episode list of a newly added series
+var ErrEpisodesNotLoaded = errors.New("Sonarr has not loaded the episodes of the series yet")
+
+// MonitorEpisodes monitors and searches for the requested episodes of a series
+// that are not monitored yet, returning how many were. It does nothing once
+// all of them are monitored, so it can be called until it succeeds.
+func (ss *sonarrService) MonitorEpisodes(ctx context.Context, instance repository.ArrService, seriesID int, requested structures.RequestedEpisodes) (int, error) {
+	episodes, err := ss.GetEpisodes(ctx, instance, seriesID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch sonarr instances: %w", err)
+		return 0, err
+	}
+	if len(episodes) == 0 {
+		return 0, ErrEpisodesNotLoaded
 	}
 
-	if len(instances) == 0 {
-		return nil, fmt.Errorf("no Sonarr instances configured")
+	found := false
+	var episodeIDs []int
+	for _, episode := range episodes {
+		if !requested.Contains(episode.SeasonNumber, episode.EpisodeNumber) {
+			continue
+		}
+		found = true
+		if !episode.Monitored {
+			episodeIDs = append(episodeIDs, episode.ID)
+		}
+	}
+	if !found {
+		return 0, fmt.Errorf("none of the requested episodes exist in Sonarr for series %d", seriesID)
+	}
+	if len(episodeIDs) == 0 {
+		return 0, nil
 	}
 
-	instance := instances[0]
+	if err := ss.send(ctx, instance, http.MethodPut, "episode/monitor", map[string]interface{}{
+		"episodeIds": episodeIDs,
+		"monitored":  true,
+	}, nil); err != nil {
+		return 0, fmt.Errorf("failed to monitor episodes: %w", err)
+	}
 
+	if err := ss.send(ctx, instance, http.MethodPost, "command", map[string]interface{}{
+		"name":       "EpisodeSearch",
+		"episodeIds": episodeIDs,
+	}, nil); err != nil {
+		slog.Warn("Failed to trigger automatic search for episodes",
+			"seriesID", seriesID,
+			"error", err)
+		// Don't fail the entire operation if search trigger fails
+	}
+
+	slog.Info("Monitored requested episodes in Sonarr", "seriesID", seriesID, "episodes", len(episodeIDs))
+	return len(episodeIDs), nil
+}
+
+// GetEpisodes returns every episode Sonarr knows for the given series
+func (ss *sonarrService) GetEpisodes(ctx context.Context, instance repository.ArrService, seriesID int) ([]EpisodeResponse, error) {
+	var episodes []EpisodeResponse
+	if err := ss.get(ctx, instance, fmt.Sprintf("episode?seriesId=%d", seriesID), &episodes); err != nil {
+		return nil, err
+	}
+	return episodes, nil
+}
+
+// SetMonitorNewItems controls whether Sonarr monitors seasons that are added
+// to the series later on
+func (ss *sonarrService) SetMonitorNewItems(ctx context.Context, instance repository.ArrService, seriesID int, followShow bool) error {
+	// The series is round-tripped as a generic object so fields this client
+	// doesn't model are sent back unchanged
+	var series map[string]interface{}
+	if err := ss.get(ctx, instance, fmt.Sprintf("series/%d", seriesID), &series); err != nil {
+		return err
+	}
+
+	series["monitorNewItems"] = monitorNewItems(followShow)
+	return ss.send(ctx, instance, http.MethodPut, fmt.Sprintf("series/%d", seriesID), series, nil)
+}
+
+func monitorNewItems(followShow bool) string {
+	if followShow {
+		return "all"
+	}
+	return "none"
+}
+
+// GetSeriesByTMDBID retrieves a TV series from the given Sonarr instance by TMDB ID
+func (ss *sonarrService) GetSeriesByTMDBID(ctx context.Context, instance repository.ArrService, tmdbID int64) (*SeriesResponse, error) {
 	url := fmt.Sprintf("%s/api/v3/series?apikey=%s&tmdbId=%d", instance.BaseUrl, instance.ApiKey, tmdbID)
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
@@ -520,3 +673,81 @@ func (ss *sonarrService) searchSeriesOnInstance(ctx context.Context, instance re
 
 	return nil
 }
+
+// GetTags returns the tags defined on the given Sonarr instance
+func (ss *sonarrService) GetTags(ctx context.Context, instance repository.ArrService) ([]structures.ArrOption, error) {
+	var tags []struct {
+		ID    int    `json:"id"`
+		Label string `json:"label"`
+	}
+	if err := ss.get(ctx, instance, "tag", &tags); err != nil {
+		return nil, err
+	}
+
+	options := make([]structures.ArrOption, 0, len(tags))
+	for _, tag := range tags {
+		options = append(options, structures.ArrOption{ID: tag.ID, Name: tag.Label})
+	}
+	return options, nil
+}
+
+// get fetches an /api/v3 resource from the given instance and decodes it into out
+func (ss *sonarrService) get(ctx context.Context, instance repository.ArrService, resource string, out interface{}) error {
+	url := fmt.Sprintf("%s/api/v3/%s", instance.BaseUrl, resource)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("X-Api-Key", instance.ApiKey.String())
+
+	resp, err := ss.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to contact Sonarr: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Sonarr returned status %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode Sonarr response: %w", err)
+	}
+	return nil
+}
+
+// send writes body as JSON to an /api/v3 resource on the given instance and
+// decodes the response into out when out is not nil
+func (ss *sonarrService) send(ctx context.Context, instance repository.ArrService, method, resource string, body interface{}, out interface{}) error {
+	requestBody, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/api/v3/%s", instance.BaseUrl, resource)
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewBuffer(requestBody))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Api-Key", instance.ApiKey.String())
+
+	resp, err := ss.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to contact Sonarr: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		responseBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("Sonarr returned status %d: %s", resp.StatusCode, string(responseBody))
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode Sonarr response: %w", err)
+	}
+	return nil
+}
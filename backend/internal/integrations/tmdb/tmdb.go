@@ -23,6 +23,7 @@ type Service interface {
 	GetTvRecommendations(seriesID string, page string) (structures.TMDBMediaResponse, error)
 	GetTvSimilar(seriesID string, page string) (structures.TMDBMediaResponse, error)
 	GetSeasonDetails(seriesID string, seasonNumber string) (structures.SeasonDetails, error)
+	GetTVDetails(seriesID string) (structures.TVDetails, error)
 
 	SearchMovie(query, page string) (structures.TMDBMediaResponse, error)
 	DiscoverMovie(params structures.DiscoverMovieParams) (structures.TMDBMediaResponse, error)
@@ -45,6 +46,12 @@ type Service interface {
 
 	// Person details
 	GetPerson(personID string) (structures.TMDBPersonResponse, error)
+
+	// Genres, language, keywords and certification used for request routing
+	GetMediaClassification(mediaType, id string) (structures.TMDBMediaClassification, error)
+
+	// Look up movies and TV shows by IMDb or TVDB id
+	FindByExternalID(externalID, source string) (structures.TMDBFindResponse, error)
 }
 
 type tmdbService struct {
@@ -585,3 +592,180 @@ func (t *tmdbService) GetPerson(personID string) (structures.TMDBPersonResponse,
 
 	return result, nil
 }
+
+// classificationResponse is the raw details response with keywords and
+// certifications appended. Movies and TV shows name these fields differently.
+type classificationResponse struct {
+	ID               int64              `json:"id"`
+	Title            string             `json:"title"`
+	Name             string             `json:"name"`
+	PosterPath       string             `json:"poster_path"`
+	Genres           []structures.Genre `json:"genres"`
+	OriginalLanguage string             `json:"original_language"`
+	Keywords         struct {
+		Keywords []structures.TMDBKeyword `json:"keywords"` // movies
+		Results  []structures.TMDBKeyword `json:"results"`  // TV
+	} `json:"keywords"`
+	ReleaseDates   structures.TMDBReleaseDatesResponse `json:"release_dates"`
+	ContentRatings struct {
+		Results []struct {
+			ISO3166_1 string `json:"iso_3166_1"`
+			Rating    string `json:"rating"`
+		} `json:"results"`
+	} `json:"content_ratings"`
+}
+
+// GetMediaClassification fetches the genres, original language, keywords and US
+// certification of a movie or TV show.
+func (t *tmdbService) GetMediaClassification(mediaType, id string) (structures.TMDBMediaClassification, error) {
+	appendToResponse := "keywords,release_dates"
+	if mediaType == "tv" {
+		appendToResponse = "keywords,content_ratings"
+	}
+
+	u, err := url.Parse(t.baseURL + "/" + mediaType + "/" + id)
+	if err != nil {
+		return structures.TMDBMediaClassification{}, fmt.Errorf("invalid endpoint: %w", err)
+	}
+
+	q := u.Query()
+	q.Set("api_key", t.apiKey)
+	q.Set("append_to_response", appendToResponse)
+	u.RawQuery = q.Encode()
+
+	ctx, cancel := context.WithTimeout(context.Background(), t.client.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return structures.TMDBMediaClassification{}, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return structures.TMDBMediaClassification{}, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return structures.TMDBMediaClassification{}, fmt.Errorf("API returned status %d: %s", resp.StatusCode, resp.Status)
+	}
+
+	var raw classificationResponse
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return structures.TMDBMediaClassification{}, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	result := structures.TMDBMediaClassification{
+		ID:               raw.ID,
+		MediaType:        mediaType,
+		Title:            raw.Title,
+		PosterPath:       raw.PosterPath,
+		Genres:           raw.Genres,
+		OriginalLanguage: raw.OriginalLanguage,
+		Keywords:         raw.Keywords.Keywords,
+	}
+
+	if mediaType == "tv" {
+		result.Title = raw.Name
+		result.Keywords = raw.Keywords.Results
+		for _, rating := range raw.ContentRatings.Results {
+			if rating.ISO3166_1 == "US" {
+				result.Certification = rating.Rating
+				break
+			}
+		}
+	} else {
+		for _, country := range raw.ReleaseDates.Results {
+			if country.ISO3166_1 != "US" {
+				continue
+			}
+			for _, release := range country.ReleaseDates {
+				if release.Certification != "" {
+					result.Certification = release.Certification
+					break
+				}
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// GetTVDetails fetches a TV show's details, including its list of seasons
+func (t *tmdbService) GetTVDetails(seriesID string) (structures.TVDetails, error) {
+	u, err := url.Parse(t.baseURL + "/tv/" + seriesID)
+	if err != nil {
+		return structures.TVDetails{}, fmt.Errorf("invalid endpoint: %w", err)
+	}
+
+	q := u.Query()
+	q.Set("api_key", t.apiKey)
+	q.Set("language", "en-US")
+	u.RawQuery = q.Encode()
+
+	ctx, cancel := context.WithTimeout(context.Background(), t.client.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return structures.TVDetails{}, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return structures.TVDetails{}, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return structures.TVDetails{}, fmt.Errorf("API returned status %d: %s", resp.StatusCode, resp.Status)
+	}
+
+	var result structures.TVDetails
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return structures.TVDetails{}, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return result, nil
+}
+
+// FindByExternalID looks up movies and TV shows by an id from another
+// database. Source is the TMDB external source name, such as imdb_id or tvdb_id.
+func (t *tmdbService) FindByExternalID(externalID, source string) (structures.TMDBFindResponse, error) {
+	u, err := url.Parse(t.baseURL + "/find/" + url.PathEscape(externalID))
+	if err != nil {
+		return structures.TMDBFindResponse{}, fmt.Errorf("invalid endpoint: %w", err)
+	}
+
+	q := u.Query()
+	q.Set("api_key", t.apiKey)
+	q.Set("external_source", source)
+	q.Set("language", "en-US")
+	u.RawQuery = q.Encode()
+
+	ctx, cancel := context.WithTimeout(context.Background(), t.client.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return structures.TMDBFindResponse{}, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return structures.TMDBFindResponse{}, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return structures.TMDBFindResponse{}, fmt.Errorf("API returned status %d: %s", resp.StatusCode, resp.Status)
+	}
+
+	var result structures.TMDBFindResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return structures.TMDBFindResponse{}, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return result, nil
+}
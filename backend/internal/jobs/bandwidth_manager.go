@@ -0,0 +1,351 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/mahcks/serra/internal/db/repository"
+	"github.com/mahcks/serra/internal/global"
+	"github.com/mahcks/serra/internal/integrations"
+	"github.com/mahcks/serra/internal/websocket"
+	"github.com/mahcks/serra/pkg/downloadclient"
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+// CurrentBandwidthState returns the bandwidth limits the bandwidth manager last
+// applied, on whichever instance leads
+func CurrentBandwidthState(ctx context.Context, query *repository.Queries) structures.BandwidthState {
+	state := structures.BandwidthState{Source: structures.BandwidthSourceDisabled, Clients: []structures.BandwidthClientState{}}
+	if _, err := loadSharedState(ctx, query, sharedStateBandwidth, &state); err != nil {
+		slog.Error("Failed to load bandwidth state", "error", err)
+	}
+	return state
+}
+
+// BandwidthManager job applies the bandwidth schedule to every download client,
+// throttling them while people stream remotely
+type BandwidthManager struct {
+	*BaseJob
+	gctx          global.Context
+	integrations  *integrations.Integration
+	clientManager *integrations.DownloadClientManager
+
+	// Limits last applied to the clients, nil while Serra doesn't manage them
+	applied *downloadclient.RateLimits
+	failed  bool
+	state   structures.BandwidthState
+
+	stopWatching func()
+}
+
+// NewBandwidthManager creates a new bandwidth manager job
+func NewBandwidthManager(gctx global.Context, integration *integrations.Integration, config JobConfig) (Job, error) {
+	baseJob := NewBaseJob(gctx, structures.JobBandwidthManager, config)
+
+	job := &BandwidthManager{
+		BaseJob:       baseJob,
+		gctx:          gctx,
+		integrations:  integration,
+		clientManager: integrations.NewDownloadClientManager(),
+	}
+
+	clients, err := gctx.Crate().Sqlite.Query().GetDownloadClients(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	// Clients that can't be reached are reported in the state instead of
+	// keeping the job from starting
+	if err := job.clientManager.InitializeClients(clients); err != nil {
+		slog.Warn("Failed to initialize download clients for bandwidth manager", "error", err)
+	}
+
+	return job, nil
+}
+
+// Name returns the job name
+func (j *BandwidthManager) Name() structures.Job {
+	return structures.JobBandwidthManager
+}
+
+// Trigger works out the limits that apply now and sets them on the clients when they changed
+func (j *BandwidthManager) Trigger(ctx context.Context) error {
+	schedule := BandwidthSchedule(ctx, j.gctx.Crate().Sqlite.Query())
+	now := time.Now()
+
+	state := structures.BandwidthState{
+		Enabled:   schedule.Enabled,
+		Source:    structures.BandwidthSourceDisabled,
+		Clients:   []structures.BandwidthClientState{},
+		UpdatedAt: now,
+	}
+
+	if !schedule.Enabled {
+		// Remove the limits Serra set so the clients are back to unlimited
+		if j.applied != nil {
+			j.apply(ctx, downloadclient.RateLimits{})
+			j.applied = nil
+		}
+		j.publish(ctx, state)
+		j.SetRunSummary(map[string]interface{}{
+			"enabled": false,
+		})
+		return nil
+	}
+
+	if schedule.StreamThrottle.Enabled {
+		sessions, err := j.integrations.Jellystat.GetActiveSessions()
+		if err != nil {
+			slog.Warn("Failed to get active streams, not throttling for them", "error", err)
+		}
+		for _, session := range sessions {
+			if session.IsRemote && !session.IsPaused {
+				state.RemoteStreams++
+			}
+		}
+	}
+
+	state.Limits, state.Source = bandwidthLimits(schedule, state.RemoteStreams, now)
+	limits := downloadclient.RateLimits{
+		Download: int64(state.Limits.DownloadKBps) * 1024,
+		Upload:   int64(state.Limits.UploadKBps) * 1024,
+	}
+
+	if j.applied == nil || *j.applied != limits || j.failed {
+		state.Clients = j.apply(ctx, limits)
+		j.applied = &limits
+	} else {
+		state.Clients = j.state.Clients
+	}
+
+	j.publish(ctx, state)
+
+	j.SetRunSummary(map[string]interface{}{
+		"enabled":        true,
+		"source":         state.Source,
+		"download_kbps":  state.Limits.DownloadKBps,
+		"upload_kbps":    state.Limits.UploadKBps,
+		"remote_streams": state.RemoteStreams,
+		"clients":        len(state.Clients),
+	})
+
+	return nil
+}
+
+// apply sets the limits on every client and reports the outcome per client
+func (j *BandwidthManager) apply(ctx context.Context, limits downloadclient.RateLimits) []structures.BandwidthClientState {
+	results := j.clientManager.SetRateLimits(ctx, limits)
+
+	j.failed = false
+	clients := make([]structures.BandwidthClientState, 0, len(results))
+	for clientID, err := range results {
+		clientState := structures.BandwidthClientState{ID: clientID, Applied: err == nil}
+		if client, ok := j.clientManager.GetClient(clientID); ok {
+			clientState.Name = client.GetName()
+			clientState.Type = client.GetType()
+		}
+		if err != nil {
+			j.failed = true
+			clientState.Error = err.Error()
+			slog.Error("Failed to set download client rate limits", "client", clientState.Name, "error", err)
+		}
+		clients = append(clients, clientState)
+	}
+	sort.Slice(clients, func(a, b int) bool { return clients[a].Name < clients[b].Name })
+
+	slog.Info("Applied download client bandwidth limits", "download", limits.Download, "upload", limits.Upload, "clients", len(clients))
+	return clients
+}
+
+// publish stores the state for every instance and sends it over the websocket
+// when it changed
+func (j *BandwidthManager) publish(ctx context.Context, state structures.BandwidthState) {
+	previous := j.state
+	j.state = state
+	if err := storeSharedState(ctx, j.gctx.Crate().Sqlite.Query(), sharedStateBandwidth, state); err != nil {
+		slog.Error("Failed to store bandwidth state", "error", err)
+	}
+
+	previous.UpdatedAt = state.UpdatedAt
+	if !reflect.DeepEqual(previous, state) {
+		websocket.BroadcastBandwidthState(state)
+	}
+}
+
+// Start initializes the job
+func (j *BandwidthManager) Start(ctx context.Context) error {
+	slog.Info("Starting bandwidth manager job", "interval", j.Config().Interval)
+	j.stopWatching = websocket.OnSubscribe(structures.TopicBandwidth, func(client websocket.ConnectedClient, topic structures.Topic) {
+		state := CurrentBandwidthState(context.Background(), j.gctx.Crate().Sqlite.Query())
+		websocket.PublishToUser(client.UserID, topic, structures.OpcodeBandwidthState, state)
+	})
+	return j.BaseJob.Start(ctx)
+}
+
+// Stop cleans up the job
+func (j *BandwidthManager) Stop(ctx context.Context) error {
+	slog.Info("Bandwidth manager job stopped")
+	if j.stopWatching != nil {
+		j.stopWatching()
+	}
+	if err := j.clientManager.CloseAll(ctx); err != nil {
+		slog.Warn("Failed to close download clients", "error", err)
+	}
+	return j.BaseJob.Stop(ctx)
+}
+
+// Health returns the job health status
+func (j *BandwidthManager) Health() error {
+	return nil
+}
+
+// ParseBandwidthSchedule parses and validates the bandwidth_schedule setting
+func ParseBandwidthSchedule(value string) (structures.BandwidthSchedule, error) {
+	var schedule structures.BandwidthSchedule
+	if value == "" {
+		return schedule, nil
+	}
+	if err := json.Unmarshal([]byte(value), &schedule); err != nil {
+		return schedule, fmt.Errorf("invalid bandwidth schedule: %w", err)
+	}
+
+	if schedule.Timezone != "" {
+		if _, err := time.LoadLocation(schedule.Timezone); err != nil {
+			return schedule, fmt.Errorf("invalid bandwidth schedule timezone: %s", schedule.Timezone)
+		}
+	}
+	if err := validBandwidthLimits(schedule.Default); err != nil {
+		return schedule, err
+	}
+	for i, window := range schedule.Windows {
+		if _, err := clockMinutes(window.Start); err != nil {
+			return schedule, fmt.Errorf("invalid start of bandwidth window %d: %w", i+1, err)
+		}
+		if _, err := clockMinutes(window.End); err != nil {
+			return schedule, fmt.Errorf("invalid end of bandwidth window %d: %w", i+1, err)
+		}
+		for _, day := range window.Days {
+			if day < time.Sunday || day > time.Saturday {
+				return schedule, fmt.Errorf("invalid day in bandwidth window %d: %d", i+1, day)
+			}
+		}
+		if err := validBandwidthLimits(window.BandwidthLimits); err != nil {
+			return schedule, err
+		}
+	}
+	if schedule.StreamThrottle.Streams < 0 {
+		return schedule, fmt.Errorf("invalid bandwidth schedule: streams can't be negative")
+	}
+	if err := validBandwidthLimits(schedule.StreamThrottle.BandwidthLimits); err != nil {
+		return schedule, err
+	}
+
+	return schedule, nil
+}
+
+// BandwidthSchedule returns the configured bandwidth schedule, disabled when
+// unset or invalid
+func BandwidthSchedule(ctx context.Context, query *repository.Queries) structures.BandwidthSchedule {
+	value, err := query.GetSetting(ctx, structures.SettingBandwidthSchedule.String())
+	if err != nil {
+		return structures.BandwidthSchedule{}
+	}
+
+	schedule, err := ParseBandwidthSchedule(value)
+	if err != nil {
+		slog.Warn("Invalid bandwidth schedule, bandwidth isn't managed", "error", err)
+		return structures.BandwidthSchedule{}
+	}
+	return schedule
+}
+
+func validBandwidthLimits(limits structures.BandwidthLimits) error {
+	if limits.DownloadKBps < 0 || limits.UploadKBps < 0 {
+		return fmt.Errorf("invalid bandwidth schedule: limits can't be negative")
+	}
+	return nil
+}
+
+// bandwidthLimits returns the limits that apply at a time with the given
+// number of remote streams playing, and what decided them
+func bandwidthLimits(schedule structures.BandwidthSchedule, remoteStreams int, now time.Time) (structures.BandwidthLimits, structures.BandwidthSource) {
+	if schedule.Timezone != "" {
+		if location, err := time.LoadLocation(schedule.Timezone); err == nil {
+			now = now.In(location)
+		}
+	}
+
+	limits, source := schedule.Default, structures.BandwidthSourceDefault
+	for _, window := range schedule.Windows {
+		if inBandwidthWindow(window, now) {
+			limits, source = window.BandwidthLimits, structures.BandwidthSourceSchedule
+			break
+		}
+	}
+
+	throttle := schedule.StreamThrottle
+	if throttle.Enabled && remoteStreams > throttle.Streams {
+		limits = structures.BandwidthLimits{
+			DownloadKBps: stricterLimit(limits.DownloadKBps, throttle.DownloadKBps),
+			UploadKBps:   stricterLimit(limits.UploadKBps, throttle.UploadKBps),
+		}
+		source = structures.BandwidthSourceStreams
+	}
+
+	return limits, source
+}
+
+// inBandwidthWindow reports whether a time falls in a window. Windows that end
+// before they start run past midnight into the next day.
+func inBandwidthWindow(window structures.BandwidthWindow, now time.Time) bool {
+	start, err := clockMinutes(window.Start)
+	if err != nil {
+		return false
+	}
+	end, err := clockMinutes(window.End)
+	if err != nil {
+		return false
+	}
+
+	minute := now.Hour()*60 + now.Minute()
+	today := now.Weekday()
+	yesterday := (today + 6) % 7
+
+	onDay := func(day time.Weekday) bool {
+		return len(window.Days) == 0 || slices.Contains(window.Days, day)
+	}
+
+	switch {
+	case start == end:
+		return onDay(today)
+	case start < end:
+		return onDay(today) && minute >= start && minute < end
+	default:
+		return (onDay(today) && minute >= start) || (onDay(yesterday) && minute < end)
+	}
+}
+
+// clockMinutes parses "HH:MM" into minutes after midnight
+func clockMinutes(clock string) (int, error) {
+	parsed, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, fmt.Errorf("expected HH:MM, got %q", clock)
+	}
+	return parsed.Hour()*60 + parsed.Minute(), nil
+}
+
+// stricterLimit returns the lower of two limits, where 0 means unlimited
+func stricterLimit(a, b int) int {
+	if a == 0 {
+		return b
+	}
+	if b == 0 {
+		return a
+	}
+	return min(a, b)
+}
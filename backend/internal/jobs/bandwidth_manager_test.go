@@ -0,0 +1,364 @@
+package jobs
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	fastws "github.com/fasthttp/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/mahcks/serra/config"
+	"github.com/mahcks/serra/internal/db/dbtest"
+	"github.com/mahcks/serra/internal/db/repository"
+	"github.com/mahcks/serra/internal/global"
+	"github.com/mahcks/serra/internal/integrations"
+	"github.com/mahcks/serra/internal/integrations/jellystat"
+	"github.com/mahcks/serra/internal/services/auth"
+	"github.com/mahcks/serra/internal/websocket"
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+func TestParseBandwidthSchedule(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{"unset", "", false},
+		{"full schedule", `{"enabled": true, "timezone": "Europe/Berlin", "default": {"download_kbps": 1000},
+			"windows": [{"days": [1, 5], "start": "22:00", "end": "06:00", "upload_kbps": 50}],
+			"stream_throttle": {"enabled": true, "streams": 1, "download_kbps": 500}}`, false},
+		{"invalid JSON", `nope`, true},
+		{"unknown timezone", `{"timezone": "Mars/Olympus"}`, true},
+		{"negative default", `{"default": {"download_kbps": -1}}`, true},
+		{"invalid start", `{"windows": [{"start": "25:00", "end": "06:00"}]}`, true},
+		{"invalid end", `{"windows": [{"start": "22:00", "end": "6pm"}]}`, true},
+		{"invalid day", `{"windows": [{"days": [7], "start": "22:00", "end": "06:00"}]}`, true},
+		{"negative window limit", `{"windows": [{"start": "22:00", "end": "06:00", "upload_kbps": -5}]}`, true},
+		{"negative streams", `{"stream_throttle": {"streams": -1}}`, true},
+		{"negative throttle limit", `{"stream_throttle": {"download_kbps": -1}}`, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := ParseBandwidthSchedule(test.value); (err != nil) != test.wantErr {
+				t.Fatalf("ParseBandwidthSchedule(%s) error = %v, want error %v", test.value, err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestBandwidthLimits(t *testing.T) {
+	schedule := structures.BandwidthSchedule{
+		Enabled: true,
+		Default: structures.BandwidthLimits{DownloadKBps: 10000, UploadKBps: 1000},
+		Windows: []structures.BandwidthWindow{
+			// Weeknights past midnight
+			{Days: []time.Weekday{time.Monday, time.Tuesday}, Start: "22:00", End: "06:00", BandwidthLimits: structures.BandwidthLimits{DownloadKBps: 0, UploadKBps: 5000}},
+			// Weekday office hours
+			{Days: []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}, Start: "09:00", End: "17:00", BandwidthLimits: structures.BandwidthLimits{DownloadKBps: 2000, UploadKBps: 200}},
+		},
+		StreamThrottle: structures.BandwidthStreamThrottle{Enabled: true, Streams: 1, BandwidthLimits: structures.BandwidthLimits{DownloadKBps: 3000}},
+	}
+	// 2026-03-02 is a Monday
+	at := func(day int, clock string) time.Time {
+		parsed, _ := time.Parse("15:04", clock)
+		return time.Date(2026, 3, day, parsed.Hour(), parsed.Minute(), 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name       string
+		now        time.Time
+		streams    int
+		wantLimits structures.BandwidthLimits
+		wantSource structures.BandwidthSource
+	}{
+		{"outside every window", at(2, "08:00"), 0, schedule.Default, structures.BandwidthSourceDefault},
+		{"office hours", at(2, "09:00"), 0, structures.BandwidthLimits{DownloadKBps: 2000, UploadKBps: 200}, structures.BandwidthSourceSchedule},
+		{"window end is exclusive", at(2, "17:00"), 0, schedule.Default, structures.BandwidthSourceDefault},
+		{"night window", at(2, "23:30"), 0, structures.BandwidthLimits{UploadKBps: 5000}, structures.BandwidthSourceSchedule},
+		{"night window past midnight", at(3, "05:59"), 0, structures.BandwidthLimits{UploadKBps: 5000}, structures.BandwidthSourceSchedule},
+		{"night window the day after the last night", at(4, "05:00"), 0, structures.BandwidthLimits{UploadKBps: 5000}, structures.BandwidthSourceSchedule},
+		{"night window not on Wednesday", at(4, "23:00"), 0, schedule.Default, structures.BandwidthSourceDefault},
+		{"office hours not on Saturday", at(7, "10:00"), 0, schedule.Default, structures.BandwidthSourceDefault},
+		{"streams up to the threshold", at(2, "08:00"), 1, schedule.Default, structures.BandwidthSourceDefault},
+		{"streams over the threshold", at(2, "08:00"), 2, structures.BandwidthLimits{DownloadKBps: 3000, UploadKBps: 1000}, structures.BandwidthSourceStreams},
+		{"throttle keeps stricter window limits", at(2, "10:00"), 2, structures.BandwidthLimits{DownloadKBps: 2000, UploadKBps: 200}, structures.BandwidthSourceStreams},
+		{"throttle limits an unlimited window", at(2, "23:00"), 3, structures.BandwidthLimits{DownloadKBps: 3000, UploadKBps: 5000}, structures.BandwidthSourceStreams},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			limits, source := bandwidthLimits(schedule, test.streams, test.now)
+			if limits != test.wantLimits || source != test.wantSource {
+				t.Fatalf("got %+v from %s, want %+v from %s", limits, source, test.wantLimits, test.wantSource)
+			}
+		})
+	}
+}
+
+func TestBandwidthLimitsUseScheduleTimezone(t *testing.T) {
+	schedule := structures.BandwidthSchedule{
+		Timezone: "America/New_York",
+		Windows:  []structures.BandwidthWindow{{Start: "20:00", End: "23:00", BandwidthLimits: structures.BandwidthLimits{DownloadKBps: 100}}},
+	}
+
+	// 01:00 UTC is 20:00 the evening before in New York
+	now := time.Date(2026, 3, 3, 1, 0, 0, 0, time.UTC)
+	if _, source := bandwidthLimits(schedule, 0, now); source != structures.BandwidthSourceSchedule {
+		t.Fatalf("window in the schedule's timezone didn't apply, source %s", source)
+	}
+	schedule.Timezone = ""
+	if _, source := bandwidthLimits(schedule, 0, now); source != structures.BandwidthSourceDefault {
+		t.Fatalf("window applied in server time, source %s", source)
+	}
+}
+
+// rateLimitRecorder is a qBittorrent instance that records the rate limits set on it
+type rateLimitRecorder struct {
+	mu     sync.Mutex
+	limits map[string][]string // By endpoint, in order
+}
+
+func (r *rateLimitRecorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	switch req.URL.Path {
+	case "/api/v2/auth/login":
+		http.SetCookie(w, &http.Cookie{Name: "SID", Value: "session"})
+	case "/api/v2/transfer/setDownloadLimit", "/api/v2/transfer/setUploadLimit":
+		_ = req.ParseForm()
+		r.mu.Lock()
+		r.limits[req.URL.Path] = append(r.limits[req.URL.Path], req.PostForm.Get("limit"))
+		r.mu.Unlock()
+	case "/api/v2/auth/logout":
+	default:
+		http.NotFound(w, req)
+	}
+}
+
+// set returns the download and upload limits set on the client so far
+func (r *rateLimitRecorder) set() (download, upload []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.limits["/api/v2/transfer/setDownloadLimit"], r.limits["/api/v2/transfer/setUploadLimit"]
+}
+
+// streamingJellystat reports a fixed set of active sessions
+type streamingJellystat struct {
+	jellystat.Service
+	sessions []structures.JellystatSession
+}
+
+func (s *streamingJellystat) GetActiveSessions() ([]structures.JellystatSession, error) {
+	return s.sessions, nil
+}
+
+func TestBandwidthManagerAppliesSchedule(t *testing.T) {
+	gctx := global.New(context.Background(), &config.Bootstrap{}, "test", "")
+	gctx.Crate().Sqlite = dbtest.Service(t)
+	query := gctx.Crate().Sqlite.Query()
+	ctx := context.Background()
+
+	recorder := &rateLimitRecorder{limits: make(map[string][]string)}
+	server := httptest.NewServer(recorder)
+	defer server.Close()
+	parsed, _ := url.Parse(server.URL)
+	port, _ := strconv.Atoi(parsed.Port())
+	if _, err := gctx.Crate().Sqlite.DB().Exec(`INSERT INTO download_clients (id, type, name, host, port, username, password) VALUES ('qbit', 'qbittorrent', 'qBittorrent', ?, ?, 'admin', 'adminadmin')`, parsed.Hostname(), port); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+
+	streams := &streamingJellystat{}
+	job, err := NewBandwidthManager(gctx, &integrations.Integration{Jellystat: streams}, JobConfig{})
+	if err != nil {
+		t.Fatalf("new bandwidth manager: %v", err)
+	}
+	t.Cleanup(func() { _ = job.Stop(ctx) })
+
+	setSchedule := func(value string) {
+		t.Helper()
+		if err := query.UpsertSetting(ctx, repository.UpsertSettingParams{Key: structures.SettingBandwidthSchedule.String(), Value: value}); err != nil {
+			t.Fatalf("set schedule: %v", err)
+		}
+	}
+	trigger := func() structures.BandwidthState {
+		t.Helper()
+		if err := job.Trigger(ctx); err != nil {
+			t.Fatalf("trigger: %v", err)
+		}
+		return CurrentBandwidthState(ctx, query)
+	}
+
+	// Not managed, the clients are left alone
+	if state := trigger(); state.Enabled || state.Source != structures.BandwidthSourceDisabled {
+		t.Fatalf("state while disabled = %+v", state)
+	}
+	if download, upload := recorder.set(); len(download) != 0 || len(upload) != 0 {
+		t.Fatalf("limits set while disabled: %v %v", download, upload)
+	}
+
+	// An all day window applies whatever the time
+	setSchedule(`{"enabled": true, "windows": [{"start": "00:00", "end": "00:00", "download_kbps": 2000, "upload_kbps": 100}],
+		"stream_throttle": {"enabled": true, "streams": 0, "download_kbps": 500}}`)
+	state := trigger()
+	if state.Source != structures.BandwidthSourceSchedule || state.Limits.DownloadKBps != 2000 || state.Limits.UploadKBps != 100 {
+		t.Fatalf("state = %+v, want the window's limits", state)
+	}
+	if len(state.Clients) != 1 || !state.Clients[0].Applied || state.Clients[0].Name != "qBittorrent" {
+		t.Fatalf("clients = %+v, want qBittorrent applied", state.Clients)
+	}
+
+	// Unchanged limits aren't sent again
+	trigger()
+	if download, upload := recorder.set(); len(download) != 1 || download[0] != "2048000" || len(upload) != 1 || upload[0] != "102400" {
+		t.Fatalf("limits set = %v %v, want 2048000 and 102400 once", download, upload)
+	}
+
+	// Paused and local streams don't count
+	streams.sessions = []structures.JellystatSession{{IsRemote: true, IsPaused: true}, {IsRemote: false}}
+	if state := trigger(); state.RemoteStreams != 0 || state.Source != structures.BandwidthSourceSchedule {
+		t.Fatalf("state = %+v, want no remote streams", state)
+	}
+
+	// A remote stream throttles the clients
+	streams.sessions = append(streams.sessions, structures.JellystatSession{IsRemote: true})
+	state = trigger()
+	if state.RemoteStreams != 1 || state.Source != structures.BandwidthSourceStreams || state.Limits.DownloadKBps != 500 || state.Limits.UploadKBps != 100 {
+		t.Fatalf("state = %+v, want throttled for one stream", state)
+	}
+
+	// Every instance serves the state the leader applied
+	follower := global.New(context.Background(), &config.Bootstrap{}, "test", "")
+	follower.Crate().Sqlite = gctx.Crate().Sqlite
+	if served := CurrentBandwidthState(ctx, follower.Crate().Sqlite.Query()); served.Source != structures.BandwidthSourceStreams || served.Limits.DownloadKBps != 500 {
+		t.Fatalf("follower served %+v, want the leader's state", served)
+	}
+
+	// Disabling removes the limits Serra set
+	setSchedule(`{"enabled": false}`)
+	if state := trigger(); state.Source != structures.BandwidthSourceDisabled {
+		t.Fatalf("state = %+v, want disabled", state)
+	}
+	download, upload := recorder.set()
+	if want := []string{"2048000", "512000", "0"}; !slices.Equal(download, want) {
+		t.Fatalf("download limits set = %v, want %v", download, want)
+	}
+	if want := []string{"102400", "102400", "0"}; !slices.Equal(upload, want) {
+		t.Fatalf("upload limits set = %v, want %v", upload, want)
+	}
+}
+
+// bandwidthWatcher is a websocket client collecting the bandwidth states it's sent
+type bandwidthWatcher struct {
+	states chan structures.BandwidthState
+}
+
+func watchBandwidth(t *testing.T, url string, authService auth.Authmen, userID string) *bandwidthWatcher {
+	t.Helper()
+
+	token, _, err := authService.CreateAccessToken(userID, userID, "", false)
+	if err != nil {
+		t.Fatalf("create token: %v", err)
+	}
+	conn, _, err := fastws.DefaultDialer.Dial(url, http.Header{"Cookie": {auth.CookieAuth + "=" + token}})
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	w := &bandwidthWatcher{states: make(chan structures.BandwidthState, 10)}
+	go func() {
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			msg, err := structures.ParseMessage(data)
+			if err != nil || msg.Op != structures.OpcodeBandwidthState {
+				continue
+			}
+			var state structures.BandwidthState
+			if err := decodeData(msg, &state); err == nil {
+				w.states <- state
+			}
+		}
+	}()
+	return w
+}
+
+// next waits for the next state, nil when none arrives
+func (w *bandwidthWatcher) next(timeout time.Duration) *structures.BandwidthState {
+	select {
+	case state := <-w.states:
+		return &state
+	case <-time.After(timeout):
+		return nil
+	}
+}
+
+func TestBandwidthStateSentOverWebsocket(t *testing.T) {
+	gctx := global.New(context.Background(), &config.Bootstrap{}, "test", "")
+	gctx.Crate().Sqlite = dbtest.Service(t)
+	gctx.Crate().AuthService = auth.New("test-secret", "localhost", false)
+	query := gctx.Crate().Sqlite.Query()
+	ctx := context.Background()
+
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	websocket.RegisterRoutes(gctx, app)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	go app.Listener(listener)
+	// The connections close with the test, shutting the default manager down
+	// would leave it unusable for the other tests
+	t.Cleanup(func() { _ = app.Shutdown() })
+	url := "ws://" + listener.Addr().String() + "/ws"
+
+	job, err := NewBandwidthManager(gctx, &integrations.Integration{Jellystat: &streamingJellystat{}}, JobConfig{})
+	if err != nil {
+		t.Fatalf("new bandwidth manager: %v", err)
+	}
+	if err := job.Start(ctx); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	t.Cleanup(func() { _ = job.Stop(ctx) })
+
+	// Clients get the current state when they connect, bandwidth is a default topic
+	first := watchBandwidth(t, url, gctx.Crate().AuthService, "alice")
+	if state := first.next(2 * time.Second); state == nil {
+		t.Fatal("no state on connect")
+	}
+
+	if err := query.UpsertSetting(ctx, repository.UpsertSettingParams{
+		Key:   structures.SettingBandwidthSchedule.String(),
+		Value: `{"enabled": true, "default": {"download_kbps": 4321}}`,
+	}); err != nil {
+		t.Fatalf("set schedule: %v", err)
+	}
+	if err := job.Trigger(ctx); err != nil {
+		t.Fatalf("trigger: %v", err)
+	}
+	state := first.next(2 * time.Second)
+	if state == nil || !state.Enabled || state.Source != structures.BandwidthSourceDefault || state.Limits.DownloadKBps != 4321 {
+		t.Fatalf("state after the schedule changed = %+v", state)
+	}
+
+	// Nothing changed, nothing is sent
+	if err := job.Trigger(ctx); err != nil {
+		t.Fatalf("trigger: %v", err)
+	}
+	if state := first.next(200 * time.Millisecond); state != nil {
+		t.Fatalf("unchanged state sent again: %+v", state)
+	}
+
+	// A client connecting later gets the limits in effect
+	second := watchBandwidth(t, url, gctx.Crate().AuthService, "bob")
+	if state := second.next(2 * time.Second); state == nil || state.Limits.DownloadKBps != 4321 {
+		t.Fatalf("state on connect = %+v", state)
+	}
+}
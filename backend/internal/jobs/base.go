@@ -25,6 +25,10 @@ type BaseJob struct {
 	lastRun        int64 // unix nano
 	lastError      string
 	lastErrorTime  int64 // unix nano
+	nextRun        int64 // unix nano, set by the manager's scheduler
+	
+	// Summary of the current execution, consumed by the manager when the run is recorded
+	runSummary    map[string]interface{}
 	
 	mu            sync.RWMutex
 	stopChan      chan struct{}
@@ -188,9 +192,12 @@ func (b *BaseJob) Metrics() JobMetrics {
 		b.mu.RUnlock()
 	}
 	
-	// Calculate next run based on interval and last run
+	// Prefer the next run computed by the scheduler, falling back to interval and last run
 	config := b.Config()
-	if lastRunNano > 0 && config.Enabled {
+	if nextRunNano := atomic.LoadInt64(&b.nextRun); nextRunNano > 0 && config.Enabled {
+		nextRun := time.Unix(0, nextRunNano)
+		metrics.NextRun = &nextRun
+	} else if lastRunNano > 0 && config.Enabled && config.Schedule == "" {
 		nextRun := time.Unix(0, lastRunNano).Add(config.Interval)
 		metrics.NextRun = &nextRun
 	}
@@ -239,6 +246,31 @@ func (b *BaseJob) OnError(ctx context.Context, err error) {
 	b.setStatus(JobStatusError)
 }
 
+// SetNextRun records when the scheduler will next execute the job
+func (b *BaseJob) SetNextRun(next time.Time) {
+	if next.IsZero() {
+		atomic.StoreInt64(&b.nextRun, 0)
+		return
+	}
+	atomic.StoreInt64(&b.nextRun, next.UnixNano())
+}
+
+// SetRunSummary records a job-specific summary for the current execution
+func (b *BaseJob) SetRunSummary(summary map[string]interface{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.runSummary = summary
+}
+
+// RunSummary returns and clears the summary recorded for the current execution
+func (b *BaseJob) RunSummary() map[string]interface{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	summary := b.runSummary
+	b.runSummary = nil
+	return summary
+}
+
 // Context returns the global context
 func (b *BaseJob) Context() global.Context {
 	return b.gctx
@@ -0,0 +1,67 @@
+package jobs
+
+import (
+	"sync"
+	"time"
+)
+
+// fakeClock is a Clock that only moves when advanced. After fires once the
+// clock has been advanced past the deadline.
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []fakeWaiter
+}
+
+type fakeWaiter struct {
+	deadline time.Time
+	ch       chan time.Time
+}
+
+func newFakeClock(now time.Time) *fakeClock {
+	return &fakeClock{now: now}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+	deadline := c.now.Add(d)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+	c.waiters = append(c.waiters, fakeWaiter{deadline: deadline, ch: ch})
+	return ch
+}
+
+// Advance moves the clock forward and fires the timers that are due
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+	pending := c.waiters[:0]
+	for _, waiter := range c.waiters {
+		if waiter.deadline.After(c.now) {
+			pending = append(pending, waiter)
+			continue
+		}
+		waiter.ch <- c.now
+	}
+	c.waiters = pending
+}
+
+// waiting returns the number of pending After timers
+func (c *fakeClock) waiting() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.waiters)
+}
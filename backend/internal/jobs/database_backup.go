@@ -0,0 +1,82 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/mahcks/serra/internal/global"
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+// DatabaseBackup job takes an online backup of the database and prunes old backups
+type DatabaseBackup struct {
+	*BaseJob
+	gctx global.Context
+}
+
+// NewDatabaseBackup creates a new database backup job
+func NewDatabaseBackup(gctx global.Context, config JobConfig) (Job, error) {
+	baseJob := NewBaseJob(gctx, structures.JobDatabaseBackup, config)
+
+	return &DatabaseBackup{
+		BaseJob: baseJob,
+		gctx:    gctx,
+	}, nil
+}
+
+// Name returns the job name
+func (j *DatabaseBackup) Name() structures.Job {
+	return structures.JobDatabaseBackup
+}
+
+// Trigger executes the database backup task
+func (j *DatabaseBackup) Trigger(ctx context.Context) error {
+	backups := j.gctx.Crate().Backup
+	if backups == nil {
+		return errors.New("backup service not initialized")
+	}
+
+	slog.Info("Starting database backup job", "directory", backups.Directory())
+
+	backup, err := backups.Create(ctx, structures.BackupKindScheduled)
+	if err != nil {
+		slog.Error("Failed to back up database", "error", err)
+		return err
+	}
+
+	retention := backups.Retention(ctx)
+	removed, err := backups.Prune(retention)
+	if err != nil {
+		// The backup itself succeeded, so pruning problems are only logged
+		slog.Warn("Failed to prune old database backups", "error", err)
+	}
+
+	j.SetRunSummary(map[string]interface{}{
+		"backup":    backup.Name,
+		"size":      backup.Size,
+		"retention": retention,
+		"pruned":    removed,
+	})
+
+	slog.Info("Database backup completed successfully", "name", backup.Name, "pruned", removed)
+
+	return nil
+}
+
+// Start initializes the job
+func (j *DatabaseBackup) Start(ctx context.Context) error {
+	slog.Info("Database backup job started")
+	return nil
+}
+
+// Stop cleans up the job
+func (j *DatabaseBackup) Stop(ctx context.Context) error {
+	slog.Info("Database backup job stopped")
+	return nil
+}
+
+// Health returns the job health status
+func (j *DatabaseBackup) Health() error {
+	return nil // Simple job, always healthy if running
+}
@@ -0,0 +1,86 @@
+package jobs
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/mahcks/serra/config"
+	"github.com/mahcks/serra/internal/db/dbtest"
+	"github.com/mahcks/serra/internal/global"
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+func TestCleanupKeepsDownloadsOfFailedSources(t *testing.T) {
+	gctx := global.New(context.Background(), &config.Bootstrap{}, "test", "")
+	gctx.Crate().Sqlite = dbtest.Service(t)
+	db := gctx.Crate().Sqlite.DB()
+	if _, err := db.Exec(`INSERT INTO downloads (id, title, torrent_title, source, progress, status, match_confidence) VALUES
+		('radarr-a_1', 'Failed queue', 'Failed.Queue', 'radarr', 40, 'downloading', 'high'),
+		('radarr-b_2', 'Gone', 'Gone', 'radarr', 100, 'importing', 'high'),
+		('radarr-b_3', 'Active', 'Active', 'radarr', 10, 'downloading', 'high'),
+		('qbit_abc_4', 'Title match', 'Title.Match', 'radarr', 20, 'downloading', 'low'),
+		('sonarr-a_5', 'Show', 'Show', 'sonarr', 30, 'downloading', 'high')`); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+
+	stored := func() []string {
+		t.Helper()
+		downloads, err := gctx.Crate().Sqlite.Query().ListDownloads(context.Background())
+		if err != nil {
+			t.Fatalf("list downloads: %v", err)
+		}
+		var ids []string
+		for _, download := range downloads {
+			ids = append(ids, download.ID)
+		}
+		slices.Sort(ids)
+		return ids
+	}
+	history := func() []string {
+		t.Helper()
+		rows, err := db.Query(`SELECT download_id FROM download_history ORDER BY download_id`)
+		if err != nil {
+			t.Fatalf("query history: %v", err)
+		}
+		defer rows.Close()
+		var ids []string
+		for rows.Next() {
+			var id string
+			if err := rows.Scan(&id); err != nil {
+				t.Fatal(err)
+			}
+			ids = append(ids, id)
+		}
+		if err := rows.Err(); err != nil {
+			t.Fatalf("read history: %v", err)
+		}
+		return ids
+	}
+
+	poller := &DownloadPoller{BaseJob: NewBaseJob(gctx, structures.JobDownloadPoller, JobConfig{})}
+	active := []Download{{ID: "radarr-b_3"}}
+
+	// The queue of radarr-a and the Sonarr instances couldn't be fetched
+	var failures pollFailures
+	failures.addService("radarr-a")
+	failures.addSource("sonarr")
+	poller.cleanupCompletedDownloads(active, failures)
+
+	if got, want := stored(), []string{"qbit_abc_4", "radarr-a_1", "radarr-b_3", "sonarr-a_5"}; !slices.Equal(got, want) {
+		t.Fatalf("stored downloads = %v, want %v", got, want)
+	}
+	if got := history(); !slices.Equal(got, []string{"radarr-b_2"}) {
+		t.Fatalf("history = %v, want only the download that left the queue", got)
+	}
+
+	// Every source answers again and the downloads are still there
+	active = append(active, Download{ID: "radarr-a_1"}, Download{ID: "qbit_abc_4"}, Download{ID: "sonarr-a_5"})
+	poller.cleanupCompletedDownloads(active, pollFailures{})
+	if got := history(); !slices.Equal(got, []string{"radarr-b_2"}) {
+		t.Fatalf("history = %v, downloads that never left were recorded", got)
+	}
+	if got := stored(); len(got) != 4 {
+		t.Fatalf("stored downloads = %v", got)
+	}
+}
@@ -0,0 +1,93 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mahcks/serra/internal/db/repository"
+	"github.com/mahcks/serra/pkg/structures"
+	"github.com/mahcks/serra/utils"
+)
+
+// DefaultDownloadHistoryRetentionDays is how many days finished downloads are kept in the history
+const DefaultDownloadHistoryRetentionDays = 90
+
+// DownloadHistoryRetentionDays returns how many days of download history to
+// keep, 0 when it is kept forever
+func DownloadHistoryRetentionDays(ctx context.Context, query *repository.Queries) int {
+	value, err := query.GetSetting(ctx, structures.SettingDownloadHistoryRetentionDays.String())
+	if err != nil || value == "" {
+		return DefaultDownloadHistoryRetentionDays
+	}
+
+	days, err := strconv.Atoi(value)
+	if err != nil || days < 0 {
+		slog.Warn("Invalid download history retention setting, using default", "value", value)
+		return DefaultDownloadHistoryRetentionDays
+	}
+
+	return days
+}
+
+// DownloadHistoryEntry converts a stored history row for the API
+func DownloadHistoryEntry(row repository.DownloadHistory) structures.DownloadHistoryEntry {
+	entry := structures.DownloadHistoryEntry{
+		ID:          row.ID,
+		DownloadID:  row.DownloadID,
+		Title:       row.Title,
+		ReleaseName: row.ReleaseName,
+		Source:      row.Source,
+		Client:      row.Client.String,
+		StartedAt:   row.StartedAt,
+		FinishedAt:  row.FinishedAt,
+		Outcome:     structures.DownloadOutcome(row.Outcome),
+	}
+	if row.RequestID.Valid {
+		entry.RequestID = utils.PtrInt64(row.RequestID.Int64)
+	}
+	if row.TmdbID.Valid {
+		entry.TmdbID = utils.PtrInt64(row.TmdbID.Int64)
+	}
+	if row.SizeBytes.Valid {
+		entry.SizeBytes = utils.PtrInt64(row.SizeBytes.Int64)
+	}
+	if row.AverageSpeed.Valid {
+		entry.AverageSpeed = utils.PtrInt64(row.AverageSpeed.Int64)
+	}
+	if row.Episodes.Valid && row.Episodes.String != "" {
+		_ = json.Unmarshal([]byte(row.Episodes.String), &entry.Episodes)
+	}
+	return entry
+}
+
+// downloadOutcome works out how a download left the queue from the last
+// status and progress seen for it
+func downloadOutcome(status string, progress float64) structures.DownloadOutcome {
+	status = strings.ToLower(status)
+	switch {
+	case strings.Contains(status, "fail"), strings.Contains(status, "error"), strings.Contains(status, "warning"):
+		return structures.DownloadOutcomeFailed
+	case progress >= 100, status == "completed", status == "importing", status == "imported", status == "seeding":
+		return structures.DownloadOutcomeImported
+	default:
+		return structures.DownloadOutcomeRemoved
+	}
+}
+
+// averageSpeed returns the bytes per second a download averaged between start
+// and finish, nil when it can't be worked out
+func averageSpeed(sizeBytes int64, progress float64, startedAt, finishedAt time.Time) *int64 {
+	seconds := finishedAt.Sub(startedAt).Seconds()
+	if sizeBytes <= 0 || progress <= 0 || seconds < 1 {
+		return nil
+	}
+	if progress > 100 {
+		progress = 100
+	}
+	downloaded := float64(sizeBytes) * progress / 100
+	return utils.PtrInt64(int64(downloaded / seconds))
+}
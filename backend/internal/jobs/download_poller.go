@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"github.com/mahcks/serra/internal/db/repository"
+	"github.com/mahcks/serra/internal/eventbus"
 	"github.com/mahcks/serra/internal/global"
 	"github.com/mahcks/serra/internal/integrations"
 	"github.com/mahcks/serra/internal/websocket"
@@ -41,10 +42,21 @@ var (
 	}
 )
 
+// maxIdlePollInterval is how far the poller backs off while nobody watches downloads
+const maxIdlePollInterval = 5 * time.Minute
+
+// maxTrackedPollInterval caps the back-off while downloads are in the queue, so
+// stalls are caught and finished downloads reach the history without waiting
+// for someone to watch
+const maxTrackedPollInterval = time.Minute
+
+// busTopicDownloadsWake is published on by followers when someone starts
+// watching downloads, so the leader polls right away
+const busTopicDownloadsWake = "jobs.downloads.wake"
+
 // DownloadPoller is a refactored version that uses the new client architecture
 type DownloadPoller struct {
 	*BaseJob
-	ticker        *time.Ticker
 	clientManager *integrations.DownloadClientManager
 
 	// Metrics
@@ -70,10 +82,19 @@ type DownloadPoller struct {
 	}
 	cacheMutex sync.RWMutex
 
-	// Adaptive polling
-	lastDownloadCount int
-	pollInterval      time.Duration
-	baseInterval      time.Duration
+	// Adaptive polling: every run while websocket clients watch downloads,
+	// backing off up to maxIdlePollInterval while nobody does
+	pollMutex        sync.Mutex
+	idleInterval     time.Duration
+	trackedDownloads int // Found by the last poll
+	stopWatching     []func()
+
+	// Reports whether this instance leads and so polls, set by the manager
+	leading func() bool
+
+	// Progress of the active downloads, sent to clients when they subscribe
+	snapshot      []structures.DownloadProgressPayload
+	snapshotMutex sync.RWMutex
 
 	// Circuit breakers
 	circuitBreakers map[string]*circuitBreaker
@@ -81,10 +102,13 @@ type DownloadPoller struct {
 
 	lastCleanupTime  time.Time // For improved cleanup scheduling
 	lastCacheCleanup time.Time // For cache cleanup scheduling
-	
+
 	// Track download states for completion events
 	lastDownloadStates map[string]string
 	statesMutex        sync.RWMutex
+
+	// Stall detection across polls
+	stalls *stallDetector
 }
 
 // Download represents a download item for internal use
@@ -94,10 +118,59 @@ type Download struct {
 	TorrentTitle string
 	Source       string
 	TmdbID       *int64
+	SeasonNumber *int64
 	Progress     float64
 	TimeLeft     *string
 	Status       *string
 	Hash         *string
+	Client       string
+	SizeBytes    int64
+
+	// Linking to the requested media
+	RequestID       *int64
+	Episodes        structures.RequestedEpisodes
+	MatchConfidence structures.DownloadMatchConfidence
+
+	// Stall detection and remediation
+	ClientItem   *downloadclient.Item // The download client's view of the download, nil when no client has it
+	ArrServiceID string               // The Radarr/Sonarr instance queueing or, when matched by title, knowing the download
+	QueueIDs     []int                // Its Radarr/Sonarr queue item ids
+	QueueFailure string               // Why Radarr/Sonarr consider the download failed
+	Stall        structures.DownloadStallReason
+}
+
+// addEpisode records an episode as part of the download. SeasonNumber is set
+// while every episode is from the same season.
+func (d *Download) addEpisode(season, episode int) {
+	if d.Episodes == nil {
+		d.Episodes = structures.RequestedEpisodes{}
+	}
+	d.Episodes[season] = append(d.Episodes[season], episode)
+	d.Episodes = d.Episodes.Normalize()
+
+	d.SeasonNumber = nil
+	if seasons := d.Episodes.Seasons(); len(seasons) == 1 {
+		d.SeasonNumber = utils.PtrInt64(int64(seasons[0]))
+	}
+}
+
+// packTitle returns the display title of a download holding several episodes
+func (d *Download) packTitle(seriesTitle string) string {
+	if d.SeasonNumber != nil {
+		return fmt.Sprintf("%s Season %d", seriesTitle, *d.SeasonNumber)
+	}
+	return fmt.Sprintf("%s Season Pack", seriesTitle)
+}
+
+// seasons returns the seasons the download has episodes of
+func (d *Download) seasons() []int {
+	if len(d.Episodes) > 0 {
+		return d.Episodes.Seasons()
+	}
+	if d.SeasonNumber != nil {
+		return []int{int(*d.SeasonNumber)}
+	}
+	return nil
 }
 
 // sonarrEpisode represents episode data from Sonarr
@@ -137,7 +210,6 @@ func NewDownloadPoller(gctx global.Context, config JobConfig) (*DownloadPoller,
 	base := NewBaseJob(gctx, structures.JobDownloadPoller, config)
 	dp := &DownloadPoller{
 		BaseJob:       base,
-		ticker:        time.NewTicker(config.Interval),
 		clientManager: integrations.NewDownloadClientManager(),
 		radarrCache: make(map[string]struct {
 			Movies map[int]struct {
@@ -158,6 +230,7 @@ func NewDownloadPoller(gctx global.Context, config JobConfig) (*DownloadPoller,
 		lastCleanupTime:    time.Now(),
 		lastCacheCleanup:   time.Now(),
 		lastDownloadStates: make(map[string]string),
+		stalls:             newStallDetector(),
 	}
 
 	// Initialize download clients
@@ -168,14 +241,116 @@ func NewDownloadPoller(gctx global.Context, config JobConfig) (*DownloadPoller,
 	return dp, nil
 }
 
-// Trigger executes the download polling task
+// Trigger polls the download clients when a poll is due. While nobody watches
+// downloads over the websocket most runs are skipped.
 func (dp *DownloadPoller) Trigger(ctx context.Context) error {
+	dp.pollMutex.Lock()
+	defer dp.pollMutex.Unlock()
+
+	watchers := websocket.DownloadWatchers()
+	if !dp.pollDue(watchers, time.Now()) {
+		dp.SetRunSummary(map[string]interface{}{
+			"skipped":       true,
+			"watchers":      watchers,
+			"idle_interval": dp.idleInterval.String(),
+		})
+		return nil
+	}
+
 	return dp.pollCombined(ctx)
 }
 
+// pollDue reports whether the clients should be polled now. Someone watching
+// means every run polls, otherwise the interval doubles after each idle poll,
+// up to maxTrackedPollInterval while the last poll found downloads.
+func (dp *DownloadPoller) pollDue(watchers int, now time.Time) bool {
+	if watchers > 0 {
+		dp.idleInterval = 0
+		return true
+	}
+
+	limit := maxIdlePollInterval
+	if dp.trackedDownloads > 0 {
+		limit = maxTrackedPollInterval
+	}
+
+	if dp.idleInterval == 0 {
+		dp.idleInterval = dp.Config().Interval
+	} else if now.Sub(dp.lastPollTime) < min(dp.idleInterval, limit) {
+		return false
+	} else {
+		dp.idleInterval = min(dp.idleInterval*2, limit)
+	}
+	return true
+}
+
+// wake polls right away when someone starts watching downloads, so they don't
+// wait out the idle back-off
+func (dp *DownloadPoller) wake(ctx context.Context) {
+	// A poll that is already running gets them fresh progress anyway
+	if !dp.pollMutex.TryLock() {
+		return
+	}
+	defer dp.pollMutex.Unlock()
+
+	dp.idleInterval = 0
+	if time.Since(dp.lastPollTime) < dp.Config().Interval {
+		return
+	}
+
+	// Followers leave polling to the leader, whose progress reaches their
+	// clients over the event bus
+	if !dp.isLeader() {
+		dp.wakeLeader(ctx)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, dp.Config().Timeout)
+	defer cancel()
+	if err := dp.pollCombined(ctx); err != nil {
+		slog.Warn("Failed to poll downloads for new watcher", "error", err)
+	}
+}
+
+// wakeLeader asks the leader to poll for a watcher connected to this instance
+func (dp *DownloadPoller) wakeLeader(ctx context.Context) {
+	bus := dp.Context().Crate().EventBus
+	if bus == nil {
+		return
+	}
+	if err := bus.Publish(ctx, busTopicDownloadsWake, nil); err != nil {
+		slog.Warn("Failed to ask the job leader to poll downloads", "error", err)
+	}
+}
+
+// SetLeadership sets how the poller finds out whether this instance leads
+func (dp *DownloadPoller) SetLeadership(leading func() bool) {
+	dp.leading = leading
+}
+
+// isLeader reports whether this instance polls downloads
+func (dp *DownloadPoller) isLeader() bool {
+	return dp.leading == nil || dp.leading()
+}
+
 // Start begins the download poller loop
 func (dp *DownloadPoller) Start(ctx context.Context) error {
 	slog.Info("Starting download poller", "interval", dp.Config().Interval)
+	watch := func(client websocket.ConnectedClient, topic structures.Topic) {
+		dp.sendSnapshot(client, topic)
+		dp.wake(ctx)
+	}
+	dp.stopWatching = []func(){
+		websocket.OnSubscribe(structures.TopicDownloads, watch),
+		websocket.OnSubscribe(structures.TopicRequest, watch),
+	}
+	if bus := dp.Context().Crate().EventBus; bus != nil {
+		dp.stopWatching = append(dp.stopWatching, bus.Subscribe(busTopicDownloadsWake, func(eventbus.Event) {
+			if dp.isLeader() {
+				dp.wake(ctx)
+			}
+		}))
+	}
 	return dp.BaseJob.Start(ctx)
 }
 
@@ -205,6 +380,9 @@ func (dp *DownloadPoller) initializeClients() error {
 
 // Stop stops the download poller
 func (dp *DownloadPoller) Stop(ctx context.Context) error {
+	for _, stop := range dp.stopWatching {
+		stop()
+	}
 	err := dp.BaseJob.Stop(ctx)
 	if clientErr := dp.clientManager.CloseAll(ctx); clientErr != nil {
 		slog.Error("Failed to close download clients", "error", clientErr)
@@ -240,41 +418,36 @@ func (dp *DownloadPoller) pollCombined(ctx context.Context) error {
 	}
 	var allEnrichedDownloads []Download
 
+	// Client downloads referenced by a Radarr/Sonarr queue item. The others are
+	// matched by title afterwards.
+	claimed := make(map[string]bool)
+
+	// Sources that couldn't be read, their downloads are left as they were
+	var failures pollFailures
+
 	// Process Radarr instances
 	radarrInstances, err := dp.Context().Crate().Sqlite.Query().GetArrServiceByType(ctx, "radarr")
 	if err != nil {
 		slog.Error("Failed to fetch Radarr instances", "error", err)
+		failures.addSource("radarr")
 	} else {
 		for _, radarr := range radarrInstances {
-			queue, err := fetchRadarrQueue(ctx, radarr.BaseUrl, radarr.ApiKey)
+			queue, err := fetchRadarrQueue(ctx, radarr.BaseUrl, radarr.ApiKey.String())
 			if err != nil {
 				slog.Error("Failed to fetch Radarr queue", "name", radarr.Name, "error", err)
+				failures.addService(radarr.ID)
 				continue
 			}
 
 			for _, item := range queue {
-				// Try to match to a download client item
-				var matched *downloadclient.Item
-				if item.DownloadID != "" {
-					// Try matching by hash first
-					if m, ok := downloadsByHash[strings.ToLower(item.DownloadID)]; ok {
-						matched = &m
-					}
-					// Try matching by download client ID (for SABnzbd NzbID)
-					if matched == nil {
-						if m, ok := downloadsByID[strings.ToLower(item.DownloadID)]; ok {
-							matched = &m
-						}
-					}
-				}
-				if matched == nil && item.Title != "" {
-					if m, ok := downloadsByName[strings.ToLower(item.Title)]; ok {
-						matched = &m
-					}
+				// Match to a download client item through the queue's download ID
+				matched, confidence := matchQueueItem(item.DownloadID, item.Title, downloadsByHash, downloadsByID, downloadsByName)
+				if matched != nil {
+					claimed[clientDownloadKey(*matched)] = true
 				}
 
 				// Fetch movie details
-				movie, err := fetchRadarrMovie(ctx, radarr.BaseUrl, radarr.ApiKey, item.MovieID)
+				movie, err := fetchRadarrMovie(ctx, radarr.BaseUrl, radarr.ApiKey.String(), item.MovieID)
 				if err != nil {
 					slog.Info("Failed to fetch Radarr movie details", "movieID", item.MovieID, "error", err)
 					continue
@@ -298,15 +471,22 @@ func (dp *DownloadPoller) pollCombined(ctx context.Context) error {
 
 				uniqueID := fmt.Sprintf("%s_%s", radarr.ID, item.DownloadID)
 				allEnrichedDownloads = append(allEnrichedDownloads, Download{
-					ID:           uniqueID,
-					Title:        movie.Title,
-					TorrentTitle: item.Title,
-					Source:       "radarr",
-					TmdbID:       utils.PtrInt64(int64(movie.TmdbID)),
-					Progress:     progress,
-					TimeLeft:     utils.PtrString(timeLeft),
-					Status:       utils.PtrString(status),
-					Hash:         utils.PtrString(hash),
+					ID:              uniqueID,
+					Title:           movie.Title,
+					TorrentTitle:    item.Title,
+					Source:          "radarr",
+					TmdbID:          utils.PtrInt64(int64(movie.TmdbID)),
+					Progress:        progress,
+					TimeLeft:        utils.PtrString(timeLeft),
+					Status:          utils.PtrString(status),
+					Hash:            utils.PtrString(hash),
+					Client:          item.DownloadClient,
+					SizeBytes:       item.Size,
+					MatchConfidence: confidence,
+					ClientItem:      matched,
+					ArrServiceID:    radarr.ID,
+					QueueIDs:        []int{item.ID},
+					QueueFailure:    arrQueueFailure(item.Status, item.TrackedDownloadStatus, item.ErrorMessage, item.StatusMessages),
 				})
 			}
 		}
@@ -316,45 +496,47 @@ func (dp *DownloadPoller) pollCombined(ctx context.Context) error {
 	sonarrInstances, err := dp.Context().Crate().Sqlite.Query().GetArrServiceByType(ctx, "sonarr")
 	if err != nil {
 		slog.Error("Failed to fetch Sonarr instances", "error", err)
+		failures.addSource("sonarr")
 	} else {
 		for _, sonarr := range sonarrInstances {
-			queue, err := fetchSonarrQueue(ctx, sonarr.BaseUrl, sonarr.ApiKey)
+			queue, err := fetchSonarrQueue(ctx, sonarr.BaseUrl, sonarr.ApiKey.String())
 			if err != nil {
 				slog.Error("Failed to fetch Sonarr queue", "name", sonarr.Name, "error", err)
+				failures.addService(sonarr.ID)
 				continue
 			}
 
+			// Sonarr lists every episode of a multi-episode release as its own
+			// queue item sharing one download ID, they become a single download
+			packs := make(map[string]int)
+			packTitles := make(map[string]string)
+
 			for _, item := range queue {
-				// Try to match to a download client item
-				var matched *downloadclient.Item
-				if item.DownloadID != "" {
-					// Try matching by hash first
-					if m, ok := downloadsByHash[strings.ToLower(item.DownloadID)]; ok {
-						matched = &m
-					}
-					// Try matching by download client ID (for SABnzbd NzbID)
-					if matched == nil {
-						if m, ok := downloadsByID[strings.ToLower(item.DownloadID)]; ok {
-							matched = &m
-						}
-					}
-				}
-				if matched == nil && item.Title != "" {
-					if m, ok := downloadsByName[strings.ToLower(item.Title)]; ok {
-						matched = &m
-					}
+				// Match to a download client item through the queue's download ID
+				matched, confidence := matchQueueItem(item.DownloadID, item.Title, downloadsByHash, downloadsByID, downloadsByName)
+				if matched != nil {
+					claimed[clientDownloadKey(*matched)] = true
 				}
 
-				// Fetch series/episode details
-				series, err := fetchSonarrSeries(ctx, sonarr.BaseUrl, sonarr.ApiKey, item.SeriesID)
+				episode, err := fetchSonarrEpisode(ctx, sonarr.BaseUrl, sonarr.ApiKey.String(), item.EpisodeID)
 				if err != nil {
-					slog.Info("Failed to fetch Sonarr series details", "seriesID", item.SeriesID, "error", err)
+					slog.Info("Failed to fetch Sonarr episode details", "episodeID", item.EpisodeID, "error", err)
 					continue
 				}
 
-				episode, err := fetchSonarrEpisode(ctx, sonarr.BaseUrl, sonarr.ApiKey, item.EpisodeID)
+				uniqueID := fmt.Sprintf("%s_%s", sonarr.ID, item.DownloadID)
+				if index, ok := packs[uniqueID]; ok && item.DownloadID != "" {
+					pack := &allEnrichedDownloads[index]
+					pack.addEpisode(episode.SeasonNumber, episode.EpisodeNumber)
+					pack.QueueIDs = append(pack.QueueIDs, item.ID)
+					pack.Title = pack.packTitle(packTitles[uniqueID])
+					continue
+				}
+
+				// Fetch series details
+				series, err := fetchSonarrSeries(ctx, sonarr.BaseUrl, sonarr.ApiKey.String(), item.SeriesID)
 				if err != nil {
-					slog.Info("Failed to fetch Sonarr episode details", "episodeID", item.EpisodeID, "error", err)
+					slog.Info("Failed to fetch Sonarr series details", "seriesID", item.SeriesID, "error", err)
 					continue
 				}
 
@@ -374,27 +556,52 @@ func (dp *DownloadPoller) pollCombined(ctx context.Context) error {
 					hash = matched.Hash
 				}
 
-				uniqueID := fmt.Sprintf("%s_%s", sonarr.ID, item.DownloadID)
-				var name string
+				download := Download{
+					ID:              uniqueID,
+					TorrentTitle:    item.Title,
+					Source:          "sonarr",
+					TmdbID:          utils.PtrInt64(int64(series.TmdbID)),
+					Progress:        progress,
+					TimeLeft:        utils.PtrString(timeLeft),
+					Status:          utils.PtrString(status),
+					Hash:            utils.PtrString(hash),
+					Client:          item.DownloadClient,
+					SizeBytes:       item.Size,
+					MatchConfidence: confidence,
+					ClientItem:      matched,
+					ArrServiceID:    sonarr.ID,
+					QueueIDs:        []int{item.ID},
+					QueueFailure:    arrQueueFailure(item.Status, item.TrackedDownloadStatus, item.ErrorMessage, item.StatusMessages),
+				}
 				if episode.SeasonNumber == 0 && episode.EpisodeNumber == 0 {
-					name = fmt.Sprintf("%s Season Pack", series.Title)
+					download.Title = fmt.Sprintf("%s Season Pack", series.Title)
 				} else {
-					name = fmt.Sprintf("%s S%dxE%d - %s", series.Title, episode.SeasonNumber, episode.EpisodeNumber, episode.Title)
+					download.Title = fmt.Sprintf("%s S%dxE%d - %s", series.Title, episode.SeasonNumber, episode.EpisodeNumber, episode.Title)
+					download.addEpisode(episode.SeasonNumber, episode.EpisodeNumber)
 				}
-				allEnrichedDownloads = append(allEnrichedDownloads, Download{
-					ID:           uniqueID,
-					Title:        name,
-					TorrentTitle: item.Title,
-					Source:       "sonarr",
-					TmdbID:       utils.PtrInt64(int64(series.TmdbID)),
-					Progress:     progress,
-					TimeLeft:     utils.PtrString(timeLeft),
-					Status:       utils.PtrString(status),
-					Hash:         utils.PtrString(hash),
-				})
+
+				packs[uniqueID] = len(allEnrichedDownloads)
+				packTitles[uniqueID] = series.Title
+				allEnrichedDownloads = append(allEnrichedDownloads, download)
 			}
 		}
 	}
+
+	// Fall back to fuzzy title matching for client downloads no queue item
+	// references. A queue that failed doesn't claim its downloads, which would
+	// then be matched by title as well.
+	var fallbackMatches []Download
+	if !failures.any() {
+		fallbackMatches = dp.matchUnclaimedDownloads(allClientDownloads, claimed, radarrInstances, sonarrInstances)
+		allEnrichedDownloads = append(allEnrichedDownloads, fallbackMatches...)
+	}
+
+	// Link every download to the request it fulfils
+	dp.linkRequests(ctx, allEnrichedDownloads, radarrInstances, sonarrInstances)
+
+	// Report stalled and failed downloads, remediating them when configured
+	stalled, remediated := dp.detectStalls(ctx, allEnrichedDownloads, radarrInstances, sonarrInstances)
+
 	// Store downloads in database
 	dp.storeDownloads(allEnrichedDownloads)
 
@@ -402,11 +609,12 @@ func (dp *DownloadPoller) pollCombined(ctx context.Context) error {
 	dp.broadcastDownloadUpdates(allEnrichedDownloads)
 
 	// Clean up completed downloads from database
-	dp.cleanupCompletedDownloads(allEnrichedDownloads)
+	dp.cleanupCompletedDownloads(allEnrichedDownloads, failures)
 
-	// Clean up old missing downloads every 25 minutes
+	// Clean up old missing downloads and prune the history every 25 minutes
 	if time.Since(dp.lastCleanupTime) > 25*time.Minute {
 		dp.cleanupOldMissingDownloads()
+		dp.pruneDownloadHistory()
 		dp.cleanupCount++
 		dp.lastCleanupTime = time.Now()
 	}
@@ -420,9 +628,18 @@ func (dp *DownloadPoller) pollCombined(ctx context.Context) error {
 	// Update metrics
 	dp.lastPollTime = time.Now()
 	dp.downloadsFound += int64(len(allEnrichedDownloads))
-
-	// Adaptive polling: adjust interval based on activity
-	dp.adjustPollInterval(len(allEnrichedDownloads))
+	dp.trackedDownloads = len(allEnrichedDownloads)
+
+	dp.SetRunSummary(map[string]interface{}{
+		"client_downloads":  len(allClientDownloads),
+		"tracked_downloads": len(allEnrichedDownloads),
+		"fallback_matches":  len(fallbackMatches),
+		"stalled_downloads": stalled,
+		"remediated":        remediated,
+		"radarr_instances":  len(radarrInstances),
+		"sonarr_instances":  len(sonarrInstances),
+		"failed_sources":    len(failures.services) + len(failures.sources),
+	})
 
 	// Get metrics from BaseJob
 	metrics := dp.Metrics()
@@ -434,13 +651,137 @@ func (dp *DownloadPoller) pollCombined(ctx context.Context) error {
 			"errors", metrics.ErrorCount,
 			"downloads_found", dp.downloadsFound,
 			"cleanups", dp.cleanupCount,
-			"poll_interval", dp.pollInterval,
+			"idle_interval", dp.idleInterval,
 			"last_poll", dp.lastPollTime.Format(time.RFC3339))
 	}
 
 	return nil
 }
 
+// matchQueueItem finds the client download a Radarr/Sonarr queue item refers
+// to. The queue's download ID (the torrent hash or SABnzbd NZO id) is tried
+// first, the release title only when that finds nothing.
+func matchQueueItem(downloadID, title string, byHash, byID, byName map[string]downloadclient.Item) (*downloadclient.Item, structures.DownloadMatchConfidence) {
+	if downloadID != "" {
+		if m, ok := byHash[strings.ToLower(downloadID)]; ok {
+			return &m, structures.DownloadMatchHigh
+		}
+		if m, ok := byID[strings.ToLower(downloadID)]; ok {
+			return &m, structures.DownloadMatchHigh
+		}
+	}
+	if title != "" {
+		if m, ok := byName[strings.ToLower(title)]; ok {
+			return &m, structures.DownloadMatchMedium
+		}
+	}
+	// Not in a download client (yet), the queue item itself still names the media
+	return nil, structures.DownloadMatchHigh
+}
+
+// clientDownloadKey identifies a download client item
+func clientDownloadKey(item downloadclient.Item) string {
+	return strings.ToLower(item.Hash) + "|" + strings.ToLower(item.ID)
+}
+
+// matchUnclaimedDownloads matches active client downloads that no Radarr or
+// Sonarr queue item references to movies and shows by title. Only downloads
+// matched to a TMDB id are returned, all with low confidence.
+func (dp *DownloadPoller) matchUnclaimedDownloads(clientDownloads []downloadclient.Item, claimed map[string]bool, radarrInstances, sonarrInstances []repository.ArrService) []Download {
+	var unclaimed []downloadclient.Item
+	for _, item := range clientDownloads {
+		// Finished torrents that are only seeding aren't of interest
+		if item.Progress < 100 && !claimed[clientDownloadKey(item)] {
+			unclaimed = append(unclaimed, item)
+		}
+	}
+	if len(unclaimed) == 0 {
+		return nil
+	}
+
+	// Load the libraries title matching compares against
+	for _, radarr := range radarrInstances {
+		if _, err := dp.getCachedRadarrData(radarr.ID, radarr.BaseUrl, radarr.ApiKey.String()); err != nil {
+			slog.Debug("Failed to load Radarr library for title matching", "name", radarr.Name, "error", err)
+		}
+	}
+	for _, sonarr := range sonarrInstances {
+		if _, _, err := dp.getCachedSonarrData(sonarr.ID, sonarr.BaseUrl, sonarr.ApiKey.String()); err != nil {
+			slog.Debug("Failed to load Sonarr library for title matching", "name", sonarr.Name, "error", err)
+		}
+	}
+
+	var matches []Download
+	for _, item := range unclaimed {
+		download := dp.enrichDownloadItem(item)
+		if download == nil || download.TmdbID == nil {
+			continue
+		}
+		download.MatchConfidence = structures.DownloadMatchLow
+		download.ClientItem = &item
+		matches = append(matches, *download)
+	}
+	return matches
+}
+
+// linkRequests sets the request each download fulfils: the newest request for
+// the same media and quality that wasn't denied and, for shows, covers a
+// season in the download. A download from a 4K instance only fulfils 4K
+// requests and the other way round.
+func (dp *DownloadPoller) linkRequests(ctx context.Context, downloads []Download, radarrInstances, sonarrInstances []repository.ArrService) {
+	is4k := make(map[string]bool)
+	for _, services := range [][]repository.ArrService{radarrInstances, sonarrInstances} {
+		for _, service := range services {
+			is4k[service.ID] = service.Is4k
+		}
+	}
+	requestsByMedia := make(map[string][]repository.Request)
+
+	for i := range downloads {
+		download := &downloads[i]
+		if download.TmdbID == nil {
+			continue
+		}
+
+		mediaType := "movie"
+		if download.Source == "sonarr" {
+			mediaType = "tv"
+		}
+
+		key := fmt.Sprintf("%s_%d", mediaType, *download.TmdbID)
+		requests, ok := requestsByMedia[key]
+		if !ok {
+			var err error
+			requests, err = dp.Context().Crate().Sqlite.Query().GetRequestsByTMDBIDAndMediaType(ctx, repository.GetRequestsByTMDBIDAndMediaTypeParams{
+				TmdbID:    sql.NullInt64{Int64: *download.TmdbID, Valid: true},
+				MediaType: mediaType,
+			})
+			if err != nil {
+				slog.Error("Failed to get requests for download", "error", err, "download_id", download.ID)
+				continue
+			}
+			requestsByMedia[key] = requests
+		}
+
+		var linked *repository.Request
+		for j := range requests {
+			request := &requests[j]
+			if request.Status == "denied" || request.Is4k != is4k[download.ArrServiceID] {
+				continue
+			}
+			if !coversSeasons(requestSeasons(request.Seasons, request.Episodes), download.seasons()) {
+				continue
+			}
+			if linked == nil || request.CreatedAt.After(linked.CreatedAt) {
+				linked = request
+			}
+		}
+		if linked != nil {
+			download.RequestID = &linked.ID
+		}
+	}
+}
+
 // enrichDownloads enriches download items with metadata from Radarr/Sonarr
 func (dp *DownloadPoller) enrichDownloads(downloads []downloadclient.Item) []Download {
 	var enrichedDownloads []Download
@@ -527,14 +868,12 @@ func (dp *DownloadPoller) determineSource(item downloadclient.Item) string {
 
 // matchWithRadarr attempts to match a download with Radarr movie data
 func (dp *DownloadPoller) matchWithRadarr(item downloadclient.Item) *Download {
-	cb := dp.getCircuitBreaker(fmt.Sprintf("radarr_cache_%s", item.ID))
-	if !cb.canExecute() {
-		return nil
-	}
 	dp.cacheMutex.RLock()
-	cached, exists := dp.radarrCache[item.ID]
-	dp.cacheMutex.RUnlock()
-	if exists && time.Since(cached.LastUpdated) < 5*time.Minute {
+	defer dp.cacheMutex.RUnlock()
+	for instanceID, cached := range dp.radarrCache {
+		if time.Since(cached.LastUpdated) >= 5*time.Minute {
+			continue
+		}
 		for id, movie := range cached.Movies {
 			if dp.isMovieMatch(item, movie) {
 				uniqueID := fmt.Sprintf("%s_%d", item.ID, id)
@@ -548,6 +887,7 @@ func (dp *DownloadPoller) matchWithRadarr(item downloadclient.Item) *Download {
 					TimeLeft:     utils.PtrString(item.TimeLeft),
 					Status:       utils.PtrString(item.Status),
 					Hash:         utils.PtrString(item.Hash),
+					ArrServiceID: instanceID,
 				}
 			}
 		}
@@ -572,48 +912,40 @@ func (dp *DownloadPoller) matchWithRadarr(item downloadclient.Item) *Download {
 
 // matchWithSonarr attempts to match a download with Sonarr series data
 func (dp *DownloadPoller) matchWithSonarr(item downloadclient.Item) *Download {
-	cb := dp.getCircuitBreaker(fmt.Sprintf("sonarr_cache_%s", item.ID))
-	if !cb.canExecute() {
-		return nil
-	}
 	dp.cacheMutex.RLock()
-	cached, exists := dp.sonarrCache[item.ID]
-	dp.cacheMutex.RUnlock()
-	if exists && time.Since(cached.LastUpdated) < 5*time.Minute {
+	defer dp.cacheMutex.RUnlock()
+	for instanceID, cached := range dp.sonarrCache {
+		if time.Since(cached.LastUpdated) >= 5*time.Minute {
+			continue
+		}
 		for id, series := range cached.Series {
-			if dp.isSeriesMatch(item, series) {
-				uniqueID := fmt.Sprintf("%s_%d", item.ID, id)
-				return &Download{
-					ID:           uniqueID,
-					Title:        series.Title,
-					TorrentTitle: item.Name,
-					Source:       "sonarr",
-					TmdbID:       utils.PtrInt64(int64(series.TmdbID)),
-					Progress:     item.Progress,
-					TimeLeft:     utils.PtrString(item.TimeLeft),
-					Status:       utils.PtrString(item.Status),
-					Hash:         utils.PtrString(item.Hash),
-				}
+			if !dp.isSeriesMatch(item, series) {
+				continue
 			}
-		}
-		for id, episode := range cached.Episodes {
-			if dp.isEpisodeMatch(item, episode) {
-				series, exists := cached.Series[episode.SeriesID]
-				if exists {
-					uniqueID := fmt.Sprintf("%s_%d", item.ID, id)
-					return &Download{
-						ID:           uniqueID,
-						Title:        fmt.Sprintf("%s S%02dE%02d - %s", series.Title, episode.SeasonNumber, episode.EpisodeNumber, episode.Title),
-						TorrentTitle: item.Name,
-						Source:       "sonarr",
-						TmdbID:       utils.PtrInt64(int64(series.TmdbID)),
-						Progress:     item.Progress,
-						TimeLeft:     utils.PtrString(item.TimeLeft),
-						Status:       utils.PtrString(item.Status),
-						Hash:         utils.PtrString(item.Hash),
-					}
+
+			download := &Download{
+				ID:           fmt.Sprintf("%s_%d", item.ID, id),
+				Title:        series.Title,
+				TorrentTitle: item.Name,
+				Source:       "sonarr",
+				TmdbID:       utils.PtrInt64(int64(series.TmdbID)),
+				Progress:     item.Progress,
+				TimeLeft:     utils.PtrString(item.TimeLeft),
+				Status:       utils.PtrString(item.Status),
+				Hash:         utils.PtrString(item.Hash),
+				ArrServiceID: instanceID,
+			}
+
+			// Only episodes of the matched series are considered, an SxxEyy
+			// pattern on its own fits an episode of nearly every show
+			for _, episode := range cached.Episodes {
+				if episode.SeriesID == id && dp.isEpisodeMatch(item, episode) {
+					download.Title = fmt.Sprintf("%s S%02dE%02d - %s", series.Title, episode.SeasonNumber, episode.EpisodeNumber, episode.Title)
+					download.addEpisode(episode.SeasonNumber, episode.EpisodeNumber)
+					break
 				}
 			}
+			return download
 		}
 	}
 
@@ -802,6 +1134,101 @@ func (dp *DownloadPoller) extractYear(title string) int {
 	return 0
 }
 
+// detectStalls flags stalled and failed downloads. Each stall is reported once
+// to admins and the requester. When the policy allows it, the download is
+// removed from the Radarr/Sonarr queue, which blocklists the release and
+// searches for another. It returns how many downloads newly stalled and how
+// many of those were remediated.
+func (dp *DownloadPoller) detectStalls(ctx context.Context, downloads []Download, radarrInstances, sonarrInstances []repository.ArrService) (int, int) {
+	policy := DownloadStallPolicy(ctx, dp.Context().Crate().Sqlite.Query())
+
+	services := make(map[string]repository.ArrService)
+	for _, service := range radarrInstances {
+		services[service.ID] = service
+	}
+	for _, service := range sonarrInstances {
+		services[service.ID] = service
+	}
+
+	now := time.Now()
+	stalled, remediated := 0, 0
+	active := make(map[string]bool, len(downloads))
+	for i := range downloads {
+		download := &downloads[i]
+		active[download.ID] = true
+
+		reason, isNew := dp.stalls.check(policy, download.ID, download.ClientItem, download.QueueFailure, now)
+		download.Stall = reason
+		if reason == "" {
+			continue
+		}
+		if isNew {
+			stalled++
+			slog.Warn("Download stalled",
+				"id", download.ID,
+				"title", download.Title,
+				"reason", reason,
+				"failure", download.QueueFailure)
+		}
+
+		// Remediation that failed is retried on the next polls until it succeeds
+		fixed := false
+		service, ok := services[download.ArrServiceID]
+		if ok && policy.AutoRemediate && len(download.QueueIDs) > 0 && dp.stalls.needsRemediation(download.ID) {
+			// Removing one queue item removes the whole download from the client
+			if err := removeArrQueueItem(ctx, service.BaseUrl, service.ApiKey.String(), download.QueueIDs[0]); err != nil {
+				slog.Error("Failed to remediate stalled download", "id", download.ID, "service", service.Name, "error", err)
+			} else {
+				slog.Info("Blocklisted stalled release and searching for another", "id", download.ID, "release", download.TorrentTitle)
+				dp.stalls.markRemediated(download.ID)
+				fixed = true
+				remediated++
+			}
+		}
+
+		// A stall is reported when first seen, and again once a retry fixed it
+		if isNew || fixed {
+			go dp.notifyDownloadStalled(*download, reason, fixed)
+		}
+	}
+	dp.stalls.forget(active)
+
+	return stalled, remediated
+}
+
+// notifyDownloadStalled tells admins and whoever made the linked request that a
+// download stalled
+func (dp *DownloadPoller) notifyDownloadStalled(download Download, reason structures.DownloadStallReason, remediated bool) {
+	bgCtx := context.Background()
+
+	mediaType := "movie"
+	if download.Source == "sonarr" {
+		mediaType = "tv"
+	}
+
+	requesterID := ""
+	if download.RequestID != nil {
+		request, err := dp.Context().Crate().Sqlite.Query().GetRequestByID(bgCtx, *download.RequestID)
+		if err == nil {
+			requesterID = request.UserID
+		}
+	}
+
+	err := dp.Context().Crate().NotificationService.NotifyDownloadStalled(
+		bgCtx,
+		requesterID,
+		download.Title,
+		mediaType,
+		reason,
+		remediated,
+		download.TmdbID,
+		&download.ID,
+	)
+	if err != nil {
+		slog.Error("Failed to send download stalled notification", "error", err, "download_id", download.ID)
+	}
+}
+
 // broadcastDownloadUpdates handles WebSocket broadcasting with completion events
 func (dp *DownloadPoller) broadcastDownloadUpdates(downloads []Download) {
 	dp.statesMutex.Lock()
@@ -822,49 +1249,59 @@ func (dp *DownloadPoller) broadcastDownloadUpdates(downloads []Download) {
 		if existed && lastStatus != "completed" && currentStatus == "completed" {
 			completedDownloads = append(completedDownloads, d)
 			slog.Info("Download completed", "id", d.ID, "title", d.Title)
-			
+
 			// Send notification for completed download
 			go func(download Download) {
 				bgCtx := context.Background()
-				
-				// Try to find the user who requested this media
-				// This is a best-effort approach - in a real system you'd want to track this more precisely
-				if download.TmdbID != nil {
-					mediaType := "movie"
-					if download.Source == "sonarr" {
-						mediaType = "tv"
+				query := dp.Context().Crate().Sqlite.Query()
+
+				mediaType := "movie"
+				if download.Source == "sonarr" {
+					mediaType = "tv"
+				}
+
+				// Notify whoever made the linked request. Downloads that aren't
+				// linked fall back to the most recent requester of the media.
+				var request *repository.Request
+				if download.RequestID != nil {
+					linked, err := query.GetRequestByID(bgCtx, *download.RequestID)
+					if err == nil {
+						request = &linked
 					}
-					
-					// Get recent requests for this TMDB ID to find the user
-					requests, err := dp.Context().Crate().Sqlite.Query().GetRequestsByTMDBIDAndMediaType(bgCtx, repository.GetRequestsByTMDBIDAndMediaTypeParams{
+				}
+				if request == nil && download.TmdbID != nil {
+					requests, err := query.GetRequestsByTMDBIDAndMediaType(bgCtx, repository.GetRequestsByTMDBIDAndMediaTypeParams{
 						TmdbID:    sql.NullInt64{Int64: *download.TmdbID, Valid: true},
 						MediaType: mediaType,
 					})
 					if err == nil && len(requests) > 0 {
-						// Notify the most recent requester
 						mostRecentRequest := requests[0]
 						for _, req := range requests {
 							if req.CreatedAt.After(mostRecentRequest.CreatedAt) {
 								mostRecentRequest = req
 							}
 						}
-						
-						err := dp.Context().Crate().NotificationService.NotifyDownloadCompleted(
-							bgCtx,
-							mostRecentRequest.UserID,
-							download.Title,
-							mediaType,
-							download.TmdbID,
-							&download.ID,
-						)
-						if err != nil {
-							slog.Error("Failed to send download completion notification", 
-								"error", err, 
-								"download_id", download.ID,
-								"user_id", mostRecentRequest.UserID)
-						}
+						request = &mostRecentRequest
 					}
 				}
+				if request == nil {
+					return
+				}
+
+				err := dp.Context().Crate().NotificationService.NotifyDownloadCompleted(
+					bgCtx,
+					request.UserID,
+					download.Title,
+					mediaType,
+					download.TmdbID,
+					&download.ID,
+				)
+				if err != nil {
+					slog.Error("Failed to send download completion notification",
+						"error", err,
+						"download_id", download.ID,
+						"user_id", request.UserID)
+				}
 			}(d)
 		}
 
@@ -879,26 +1316,33 @@ func (dp *DownloadPoller) broadcastDownloadUpdates(downloads []Download) {
 		var batch []structures.DownloadProgressPayload
 		for _, d := range activeDownloads {
 			batch = append(batch, structures.DownloadProgressPayload{
-				ID:           d.ID,
-				Title:        d.Title,
-				TorrentTitle: d.TorrentTitle,
-				Source:       d.Source,
-				TMDBID:       d.TmdbID,
-				TvDBID:       nil,
-				Hash:         utils.DerefString(d.Hash),
-				Progress:     d.Progress,
-				TimeLeft:     utils.DerefString(d.TimeLeft),
-				Status:       utils.DerefString(d.Status),
-				LastUpdated:  time.Now().Format(time.RFC3339),
+				ID:              d.ID,
+				Title:           d.Title,
+				TorrentTitle:    d.TorrentTitle,
+				Source:          d.Source,
+				TMDBID:          d.TmdbID,
+				TvDBID:          nil,
+				Hash:            utils.DerefString(d.Hash),
+				Progress:        d.Progress,
+				TimeLeft:        utils.DerefString(d.TimeLeft),
+				Status:          utils.DerefString(d.Status),
+				LastUpdated:     time.Now().Format(time.RFC3339),
+				RequestID:       d.RequestID,
+				Episodes:        d.Episodes,
+				MatchConfidence: d.MatchConfidence.String(),
+				StallReason:     d.Stall.String(),
 			})
 		}
 
-		websocket.BroadcastDownloadProgressBatch(batch)
-		
+		dp.storeSnapshot(batch)
+		dp.sendDownloadProgressBatch(batch)
+
 		connectedClients := websocket.GetConnectionCount()
 		slog.Debug("Broadcasting active downloads",
 			"activeDownloads", len(activeDownloads),
 			"connectedClients", connectedClients)
+	} else {
+		dp.storeSnapshot(nil)
 	}
 
 	// Broadcast completion events
@@ -906,22 +1350,25 @@ func (dp *DownloadPoller) broadcastDownloadUpdates(downloads []Download) {
 		var completionBatch []structures.DownloadProgressPayload
 		for _, d := range completedDownloads {
 			completionBatch = append(completionBatch, structures.DownloadProgressPayload{
-				ID:           d.ID,
-				Title:        d.Title,
-				TorrentTitle: d.TorrentTitle,
-				Source:       d.Source,
-				TMDBID:       d.TmdbID,
-				TvDBID:       nil,
-				Hash:         utils.DerefString(d.Hash),
-				Progress:     100.0, // Ensure completed downloads show 100%
-				TimeLeft:     "",
-				Status:       "completed",
-				LastUpdated:  time.Now().Format(time.RFC3339),
+				ID:              d.ID,
+				Title:           d.Title,
+				TorrentTitle:    d.TorrentTitle,
+				Source:          d.Source,
+				TMDBID:          d.TmdbID,
+				TvDBID:          nil,
+				Hash:            utils.DerefString(d.Hash),
+				Progress:        100.0, // Ensure completed downloads show 100%
+				TimeLeft:        "",
+				Status:          "completed",
+				LastUpdated:     time.Now().Format(time.RFC3339),
+				RequestID:       d.RequestID,
+				Episodes:        d.Episodes,
+				MatchConfidence: d.MatchConfidence.String(),
 			})
 		}
 
-		websocket.BroadcastDownloadProgressBatch(completionBatch)
-		
+		dp.sendDownloadProgressBatch(completionBatch)
+
 		connectedClients := websocket.GetConnectionCount()
 		slog.Info("Broadcasting completion events",
 			"completedDownloads", len(completedDownloads),
@@ -933,7 +1380,7 @@ func (dp *DownloadPoller) broadcastDownloadUpdates(downloads []Download) {
 	for _, d := range downloads {
 		currentIDs[d.ID] = true
 	}
-	
+
 	for id := range dp.lastDownloadStates {
 		if !currentIDs[id] {
 			delete(dp.lastDownloadStates, id)
@@ -941,6 +1388,138 @@ func (dp *DownloadPoller) broadcastDownloadUpdates(downloads []Download) {
 	}
 }
 
+// sendDownloadProgressBatch sends download progress over WebSocket. When
+// download visibility is limited to a user's own downloads, each connected user
+// is only sent the downloads linked to their requests.
+func (dp *DownloadPoller) sendDownloadProgressBatch(batch []structures.DownloadProgressPayload) {
+	ctx := context.Background()
+	query := dp.Context().Crate().Sqlite.Query()
+
+	// Subscribers of a request get its downloads whatever the visibility,
+	// they were checked when subscribing. Those also subscribed to all
+	// downloads are skipped, they may see the request's downloads and get them
+	// in the batch below.
+	for requestID, downloads := range downloadsByRequest(batch) {
+		websocket.PublishDownloadProgressBatch(structures.RequestTopic(requestID), downloads)
+	}
+
+	if DownloadVisibility(ctx, query) == structures.DownloadVisibilityAll {
+		websocket.BroadcastDownloadProgressBatch(batch)
+		return
+	}
+
+	owners, err := DownloadOwners(ctx, query)
+	if err != nil {
+		slog.Error("Failed to link downloads to requests, not broadcasting progress", "error", err)
+		return
+	}
+
+	for _, client := range websocket.GetConnectedClients() {
+		seesAll, err := SeesAllDownloads(ctx, query, client.UserID, client.IsAdmin)
+		if err != nil {
+			slog.Error("Failed to check download visibility", "error", err, "user_id", client.UserID)
+			continue
+		}
+
+		if seesAll {
+			websocket.SendDownloadProgressBatch(client.UserID, batch)
+			continue
+		}
+
+		var visible []structures.DownloadProgressPayload
+		for _, d := range batch {
+			if owners[d.ID][client.UserID] {
+				visible = append(visible, d)
+			}
+		}
+		if len(visible) > 0 {
+			websocket.SendDownloadProgressBatch(client.UserID, visible)
+		}
+	}
+}
+
+// storeSnapshot keeps the progress of the active downloads for new
+// subscribers, on this instance and on the followers
+func (dp *DownloadPoller) storeSnapshot(batch []structures.DownloadProgressPayload) {
+	dp.snapshotMutex.Lock()
+	dp.snapshot = batch
+	dp.snapshotMutex.Unlock()
+
+	if err := storeSharedState(context.Background(), dp.Context().Crate().Sqlite.Query(), sharedStateDownloads, batch); err != nil {
+		slog.Error("Failed to share download progress", "error", err)
+	}
+}
+
+// currentSnapshot returns the progress of the active downloads. Followers
+// don't poll, so they serve what the leader last stored.
+func (dp *DownloadPoller) currentSnapshot(ctx context.Context) []structures.DownloadProgressPayload {
+	if dp.isLeader() {
+		dp.snapshotMutex.RLock()
+		defer dp.snapshotMutex.RUnlock()
+		return dp.snapshot
+	}
+
+	var batch []structures.DownloadProgressPayload
+	if _, err := loadSharedState(ctx, dp.Context().Crate().Sqlite.Query(), sharedStateDownloads, &batch); err != nil {
+		slog.Error("Failed to load download progress shared by the job leader", "error", err)
+	}
+	return batch
+}
+
+// sendSnapshot sends a client that just subscribed the active downloads of the
+// topic it may see
+func (dp *DownloadPoller) sendSnapshot(client websocket.ConnectedClient, topic structures.Topic) {
+	ctx := context.Background()
+	batch := dp.currentSnapshot(ctx)
+
+	if requestID, ok := topic.RequestID(); ok {
+		downloads := downloadsByRequest(batch)[requestID]
+		websocket.PublishToUser(client.UserID, topic, structures.OpcodeDownloadProgressBatch, structures.DownloadProgressBatchPayload{
+			Downloads: downloads,
+			Count:     len(downloads),
+			Timestamp: time.Now().UnixMilli(),
+		})
+		return
+	}
+
+	query := dp.Context().Crate().Sqlite.Query()
+
+	if DownloadVisibility(ctx, query) != structures.DownloadVisibilityAll {
+		seesAll, err := SeesAllDownloads(ctx, query, client.UserID, client.IsAdmin)
+		if err != nil {
+			slog.Error("Failed to check download visibility", "error", err, "user_id", client.UserID)
+			return
+		}
+		if !seesAll {
+			owners, err := DownloadOwners(ctx, query)
+			if err != nil {
+				slog.Error("Failed to link downloads to requests, not sending snapshot", "error", err)
+				return
+			}
+			var visible []structures.DownloadProgressPayload
+			for _, d := range batch {
+				if owners[d.ID][client.UserID] {
+					visible = append(visible, d)
+				}
+			}
+			batch = visible
+		}
+	}
+
+	websocket.SendDownloadProgressBatch(client.UserID, batch)
+}
+
+// downloadsByRequest groups download progress by the request it fulfils
+func downloadsByRequest(batch []structures.DownloadProgressPayload) map[int64][]structures.DownloadProgressPayload {
+	grouped := make(map[int64][]structures.DownloadProgressPayload)
+	for _, d := range batch {
+		if d.RequestID != nil {
+			grouped[*d.RequestID] = append(grouped[*d.RequestID], d)
+		}
+	}
+	return grouped
+}
+
 // storeDownloads stores downloads in the database
 func (dp *DownloadPoller) storeDownloads(downloads []Download) {
 	slog.Debug("Storing downloads in database", "count", len(downloads))
@@ -952,6 +1531,28 @@ func (dp *DownloadPoller) storeDownloads(downloads []Download) {
 			tmdbID = utils.NewNullInt64(*download.TmdbID, true)
 		}
 
+		var seasonNumber sql.NullInt64
+		if download.SeasonNumber != nil {
+			seasonNumber = utils.NewNullInt64(*download.SeasonNumber, true)
+		}
+
+		var requestID sql.NullInt64
+		if download.RequestID != nil {
+			requestID = utils.NewNullInt64(*download.RequestID, true)
+		}
+
+		var episodes sql.NullString
+		if len(download.Episodes) > 0 {
+			if encoded, err := json.Marshal(download.Episodes); err == nil {
+				episodes = utils.NewNullString(string(encoded))
+			}
+		}
+
+		var matchConfidence sql.NullString
+		if download.MatchConfidence != "" {
+			matchConfidence = utils.NewNullString(download.MatchConfidence.String())
+		}
+
 		var hash sql.NullString
 		if download.Hash != nil {
 			hash = utils.NewNullString(*download.Hash)
@@ -968,15 +1569,21 @@ func (dp *DownloadPoller) storeDownloads(downloads []Download) {
 		}
 
 		err := dp.Context().Crate().Sqlite.Query().UpsertDownloadQueue(context.Background(), repository.UpsertDownloadQueueParams{
-			ID:           download.ID,
-			Title:        download.Title,
-			TorrentTitle: download.TorrentTitle,
-			Source:       download.Source,
-			TmdbID:       tmdbID,
-			Hash:         hash,
-			Progress:     utils.NewNullFloat64(download.Progress, true),
-			TimeLeft:     timeLeft,
-			Status:       status,
+			ID:              download.ID,
+			Title:           download.Title,
+			TorrentTitle:    download.TorrentTitle,
+			Source:          download.Source,
+			TmdbID:          tmdbID,
+			SeasonNumber:    seasonNumber,
+			RequestID:       requestID,
+			Episodes:        episodes,
+			MatchConfidence: matchConfidence,
+			Client:          utils.NewNullString(download.Client),
+			SizeBytes:       utils.NewNullInt64(download.SizeBytes, download.SizeBytes > 0),
+			Hash:            hash,
+			Progress:        utils.NewNullFloat64(download.Progress, true),
+			TimeLeft:        timeLeft,
+			Status:          status,
 		})
 		if err != nil {
 			slog.Error("Failed to upsert download", "id", download.ID, "error", err)
@@ -984,39 +1591,52 @@ func (dp *DownloadPoller) storeDownloads(downloads []Download) {
 	}
 }
 
-// adjustPollInterval adjusts the polling interval based on download activity
-func (dp *DownloadPoller) adjustPollInterval(currentDownloadCount int) {
-	// If downloads increased, poll more frequently
-	if currentDownloadCount > dp.lastDownloadCount {
-		newInterval := dp.pollInterval - 5*time.Second
-		if newInterval < 5*time.Second {
-			newInterval = 5 * time.Second // Minimum 5 seconds
-		}
-		dp.pollInterval = newInterval
-	} else if currentDownloadCount == 0 && dp.lastDownloadCount > 0 {
-		// If downloads went from some to none, gradually increase interval
-		newInterval := dp.pollInterval + 10*time.Second
-		if newInterval > 60*time.Second {
-			newInterval = 60 * time.Second // Maximum 60 seconds
-		}
-		dp.pollInterval = newInterval
-	} else if currentDownloadCount == 0 && dp.lastDownloadCount == 0 {
-		// If no downloads for a while, use longer interval
-		if dp.pollInterval < 30*time.Second {
-			dp.pollInterval = 30 * time.Second
-		}
+// pollFailures records the sources a poll couldn't read. Their downloads are
+// missing from the poll without having left the queue.
+type pollFailures struct {
+	services map[string]bool // Radarr/Sonarr instances whose queue failed
+	sources  map[string]bool // "radarr" or "sonarr" when the instances couldn't be listed
+}
+
+func (f *pollFailures) addService(id string) {
+	if f.services == nil {
+		f.services = make(map[string]bool)
 	}
+	f.services[id] = true
+}
 
-	// Update ticker if interval changed
-	if dp.ticker != nil {
-		dp.ticker.Reset(dp.pollInterval)
+func (f *pollFailures) addSource(source string) {
+	if f.sources == nil {
+		f.sources = make(map[string]bool)
 	}
+	f.sources[source] = true
+}
+
+// any reports whether a source failed
+func (f pollFailures) any() bool {
+	return len(f.services) > 0 || len(f.sources) > 0
+}
 
-	dp.lastDownloadCount = currentDownloadCount
+// covers reports whether a stored download comes from a source that failed.
+// Queue downloads are identified by their instance, title matches depend on
+// every queue and are covered by any failure.
+func (f pollFailures) covers(download repository.ListDownloadsRow) bool {
+	if !f.any() {
+		return false
+	}
+	if f.sources[download.Source] || download.MatchConfidence.String == structures.DownloadMatchLow.String() {
+		return true
+	}
+	for id := range f.services {
+		if strings.HasPrefix(download.ID, id+"_") {
+			return true
+		}
+	}
+	return false
 }
 
 // cleanupCompletedDownloads removes downloads that are no longer active from the database
-func (dp *DownloadPoller) cleanupCompletedDownloads(activeDownloads []Download) {
+func (dp *DownloadPoller) cleanupCompletedDownloads(activeDownloads []Download, failures pollFailures) {
 	// Get all downloads from database
 	downloads, err := dp.Context().Crate().Sqlite.Query().ListDownloads(context.Background())
 	if err != nil {
@@ -1030,9 +1650,12 @@ func (dp *DownloadPoller) cleanupCompletedDownloads(activeDownloads []Download)
 		activeIDs[download.ID] = true
 	}
 
-	// Remove downloads that are no longer active
+	// Remove downloads that are no longer active, keeping them in the history.
+	// Those of sources that failed this poll may still be active.
 	for _, dbDownload := range downloads {
-		if !activeIDs[dbDownload.ID] {
+		if !activeIDs[dbDownload.ID] && !failures.covers(dbDownload) {
+			dp.recordDownloadHistory(dbDownload)
+
 			err := dp.Context().Crate().Sqlite.Query().DeleteDownload(context.Background(), dbDownload.ID)
 			if err != nil {
 				slog.Error("Failed to delete completed download", "id", dbDownload.ID, "error", err)
@@ -1053,6 +1676,8 @@ func (dp *DownloadPoller) cleanupOldMissingDownloads() {
 	}
 
 	for _, download := range downloads {
+		dp.recordDownloadHistory(repository.ListDownloadsRow(download))
+
 		err := dp.Context().Crate().Sqlite.Query().DeleteDownload(context.Background(), download.ID)
 		if err != nil {
 			slog.Error("Failed to delete old missing download", "id", download.ID, "error", err)
@@ -1068,6 +1693,62 @@ func (dp *DownloadPoller) cleanupOldMissingDownloads() {
 	}
 }
 
+// recordDownloadHistory keeps a download that left the queue in the download history
+func (dp *DownloadPoller) recordDownloadHistory(download repository.ListDownloadsRow) {
+	finishedAt := time.Now().UTC()
+	startedAt := finishedAt
+	if download.StartedAt.Valid {
+		startedAt = download.StartedAt.Time.UTC()
+	} else if download.LastUpdated.Valid {
+		startedAt = download.LastUpdated.Time.UTC()
+	}
+
+	progress := download.Progress.Float64
+	outcome := downloadOutcome(download.Status.String, progress)
+
+	releaseName := download.TorrentTitle
+	if releaseName == "" {
+		releaseName = download.Title
+	}
+
+	var speed sql.NullInt64
+	if download.SizeBytes.Valid {
+		speed = utils.NewNullInt64FromPtr(averageSpeed(download.SizeBytes.Int64, progress, startedAt, finishedAt))
+	}
+
+	err := dp.Context().Crate().Sqlite.Query().CreateDownloadHistory(context.Background(), repository.CreateDownloadHistoryParams{
+		DownloadID:   download.ID,
+		RequestID:    download.RequestID,
+		Title:        download.Title,
+		ReleaseName:  releaseName,
+		Source:       download.Source,
+		Client:       download.Client,
+		TmdbID:       download.TmdbID,
+		Episodes:     download.Episodes,
+		SizeBytes:    download.SizeBytes,
+		AverageSpeed: speed,
+		StartedAt:    startedAt,
+		FinishedAt:   finishedAt,
+		Outcome:      outcome.String(),
+	})
+	if err != nil {
+		slog.Error("Failed to record download history", "id", download.ID, "error", err)
+	}
+}
+
+// pruneDownloadHistory removes history entries older than the configured retention
+func (dp *DownloadPoller) pruneDownloadHistory() {
+	days := DownloadHistoryRetentionDays(context.Background(), dp.Context().Crate().Sqlite.Query())
+	if days == 0 {
+		return
+	}
+
+	cutoff := time.Now().AddDate(0, 0, -days).UTC()
+	if err := dp.Context().Crate().Sqlite.Query().DeleteDownloadHistoryBefore(context.Background(), cutoff); err != nil {
+		slog.Error("Failed to prune download history", "error", err)
+	}
+}
+
 // cleanupCache removes old cache entries to prevent memory leaks
 func (dp *DownloadPoller) cleanupCache() {
 	dp.cacheMutex.Lock()
@@ -1421,24 +2102,41 @@ func (dp *DownloadPoller) extractTVShowTitle(name string) string {
 // --- Helper functions for fetching queues and details ---
 
 type radarrQueueItem struct {
-	DownloadID string `json:"downloadId"`
-	Title      string `json:"title"`
-	MovieID    int    `json:"movieId"`
-	Size       int64  `json:"size"`
-	SizeLeft   int64  `json:"sizeleft"`
-	Status     string `json:"status"`
-	TimeLeft   string `json:"timeleft"`
+	ID                    int                `json:"id"`
+	DownloadID            string             `json:"downloadId"`
+	DownloadClient        string             `json:"downloadClient"`
+	Title                 string             `json:"title"`
+	MovieID               int                `json:"movieId"`
+	Size                  int64              `json:"size"`
+	SizeLeft              int64              `json:"sizeleft"`
+	Status                string             `json:"status"`
+	TrackedDownloadStatus string             `json:"trackedDownloadStatus"`
+	ErrorMessage          string             `json:"errorMessage"`
+	StatusMessages        []arrStatusMessage `json:"statusMessages"`
+	TimeLeft              string             `json:"timeleft"`
 }
 
 type sonarrQueueItem struct {
-	DownloadID string `json:"downloadId"`
-	Title      string `json:"title"`
-	SeriesID   int    `json:"seriesId"`
-	EpisodeID  int    `json:"episodeId"`
-	Size       int64  `json:"size"`
-	SizeLeft   int64  `json:"sizeleft"`
-	Status     string `json:"status"`
-	TimeLeft   string `json:"timeleft"`
+	ID                    int                `json:"id"`
+	DownloadID            string             `json:"downloadId"`
+	DownloadClient        string             `json:"downloadClient"`
+	Title                 string             `json:"title"`
+	SeriesID              int                `json:"seriesId"`
+	EpisodeID             int                `json:"episodeId"`
+	Size                  int64              `json:"size"`
+	SizeLeft              int64              `json:"sizeleft"`
+	Status                string             `json:"status"`
+	TrackedDownloadStatus string             `json:"trackedDownloadStatus"`
+	ErrorMessage          string             `json:"errorMessage"`
+	StatusMessages        []arrStatusMessage `json:"statusMessages"`
+	TimeLeft              string             `json:"timeleft"`
+}
+
+// arrStatusMessage is a message Radarr/Sonarr attach to a queue item, e.g. a
+// failed repair
+type arrStatusMessage struct {
+	Title    string   `json:"title"`
+	Messages []string `json:"messages"`
 }
 
 func fetchRadarrQueue(ctx context.Context, baseURL, apiKey string) ([]radarrQueueItem, error) {
@@ -1462,6 +2160,28 @@ func fetchRadarrQueue(ctx context.Context, baseURL, apiKey string) ([]radarrQueu
 	return result.Records, nil
 }
 
+// removeArrQueueItem removes a download from a Radarr/Sonarr queue and its
+// download client. The release is blocklisted and the *arr searches for
+// another one.
+func removeArrQueueItem(ctx context.Context, baseURL, apiKey string, queueID int) error {
+	url := utils.BuildURL(baseURL, fmt.Sprintf("/api/v3/queue/%d", queueID), map[string]string{
+		"removeFromClient": "true",
+		"blocklist":        "true",
+		"skipRedownload":   "false",
+	})
+	req, _ := http.NewRequestWithContext(ctx, http.MethodDelete, url, nil)
+	req.Header.Set("X-Api-Key", apiKey)
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if !utils.IsHTTPSuccess(resp.StatusCode) {
+		return fmt.Errorf("queue removal request failed: %s", resp.Status)
+	}
+	return nil
+}
+
 func fetchRadarrMovie(ctx context.Context, baseURL, apiKey string, movieID int) (struct {
 	TmdbID int
 	Title  string
@@ -0,0 +1,115 @@
+package jobs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mahcks/serra/config"
+	"github.com/mahcks/serra/internal/db/dbtest"
+	"github.com/mahcks/serra/internal/eventbus"
+	"github.com/mahcks/serra/internal/global"
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+// idlePolls runs the poller's schedule for a while with nobody watching and
+// returns the times it polled
+func idlePolls(poller *DownloadPoller, start time.Time, run time.Duration) []time.Duration {
+	var polls []time.Duration
+	for elapsed := time.Duration(0); elapsed <= run; elapsed += poller.Config().Interval {
+		now := start.Add(elapsed)
+		if poller.pollDue(0, now) {
+			poller.lastPollTime = now
+			polls = append(polls, elapsed)
+		}
+	}
+	return polls
+}
+
+func TestPollDueBacksOffWhileIdle(t *testing.T) {
+	gctx := global.New(context.Background(), &config.Bootstrap{}, "test", "")
+	poller := &DownloadPoller{BaseJob: NewBaseJob(gctx, structures.JobDownloadPoller, JobConfig{Interval: 15 * time.Second})}
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	polls := idlePolls(poller, start, 20*time.Minute)
+	gaps := make([]time.Duration, 0, len(polls)-1)
+	for i := 1; i < len(polls); i++ {
+		gaps = append(gaps, polls[i]-polls[i-1])
+	}
+	want := []time.Duration{15 * time.Second, 30 * time.Second, time.Minute, 2 * time.Minute, 4 * time.Minute, 5 * time.Minute, 5 * time.Minute}
+	if len(gaps) < len(want) {
+		t.Fatalf("polled at %v", polls)
+	}
+	for i, gap := range want {
+		if gaps[i] != gap {
+			t.Fatalf("gaps between polls = %v, want %v first", gaps, want)
+		}
+	}
+
+	// Someone watching polls every run and starts the back-off over
+	if !poller.pollDue(1, start.Add(20*time.Minute+time.Second)) || poller.idleInterval != 0 {
+		t.Fatalf("a watcher didn't reset the back-off, idle interval %s", poller.idleInterval)
+	}
+}
+
+func TestPollDueCapsBackOffWhileDownloadsAreTracked(t *testing.T) {
+	gctx := global.New(context.Background(), &config.Bootstrap{}, "test", "")
+	poller := &DownloadPoller{BaseJob: NewBaseJob(gctx, structures.JobDownloadPoller, JobConfig{Interval: 15 * time.Second})}
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	// Backed off fully while the queue was empty
+	idlePolls(poller, start, 20*time.Minute)
+	if poller.idleInterval != maxIdlePollInterval {
+		t.Fatalf("idle interval = %s, want %s", poller.idleInterval, maxIdlePollInterval)
+	}
+
+	// A poll finds downloads, stall checks and the history need them polled more often
+	poller.trackedDownloads = 2
+	polls := idlePolls(poller, poller.lastPollTime, 10*time.Minute)
+	for i := 1; i < len(polls); i++ {
+		if gap := polls[i] - polls[i-1]; gap > maxTrackedPollInterval {
+			t.Fatalf("polled %s apart with downloads tracked: %v", gap, polls)
+		}
+	}
+	if len(polls) < 10 {
+		t.Fatalf("only %d polls in 10 minutes with downloads tracked", len(polls))
+	}
+}
+
+func TestFollowersServeTheLeadersDownloads(t *testing.T) {
+	db := dbtest.Service(t)
+	ctx := context.Background()
+	newPoller := func(nodeID string, leading bool) *DownloadPoller {
+		gctx := global.New(context.Background(), &config.Bootstrap{}, "test", "")
+		gctx.Crate().Sqlite = db
+		gctx.Crate().EventBus = eventbus.NewMemory(nodeID)
+		poller := &DownloadPoller{BaseJob: NewBaseJob(gctx, structures.JobDownloadPoller, JobConfig{Interval: 15 * time.Second, Timeout: time.Minute})}
+		poller.SetLeadership(func() bool { return leading })
+		return poller
+	}
+	leader, follower := newPoller("node-a", true), newPoller("node-b", false)
+
+	if batch := follower.currentSnapshot(ctx); len(batch) != 0 {
+		t.Fatalf("follower served %+v before the leader polled", batch)
+	}
+
+	requestID := int64(1)
+	leader.storeSnapshot([]structures.DownloadProgressPayload{
+		{ID: "radarr_1_a", Title: "The Matrix", Progress: 42, RequestID: &requestID},
+		{ID: "sonarr_2_b", Title: "Show", Progress: 7},
+	})
+	batch := follower.currentSnapshot(ctx)
+	if len(batch) != 2 || batch[0].ID != "radarr_1_a" || batch[0].Progress != 42 || batch[0].RequestID == nil || *batch[0].RequestID != 1 {
+		t.Fatalf("follower served %+v, want the leader's downloads", batch)
+	}
+
+	// Someone watching on the follower gets the leader to poll
+	woken := make(chan struct{}, 1)
+	follower.Context().Crate().EventBus.Subscribe(busTopicDownloadsWake, func(eventbus.Event) { woken <- struct{}{} })
+	follower.wake(ctx)
+	select {
+	case <-woken:
+	case <-time.After(time.Second):
+		t.Fatal("follower didn't ask the leader to poll")
+	}
+}
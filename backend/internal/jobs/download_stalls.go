@@ -0,0 +1,229 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mahcks/serra/internal/db/repository"
+	"github.com/mahcks/serra/pkg/downloadclient"
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+// DefaultDownloadStallPolicy is the stall policy used until one is configured.
+// Stalled downloads are reported but not remediated.
+func DefaultDownloadStallPolicy() structures.DownloadStallPolicy {
+	return structures.DownloadStallPolicy{
+		NoProgressMinutes: 60,
+		NoPeersMinutes:    30,
+		MetadataMinutes:   30,
+		DetectFailures:    true,
+	}
+}
+
+// ParseDownloadStallPolicy parses the download_stall_policy setting. Fields
+// that are left out keep their defaults.
+func ParseDownloadStallPolicy(value string) (structures.DownloadStallPolicy, error) {
+	policy := DefaultDownloadStallPolicy()
+	if value == "" {
+		return policy, nil
+	}
+	if err := json.Unmarshal([]byte(value), &policy); err != nil {
+		return policy, fmt.Errorf("invalid download stall policy: %w", err)
+	}
+	if policy.NoProgressMinutes < 0 || policy.NoPeersMinutes < 0 || policy.MetadataMinutes < 0 {
+		return policy, fmt.Errorf("invalid download stall policy: minutes can't be negative")
+	}
+	return policy, nil
+}
+
+// DownloadStallPolicy returns the configured stall policy, the default when
+// unset or invalid
+func DownloadStallPolicy(ctx context.Context, query *repository.Queries) structures.DownloadStallPolicy {
+	value, err := query.GetSetting(ctx, structures.SettingDownloadStallPolicy.String())
+	if err != nil {
+		return DefaultDownloadStallPolicy()
+	}
+
+	policy, err := ParseDownloadStallPolicy(value)
+	if err != nil {
+		slog.Warn("Invalid download stall policy, using default", "error", err)
+		return DefaultDownloadStallPolicy()
+	}
+	return policy
+}
+
+// stallState is what the detector remembers about a download between polls
+type stallState struct {
+	progress      float64
+	progressAt    time.Time // When the progress last moved
+	noPeersSince  time.Time
+	metadataSince time.Time
+	reason        structures.DownloadStallReason // The stall reported for the download, empty while healthy
+	remediated    bool                           // Whether the stalled download was removed from the queue
+}
+
+// stallDetector tracks downloads across polls to tell when they stall
+type stallDetector struct {
+	mu     sync.Mutex
+	states map[string]*stallState
+}
+
+func newStallDetector() *stallDetector {
+	return &stallDetector{
+		states: make(map[string]*stallState),
+	}
+}
+
+// check records the latest state of a download and returns why it is stalled,
+// empty when it isn't. isNew is set the first time a stall is seen, so it is
+// only reported once until the download recovers. item is nil when no download
+// client has the download, then only failures reported by Radarr/Sonarr count.
+func (s *stallDetector) check(policy structures.DownloadStallPolicy, id string, item *downloadclient.Item, failure string, now time.Time) (reason structures.DownloadStallReason, isNew bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.states[id]
+	if !ok {
+		state = &stallState{progressAt: now}
+		if item != nil {
+			state.progress = item.Progress
+		}
+		s.states[id] = state
+	}
+
+	reason = stallReason(policy, state, item, failure, now)
+	isNew = reason != "" && state.reason == ""
+	state.reason = reason
+	if reason == "" {
+		state.remediated = false
+	}
+	return reason, isNew
+}
+
+// needsRemediation reports whether a download is stalled and wasn't removed
+// from the queue yet, also when an earlier attempt failed
+func (s *stallDetector) needsRemediation(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.states[id]
+	return ok && state.reason != "" && !state.remediated
+}
+
+// markRemediated records that a stalled download was removed from the queue
+func (s *stallDetector) markRemediated(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if state, ok := s.states[id]; ok {
+		state.remediated = true
+	}
+}
+
+// stallReason updates the tracked state and works out why a download is stalled
+func stallReason(policy structures.DownloadStallPolicy, state *stallState, item *downloadclient.Item, failure string, now time.Time) structures.DownloadStallReason {
+	if policy.DetectFailures && (failure != "" || (item != nil && clientFailed(item.Status))) {
+		return structures.DownloadStallFailed
+	}
+	if item == nil || item.Progress >= 100 {
+		return ""
+	}
+
+	// Paused and queued downloads aren't expected to move
+	status := strings.ToLower(item.Status)
+	if status == "paused" || status == "queued" {
+		state.progress = item.Progress
+		state.progressAt = now
+		state.noPeersSince = time.Time{}
+		state.metadataSince = time.Time{}
+		return ""
+	}
+
+	if item.Progress > state.progress {
+		state.progressAt = now
+	}
+	state.progress = item.Progress
+
+	if strings.EqualFold(item.State, "metaDL") {
+		if state.metadataSince.IsZero() {
+			state.metadataSince = now
+		}
+	} else {
+		state.metadataSince = time.Time{}
+	}
+
+	if item.Seeds != nil && *item.Seeds == 0 && (item.Peers == nil || *item.Peers == 0) {
+		if state.noPeersSince.IsZero() {
+			state.noPeersSince = now
+		}
+	} else {
+		state.noPeersSince = time.Time{}
+	}
+
+	switch {
+	case exceeded(state.metadataSince, policy.MetadataMinutes, now):
+		return structures.DownloadStallMetadata
+	case exceeded(state.noPeersSince, policy.NoPeersMinutes, now):
+		return structures.DownloadStallNoPeers
+	case exceeded(state.progressAt, policy.NoProgressMinutes, now):
+		return structures.DownloadStallNoProgress
+	}
+	return ""
+}
+
+// exceeded reports whether more than the given minutes have passed since a
+// time. A limit of 0 disables it.
+func exceeded(since time.Time, minutes int, now time.Time) bool {
+	return minutes > 0 && !since.IsZero() && now.Sub(since) >= time.Duration(minutes)*time.Minute
+}
+
+// clientFailed reports whether a download client status means the download failed
+func clientFailed(status string) bool {
+	status = strings.ToLower(status)
+	return status == "failed" || status == "error"
+}
+
+// forget drops the tracking of downloads that are gone
+func (s *stallDetector) forget(active map[string]bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id := range s.states {
+		if !active[id] {
+			delete(s.states, id)
+		}
+	}
+}
+
+// arrQueueFailure returns the reason Radarr/Sonarr give for a failed queue
+// item, empty when it hasn't failed. SABnzbd failures and repair errors are
+// reported this way.
+func arrQueueFailure(status, trackedStatus, errorMessage string, statusMessages []arrStatusMessage) string {
+	failed := strings.EqualFold(status, "failed") || strings.EqualFold(trackedStatus, "error")
+
+	var messages []string
+	if errorMessage != "" {
+		messages = append(messages, errorMessage)
+	}
+	for _, statusMessage := range statusMessages {
+		for _, message := range statusMessage.Messages {
+			if strings.Contains(strings.ToLower(message), "repair") {
+				failed = true
+			}
+			messages = append(messages, message)
+		}
+	}
+
+	if !failed {
+		return ""
+	}
+	if len(messages) == 0 {
+		return "Download failed"
+	}
+	return strings.Join(messages, "; ")
+}
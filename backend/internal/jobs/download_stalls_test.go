@@ -0,0 +1,380 @@
+package jobs
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mahcks/serra/config"
+	"github.com/mahcks/serra/internal/db/dbtest"
+	"github.com/mahcks/serra/internal/db/repository"
+	"github.com/mahcks/serra/internal/global"
+	"github.com/mahcks/serra/internal/services/notifications"
+	"github.com/mahcks/serra/pkg/downloadclient"
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+// fakeDownloadClient is a download client whose downloads the test changes
+// between polls
+type fakeDownloadClient struct {
+	items map[string]*downloadclient.Item
+}
+
+func newFakeDownloadClient(items ...downloadclient.Item) *fakeDownloadClient {
+	c := &fakeDownloadClient{items: make(map[string]*downloadclient.Item)}
+	for i := range items {
+		c.items[items[i].ID] = &items[i]
+	}
+	return c
+}
+
+func (c *fakeDownloadClient) GetType() string                      { return "fake" }
+func (c *fakeDownloadClient) GetName() string                      { return "Fake" }
+func (c *fakeDownloadClient) Connect(ctx context.Context) error    { return nil }
+func (c *fakeDownloadClient) Disconnect(ctx context.Context) error { return nil }
+func (c *fakeDownloadClient) IsConnected() bool                    { return true }
+func (c *fakeDownloadClient) GetConnectionInfo() downloadclient.ConnectionInfo {
+	return downloadclient.ConnectionInfo{Connected: true}
+}
+
+func (c *fakeDownloadClient) SetRateLimits(ctx context.Context, limits downloadclient.RateLimits) error {
+	return nil
+}
+
+func (c *fakeDownloadClient) GetDownloads(ctx context.Context) ([]downloadclient.Item, error) {
+	items := make([]downloadclient.Item, 0, len(c.items))
+	for _, item := range c.items {
+		items = append(items, *item)
+	}
+	return items, nil
+}
+
+func (c *fakeDownloadClient) GetDownloadProgress(ctx context.Context, downloadID string) (*downloadclient.Progress, error) {
+	item := c.items[downloadID]
+	return &downloadclient.Progress{Progress: item.Progress, Status: item.Status}, nil
+}
+
+var _ downloadclient.Interface = (*fakeDownloadClient)(nil)
+
+// stallPoll is one poll of the detector, reporting what it found per download
+type stallPoll map[string]struct {
+	reason structures.DownloadStallReason
+	isNew  bool
+}
+
+// poll checks every download of the client like the poller does, with queue
+// failures reported by Radarr/Sonarr by download id
+func pollStalls(t *testing.T, detector *stallDetector, policy structures.DownloadStallPolicy, client downloadclient.Interface, failures map[string]string, now time.Time) stallPoll {
+	t.Helper()
+	items, err := client.GetDownloads(context.Background())
+	if err != nil {
+		t.Fatalf("get downloads: %v", err)
+	}
+
+	result := make(stallPoll)
+	active := make(map[string]bool)
+	for i := range items {
+		item := &items[i]
+		reason, isNew := detector.check(policy, item.ID, item, failures[item.ID], now)
+		result[item.ID] = struct {
+			reason structures.DownloadStallReason
+			isNew  bool
+		}{reason, isNew}
+		active[item.ID] = true
+	}
+	detector.forget(active)
+	return result
+}
+
+func count(n int) *int {
+	return &n
+}
+
+func TestStallDetector(t *testing.T) {
+	policy := structures.DownloadStallPolicy{
+		NoProgressMinutes: 60,
+		NoPeersMinutes:    30,
+		MetadataMinutes:   20,
+		DetectFailures:    true,
+	}
+
+	tests := []struct {
+		name string
+		item downloadclient.Item
+		// change is applied before each poll after the first
+		change  func(item *downloadclient.Item)
+		failure string
+		want    structures.DownloadStallReason
+		after   time.Duration // When the stall is first reported
+	}{
+		{
+			name:  "no progress",
+			item:  downloadclient.Item{Progress: 40, Status: "downloading", Seeds: count(3), Peers: count(1)},
+			want:  structures.DownloadStallNoProgress,
+			after: 60 * time.Minute,
+		},
+		{
+			name:  "no seeds or peers",
+			item:  downloadclient.Item{Progress: 40, Status: "downloading", Seeds: count(0), Peers: count(0)},
+			want:  structures.DownloadStallNoPeers,
+			after: 30 * time.Minute,
+		},
+		{
+			name:  "fetching metadata",
+			item:  downloadclient.Item{Status: "downloading", State: "metaDL", Seeds: count(0), Peers: count(0)},
+			want:  structures.DownloadStallMetadata,
+			after: 20 * time.Minute,
+		},
+		{
+			name:  "SABnzbd failure",
+			item:  downloadclient.Item{Progress: 100, Status: "Failed"},
+			want:  structures.DownloadStallFailed,
+			after: 0,
+		},
+		{
+			name:    "failure reported by the arr",
+			item:    downloadclient.Item{Progress: 100, Status: "completed"},
+			failure: "Repair failed, not enough repair blocks",
+			want:    structures.DownloadStallFailed,
+			after:   0,
+		},
+		{
+			name:   "progressing",
+			item:   downloadclient.Item{Progress: 1, Status: "downloading", Seeds: count(0), Peers: count(2)},
+			change: func(item *downloadclient.Item) { item.Progress++ },
+		},
+		{
+			name: "paused",
+			item: downloadclient.Item{Progress: 40, Status: "paused", Seeds: count(0), Peers: count(0)},
+		},
+		{
+			name: "usenet without peer counts",
+			item: downloadclient.Item{Progress: 40, Status: "downloading"},
+			// Only the no progress check applies
+			want:  structures.DownloadStallNoProgress,
+			after: 60 * time.Minute,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.item.ID = "download"
+			client := newFakeDownloadClient(test.item)
+			failures := map[string]string{"download": test.failure}
+			detector := newStallDetector()
+			start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+			reported := 0
+			var firstReport time.Duration = -1
+			for elapsed := time.Duration(0); elapsed <= 3*time.Hour; elapsed += 5 * time.Minute {
+				if elapsed > 0 && test.change != nil {
+					test.change(client.items["download"])
+				}
+				result := pollStalls(t, detector, policy, client, failures, start.Add(elapsed))["download"]
+
+				if result.reason != "" && firstReport < 0 {
+					firstReport = elapsed
+					if result.reason != test.want {
+						t.Fatalf("expected %q, got %q after %s", test.want, result.reason, elapsed)
+					}
+				}
+				if firstReport >= 0 && result.reason != test.want {
+					t.Fatalf("stall changed to %q after %s", result.reason, elapsed)
+				}
+				if result.isNew {
+					reported++
+				}
+			}
+
+			if test.want == "" {
+				if firstReport >= 0 {
+					t.Fatalf("expected no stall, got one after %s", firstReport)
+				}
+				return
+			}
+			if firstReport != test.after {
+				t.Fatalf("expected the stall after %s, got %s", test.after, firstReport)
+			}
+			if reported != 1 {
+				t.Fatalf("stall should be reported once, was %d times", reported)
+			}
+		})
+	}
+}
+
+func TestStallDetectorReportsAgainAfterRecovery(t *testing.T) {
+	policy := DefaultDownloadStallPolicy()
+	client := newFakeDownloadClient(downloadclient.Item{ID: "download", Progress: 10, Status: "downloading", Seeds: count(0), Peers: count(0)})
+	detector := newStallDetector()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	pollStalls(t, detector, policy, client, nil, now)
+	now = now.Add(30 * time.Minute)
+	if result := pollStalls(t, detector, policy, client, nil, now)["download"]; result.reason != structures.DownloadStallNoPeers || !result.isNew {
+		t.Fatalf("expected a new no peers stall, got %+v", result)
+	}
+
+	// Peers come back and the download moves
+	item := client.items["download"]
+	item.Seeds, item.Progress = count(4), 20
+	now = now.Add(5 * time.Minute)
+	if result := pollStalls(t, detector, policy, client, nil, now)["download"]; result.reason != "" {
+		t.Fatalf("expected the download to recover, got %+v", result)
+	}
+
+	item.Seeds = count(0)
+	pollStalls(t, detector, policy, client, nil, now.Add(5*time.Minute))
+	if result := pollStalls(t, detector, policy, client, nil, now.Add(35*time.Minute))["download"]; !result.isNew {
+		t.Fatalf("a stall after recovering should be reported again, got %+v", result)
+	}
+}
+
+func TestStallDetectorForgetsRemovedDownloads(t *testing.T) {
+	policy := DefaultDownloadStallPolicy()
+	client := newFakeDownloadClient(downloadclient.Item{ID: "download", Progress: 10, Status: "downloading"})
+	detector := newStallDetector()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	pollStalls(t, detector, policy, client, nil, now)
+	delete(client.items, "download")
+	pollStalls(t, detector, policy, client, nil, now.Add(time.Minute))
+	if len(detector.states) != 0 {
+		t.Fatalf("removed download is still tracked")
+	}
+
+	// Added again it starts over
+	client.items["download"] = &downloadclient.Item{ID: "download", Progress: 10, Status: "downloading"}
+	if result := pollStalls(t, detector, policy, client, nil, now.Add(2*time.Hour))["download"]; result.reason != "" {
+		t.Fatalf("expected a fresh start, got %+v", result)
+	}
+}
+
+func TestStallDetectorIgnoresFailuresWhenDisabled(t *testing.T) {
+	policy := DefaultDownloadStallPolicy()
+	policy.DetectFailures = false
+	client := newFakeDownloadClient(downloadclient.Item{ID: "download", Progress: 100, Status: "Failed"})
+
+	result := pollStalls(t, newStallDetector(), policy, client, map[string]string{"download": "Download failed"}, time.Now())["download"]
+	if result.reason != "" {
+		t.Fatalf("expected failures to be ignored, got %q", result.reason)
+	}
+}
+
+func TestArrQueueFailure(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        string
+		trackedStatus string
+		errorMessage  string
+		messages      []arrStatusMessage
+		want          string
+	}{
+		{"healthy", "downloading", "ok", "", nil, ""},
+		{"failed without a message", "failed", "", "", nil, "Download failed"},
+		{"tracked error", "completed", "error", "Not enough free space", nil, "Not enough free space"},
+		{"repair error", "completed", "warning", "", []arrStatusMessage{{Messages: []string{"Repair failed"}}}, "Repair failed"},
+		{"warning only", "completed", "warning", "", []arrStatusMessage{{Messages: []string{"No files found"}}}, ""},
+		{"all messages", "failed", "", "Aborted", []arrStatusMessage{{Messages: []string{"a", "b"}}}, "Aborted; a; b"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := arrQueueFailure(test.status, test.trackedStatus, test.errorMessage, test.messages); got != test.want {
+				t.Fatalf("expected %q, got %q", test.want, got)
+			}
+		})
+	}
+}
+
+func TestParseDownloadStallPolicy(t *testing.T) {
+	policy, err := ParseDownloadStallPolicy(`{"no_peers_minutes": 10, "auto_remediate": true}`)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	want := DefaultDownloadStallPolicy()
+	want.NoPeersMinutes, want.AutoRemediate = 10, true
+	if policy != want {
+		t.Fatalf("expected %+v, got %+v", want, policy)
+	}
+
+	if _, err := ParseDownloadStallPolicy(`{"metadata_minutes": -1}`); err == nil {
+		t.Fatal("negative minutes should be rejected")
+	}
+	if _, err := ParseDownloadStallPolicy(`nope`); err == nil {
+		t.Fatal("invalid JSON should be rejected")
+	}
+}
+
+func TestStalledDownloadRemediationIsRetried(t *testing.T) {
+	gctx := global.New(context.Background(), &config.Bootstrap{}, "test", "")
+	gctx.Crate().Sqlite = dbtest.Service(t)
+	query := gctx.Crate().Sqlite.Query()
+	gctx.Crate().NotificationService = notifications.NewService(query)
+	ctx := context.Background()
+
+	if _, err := gctx.Crate().Sqlite.DB().Exec(`INSERT INTO users (id, username) VALUES ('admin', 'admin');
+		INSERT INTO user_permissions (user_id, permission_id) VALUES ('admin', 'owner')`); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+	if err := query.UpsertSetting(ctx, repository.UpsertSettingParams{
+		Key:   structures.SettingDownloadStallPolicy.String(),
+		Value: `{"auto_remediate": true}`,
+	}); err != nil {
+		t.Fatalf("set stall policy: %v", err)
+	}
+
+	// Radarr refuses the first removal
+	var removals atomic.Int32
+	radarr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete || r.URL.Path != "/api/v3/queue/7" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if removals.Add(1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer radarr.Close()
+	instances := []repository.ArrService{{ID: "radarr", Name: "Radarr", BaseUrl: radarr.URL}}
+
+	poller := &DownloadPoller{BaseJob: NewBaseJob(gctx, structures.JobDownloadPoller, JobConfig{}), stalls: newStallDetector()}
+	download := Download{ID: "radarr_abc", Title: "Movie", ArrServiceID: "radarr", QueueIDs: []int{7}, QueueFailure: "Download failed"}
+	notified := func(want int64) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			count, err := query.CountUnreadNotifications(ctx, "admin")
+			if err != nil {
+				t.Fatalf("count notifications: %v", err)
+			}
+			if count == want {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("admin has %d notifications, want %d", count, want)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	if stalled, remediated := poller.detectStalls(ctx, []Download{download}, instances, nil); stalled != 1 || remediated != 0 {
+		t.Fatalf("first poll: stalled %d, remediated %d", stalled, remediated)
+	}
+	notified(1)
+
+	// The next poll retries the removal and reports the fix
+	if stalled, remediated := poller.detectStalls(ctx, []Download{download}, instances, nil); stalled != 0 || remediated != 1 {
+		t.Fatalf("second poll: stalled %d, remediated %d", stalled, remediated)
+	}
+	notified(2)
+
+	// Once removed it isn't removed again
+	if _, remediated := poller.detectStalls(ctx, []Download{download}, instances, nil); remediated != 0 {
+		t.Fatalf("third poll remediated %d", remediated)
+	}
+	if n := removals.Load(); n != 2 {
+		t.Fatalf("queue item removed %d times, want 2", n)
+	}
+	notified(2)
+}
@@ -0,0 +1,112 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"slices"
+
+	"github.com/mahcks/serra/internal/db/repository"
+	"github.com/mahcks/serra/pkg/permissions"
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+// DownloadVisibility returns the configured download visibility, all when unset
+func DownloadVisibility(ctx context.Context, query *repository.Queries) structures.DownloadVisibility {
+	value, err := query.GetSetting(ctx, structures.SettingDownloadVisibility.String())
+	if err != nil || value != string(structures.DownloadVisibilityOwn) {
+		return structures.DownloadVisibilityAll
+	}
+	return structures.DownloadVisibilityOwn
+}
+
+// SeesAllDownloads reports whether a user sees every download even when
+// visibility is limited to their own. Admins, owners and users holding
+// requests.view do.
+func SeesAllDownloads(ctx context.Context, query *repository.Queries, userID string, isAdmin bool) (bool, error) {
+	if isAdmin {
+		return true, nil
+	}
+
+	userPermissions, err := query.GetUserPermissions(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, userPerm := range userPermissions {
+		if userPerm.PermissionID == permissions.Owner || userPerm.PermissionID == permissions.RequestsView {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// DownloadOwners returns, per download id, the users whose requests the
+// download fulfils. Downloads are linked to requests by TMDB id and, for
+// shows, by season.
+func DownloadOwners(ctx context.Context, query *repository.Queries) (map[string]map[string]bool, error) {
+	rows, err := query.ListDownloadRequesters(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	owners := make(map[string]map[string]bool)
+	for _, row := range rows {
+		if !downloadCoversRequest(row) {
+			continue
+		}
+
+		users, ok := owners[row.DownloadID]
+		if !ok {
+			users = make(map[string]bool)
+			owners[row.DownloadID] = users
+		}
+		users[row.UserID] = true
+		if row.OnBehalfOf.Valid && row.OnBehalfOf.String != "" {
+			users[row.OnBehalfOf.String] = true
+		}
+	}
+	return owners, nil
+}
+
+// downloadCoversRequest reports whether a download is for one of the seasons a
+// request asked for
+func downloadCoversRequest(row repository.ListDownloadRequestersRow) bool {
+	var downloaded []int
+	if row.SeasonNumber.Valid {
+		downloaded = []int{int(row.SeasonNumber.Int64)}
+	}
+	return coversSeasons(requestSeasons(row.Seasons, row.Episodes), downloaded)
+}
+
+// requestSeasons returns the seasons a TV request covers, either directly or
+// through its requested episodes. It is empty for whole-series requests.
+func requestSeasons(seasons, episodes sql.NullString) []int {
+	if episodes.Valid && episodes.String != "" {
+		var requested structures.RequestedEpisodes
+		if err := json.Unmarshal([]byte(episodes.String), &requested); err == nil {
+			return requested.Seasons()
+		}
+	}
+
+	var requested []int
+	if seasons.Valid && seasons.String != "" {
+		_ = json.Unmarshal([]byte(seasons.String), &requested)
+	}
+	return requested
+}
+
+// coversSeasons reports whether a request for the requested seasons covers any
+// of the downloaded seasons. Whole-series requests and downloads without a
+// known season always match.
+func coversSeasons(requested, downloaded []int) bool {
+	if len(requested) == 0 || len(downloaded) == 0 {
+		return true
+	}
+	for _, season := range downloaded {
+		if slices.Contains(requested, season) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,345 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	fastws "github.com/fasthttp/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/mahcks/serra/config"
+	"github.com/mahcks/serra/internal/db/dbtest"
+	"github.com/mahcks/serra/internal/db/repository"
+	"github.com/mahcks/serra/internal/global"
+	"github.com/mahcks/serra/internal/services/auth"
+	"github.com/mahcks/serra/internal/websocket"
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+func TestCoversSeasons(t *testing.T) {
+	tests := []struct {
+		name       string
+		requested  []int
+		downloaded []int
+		want       bool
+	}{
+		{"whole series request", nil, []int{3}, true},
+		{"season pack of unknown season", []int{1, 2}, nil, true},
+		{"requested season", []int{1, 2}, []int{2}, true},
+		{"other season", []int{1, 2}, []int{3}, false},
+		{"one of several seasons", []int{4}, []int{3, 4}, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := coversSeasons(test.requested, test.downloaded); got != test.want {
+				t.Fatalf("coversSeasons(%v, %v) = %v, want %v", test.requested, test.downloaded, got, test.want)
+			}
+		})
+	}
+}
+
+func TestDownloadCoversRequest(t *testing.T) {
+	season := func(n int64) sql.NullInt64 { return sql.NullInt64{Int64: n, Valid: true} }
+	text := func(s string) sql.NullString { return sql.NullString{String: s, Valid: true} }
+
+	tests := []struct {
+		name string
+		row  repository.ListDownloadRequestersRow
+		want bool
+	}{
+		{"movie", repository.ListDownloadRequestersRow{}, true},
+		{"whole series", repository.ListDownloadRequestersRow{SeasonNumber: season(2)}, true},
+		{"empty seasons", repository.ListDownloadRequestersRow{SeasonNumber: season(2), Seasons: text("")}, true},
+		{"requested season", repository.ListDownloadRequestersRow{SeasonNumber: season(2), Seasons: text("[1,2]")}, true},
+		{"other season", repository.ListDownloadRequestersRow{SeasonNumber: season(3), Seasons: text("[1,2]")}, false},
+		{"unknown season", repository.ListDownloadRequestersRow{Seasons: text("[1,2]")}, true},
+		{"requested episodes", repository.ListDownloadRequestersRow{SeasonNumber: season(3), Episodes: text(`{"3":[1,2]}`)}, true},
+		{"episodes of another season", repository.ListDownloadRequestersRow{SeasonNumber: season(2), Episodes: text(`{"3":[1,2]}`)}, false},
+		{"episodes take precedence", repository.ListDownloadRequestersRow{SeasonNumber: season(2), Seasons: text("[2]"), Episodes: text(`{"3":[1]}`)}, false},
+		{"invalid episodes fall back to seasons", repository.ListDownloadRequestersRow{SeasonNumber: season(2), Seasons: text("[2]"), Episodes: text("nope")}, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := downloadCoversRequest(test.row); got != test.want {
+				t.Fatalf("downloadCoversRequest(%+v) = %v, want %v", test.row, got, test.want)
+			}
+		})
+	}
+}
+
+// seedVisibility stores requests and downloads of several users:
+//   - alice requested movie 100 and bob requested movie 300 on her behalf
+//   - bob requested season 1 of show 200, which has downloads of seasons 1 and 2
+//   - carol's request for movie 100 was denied
+//   - viewer holds requests.view
+func seedVisibility(t *testing.T, db *sql.DB) {
+	t.Helper()
+	statements := []string{
+		`INSERT INTO users (id, username) VALUES ('alice', 'alice'), ('bob', 'bob'), ('carol', 'carol'), ('viewer', 'viewer'), ('admin', 'admin')`,
+		`INSERT INTO user_permissions (user_id, permission_id) VALUES ('viewer', 'requests.view')`,
+		`INSERT INTO requests (user_id, media_type, tmdb_id, title, status, seasons, on_behalf_of) VALUES
+			('alice', 'movie', 100, 'Movie 100', 'approved', NULL, NULL),
+			('bob', 'tv', 200, 'Show 200', 'approved', '[1]', NULL),
+			('bob', 'movie', 300, 'Movie 300', 'approved', NULL, 'alice'),
+			('carol', 'movie', 100, 'Movie 100', 'denied', NULL, NULL)`,
+		`INSERT INTO downloads (id, title, torrent_title, source, tmdb_id, season_number) VALUES
+			('radarr-100', 'Movie 100', 'movie.100', 'radarr', 100, NULL),
+			('sonarr-200-1', 'Show 200', 'show.200.s01', 'sonarr', 200, 1),
+			('sonarr-200-2', 'Show 200', 'show.200.s02', 'sonarr', 200, 2),
+			('radarr-300', 'Movie 300', 'movie.300', 'radarr', 300, NULL)`,
+	}
+	for _, statement := range statements {
+		if _, err := db.Exec(statement); err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+}
+
+var allSeededDownloads = []string{"radarr-100", "radarr-300", "sonarr-200-1", "sonarr-200-2"}
+
+func setDownloadVisibility(t *testing.T, db *sql.DB, visibility structures.DownloadVisibility) {
+	t.Helper()
+	_, err := db.Exec(`INSERT INTO settings (key, value) VALUES (?1, ?2) ON CONFLICT (key) DO UPDATE SET value = excluded.value`,
+		structures.SettingDownloadVisibility.String(), string(visibility))
+	if err != nil {
+		t.Fatalf("set download visibility: %v", err)
+	}
+}
+
+func TestDownloadOwners(t *testing.T) {
+	db, query := dbtest.Open(t)
+	seedVisibility(t, db)
+
+	owners, err := DownloadOwners(context.Background(), query)
+	if err != nil {
+		t.Fatalf("download owners: %v", err)
+	}
+
+	want := map[string][]string{
+		"radarr-100":   {"alice"},
+		"sonarr-200-1": {"bob"},
+		"radarr-300":   {"alice", "bob"},
+	}
+	if len(owners) != len(want) {
+		t.Fatalf("expected owners of %d downloads, got %v", len(want), owners)
+	}
+	for downloadID, users := range want {
+		var got []string
+		for user := range owners[downloadID] {
+			got = append(got, user)
+		}
+		slices.Sort(got)
+		if !slices.Equal(got, users) {
+			t.Errorf("owners of %s: expected %v, got %v", downloadID, users, got)
+		}
+	}
+}
+
+func TestSeesAllDownloads(t *testing.T) {
+	db, query := dbtest.Open(t)
+	seedVisibility(t, db)
+	ctx := context.Background()
+
+	for _, test := range []struct {
+		userID  string
+		isAdmin bool
+		want    bool
+	}{
+		{"alice", false, false},
+		{"viewer", false, true},
+		{"admin", true, true},
+	} {
+		got, err := SeesAllDownloads(ctx, query, test.userID, test.isAdmin)
+		if err != nil || got != test.want {
+			t.Errorf("SeesAllDownloads(%s) = %v (%v), want %v", test.userID, got, err, test.want)
+		}
+	}
+}
+
+// progressWatcher is a websocket client collecting the download ids of the
+// progress batches it's sent
+type progressWatcher struct {
+	batches chan []string
+}
+
+func watchProgress(t *testing.T, url string, authService auth.Authmen, userID string, isAdmin bool) *progressWatcher {
+	t.Helper()
+
+	token, _, err := authService.CreateAccessToken(userID, userID, "", isAdmin)
+	if err != nil {
+		t.Fatalf("create token: %v", err)
+	}
+	conn, _, err := fastws.DefaultDialer.Dial(url, http.Header{"Cookie": {auth.CookieAuth + "=" + token}})
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	hello := make(chan struct{})
+	w := &progressWatcher{batches: make(chan []string, 10)}
+	go func() {
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			msg, err := structures.ParseMessage(data)
+			if err != nil {
+				continue
+			}
+			switch msg.Op {
+			case structures.OpcodeHello:
+				close(hello)
+			case structures.OpcodeDownloadProgressBatch:
+				var batch structures.DownloadProgressBatchPayload
+				if err := decodeData(msg, &batch); err != nil {
+					continue
+				}
+				ids := make([]string, 0, len(batch.Downloads))
+				for _, download := range batch.Downloads {
+					ids = append(ids, download.ID)
+				}
+				slices.Sort(ids)
+				w.batches <- ids
+			}
+		}
+	}()
+
+	select {
+	case <-hello:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("%s didn't connect", userID)
+	}
+	return w
+}
+
+// decodeData decodes a message's payload into v
+func decodeData(msg structures.Message, v any) error {
+	data, err := json.Marshal(msg.Data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// expect checks the next batch holds exactly the downloads, or that none
+// arrives when there are none
+func (w *progressWatcher) expect(t *testing.T, userID string, downloads ...string) {
+	t.Helper()
+	timeout := 2 * time.Second
+	if len(downloads) == 0 {
+		timeout = 200 * time.Millisecond
+	}
+
+	select {
+	case got := <-w.batches:
+		if !slices.Equal(got, downloads) {
+			t.Errorf("%s: expected %v, got %v", userID, downloads, got)
+		}
+	case <-time.After(timeout):
+		if len(downloads) > 0 {
+			t.Errorf("%s: expected %v, got nothing", userID, downloads)
+		}
+	}
+}
+
+func TestDownloadProgressDeliveryFollowsVisibility(t *testing.T) {
+	service := dbtest.Service(t)
+	seedVisibility(t, service.DB())
+
+	gctx := global.New(context.Background(), &config.Bootstrap{}, "test", "")
+	gctx.Crate().Sqlite = service
+	gctx.Crate().AuthService = auth.New("test-secret", "localhost", false)
+
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	websocket.RegisterRoutes(gctx, app)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	go app.Listener(listener)
+	t.Cleanup(func() {
+		websocket.CloseAllConnections()
+		_ = app.Shutdown()
+	})
+	url := "ws://" + listener.Addr().String() + "/ws"
+
+	watchers := map[string]*progressWatcher{}
+	for _, user := range []struct {
+		id      string
+		isAdmin bool
+	}{{"alice", false}, {"bob", false}, {"carol", false}, {"viewer", false}, {"admin", true}} {
+		watchers[user.id] = watchProgress(t, url, gctx.Crate().AuthService, user.id, user.isAdmin)
+	}
+
+	poller := &DownloadPoller{BaseJob: NewBaseJob(gctx, structures.JobDownloadPoller, JobConfig{})}
+	batch := make([]structures.DownloadProgressPayload, 0, len(allSeededDownloads))
+	for _, id := range allSeededDownloads {
+		batch = append(batch, structures.DownloadProgressPayload{ID: id})
+	}
+
+	t.Run("own", func(t *testing.T) {
+		setDownloadVisibility(t, service.DB(), structures.DownloadVisibilityOwn)
+		poller.sendDownloadProgressBatch(batch)
+
+		watchers["alice"].expect(t, "alice", "radarr-100", "radarr-300")
+		watchers["bob"].expect(t, "bob", "radarr-300", "sonarr-200-1")
+		watchers["viewer"].expect(t, "viewer", allSeededDownloads...)
+		watchers["admin"].expect(t, "admin", allSeededDownloads...)
+		watchers["carol"].expect(t, "carol")
+	})
+
+	t.Run("all", func(t *testing.T) {
+		setDownloadVisibility(t, service.DB(), structures.DownloadVisibilityAll)
+		poller.sendDownloadProgressBatch(batch)
+
+		for userID, watcher := range watchers {
+			watcher.expect(t, userID, allSeededDownloads...)
+		}
+	})
+}
+
+func TestLinkRequestsMatchesQuality(t *testing.T) {
+	gctx := global.New(context.Background(), &config.Bootstrap{}, "test", "")
+	gctx.Crate().Sqlite = dbtest.Service(t)
+	statements := []string{
+		`INSERT INTO users (id, username) VALUES ('alice', 'alice'), ('bob', 'bob')`,
+		`INSERT INTO requests (id, user_id, media_type, tmdb_id, title, status, is_4k, created_at) VALUES
+			(1, 'alice', 'movie', 100, 'Movie', 'approved', FALSE, datetime('now', '-1 hour')),
+			(2, 'bob', 'movie', 100, 'Movie', 'approved', TRUE, datetime('now', '-2 hours')),
+			(3, 'bob', 'tv', 200, 'Show', 'approved', TRUE, datetime('now'))`,
+	}
+	for _, statement := range statements {
+		if _, err := gctx.Crate().Sqlite.DB().Exec(statement); err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+
+	radarr := []repository.ArrService{{ID: "radarr-hd"}, {ID: "radarr-4k", Is4k: true}}
+	sonarr := []repository.ArrService{{ID: "sonarr-hd"}}
+	tmdbID := func(id int64) *int64 { return &id }
+	downloads := []Download{
+		{ID: "hd", Source: "radarr", TmdbID: tmdbID(100), ArrServiceID: "radarr-hd"},
+		{ID: "4k", Source: "radarr", TmdbID: tmdbID(100), ArrServiceID: "radarr-4k"},
+		{ID: "show", Source: "sonarr", TmdbID: tmdbID(200), ArrServiceID: "sonarr-hd"},
+	}
+
+	poller := &DownloadPoller{BaseJob: NewBaseJob(gctx, structures.JobDownloadPoller, JobConfig{})}
+	poller.linkRequests(context.Background(), downloads, radarr, sonarr)
+
+	want := map[string]int64{"hd": 1, "4k": 2}
+	for _, download := range downloads {
+		var got int64
+		if download.RequestID != nil {
+			got = *download.RequestID
+		}
+		if got != want[download.ID] {
+			t.Errorf("%s: linked to request %d, want %d", download.ID, got, want[download.ID])
+		}
+	}
+}
@@ -12,60 +12,125 @@ import (
 // Default job configurations
 var defaultConfigs = map[structures.Job]JobConfig{
 	structures.JobDownloadPoller: {
-		Enabled:      true,
-		Interval:     10 * time.Second,
-		MaxRetries:   3,
-		RetryDelay:   5 * time.Second,
-		Timeout:      2 * time.Minute,
-		RunOnStartup: true,
+		Enabled:               true,
+		Interval:              10 * time.Second,
+		MaxRetries:            3,
+		RetryDelay:            5 * time.Second,
+		Timeout:               2 * time.Minute,
+		RunOnStartup:          true,
+		FailureAlertThreshold: 10,
+		RecordChangesOnly:     true,
 	},
 	structures.JobDriveMonitor: {
-		Enabled:      true,
-		Interval:     5 * time.Minute,
-		MaxRetries:   2,
-		RetryDelay:   30 * time.Second,
-		Timeout:      1 * time.Minute,
-		RunOnStartup: false,
+		Enabled:               true,
+		Interval:              5 * time.Minute,
+		MaxRetries:            2,
+		RetryDelay:            30 * time.Second,
+		Timeout:               1 * time.Minute,
+		RunOnStartup:          false,
+		FailureAlertThreshold: 3,
 	},
 	structures.JobRequestProcessor: {
-		Enabled:      true,
-		Interval:     20 * time.Second,
-		MaxRetries:   3,
-		RetryDelay:   30 * time.Second,
-		Timeout:      1 * time.Minute,
-		RunOnStartup: false,
+		Enabled:               true,
+		Interval:              20 * time.Second,
+		MaxRetries:            3,
+		RetryDelay:            30 * time.Second,
+		Timeout:               1 * time.Minute,
+		RunOnStartup:          false,
+		FailureAlertThreshold: 5,
+		RecordChangesOnly:     true,
 	},
 	structures.JobLibrarySyncFull: {
-		Enabled:      false,          // Disabled by default, only enabled in dev
-		Interval:     24 * time.Hour, // Full sync every 24 hours
-		MaxRetries:   2,
-		RetryDelay:   10 * time.Minute,
-		Timeout:      15 * time.Minute,
-		RunOnStartup: false, // Don't run on startup by default
+		Enabled:               false,          // Disabled by default, only enabled in dev
+		Interval:              24 * time.Hour, // Full sync every 24 hours
+		MaxRetries:            2,
+		RetryDelay:            10 * time.Minute,
+		Timeout:               15 * time.Minute,
+		RunOnStartup:          false, // Don't run on startup by default
+		FailureAlertThreshold: 2,
 	},
 	structures.JobLibrarySyncIncremental: {
-		Enabled:      false,            // Disabled by default, only enabled in dev
-		Interval:     15 * time.Minute, // Incremental sync every 15 minutes
-		MaxRetries:   3,
-		RetryDelay:   2 * time.Minute,
-		Timeout:      5 * time.Minute,
-		RunOnStartup: false, // Don't run on startup
+		Enabled:               false,            // Disabled by default, only enabled in dev
+		Interval:              15 * time.Minute, // Incremental sync every 15 minutes
+		MaxRetries:            3,
+		RetryDelay:            2 * time.Minute,
+		Timeout:               5 * time.Minute,
+		RunOnStartup:          false, // Don't run on startup
+		FailureAlertThreshold: 3,
 	},
 	structures.JobInvitationCleanup: {
-		Enabled:      true,
-		Interval:     1 * time.Hour, // Clean up expired invitations every hour
-		MaxRetries:   2,
-		RetryDelay:   10 * time.Minute,
-		Timeout:      30 * time.Second,
-		RunOnStartup: false, // Don't run on startup
+		Enabled:               true,
+		Interval:              1 * time.Hour, // Clean up expired invitations every hour
+		MaxRetries:            2,
+		RetryDelay:            10 * time.Minute,
+		Timeout:               30 * time.Second,
+		RunOnStartup:          false, // Don't run on startup
+		FailureAlertThreshold: 3,
 	},
 	structures.JobNotificationCleanup: {
-		Enabled:      true,
-		Interval:     1 * time.Hour, // Clean up expired notifications every hour
-		MaxRetries:   2,
-		RetryDelay:   10 * time.Minute,
-		Timeout:      30 * time.Second,
-		RunOnStartup: false, // Don't run on startup
+		Enabled:               true,
+		Interval:              1 * time.Hour, // Clean up expired notifications every hour
+		MaxRetries:            2,
+		RetryDelay:            10 * time.Minute,
+		Timeout:               30 * time.Second,
+		RunOnStartup:          false, // Don't run on startup
+		FailureAlertThreshold: 3,
+	},
+	structures.JobRunCleanup: {
+		Enabled:               true,
+		Interval:              6 * time.Hour, // Prune old job run history every 6 hours
+		MaxRetries:            2,
+		RetryDelay:            10 * time.Minute,
+		Timeout:               1 * time.Minute,
+		RunOnStartup:          false, // Don't run on startup
+		FailureAlertThreshold: 3,
+	},
+	structures.JobDatabaseBackup: {
+		Enabled:               true,
+		Interval:              24 * time.Hour,
+		Schedule:              "0 3 * * *", // Back up the database daily at 03:00
+		MaxRetries:            1,
+		RetryDelay:            5 * time.Minute,
+		Timeout:               10 * time.Minute,
+		RunOnStartup:          false, // Don't run on startup
+		FailureAlertThreshold: 2,
+	},
+	structures.JobNewSeasonRequests: {
+		Enabled:               true,
+		Interval:              12 * time.Hour, // Check TMDB for renewed shows twice a day
+		MaxRetries:            2,
+		RetryDelay:            10 * time.Minute,
+		Timeout:               10 * time.Minute,
+		RunOnStartup:          false, // Don't run on startup
+		FailureAlertThreshold: 3,
+	},
+	structures.JobReleaseWatcher: {
+		Enabled:               true,
+		Interval:              6 * time.Hour, // Check TMDB for newly released movies four times a day
+		MaxRetries:            2,
+		RetryDelay:            10 * time.Minute,
+		Timeout:               10 * time.Minute,
+		RunOnStartup:          false, // Don't run on startup
+		FailureAlertThreshold: 3,
+	},
+	structures.JobRequestSLA: {
+		Enabled:               true,
+		Interval:              1 * time.Hour, // Check for requests waiting past the approval SLA hourly
+		MaxRetries:            2,
+		RetryDelay:            10 * time.Minute,
+		Timeout:               5 * time.Minute,
+		RunOnStartup:          false, // Don't run on startup
+		FailureAlertThreshold: 3,
+	},
+	structures.JobBandwidthManager: {
+		Enabled:               true,
+		Interval:              1 * time.Minute, // Follow the bandwidth schedule and remote streams closely
+		MaxRetries:            1,
+		RetryDelay:            30 * time.Second,
+		Timeout:               30 * time.Second,
+		RunOnStartup:          true, // Apply the current limits right away
+		FailureAlertThreshold: 5,
+		RecordChangesOnly:     true,
 	},
 }
 
@@ -100,6 +165,18 @@ func NewJob(name structures.Job, gctx global.Context, integrations *integrations
 		return NewInvitationCleanup(gctx, config)
 	case structures.JobNotificationCleanup:
 		return NewNotificationCleanup(gctx, config)
+	case structures.JobRunCleanup:
+		return NewJobRunCleanup(gctx, config)
+	case structures.JobDatabaseBackup:
+		return NewDatabaseBackup(gctx, config)
+	case structures.JobNewSeasonRequests:
+		return NewNewSeasonRequests(gctx, integrations, config)
+	case structures.JobReleaseWatcher:
+		return NewReleaseWatcher(gctx, integrations, config)
+	case structures.JobRequestSLA:
+		return NewRequestSLA(gctx, config)
+	case structures.JobBandwidthManager:
+		return NewBandwidthManager(gctx, integrations, config)
 	default:
 		return nil, fmt.Errorf("unknown job: %s", name)
 	}
@@ -131,6 +208,18 @@ func NewJobWithConfig(name structures.Job, gctx global.Context, integrations *in
 		return NewInvitationCleanup(gctx, config)
 	case structures.JobNotificationCleanup:
 		return NewNotificationCleanup(gctx, config)
+	case structures.JobRunCleanup:
+		return NewJobRunCleanup(gctx, config)
+	case structures.JobDatabaseBackup:
+		return NewDatabaseBackup(gctx, config)
+	case structures.JobNewSeasonRequests:
+		return NewNewSeasonRequests(gctx, integrations, config)
+	case structures.JobReleaseWatcher:
+		return NewReleaseWatcher(gctx, integrations, config)
+	case structures.JobRequestSLA:
+		return NewRequestSLA(gctx, config)
+	case structures.JobBandwidthManager:
+		return NewBandwidthManager(gctx, integrations, config)
 	default:
 		return nil, fmt.Errorf("unknown job: %s", name)
 	}
@@ -138,7 +227,7 @@ func NewJobWithConfig(name structures.Job, gctx global.Context, integrations *in
 
 // AllJobNames returns all available job names
 func AllJobNames() []structures.Job {
-	return []structures.Job{structures.JobDownloadPoller, structures.JobDriveMonitor, structures.JobRequestProcessor, structures.JobLibrarySyncFull, structures.JobLibrarySyncIncremental, structures.JobInvitationCleanup, structures.JobNotificationCleanup}
+	return []structures.Job{structures.JobDownloadPoller, structures.JobDriveMonitor, structures.JobRequestProcessor, structures.JobLibrarySyncFull, structures.JobLibrarySyncIncremental, structures.JobInvitationCleanup, structures.JobNotificationCleanup, structures.JobRunCleanup, structures.JobDatabaseBackup, structures.JobNewSeasonRequests, structures.JobReleaseWatcher, structures.JobRequestSLA, structures.JobBandwidthManager}
 }
 
 // GetDefaultConfig returns the default configuration for a job
@@ -0,0 +1,134 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/mahcks/serra/internal/db/repository"
+	"github.com/mahcks/serra/internal/websocket"
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+// DefaultJobRunRetention is how long job execution history is kept before it is pruned
+const DefaultJobRunRetention = 30 * 24 * time.Hour
+
+// recordRun persists a single job execution and raises a system alert once a
+// job has failed FailureAlertThreshold times in a row
+func (m *Manager) recordRun(job Job, trigger structures.JobRunTrigger, start time.Time, attempts int, runErr error) {
+	// The execution context may already be cancelled or timed out, so use a fresh one
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	name := job.Name()
+	finished := time.Now()
+
+	outcome := structures.JobRunOutcomeSuccess
+	if runErr != nil {
+		outcome = structures.JobRunOutcomeFailed
+		if errors.Is(runErr, context.Canceled) {
+			outcome = structures.JobRunOutcomeCancelled
+		}
+	}
+
+	var summary sql.NullString
+	if summarizer, ok := job.(RunSummarizer); ok {
+		if data := summarizer.RunSummary(); len(data) > 0 {
+			if b, err := json.Marshal(data); err == nil {
+				summary = sql.NullString{String: string(b), Valid: true}
+			}
+		}
+	}
+
+	var errMsg sql.NullString
+	if runErr != nil {
+		errMsg = sql.NullString{String: runErr.Error(), Valid: true}
+	}
+
+	status := structures.JobStatusPayload{
+		Name:       name.String(),
+		Trigger:    string(trigger),
+		Outcome:    string(outcome),
+		Attempts:   attempts,
+		DurationMs: finished.Sub(start).Milliseconds(),
+		FinishedAt: finished.UnixMilli(),
+	}
+	if runErr != nil {
+		status.Error = runErr.Error()
+	}
+	websocket.PublishJobStatus(status)
+
+	// Unchanged runs of frequent jobs are left out of the history, manual runs are always kept
+	if changed := m.changedOutcome(job, outcome); !changed && trigger != structures.JobRunTriggerManual {
+		return
+	}
+
+	query := m.gctx.Crate().Sqlite.Query()
+	err := query.CreateJobRun(ctx, repository.CreateJobRunParams{
+		JobName:     name.String(),
+		TriggerType: string(trigger),
+		StartedAt:   start,
+		FinishedAt:  sql.NullTime{Time: finished, Valid: true},
+		DurationMs:  finished.Sub(start).Milliseconds(),
+		Outcome:     string(outcome),
+		Attempts:    int64(attempts),
+		Error:       errMsg,
+		Summary:     summary,
+	})
+	if err != nil {
+		slog.Error("Failed to record job run", "name", name, "error", err)
+		return
+	}
+
+	threshold := job.Config().FailureAlertThreshold
+	if outcome != structures.JobRunOutcomeFailed || threshold <= 0 {
+		return
+	}
+
+	failures, err := query.CountConsecutiveJobFailures(ctx, repository.CountConsecutiveJobFailuresParams{
+		JobName:   name.String(),
+		JobName_2: name.String(),
+	})
+	if err != nil {
+		slog.Error("Failed to count consecutive job failures", "name", name, "error", err)
+		return
+	}
+
+	// Only alert once per failure streak
+	if failures != int64(threshold) {
+		return
+	}
+
+	slog.Warn("Job failure threshold reached", "name", name, "consecutive_failures", failures)
+
+	notificationService := m.gctx.Crate().NotificationService
+	if notificationService == nil {
+		return
+	}
+
+	title := fmt.Sprintf("Background job %s is failing", name)
+	message := fmt.Sprintf("%s has failed %d times in a row. Last error: %s", name, failures, runErr.Error())
+	if err := notificationService.NotifySystemAlert(ctx, title, message, structures.NotificationPriorityHigh); err != nil {
+		slog.Error("Failed to send job failure alert", "name", name, "error", err)
+	}
+}
+
+// changedOutcome tracks the outcome of each run and reports whether it should
+// be recorded. Jobs with RecordChangesOnly skip successful runs that follow
+// another success.
+func (m *Manager) changedOutcome(job Job, outcome structures.JobRunOutcome) bool {
+	m.outcomesMu.Lock()
+	defer m.outcomesMu.Unlock()
+
+	last, seen := m.outcomes[job.Name()]
+	m.outcomes[job.Name()] = outcome
+
+	if !job.Config().RecordChangesOnly || outcome != structures.JobRunOutcomeSuccess {
+		return true
+	}
+	return !seen || last != outcome
+}
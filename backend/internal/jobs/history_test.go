@@ -0,0 +1,140 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/mahcks/serra/config"
+	"github.com/mahcks/serra/internal/db/dbtest"
+	"github.com/mahcks/serra/internal/db/repository"
+	"github.com/mahcks/serra/internal/global"
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+func newHistoryManager(t *testing.T) (*Manager, *repository.Queries) {
+	t.Helper()
+	gctx := global.New(context.Background(), &config.Bootstrap{}, "test", "")
+	gctx.Crate().Sqlite = dbtest.Service(t)
+	return NewManager(gctx, nil), gctx.Crate().Sqlite.Query()
+}
+
+// recordedOutcomes returns the outcomes in the job's run history, oldest first
+func recordedOutcomes(t *testing.T, query *repository.Queries, name structures.Job) []string {
+	t.Helper()
+	runs, err := query.ListJobRuns(context.Background(), repository.ListJobRunsParams{JobName: name.String(), Limit: 100})
+	if err != nil {
+		t.Fatalf("list job runs: %v", err)
+	}
+	outcomes := make([]string, 0, len(runs))
+	for _, run := range runs {
+		outcomes = append(outcomes, run.Outcome)
+	}
+	slices.Reverse(outcomes)
+	return outcomes
+}
+
+func TestRecordRunKeepsEveryRun(t *testing.T) {
+	manager, query := newHistoryManager(t)
+	job := &countingJob{BaseJob: NewBaseJob(manager.gctx, structures.Job("test_job"), JobConfig{})}
+
+	for range 3 {
+		manager.recordRun(job, structures.JobRunTriggerScheduled, time.Now(), 1, nil)
+	}
+
+	if got := recordedOutcomes(t, query, job.Name()); len(got) != 3 {
+		t.Fatalf("expected 3 runs, got %v", got)
+	}
+}
+
+func TestRecordRunChangesOnly(t *testing.T) {
+	manager, query := newHistoryManager(t)
+	job := &countingJob{BaseJob: NewBaseJob(manager.gctx, structures.Job("frequent_job"), JobConfig{RecordChangesOnly: true})}
+	failure := errors.New("unreachable")
+
+	for _, run := range []struct {
+		trigger structures.JobRunTrigger
+		err     error
+	}{
+		{structures.JobRunTriggerStartup, nil},
+		{structures.JobRunTriggerScheduled, nil},
+		{structures.JobRunTriggerScheduled, failure},
+		{structures.JobRunTriggerScheduled, failure},
+		{structures.JobRunTriggerScheduled, nil},
+		{structures.JobRunTriggerScheduled, nil},
+		{structures.JobRunTriggerManual, nil},
+	} {
+		manager.recordRun(job, run.trigger, time.Now(), 1, run.err)
+	}
+
+	// Repeated successes are skipped, failures and manual runs are always kept
+	want := []string{"success", "failed", "failed", "success", "success"}
+	if got := recordedOutcomes(t, query, job.Name()); !slices.Equal(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestRecordRunCountsFailureStreaks(t *testing.T) {
+	manager, query := newHistoryManager(t)
+	job := &countingJob{BaseJob: NewBaseJob(manager.gctx, structures.Job("frequent_job"), JobConfig{RecordChangesOnly: true})}
+
+	manager.recordRun(job, structures.JobRunTriggerScheduled, time.Now(), 1, errors.New("first"))
+	manager.recordRun(job, structures.JobRunTriggerScheduled, time.Now(), 1, nil)
+	manager.recordRun(job, structures.JobRunTriggerScheduled, time.Now(), 1, nil)
+	manager.recordRun(job, structures.JobRunTriggerScheduled, time.Now(), 1, errors.New("second"))
+	manager.recordRun(job, structures.JobRunTriggerScheduled, time.Now(), 1, errors.New("third"))
+
+	failures, err := query.CountConsecutiveJobFailures(context.Background(), repository.CountConsecutiveJobFailuresParams{
+		JobName:   job.Name().String(),
+		JobName_2: job.Name().String(),
+	})
+	if err != nil {
+		t.Fatalf("count failures: %v", err)
+	}
+	if failures != 2 {
+		t.Fatalf("expected a streak of 2 failures, got %d", failures)
+	}
+}
+
+func TestJobRunCleanupPrunesPastRetention(t *testing.T) {
+	manager, query := newHistoryManager(t)
+	ctx := context.Background()
+
+	now := time.Now()
+	for _, age := range []time.Duration{10 * 24 * time.Hour, 8 * 24 * time.Hour, 6 * 24 * time.Hour, time.Hour} {
+		err := query.CreateJobRun(ctx, repository.CreateJobRunParams{
+			JobName:     "test_job",
+			TriggerType: string(structures.JobRunTriggerScheduled),
+			StartedAt:   now.Add(-age),
+			Outcome:     string(structures.JobRunOutcomeSuccess),
+			Attempts:    1,
+		})
+		if err != nil {
+			t.Fatalf("create job run: %v", err)
+		}
+	}
+	if err := query.UpsertSetting(ctx, repository.UpsertSettingParams{
+		Key:   structures.SettingJobRunRetentionDays.String(),
+		Value: "7",
+	}); err != nil {
+		t.Fatalf("set retention: %v", err)
+	}
+
+	cleanup, err := NewJobRunCleanup(manager.gctx, JobConfig{})
+	if err != nil {
+		t.Fatalf("new job run cleanup: %v", err)
+	}
+	if err := cleanup.Trigger(ctx); err != nil {
+		t.Fatalf("cleanup: %v", err)
+	}
+
+	remaining, err := query.CountJobRuns(ctx, "test_job")
+	if err != nil {
+		t.Fatalf("count job runs: %v", err)
+	}
+	if remaining != 2 {
+		t.Fatalf("expected the 2 runs within 7 days to remain, got %d", remaining)
+	}
+}
@@ -38,6 +38,21 @@ type JobConfig struct {
 	RetryDelay   time.Duration `json:"retry_delay"`
 	Timeout      time.Duration `json:"timeout"`
 	RunOnStartup bool          `json:"run_on_startup"`
+	// FailureAlertThreshold raises a system alert after this many consecutive failed runs (0 = disabled)
+	FailureAlertThreshold int `json:"failure_alert_threshold"`
+
+	// Schedule is an optional cron expression (e.g. "30 3 * * *") used instead of Interval
+	Schedule string `json:"schedule,omitempty"`
+	// Timezone is the IANA timezone the schedule and blackout windows are evaluated in (default: server local time)
+	Timezone string `json:"timezone,omitempty"`
+	// Jitter delays each run by a random duration up to this value
+	Jitter time.Duration `json:"jitter,omitempty"`
+	// Blackouts are recurring windows during which runs are deferred until the window ends
+	Blackouts []structures.JobBlackoutWindow `json:"blackouts,omitempty"`
+
+	// RecordChangesOnly keeps only failed runs and runs whose outcome differs from the last one
+	// in the run history, for jobs that run too often to keep every run
+	RecordChangesOnly bool `json:"record_changes_only,omitempty"`
 }
 
 // Job is the interface all jobs must implement.
@@ -63,3 +78,9 @@ type Job interface {
 	OnError(ctx context.Context, err error)
 	OnSuccess(ctx context.Context, duration time.Duration)
 }
+
+// RunSummarizer is implemented by jobs that report a job-specific summary
+// (items synced, requests processed, ...) for each execution
+type RunSummarizer interface {
+	RunSummary() map[string]interface{}
+}
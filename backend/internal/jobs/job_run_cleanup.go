@@ -0,0 +1,88 @@
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/mahcks/serra/internal/global"
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+// JobRunCleanup job prunes job execution history older than the configured retention
+type JobRunCleanup struct {
+	*BaseJob
+	gctx global.Context
+}
+
+// NewJobRunCleanup creates a new job run cleanup job
+func NewJobRunCleanup(gctx global.Context, config JobConfig) (Job, error) {
+	baseJob := NewBaseJob(gctx, structures.JobRunCleanup, config)
+
+	return &JobRunCleanup{
+		BaseJob: baseJob,
+		gctx:    gctx,
+	}, nil
+}
+
+// Name returns the job name
+func (j *JobRunCleanup) Name() structures.Job {
+	return structures.JobRunCleanup
+}
+
+// Trigger executes the job run cleanup task
+func (j *JobRunCleanup) Trigger(ctx context.Context) error {
+	retention := j.retention(ctx)
+	cutoff := time.Now().Add(-retention)
+
+	slog.Info("Starting job run cleanup job", "retention", retention)
+
+	err := j.gctx.Crate().Sqlite.Query().DeleteJobRunsBefore(ctx, cutoff)
+	if err != nil {
+		slog.Error("Failed to prune job run history", "error", err)
+		return err
+	}
+
+	j.SetRunSummary(map[string]interface{}{
+		"retention_days": int(retention.Hours() / 24),
+		"cutoff":         cutoff.Format(time.RFC3339),
+	})
+
+	slog.Info("Job run cleanup completed successfully", "cutoff", cutoff)
+
+	return nil
+}
+
+// retention returns the configured history retention, falling back to the default
+func (j *JobRunCleanup) retention(ctx context.Context) time.Duration {
+	value, err := j.gctx.Crate().Sqlite.Query().GetSetting(ctx, structures.SettingJobRunRetentionDays.String())
+	if err != nil || value == "" {
+		return DefaultJobRunRetention
+	}
+
+	days, err := strconv.Atoi(value)
+	if err != nil || days <= 0 {
+		slog.Warn("Invalid job run retention setting, using default", "value", value)
+		return DefaultJobRunRetention
+	}
+
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// Start initializes the job
+func (j *JobRunCleanup) Start(ctx context.Context) error {
+	slog.Info("Job run cleanup job started")
+	return nil
+}
+
+// Stop cleans up the job
+func (j *JobRunCleanup) Stop(ctx context.Context) error {
+	slog.Info("Job run cleanup job stopped")
+	return nil
+}
+
+// Health returns the job health status
+func (j *JobRunCleanup) Health() error {
+	return nil // Simple job, always healthy if running
+}
@@ -0,0 +1,155 @@
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mahcks/serra/internal/db/repository"
+)
+
+const (
+	// jobLease is the lease held by the instance running scheduled jobs
+	jobLease = "jobs"
+
+	// leaseTTL is how long a lease lasts without renewal, so how long jobs
+	// pause when the leader dies without releasing it
+	leaseTTL = 30 * time.Second
+)
+
+// leadership tracks whether an instance runs scheduled jobs. Replicas sharing
+// a database elect one leader, a single instance always leads.
+type leadership struct {
+	// Set while another instance holds the job lease
+	follower atomic.Bool
+
+	// Closed and replaced when the instance stops leading
+	lostMu sync.Mutex
+	lost   chan struct{}
+}
+
+func newLeadership() *leadership {
+	return &leadership{lost: make(chan struct{})}
+}
+
+// IsLeader reports whether this instance runs scheduled jobs
+func (l *leadership) IsLeader() bool {
+	return !l.follower.Load()
+}
+
+// lostChan returns a channel that is closed once the instance stops leading
+func (l *leadership) lostChan() <-chan struct{} {
+	l.lostMu.Lock()
+	defer l.lostMu.Unlock()
+	return l.lost
+}
+
+// set records whether the instance leads and reports whether that changed
+func (l *leadership) set(leading bool) bool {
+	l.lostMu.Lock()
+	defer l.lostMu.Unlock()
+
+	if was := !l.follower.Swap(!leading); was == leading {
+		return false
+	}
+	if !leading {
+		close(l.lost)
+		l.lost = make(chan struct{})
+	}
+	return true
+}
+
+// leaderElector competes for the job lease with the other instances sharing
+// the database and keeps renewing it while held
+type leaderElector struct {
+	query  *repository.Queries
+	nodeID string
+	clock  Clock
+	state  *leadership
+
+	// When the lease was last acquired or renewed, it lasts leaseTTL from then
+	lastRenewed time.Time
+}
+
+func newLeaderElector(query *repository.Queries, nodeID string, clock Clock, state *leadership) *leaderElector {
+	return &leaderElector{
+		query:  query,
+		nodeID: nodeID,
+		clock:  clock,
+		state:  state,
+	}
+}
+
+// campaign tries to acquire or renew the lease and records the outcome
+func (e *leaderElector) campaign(ctx context.Context) {
+	now := e.clock.Now().UTC()
+	acquired, err := e.query.AcquireLease(ctx, repository.AcquireLeaseParams{
+		Name:      jobLease,
+		Holder:    e.nodeID,
+		ExpiresAt: now.Add(leaseTTL),
+		Now:       now,
+	})
+	if err != nil {
+		slog.Error("Failed to renew job lease", "node_id", e.nodeID, "error", err)
+
+		// A held lease stays valid until it expires. Once it has, another
+		// instance may hold it, so stop running jobs until renewal succeeds.
+		if e.state.IsLeader() && now.Sub(e.lastRenewed) >= leaseTTL {
+			e.state.set(false)
+			slog.Warn("Job lease expired without renewal, pausing scheduled jobs", "node_id", e.nodeID)
+		}
+		return
+	}
+
+	leading := acquired == 1
+	if leading {
+		e.lastRenewed = now
+	}
+	if e.state.set(leading) {
+		if leading {
+			slog.Info("Became job leader", "node_id", e.nodeID)
+		} else {
+			slog.Info("Following job leader, scheduled jobs run on another instance", "node_id", e.nodeID)
+		}
+	}
+}
+
+// run renews the lease until stopped
+func (e *leaderElector) run(ctx context.Context, stop <-chan struct{}) {
+	ticker := time.NewTicker(leaseTTL / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			e.campaign(ctx)
+		case <-stop:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// resign releases the lease so another instance can take over right away.
+// It runs during shutdown, after the server context is cancelled.
+func (e *leaderElector) resign() {
+	if !e.state.IsLeader() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := e.query.ReleaseLease(ctx, repository.ReleaseLeaseParams{
+		Name:   jobLease,
+		Holder: e.nodeID,
+	})
+	if err != nil {
+		slog.Error("Failed to release job lease", "node_id", e.nodeID, "error", err)
+		return
+	}
+	e.state.set(false)
+}
@@ -0,0 +1,220 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mahcks/serra/config"
+	"github.com/mahcks/serra/internal/db/dbtest"
+	"github.com/mahcks/serra/internal/eventbus"
+	"github.com/mahcks/serra/internal/global"
+	"github.com/mahcks/serra/internal/services/sqlite"
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+// startNode runs a job under a manager of one instance of a deployment sharing
+// the database
+func startNode(t *testing.T, db sqlite.Service, clock *fakeClock, nodeID string, job Job) *Manager {
+	t.Helper()
+
+	gctx := global.New(context.Background(), &config.Bootstrap{}, "test", "")
+	gctx.Crate().Sqlite = db
+	gctx.Crate().EventBus = eventbus.NewMemory(nodeID)
+
+	manager := NewManager(gctx, nil, WithClock(clock))
+	if err := manager.Register(job); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	if err := manager.Start(context.Background()); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = manager.Stop(ctx)
+	})
+	return manager
+}
+
+// awaitTimers waits for n job runners to arm their timers
+func awaitTimers(t *testing.T, clock *fakeClock, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for clock.waiting() < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d of %d job runners waited on the clock", clock.waiting(), n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// blockingJob runs until its context is cancelled and reports why it stopped
+type blockingJob struct {
+	*BaseJob
+	started chan struct{}
+	stopped chan error
+}
+
+func (j *blockingJob) Trigger(ctx context.Context) error {
+	j.started <- struct{}{}
+	<-ctx.Done()
+	j.stopped <- ctx.Err()
+	return ctx.Err()
+}
+
+func TestLeaderElection(t *testing.T) {
+	ctx := context.Background()
+	_, query := dbtest.Open(t)
+	clock := newFakeClock(time.Date(2025, 8, 13, 12, 0, 0, 0, time.UTC))
+
+	a := newLeaderElector(query, "node-a", clock, newLeadership())
+	b := newLeaderElector(query, "node-b", clock, newLeadership())
+	leaders := func() (bool, bool) { return a.state.IsLeader(), b.state.IsLeader() }
+
+	a.campaign(ctx)
+	b.campaign(ctx)
+	if leadsA, leadsB := leaders(); !leadsA || leadsB {
+		t.Fatalf("leaders = %v, %v: the first instance should hold the lease and the second follow", leadsA, leadsB)
+	}
+	aLost := a.state.lostChan()
+
+	clock.Advance(leaseTTL / 3)
+	a.campaign(ctx)
+	if leadsA, leadsB := leaders(); !leadsA || leadsB {
+		t.Fatalf("leaders = %v, %v: the holder should renew its own lease", leadsA, leadsB)
+	}
+
+	// The holder stops renewing, e.g. because it died
+	clock.Advance(leaseTTL + time.Second)
+	b.campaign(ctx)
+	a.campaign(ctx)
+	if leadsA, leadsB := leaders(); leadsA || !leadsB {
+		t.Fatalf("leaders = %v, %v: the second instance should take over the expired lease", leadsA, leadsB)
+	}
+	select {
+	case <-aLost:
+	default:
+		t.Fatal("the previous holder wasn't told it lost the lease")
+	}
+
+	// Resigning frees the lease right away
+	b.resign()
+	a.campaign(ctx)
+	b.campaign(ctx)
+	if leadsA, leadsB := leaders(); !leadsA || leadsB {
+		t.Fatalf("leaders = %v, %v: the lease should be free after the holder resigned", leadsA, leadsB)
+	}
+}
+
+func TestLeaderStepsDownWhenRenewalFails(t *testing.T) {
+	ctx := context.Background()
+	db, query := dbtest.Open(t)
+	clock := newFakeClock(time.Date(2025, 8, 13, 12, 0, 0, 0, time.UTC))
+
+	e := newLeaderElector(query, "node-a", clock, newLeadership())
+	e.campaign(ctx)
+	if !e.state.IsLeader() {
+		t.Fatal("instance should acquire the free lease")
+	}
+
+	// Renewals fail from now on
+	db.Close()
+
+	clock.Advance(leaseTTL / 3)
+	e.campaign(ctx)
+	if !e.state.IsLeader() {
+		t.Fatal("a failed renewal shouldn't give up a lease that is still valid")
+	}
+
+	clock.Advance(leaseTTL / 3)
+	e.campaign(ctx)
+	if !e.state.IsLeader() {
+		t.Fatal("a failed renewal shouldn't give up a lease that is still valid")
+	}
+
+	clock.Advance(leaseTTL / 3)
+	e.campaign(ctx)
+	if e.state.IsLeader() {
+		t.Fatal("instance should stop leading once its lease expired without renewal")
+	}
+}
+
+func TestOnlyTheLeaderRunsScheduledJobs(t *testing.T) {
+	db := dbtest.Service(t)
+	clock := newFakeClock(time.Date(2025, 8, 13, 12, 0, 0, 0, time.UTC))
+	newJob := func() *countingJob {
+		return &countingJob{
+			BaseJob: NewBaseJob(nil, structures.Job("test_job"), JobConfig{Enabled: true, Interval: time.Hour, Timezone: "UTC", Timeout: time.Minute}),
+			runs:    make(chan time.Time, 10),
+		}
+	}
+
+	jobA, jobB := newJob(), newJob()
+	a := startNode(t, db, clock, "node-a", jobA)
+	b := startNode(t, db, clock, "node-b", jobB)
+	if !a.IsLeader() || b.IsLeader() {
+		t.Fatalf("leaders = %v, %v: the instance started first should lead", a.IsLeader(), b.IsLeader())
+	}
+
+	awaitTimers(t, clock, 2)
+	clock.Advance(time.Hour)
+	expectRun(t, jobA)
+	expectNoRun(t, jobB)
+
+	// The leader shuts down and hands over the lease
+	awaitTimers(t, clock, 2)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := a.Stop(ctx); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+	b.leader.campaign(ctx)
+	if !b.IsLeader() {
+		t.Fatal("the remaining instance should take over the lease")
+	}
+
+	clock.Advance(time.Hour)
+	expectRun(t, jobB)
+	expectNoRun(t, jobA)
+}
+
+func TestRunsStopWhenLeadershipIsLost(t *testing.T) {
+	db := dbtest.Service(t)
+	clock := newFakeClock(time.Date(2025, 8, 13, 12, 0, 0, 0, time.UTC))
+	job := &blockingJob{
+		BaseJob: NewBaseJob(nil, structures.Job("test_job"), JobConfig{Enabled: true, Interval: 24 * time.Hour, Timezone: "UTC", Timeout: time.Hour}),
+		started: make(chan struct{}, 1),
+		stopped: make(chan error, 1),
+	}
+	a := startNode(t, db, clock, "node-a", job)
+	ctx := context.Background()
+
+	if err := a.TriggerJob(ctx, job.Name()); err != nil {
+		t.Fatalf("trigger: %v", err)
+	}
+	select {
+	case <-job.started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("job did not start")
+	}
+
+	// The leader misses its renewals and another instance takes the lease
+	clock.Advance(leaseTTL + time.Second)
+	other := newLeaderElector(db.Query(), "node-b", clock, newLeadership())
+	other.campaign(ctx)
+	a.leader.campaign(ctx)
+	if a.IsLeader() || !other.state.IsLeader() {
+		t.Fatal("the other instance should hold the lease")
+	}
+
+	select {
+	case err := <-job.stopped:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("run stopped with %v, want it cancelled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("run kept going after leadership was lost")
+	}
+}
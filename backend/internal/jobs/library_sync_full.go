@@ -108,6 +108,16 @@ func (j *LibrarySyncFullJob) Execute(ctx context.Context) error {
 		"inserted", insertedCount,
 		"skipped", skippedCount)
 
+	watchlistNotified := notifyAvailableWatchlistItems(ctx, j.Context())
+
+	j.SetRunSummary(map[string]interface{}{
+		"total_items":        len(libraryItems),
+		"items_synced":       insertedCount,
+		"items_skipped":      skippedCount,
+		"tv_shows_queued":    len(tvShowsToSync),
+		"watchlist_notified": watchlistNotified,
+	})
+
 	return nil
 }
 
@@ -140,6 +140,10 @@ func (j *LibrarySyncIncrementalJob) Execute(ctx context.Context) error {
 		go j.processNewTVShowSeasons(newTVShows)
 	}
 
+	if insertedCount > 0 {
+		notifyAvailableWatchlistItems(ctx, j.Context())
+	}
+
 	return nil
 }
 
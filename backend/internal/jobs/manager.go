@@ -7,6 +7,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/mahcks/serra/internal/eventbus"
 	"github.com/mahcks/serra/internal/global"
 	"github.com/mahcks/serra/internal/integrations"
 	"github.com/mahcks/serra/pkg/structures"
@@ -21,16 +22,47 @@ type Manager struct {
 	running      bool
 	stopChan     chan struct{}
 	wg           sync.WaitGroup
+	clock        Clock
+	leadership   *leadership
+	leader       *leaderElector
+
+	// Outcome of the last run of each job, to skip recording unchanged runs
+	outcomesMu sync.Mutex
+	outcomes   map[structures.Job]structures.JobRunOutcome
+
+	// Closed and replaced when the job schedules change, waking the runners
+	schedulesMu      sync.Mutex
+	schedulesChanged chan struct{}
+}
+
+// ManagerOption configures a Manager
+type ManagerOption func(*Manager)
+
+// WithClock drives job scheduling and leader election from the given clock
+// instead of the wall clock
+func WithClock(clock Clock) ManagerOption {
+	return func(m *Manager) {
+		m.clock = clock
+	}
 }
 
 // NewManager creates a new job manager
-func NewManager(gctx global.Context, integrations *integrations.Integration) *Manager {
-	return &Manager{
+func NewManager(gctx global.Context, integrations *integrations.Integration, opts ...ManagerOption) *Manager {
+	m := &Manager{
 		gctx:         gctx,
 		integrations: integrations,
 		jobs:         make(map[structures.Job]Job),
 		stopChan:     make(chan struct{}),
+		clock:        realClock{},
+		leadership:   newLeadership(),
+		outcomes:     make(map[structures.Job]structures.JobRunOutcome),
+
+		schedulesChanged: make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(m)
 	}
+	return m
 }
 
 // Register registers a job with the manager
@@ -44,6 +76,7 @@ func (m *Manager) Register(job Job) error {
 	}
 
 	m.jobs[name] = job
+	setLeadership(job, m.IsLeader)
 	slog.Info("Registered job", "name", name)
 	return nil
 }
@@ -74,6 +107,28 @@ func (m *Manager) Start(ctx context.Context) error {
 	slog.Info("Starting job manager", "job_count", len(m.jobs))
 	m.running = true
 
+	// Replicas sharing the database elect one instance to run scheduled jobs
+	if bus := m.gctx.Crate().EventBus; bus != nil {
+		m.leader = newLeaderElector(m.gctx.Crate().Sqlite.Query(), bus.NodeID(), m.clock, m.leadership)
+		m.leader.campaign(ctx)
+
+		m.wg.Add(1)
+		go func() {
+			defer m.wg.Done()
+			m.leader.run(ctx, m.stopChan)
+		}()
+
+		unsubscribe := bus.Subscribe(busTopicSchedules, func(eventbus.Event) {
+			m.rescheduleAll()
+		})
+		m.wg.Add(1)
+		go func() {
+			defer m.wg.Done()
+			<-m.stopChan
+			unsubscribe()
+		}()
+	}
+
 	// Start each job
 	for name, job := range m.jobs {
 		if job.Config().Enabled {
@@ -101,6 +156,11 @@ func (m *Manager) Stop(ctx context.Context) error {
 	m.running = false
 	close(m.stopChan)
 
+	// Hand the job lease to another instance once the jobs stopped
+	if m.leader != nil {
+		defer m.leader.resign()
+	}
+
 	// Stop all jobs
 	for name, job := range m.jobs {
 		if err := job.Stop(ctx); err != nil {
@@ -133,7 +193,7 @@ func (m *Manager) runJob(ctx context.Context, job Job) {
 	name := job.Name()
 	config := job.Config()
 
-	slog.Debug("Starting job runner", "name", name, "interval", config.Interval)
+	slog.Debug("Starting job runner", "name", name, "interval", config.Interval, "schedule", config.Schedule)
 
 	// Start the job
 	if err := job.Start(ctx); err != nil {
@@ -143,18 +203,47 @@ func (m *Manager) runJob(ctx context.Context, job Job) {
 	}
 
 	// Run on startup if configured
-	if config.RunOnStartup {
-		m.executeJob(ctx, job)
+	if config.RunOnStartup && m.IsLeader() {
+		m.executeJob(ctx, job, structures.JobRunTriggerStartup)
 	}
 
-	// Set up ticker for periodic execution
-	ticker := time.NewTicker(config.Interval)
-	defer ticker.Stop()
-
+	base := m.clock.Now()
 	for {
+		// Taken before reading the schedule so no change goes unnoticed
+		changed := m.scheduleChanges()
+		m.refreshSchedule(ctx, job)
+
+		sched, err := newScheduler(job.Config())
+		if err != nil {
+			slog.Error("Invalid job schedule", "name", name, "error", err)
+			job.OnError(ctx, err)
+			return
+		}
+
+		// Activations missed while the previous run was still executing are skipped
+		now := m.clock.Now()
+		next := sched.schedule.Next(base)
+		if next.Before(now) {
+			next = sched.schedule.Next(now)
+		}
+		if next.IsZero() {
+			slog.Warn("Job schedule never fires, stopping runner", "name", name)
+			return
+		}
+
+		fire := sched.fireTime(next)
+		setNextRun(job, fire)
+
 		select {
-		case <-ticker.C:
-			m.executeJob(ctx, job)
+		case <-m.clock.After(fire.Sub(now)):
+			base = next
+			if !m.IsLeader() {
+				slog.Debug("Skipping scheduled run, another instance leads", "name", name)
+				continue
+			}
+			m.executeJob(ctx, job, structures.JobRunTriggerScheduled)
+		case <-changed:
+			slog.Debug("Job schedules changed, rescheduling", "name", name)
 		case <-m.stopChan:
 			slog.Debug("Job runner stopping", "name", name)
 			return
@@ -165,8 +254,45 @@ func (m *Manager) runJob(ctx context.Context, job Job) {
 	}
 }
 
-// executeJob executes a job with timeout and retry logic
-func (m *Manager) executeJob(ctx context.Context, job Job) {
+// scheduleChanges returns a channel that is closed once the job schedules change
+func (m *Manager) scheduleChanges() <-chan struct{} {
+	m.schedulesMu.Lock()
+	defer m.schedulesMu.Unlock()
+	return m.schedulesChanged
+}
+
+// rescheduleAll wakes every job runner to pick up changed schedules instead of
+// waiting out the run it already scheduled
+func (m *Manager) rescheduleAll() {
+	m.schedulesMu.Lock()
+	defer m.schedulesMu.Unlock()
+	close(m.schedulesChanged)
+	m.schedulesChanged = make(chan struct{})
+}
+
+// IsLeader reports whether this instance runs scheduled jobs. Replicas sharing
+// a database elect one leader, a single instance always leads.
+func (m *Manager) IsLeader() bool {
+	return m.leadership.IsLeader()
+}
+
+// setLeadership tells jobs that act on their own, outside of scheduled runs,
+// whether this instance leads
+func setLeadership(job Job, leading func() bool) {
+	if setter, ok := job.(interface{ SetLeadership(func() bool) }); ok {
+		setter.SetLeadership(leading)
+	}
+}
+
+// setNextRun publishes the next scheduled run to jobs that track it
+func setNextRun(job Job, next time.Time) {
+	if setter, ok := job.(interface{ SetNextRun(time.Time) }); ok {
+		setter.SetNextRun(next)
+	}
+}
+
+// executeJob executes a job with timeout and retry logic and records the run
+func (m *Manager) executeJob(ctx context.Context, job Job, trigger structures.JobRunTrigger) {
 	name := job.Name()
 	config := job.Config()
 	start := time.Now()
@@ -175,6 +301,21 @@ func (m *Manager) executeJob(ctx context.Context, job Job) {
 	execCtx, cancel := context.WithTimeout(ctx, config.Timeout)
 	defer cancel()
 
+	// Runs started as leader stop once another instance takes over, which
+	// starts running the jobs itself. Taken before checking leadership so no
+	// loss goes unnoticed.
+	lost := m.leadership.lostChan()
+	if m.IsLeader() {
+		go func() {
+			select {
+			case <-lost:
+				slog.Warn("Lost job leadership, cancelling run", "name", name)
+				cancel()
+			case <-execCtx.Done():
+			}
+		}()
+	}
+
 	var lastErr error
 	for attempt := 0; attempt <= config.MaxRetries; attempt++ {
 		if attempt > 0 {
@@ -185,6 +326,7 @@ func (m *Manager) executeJob(ctx context.Context, job Job) {
 			case <-time.After(config.RetryDelay):
 			case <-execCtx.Done():
 				job.OnError(execCtx, execCtx.Err())
+				m.recordRun(job, trigger, start, attempt, execCtx.Err())
 				return
 			}
 		}
@@ -195,6 +337,7 @@ func (m *Manager) executeJob(ctx context.Context, job Job) {
 			duration := time.Since(start)
 			slog.Debug("Job executed successfully", "name", name, "duration", duration)
 			job.OnSuccess(execCtx, duration)
+			m.recordRun(job, trigger, start, attempt+1, nil)
 			return
 		}
 
@@ -205,6 +348,7 @@ func (m *Manager) executeJob(ctx context.Context, job Job) {
 	// All retries exhausted
 	slog.Error("Job failed after all retries", "name", name, "error", lastErr)
 	job.OnError(execCtx, lastErr)
+	m.recordRun(job, trigger, start, config.MaxRetries+1, lastErr)
 }
 
 // GetJob returns a specific job by name
@@ -246,7 +390,7 @@ func (m *Manager) TriggerJob(ctx context.Context, name structures.Job) error {
 		return fmt.Errorf("job %s not found", name)
 	}
 
-	go m.executeJob(ctx, job)
+	go m.executeJob(ctx, job, structures.JobRunTriggerManual)
 	return nil
 }
 
@@ -0,0 +1,161 @@
+package jobs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mahcks/serra/config"
+	"github.com/mahcks/serra/internal/db/dbtest"
+	"github.com/mahcks/serra/internal/db/repository"
+	"github.com/mahcks/serra/internal/global"
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+// countingJob reports each run on a channel
+type countingJob struct {
+	*BaseJob
+	runs chan time.Time
+}
+
+func (j *countingJob) Trigger(ctx context.Context) error {
+	j.runs <- time.Now()
+	return nil
+}
+
+// startManager runs a job under a manager driven by the fake clock
+func startManager(t *testing.T, clock *fakeClock, jobConfig JobConfig) (*countingJob, *Manager) {
+	t.Helper()
+
+	gctx := global.New(context.Background(), &config.Bootstrap{}, "test", "")
+	gctx.Crate().Sqlite = dbtest.Service(t)
+
+	job := &countingJob{
+		BaseJob: NewBaseJob(gctx, structures.Job("test_job"), jobConfig),
+		runs:    make(chan time.Time, 10),
+	}
+
+	manager := NewManager(gctx, nil, WithClock(clock))
+	if err := manager.Register(job); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	if err := manager.Start(context.Background()); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = manager.Stop(ctx)
+	})
+	return job, manager
+}
+
+// awaitTimer waits for the job runner to arm its timer
+func awaitTimer(t *testing.T, clock *fakeClock) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for clock.waiting() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("job runner never waited on the clock")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func expectRun(t *testing.T, job *countingJob) {
+	t.Helper()
+	select {
+	case <-job.runs:
+	case <-time.After(5 * time.Second):
+		t.Fatal("job did not run")
+	}
+}
+
+func expectNoRun(t *testing.T, job *countingJob) {
+	t.Helper()
+	select {
+	case <-job.runs:
+		t.Fatal("job ran early")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestManagerRunsCronJobsOnTheInjectedClock(t *testing.T) {
+	clock := newFakeClock(time.Date(2025, 8, 13, 10, 20, 0, 0, time.UTC))
+	job, _ := startManager(t, clock, JobConfig{
+		Enabled:  true,
+		Schedule: "0 * * * *",
+		Timezone: "UTC",
+		Timeout:  time.Minute,
+	})
+
+	awaitTimer(t, clock)
+	if next := job.Metrics().NextRun; next == nil || !next.Equal(time.Date(2025, 8, 13, 11, 0, 0, 0, time.UTC)) {
+		t.Fatalf("next run = %v, want 11:00", next)
+	}
+
+	clock.Advance(30 * time.Minute)
+	expectNoRun(t, job)
+
+	clock.Advance(10 * time.Minute)
+	expectRun(t, job)
+
+	awaitTimer(t, clock)
+	if next := job.Metrics().NextRun; next == nil || !next.Equal(time.Date(2025, 8, 13, 12, 0, 0, 0, time.UTC)) {
+		t.Fatalf("next run after the first = %v, want 12:00", next)
+	}
+	clock.Advance(time.Hour)
+	expectRun(t, job)
+}
+
+func TestManagerDefersRunsPastBlackouts(t *testing.T) {
+	clock := newFakeClock(time.Date(2025, 8, 13, 17, 30, 0, 0, time.UTC))
+	job, _ := startManager(t, clock, JobConfig{
+		Enabled:   true,
+		Interval:  time.Hour,
+		Timezone:  "UTC",
+		Timeout:   time.Minute,
+		Blackouts: []structures.JobBlackoutWindow{{Start: "18:00", End: "20:00"}},
+	})
+
+	// 18:30 falls in the blackout, so the run waits until 20:00
+	awaitTimer(t, clock)
+	clock.Advance(time.Hour)
+	expectNoRun(t, job)
+
+	clock.Advance(90 * time.Minute)
+	expectRun(t, job)
+}
+
+func TestManagerReschedulesWhenSchedulesChange(t *testing.T) {
+	clock := newFakeClock(time.Date(2025, 8, 13, 10, 20, 0, 0, time.UTC))
+	job, manager := startManager(t, clock, JobConfig{
+		Enabled:  true,
+		Schedule: "0 * * * *",
+		Timezone: "UTC",
+		Timeout:  time.Minute,
+	})
+	awaitTimer(t, clock)
+
+	err := manager.gctx.Crate().Sqlite.Query().UpsertSetting(context.Background(), repository.UpsertSettingParams{
+		Key:   structures.SettingJobSchedules.String(),
+		Value: `{"test_job":{"schedule":"*/5 * * * *","timezone":"UTC"}}`,
+	})
+	if err != nil {
+		t.Fatalf("set job schedules: %v", err)
+	}
+	manager.rescheduleAll()
+
+	// The runner drops its 11:00 wait and picks up the new schedule
+	want := time.Date(2025, 8, 13, 10, 25, 0, 0, time.UTC)
+	deadline := time.Now().Add(5 * time.Second)
+	for next := job.Metrics().NextRun; next == nil || !next.Equal(want); next = job.Metrics().NextRun {
+		if time.Now().After(deadline) {
+			t.Fatalf("next run = %v, want 10:25", next)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	clock.Advance(5 * time.Minute)
+	expectRun(t, job)
+}
@@ -0,0 +1,109 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/mahcks/serra/internal/global"
+	"github.com/mahcks/serra/internal/integrations"
+	"github.com/mahcks/serra/internal/integrations/radarr"
+	"github.com/mahcks/serra/internal/integrations/sonarr"
+	"github.com/mahcks/serra/internal/services/request_processor"
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+// NewSeasonRequests job requests newly announced seasons of followed shows and
+// fulfilled season requests on behalf of the users who requested them
+type NewSeasonRequests struct {
+	*BaseJob
+	gctx      global.Context
+	processor request_processor.Service
+}
+
+// NewNewSeasonRequests creates a new season request job
+func NewNewSeasonRequests(gctx global.Context, integrations *integrations.Integration, config JobConfig) (Job, error) {
+	radarrSvc := radarr.New(gctx.Crate().Sqlite.Query())
+	sonarrSvc := sonarr.New(gctx.Crate().Sqlite.Query())
+	processor := request_processor.New(gctx.Crate().Sqlite.Query(), radarrSvc, sonarrSvc, integrations)
+
+	return &NewSeasonRequests{
+		BaseJob:   NewBaseJob(gctx, structures.JobNewSeasonRequests, config),
+		gctx:      gctx,
+		processor: processor,
+	}, nil
+}
+
+// Name returns the job name
+func (j *NewSeasonRequests) Name() structures.Job {
+	return structures.JobNewSeasonRequests
+}
+
+// Trigger requests new seasons and notifies the users they were requested for
+func (j *NewSeasonRequests) Trigger(ctx context.Context) error {
+	slog.Debug("Starting new season request job")
+
+	created, err := j.processor.RequestNewSeasons(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to request new seasons: %w", err)
+	}
+
+	approved := 0
+	for _, request := range created {
+		if request.Status == "approved" {
+			approved++
+		}
+
+		notifications := j.gctx.Crate().NotificationService
+		if notifications == nil {
+			continue
+		}
+
+		var seasons []int
+		if err := json.Unmarshal([]byte(request.Seasons.String), &seasons); err != nil || len(seasons) == 0 {
+			continue
+		}
+
+		userID := request.UserID
+		if request.OnBehalfOf.Valid && request.OnBehalfOf.String != "" {
+			userID = request.OnBehalfOf.String
+		}
+		tmdbID := request.TmdbID.Int64
+		requestID := strconv.FormatInt(request.ID, 10)
+		if err := notifications.NotifyNewSeasonRequested(ctx, userID, request.Title.String, seasons[0], request.Status == "approved", &tmdbID, &requestID); err != nil {
+			slog.Warn("Failed to notify user about new season request",
+				"request_id", request.ID,
+				"user_id", userID,
+				"error", err)
+		}
+	}
+
+	j.SetRunSummary(map[string]interface{}{
+		"requests_created":  len(created),
+		"requests_approved": approved,
+		"requests_pending":  len(created) - approved,
+	})
+
+	slog.Debug("New season request job completed", "created", len(created), "approved", approved)
+
+	return nil
+}
+
+// Start initializes the job
+func (j *NewSeasonRequests) Start(ctx context.Context) error {
+	slog.Info("Starting new season request job", "interval", j.Config().Interval)
+	return j.BaseJob.Start(ctx)
+}
+
+// Stop cleans up the job
+func (j *NewSeasonRequests) Stop(ctx context.Context) error {
+	slog.Info("New season request job stopped")
+	return j.BaseJob.Stop(ctx)
+}
+
+// Health returns the job health status
+func (j *NewSeasonRequests) Health() error {
+	return nil
+}
@@ -0,0 +1,112 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/mahcks/serra/internal/eventbus"
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+// busTopicSchedules is published on when the job schedules setting changes
+const busTopicSchedules = "jobs.schedules"
+
+// NotifyScheduleChange wakes the job runners of every instance so a changed
+// job schedules setting takes effect right away
+func NotifyScheduleChange(ctx context.Context, bus eventbus.Bus) error {
+	if bus == nil {
+		return nil
+	}
+	return bus.Publish(ctx, busTopicSchedules, nil)
+}
+
+// ApplyScheduleOverride returns the configuration with the override's scheduling
+// fields applied, validating the result
+func ApplyScheduleOverride(config JobConfig, override structures.JobScheduleOverride) (JobConfig, error) {
+	config.Schedule = override.Schedule
+	config.Timezone = override.Timezone
+	config.Blackouts = override.Blackouts
+	config.Jitter = 0
+
+	if override.Jitter != "" {
+		jitter, err := time.ParseDuration(override.Jitter)
+		if err != nil {
+			return config, fmt.Errorf("invalid jitter %q: %w", override.Jitter, err)
+		}
+		config.Jitter = jitter
+	}
+
+	if err := ValidateSchedule(config); err != nil {
+		return config, err
+	}
+	return config, nil
+}
+
+// ParseScheduleOverrides decodes the job_schedules setting value
+func ParseScheduleOverrides(value string) (map[structures.Job]structures.JobScheduleOverride, error) {
+	overrides := make(map[structures.Job]structures.JobScheduleOverride)
+	if value == "" {
+		return overrides, nil
+	}
+	if err := json.Unmarshal([]byte(value), &overrides); err != nil {
+		return nil, fmt.Errorf("invalid job schedules: %w", err)
+	}
+	return overrides, nil
+}
+
+// refreshSchedule applies the schedule override stored in settings to the job so
+// that changes take effect without a restart
+func (m *Manager) refreshSchedule(ctx context.Context, job Job) {
+	value, err := m.gctx.Crate().Sqlite.Query().GetSetting(ctx, structures.SettingJobSchedules.String())
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		slog.Warn("Failed to load job schedules", "error", err)
+		return
+	}
+
+	overrides, err := ParseScheduleOverrides(value)
+	if err != nil {
+		slog.Warn("Ignoring invalid job schedules setting", "error", err)
+		return
+	}
+
+	current := job.Config()
+	desired := current
+	if override, ok := overrides[job.Name()]; ok {
+		desired, err = ApplyScheduleOverride(current, override)
+		if err != nil {
+			slog.Warn("Ignoring invalid schedule override", "name", job.Name(), "error", err)
+			return
+		}
+	} else if defaults, ok := GetDefaultConfig(job.Name()); ok {
+		desired.Schedule = defaults.Schedule
+		desired.Timezone = defaults.Timezone
+		desired.Jitter = defaults.Jitter
+		desired.Blackouts = defaults.Blackouts
+	}
+
+	if scheduleEqual(current, desired) {
+		return
+	}
+
+	if err := job.SetConfig(desired); err != nil {
+		slog.Warn("Failed to apply schedule override", "name", job.Name(), "error", err)
+		return
+	}
+	slog.Info("Applied job schedule", "name", job.Name(), "schedule", desired.Schedule, "timezone", desired.Timezone)
+}
+
+// scheduleEqual compares only the scheduling fields of two configurations
+func scheduleEqual(a, b JobConfig) bool {
+	return a.Schedule == b.Schedule &&
+		a.Timezone == b.Timezone &&
+		a.Jitter == b.Jitter &&
+		slices.EqualFunc(a.Blackouts, b.Blackouts, func(x, y structures.JobBlackoutWindow) bool {
+			return x.Start == y.Start && x.End == y.End && slices.Equal(x.Days, y.Days)
+		})
+}
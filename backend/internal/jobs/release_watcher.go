@@ -0,0 +1,110 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/mahcks/serra/internal/global"
+	"github.com/mahcks/serra/internal/integrations"
+	"github.com/mahcks/serra/internal/integrations/radarr"
+	"github.com/mahcks/serra/internal/integrations/sonarr"
+	"github.com/mahcks/serra/internal/services/request_processor"
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+// ReleaseWatcher job requests movies that were waiting for their release: the
+// unreleased parts of watched collection requests and watchlisted movies with
+// auto-request enabled
+type ReleaseWatcher struct {
+	*BaseJob
+	gctx      global.Context
+	processor request_processor.Service
+}
+
+// NewReleaseWatcher creates a new release watcher job
+func NewReleaseWatcher(gctx global.Context, integrations *integrations.Integration, config JobConfig) (Job, error) {
+	radarrSvc := radarr.New(gctx.Crate().Sqlite.Query())
+	sonarrSvc := sonarr.New(gctx.Crate().Sqlite.Query())
+	processor := request_processor.New(gctx.Crate().Sqlite.Query(), radarrSvc, sonarrSvc, integrations)
+
+	return &ReleaseWatcher{
+		BaseJob:   NewBaseJob(gctx, structures.JobReleaseWatcher, config),
+		gctx:      gctx,
+		processor: processor,
+	}, nil
+}
+
+// Name returns the job name
+func (j *ReleaseWatcher) Name() structures.Job {
+	return structures.JobReleaseWatcher
+}
+
+// Trigger requests the collection parts and watchlisted movies that have been
+// released, and notifies users about their watchlisted movies
+func (j *ReleaseWatcher) Trigger(ctx context.Context) error {
+	slog.Debug("Starting release watcher job")
+
+	collectionParts, err := j.processor.RequestReleasedCollectionParts(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to request released collection parts: %w", err)
+	}
+
+	watchlistMovies, err := j.processor.RequestReleasedWatchlistMovies(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to request released watchlist movies: %w", err)
+	}
+
+	approved := 0
+	for _, request := range append(collectionParts, watchlistMovies...) {
+		if request.Status == "approved" {
+			approved++
+		}
+	}
+
+	for _, request := range watchlistMovies {
+		notifications := j.gctx.Crate().NotificationService
+		if notifications == nil {
+			break
+		}
+
+		tmdbID := request.TmdbID.Int64
+		requestID := strconv.FormatInt(request.ID, 10)
+		if err := notifications.NotifyWatchlistRequested(ctx, request.UserID, request.Title.String, request.Status == "approved", &tmdbID, &requestID); err != nil {
+			slog.Warn("Failed to notify user about watchlist request",
+				"request_id", request.ID,
+				"user_id", request.UserID,
+				"error", err)
+		}
+	}
+
+	created := len(collectionParts) + len(watchlistMovies)
+	j.SetRunSummary(map[string]interface{}{
+		"collection_requests_created": len(collectionParts),
+		"watchlist_requests_created":  len(watchlistMovies),
+		"requests_approved":           approved,
+		"requests_pending":            created - approved,
+	})
+
+	slog.Debug("Release watcher job completed", "created", created, "approved", approved)
+
+	return nil
+}
+
+// Start initializes the job
+func (j *ReleaseWatcher) Start(ctx context.Context) error {
+	slog.Info("Starting release watcher job", "interval", j.Config().Interval)
+	return j.BaseJob.Start(ctx)
+}
+
+// Stop cleans up the job
+func (j *ReleaseWatcher) Stop(ctx context.Context) error {
+	slog.Info("Release watcher job stopped")
+	return j.BaseJob.Stop(ctx)
+}
+
+// Health returns the job health status
+func (j *ReleaseWatcher) Health() error {
+	return nil
+}
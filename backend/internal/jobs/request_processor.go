@@ -81,5 +81,11 @@ func (j *RequestProcessorJob) Execute(ctx context.Context) error {
 		"total_requests", len(requests),
 		"processed", processedCount)
 
+	j.SetRunSummary(map[string]interface{}{
+		"total_requests":     len(requests),
+		"requests_processed": processedCount,
+		"requests_failed":    len(requests) - processedCount,
+	})
+
 	return nil
 }
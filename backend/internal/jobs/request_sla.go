@@ -0,0 +1,144 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/mahcks/serra/internal/db/repository"
+	"github.com/mahcks/serra/internal/global"
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+// RequestSLA job escalates pending requests that have waited longer than the
+// configured approval SLA to everyone who can approve them
+type RequestSLA struct {
+	*BaseJob
+	gctx global.Context
+}
+
+// NewRequestSLA creates a new request SLA job
+func NewRequestSLA(gctx global.Context, config JobConfig) (Job, error) {
+	baseJob := NewBaseJob(gctx, structures.JobRequestSLA, config)
+
+	return &RequestSLA{
+		BaseJob: baseJob,
+		gctx:    gctx,
+	}, nil
+}
+
+// Name returns the job name
+func (j *RequestSLA) Name() structures.Job {
+	return structures.JobRequestSLA
+}
+
+// Trigger escalates every pending request past the SLA that hasn't been escalated yet
+func (j *RequestSLA) Trigger(ctx context.Context) error {
+	query := j.gctx.Crate().Sqlite.Query()
+
+	hours := RequestSLAHours(ctx, query)
+	if hours == 0 {
+		j.SetRunSummary(map[string]interface{}{
+			"sla_hours": 0,
+		})
+		return nil
+	}
+
+	now := time.Now()
+	overdue, err := query.GetOverdueRequests(ctx, now.Add(-time.Duration(hours)*time.Hour).UTC())
+	if err != nil {
+		slog.Error("Failed to get overdue requests", "error", err)
+		return err
+	}
+
+	notifications := j.gctx.Crate().NotificationService
+	if notifications == nil {
+		slog.Warn("Notifications are unavailable, overdue requests are not escalated", "count", len(overdue))
+		return nil
+	}
+
+	escalated := 0
+	for _, request := range overdue {
+		waited := now.Sub(request.CreatedAt)
+
+		title := request.Title.String
+		if !request.Title.Valid {
+			title = fmt.Sprintf("request #%d", request.ID)
+		}
+		var tmdbID *int64
+		if request.TmdbID.Valid {
+			tmdbID = &request.TmdbID.Int64
+		}
+		requestIDStr := strconv.FormatInt(request.ID, 10)
+
+		// Only requests whose approvers were told are escalated, the rest are
+		// tried again on the next run
+		if err := notifications.NotifyRequestOverdue(ctx, title, request.MediaType, request.Priority, waited, tmdbID, &requestIDStr); err != nil {
+			slog.Error("Failed to notify approvers of overdue request", "error", err, "request_id", request.ID)
+			continue
+		}
+
+		// The metric marks the request as escalated so it is only escalated once
+		_, err := query.RecordRequestMetric(ctx, repository.RecordRequestMetricParams{
+			RequestID:             request.ID,
+			StatusChange:          "sla_escalated",
+			PreviousStatus:        sql.NullString{String: "pending", Valid: true},
+			NewStatus:             "pending",
+			ProcessingTimeSeconds: sql.NullInt64{Int64: int64(waited.Seconds()), Valid: true},
+			UserID:                request.UserID,
+		})
+		if err != nil {
+			slog.Error("Failed to record request escalation", "error", err, "request_id", request.ID)
+			continue
+		}
+		escalated++
+	}
+
+	j.SetRunSummary(map[string]interface{}{
+		"sla_hours": hours,
+		"escalated": escalated,
+	})
+
+	if escalated > 0 {
+		slog.Info("Escalated overdue requests", "count", escalated, "sla_hours", hours)
+	}
+
+	return nil
+}
+
+// RequestSLAHours returns how many hours a request may wait for approval
+// before it is escalated, 0 when no SLA is configured
+func RequestSLAHours(ctx context.Context, query *repository.Queries) int {
+	value, err := query.GetSetting(ctx, structures.SettingRequestSLAHours.String())
+	if err != nil || value == "" {
+		return 0
+	}
+
+	hours, err := strconv.Atoi(value)
+	if err != nil || hours < 0 {
+		slog.Warn("Invalid request SLA setting, SLA disabled", "value", value)
+		return 0
+	}
+
+	return hours
+}
+
+// Start initializes the job
+func (j *RequestSLA) Start(ctx context.Context) error {
+	slog.Info("Starting request SLA job", "interval", j.Config().Interval)
+	return j.BaseJob.Start(ctx)
+}
+
+// Stop cleans up the job
+func (j *RequestSLA) Stop(ctx context.Context) error {
+	slog.Info("Request SLA job stopped")
+	return j.BaseJob.Stop(ctx)
+}
+
+// Health returns the job health status
+func (j *RequestSLA) Health() error {
+	return nil
+}
@@ -0,0 +1,91 @@
+package jobs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mahcks/serra/config"
+	"github.com/mahcks/serra/internal/db/dbtest"
+	"github.com/mahcks/serra/internal/db/repository"
+	"github.com/mahcks/serra/internal/global"
+	"github.com/mahcks/serra/internal/services/notifications"
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+func TestRequestSLAEscalatesOnlyNotifiedRequests(t *testing.T) {
+	gctx := global.New(context.Background(), &config.Bootstrap{}, "test", "")
+	gctx.Crate().Sqlite = dbtest.Service(t)
+	query := gctx.Crate().Sqlite.Query()
+	gctx.Crate().NotificationService = notifications.NewService(query)
+	ctx := context.Background()
+
+	statements := []string{
+		`INSERT INTO users (id, username) VALUES ('alice', 'alice'), ('admin', 'admin')`,
+		`INSERT INTO requests (id, user_id, media_type, tmdb_id, title, status, created_at) VALUES
+			(1, 'alice', 'movie', 100, 'Overdue', 'pending', datetime('now', '-3 days')),
+			(2, 'alice', 'tv', 200, 'Also overdue', 'pending', datetime('now', '-4 days')),
+			(3, 'alice', 'movie', 300, 'Recent', 'pending', datetime('now'))`,
+	}
+	for _, statement := range statements {
+		if _, err := gctx.Crate().Sqlite.DB().Exec(statement); err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+	if err := query.UpsertSetting(ctx, repository.UpsertSettingParams{
+		Key:   structures.SettingRequestSLAHours.String(),
+		Value: "24",
+	}); err != nil {
+		t.Fatalf("set SLA: %v", err)
+	}
+
+	job, err := NewRequestSLA(gctx, JobConfig{})
+	if err != nil {
+		t.Fatalf("new request SLA: %v", err)
+	}
+	escalated := func() []int64 {
+		t.Helper()
+		rows, err := gctx.Crate().Sqlite.DB().Query(`SELECT request_id FROM request_metrics WHERE status_change = 'sla_escalated' ORDER BY request_id`)
+		if err != nil {
+			t.Fatalf("query escalations: %v", err)
+		}
+		defer rows.Close()
+		var ids []int64
+		for rows.Next() {
+			var id int64
+			if err := rows.Scan(&id); err != nil {
+				t.Fatal(err)
+			}
+			ids = append(ids, id)
+		}
+		return ids
+	}
+
+	// Nobody can approve yet, so nothing may be marked as escalated
+	if err := job.Trigger(ctx); err != nil {
+		t.Fatalf("trigger: %v", err)
+	}
+	if ids := escalated(); len(ids) != 0 {
+		t.Fatalf("escalated %v without notifying anyone", ids)
+	}
+
+	if _, err := gctx.Crate().Sqlite.DB().Exec(`INSERT INTO user_permissions (user_id, permission_id) VALUES ('admin', 'requests.approve')`); err != nil {
+		t.Fatalf("grant approve: %v", err)
+	}
+	for range 2 {
+		if err := job.Trigger(ctx); err != nil {
+			t.Fatalf("trigger: %v", err)
+		}
+	}
+
+	// Each overdue request is escalated once
+	if ids := escalated(); len(ids) != 2 || ids[0] != 1 || ids[1] != 2 {
+		t.Errorf("escalated %v, want [1 2]", ids)
+	}
+	unread, err := query.CountUnreadNotifications(ctx, "admin")
+	if err != nil {
+		t.Fatalf("count notifications: %v", err)
+	}
+	if unread != 2 {
+		t.Errorf("approver has %d notifications, want 2", unread)
+	}
+}
@@ -0,0 +1,462 @@
+package jobs
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+// Clock abstracts time so job scheduling can be driven by a fake clock
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// realClock is the wall clock used in production
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// Schedule computes the next activation time after a given time
+type Schedule interface {
+	Next(after time.Time) time.Time
+}
+
+// intervalSchedule fires at a fixed interval
+type intervalSchedule struct {
+	interval time.Duration
+}
+
+func (s intervalSchedule) Next(after time.Time) time.Time {
+	return after.Add(s.interval)
+}
+
+// parsedBlackout is a validated blackout window expressed in minutes since midnight
+type parsedBlackout struct {
+	start int
+	end   int
+	days  map[time.Weekday]bool
+}
+
+// parseClock parses a "HH:MM" time of day into minutes since midnight
+func parseClock(value string) (int, error) {
+	parts := strings.Split(strings.TrimSpace(value), ":")
+	if len(parts) != 2 {
+		return 0, fmt.Errorf("invalid time of day %q, expected HH:MM", value)
+	}
+	hour, err := strconv.Atoi(parts[0])
+	if err != nil || hour < 0 || hour > 23 {
+		return 0, fmt.Errorf("invalid hour in %q", value)
+	}
+	minute, err := strconv.Atoi(parts[1])
+	if err != nil || minute < 0 || minute > 59 {
+		return 0, fmt.Errorf("invalid minute in %q", value)
+	}
+	return hour*60 + minute, nil
+}
+
+func parseBlackout(window structures.JobBlackoutWindow) (parsedBlackout, error) {
+	start, err := parseClock(window.Start)
+	if err != nil {
+		return parsedBlackout{}, err
+	}
+	end, err := parseClock(window.End)
+	if err != nil {
+		return parsedBlackout{}, err
+	}
+	if start == end {
+		return parsedBlackout{}, fmt.Errorf("blackout window %s-%s is empty", window.Start, window.End)
+	}
+
+	var days map[time.Weekday]bool
+	if len(window.Days) > 0 {
+		days = make(map[time.Weekday]bool, len(window.Days))
+		for _, day := range window.Days {
+			if day < time.Sunday || day > time.Saturday {
+				return parsedBlackout{}, fmt.Errorf("invalid weekday %d in blackout window", day)
+			}
+			days[day] = true
+		}
+	}
+
+	return parsedBlackout{start: start, end: end, days: days}, nil
+}
+
+// windowEnd returns the end of the blackout window containing t, if any
+func (b parsedBlackout) windowEnd(t time.Time) (time.Time, bool) {
+	minutes := t.Hour()*60 + t.Minute()
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+
+	// Window started today
+	if b.start < b.end {
+		if minutes >= b.start && minutes < b.end && b.activeOn(t.Weekday()) {
+			return clockOn(midnight, b.end), true
+		}
+		return time.Time{}, false
+	}
+
+	// Window crosses midnight: either the late part of today's window...
+	if minutes >= b.start && b.activeOn(t.Weekday()) {
+		return clockOn(midnight.AddDate(0, 0, 1), b.end), true
+	}
+	// ...or the early part of yesterday's window
+	yesterday := midnight.AddDate(0, 0, -1)
+	if minutes < b.end && b.activeOn(yesterday.Weekday()) {
+		return clockOn(midnight, b.end), true
+	}
+	return time.Time{}, false
+}
+
+func (b parsedBlackout) activeOn(day time.Weekday) bool {
+	return b.days == nil || b.days[day]
+}
+
+// clockOn returns the wall-clock time minutes after the given midnight
+func clockOn(midnight time.Time, minutes int) time.Time {
+	return time.Date(midnight.Year(), midnight.Month(), midnight.Day(), minutes/60, minutes%60, 0, 0, midnight.Location())
+}
+
+// scheduler combines a base schedule with jitter and blackout windows
+type scheduler struct {
+	schedule  Schedule
+	location  *time.Location
+	jitter    time.Duration
+	blackouts []parsedBlackout
+	random    func(n int64) int64
+}
+
+// newScheduler builds the scheduler for a job configuration. A cron expression
+// takes precedence over the fixed interval.
+func newScheduler(config JobConfig) (*scheduler, error) {
+	location := time.Local
+	if config.Timezone != "" {
+		loc, err := time.LoadLocation(config.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timezone %q: %w", config.Timezone, err)
+		}
+		location = loc
+	}
+
+	var schedule Schedule
+	if config.Schedule != "" {
+		cron, err := ParseCron(config.Schedule, location)
+		if err != nil {
+			return nil, err
+		}
+		schedule = cron
+	} else {
+		if config.Interval <= 0 {
+			return nil, fmt.Errorf("job needs either a schedule or a positive interval")
+		}
+		schedule = intervalSchedule{interval: config.Interval}
+	}
+
+	if config.Jitter < 0 {
+		return nil, fmt.Errorf("jitter must not be negative")
+	}
+
+	blackouts := make([]parsedBlackout, 0, len(config.Blackouts))
+	for _, window := range config.Blackouts {
+		parsed, err := parseBlackout(window)
+		if err != nil {
+			return nil, err
+		}
+		blackouts = append(blackouts, parsed)
+	}
+
+	return &scheduler{
+		schedule:  schedule,
+		location:  location,
+		jitter:    config.Jitter,
+		blackouts: blackouts,
+		random:    rand.Int63n,
+	}, nil
+}
+
+// ValidateSchedule reports whether the scheduling fields of a job configuration are valid
+func ValidateSchedule(config JobConfig) error {
+	_, err := newScheduler(config)
+	return err
+}
+
+// fireTime applies jitter and blackout windows to a base activation time
+func (s *scheduler) fireTime(base time.Time) time.Time {
+	fire := base
+	if s.jitter > 0 {
+		fire = fire.Add(time.Duration(s.random(int64(s.jitter))))
+	}
+	return s.deferBlackouts(fire)
+}
+
+// deferBlackouts moves t to the end of any blackout window it falls into.
+// Windows may chain back to back, so keep deferring until t is clear.
+func (s *scheduler) deferBlackouts(t time.Time) time.Time {
+	local := t.In(s.location)
+	for i := 0; i < 2*len(s.blackouts)+1; i++ {
+		deferred := false
+		for _, window := range s.blackouts {
+			if end, ok := window.windowEnd(local); ok {
+				local = end
+				deferred = true
+			}
+		}
+		if !deferred {
+			break
+		}
+	}
+	return local
+}
+
+// cronField is a bitset of allowed values for one cron field
+type cronField uint64
+
+func (f cronField) has(v int) bool {
+	return f&(1<<uint(v)) != 0
+}
+
+type cronBounds struct {
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minuteBounds = cronBounds{0, 59, nil}
+	hourBounds   = cronBounds{0, 23, nil}
+	domBounds    = cronBounds{1, 31, nil}
+	monthBounds  = cronBounds{1, 12, map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	dowBounds = cronBounds{0, 7, map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// CronSchedule is a standard five-field cron expression
+// (minute hour day-of-month month day-of-week) evaluated in a timezone
+type CronSchedule struct {
+	expr     string
+	minute   cronField
+	hour     cronField
+	dom      cronField
+	month    cronField
+	dow      cronField
+	domStar  bool
+	dowStar  bool
+	location *time.Location
+}
+
+// ParseCron parses a five-field cron expression or one of the @daily style macros
+func ParseCron(expr string, location *time.Location) (*CronSchedule, error) {
+	if location == nil {
+		location = time.Local
+	}
+
+	spec := strings.TrimSpace(expr)
+	if macro, ok := cronMacros[strings.ToLower(spec)]; ok {
+		spec = macro
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron expression %q: expected 5 fields, got %d", expr, len(fields))
+	}
+
+	schedule := &CronSchedule{expr: expr, location: location}
+	var err error
+	if schedule.minute, err = parseCronField(fields[0], minuteBounds); err != nil {
+		return nil, fmt.Errorf("invalid minute field: %w", err)
+	}
+	if schedule.hour, err = parseCronField(fields[1], hourBounds); err != nil {
+		return nil, fmt.Errorf("invalid hour field: %w", err)
+	}
+	if schedule.dom, err = parseCronField(fields[2], domBounds); err != nil {
+		return nil, fmt.Errorf("invalid day-of-month field: %w", err)
+	}
+	if schedule.month, err = parseCronField(fields[3], monthBounds); err != nil {
+		return nil, fmt.Errorf("invalid month field: %w", err)
+	}
+	if schedule.dow, err = parseCronField(fields[4], dowBounds); err != nil {
+		return nil, fmt.Errorf("invalid day-of-week field: %w", err)
+	}
+
+	// Sunday may be written as 0 or 7
+	if schedule.dow.has(7) {
+		schedule.dow |= 1
+	}
+	schedule.domStar = fields[2] == "*" || fields[2] == "?"
+	schedule.dowStar = fields[4] == "*" || fields[4] == "?"
+
+	return schedule, nil
+}
+
+func parseCronField(field string, bounds cronBounds) (cronField, error) {
+	var result cronField
+	for _, part := range strings.Split(field, ",") {
+		bits, err := parseCronRange(part, bounds)
+		if err != nil {
+			return 0, err
+		}
+		result |= bits
+	}
+	return result, nil
+}
+
+func parseCronRange(part string, bounds cronBounds) (cronField, error) {
+	step := 1
+	rangePart := part
+	if idx := strings.Index(part, "/"); idx >= 0 {
+		s, err := strconv.Atoi(part[idx+1:])
+		if err != nil || s <= 0 {
+			return 0, fmt.Errorf("invalid step in %q", part)
+		}
+		step = s
+		rangePart = part[:idx]
+	}
+
+	var start, end int
+	switch {
+	case rangePart == "*" || rangePart == "?":
+		start, end = bounds.min, bounds.max
+	case strings.Contains(rangePart, "-"):
+		pieces := strings.SplitN(rangePart, "-", 2)
+		var err error
+		if start, err = parseCronValue(pieces[0], bounds); err != nil {
+			return 0, err
+		}
+		if end, err = parseCronValue(pieces[1], bounds); err != nil {
+			return 0, err
+		}
+	default:
+		value, err := parseCronValue(rangePart, bounds)
+		if err != nil {
+			return 0, err
+		}
+		start = value
+		end = value
+		// "5/15" means starting at 5 through the maximum
+		if step > 1 {
+			end = bounds.max
+		}
+	}
+
+	if start > end {
+		return 0, fmt.Errorf("invalid range %q", part)
+	}
+
+	var bits cronField
+	for v := start; v <= end; v += step {
+		bits |= 1 << uint(v)
+	}
+	return bits, nil
+}
+
+func parseCronValue(value string, bounds cronBounds) (int, error) {
+	if bounds.names != nil {
+		if v, ok := bounds.names[strings.ToLower(value)]; ok {
+			return v, nil
+		}
+	}
+	v, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", value)
+	}
+	if v < bounds.min || v > bounds.max {
+		return 0, fmt.Errorf("value %d out of range [%d, %d]", v, bounds.min, bounds.max)
+	}
+	return v, nil
+}
+
+// String returns the original expression
+func (s *CronSchedule) String() string {
+	return s.expr
+}
+
+// Next returns the first activation strictly after the given time.
+//
+// Matching happens on wall-clock time in the schedule's timezone, so DST
+// transitions behave like classic cron: a time skipped by a spring-forward
+// transition runs at the equivalent instant just after the gap, and a time
+// repeated by a fall-back transition runs only once.
+func (s *CronSchedule) Next(after time.Time) time.Time {
+	wall := wallClock(after.In(s.location))
+
+	// A fall-back transition can repeat up to an hour of wall-clock minutes
+	for i := 0; i < 24*60; i++ {
+		// Expressions like "0 0 30 2 *" never match
+		candidate, ok := s.nextWall(wall)
+		if !ok {
+			return time.Time{}
+		}
+		next := time.Date(candidate.Year(), candidate.Month(), candidate.Day(), candidate.Hour(), candidate.Minute(), 0, 0, s.location)
+		// Wall times inside a DST gap don't exist; shift them past the gap
+		if got := wallClock(next); !got.Equal(candidate) {
+			next = next.Add(candidate.Sub(got))
+		}
+		if next.After(after) {
+			return next
+		}
+		// The wall time maps to an instant that already passed (repeated by DST); keep searching
+		wall = candidate
+	}
+	return time.Time{}
+}
+
+// wallClock returns the wall-clock minute of t as a DST-free time in UTC
+func wallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, time.UTC)
+}
+
+// nextWall finds the next matching wall-clock minute strictly after t, where t
+// is a DST-free wall time expressed in UTC
+func (s *CronSchedule) nextWall(t time.Time) (time.Time, bool) {
+	t = t.Add(time.Minute)
+	yearLimit := t.Year() + 5
+
+	for t.Year() <= yearLimit {
+		if !s.month.has(int(t.Month())) {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if !s.hour.has(t.Hour()) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, time.UTC)
+			continue
+		}
+		if !s.minute.has(t.Minute()) {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+// dayMatches applies cron's day-of-month/day-of-week rule: when both fields
+// are restricted, a day matching either one is accepted
+func (s *CronSchedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom.has(t.Day())
+	dowMatch := s.dow.has(int(t.Weekday()))
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
@@ -0,0 +1,376 @@
+package jobs
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata" // DST tests need America/New_York wherever they run
+
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+func newYork(t *testing.T) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("load location: %v", err)
+	}
+	return loc
+}
+
+// activations returns the next n activations of a cron expression after a time
+func activations(t *testing.T, expr string, loc *time.Location, after time.Time, n int) []time.Time {
+	t.Helper()
+	schedule, err := ParseCron(expr, loc)
+	if err != nil {
+		t.Fatalf("ParseCron(%q): %v", expr, err)
+	}
+	next := make([]time.Time, 0, n)
+	for i := 0; i < n; i++ {
+		after = schedule.Next(after)
+		next = append(next, after)
+	}
+	return next
+}
+
+func expectTimes(t *testing.T, got []time.Time, want ...time.Time) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d activations, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if !got[i].Equal(want[i]) {
+			t.Errorf("activation %d = %s, want %s", i, got[i], want[i])
+		}
+	}
+}
+
+func TestParseCron(t *testing.T) {
+	valid := []string{
+		"* * * * *",
+		"30 3 * * *",
+		"*/15 * * * *",
+		"5/15 * * * *",
+		"0 9-17 * * mon-fri",
+		"0 0 1,15 * *",
+		"0 0 * jan,jul 7",
+		"@daily",
+		"@Hourly",
+	}
+	for _, expr := range valid {
+		if _, err := ParseCron(expr, time.UTC); err != nil {
+			t.Errorf("ParseCron(%q) = %v, want nil", expr, err)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"10-5 * * * *",
+		"@sometimes",
+	}
+	for _, expr := range invalid {
+		if _, err := ParseCron(expr, time.UTC); err == nil {
+			t.Errorf("ParseCron(%q) = nil, want an error", expr)
+		}
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	start := time.Date(2025, 8, 13, 10, 7, 0, 0, time.UTC) // A Wednesday
+
+	tests := []struct {
+		expr string
+		want []time.Time
+	}{
+		{"*/15 * * * *", []time.Time{
+			time.Date(2025, 8, 13, 10, 15, 0, 0, time.UTC),
+			time.Date(2025, 8, 13, 10, 30, 0, 0, time.UTC),
+			time.Date(2025, 8, 13, 10, 45, 0, 0, time.UTC),
+		}},
+		{"30 3 * * *", []time.Time{
+			time.Date(2025, 8, 14, 3, 30, 0, 0, time.UTC),
+			time.Date(2025, 8, 15, 3, 30, 0, 0, time.UTC),
+		}},
+		{"0 9 * * sat,sun", []time.Time{
+			time.Date(2025, 8, 16, 9, 0, 0, 0, time.UTC),
+			time.Date(2025, 8, 17, 9, 0, 0, 0, time.UTC),
+			time.Date(2025, 8, 23, 9, 0, 0, 0, time.UTC),
+		}},
+		// Day-of-month and day-of-week both restricted: either one matches
+		{"0 0 1 * fri", []time.Time{
+			time.Date(2025, 8, 15, 0, 0, 0, 0, time.UTC),
+			time.Date(2025, 8, 22, 0, 0, 0, 0, time.UTC),
+			time.Date(2025, 8, 29, 0, 0, 0, 0, time.UTC),
+			time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC),
+		}},
+		{"0 0 29 2 *", []time.Time{
+			time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC),
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			expectTimes(t, activations(t, tt.expr, time.UTC, start, len(tt.want)), tt.want...)
+		})
+	}
+
+	// An exact activation time is not returned again
+	expectTimes(t, activations(t, "*/15 * * * *", time.UTC, time.Date(2025, 8, 13, 10, 15, 0, 0, time.UTC), 1),
+		time.Date(2025, 8, 13, 10, 30, 0, 0, time.UTC))
+
+	// Impossible dates never fire
+	schedule, err := ParseCron("0 0 30 2 *", time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next := schedule.Next(start); !next.IsZero() {
+		t.Errorf("Next for February 30th = %s, want zero time", next)
+	}
+}
+
+func TestCronNextSpringForward(t *testing.T) {
+	ny := newYork(t)
+	// Clocks jump from 02:00 EST to 03:00 EDT on 2025-03-09
+	before := time.Date(2025, 3, 9, 1, 0, 0, 0, ny)
+
+	t.Run("skipped time runs after the gap", func(t *testing.T) {
+		expectTimes(t, activations(t, "30 2 * * *", ny, before, 2),
+			time.Date(2025, 3, 9, 7, 30, 0, 0, time.UTC),  // 03:30 EDT
+			time.Date(2025, 3, 10, 6, 30, 0, 0, time.UTC), // 02:30 EDT
+		)
+	})
+
+	t.Run("half-hourly skips the missing hour", func(t *testing.T) {
+		expectTimes(t, activations(t, "*/30 * * * *", ny, before, 3),
+			time.Date(2025, 3, 9, 6, 30, 0, 0, time.UTC), // 01:30 EST
+			time.Date(2025, 3, 9, 7, 0, 0, 0, time.UTC),  // 03:00 EDT
+			time.Date(2025, 3, 9, 7, 30, 0, 0, time.UTC), // 03:30 EDT
+		)
+	})
+
+	t.Run("times outside the gap keep their wall clock", func(t *testing.T) {
+		expectTimes(t, activations(t, "0 4 * * *", ny, before, 2),
+			time.Date(2025, 3, 9, 8, 0, 0, 0, time.UTC),  // 04:00 EDT
+			time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC), // 04:00 EDT
+		)
+	})
+}
+
+func TestCronNextFallBack(t *testing.T) {
+	ny := newYork(t)
+	// Clocks go back from 02:00 EDT to 01:00 EST on 2025-11-02, repeating 01:00-02:00
+
+	t.Run("repeated time runs once", func(t *testing.T) {
+		expectTimes(t, activations(t, "30 1 * * *", ny, time.Date(2025, 11, 2, 0, 0, 0, 0, ny), 2),
+			time.Date(2025, 11, 2, 5, 30, 0, 0, time.UTC), // 01:30 EDT
+			time.Date(2025, 11, 3, 6, 30, 0, 0, time.UTC), // 01:30 EST the next day
+		)
+	})
+
+	t.Run("half-hourly does not repeat the hour", func(t *testing.T) {
+		expectTimes(t, activations(t, "*/30 * * * *", ny, time.Date(2025, 11, 2, 0, 10, 0, 0, ny), 4),
+			time.Date(2025, 11, 2, 4, 30, 0, 0, time.UTC), // 00:30 EDT
+			time.Date(2025, 11, 2, 5, 0, 0, 0, time.UTC),  // 01:00 EDT
+			time.Date(2025, 11, 2, 5, 30, 0, 0, time.UTC), // 01:30 EDT
+			time.Date(2025, 11, 2, 7, 0, 0, 0, time.UTC),  // 02:00 EST
+		)
+	})
+
+	t.Run("inside the repeated hour", func(t *testing.T) {
+		// 01:10 EST, the second pass through 01:10; 01:30 already ran during the first one
+		after := time.Date(2025, 11, 2, 6, 10, 0, 0, time.UTC)
+		expectTimes(t, activations(t, "*/30 * * * *", ny, after, 1),
+			time.Date(2025, 11, 2, 7, 0, 0, 0, time.UTC), // 02:00 EST
+		)
+	})
+}
+
+// newTestScheduler builds a scheduler whose jitter draws are returned by random
+func newTestScheduler(t *testing.T, config JobConfig, random func(n int64) int64) *scheduler {
+	t.Helper()
+	sched, err := newScheduler(config)
+	if err != nil {
+		t.Fatalf("newScheduler: %v", err)
+	}
+	if random != nil {
+		sched.random = random
+	}
+	return sched
+}
+
+func TestSchedulerJitterBounds(t *testing.T) {
+	base := time.Date(2025, 8, 13, 3, 30, 0, 0, time.UTC)
+	config := JobConfig{Schedule: "30 3 * * *", Timezone: "UTC", Jitter: 10 * time.Minute}
+
+	t.Run("extremes", func(t *testing.T) {
+		var drawn int64
+		low := newTestScheduler(t, config, func(n int64) int64 { drawn = n; return 0 })
+		if fire := low.fireTime(base); !fire.Equal(base) {
+			t.Errorf("smallest jitter fires at %s, want %s", fire, base)
+		}
+		if drawn != int64(config.Jitter) {
+			t.Errorf("jitter drawn from [0, %d), want [0, %d)", drawn, int64(config.Jitter))
+		}
+
+		high := newTestScheduler(t, config, func(n int64) int64 { return n - 1 })
+		want := base.Add(config.Jitter - time.Nanosecond)
+		if fire := high.fireTime(base); !fire.Equal(want) {
+			t.Errorf("largest jitter fires at %s, want %s", fire, want)
+		}
+	})
+
+	t.Run("random", func(t *testing.T) {
+		sched := newTestScheduler(t, config, nil)
+		for i := 0; i < 1000; i++ {
+			fire := sched.fireTime(base)
+			if fire.Before(base) || !fire.Before(base.Add(config.Jitter)) {
+				t.Fatalf("fire time %s outside [%s, %s)", fire, base, base.Add(config.Jitter))
+			}
+		}
+	})
+
+	t.Run("no jitter", func(t *testing.T) {
+		sched := newTestScheduler(t, JobConfig{Schedule: "30 3 * * *", Timezone: "UTC"}, func(n int64) int64 {
+			t.Fatal("random drawn without jitter")
+			return 0
+		})
+		if fire := sched.fireTime(base); !fire.Equal(base) {
+			t.Errorf("fire time = %s, want %s", fire, base)
+		}
+	})
+
+	t.Run("negative", func(t *testing.T) {
+		if err := ValidateSchedule(JobConfig{Interval: time.Hour, Jitter: -time.Second}); err == nil {
+			t.Error("negative jitter should be rejected")
+		}
+	})
+}
+
+func TestSchedulerBlackouts(t *testing.T) {
+	ny := newYork(t)
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2025, 8, day, hour, minute, 0, 0, ny)
+	}
+
+	tests := []struct {
+		name      string
+		blackouts []structures.JobBlackoutWindow
+		base      time.Time
+		want      time.Time
+	}{
+		{
+			name:      "outside the window",
+			blackouts: []structures.JobBlackoutWindow{{Start: "18:00", End: "23:00"}},
+			base:      at(13, 12, 0),
+			want:      at(13, 12, 0),
+		},
+		{
+			name:      "deferred to the end",
+			blackouts: []structures.JobBlackoutWindow{{Start: "18:00", End: "23:00"}},
+			base:      at(13, 19, 30),
+			want:      at(13, 23, 0),
+		},
+		{
+			name:      "end is exclusive",
+			blackouts: []structures.JobBlackoutWindow{{Start: "18:00", End: "23:00"}},
+			base:      at(13, 23, 0),
+			want:      at(13, 23, 0),
+		},
+		{
+			name:      "late part of a window crossing midnight",
+			blackouts: []structures.JobBlackoutWindow{{Start: "22:00", End: "06:00"}},
+			base:      at(13, 23, 15),
+			want:      at(14, 6, 0),
+		},
+		{
+			name:      "early part of a window crossing midnight",
+			blackouts: []structures.JobBlackoutWindow{{Start: "22:00", End: "06:00"}},
+			base:      at(14, 2, 0),
+			want:      at(14, 6, 0),
+		},
+		{
+			name:      "window on another weekday",
+			blackouts: []structures.JobBlackoutWindow{{Start: "09:00", End: "17:00", Days: []time.Weekday{time.Saturday, time.Sunday}}},
+			base:      at(13, 10, 0), // Wednesday
+			want:      at(13, 10, 0),
+		},
+		{
+			name:      "window on this weekday",
+			blackouts: []structures.JobBlackoutWindow{{Start: "09:00", End: "17:00", Days: []time.Weekday{time.Saturday, time.Sunday}}},
+			base:      at(16, 10, 0), // Saturday
+			want:      at(16, 17, 0),
+		},
+		{
+			name:      "early part belongs to the previous day's window",
+			blackouts: []structures.JobBlackoutWindow{{Start: "22:00", End: "06:00", Days: []time.Weekday{time.Friday}}},
+			base:      at(16, 1, 0), // Saturday, inside Friday night's window
+			want:      at(16, 6, 0),
+		},
+		{
+			name:      "early part after a day without the window",
+			blackouts: []structures.JobBlackoutWindow{{Start: "22:00", End: "06:00", Days: []time.Weekday{time.Friday}}},
+			base:      at(17, 1, 0), // Sunday, Saturday night has no window
+			want:      at(17, 1, 0),
+		},
+		{
+			name: "chained windows",
+			blackouts: []structures.JobBlackoutWindow{
+				{Start: "14:00", End: "15:00"},
+				{Start: "12:00", End: "14:00"},
+			},
+			base: at(13, 12, 30),
+			want: at(13, 15, 0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sched := newTestScheduler(t, JobConfig{
+				Schedule:  "* * * * *",
+				Timezone:  "America/New_York",
+				Blackouts: tt.blackouts,
+			}, nil)
+			if got := sched.fireTime(tt.base); !got.Equal(tt.want) {
+				t.Errorf("fireTime(%s) = %s, want %s", tt.base, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchedulerJitterIntoBlackout(t *testing.T) {
+	base := time.Date(2025, 8, 13, 17, 55, 0, 0, time.UTC)
+	sched := newTestScheduler(t, JobConfig{
+		Schedule:  "55 17 * * *",
+		Timezone:  "UTC",
+		Jitter:    10 * time.Minute,
+		Blackouts: []structures.JobBlackoutWindow{{Start: "18:00", End: "19:00"}},
+	}, func(n int64) int64 { return int64(8 * time.Minute) })
+
+	want := time.Date(2025, 8, 13, 19, 0, 0, 0, time.UTC)
+	if got := sched.fireTime(base); !got.Equal(want) {
+		t.Errorf("jittered run landing in a blackout fires at %s, want %s", got, want)
+	}
+}
+
+func TestBlackoutValidation(t *testing.T) {
+	invalid := []structures.JobBlackoutWindow{
+		{Start: "10:00", End: "10:00"},
+		{Start: "24:00", End: "01:00"},
+		{Start: "10:60", End: "11:00"},
+		{Start: "10", End: "11:00"},
+		{Start: "10:00", End: "11:00", Days: []time.Weekday{7}},
+	}
+	for _, window := range invalid {
+		config := JobConfig{Interval: time.Hour, Blackouts: []structures.JobBlackoutWindow{window}}
+		if err := ValidateSchedule(config); err == nil {
+			t.Errorf("blackout %+v should be rejected", window)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/mahcks/serra/internal/db/repository"
+)
+
+// Keys of the state the job leader works out and every instance serves
+const (
+	sharedStateBandwidth = "bandwidth"
+	sharedStateDownloads = "downloads"
+)
+
+// storeSharedState stores state for the other instances sharing the database
+func storeSharedState(ctx context.Context, query *repository.Queries, key string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return query.SetSharedState(ctx, repository.SetSharedStateParams{
+		Key:       key,
+		Value:     string(data),
+		UpdatedAt: time.Now().UTC(),
+	})
+}
+
+// loadSharedState decodes the state stored under key into value and reports
+// whether any was stored
+func loadSharedState(ctx context.Context, query *repository.Queries, key string, value interface{}) (bool, error) {
+	state, err := query.GetSharedState(ctx, key)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, json.Unmarshal([]byte(state.Value), value)
+}
@@ -0,0 +1,45 @@
+package jobs
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/mahcks/serra/internal/global"
+)
+
+// notifyAvailableWatchlistItems tells users that titles on their watchlist
+// reached the library. Each item is only notified once. It returns the number
+// of items that were notified.
+func notifyAvailableWatchlistItems(ctx context.Context, gctx global.Context) int {
+	query := gctx.Crate().Sqlite.Query()
+	items, err := query.GetNewlyAvailableWatchlistItems(ctx)
+	if err != nil {
+		slog.Error("Failed to get newly available watchlist items", "error", err)
+		return 0
+	}
+
+	notified := 0
+	for _, item := range items {
+		if notifications := gctx.Crate().NotificationService; notifications != nil {
+			tmdbID := item.TmdbID
+			if err := notifications.NotifyWatchlistAvailable(ctx, item.UserID, item.Title, item.MediaType, &tmdbID); err != nil {
+				slog.Warn("Failed to notify user about available watchlist item",
+					"watchlist_item_id", item.ID,
+					"user_id", item.UserID,
+					"error", err)
+				continue
+			}
+		}
+
+		if err := query.MarkWatchlistItemNotified(ctx, item.ID); err != nil {
+			slog.Error("Failed to mark watchlist item as notified", "watchlist_item_id", item.ID, "error", err)
+			continue
+		}
+		notified++
+	}
+
+	if notified > 0 {
+		slog.Info("Notified users about available watchlist items", "count", notified)
+	}
+	return notified
+}
@@ -0,0 +1,46 @@
+package jobs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mahcks/serra/config"
+	"github.com/mahcks/serra/internal/db/dbtest"
+	"github.com/mahcks/serra/internal/global"
+)
+
+func TestNotifyAvailableWatchlistItemsOnce(t *testing.T) {
+	gctx := global.New(context.Background(), &config.Bootstrap{}, "test", "")
+	gctx.Crate().Sqlite = dbtest.Service(t)
+	ctx := context.Background()
+
+	statements := []string{
+		`INSERT INTO users (id, username) VALUES ('alice', 'alice')`,
+		`INSERT INTO library_items (id, name, type, tmdb_id, updated_at) VALUES ('lib', 'Arrived', 'Movie', '100', CURRENT_TIMESTAMP)`,
+		`INSERT INTO watchlist_items (user_id, media_type, tmdb_id, title) VALUES
+			('alice', 'movie', 100, 'Arrived'),
+			('alice', 'movie', 200, 'Not yet')`,
+	}
+	for _, statement := range statements {
+		if _, err := gctx.Crate().Sqlite.DB().Exec(statement); err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+
+	if notified := notifyAvailableWatchlistItems(ctx, gctx); notified != 1 {
+		t.Fatalf("notified %d items, want 1", notified)
+	}
+	if notified := notifyAvailableWatchlistItems(ctx, gctx); notified != 0 {
+		t.Errorf("notified %d items again, want 0", notified)
+	}
+
+	items, err := gctx.Crate().Sqlite.Query().GetUserWatchlist(ctx, "alice")
+	if err != nil {
+		t.Fatalf("get watchlist: %v", err)
+	}
+	for _, item := range items {
+		if notified := item.AvailableNotifiedAt.Valid; notified != (item.TmdbID == 100) {
+			t.Errorf("%s: notified = %v", item.Title, notified)
+		}
+	}
+}
@@ -1,15 +1,24 @@
 package middleware
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"log/slog"
 	"sync"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/mahcks/serra/internal/db/repository"
 	apiErrors "github.com/mahcks/serra/pkg/api_errors"
 )
 
+// csrfTokens issues single-use CSRF tokens
+type csrfTokens interface {
+	generateToken(ctx context.Context) (string, error)
+	validateToken(ctx context.Context, token string) bool
+}
+
 type csrfTokenStore struct {
 	mu     sync.RWMutex
 	tokens map[string]time.Time
@@ -26,13 +35,20 @@ func newCSRFStore() *csrfTokenStore {
 	return store
 }
 
-func (cs *csrfTokenStore) generateToken() (string, error) {
+// newCSRFTokenValue returns a random token
+func newCSRFTokenValue() (string, error) {
 	bytes := make([]byte, 32)
 	if _, err := rand.Read(bytes); err != nil {
 		return "", err
 	}
+	return hex.EncodeToString(bytes), nil
+}
 
-	token := hex.EncodeToString(bytes)
+func (cs *csrfTokenStore) generateToken(ctx context.Context) (string, error) {
+	token, err := newCSRFTokenValue()
+	if err != nil {
+		return "", err
+	}
 
 	cs.mu.Lock()
 	cs.tokens[token] = time.Now().Add(1 * time.Hour) // 1 hour expiry
@@ -41,7 +57,7 @@ func (cs *csrfTokenStore) generateToken() (string, error) {
 	return token, nil
 }
 
-func (cs *csrfTokenStore) validateToken(token string) bool {
+func (cs *csrfTokenStore) validateToken(ctx context.Context, token string) bool {
 	cs.mu.RLock()
 	expiry, exists := cs.tokens[token]
 	cs.mu.RUnlock()
@@ -82,7 +98,42 @@ func (cs *csrfTokenStore) cleanup() {
 	}
 }
 
-var csrfStore = newCSRFStore()
+// dbCSRFStore keeps the tokens in the database so a token issued by one
+// instance is accepted by every other
+type dbCSRFStore struct {
+	query *repository.Queries
+}
+
+func (s *dbCSRFStore) generateToken(ctx context.Context) (string, error) {
+	token, err := newCSRFTokenValue()
+	if err != nil {
+		return "", err
+	}
+
+	err = s.query.CreateCSRFToken(ctx, repository.CreateCSRFTokenParams{
+		Token:     token,
+		ExpiresAt: time.Now().UTC().Add(1 * time.Hour), // 1 hour expiry
+	})
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (s *dbCSRFStore) validateToken(ctx context.Context, token string) bool {
+	// Deleting the token consumes it, so it is only accepted once across instances
+	consumed, err := s.query.ConsumeCSRFToken(ctx, repository.ConsumeCSRFTokenParams{
+		Token:     token,
+		ExpiresAt: time.Now().UTC(),
+	})
+	if err != nil {
+		slog.Error("Failed to validate CSRF token", "error", err)
+		return false
+	}
+	return consumed == 1
+}
+
+var csrfStore csrfTokens = newCSRFStore()
 
 // CSRFProtection provides CSRF protection for state-changing operations
 func CSRFProtection() fiber.Handler {
@@ -101,7 +152,7 @@ func CSRFProtection() fiber.Handler {
 		}
 
 		// Validate token
-		if !csrfStore.validateToken(token) {
+		if !csrfStore.validateToken(c.Context(), token) {
 			return apiErrors.ErrForbidden().SetDetail("Invalid or expired CSRF token")
 		}
 
@@ -111,5 +162,5 @@ func CSRFProtection() fiber.Handler {
 
 // GenerateCSRFToken generates a new CSRF token
 func GenerateCSRFToken() (string, error) {
-	return csrfStore.generateToken()
+	return csrfStore.generateToken(context.Background())
 }
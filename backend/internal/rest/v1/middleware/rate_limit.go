@@ -1,13 +1,21 @@
 package middleware
 
 import (
+	"context"
+	"log/slog"
 	"sync"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/mahcks/serra/internal/db/repository"
 	apiErrors "github.com/mahcks/serra/pkg/api_errors"
 )
 
+// limiter decides whether a client may make another request
+type limiter interface {
+	isAllowed(ctx context.Context, key string) bool
+}
+
 type rateLimiter struct {
 	mu         sync.Mutex
 	requests   map[string][]time.Time
@@ -23,7 +31,7 @@ func newRateLimiter(maxReqs int, windowSize time.Duration) *rateLimiter {
 	}
 }
 
-func (rl *rateLimiter) isAllowed(key string) bool {
+func (rl *rateLimiter) isAllowed(ctx context.Context, key string) bool {
 	rl.mu.Lock()
 	defer rl.mu.Unlock()
 
@@ -77,9 +85,36 @@ func (rl *rateLimiter) cleanup() {
 	}
 }
 
+// dbRateLimiter counts requests in the database so the limit holds across instances
+type dbRateLimiter struct {
+	query      *repository.Queries
+	name       string
+	maxReqs    int
+	windowSize time.Duration
+}
+
+func (rl *dbRateLimiter) isAllowed(ctx context.Context, key string) bool {
+	now := time.Now().UTC()
+	recorded, err := rl.query.RecordRateLimitHit(ctx, repository.RecordRateLimitHitParams{
+		Key:         rl.name + ":" + key,
+		HitAt:       now,
+		WindowStart: now.Add(-rl.windowSize),
+		MaxHits:     int64(rl.maxReqs),
+	})
+	if err != nil {
+		// Fail open, the limiter only slows down abuse
+		slog.Error("Failed to record rate limit hit", "limiter", rl.name, "error", err)
+		return true
+	}
+	return recorded == 1
+}
+
 var (
 	// 5 requests per minute for invitation acceptance
 	inviteRateLimiter = newRateLimiter(5, time.Minute)
+
+	// Limiter RateLimitInvitations checks, moved to the database by UseDatabase
+	invitationLimiter limiter = inviteRateLimiter
 )
 
 // Start cleanup goroutine
@@ -102,7 +137,7 @@ func RateLimitInvitations() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		clientIP := c.IP()
 		
-		if !inviteRateLimiter.isAllowed(clientIP) {
+		if !invitationLimiter.isAllowed(c.Context(), clientIP) {
 			return apiErrors.ErrTooManyRequests().SetDetail("Too many invitation requests. Please try again later.")
 		}
 		
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/mahcks/serra/internal/db/repository"
+)
+
+// UseDatabase moves the CSRF tokens and rate limits into the database, so
+// every instance of a deployment sharing it accepts the same tokens and counts
+// the same requests. Expired entries are pruned until ctx is done.
+func UseDatabase(ctx context.Context, query *repository.Queries) {
+	csrfStore = &dbCSRFStore{query: query}
+	invitationLimiter = &dbRateLimiter{
+		query:      query,
+		name:       "invitations",
+		maxReqs:    inviteRateLimiter.maxReqs,
+		windowSize: inviteRateLimiter.windowSize,
+	}
+
+	go pruneSharedState(ctx, query)
+}
+
+// pruneSharedState deletes expired CSRF tokens and rate limit hits outside every window
+func pruneSharedState(ctx context.Context, query *repository.Queries) {
+	ticker := time.NewTicker(5 * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			now := time.Now().UTC()
+			if err := query.DeleteExpiredCSRFTokens(ctx, now); err != nil {
+				slog.Error("Failed to delete expired CSRF tokens", "error", err)
+			}
+			// Longer than any limiter's window
+			if err := query.DeleteRateLimitHitsBefore(ctx, now.Add(-time.Hour)); err != nil {
+				slog.Error("Failed to delete old rate limit hits", "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mahcks/serra/internal/db/dbtest"
+	"github.com/mahcks/serra/internal/db/repository"
+)
+
+func TestDatabaseCSRFTokensAreSharedAndSingleUse(t *testing.T) {
+	ctx := context.Background()
+	_, query := dbtest.Open(t)
+
+	// Two instances sharing the database
+	issuer := &dbCSRFStore{query: query}
+	validator := &dbCSRFStore{query: query}
+
+	token, err := issuer.generateToken(ctx)
+	if err != nil {
+		t.Fatalf("generate token: %v", err)
+	}
+
+	if !validator.validateToken(ctx, token) {
+		t.Fatal("token issued by one instance should be accepted by another")
+	}
+	if issuer.validateToken(ctx, token) || validator.validateToken(ctx, token) {
+		t.Fatal("token should only be accepted once")
+	}
+	if validator.validateToken(ctx, "unknown") {
+		t.Fatal("unknown token should be rejected")
+	}
+
+	err = query.CreateCSRFToken(ctx, repository.CreateCSRFTokenParams{
+		Token:     "expired",
+		ExpiresAt: time.Now().UTC().Add(-time.Minute),
+	})
+	if err != nil {
+		t.Fatalf("create expired token: %v", err)
+	}
+	if validator.validateToken(ctx, "expired") {
+		t.Fatal("expired token should be rejected")
+	}
+}
+
+func TestDatabaseRateLimiterIsShared(t *testing.T) {
+	ctx := context.Background()
+	_, query := dbtest.Open(t)
+
+	newLimiter := func() *dbRateLimiter {
+		return &dbRateLimiter{query: query, name: "invitations", maxReqs: 5, windowSize: time.Minute}
+	}
+	a, b := newLimiter(), newLimiter()
+
+	for i := 0; i < 5; i++ {
+		limiter := a
+		if i%2 == 1 {
+			limiter = b
+		}
+		if !limiter.isAllowed(ctx, "10.0.0.1") {
+			t.Fatalf("request %d should be allowed", i+1)
+		}
+	}
+	if a.isAllowed(ctx, "10.0.0.1") || b.isAllowed(ctx, "10.0.0.1") {
+		t.Fatal("limit should hold across instances")
+	}
+	if !a.isAllowed(ctx, "10.0.0.2") {
+		t.Fatal("other clients should have their own limit")
+	}
+
+	// Hits outside the window don't count
+	for i := 0; i < 5; i++ {
+		_, err := query.RecordRateLimitHit(ctx, repository.RecordRateLimitHitParams{
+			Key:         "invitations:10.0.0.3",
+			HitAt:       time.Now().UTC().Add(-2 * time.Minute),
+			WindowStart: time.Now().UTC().Add(-3 * time.Minute),
+			MaxHits:     5,
+		})
+		if err != nil {
+			t.Fatalf("record old hit: %v", err)
+		}
+	}
+	if !a.isAllowed(ctx, "10.0.0.3") {
+		t.Fatal("hits outside the window shouldn't count")
+	}
+}
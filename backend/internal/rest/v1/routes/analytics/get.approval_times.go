@@ -0,0 +1,106 @@
+package analytics
+
+import (
+	"database/sql"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/mahcks/serra/internal/jobs"
+	"github.com/mahcks/serra/internal/rest/v1/respond"
+	"github.com/mahcks/serra/pkg/api_errors"
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+// GetApprovalTimes returns the median time to approve and to fulfil requests
+// per approver and media type
+func (rg *RouteGroup) GetApprovalTimes(ctx *respond.Ctx) error {
+	user := ctx.ParseClaims()
+	if user == nil || !user.IsAdmin {
+		return apiErrors.ErrInsufficientPermissions()
+	}
+
+	daysParam := ctx.Query("days", "90")
+	days, err := strconv.Atoi(daysParam)
+	if err != nil || days < 1 {
+		days = 90
+	}
+
+	since := time.Now().AddDate(0, 0, -days).UTC()
+
+	rows, err := rg.gctx.Crate().Sqlite.Query().GetApprovalDurations(ctx.Context(), sql.NullTime{Time: since, Valid: true})
+	if err != nil {
+		return apiErrors.ErrInternalServerError().SetDetail("Failed to get approval times")
+	}
+
+	type group struct {
+		response    structures.ApprovalTimeResponse
+		approvals   []float64
+		fulfillment []float64
+	}
+
+	groups := make(map[string]*group)
+	var keys []string
+	for _, row := range rows {
+		key := row.ApproverID + "|" + row.MediaType
+		g, ok := groups[key]
+		if !ok {
+			g = &group{response: structures.ApprovalTimeResponse{
+				ApproverID: row.ApproverID,
+				Username:   row.Username.String,
+				MediaType:  row.MediaType,
+			}}
+			groups[key] = g
+			keys = append(keys, key)
+		}
+
+		if row.ProcessingTimeSeconds.Valid {
+			g.approvals = append(g.approvals, float64(row.ProcessingTimeSeconds.Int64)/3600)
+		}
+		if row.FulfilledAt.Valid {
+			g.fulfillment = append(g.fulfillment, row.FulfilledAt.Time.Sub(row.CreatedAt).Hours())
+		}
+	}
+
+	approvers := make([]structures.ApprovalTimeResponse, 0, len(keys))
+	for _, key := range keys {
+		g := groups[key]
+		g.response.Approved = len(g.approvals)
+		g.response.MedianApprovalHours = median(g.approvals)
+		g.response.Fulfilled = len(g.fulfillment)
+		if len(g.fulfillment) > 0 {
+			m := median(g.fulfillment)
+			g.response.MedianFulfillmentHours = &m
+		}
+		approvers = append(approvers, g.response)
+	}
+
+	sort.Slice(approvers, func(i, j int) bool {
+		if approvers[i].Approved != approvers[j].Approved {
+			return approvers[i].Approved > approvers[j].Approved
+		}
+		return approvers[i].ApproverID+approvers[i].MediaType < approvers[j].ApproverID+approvers[j].MediaType
+	})
+
+	return ctx.JSON(map[string]interface{}{
+		"approvers":   approvers,
+		"sla_hours":   jobs.RequestSLAHours(ctx.Context(), rg.gctx.Crate().Sqlite.Query()),
+		"period_days": days,
+	})
+}
+
+// median returns the median of values, 0 when there are none
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
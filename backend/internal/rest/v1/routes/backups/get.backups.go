@@ -0,0 +1,47 @@
+package backups
+
+import (
+	"log/slog"
+
+	"github.com/mahcks/serra/internal/rest/v1/respond"
+	apiErrors "github.com/mahcks/serra/pkg/api_errors"
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+// GetBackups lists the database backups in the backup directory, newest first
+func (rg *RouteGroup) GetBackups(ctx *respond.Ctx) error {
+	user := ctx.ParseClaims()
+	if user == nil || user.ID == "" {
+		return apiErrors.ErrUnauthorized()
+	}
+
+	service := rg.gctx.Crate().Backup
+	backups, err := service.List()
+	if err != nil {
+		slog.Error("Failed to list backups", "error", err)
+		return apiErrors.ErrInternalServerError().SetDetail("Failed to list backups")
+	}
+
+	return ctx.JSON(structures.BackupsResponse{
+		Backups:   backups,
+		Directory: service.Directory(),
+		Retention: service.Retention(ctx.Context()),
+	})
+}
+
+// DownloadBackup streams a backup file to the client
+func (rg *RouteGroup) DownloadBackup(ctx *respond.Ctx) error {
+	user := ctx.ParseClaims()
+	if user == nil || user.ID == "" {
+		return apiErrors.ErrUnauthorized()
+	}
+
+	name := ctx.Params("name")
+	path, err := rg.gctx.Crate().Backup.Path(name)
+	if err != nil {
+		return apiErrors.ErrNotFound().SetDetail("Backup not found: %s", name)
+	}
+
+	slog.Info("Backup downloaded", "name", name, "user_id", user.ID)
+	return ctx.Download(path, name)
+}
@@ -0,0 +1,33 @@
+package backups
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/mahcks/serra/internal/rest/v1/respond"
+	apiErrors "github.com/mahcks/serra/pkg/api_errors"
+)
+
+// ExportConfig returns a JSON export of the configuration tables. Secrets are
+// redacted unless redact_secrets=false is passed.
+func (rg *RouteGroup) ExportConfig(ctx *respond.Ctx) error {
+	user := ctx.ParseClaims()
+	if user == nil || user.ID == "" {
+		return apiErrors.ErrUnauthorized()
+	}
+
+	redact := ctx.QueryBool("redact_secrets", true)
+
+	export, err := rg.gctx.Crate().Backup.Export(ctx.Context(), rg.gctx.Metadata().Version, redact)
+	if err != nil {
+		slog.Error("Failed to export configuration", "error", err)
+		return apiErrors.ErrInternalServerError().SetDetail("Failed to export configuration")
+	}
+
+	slog.Info("Configuration exported", "user_id", user.ID, "redacted", redact)
+
+	filename := fmt.Sprintf("serra-config-%s.json", time.Now().UTC().Format("20060102-150405"))
+	ctx.Attachment(filename)
+	return ctx.JSON(export)
+}
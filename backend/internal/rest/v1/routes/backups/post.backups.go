@@ -0,0 +1,92 @@
+package backups
+
+import (
+	"errors"
+	"log/slog"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mahcks/serra/internal/db/migrate"
+	"github.com/mahcks/serra/internal/rest/v1/respond"
+	"github.com/mahcks/serra/internal/services/backup"
+	"github.com/mahcks/serra/internal/services/sqlite"
+	"github.com/mahcks/serra/migrations"
+	apiErrors "github.com/mahcks/serra/pkg/api_errors"
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+// CreateBackup takes an online backup of the database immediately
+func (rg *RouteGroup) CreateBackup(ctx *respond.Ctx) error {
+	user := ctx.ParseClaims()
+	if user == nil || user.ID == "" {
+		return apiErrors.ErrUnauthorized()
+	}
+
+	created, err := rg.gctx.Crate().Backup.Create(ctx.Context(), structures.BackupKindManual)
+	if err != nil {
+		slog.Error("Failed to create backup", "error", err, "user_id", user.ID)
+		return apiErrors.ErrInternalServerError().SetDetail("Failed to create backup")
+	}
+
+	slog.Info("Manual backup created", "name", created.Name, "user_id", user.ID)
+	return ctx.Status(fiber.StatusCreated).JSON(created)
+}
+
+// RestoreBackup replaces the live database with a backup. The current database
+// is backed up first, the restored database is migrated to the current schema
+// and the configuration is reloaded once the swap is done.
+func (rg *RouteGroup) RestoreBackup(ctx *respond.Ctx) error {
+	user := ctx.ParseClaims()
+	if user == nil || user.ID == "" {
+		return apiErrors.ErrUnauthorized()
+	}
+
+	name := ctx.Params("name")
+	path, err := rg.gctx.Crate().Backup.Path(name)
+	if err != nil {
+		return apiErrors.ErrNotFound().SetDetail("Backup not found: %s", name)
+	}
+
+	// Refuse backups written by a newer release before touching the live database
+	if err := migrate.CheckFile(ctx.Context(), path, migrations.FS); err != nil {
+		if errors.Is(err, migrate.ErrDatabaseNewer) {
+			return apiErrors.ErrConflict().SetDetail("Backup was created by a newer version of Serra")
+		}
+		slog.Error("Failed to check backup schema", "error", err, "name", name)
+		return apiErrors.ErrBadRequest().SetDetail("Backup could not be read")
+	}
+
+	slog.Warn("Restoring database from backup", "name", name, "user_id", user.ID)
+
+	safety, err := rg.gctx.Crate().Backup.Restore(ctx.Context(), name)
+	if err != nil {
+		if errors.Is(err, backup.ErrNotFound) {
+			return apiErrors.ErrNotFound().SetDetail("Backup not found: %s", name)
+		}
+		if errors.Is(err, sqlite.ErrDatabaseBusy) {
+			return apiErrors.ErrConflict().SetDetail("The database is in use, try the restore again in a moment")
+		}
+		slog.Error("Failed to restore backup", "error", err, "name", name)
+		return apiErrors.ErrInternalServerError().SetDetail("Failed to restore backup: %s", err.Error())
+	}
+
+	// Older backups are brought up to date; the pre-restore backup already covers rollback
+	db, release, err := rg.gctx.Crate().Sqlite.Hold()
+	if err != nil {
+		return apiErrors.ErrConflict().SetDetail("Backup restored but the database is in use, restart Serra to apply migrations")
+	}
+	defer release()
+	if _, err := migrate.Run(ctx.Context(), db, migrations.FS, nil); err != nil {
+		slog.Error("Failed to migrate restored database", "error", err, "name", name)
+		return apiErrors.ErrInternalServerError().SetDetail("Backup restored but migrations failed: %s", err.Error())
+	}
+
+	if err := rg.gctx.Crate().Config.Load(ctx.Context()); err != nil {
+		slog.Error("Failed to reload configuration after restore", "error", err)
+	}
+
+	return ctx.JSON(fiber.Map{
+		"message":            "Database restored",
+		"restored":           name,
+		"pre_restore_backup": safety.Name,
+	})
+}
@@ -49,9 +49,11 @@ func (rg *RouteGroup) GetMediaStatus(ctx *respond.Ctx) error {
 
 	// Return the status information
 	return ctx.JSON(map[string]interface{}{
-		"tmdb_id":     tmdbID,
-		"media_type":  mediaType,
-		"in_library":  enrichedItem.InLibrary,
-		"requested":   enrichedItem.Requested,
+		"tmdb_id":       tmdbID,
+		"media_type":    mediaType,
+		"in_library":    enrichedItem.InLibrary,
+		"requested":     enrichedItem.Requested,
+		"in_library_4k": enrichedItem.InLibrary4K,
+		"requested_4k":  enrichedItem.Requested4K,
 	})
 }
\ No newline at end of file
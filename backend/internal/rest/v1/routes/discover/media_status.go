@@ -18,145 +18,26 @@ func (rg *RouteGroup) enrichWithMediaStatus(ctx context.Context, response *struc
 		}, nil
 	}
 
-	// Extract TMDB IDs for batch processing
 	tmdbIDs := make([]int64, 0, len(response.Results))
-	tmdbIDStrings := make([]string, 0, len(response.Results))
-
 	for _, item := range response.Results {
 		tmdbIDs = append(tmdbIDs, item.ID)
-		tmdbIDStrings = append(tmdbIDStrings, strconv.FormatInt(item.ID, 10))
-	}
-
-	// Batch check library status for all items - check individually since current implementation doesn't support batch
-	libraryMap := make(map[string]bool)
-	for _, tmdbIDStr := range tmdbIDStrings {
-		inLibrary, err := rg.gctx.Crate().Sqlite.Query().CheckMediaInLibrary(ctx, sql.NullString{
-			String: tmdbIDStr,
-			Valid:  true,
-		})
-		if err != nil {
-			return nil, err
-		}
-		libraryMap[tmdbIDStr] = inLibrary
-	}
-
-	// For mixed media types (trending), we need to check requests separately for movies and TV
-	var requestStatus []repository.CheckMultipleUserRequestsRow
-	if mediaType == "mixed" {
-		// Separate movies and TV shows
-		var movieIDs, tvIDs []int64
-		for _, item := range response.Results {
-			itemMediaType := item.MediaType
-			if itemMediaType == "" {
-				// Fallback: determine media type from available fields
-				if item.ReleaseDate != "" {
-					itemMediaType = "movie"
-				} else if item.FirstAirDate != "" {
-					itemMediaType = "tv"
-				}
-			}
-
-			switch itemMediaType {
-			case "movie":
-				movieIDs = append(movieIDs, item.ID)
-			case "tv":
-				tvIDs = append(tvIDs, item.ID)
-			}
-		}
-
-		// Check movie requests individually
-		for _, movieID := range movieIDs {
-			requested, err := rg.gctx.Crate().Sqlite.Query().CheckUserRequestExists(ctx, repository.CheckUserRequestExistsParams{
-				TmdbID: sql.NullInt64{
-					Int64: movieID,
-					Valid: true,
-				},
-				MediaType: "movie",
-				UserID:    userID,
-			})
-			if err != nil {
-				return nil, err
-			}
-			if requested {
-				requestStatus = append(requestStatus, repository.CheckMultipleUserRequestsRow{
-					TmdbID: sql.NullInt64{
-						Int64: movieID,
-						Valid: true,
-					},
-					Requested: true,
-				})
-			}
-		}
-
-		// Check TV requests individually
-		for _, tvID := range tvIDs {
-			requested, err := rg.gctx.Crate().Sqlite.Query().CheckUserRequestExists(ctx, repository.CheckUserRequestExistsParams{
-				TmdbID: sql.NullInt64{
-					Int64: tvID,
-					Valid: true,
-				},
-				MediaType: "tv",
-				UserID:    userID,
-			})
-			if err != nil {
-				return nil, err
-			}
-			if requested {
-				requestStatus = append(requestStatus, repository.CheckMultipleUserRequestsRow{
-					TmdbID: sql.NullInt64{
-						Int64: tvID,
-						Valid: true,
-					},
-					Requested: true,
-				})
-			}
-		}
-	} else {
-		// Single media type - check requests individually
-		for _, tmdbID := range tmdbIDs {
-			requested, err := rg.gctx.Crate().Sqlite.Query().CheckUserRequestExists(ctx, repository.CheckUserRequestExistsParams{
-				TmdbID: sql.NullInt64{
-					Int64: tmdbID,
-					Valid: true,
-				},
-				MediaType: mediaType,
-				UserID:    userID,
-			})
-			if err != nil {
-				return nil, err
-			}
-			if requested {
-				requestStatus = append(requestStatus, repository.CheckMultipleUserRequestsRow{
-					TmdbID: sql.NullInt64{
-						Int64: tmdbID,
-						Valid: true,
-					},
-					Requested: true,
-				})
-			}
-		}
-	}
-
-	// Create request map for quick lookup (libraryMap already created above)
-
-	requestMap := make(map[int64]bool)
-	for _, status := range requestStatus {
-		if status.TmdbID.Valid {
-			requestMap[status.TmdbID.Int64] = status.Requested
-		}
+	}
+
+	status, err := rg.loadMediaStatus(ctx, userID, tmdbIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	watchlisted, err := rg.watchlistedTitles(ctx, userID)
+	if err != nil {
+		return nil, err
 	}
 
 	// Build enriched response
 	enrichedResults := make([]structures.TMDBFullMediaItem, 0, len(response.Results))
 	for _, item := range response.Results {
-		tmdbIDStr := strconv.FormatInt(item.ID, 10)
-
-		enrichedItem := structures.TMDBFullMediaItem{
-			TMDBMediaItem: item,
-			InLibrary:     libraryMap[tmdbIDStr],
-			Requested:     requestMap[item.ID],
-		}
-		enrichedResults = append(enrichedResults, enrichedItem)
+		itemMediaType := resolveMediaType(item, mediaType)
+		enrichedResults = append(enrichedResults, status.enrich(item, itemMediaType, watchlisted))
 	}
 	return &structures.TMDBFullMediaResponse{
 		TMDBPageResults: response.TMDBPageResults,
@@ -170,33 +51,115 @@ func (rg *RouteGroup) enrichSingleMediaWithStatus(ctx context.Context, item *str
 		return nil, nil
 	}
 
-	tmdbIDStr := strconv.FormatInt(item.ID, 10)
+	status, err := rg.loadMediaStatus(ctx, userID, []int64{item.ID})
+	if err != nil {
+		return nil, err
+	}
 
-	// Check library status
-	inLibrary, err := rg.gctx.Crate().Sqlite.Query().CheckMediaInLibrary(ctx, sql.NullString{
-		String: tmdbIDStr,
-		Valid:  true,
-	})
+	watchlisted, err := rg.watchlistedTitles(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Check request status for user
-	requested, err := rg.gctx.Crate().Sqlite.Query().CheckUserRequestExists(ctx, repository.CheckUserRequestExistsParams{
-		TmdbID: sql.NullInt64{
-			Int64: item.ID,
-			Valid: true,
-		},
-		MediaType: mediaType,
-		UserID:    userID,
+	enriched := status.enrich(*item, mediaType, watchlisted)
+	return &enriched, nil
+}
+
+// mediaStatus holds the library and request state of a set of titles
+type mediaStatus struct {
+	library   map[string]repository.GetLibraryAvailabilityRow // Keyed by TMDB ID
+	requested map[mediaRequestKey]bool
+}
+
+type mediaRequestKey struct {
+	mediaType string
+	tmdbID    int64
+	is4K      bool
+}
+
+// loadMediaStatus looks up the library availability of the titles and the
+// user's requests for them, regular and 4K, in one query each
+func (rg *RouteGroup) loadMediaStatus(ctx context.Context, userID string, tmdbIDs []int64) (*mediaStatus, error) {
+	query := rg.gctx.Crate().Sqlite.Query()
+
+	libraryIDs := make([]sql.NullString, 0, len(tmdbIDs))
+	for _, id := range tmdbIDs {
+		libraryIDs = append(libraryIDs, sql.NullString{String: strconv.FormatInt(id, 10), Valid: true})
+	}
+	available, err := query.GetLibraryAvailability(ctx, libraryIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	requests, err := query.GetUserRequestedTitles(ctx, repository.GetUserRequestedTitlesParams{
+		UserID:  userID,
+		TmdbIds: tmdbIDs,
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	return &structures.TMDBFullMediaItem{
-		TMDBMediaItem: *item,
-		InLibrary:     inLibrary,
-		Requested:     requested,
-	}, nil
+	status := &mediaStatus{
+		library:   make(map[string]repository.GetLibraryAvailabilityRow, len(available)),
+		requested: make(map[mediaRequestKey]bool, len(requests)),
+	}
+	for _, row := range available {
+		status.library[row.TmdbID.String] = row
+	}
+	for _, row := range requests {
+		status.requested[mediaRequestKey{mediaType: row.MediaType, tmdbID: row.TmdbID.Int64, is4K: row.Is4k}] = true
+	}
+	return status, nil
+}
+
+// enrich adds the status of a title to it. The regular and 4K versions are
+// reported independently, so a 4K copy doesn't make the regular one available.
+func (s *mediaStatus) enrich(item structures.TMDBMediaItem, mediaType string, watchlisted map[string]bool) structures.TMDBFullMediaItem {
+	library := s.library[strconv.FormatInt(item.ID, 10)]
+	return structures.TMDBFullMediaItem{
+		TMDBMediaItem: item,
+		InLibrary:     library.InLibrary,
+		Requested:     s.requested[mediaRequestKey{mediaType: mediaType, tmdbID: item.ID}],
+		InLibrary4K:   library.InLibrary4k,
+		Requested4K:   s.requested[mediaRequestKey{mediaType: mediaType, tmdbID: item.ID, is4K: true}],
+		Watchlisted:   watchlisted[watchlistKey(mediaType, item.ID)],
+	}
+}
+
+// watchlistedTitles returns the titles on the user's watchlist, keyed by watchlistKey
+func (rg *RouteGroup) watchlistedTitles(ctx context.Context, userID string) (map[string]bool, error) {
+	items, err := rg.gctx.Crate().Sqlite.Query().GetUserWatchlist(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	watchlisted := make(map[string]bool, len(items))
+	for _, item := range items {
+		watchlisted[watchlistKey(item.MediaType, item.TmdbID)] = true
+	}
+	return watchlisted, nil
+}
+
+func watchlistKey(mediaType string, tmdbID int64) string {
+	return mediaType + ":" + strconv.FormatInt(tmdbID, 10)
+}
+
+// resolveMediaType returns the media type of an item, falling back to the
+// item's own type or its date fields for mixed results such as trending
+func resolveMediaType(item structures.TMDBMediaItem, mediaType string) string {
+	if mediaType != "mixed" {
+		return mediaType
+	}
+
+	if item.MediaType != "" {
+		return item.MediaType
+	}
+
+	// Fallback: determine media type from available fields
+	if item.ReleaseDate != "" {
+		return "movie"
+	} else if item.FirstAirDate != "" {
+		return "tv"
+	}
+	return ""
 }
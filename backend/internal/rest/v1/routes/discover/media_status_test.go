@@ -0,0 +1,107 @@
+package discover
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/mahcks/serra/config"
+	"github.com/mahcks/serra/internal/db/dbtest"
+	"github.com/mahcks/serra/internal/global"
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+func TestMediaStatusKeeps4KApart(t *testing.T) {
+	gctx := global.New(context.Background(), &config.Bootstrap{}, "test", "")
+	gctx.Crate().Sqlite = dbtest.Service(t)
+	ctx := context.Background()
+
+	statements := []string{
+		`INSERT INTO users (id, username) VALUES ('alice', 'alice')`,
+		`INSERT INTO library_items (id, name, type, tmdb_id, is_4k, updated_at) VALUES
+			('a', 'Regular only', 'Movie', '100', FALSE, CURRENT_TIMESTAMP),
+			('b', '4K only', 'Movie', '200', TRUE, CURRENT_TIMESTAMP),
+			('c', 'Both', 'Movie', '300', NULL, CURRENT_TIMESTAMP),
+			('d', 'Both', 'Movie', '300', TRUE, CURRENT_TIMESTAMP)`,
+		`INSERT INTO requests (user_id, media_type, tmdb_id, title, status, is_4k) VALUES
+			('alice', 'movie', 200, '4K only', 'pending', FALSE),
+			('alice', 'movie', 400, 'Missing', 'pending', TRUE),
+			('alice', 'tv', 100, 'Same id, other type', 'pending', FALSE)`,
+	}
+	for _, statement := range statements {
+		if _, err := gctx.Crate().Sqlite.DB().Exec(statement); err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+
+	rg := &RouteGroup{gctx: gctx}
+	status, err := rg.loadMediaStatus(ctx, "alice", []int64{100, 200, 300, 400})
+	if err != nil {
+		t.Fatalf("load media status: %v", err)
+	}
+
+	tests := []struct {
+		tmdbID                                         int64
+		inLibrary, inLibrary4K, requested, requested4K bool
+	}{
+		{tmdbID: 100, inLibrary: true},
+		{tmdbID: 200, inLibrary4K: true, requested: true},
+		{tmdbID: 300, inLibrary: true, inLibrary4K: true},
+		{tmdbID: 400, requested4K: true},
+	}
+	for _, tt := range tests {
+		got := status.enrich(structures.TMDBMediaItem{ID: tt.tmdbID}, "movie", nil)
+		if got.InLibrary != tt.inLibrary || got.InLibrary4K != tt.inLibrary4K ||
+			got.Requested != tt.requested || got.Requested4K != tt.requested4K {
+			t.Errorf("%d: in_library=%v in_library_4k=%v requested=%v requested_4k=%v, want %v %v %v %v",
+				tt.tmdbID, got.InLibrary, got.InLibrary4K, got.Requested, got.Requested4K,
+				tt.inLibrary, tt.inLibrary4K, tt.requested, tt.requested4K)
+		}
+	}
+
+	// The single-title check agrees with the batch lookup
+	inLibrary, err := gctx.Crate().Sqlite.Query().CheckMediaInLibrary(ctx, sql.NullString{String: "200", Valid: true})
+	if err != nil {
+		t.Fatalf("check media in library: %v", err)
+	}
+	if inLibrary {
+		t.Error("a 4K copy counted towards the regular library status")
+	}
+}
+
+func TestEnrichWithMediaStatusMarksWatchlisted(t *testing.T) {
+	gctx := global.New(context.Background(), &config.Bootstrap{}, "test", "")
+	gctx.Crate().Sqlite = dbtest.Service(t)
+
+	statements := []string{
+		`INSERT INTO users (id, username) VALUES ('alice', 'alice'), ('bob', 'bob')`,
+		`INSERT INTO watchlist_items (user_id, media_type, tmdb_id, title) VALUES
+			('alice', 'movie', 100, 'Movie'),
+			('alice', 'tv', 200, 'Show'),
+			('bob', 'movie', 300, 'Bob''s movie')`,
+	}
+	for _, statement := range statements {
+		if _, err := gctx.Crate().Sqlite.DB().Exec(statement); err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+
+	rg := &RouteGroup{gctx: gctx}
+	response := &structures.TMDBMediaResponse{Results: []structures.TMDBMediaItem{
+		{ID: 100, MediaType: "movie"},
+		{ID: 200, MediaType: "movie"}, // Watchlisted as a show, not this movie
+		{ID: 200, MediaType: "tv"},
+		{ID: 300, MediaType: "movie"},
+	}}
+	enriched, err := rg.enrichWithMediaStatus(context.Background(), response, "alice", "mixed")
+	if err != nil {
+		t.Fatalf("enrich: %v", err)
+	}
+
+	want := []bool{true, false, true, false}
+	for i, item := range enriched.Results {
+		if item.Watchlisted != want[i] {
+			t.Errorf("%s %d: watchlisted = %v, want %v", response.Results[i].MediaType, item.ID, item.Watchlisted, want[i])
+		}
+	}
+}
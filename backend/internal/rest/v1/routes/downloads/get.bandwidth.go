@@ -0,0 +1,18 @@
+package downloads
+
+import (
+	"github.com/mahcks/serra/internal/jobs"
+	"github.com/mahcks/serra/internal/rest/v1/respond"
+	apiErrors "github.com/mahcks/serra/pkg/api_errors"
+)
+
+// GetBandwidth returns the bandwidth limits currently set on the download
+// clients and what decided them
+func (rg *RouteGroup) GetBandwidth(ctx *respond.Ctx) error {
+	user := ctx.ParseClaims()
+	if user == nil || user.ID == "" {
+		return apiErrors.ErrUnauthorized()
+	}
+
+	return ctx.JSON(jobs.CurrentBandwidthState(ctx.Context(), rg.gctx.Crate().Sqlite.Query()))
+}
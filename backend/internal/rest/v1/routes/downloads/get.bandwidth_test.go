@@ -0,0 +1,58 @@
+package downloads
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	jwt "github.com/golang-jwt/jwt/v5"
+	"github.com/mahcks/serra/config"
+	"github.com/mahcks/serra/internal/db/dbtest"
+	"github.com/mahcks/serra/internal/global"
+	"github.com/mahcks/serra/internal/rest/v1/respond"
+	"github.com/mahcks/serra/internal/services/auth"
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+func TestGetBandwidthServesTheLeadersState(t *testing.T) {
+	gctx := global.New(context.Background(), &config.Bootstrap{}, "test", "")
+	gctx.Crate().Sqlite = dbtest.Service(t)
+	rg := NewRouteGroup(gctx)
+
+	app := fiber.New()
+	app.Get("/downloads/bandwidth", func(c *fiber.Ctx) error {
+		// Stands in for the JWT middleware
+		c.Locals("_serrauser", &jwt.Token{Claims: &auth.JWTClaimUser{UserID: "alice"}})
+		return rg.GetBandwidth(&respond.Ctx{Ctx: c})
+	})
+	get := func() structures.BandwidthState {
+		t.Helper()
+		resp, err := app.Test(httptest.NewRequest("GET", "/downloads/bandwidth", nil))
+		if err != nil || resp.StatusCode != fiber.StatusOK {
+			t.Fatalf("get bandwidth: %v (%v)", resp.Status, err)
+		}
+		defer resp.Body.Close()
+
+		var state structures.BandwidthState
+		if err := json.NewDecoder(resp.Body).Decode(&state); err != nil {
+			t.Fatalf("decode bandwidth: %v", err)
+		}
+		return state
+	}
+
+	if state := get(); state.Enabled || state.Source != structures.BandwidthSourceDisabled {
+		t.Fatalf("state before the bandwidth manager ran = %+v, want disabled", state)
+	}
+
+	// Stored by the bandwidth manager of the instance leading the jobs
+	_, err := gctx.Crate().Sqlite.DB().Exec(`INSERT INTO shared_state (key, value, updated_at) VALUES ('bandwidth', ?, CURRENT_TIMESTAMP)`,
+		`{"enabled": true, "source": "streams", "limits": {"download_kbps": 500}, "remote_streams": 1, "clients": []}`)
+	if err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+	if state := get(); !state.Enabled || state.Source != structures.BandwidthSourceStreams || state.Limits.DownloadKBps != 500 || state.RemoteStreams != 1 {
+		t.Fatalf("state = %+v, want the leader's", state)
+	}
+}
@@ -0,0 +1,90 @@
+package downloads
+
+import (
+	"database/sql"
+	"log/slog"
+	"strconv"
+
+	"github.com/mahcks/serra/internal/db/repository"
+	"github.com/mahcks/serra/internal/jobs"
+	"github.com/mahcks/serra/internal/rest/v1/respond"
+	apiErrors "github.com/mahcks/serra/pkg/api_errors"
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+// GetDownloadHistory returns the paginated history of downloads that left the
+// queue, newest first. When download visibility is limited users only see the
+// downloads of their own requests.
+func (rg *RouteGroup) GetDownloadHistory(ctx *respond.Ctx) error {
+	user := ctx.ParseClaims()
+	if user == nil || user.ID == "" {
+		return apiErrors.ErrUnauthorized()
+	}
+
+	// Parse query parameters
+	limit, err := strconv.Atoi(ctx.Query("limit", "50"))
+	if err != nil || limit < 1 || limit > 200 {
+		limit = 50
+	}
+
+	offset, err := strconv.Atoi(ctx.Query("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	var requestID sql.NullInt64
+	if value := ctx.Query("request_id"); value != "" {
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return apiErrors.ErrBadRequest().SetDetail("Invalid request_id")
+		}
+		requestID = sql.NullInt64{Int64: id, Valid: true}
+	}
+
+	query := rg.gctx.Crate().Sqlite.Query()
+
+	var userID sql.NullString
+	if jobs.DownloadVisibility(ctx.Context(), query) == structures.DownloadVisibilityOwn {
+		seesAll, err := jobs.SeesAllDownloads(ctx.Context(), query, user.ID, user.IsAdmin)
+		if err != nil {
+			slog.Error("GetDownloadHistory: Failed to determine download visibility", "error", err, "user_id", user.ID)
+			return apiErrors.ErrInternalServerError().SetDetail("Failed to determine download visibility")
+		}
+		if !seesAll {
+			userID = sql.NullString{String: user.ID, Valid: true}
+		}
+	}
+
+	rows, err := query.ListDownloadHistory(ctx.Context(), repository.ListDownloadHistoryParams{
+		UserID:    userID,
+		RequestID: requestID,
+		Limit:     int64(limit),
+		Offset:    int64(offset),
+	})
+	if err != nil {
+		slog.Error("GetDownloadHistory: Failed to retrieve download history", "error", err)
+		return apiErrors.ErrInternalServerError().SetDetail("Failed to retrieve download history")
+	}
+
+	total, err := query.CountDownloadHistory(ctx.Context(), repository.CountDownloadHistoryParams{
+		UserID:    userID,
+		RequestID: requestID,
+	})
+	if err != nil {
+		slog.Error("GetDownloadHistory: Failed to count download history", "error", err)
+		return apiErrors.ErrInternalServerError().SetDetail("Failed to count download history")
+	}
+
+	entries := make([]structures.DownloadHistoryEntry, 0, len(rows))
+	for _, row := range rows {
+		entries = append(entries, jobs.DownloadHistoryEntry(row))
+	}
+
+	return ctx.JSON(structures.DownloadHistoryResponse{
+		Entries: entries,
+		Total:   total,
+		Page:    offset/limit + 1,
+		Limit:   limit,
+		HasMore: int64(offset+len(entries)) < total,
+	})
+}
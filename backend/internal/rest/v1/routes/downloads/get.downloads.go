@@ -1,9 +1,12 @@
 package downloads
 
 import (
+	"context"
+	"encoding/json"
 	"log/slog"
 	"time"
 
+	"github.com/mahcks/serra/internal/jobs"
 	"github.com/mahcks/serra/internal/rest/v1/respond"
 	apiErrors "github.com/mahcks/serra/pkg/api_errors"
 	"github.com/mahcks/serra/pkg/structures"
@@ -11,6 +14,11 @@ import (
 )
 
 func (rg *RouteGroup) GetDownloads(ctx *respond.Ctx) error {
+	user := ctx.ParseClaims()
+	if user == nil {
+		return apiErrors.ErrUnauthorized()
+	}
+
 	// Get the downloads from the database
 	downloads, err := rg.gctx.Crate().Sqlite.Query().ListDownloads(ctx.Context())
 	if err != nil {
@@ -18,6 +26,13 @@ func (rg *RouteGroup) GetDownloads(ctx *respond.Ctx) error {
 		return apiErrors.ErrInternalServerError().SetDetail(err.Error())
 	}
 
+	// When visibility is limited, only return downloads linked to the user's requests
+	visible, err := rg.visibleDownloads(ctx.Context(), user.ID, user.IsAdmin)
+	if err != nil {
+		slog.Error("GetDownloads: Failed to determine download visibility", "error", err, "user_id", user.ID)
+		return apiErrors.ErrInternalServerError().SetDetail("Failed to determine download visibility")
+	}
+
 	// Always initialize result as empty array, even if no downloads
 	var result []structures.Download
 
@@ -27,6 +42,10 @@ func (rg *RouteGroup) GetDownloads(ctx *respond.Ctx) error {
 	}
 
 	for _, d := range downloads {
+		if visible != nil && !visible[d.ID] {
+			continue
+		}
+
 		download := structures.Download{
 			ID:           d.ID,
 			Title:        d.Title,
@@ -39,6 +58,16 @@ func (rg *RouteGroup) GetDownloads(ctx *respond.Ctx) error {
 			TimeLeft:     utils.NullableString{NullString: d.TimeLeft}.ToPointer(),
 			Status:       utils.NullableString{NullString: d.Status}.ToPointer(),
 			UpdatedAt:    nil,
+			RequestID:    utils.NullableInt64{NullInt64: d.RequestID}.ToPointer(),
+		}
+
+		if d.MatchConfidence.Valid {
+			download.MatchConfidence = d.MatchConfidence.String
+		}
+		if d.Episodes.Valid && d.Episodes.String != "" {
+			if err := json.Unmarshal([]byte(d.Episodes.String), &download.Episodes); err != nil {
+				slog.Warn("GetDownloads: Failed to decode download episodes", "id", d.ID, "error", err)
+			}
 		}
 
 		// Handle LastUpdated using wrapper
@@ -56,3 +85,33 @@ func (rg *RouteGroup) GetDownloads(ctx *respond.Ctx) error {
 	// Return the downloads as a JSON response
 	return ctx.JSON(result)
 }
+
+// visibleDownloads returns the ids of the downloads a user may see, or nil when
+// they may see all of them
+func (rg *RouteGroup) visibleDownloads(ctx context.Context, userID string, isAdmin bool) (map[string]bool, error) {
+	query := rg.gctx.Crate().Sqlite.Query()
+	if jobs.DownloadVisibility(ctx, query) == structures.DownloadVisibilityAll {
+		return nil, nil
+	}
+
+	seesAll, err := jobs.SeesAllDownloads(ctx, query, userID, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+	if seesAll {
+		return nil, nil
+	}
+
+	owners, err := jobs.DownloadOwners(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	visible := make(map[string]bool)
+	for downloadID, users := range owners {
+		if users[userID] {
+			visible[downloadID] = true
+		}
+	}
+	return visible, nil
+}
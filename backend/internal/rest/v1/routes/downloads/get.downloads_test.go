@@ -0,0 +1,108 @@
+package downloads
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	jwt "github.com/golang-jwt/jwt/v5"
+	"github.com/mahcks/serra/config"
+	"github.com/mahcks/serra/internal/db/dbtest"
+	"github.com/mahcks/serra/internal/global"
+	"github.com/mahcks/serra/internal/rest/v1/respond"
+	"github.com/mahcks/serra/internal/services/auth"
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+func TestGetDownloadsFollowsVisibility(t *testing.T) {
+	service := dbtest.Service(t)
+	statements := []string{
+		`INSERT INTO users (id, username) VALUES ('alice', 'alice'), ('bob', 'bob'), ('viewer', 'viewer'), ('admin', 'admin')`,
+		`INSERT INTO user_permissions (user_id, permission_id) VALUES ('viewer', 'requests.view')`,
+		`INSERT INTO requests (user_id, media_type, tmdb_id, title, status, seasons, on_behalf_of) VALUES
+			('alice', 'movie', 100, 'Movie 100', 'approved', NULL, NULL),
+			('bob', 'tv', 200, 'Show 200', 'approved', '[1]', 'alice')`,
+		`INSERT INTO downloads (id, title, torrent_title, source, tmdb_id, season_number) VALUES
+			('radarr-100', 'Movie 100', 'movie.100', 'radarr', 100, NULL),
+			('sonarr-200-1', 'Show 200', 'show.200.s01', 'sonarr', 200, 1),
+			('sonarr-200-2', 'Show 200', 'show.200.s02', 'sonarr', 200, 2),
+			('radarr-300', 'Movie 300', 'movie.300', 'radarr', 300, NULL)`,
+	}
+	for _, statement := range statements {
+		if _, err := service.DB().Exec(statement); err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+
+	gctx := global.New(context.Background(), &config.Bootstrap{}, "test", "")
+	gctx.Crate().Sqlite = service
+	rg := NewRouteGroup(gctx)
+
+	app := fiber.New()
+	app.Get("/downloads", func(c *fiber.Ctx) error {
+		// Stands in for the JWT middleware
+		c.Locals("_serrauser", &jwt.Token{Claims: &auth.JWTClaimUser{
+			UserID:  c.Query("user"),
+			IsAdmin: c.Query("admin") == "true",
+		}})
+		return rg.GetDownloads(&respond.Ctx{Ctx: c})
+	})
+
+	get := func(t *testing.T, query string) []string {
+		t.Helper()
+		resp, err := app.Test(httptest.NewRequest("GET", "/downloads?"+query, nil))
+		if err != nil || resp.StatusCode != fiber.StatusOK {
+			t.Fatalf("get downloads: %v (%v)", resp.Status, err)
+		}
+		defer resp.Body.Close()
+
+		var downloads []structures.Download
+		if err := json.NewDecoder(resp.Body).Decode(&downloads); err != nil {
+			t.Fatalf("decode downloads: %v", err)
+		}
+		ids := make([]string, 0, len(downloads))
+		for _, download := range downloads {
+			ids = append(ids, download.ID)
+		}
+		slices.Sort(ids)
+		return ids
+	}
+
+	all := []string{"radarr-100", "radarr-300", "sonarr-200-1", "sonarr-200-2"}
+	setVisibility := func(visibility structures.DownloadVisibility) {
+		_, err := service.DB().Exec(`INSERT INTO settings (key, value) VALUES (?1, ?2) ON CONFLICT (key) DO UPDATE SET value = excluded.value`,
+			structures.SettingDownloadVisibility.String(), string(visibility))
+		if err != nil {
+			t.Fatalf("set download visibility: %v", err)
+		}
+	}
+
+	t.Run("all", func(t *testing.T) {
+		setVisibility(structures.DownloadVisibilityAll)
+		if got := get(t, "user=bob"); !slices.Equal(got, all) {
+			t.Fatalf("expected every download, got %v", got)
+		}
+	})
+
+	t.Run("own", func(t *testing.T) {
+		setVisibility(structures.DownloadVisibilityOwn)
+		for _, test := range []struct {
+			query string
+			want  []string
+		}{
+			// Including the season requested on her behalf
+			{"user=alice", []string{"radarr-100", "sonarr-200-1"}},
+			{"user=bob", []string{"sonarr-200-1"}},
+			{"user=viewer", all},
+			{"user=admin&admin=true", all},
+			{"user=nobody", []string{}},
+		} {
+			if got := get(t, test.query); !slices.Equal(got, test.want) {
+				t.Errorf("%s: expected %v, got %v", test.query, test.want, got)
+			}
+		}
+	})
+}
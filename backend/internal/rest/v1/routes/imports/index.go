@@ -0,0 +1,26 @@
+package imports
+
+import (
+	"github.com/mahcks/serra/internal/global"
+	"github.com/mahcks/serra/internal/integrations"
+	"github.com/mahcks/serra/internal/integrations/radarr"
+	"github.com/mahcks/serra/internal/integrations/sonarr"
+	"github.com/mahcks/serra/internal/services/request_processor"
+)
+
+type RouteGroup struct {
+	gctx             global.Context
+	integrations     *integrations.Integration
+	requestProcessor request_processor.Service
+}
+
+func NewRouteGroup(gctx global.Context, integrations *integrations.Integration) *RouteGroup {
+	radarrSvc := radarr.New(gctx.Crate().Sqlite.Query())
+	sonarrSvc := sonarr.New(gctx.Crate().Sqlite.Query())
+
+	return &RouteGroup{
+		gctx:             gctx,
+		integrations:     integrations,
+		requestProcessor: request_processor.New(gctx.Crate().Sqlite.Query(), radarrSvc, sonarrSvc, integrations),
+	}
+}
@@ -0,0 +1,221 @@
+package imports
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/mahcks/serra/internal/db/repository"
+	"github.com/mahcks/serra/internal/rest/v1/respond"
+	apiErrors "github.com/mahcks/serra/pkg/api_errors"
+	"github.com/mahcks/serra/pkg/permissions"
+	"github.com/mahcks/serra/pkg/structures"
+	"github.com/mahcks/serra/utils"
+)
+
+const tmdbPosterBaseURL = "https://image.tmdb.org/t/p/w500"
+
+// CommitImport adds the titles picked from an import preview to the user's
+// watchlist, or requests them. Each title is handled on its own and reported
+// in the result.
+func (rg *RouteGroup) CommitImport(ctx *respond.Ctx) error {
+	user := ctx.ParseClaims()
+	if user == nil || user.ID == "" {
+		return apiErrors.ErrUnauthorized()
+	}
+
+	var req structures.CommitImportRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return apiErrors.ErrBadRequest().SetDetail("Invalid request body")
+	}
+	if req.Target != structures.ImportTargetWatchlist && req.Target != structures.ImportTargetRequest {
+		return apiErrors.ErrBadRequest().SetDetail("target must be watchlist or request")
+	}
+	if len(req.Items) == 0 {
+		return apiErrors.ErrBadRequest().SetDetail("No titles were selected")
+	}
+
+	result := structures.ImportCommitResult{
+		Target: req.Target,
+		Items:  make([]structures.ImportCommitItem, 0, len(req.Items)),
+	}
+
+	var approved []int64
+	for _, candidate := range req.Items {
+		item := structures.ImportCommitItem{
+			MediaType: candidate.MediaType,
+			TmdbID:    candidate.TmdbID,
+			Title:     candidate.Title,
+		}
+
+		switch {
+		case candidate.MediaType != "movie" && candidate.MediaType != "tv":
+			item.Result = "failed"
+			item.Reason = fmt.Sprintf("Media type '%s' is not supported", candidate.MediaType)
+		case candidate.TmdbID < 1 || candidate.Title == "":
+			item.Result = "failed"
+			item.Reason = "tmdb_id and title are required"
+		case req.Target == structures.ImportTargetWatchlist:
+			rg.importToWatchlist(ctx.Context(), user.ID, candidate, req.AutoRequest, &item)
+		default:
+			rg.importAsRequest(ctx.Context(), user.ID, user.IsAdmin, candidate, &item)
+			if item.Status == "approved" {
+				approved = append(approved, *item.RequestID)
+			}
+		}
+
+		switch item.Result {
+		case "created":
+			result.Created++
+		case "skipped":
+			result.Skipped++
+		default:
+			result.Failed++
+		}
+		result.Items = append(result.Items, item)
+	}
+
+	if len(approved) > 0 {
+		go rg.processApprovedRequests(approved)
+	}
+
+	slog.Info("Import committed",
+		"user_id", user.ID,
+		"target", req.Target,
+		"created", result.Created,
+		"skipped", result.Skipped,
+		"failed", result.Failed)
+
+	return ctx.JSON(result)
+}
+
+func (rg *RouteGroup) importToWatchlist(ctx context.Context, userID string, candidate structures.ImportCandidate, autoRequest bool, item *structures.ImportCommitItem) {
+	params := repository.AddWatchlistItemParams{
+		UserID:      userID,
+		MediaType:   candidate.MediaType,
+		TmdbID:      candidate.TmdbID,
+		Title:       candidate.Title,
+		AutoRequest: autoRequest && candidate.MediaType == "movie",
+	}
+	if candidate.PosterPath != "" {
+		params.PosterUrl = sql.NullString{String: tmdbPosterBaseURL + candidate.PosterPath, Valid: true}
+	}
+
+	if _, err := rg.gctx.Crate().Sqlite.Query().AddWatchlistItem(ctx, params); err != nil {
+		slog.Error("Failed to add imported title to watchlist", "error", err, "user_id", userID, "tmdb_id", candidate.TmdbID)
+		item.Result = "failed"
+		item.Reason = "Failed to add to watchlist"
+		return
+	}
+	item.Result = "created"
+}
+
+func (rg *RouteGroup) importAsRequest(ctx context.Context, userID string, isAdmin bool, candidate structures.ImportCandidate, item *structures.ImportCommitItem) {
+	query := rg.gctx.Crate().Sqlite.Query()
+
+	requestPermission := utils.Ternary(candidate.MediaType == "movie", permissions.RequestMovies, permissions.RequestSeries)
+	canRequest, err := rg.hasPermission(ctx, userID, isAdmin, requestPermission)
+	if err != nil {
+		slog.Error("Failed to check permission", "error", err)
+		item.Result = "failed"
+		item.Reason = "Permission check failed"
+		return
+	}
+	if !canRequest {
+		item.Result = "skipped"
+		item.Reason = fmt.Sprintf("You need permission to request %s", utils.Ternary(candidate.MediaType == "movie", "movies", "TV shows"))
+		return
+	}
+
+	inLibrary, err := query.CheckMediaInLibrary(ctx, sql.NullString{String: strconv.FormatInt(candidate.TmdbID, 10), Valid: true})
+	if err != nil {
+		slog.Error("Failed to check library", "error", err, "tmdb_id", candidate.TmdbID)
+		item.Result = "failed"
+		item.Reason = "Failed to check the library"
+		return
+	}
+	if inLibrary {
+		item.Result = "skipped"
+		item.Reason = "Already in the library"
+		return
+	}
+
+	// TV shows are requested as a whole series
+	if err := rg.requestProcessor.CheckDuplicateRequest(ctx, userID, candidate.MediaType, candidate.TmdbID, nil, nil, false); err != nil {
+		item.Result = "skipped"
+		item.Reason = "Already requested"
+		return
+	}
+
+	autoApprovalPermission := utils.Ternary(candidate.MediaType == "movie", permissions.RequestAutoApproveMovies, permissions.RequestAutoApproveSeries)
+	autoApproved, err := rg.hasPermission(ctx, userID, isAdmin, autoApprovalPermission)
+	if err != nil {
+		slog.Error("Failed to check auto-approval permission", "error", err, "permission", autoApprovalPermission)
+		// Continue with normal flow if permission check fails
+	}
+
+	params := repository.CreateRequestParams{
+		UserID:    userID,
+		MediaType: candidate.MediaType,
+		TmdbID:    sql.NullInt64{Int64: candidate.TmdbID, Valid: true},
+		Title:     sql.NullString{String: candidate.Title, Valid: true},
+		Status:    utils.Ternary(autoApproved, "approved", "pending"),
+		Notes:     sql.NullString{String: "Imported from a list", Valid: true},
+		Priority:  rg.requestProcessor.RequestPriority(ctx, userID),
+	}
+	if candidate.PosterPath != "" {
+		params.PosterUrl = sql.NullString{String: tmdbPosterBaseURL + candidate.PosterPath, Valid: true}
+	}
+
+	request, err := query.CreateRequest(ctx, params)
+	if err != nil {
+		slog.Error("Failed to create imported request", "error", err, "user_id", userID, "tmdb_id", candidate.TmdbID)
+		item.Result = "failed"
+		item.Reason = "Failed to create request"
+		return
+	}
+
+	item.Result = "created"
+	item.RequestID = &request.ID
+	item.Status = request.Status
+}
+
+// processApprovedRequests sends auto-approved imported requests to
+// Radarr/Sonarr one at a time so a large import doesn't flood them
+func (rg *RouteGroup) processApprovedRequests(requestIDs []int64) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("Panic while processing imported requests", "panic", r)
+		}
+	}()
+
+	for _, requestID := range requestIDs {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		if err := rg.requestProcessor.ProcessApprovedRequest(ctx, requestID); err != nil {
+			slog.Error("Failed to process imported request", "request_id", requestID, "error", err)
+		}
+		cancel()
+	}
+}
+
+// hasPermission checks if a user has a specific permission. Admins and owners have every permission.
+func (rg *RouteGroup) hasPermission(ctx context.Context, userID string, isAdmin bool, permission string) (bool, error) {
+	if isAdmin {
+		return true, nil
+	}
+
+	userPermissions, err := rg.gctx.Crate().Sqlite.Query().GetUserPermissions(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, userPerm := range userPermissions {
+		if userPerm.PermissionID == permissions.Owner || userPerm.PermissionID == permission {
+			return true, nil
+		}
+	}
+	return false, nil
+}
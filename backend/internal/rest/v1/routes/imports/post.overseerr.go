@@ -0,0 +1,121 @@
+package imports
+
+import (
+	"log/slog"
+	"os"
+
+	"github.com/mahcks/serra/internal/rest/v1/respond"
+	"github.com/mahcks/serra/internal/services/overseerr_import"
+	apiErrors "github.com/mahcks/serra/pkg/api_errors"
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+// ImportOverseerr brings users, requests and default permissions over from
+// Overseerr or Jellyseerr. The data is read from an uploaded db.sqlite3, with
+// an optional settings.json for the default permissions, or from the API of a
+// running instance; the API URL defaults to the external request system URL.
+// With dry_run set the report shows what would be imported without changing
+// anything.
+func (rg *RouteGroup) ImportOverseerr(ctx *respond.Ctx) error {
+	user := ctx.ParseClaims()
+	if user == nil || user.ID == "" {
+		return apiErrors.ErrUnauthorized()
+	}
+
+	source := ctx.FormValue("source")
+	opts := overseerr_import.Options{
+		DryRun:             ctx.FormValue("dry_run") == "true",
+		CreateMissingUsers: ctx.FormValue("create_missing_users") == "true",
+	}
+
+	var (
+		export *overseerr_import.Export
+		err    error
+	)
+	switch source {
+	case structures.OverseerrSourceDatabase:
+		export, err = rg.readOverseerrDatabase(ctx)
+	case structures.OverseerrSourceAPI:
+		url := ctx.FormValue("url")
+		if url == "" {
+			url, _ = rg.gctx.Crate().Sqlite.Query().GetSetting(ctx.Context(), structures.SettingRequestSystemURL.String())
+		}
+		if url == "" {
+			return apiErrors.ErrBadRequest().SetDetail("A URL is required")
+		}
+		export, err = overseerr_import.FetchAPI(ctx.Context(), url, ctx.FormValue("api_key"))
+		if err != nil {
+			slog.Warn("Failed to fetch from Overseerr", "url", url, "error", err)
+			err = apiErrors.ErrBadRequest().SetDetail("Failed to fetch from Overseerr: %s", err.Error())
+		}
+	default:
+		return apiErrors.ErrBadRequest().SetDetail("source must be database or api")
+	}
+	if err != nil {
+		return err
+	}
+
+	// The importer keeps using the pool for its whole run, so a restore has to wait
+	db, release, err := rg.gctx.Crate().Sqlite.Hold()
+	if err != nil {
+		return apiErrors.ErrConflict().SetDetail("A database restore is in progress, try again in a moment")
+	}
+	defer release()
+
+	importer := overseerr_import.New(
+		db,
+		rg.gctx.Crate().Sqlite.Query(),
+		rg.integrations.TMDB,
+		rg.requestProcessor.RequestPriority,
+	)
+
+	report, err := importer.Import(ctx.Context(), source, export, opts)
+	if err != nil {
+		slog.Error("Overseerr import failed", "user_id", user.ID, "source", source, "dry_run", opts.DryRun, "error", err)
+		return apiErrors.ErrInternalServerError().SetDetail("Import failed: %s", err.Error())
+	}
+
+	return ctx.JSON(report)
+}
+
+// readOverseerrDatabase reads the uploaded database file, and the settings file
+// when one was uploaded too
+func (rg *RouteGroup) readOverseerrDatabase(ctx *respond.Ctx) (*overseerr_import.Export, error) {
+	fileHeader, err := ctx.FormFile("database")
+	if err != nil {
+		return nil, apiErrors.ErrBadRequest().SetDetail("A db.sqlite3 file is required")
+	}
+
+	// SQLite needs a file on disk
+	tmp, err := os.CreateTemp("", "overseerr-*.sqlite3")
+	if err != nil {
+		slog.Error("Failed to create temporary file for Overseerr database", "error", err)
+		return nil, apiErrors.ErrInternalServerError().SetDetail("Failed to store the database file")
+	}
+	tmp.Close()
+	defer os.Remove(tmp.Name())
+
+	if err := ctx.SaveFile(fileHeader, tmp.Name()); err != nil {
+		slog.Error("Failed to save uploaded Overseerr database", "error", err)
+		return nil, apiErrors.ErrInternalServerError().SetDetail("Failed to store the database file")
+	}
+
+	export, err := overseerr_import.ReadDatabase(ctx.Context(), tmp.Name())
+	if err != nil {
+		return nil, apiErrors.ErrBadRequest().SetDetail("Failed to read the database file: %s", err.Error())
+	}
+
+	if settingsHeader, err := ctx.FormFile("settings"); err == nil {
+		file, err := settingsHeader.Open()
+		if err != nil {
+			return nil, apiErrors.ErrBadRequest().SetDetail("Failed to read the settings file")
+		}
+		defer file.Close()
+
+		if export.DefaultPermissions, err = overseerr_import.ReadSettings(file); err != nil {
+			return nil, apiErrors.ErrBadRequest().SetDetail("%s", err.Error())
+		}
+	}
+
+	return export, nil
+}
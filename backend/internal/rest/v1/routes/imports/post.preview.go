@@ -0,0 +1,53 @@
+package imports
+
+import (
+	"log/slog"
+
+	"github.com/mahcks/serra/internal/rest/v1/respond"
+	"github.com/mahcks/serra/internal/services/list_import"
+	apiErrors "github.com/mahcks/serra/pkg/api_errors"
+)
+
+// PreviewImport parses an uploaded Letterboxd, IMDb or Trakt export and shows
+// how each row resolves to TMDB. Nothing is created until the picked titles
+// are committed.
+func (rg *RouteGroup) PreviewImport(ctx *respond.Ctx) error {
+	user := ctx.ParseClaims()
+	if user == nil || user.ID == "" {
+		return apiErrors.ErrUnauthorized()
+	}
+
+	if rg.integrations.TMDB == nil {
+		return apiErrors.ErrInternalServerError().SetDetail("TMDB is not configured")
+	}
+
+	format := ctx.FormValue("format")
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		return apiErrors.ErrBadRequest().SetDetail("An export file is required")
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		slog.Error("Failed to open uploaded import file", "error", err)
+		return apiErrors.ErrBadRequest().SetDetail("Failed to read the export file")
+	}
+	defer file.Close()
+
+	entries, err := list_import.Parse(format, file)
+	if err != nil {
+		return apiErrors.ErrBadRequest().SetDetail("%s", err.Error())
+	}
+
+	preview := list_import.Preview(rg.integrations.TMDB, format, entries)
+
+	slog.Info("Import previewed",
+		"user_id", user.ID,
+		"format", format,
+		"rows", preview.Total,
+		"matched", preview.Matched,
+		"ambiguous", preview.Ambiguous,
+		"unmatched", preview.Unmatched)
+
+	return ctx.JSON(preview)
+}
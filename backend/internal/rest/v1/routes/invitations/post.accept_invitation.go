@@ -94,7 +94,13 @@ func (rg *RouteGroup) AcceptInvitation(ctx *respond.Ctx) error {
 	}
 
 	// Use a transaction to prevent race conditions during user creation
-	tx, err := rg.gctx.Crate().Sqlite.DB().BeginTx(ctx.Context(), nil)
+	db, release, err := rg.gctx.Crate().Sqlite.Hold()
+	if err != nil {
+		return apiErrors.ErrConflict().SetDetail("A database restore is in progress, try again in a moment")
+	}
+	defer release()
+
+	tx, err := db.BeginTx(ctx.Context(), nil)
 	if err != nil {
 		utils.LogErrorWithStack("Failed to begin transaction", err)
 		return apiErrors.ErrInternalServerError()
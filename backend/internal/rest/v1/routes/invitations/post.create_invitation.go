@@ -15,6 +15,7 @@ import (
 
 	"github.com/mahcks/serra/internal/db/repository"
 	"github.com/mahcks/serra/internal/rest/v1/respond"
+	"github.com/mahcks/serra/internal/secrets"
 	"github.com/mahcks/serra/internal/services/email"
 	apiErrors "github.com/mahcks/serra/pkg/api_errors"
 	"github.com/mahcks/serra/pkg/structures"
@@ -241,7 +242,9 @@ func (rg *RouteGroup) getEmailSettingsFromDB(ctx context.Context) (*structures.E
 
 	getStringSetting := func(key string, defaultVal string) string {
 		if val, err := rg.gctx.Crate().Sqlite.Query().GetSetting(ctx, key); err == nil {
-			return val
+			if plaintext, err := secrets.Open(val); err == nil {
+				return plaintext
+			}
 		}
 		return defaultVal
 	}
@@ -0,0 +1,103 @@
+package jobs
+
+import (
+	"encoding/json"
+	"log/slog"
+	"strconv"
+
+	"github.com/mahcks/serra/internal/db/repository"
+	jobsPkg "github.com/mahcks/serra/internal/jobs"
+	"github.com/mahcks/serra/internal/rest/v1/respond"
+	apiErrors "github.com/mahcks/serra/pkg/api_errors"
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+// GetJobRuns returns the paginated execution history of a background job
+func (rg *RouteGroup) GetJobRuns(ctx *respond.Ctx) error {
+	user := ctx.ParseClaims()
+	if user == nil || user.ID == "" {
+		return apiErrors.ErrUnauthorized()
+	}
+
+	name := structures.Job(ctx.Params("name"))
+	if !isKnownJob(name) {
+		return apiErrors.ErrNotFound().SetDetail("Unknown job: %s", name)
+	}
+
+	// Parse query parameters
+	limit, err := strconv.Atoi(ctx.Query("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	offset, err := strconv.Atoi(ctx.Query("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	dbRuns, err := rg.gctx.Crate().Sqlite.Query().ListJobRuns(ctx.Context(), repository.ListJobRunsParams{
+		JobName: name.String(),
+		Limit:   int64(limit),
+		Offset:  int64(offset),
+	})
+	if err != nil {
+		slog.Error("Failed to get job runs", "error", err, "job", name)
+		return apiErrors.ErrInternalServerError().SetDetail("Failed to retrieve job runs")
+	}
+
+	total, err := rg.gctx.Crate().Sqlite.Query().CountJobRuns(ctx.Context(), name.String())
+	if err != nil {
+		slog.Error("Failed to count job runs", "error", err, "job", name)
+		return apiErrors.ErrInternalServerError().SetDetail("Failed to count job runs")
+	}
+
+	// Convert to API response format
+	runs := make([]structures.JobRun, 0, len(dbRuns))
+	for _, dbRun := range dbRuns {
+		run := structures.JobRun{
+			ID:         dbRun.ID,
+			JobName:    structures.Job(dbRun.JobName),
+			Trigger:    structures.JobRunTrigger(dbRun.TriggerType),
+			StartedAt:  dbRun.StartedAt,
+			DurationMs: dbRun.DurationMs,
+			Outcome:    structures.JobRunOutcome(dbRun.Outcome),
+			Attempts:   dbRun.Attempts,
+		}
+
+		// Handle optional fields
+		if dbRun.FinishedAt.Valid {
+			run.FinishedAt = &dbRun.FinishedAt.Time
+		}
+		if dbRun.Error.Valid {
+			run.Error = dbRun.Error.String
+		}
+		if dbRun.Summary.Valid {
+			var summary map[string]interface{}
+			if err := json.Unmarshal([]byte(dbRun.Summary.String), &summary); err == nil {
+				run.Summary = summary
+			}
+		}
+
+		runs = append(runs, run)
+	}
+
+	response := structures.JobRunsResponse{
+		Runs:    runs,
+		Total:   total,
+		Page:    offset/limit + 1,
+		Limit:   limit,
+		HasMore: int64(offset+len(runs)) < total,
+	}
+
+	return ctx.JSON(response)
+}
+
+// isKnownJob checks whether the name refers to a registered job type
+func isKnownJob(name structures.Job) bool {
+	for _, known := range jobsPkg.AllJobNames() {
+		if known == name {
+			return true
+		}
+	}
+	return false
+}
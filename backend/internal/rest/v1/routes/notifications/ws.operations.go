@@ -0,0 +1,195 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/mahcks/serra/internal/db/repository"
+	"github.com/mahcks/serra/internal/websocket"
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+// RegisterOperations registers the notification operations clients can call
+// over the WebSocket
+func (rg *RouteGroup) RegisterOperations() {
+	websocket.HandleOperation("notifications.list", rg.listOperation)
+	websocket.HandleOperation("notifications.count", rg.countOperation)
+	websocket.HandleOperation("notifications.mark_read", rg.markReadOperation)
+	websocket.HandleOperation("notifications.mark_all_read", rg.markAllReadOperation)
+}
+
+// listOperation returns the user's notifications, like GET /notifications
+func (rg *RouteGroup) listOperation(ctx context.Context, client websocket.ConnectedClient, params json.RawMessage) (interface{}, error) {
+	var req struct {
+		Limit      int  `json:"limit"`
+		Offset     int  `json:"offset"`
+		UnreadOnly bool `json:"unread_only"`
+	}
+	if err := decodeParams(params, &req); err != nil {
+		return nil, err
+	}
+	if req.Limit < 1 || req.Limit > 100 {
+		req.Limit = 20
+	}
+	if req.Offset < 0 {
+		req.Offset = 0
+	}
+
+	query := rg.gctx.Crate().Sqlite.Query()
+
+	var rows []repository.Notification
+	var err error
+	if req.UnreadOnly {
+		rows, err = query.GetUnreadUserNotifications(ctx, client.UserID)
+	} else {
+		rows, err = query.GetUserNotifications(ctx, repository.GetUserNotificationsParams{
+			UserID: client.UserID,
+			Limit:  int64(req.Limit),
+			Offset: int64(req.Offset),
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	unreadCount, err := query.CountUnreadNotifications(ctx, client.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	notifications := make([]structures.Notification, 0, len(rows))
+	for _, row := range rows {
+		notifications = append(notifications, toNotification(row))
+	}
+
+	return structures.NotificationsResponse{
+		Notifications: notifications,
+		Total:         int64(len(notifications)),
+		Unread:        unreadCount,
+		Page:          req.Offset/req.Limit + 1,
+		Limit:         req.Limit,
+		HasMore:       len(notifications) == req.Limit,
+	}, nil
+}
+
+// countOperation returns the user's unread notification count
+func (rg *RouteGroup) countOperation(ctx context.Context, client websocket.ConnectedClient, params json.RawMessage) (interface{}, error) {
+	count, err := rg.gctx.Crate().Sqlite.Query().CountUnreadNotifications(ctx, client.UserID)
+	if err != nil {
+		return nil, err
+	}
+	return structures.NotificationCountResponse{UnreadCount: count}, nil
+}
+
+// markReadOperation marks one of the user's notifications as read, like PUT /notifications/:id/read
+func (rg *RouteGroup) markReadOperation(ctx context.Context, client websocket.ConnectedClient, params json.RawMessage) (interface{}, error) {
+	var req struct {
+		ID string `json:"id"`
+	}
+	if err := decodeParams(params, &req); err != nil {
+		return nil, err
+	}
+	if req.ID == "" {
+		return nil, &websocket.OperationError{Code: "bad_request", Message: "Notification ID is required"}
+	}
+
+	query := rg.gctx.Crate().Sqlite.Query()
+
+	notification, err := query.GetNotificationById(ctx, req.ID)
+	if err != nil || notification.UserID != client.UserID {
+		return nil, &websocket.OperationError{Code: "not_found", Message: "Notification not found"}
+	}
+
+	err = query.MarkNotificationAsRead(ctx, repository.MarkNotificationAsReadParams{
+		ID:     req.ID,
+		UserID: client.UserID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := query.GetNotificationById(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	notif := toNotification(updated)
+	websocket.BroadcastToUser(client.UserID, structures.OpcodeNotification, structures.NotificationWebSocketPayload{
+		Type:         "notification_updated",
+		Notification: notif,
+	})
+
+	return notif, nil
+}
+
+// markAllReadOperation marks all of the user's unread notifications as read
+func (rg *RouteGroup) markAllReadOperation(ctx context.Context, client websocket.ConnectedClient, params json.RawMessage) (interface{}, error) {
+	query := rg.gctx.Crate().Sqlite.Query()
+
+	unread, err := query.GetUnreadUserNotifications(ctx, client.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(unread) > 0 {
+		ids := make([]string, 0, len(unread))
+		for _, n := range unread {
+			ids = append(ids, n.ID)
+		}
+		err = query.BulkMarkAsRead(ctx, repository.BulkMarkAsReadParams{
+			NotificationIds: ids,
+			UserID:          client.UserID,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return map[string]interface{}{
+		"updated_count": len(unread),
+		"unread_count":  0,
+	}, nil
+}
+
+// decodeParams decodes the params of an operation, which may be left out
+func decodeParams(params json.RawMessage, v interface{}) error {
+	if len(params) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(params, v); err != nil {
+		return &websocket.OperationError{Code: "bad_request", Message: "Invalid params"}
+	}
+	return nil
+}
+
+// toNotification converts a stored notification to its API format
+func toNotification(n repository.Notification) structures.Notification {
+	notification := structures.Notification{
+		ID:       n.ID,
+		UserID:   n.UserID,
+		Title:    n.Title,
+		Message:  n.Message,
+		Type:     structures.NotificationType(n.Type),
+		Priority: structures.NotificationPriority(n.Priority),
+	}
+	if n.CreatedAt.Valid {
+		notification.CreatedAt = n.CreatedAt.Time
+	}
+
+	if n.Data.Valid {
+		var data structures.NotificationData
+		if err := data.Scan(n.Data.String); err == nil {
+			notification.Data = &data
+		}
+	}
+
+	if n.ReadAt.Valid {
+		notification.ReadAt = &n.ReadAt.Time
+	}
+
+	if n.ExpiresAt.Valid {
+		notification.ExpiresAt = &n.ExpiresAt.Time
+	}
+
+	return notification
+}
@@ -0,0 +1,101 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/mahcks/serra/config"
+	"github.com/mahcks/serra/internal/db/dbtest"
+	"github.com/mahcks/serra/internal/global"
+	"github.com/mahcks/serra/internal/websocket"
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+func TestNotificationOperations(t *testing.T) {
+	gctx := global.New(context.Background(), &config.Bootstrap{}, "test", "")
+	gctx.Crate().Sqlite = dbtest.Service(t)
+	ctx := context.Background()
+
+	statements := []string{
+		`INSERT INTO users (id, username) VALUES ('alice', 'alice'), ('bob', 'bob')`,
+		`INSERT INTO notifications (id, user_id, title, message, type, created_at) VALUES
+			('a1', 'alice', 'First', 'first', 'info', datetime('now', '-2 minutes')),
+			('a2', 'alice', 'Second', 'second', 'info', datetime('now', '-1 minute')),
+			('a3', 'alice', 'Third', 'third', 'success', datetime('now')),
+			('b1', 'bob', 'Bob', 'bob', 'info', datetime('now'))`,
+	}
+	for _, statement := range statements {
+		if _, err := gctx.Crate().Sqlite.DB().Exec(statement); err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+
+	rg := NewRouteGroup(gctx)
+	alice := websocket.ConnectedClient{UserID: "alice"}
+	params := func(v string) json.RawMessage { return json.RawMessage(v) }
+	wantCode := func(err error, code string) {
+		t.Helper()
+		var opErr *websocket.OperationError
+		if !errors.As(err, &opErr) || opErr.Code != code {
+			t.Errorf("error = %v, want code %s", err, code)
+		}
+	}
+	unread := func() int64 {
+		t.Helper()
+		data, err := rg.countOperation(ctx, alice, nil)
+		if err != nil {
+			t.Fatalf("count: %v", err)
+		}
+		return data.(structures.NotificationCountResponse).UnreadCount
+	}
+
+	data, err := rg.listOperation(ctx, alice, params(`{"limit": 2}`))
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	list := data.(structures.NotificationsResponse)
+	if len(list.Notifications) != 2 || list.Unread != 3 || !list.HasMore || list.Notifications[0].ID != "a3" {
+		t.Errorf("list = %+v, want the 2 newest of alice's 3 unread notifications", list)
+	}
+	_, err = rg.listOperation(ctx, alice, params(`{"limit": "all"}`))
+	wantCode(err, "bad_request")
+
+	// Only the user's own notifications can be marked as read
+	_, err = rg.markReadOperation(ctx, alice, params(`{"id": "b1"}`))
+	wantCode(err, "not_found")
+	_, err = rg.markReadOperation(ctx, alice, params(`{}`))
+	wantCode(err, "bad_request")
+
+	data, err = rg.markReadOperation(ctx, alice, params(`{"id": "a1"}`))
+	if err != nil {
+		t.Fatalf("mark read: %v", err)
+	}
+	if notification := data.(structures.Notification); notification.ID != "a1" || notification.ReadAt == nil {
+		t.Errorf("marked %+v, want a1 read", notification)
+	}
+	if count := unread(); count != 2 {
+		t.Errorf("unread = %d after marking one, want 2", count)
+	}
+
+	data, err = rg.markAllReadOperation(ctx, alice, nil)
+	if err != nil {
+		t.Fatalf("mark all read: %v", err)
+	}
+	if updated := data.(map[string]interface{})["updated_count"]; updated != 2 {
+		t.Errorf("updated_count = %v, want 2", updated)
+	}
+	if count := unread(); count != 0 {
+		t.Errorf("unread = %d after marking all, want 0", count)
+	}
+
+	// Other users are left alone
+	count, err := gctx.Crate().Sqlite.Query().CountUnreadNotifications(ctx, "bob")
+	if err != nil {
+		t.Fatalf("count bob's notifications: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("bob has %d unread notifications, want 1", count)
+	}
+}
@@ -3,15 +3,18 @@ package radarr
 import (
 	"github.com/mahcks/serra/config"
 	"github.com/mahcks/serra/internal/global"
+	"github.com/mahcks/serra/internal/integrations"
 )
 
 type RouteGroup struct {
-	gctx global.Context
+	gctx         global.Context
+	integrations *integrations.Integration
 }
 
-func NewRouteGroup(gctx global.Context) *RouteGroup {
+func NewRouteGroup(gctx global.Context, integrations *integrations.Integration) *RouteGroup {
 	return &RouteGroup{
-		gctx: gctx,
+		gctx:         gctx,
+		integrations: integrations,
 	}
 }
 
@@ -1,14 +1,12 @@
 package radarr
 
 import (
-	"encoding/json"
-	"fmt"
-	"net/http"
-	"time"
+	"log/slog"
 
+	"github.com/mahcks/serra/internal/db/repository"
 	"github.com/mahcks/serra/internal/rest/v1/respond"
+	"github.com/mahcks/serra/internal/secrets"
 	apiErrors "github.com/mahcks/serra/pkg/api_errors"
-	"github.com/mahcks/serra/pkg/structures"
 )
 
 type getRootFoldersRequest struct {
@@ -16,70 +14,19 @@ type getRootFoldersRequest struct {
 	APIKey  string `json:"api_key"`
 }
 
-type rootFolder struct {
-	Path            string           `json:"path"`
-	Accessible      bool             `json:"accessible"`
-	FreeSpace       int64            `json:"free_space"`
-	UnmappedFolders []unmappedFolder `json:"unmapped_folders,omitempty"`
-}
-
-type unmappedFolder struct {
-	Name         string `json:"name"`
-	Path         string `json:"path"`
-	RelativePath string `json:"relative_path"`
-}
-
+// GetRootFolders lists the root folders of a Radarr server that is being set up
 func (rg *RouteGroup) GetRootFolders(ctx *respond.Ctx) error {
 	var req getRootFoldersRequest
 	if err := ctx.BodyParser(&req); err != nil {
 		return apiErrors.ErrBadRequest().SetDetail("failed to parse request body")
 	}
 
-	client := &http.Client{Timeout: 30 * time.Second}
-	url := fmt.Sprintf("%s/api/v3/rootFolder", req.BaseURL)
-	httpReq, err := http.NewRequest("GET", url, nil)
+	instance := repository.ArrService{BaseUrl: req.BaseURL, ApiKey: secrets.String(req.APIKey)}
+	folders, err := rg.integrations.Radarr.GetRootFolders(ctx.Context(), instance)
 	if err != nil {
-		return apiErrors.ErrInternalServerError().SetDetail("failed to create request")
-	}
-	httpReq.Header.Set("X-Api-Key", req.APIKey)
-
-	resp, err := client.Do(httpReq)
-	if err != nil {
-		return apiErrors.ErrBadGateway().SetDetail("failed to contact Radarr server")
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		return apiErrors.ErrInternalServerError().SetDetail("Radarr server returned an error: " + resp.Status)
-	}
-
-	var folders []rootFolder
-	if err := json.NewDecoder(resp.Body).Decode(&folders); err != nil {
-		return apiErrors.ErrInternalServerError().SetDetail("failed to decode Radarr response")
-	}
-
-	var result []structures.RadarrRootFolder
-	for _, folder := range folders {
-		rf := structures.RadarrRootFolder{
-			Path:       folder.Path,
-			Accessible: folder.Accessible,
-			FreeSpace:  folder.FreeSpace,
-		}
-
-		if len(folder.UnmappedFolders) > 0 {
-			umappedFolders := make([]structures.RadarrUnmappedFolder, len(folder.UnmappedFolders))
-			for i, unmapped := range folder.UnmappedFolders {
-				umappedFolders[i] = structures.RadarrUnmappedFolder{
-					Name:         unmapped.Name,
-					Path:         unmapped.Path,
-					RelativePath: unmapped.RelativePath,
-				}
-			}
-			rf.UnmappedFolders = umappedFolders
-		}
-
-		result = append(result, rf)
+		slog.Warn("Failed to fetch Radarr root folders", "base_url", req.BaseURL, "error", err)
+		return apiErrors.ErrBadGateway().SetDetail("failed to fetch root folders from Radarr: %s", err.Error())
 	}
 
-	return ctx.JSON(result)
+	return ctx.JSON(folders)
 }
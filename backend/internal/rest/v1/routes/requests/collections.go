@@ -0,0 +1,84 @@
+package requests
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/mahcks/serra/internal/db/repository"
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+// Aggregate statuses of a collection request whose parts differ, most urgent first
+var collectionStatusOrder = []string{"failed", "pending", "processing", "approved", "denied"}
+
+// groupCollectionRequests lists the movie requests made through a collection
+// request as one entry. The first part stays in its place and carries the
+// collection, the other parts are moved into its parts.
+func (rg *RouteGroup) groupCollectionRequests(ctx context.Context, apiRequests []structures.Request) []structures.Request {
+	items, err := rg.gctx.Crate().Sqlite.Query().GetCollectionRequestItems(ctx)
+	if err != nil {
+		slog.Error("Failed to get collection request items", "error", err)
+		return apiRequests
+	}
+	if len(items) == 0 {
+		return apiRequests
+	}
+
+	itemByRequest := make(map[int64]repository.GetCollectionRequestItemsRow, len(items))
+	for _, item := range items {
+		itemByRequest[item.RequestID] = item
+	}
+
+	// Position of the first part of each collection request in grouped
+	positions := make(map[int64]int)
+	grouped := make([]structures.Request, 0, len(apiRequests))
+	for _, request := range apiRequests {
+		item, ok := itemByRequest[request.ID]
+		if !ok {
+			grouped = append(grouped, request)
+			continue
+		}
+
+		position, exists := positions[item.CollectionRequestID]
+		if !exists {
+			request.Collection = &structures.RequestCollection{
+				ID:           item.CollectionRequestID,
+				CollectionID: item.CollectionID,
+				Name:         item.Name,
+				PosterURL:    item.PosterUrl.String,
+			}
+			positions[item.CollectionRequestID] = len(grouped)
+			grouped = append(grouped, request)
+			continue
+		}
+		grouped[position].Parts = append(grouped[position].Parts, request)
+	}
+
+	for _, position := range positions {
+		first := &grouped[position]
+		first.Collection.Status = collectionStatus(append([]structures.Request{*first}, first.Parts...))
+	}
+
+	return grouped
+}
+
+// collectionStatus is the status shared by all parts. When they differ it is
+// "partial" if some parts are fulfilled, otherwise the most urgent status.
+func collectionStatus(parts []structures.Request) string {
+	statuses := make(map[string]bool)
+	for _, part := range parts {
+		statuses[part.Status] = true
+	}
+	if len(statuses) == 1 {
+		return parts[0].Status
+	}
+	if statuses["fulfilled"] {
+		return "partial"
+	}
+	for _, status := range collectionStatusOrder {
+		if statuses[status] {
+			return status
+		}
+	}
+	return parts[0].Status
+}
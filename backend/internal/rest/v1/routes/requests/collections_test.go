@@ -0,0 +1,62 @@
+package requests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mahcks/serra/config"
+	"github.com/mahcks/serra/internal/db/dbtest"
+	"github.com/mahcks/serra/internal/global"
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+func TestGroupCollectionRequestsKeepsRequestFields(t *testing.T) {
+	gctx := global.New(context.Background(), &config.Bootstrap{}, "test", "")
+	gctx.Crate().Sqlite = dbtest.Service(t)
+
+	statements := []string{
+		`INSERT INTO users (id, username) VALUES ('alice', 'alice')`,
+		`INSERT INTO requests (id, user_id, media_type, tmdb_id, title, status) VALUES
+			(1, 'alice', 'movie', 603, 'The Matrix', 'fulfilled'),
+			(2, 'alice', 'tv', 1399, 'Show', 'pending'),
+			(3, 'alice', 'movie', 604, 'The Matrix Reloaded', 'pending')`,
+		`INSERT INTO collection_requests (id, collection_id, user_id, name) VALUES (9, 2344, 'alice', 'The Matrix Collection')`,
+		`INSERT INTO collection_request_items (request_id, collection_request_id, tmdb_id) VALUES (1, 9, 603), (3, 9, 604)`,
+	}
+	for _, statement := range statements {
+		if _, err := gctx.Crate().Sqlite.DB().Exec(statement); err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+
+	tmdbID := func(id int64) *int64 { return &id }
+	requests := []structures.Request{
+		{ID: 1, MediaType: "movie", TmdbID: tmdbID(603), Status: "fulfilled"},
+		{ID: 2, MediaType: "tv", TmdbID: tmdbID(1399), Status: "pending"},
+		{ID: 3, MediaType: "movie", TmdbID: tmdbID(604), Status: "pending"},
+	}
+
+	rg := &RouteGroup{gctx: gctx}
+	grouped := rg.groupCollectionRequests(context.Background(), requests)
+	if len(grouped) != 2 {
+		t.Fatalf("got %d entries, want 2: %+v", len(grouped), grouped)
+	}
+
+	first := grouped[0]
+	if first.ID != 1 || first.MediaType != "movie" || *first.TmdbID != 603 || first.Status != "fulfilled" {
+		t.Errorf("first part changed: id=%d media_type=%s tmdb_id=%d status=%s", first.ID, first.MediaType, *first.TmdbID, first.Status)
+	}
+	if first.Collection == nil {
+		t.Fatal("first part has no collection")
+	}
+	if first.Collection.ID != 9 || first.Collection.CollectionID != 2344 || first.Collection.Status != "partial" {
+		t.Errorf("collection = %+v, want request 9 for collection 2344 with status partial", first.Collection)
+	}
+	if len(first.Parts) != 1 || first.Parts[0].ID != 3 || first.Parts[0].MediaType != "movie" {
+		t.Errorf("parts = %+v, want request 3", first.Parts)
+	}
+
+	if grouped[1].ID != 2 || grouped[1].Collection != nil {
+		t.Errorf("unrelated request changed: %+v", grouped[1])
+	}
+}
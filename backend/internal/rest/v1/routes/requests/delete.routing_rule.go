@@ -0,0 +1,41 @@
+package requests
+
+import (
+	"database/sql"
+	"log/slog"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mahcks/serra/internal/rest/v1/respond"
+	apiErrors "github.com/mahcks/serra/pkg/api_errors"
+)
+
+// DeleteRoutingRule removes a routing rule
+func (rg *RouteGroup) DeleteRoutingRule(ctx *respond.Ctx) error {
+	user := ctx.ParseClaims()
+	if user == nil || user.ID == "" {
+		return apiErrors.ErrUnauthorized()
+	}
+
+	ruleID, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return apiErrors.ErrBadRequest().SetDetail("Invalid routing rule ID")
+	}
+
+	query := rg.gctx.Crate().Sqlite.Query()
+	if _, err := query.GetRoutingRuleByID(ctx.Context(), ruleID); err != nil {
+		if err == sql.ErrNoRows {
+			return apiErrors.ErrNotFound().SetDetail("Routing rule not found")
+		}
+		slog.Error("Failed to get routing rule", "error", err, "rule_id", ruleID)
+		return apiErrors.ErrInternalServerError().SetDetail("Failed to delete routing rule")
+	}
+
+	if err := query.DeleteRoutingRule(ctx.Context(), ruleID); err != nil {
+		slog.Error("Failed to delete routing rule", "error", err, "rule_id", ruleID)
+		return apiErrors.ErrInternalServerError().SetDetail("Failed to delete routing rule")
+	}
+
+	slog.Info("Routing rule deleted", "rule_id", ruleID, "user_id", user.ID)
+	return ctx.JSON(fiber.Map{"message": "Routing rule deleted"})
+}
@@ -0,0 +1,42 @@
+package requests
+
+import (
+	"github.com/mahcks/serra/internal/rest/v1/respond"
+	apiErrors "github.com/mahcks/serra/pkg/api_errors"
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+// GetRequestOptions lists the quality profiles, root folders and tags that can
+// be chosen on the instance a request would be routed to
+func (rg *RouteGroup) GetRequestOptions(ctx *respond.Ctx) error {
+	user := ctx.ParseClaims()
+	if user == nil || user.ID == "" {
+		return apiErrors.ErrUnauthorized()
+	}
+
+	hasAdvanced, err := rg.checkAdvancedPermission(ctx.Context(), user.ID, user.IsAdmin)
+	if err != nil {
+		return apiErrors.ErrInternalServerError().SetDetail("Permission check failed")
+	}
+	if !hasAdvanced {
+		return apiErrors.ErrForbidden().SetDetail("You don't have permission to set advanced request options")
+	}
+
+	mediaType := ctx.Query("media_type")
+	if mediaType != "movie" && mediaType != "tv" {
+		return apiErrors.ErrInvalidMediaType().SetDetail("media_type must be 'movie' or 'tv'")
+	}
+
+	// tmdb_id is optional; without it only rules that don't need TMDB details can match
+	options, err := rg.requestProcessor.GetRequestOptions(ctx.Context(), structures.RoutingInput{
+		MediaType: mediaType,
+		TmdbID:    int64(ctx.QueryInt("tmdb_id", 0)),
+		Is4K:      ctx.QueryBool("is_4k", false),
+		UserID:    user.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(options)
+}
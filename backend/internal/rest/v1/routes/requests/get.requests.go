@@ -7,6 +7,7 @@ import (
 	"strconv"
 
 	"github.com/mahcks/serra/internal/db/repository"
+	"github.com/mahcks/serra/internal/jobs"
 	"github.com/mahcks/serra/internal/rest/v1/respond"
 	apiErrors "github.com/mahcks/serra/pkg/api_errors"
 	"github.com/mahcks/serra/pkg/permissions"
@@ -80,6 +81,12 @@ func (rg *RouteGroup) GetAllRequests(ctx *respond.Ctx) error {
 			Status:    req.Status,
 			CreatedAt: req.CreatedAt.Format("2006-01-02T15:04:05Z"),
 			UpdatedAt: req.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+			Is4K:      req.Is4k,
+			Episodes:        requestEpisodes(req),
+			FollowShow:      req.FollowShow,
+			ParentRequestID: parentRequestID(req),
+			Priority:        req.Priority,
+			RequestOverrides: requestOverrides(req),
 		}
 		
 		if req.TmdbID.Valid {
@@ -92,6 +99,9 @@ func (rg *RouteGroup) GetAllRequests(ctx *respond.Ctx) error {
 		if req.Notes.Valid {
 			apiRequest.Notes = req.Notes.String
 		}
+		if req.DenyReason.Valid {
+			apiRequest.DenyReason = req.DenyReason.String
+		}
 		if req.FulfilledAt.Valid {
 			fulfilledAt := req.FulfilledAt.Time.Format("2006-01-02T15:04:05Z")
 			apiRequest.FulfilledAt = fulfilledAt
@@ -109,7 +119,7 @@ func (rg *RouteGroup) GetAllRequests(ctx *respond.Ctx) error {
 		apiRequests = append(apiRequests, apiRequest)
 	}
 
-	return ctx.JSON(apiRequests)
+	return ctx.JSON(rg.groupCollectionRequests(ctx.Context(), apiRequests))
 }
 
 // GetUserRequests returns current user's requests
@@ -144,6 +154,12 @@ func (rg *RouteGroup) GetUserRequests(ctx *respond.Ctx) error {
 			Status:    req.Status,
 			CreatedAt: req.CreatedAt.Format("2006-01-02T15:04:05Z"),
 			UpdatedAt: req.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+			Is4K:      req.Is4k,
+			Episodes:        requestEpisodes(req),
+			FollowShow:      req.FollowShow,
+			ParentRequestID: parentRequestID(req),
+			Priority:        req.Priority,
+			RequestOverrides: requestOverrides(req),
 		}
 		
 		if req.TmdbID.Valid {
@@ -156,6 +172,9 @@ func (rg *RouteGroup) GetUserRequests(ctx *respond.Ctx) error {
 		if req.Notes.Valid {
 			apiRequest.Notes = req.Notes.String
 		}
+		if req.DenyReason.Valid {
+			apiRequest.DenyReason = req.DenyReason.String
+		}
 		if req.FulfilledAt.Valid {
 			fulfilledAt := req.FulfilledAt.Time.Format("2006-01-02T15:04:05Z")
 			apiRequest.FulfilledAt = fulfilledAt
@@ -173,7 +192,7 @@ func (rg *RouteGroup) GetUserRequests(ctx *respond.Ctx) error {
 		apiRequests = append(apiRequests, apiRequest)
 	}
 
-	return ctx.JSON(apiRequests)
+	return ctx.JSON(rg.groupCollectionRequests(ctx.Context(), apiRequests))
 }
 
 // GetPendingRequests returns pending requests (admin only)
@@ -246,7 +265,25 @@ func (rg *RouteGroup) GetRequestByID(ctx *respond.Ctx) error {
 		return apiErrors.ErrForbidden().SetDetail("You don't have permission to view this request")
 	}
 
-	return ctx.JSON(request)
+	// Include everything that was downloaded for the request, oldest first
+	history, err := rg.gctx.Crate().Sqlite.Query().ListDownloadHistoryByRequest(ctx.Context(), sql.NullInt64{Int64: request.ID, Valid: true})
+	if err != nil {
+		slog.Error("Failed to get download history for request", "error", err, "request_id", requestID)
+		return apiErrors.ErrInternalServerError().SetDetail("Failed to retrieve download history")
+	}
+
+	downloadHistory := make([]structures.DownloadHistoryEntry, 0, len(history))
+	for _, row := range history {
+		downloadHistory = append(downloadHistory, jobs.DownloadHistoryEntry(row))
+	}
+
+	return ctx.JSON(struct {
+		repository.Request
+		DownloadHistory []structures.DownloadHistoryEntry `json:"download_history"`
+	}{
+		Request:         request,
+		DownloadHistory: downloadHistory,
+	})
 }
 
 // GetRequestStatistics returns request statistics (admin only)
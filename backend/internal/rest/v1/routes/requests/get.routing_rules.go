@@ -0,0 +1,30 @@
+package requests
+
+import (
+	"log/slog"
+
+	"github.com/mahcks/serra/internal/rest/v1/respond"
+	apiErrors "github.com/mahcks/serra/pkg/api_errors"
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+// GetRoutingRules lists all routing rules in evaluation order
+func (rg *RouteGroup) GetRoutingRules(ctx *respond.Ctx) error {
+	user := ctx.ParseClaims()
+	if user == nil || user.ID == "" {
+		return apiErrors.ErrUnauthorized()
+	}
+
+	rules, err := rg.gctx.Crate().Sqlite.Query().GetRoutingRules(ctx.Context())
+	if err != nil {
+		slog.Error("Failed to get routing rules", "error", err)
+		return apiErrors.ErrInternalServerError().SetDetail("Failed to retrieve routing rules")
+	}
+
+	apiRules := make([]structures.RoutingRule, 0, len(rules))
+	for _, rule := range rules {
+		apiRules = append(apiRules, toRoutingRule(rule))
+	}
+
+	return ctx.JSON(apiRules)
+}
@@ -0,0 +1,28 @@
+package requests
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+
+	"github.com/mahcks/serra/internal/db/repository"
+)
+
+// recordDecision records an approver approving or denying a request in
+// request_metrics, along with how long the request waited. Approval analytics
+// are based on these, so auto-approved requests aren't recorded. A failure is
+// logged but doesn't undo the decision.
+func recordDecision(ctx context.Context, query *repository.Queries, request repository.Request, newStatus, approverID string) {
+	_, err := query.RecordRequestMetric(ctx, repository.RecordRequestMetricParams{
+		RequestID:             request.ID,
+		StatusChange:          newStatus,
+		PreviousStatus:        sql.NullString{String: request.Status, Valid: true},
+		NewStatus:             newStatus,
+		ProcessingTimeSeconds: sql.NullInt64{Int64: int64(time.Since(request.CreatedAt).Seconds()), Valid: true},
+		UserID:                approverID,
+	})
+	if err != nil {
+		slog.Error("Failed to record request decision", "error", err, "request_id", request.ID, "status", newStatus)
+	}
+}
@@ -0,0 +1,127 @@
+package requests
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log/slog"
+
+	"github.com/mahcks/serra/internal/db/repository"
+	apiErrors "github.com/mahcks/serra/pkg/api_errors"
+	"github.com/mahcks/serra/pkg/permissions"
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+// storedOverrides holds request overrides in their database representation
+type storedOverrides struct {
+	QualityProfileID sql.NullInt64
+	RootFolderPath   sql.NullString
+	Tags             sql.NullString
+	SeriesType       sql.NullString
+}
+
+// checkAdvancedPermission reports whether the user may set advanced request
+// options. Users who can approve requests may always set them.
+func (rg *RouteGroup) checkAdvancedPermission(ctx context.Context, userID string, isAdmin bool) (bool, error) {
+	if isAdmin {
+		return true, nil
+	}
+
+	hasPermission, err := rg.checkUserPermissionForUpdate(ctx, userID, permissions.RequestAdvanced)
+	if err != nil || hasPermission {
+		return hasPermission, err
+	}
+	return rg.checkUserPermissionForUpdate(ctx, userID, permissions.RequestsApprove)
+}
+
+// toStoredOverrides converts validated overrides into database columns
+func toStoredOverrides(overrides structures.RequestOverrides) (storedOverrides, error) {
+	var stored storedOverrides
+
+	if overrides.QualityProfileID != nil {
+		stored.QualityProfileID = sql.NullInt64{Int64: int64(*overrides.QualityProfileID), Valid: true}
+	}
+	if overrides.RootFolderPath != nil {
+		stored.RootFolderPath = sql.NullString{String: *overrides.RootFolderPath, Valid: true}
+	}
+	if len(overrides.Tags) > 0 {
+		tagsJSON, err := json.Marshal(overrides.Tags)
+		if err != nil {
+			slog.Error("Failed to marshal tags", "error", err)
+			return stored, apiErrors.ErrInternalServerError().SetDetail("Failed to process tags")
+		}
+		stored.Tags = sql.NullString{String: string(tagsJSON), Valid: true}
+	}
+	if overrides.SeriesType != nil {
+		stored.SeriesType = sql.NullString{String: *overrides.SeriesType, Valid: true}
+	}
+
+	return stored, nil
+}
+
+// requestOverrides reads the overrides stored on a request
+func requestOverrides(request repository.Request) structures.RequestOverrides {
+	var overrides structures.RequestOverrides
+
+	if request.QualityProfileID.Valid {
+		qualityProfileID := int(request.QualityProfileID.Int64)
+		overrides.QualityProfileID = &qualityProfileID
+	}
+	if request.RootFolderPath.Valid {
+		rootFolderPath := request.RootFolderPath.String
+		overrides.RootFolderPath = &rootFolderPath
+	}
+	if request.Tags.Valid {
+		var tags []int
+		if err := json.Unmarshal([]byte(request.Tags.String), &tags); err == nil {
+			overrides.Tags = tags
+		}
+	}
+	if request.SeriesType.Valid {
+		seriesType := request.SeriesType.String
+		overrides.SeriesType = &seriesType
+	}
+
+	return overrides
+}
+
+// mergeOverrides applies the overrides an approver set on top of the ones
+// stored on the request. Omitted fields keep the stored value, while an empty
+// tag list clears the tags.
+func mergeOverrides(stored, changes structures.RequestOverrides) structures.RequestOverrides {
+	if changes.QualityProfileID != nil {
+		stored.QualityProfileID = changes.QualityProfileID
+	}
+	if changes.RootFolderPath != nil {
+		stored.RootFolderPath = changes.RootFolderPath
+	}
+	if changes.Tags != nil {
+		stored.Tags = changes.Tags
+	}
+	if changes.SeriesType != nil {
+		stored.SeriesType = changes.SeriesType
+	}
+	return stored
+}
+
+// requestEpisodes reads the individual episodes stored on a TV request
+func requestEpisodes(request repository.Request) structures.RequestedEpisodes {
+	if !request.Episodes.Valid || request.Episodes.String == "" {
+		return nil
+	}
+
+	var episodes structures.RequestedEpisodes
+	if err := json.Unmarshal([]byte(request.Episodes.String), &episodes); err != nil {
+		return nil
+	}
+	return episodes
+}
+
+// parentRequestID returns the followed request a follow-up request was opened for
+func parentRequestID(request repository.Request) *int64 {
+	if !request.ParentRequestID.Valid {
+		return nil
+	}
+	parentID := request.ParentRequestID.Int64
+	return &parentID
+}
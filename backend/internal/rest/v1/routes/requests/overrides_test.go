@@ -0,0 +1,49 @@
+package requests
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+func TestMergeOverridesTags(t *testing.T) {
+	profile := 4
+	stored := structures.RequestOverrides{QualityProfileID: &profile, Tags: []int{1, 2}}
+
+	tests := []struct {
+		name string
+		body string
+		want []int
+	}{
+		{name: "omitted keeps the stored tags", body: `{"status":"approved"}`, want: []int{1, 2}},
+		{name: "empty list clears the tags", body: `{"status":"approved","tags":[]}`, want: []int{}},
+		{name: "new list replaces the tags", body: `{"status":"approved","tags":[3]}`, want: []int{3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req structures.UpdateRequestRequest
+			if err := json.Unmarshal([]byte(tt.body), &req); err != nil {
+				t.Fatal(err)
+			}
+
+			merged := mergeOverrides(stored, req.RequestOverrides)
+			if !slices.Equal(merged.Tags, tt.want) {
+				t.Errorf("tags = %v, want %v", merged.Tags, tt.want)
+			}
+			if merged.QualityProfileID == nil || *merged.QualityProfileID != profile {
+				t.Errorf("quality profile = %v, want %d", merged.QualityProfileID, profile)
+			}
+
+			// Cleared tags are stored as NULL
+			columns, err := toStoredOverrides(merged)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if columns.Tags.Valid != (len(tt.want) > 0) {
+				t.Errorf("stored tags = %+v", columns.Tags)
+			}
+		})
+	}
+}
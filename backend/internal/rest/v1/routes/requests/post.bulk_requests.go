@@ -0,0 +1,327 @@
+package requests
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/mahcks/serra/internal/db/repository"
+	"github.com/mahcks/serra/internal/rest/v1/respond"
+	apiErrors "github.com/mahcks/serra/pkg/api_errors"
+	"github.com/mahcks/serra/pkg/permissions"
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+// maxBulkRequests is the largest number of requests a single bulk action may change
+const maxBulkRequests = 500
+
+// BulkUpdateRequests approves, denies, deletes or re-prioritises many requests
+// at once. The changes are made in a single transaction and reported per
+// request. Approved requests are processed and requesters notified in the
+// background once the transaction is committed.
+func (rg *RouteGroup) BulkUpdateRequests(ctx *respond.Ctx) error {
+	user := ctx.ParseClaims()
+	if user == nil || user.ID == "" {
+		return apiErrors.ErrUnauthorized()
+	}
+
+	var req structures.BulkRequestRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return apiErrors.ErrBadRequest().SetDetail("Invalid request body")
+	}
+
+	switch req.Action {
+	case structures.BulkRequestApprove, structures.BulkRequestDeny, structures.BulkRequestDelete:
+	case structures.BulkRequestPrioritize:
+		if !validRequestPriority(req.Priority) {
+			return apiErrors.ErrBadRequest().SetDetail("priority must be one of low, normal, high, urgent")
+		}
+	default:
+		return apiErrors.ErrBadRequest().SetDetail("action must be one of approve, deny, delete, prioritize")
+	}
+
+	if len(req.IDs) == 0 && (req.Filter == nil || !req.Filter.IsSet()) {
+		return apiErrors.ErrBadRequest().SetDetail("Either ids or a filter is required")
+	}
+	if len(req.IDs) > 0 && req.Filter != nil {
+		return apiErrors.ErrBadRequest().SetDetail("ids and filter can't be combined")
+	}
+	if len(req.IDs) > maxBulkRequests {
+		return apiErrors.ErrBadRequest().SetDetail("At most %d requests can be changed at once", maxBulkRequests)
+	}
+
+	// Deleting needs requests.manage, everything else approvers may do as well
+	canApprove := user.IsAdmin
+	canManage := user.IsAdmin
+	if !user.IsAdmin {
+		var err error
+		canApprove, err = rg.checkUserPermission(ctx.Context(), user.ID, permissions.RequestsApprove)
+		if err != nil {
+			slog.Error("Failed to check approve permission", "error", err)
+			return apiErrors.ErrInternalServerError().SetDetail("Permission check failed")
+		}
+		canManage, err = rg.checkUserPermission(ctx.Context(), user.ID, permissions.RequestsManage)
+		if err != nil {
+			slog.Error("Failed to check manage permission", "error", err)
+			return apiErrors.ErrInternalServerError().SetDetail("Permission check failed")
+		}
+	}
+	if req.Action == structures.BulkRequestDelete && !canManage {
+		return apiErrors.ErrForbidden().SetDetail("You don't have permission to delete requests")
+	}
+	if !canApprove && !canManage {
+		return apiErrors.ErrForbidden().SetDetail("You don't have permission to manage requests")
+	}
+
+	// Keep a restore from closing the pool under the transaction
+	db, release, err := rg.gctx.Crate().Sqlite.Hold()
+	if err != nil {
+		return apiErrors.ErrConflict().SetDetail("A database restore is in progress, try again in a moment")
+	}
+	defer release()
+
+	tx, err := db.BeginTx(ctx.Context(), nil)
+	if err != nil {
+		slog.Error("Failed to begin transaction", "error", err)
+		return apiErrors.ErrInternalServerError()
+	}
+	defer tx.Rollback()
+
+	query := rg.gctx.Crate().Sqlite.Query().WithTx(tx)
+
+	targets, items, err := rg.bulkTargets(ctx.Context(), query, req)
+	if err != nil {
+		slog.Error("Failed to select requests for bulk action", "error", err, "action", req.Action)
+		return apiErrors.ErrInternalServerError().SetDetail("Failed to select requests")
+	}
+	if len(targets)+len(items) > maxBulkRequests {
+		return apiErrors.ErrBadRequest().SetDetail("The filter matches more than %d requests, narrow it down", maxBulkRequests)
+	}
+
+	var changed []repository.Request
+	for _, target := range targets {
+		item, updated := rg.applyBulkAction(ctx.Context(), query, req, user.ID, target)
+		if updated != nil {
+			changed = append(changed, *updated)
+		}
+		items = append(items, item)
+	}
+
+	if err := tx.Commit(); err != nil {
+		slog.Error("Failed to commit bulk request action", "error", err, "action", req.Action)
+		return apiErrors.ErrInternalServerError().SetDetail("Failed to save changes")
+	}
+
+	result := structures.BulkRequestResult{
+		Action: req.Action,
+		Total:  len(items),
+		Items:  items,
+	}
+	for _, item := range items {
+		switch item.Result {
+		case "updated":
+			result.Updated++
+		case "skipped":
+			result.Skipped++
+		default:
+			result.Failed++
+		}
+	}
+
+	slog.Info("Bulk request action applied",
+		"action", req.Action,
+		"user_id", user.ID,
+		"total", result.Total,
+		"updated", result.Updated,
+		"skipped", result.Skipped,
+		"failed", result.Failed)
+
+	if len(changed) > 0 && (req.Action == structures.BulkRequestApprove || req.Action == structures.BulkRequestDeny) {
+		go rg.afterBulkAction(req.Action, req.Reason, changed)
+	}
+
+	return ctx.JSON(result)
+}
+
+// bulkTargets returns the requests a bulk action applies to. Ids that don't
+// exist are reported as failed items straight away.
+func (rg *RouteGroup) bulkTargets(ctx context.Context, query *repository.Queries, req structures.BulkRequestRequest) ([]repository.Request, []structures.BulkRequestItem, error) {
+	if len(req.IDs) == 0 {
+		filter := req.Filter
+		params := repository.GetRequestsByFilterParams{
+			Status:    sql.NullString{String: filter.Status, Valid: filter.Status != ""},
+			MediaType: sql.NullString{String: filter.MediaType, Valid: filter.MediaType != ""},
+			UserID:    sql.NullString{String: filter.UserID, Valid: filter.UserID != ""},
+		}
+		// created_at is stored in UTC
+		if filter.From != nil {
+			params.CreatedAfter = sql.NullTime{Time: filter.From.UTC(), Valid: true}
+		}
+		if filter.To != nil {
+			params.CreatedBefore = sql.NullTime{Time: filter.To.UTC(), Valid: true}
+		}
+
+		targets, err := query.GetRequestsByFilter(ctx, params)
+		return targets, nil, err
+	}
+
+	var targets []repository.Request
+	var missing []structures.BulkRequestItem
+	seen := make(map[int64]bool, len(req.IDs))
+	for _, id := range req.IDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		request, err := query.GetRequestByID(ctx, id)
+		if err == sql.ErrNoRows {
+			missing = append(missing, structures.BulkRequestItem{
+				RequestID: id,
+				Result:    "failed",
+				Reason:    "Request not found",
+			})
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		targets = append(targets, request)
+	}
+	return targets, missing, nil
+}
+
+// applyBulkAction applies the action to one request. It returns the updated
+// request when it changed, nil otherwise.
+func (rg *RouteGroup) applyBulkAction(ctx context.Context, query *repository.Queries, req structures.BulkRequestRequest, userID string, request repository.Request) (structures.BulkRequestItem, *repository.Request) {
+	item := structures.BulkRequestItem{
+		RequestID: request.ID,
+		Status:    request.Status,
+		Priority:  request.Priority,
+	}
+
+	var updated repository.Request
+	var err error
+	switch req.Action {
+	case structures.BulkRequestApprove:
+		if request.Status != "pending" && request.Status != "denied" && request.Status != "failed" {
+			item.Result = "skipped"
+			item.Reason = fmt.Sprintf("Request is %s", request.Status)
+			return item, nil
+		}
+		updated, err = query.UpdateRequestStatus(ctx, repository.UpdateRequestStatusParams{
+			Status:     "approved",
+			ApproverID: sql.NullString{String: userID, Valid: true},
+			ID:         request.ID,
+		})
+	case structures.BulkRequestDeny:
+		if request.Status != "pending" && request.Status != "approved" && request.Status != "failed" {
+			item.Result = "skipped"
+			item.Reason = fmt.Sprintf("Request is %s", request.Status)
+			return item, nil
+		}
+		updated, err = query.UpdateRequestStatus(ctx, repository.UpdateRequestStatusParams{
+			Status:     "denied",
+			ApproverID: sql.NullString{String: userID, Valid: true},
+			ID:         request.ID,
+		})
+		// The reason is kept apart from the notes the requester wrote
+		if err == nil && req.Reason != "" {
+			updated, err = query.UpdateRequestDenyReason(ctx, repository.UpdateRequestDenyReasonParams{
+				DenyReason: sql.NullString{String: req.Reason, Valid: true},
+				ID:         request.ID,
+			})
+		}
+	case structures.BulkRequestPrioritize:
+		if request.Priority == req.Priority {
+			item.Result = "skipped"
+			item.Reason = fmt.Sprintf("Priority is already %s", req.Priority)
+			return item, nil
+		}
+		updated, err = query.UpdateRequestPriority(ctx, repository.UpdateRequestPriorityParams{
+			Priority: req.Priority,
+			ID:       request.ID,
+		})
+	case structures.BulkRequestDelete:
+		if err := query.DeleteRequest(ctx, request.ID); err != nil {
+			slog.Error("Failed to delete request", "error", err, "request_id", request.ID)
+			item.Result = "failed"
+			item.Reason = "Failed to delete request"
+			return item, nil
+		}
+		item.Result = "updated"
+		item.Status = ""
+		item.Priority = ""
+		return item, &request
+	}
+
+	if err != nil {
+		slog.Error("Failed to apply bulk action to request", "error", err, "action", req.Action, "request_id", request.ID)
+		item.Result = "failed"
+		item.Reason = "Failed to update request"
+		return item, nil
+	}
+
+	if req.Action == structures.BulkRequestApprove || req.Action == structures.BulkRequestDeny {
+		recordDecision(ctx, query, request, updated.Status, userID)
+	}
+
+	item.Result = "updated"
+	item.Status = updated.Status
+	item.Priority = updated.Priority
+	return item, &updated
+}
+
+// afterBulkAction notifies the requesters of approved and denied requests and
+// sends approved ones to Radarr/Sonarr one at a time, so a large batch doesn't
+// flood them
+func (rg *RouteGroup) afterBulkAction(action, reason string, requests []repository.Request) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("Panic while finishing bulk request action", "panic", r)
+		}
+	}()
+
+	notificationService := rg.gctx.Crate().NotificationService
+	for _, request := range requests {
+		if request.Title.Valid {
+			var tmdbID *int64
+			if request.TmdbID.Valid {
+				tmdbID = &request.TmdbID.Int64
+			}
+			requestIDStr := strconv.FormatInt(request.ID, 10)
+
+			var err error
+			if action == structures.BulkRequestApprove {
+				err = notificationService.NotifyRequestApproved(context.Background(), request.UserID, request.Title.String, request.MediaType, tmdbID, &requestIDStr)
+			} else {
+				err = notificationService.NotifyRequestDenied(context.Background(), request.UserID, request.Title.String, request.MediaType, reason, tmdbID, &requestIDStr)
+			}
+			if err != nil {
+				slog.Error("Failed to send request notification", "error", err, "action", action, "request_id", request.ID)
+			}
+		}
+
+		if action == structures.BulkRequestApprove {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+			if err := rg.processApprovedRequest(ctx, request.ID); err != nil {
+				slog.Error("Failed to process approved request - request marked as failed",
+					"request_id", request.ID,
+					"title", request.Title,
+					"error", err)
+			}
+			cancel()
+		}
+	}
+}
+
+func validRequestPriority(priority string) bool {
+	switch priority {
+	case structures.RequestPriorityLow, structures.RequestPriorityNormal, structures.RequestPriorityHigh, structures.RequestPriorityUrgent:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package requests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mahcks/serra/internal/db/dbtest"
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+func TestBulkDenyKeepsReason(t *testing.T) {
+	db, query := dbtest.Open(t)
+	ctx := context.Background()
+	statements := []string{
+		`INSERT INTO users (id, username) VALUES ('alice', 'alice'), ('admin', 'admin')`,
+		`INSERT INTO requests (user_id, media_type, tmdb_id, title, status, notes) VALUES
+			('alice', 'movie', 100, 'Movie 100', 'pending', 'Director''s cut please'),
+			('alice', 'movie', 200, 'Movie 200', 'pending', NULL)`,
+	}
+	for _, statement := range statements {
+		if _, err := db.Exec(statement); err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+
+	rg := &RouteGroup{}
+	tests := []struct {
+		requestID int64
+		reason    string
+		notes     string
+	}{
+		{requestID: 1, reason: "Not available in your region", notes: "Director's cut please"},
+		{requestID: 2, reason: ""},
+	}
+	for _, tt := range tests {
+		request, err := query.GetRequestByID(ctx, tt.requestID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req := structures.BulkRequestRequest{Action: structures.BulkRequestDeny, Reason: tt.reason}
+		item, updated := rg.applyBulkAction(ctx, query, req, "admin", request)
+		if item.Result != "updated" || updated == nil {
+			t.Fatalf("request %d: result = %+v, want updated", tt.requestID, item)
+		}
+
+		stored, err := query.GetRequestByID(ctx, tt.requestID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stored.Status != "denied" {
+			t.Errorf("request %d status = %s, want denied", tt.requestID, stored.Status)
+		}
+		if stored.DenyReason.Valid != (tt.reason != "") || stored.DenyReason.String != tt.reason {
+			t.Errorf("request %d deny reason = %+v, want %q", tt.requestID, stored.DenyReason, tt.reason)
+		}
+		if updated.DenyReason != stored.DenyReason {
+			t.Errorf("request %d: returned deny reason %+v differs from stored %+v", tt.requestID, updated.DenyReason, stored.DenyReason)
+		}
+		// The requester's notes are left alone
+		if stored.Notes.String != tt.notes {
+			t.Errorf("request %d notes = %q, want %q", tt.requestID, stored.Notes.String, tt.notes)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package requests
+
+import (
+	"log/slog"
+	"strconv"
+
+	"github.com/mahcks/serra/internal/rest/v1/respond"
+	apiErrors "github.com/mahcks/serra/pkg/api_errors"
+	"github.com/mahcks/serra/pkg/permissions"
+	"github.com/mahcks/serra/pkg/structures"
+	"github.com/mahcks/serra/utils"
+)
+
+// CreateCollectionRequest requests every movie of a TMDB collection that isn't
+// in the library or requested yet, and reports the result for each movie
+func (rg *RouteGroup) CreateCollectionRequest(ctx *respond.Ctx) error {
+	user := ctx.ParseClaims()
+	if user == nil || user.ID == "" {
+		return apiErrors.ErrUnauthorized()
+	}
+
+	collectionID, err := strconv.ParseInt(ctx.Params("collection_id"), 10, 64)
+	if err != nil || collectionID < 1 {
+		return apiErrors.ErrBadRequest().SetDetail("Invalid collection ID")
+	}
+
+	var req structures.CreateCollectionRequestRequest
+	if len(ctx.Body()) > 0 {
+		if err := ctx.BodyParser(&req); err != nil {
+			return apiErrors.ErrBadRequest().SetDetail("Invalid request body")
+		}
+	}
+
+	requiredPermission := utils.Ternary(req.Is4K, permissions.Request4KMovies, permissions.RequestMovies)
+	hasRequestPermission := user.IsAdmin
+	if !hasRequestPermission {
+		hasRequestPermission, err = rg.checkUserPermissionCreate(ctx.Context(), user.ID, requiredPermission)
+		if err != nil {
+			slog.Error("Failed to check permission", "error", err)
+			return apiErrors.ErrInternalServerError().SetDetail("Permission check failed")
+		}
+	}
+	if !hasRequestPermission {
+		if req.Is4K {
+			return apiErrors.ErrNo4KPermission().SetDetail("You need permission to request 4K movies")
+		}
+		return apiErrors.ErrNoRequestPermission().SetDetail("You need permission to request movies")
+	}
+
+	if req.Is4K {
+		if err := rg.check4KInstanceConfigured(ctx.Context(), "movie"); err != nil {
+			return err
+		}
+	}
+
+	// Auto-approval applies to each movie the same way as a single request
+	autoApprovalPermission := utils.Ternary(req.Is4K, permissions.RequestAutoApprove4KMovies, permissions.RequestAutoApproveMovies)
+	hasAutoApproval := user.IsAdmin
+	if !hasAutoApproval {
+		hasAutoApproval, err = rg.checkUserPermissionCreate(ctx.Context(), user.ID, autoApprovalPermission)
+		if err != nil {
+			slog.Error("Failed to check auto-approval permission", "error", err, "permission", autoApprovalPermission)
+			// Continue with normal flow if permission check fails
+		}
+	}
+
+	result, err := rg.requestProcessor.RequestCollection(ctx.Context(), structures.CollectionRequestInput{
+		CollectionID:    collectionID,
+		UserID:          user.ID,
+		Is4K:            req.Is4K,
+		WatchUnreleased: req.WatchUnreleased,
+		AutoApprove:     hasAutoApproval,
+	})
+	if err != nil {
+		return err
+	}
+
+	requested := 0
+	for _, item := range result.Items {
+		if item.Result != structures.CollectionItemRequested || item.RequestID == nil {
+			continue
+		}
+		requested++
+		if item.Status == "approved" {
+			go rg.processAutoApprovedRequestWithRecovery(*item.RequestID, item.Title)
+		}
+	}
+
+	slog.Info("Collection request created",
+		"collection_request_id", result.ID,
+		"collection_id", collectionID,
+		"user_id", user.ID,
+		"is_4k", req.Is4K,
+		"requested", requested,
+		"parts", len(result.Items),
+		"auto_approved", hasAutoApproval)
+
+	return ctx.JSON(result)
+}
@@ -56,9 +56,9 @@ func (rg *RouteGroup) CreateRequest(ctx *respond.Ctx) error {
 	// Check permissions
 	var requiredPermission string
 	if req.MediaType == "movie" {
-		requiredPermission = permissions.RequestMovies
+		requiredPermission = utils.Ternary(req.Is4K, permissions.Request4KMovies, permissions.RequestMovies)
 	} else if req.MediaType == "tv" {
-		requiredPermission = permissions.RequestSeries
+		requiredPermission = utils.Ternary(req.Is4K, permissions.Request4KSeries, permissions.RequestSeries)
 	} else {
 		return apiErrors.ErrInvalidMediaType().SetDetail("Media type '%s' is not supported", req.MediaType)
 	}
@@ -80,14 +80,74 @@ func (rg *RouteGroup) CreateRequest(ctx *respond.Ctx) error {
 		} else {
 			mediaTypeFriendly = "TV shows"
 		}
+		if req.Is4K {
+			return apiErrors.ErrNo4KPermission().SetDetail("You need permission to request 4K %s", mediaTypeFriendly)
+		}
 		return apiErrors.ErrNoRequestPermission().SetDetail("You need permission to request %s", mediaTypeFriendly)
 	}
 
+	// Episode selection and following only apply to TV shows
+	if len(req.Episodes) > 0 || req.FollowShow {
+		if req.MediaType != "tv" {
+			return apiErrors.ErrBadRequest().SetDetail("episodes and follow_show can only be set on TV requests")
+		}
+	}
+	if len(req.Episodes) > 0 {
+		if len(req.Seasons) > 0 {
+			return apiErrors.ErrBadRequest().SetDetail("Request either seasons or episodes, not both")
+		}
+		for season, episodes := range req.Episodes {
+			for _, episode := range episodes {
+				if season < 0 || episode < 1 {
+					return apiErrors.ErrBadRequest().SetDetail("Invalid episode S%02dE%02d", season, episode)
+				}
+			}
+		}
+		req.Episodes = req.Episodes.Normalize()
+		if len(req.Episodes) == 0 {
+			return apiErrors.ErrBadRequest().SetDetail("No episodes were selected")
+		}
+	}
+
+	// 4K requests are only accepted when there is a 4K instance to send them to
+	if req.Is4K {
+		if err := rg.check4KInstanceConfigured(ctx.Context(), req.MediaType); err != nil {
+			return err
+		}
+	}
+
 	// Check for duplicate requests with sophisticated season handling
-	if err := rg.checkForDuplicateRequest(ctx.Context(), user.ID, req.MediaType, req.TmdbID, req.Seasons); err != nil {
+	if err := rg.requestProcessor.CheckDuplicateRequest(ctx.Context(), user.ID, req.MediaType, req.TmdbID, req.Seasons, req.Episodes, req.Is4K); err != nil {
 		return err
 	}
 
+	// Advanced options need their own permission and must exist on the target instance
+	var overrides storedOverrides
+	if req.RequestOverrides.IsSet() {
+		hasAdvanced, err := rg.checkAdvancedPermission(ctx.Context(), user.ID, user.IsAdmin)
+		if err != nil {
+			slog.Error("Failed to check advanced request permission", "error", err)
+			return apiErrors.ErrInternalServerError().SetDetail("Permission check failed")
+		}
+		if !hasAdvanced {
+			return apiErrors.ErrForbidden().SetDetail("You don't have permission to set advanced request options")
+		}
+
+		routingUserID := user.ID
+		if req.OnBehalfOf != nil && *req.OnBehalfOf != "" {
+			routingUserID = *req.OnBehalfOf
+		}
+		input := structures.RoutingInput{MediaType: req.MediaType, TmdbID: req.TmdbID, Is4K: req.Is4K, UserID: routingUserID}
+		if err := rg.requestProcessor.ValidateOverrides(ctx.Context(), input, req.RequestOverrides); err != nil {
+			return err
+		}
+
+		overrides, err = toStoredOverrides(req.RequestOverrides)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Validate on_behalf_of user exists if provided
 	if req.OnBehalfOf != nil && *req.OnBehalfOf != "" {
 		// Check if user has permission to create requests on behalf of others
@@ -127,6 +187,12 @@ func (rg *RouteGroup) CreateRequest(ctx *respond.Ctx) error {
 		OnBehalfOf: sql.NullString{},
 		Seasons:   sql.NullString{},
 		SeasonStatuses: sql.NullString{},
+		Is4k:      req.Is4K,
+		FollowShow: req.FollowShow,
+		QualityProfileID: overrides.QualityProfileID,
+		RootFolderPath:   overrides.RootFolderPath,
+		Tags:             overrides.Tags,
+		SeriesType:       overrides.SeriesType,
 	}
 
 	if req.Notes != nil {
@@ -141,6 +207,13 @@ func (rg *RouteGroup) CreateRequest(ctx *respond.Ctx) error {
 		params.OnBehalfOf = sql.NullString{String: *req.OnBehalfOf, Valid: true}
 	}
 
+	// Requests start at the priority of the tier of the user they are for
+	if req.OnBehalfOf != nil && *req.OnBehalfOf != "" {
+		params.Priority = rg.requestProcessor.RequestPriority(ctx.Context(), *req.OnBehalfOf)
+	} else {
+		params.Priority = rg.requestProcessor.RequestPriority(ctx.Context(), user.ID)
+	}
+
 	// Handle seasons for TV shows
 	if req.MediaType == "tv" && len(req.Seasons) > 0 {
 		// Check existing availability before creating request
@@ -203,14 +276,40 @@ func (rg *RouteGroup) CreateRequest(ctx *respond.Ctx) error {
 		params.SeasonStatuses = sql.NullString{String: string(statusJSON), Valid: true}
 	}
 
-	// Check if user has auto-approval permission for this specific media type
-	// TODO: In the future, add support for 4K detection from frontend
-	// For now, treat all requests as regular (non-4K) content
+	// Handle individual episodes for TV shows. Their season statuses track the
+	// requested episodes rather than whole seasons.
+	if req.MediaType == "tv" && len(req.Episodes) > 0 {
+		episodesJSON, err := json.Marshal(req.Episodes)
+		if err != nil {
+			slog.Error("Failed to marshal episodes", "error", err)
+			return apiErrors.ErrInternalServerError().SetDetail("Failed to process episodes")
+		}
+		params.Episodes = sql.NullString{String: string(episodesJSON), Valid: true}
+
+		seasonStatuses := make(map[string]structures.SeasonInfo, len(req.Episodes))
+		for season, episodes := range req.Episodes {
+			seasonStatuses[fmt.Sprintf("%d", season)] = structures.SeasonInfo{
+				Status:            "pending",
+				Episodes:          fmt.Sprintf("0/%d", len(episodes)),
+				TotalEpisodes:     len(episodes),
+				RequestedEpisodes: episodes,
+			}
+		}
+
+		statusJSON, err := json.Marshal(seasonStatuses)
+		if err != nil {
+			slog.Error("Failed to marshal season statuses", "error", err)
+			return apiErrors.ErrInternalServerError().SetDetail("Failed to process season statuses")
+		}
+		params.SeasonStatuses = sql.NullString{String: string(statusJSON), Valid: true}
+	}
+
+	// Check if user has auto-approval permission for this specific media type and quality
 	var autoApprovalPermission string
 	if req.MediaType == "movie" {
-		autoApprovalPermission = permissions.RequestAutoApproveMovies
+		autoApprovalPermission = utils.Ternary(req.Is4K, permissions.RequestAutoApprove4KMovies, permissions.RequestAutoApproveMovies)
 	} else if req.MediaType == "tv" {
-		autoApprovalPermission = permissions.RequestAutoApproveSeries
+		autoApprovalPermission = utils.Ternary(req.Is4K, permissions.RequestAutoApprove4KSeries, permissions.RequestAutoApproveSeries)
 	}
 
 	hasAutoApproval := user.IsAdmin
@@ -226,7 +325,7 @@ func (rg *RouteGroup) CreateRequest(ctx *respond.Ctx) error {
 	// Set status based on auto-approval permission
 	if hasAutoApproval {
 		params.Status = "approved"
-		slog.Info("Request auto-approved", "user_id", user.ID, "media_type", req.MediaType, "tmdb_id", req.TmdbID, "permission", autoApprovalPermission)
+		slog.Info("Request auto-approved", "user_id", user.ID, "media_type", req.MediaType, "tmdb_id", req.TmdbID, "is_4k", req.Is4K, "permission", autoApprovalPermission)
 	} else {
 		params.Status = "pending"
 	}
@@ -247,6 +346,7 @@ func (rg *RouteGroup) CreateRequest(ctx *respond.Ctx) error {
 		"tmdb_id", req.TmdbID,
 		"title", req.Title,
 		"status", params.Status,
+		"is_4k", request.Is4k,
 		"auto_approved", hasAutoApproval)
 
 	// If request was auto-approved, automatically process it with proper error handling
@@ -273,6 +373,12 @@ func (rg *RouteGroup) CreateRequest(ctx *respond.Ctx) error {
 		Status:    request.Status,
 		CreatedAt: request.CreatedAt.Format("2006-01-02T15:04:05Z"),
 		UpdatedAt: request.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+		Is4K:      request.Is4k,
+		Episodes:        requestEpisodes(request),
+		FollowShow:      request.FollowShow,
+		ParentRequestID: parentRequestID(request),
+		Priority:        request.Priority,
+		RequestOverrides: requestOverrides(request),
 	}
 	
 	if request.TmdbID.Valid {
@@ -285,6 +391,9 @@ func (rg *RouteGroup) CreateRequest(ctx *respond.Ctx) error {
 	if request.Notes.Valid {
 		apiRequest.Notes = request.Notes.String
 	}
+	if request.DenyReason.Valid {
+		apiRequest.DenyReason = request.DenyReason.String
+	}
 	if request.FulfilledAt.Valid {
 		fulfilledAt := request.FulfilledAt.Time.Format("2006-01-02T15:04:05Z")
 		apiRequest.FulfilledAt = fulfilledAt
@@ -314,126 +423,27 @@ func (rg *RouteGroup) CreateRequest(ctx *respond.Ctx) error {
 	return ctx.JSON(apiRequest)
 }
 
-// checkForDuplicateRequest checks for duplicate requests with sophisticated season handling
-func (rg *RouteGroup) checkForDuplicateRequest(ctx context.Context, userID, mediaType string, tmdbID int64, requestedSeasons []int) error {
-	var seasonsJSON sql.NullString
-	if len(requestedSeasons) > 0 {
-		seasonsBytes, err := json.Marshal(requestedSeasons)
-		if err != nil {
-			slog.Error("Failed to marshal seasons", "error", err)
-			return apiErrors.ErrInternalServerError().SetDetail("Failed to process season data")
-		}
-		seasonsJSON = sql.NullString{String: string(seasonsBytes), Valid: true}
-	}
-
-	// For movies, check exact duplicate (including null seasons)
-	if mediaType == "movie" {
-		existingRequest, err := rg.gctx.Crate().Sqlite.Query().CheckExistingRequest(ctx, repository.CheckExistingRequestParams{
-			MediaType: mediaType,
-			TmdbID:    sql.NullInt64{Int64: tmdbID, Valid: true},
-			UserID:    userID,
-			Seasons:   seasonsJSON,
-		})
-
-		if err == nil {
-			// Exact duplicate found
-			return apiErrors.ErrConflict().SetDetail(fmt.Sprintf("You already have a %s request for this movie", existingRequest.Status))
-		} else if err != sql.ErrNoRows {
-			// Database error
-			slog.Error("Failed to check existing movie request", "error", err)
-			return apiErrors.ErrInternalServerError().SetDetail("Failed to check existing request")
-		}
-		return nil // No duplicate found
-	}
-
-	// For TV shows, we need more sophisticated checking
-	if mediaType == "tv" {
-		// First, check for exact duplicate (same seasons)
-		existingRequest, err := rg.gctx.Crate().Sqlite.Query().CheckExistingRequest(ctx, repository.CheckExistingRequestParams{
-			MediaType: mediaType,
-			TmdbID:    sql.NullInt64{Int64: tmdbID, Valid: true},
-			UserID:    userID,
-			Seasons:   seasonsJSON,
-		})
-
-		if err == nil {
-			// Exact duplicate found
-			return apiErrors.ErrConflict().SetDetail(fmt.Sprintf("You already have a %s request for these exact seasons", existingRequest.Status))
-		} else if err != sql.ErrNoRows {
-			// Database error
-			slog.Error("Failed to check existing TV request", "error", err)
-			return apiErrors.ErrInternalServerError().SetDetail("Failed to check existing request")
-		}
-
-		// Check for conflicting requests (overlapping seasons or whole series requests)
-		existingRequests, err := rg.gctx.Crate().Sqlite.Query().CheckExistingRequestAnySeasons(ctx, repository.CheckExistingRequestAnySeasonsParams{
-			MediaType: mediaType,
-			TmdbID:    sql.NullInt64{Int64: tmdbID, Valid: true},
-			UserID:    userID,
-		})
-
-		if err != nil && err != sql.ErrNoRows {
-			slog.Error("Failed to check existing TV requests", "error", err)
-			return apiErrors.ErrInternalServerError().SetDetail("Failed to check existing requests")
-		}
+// check4KInstanceConfigured rejects 4K requests when no 4K Radarr/Sonarr
+// instance exists to fulfil them
+func (rg *RouteGroup) check4KInstanceConfigured(ctx context.Context, mediaType string) error {
+	provider := utils.Ternary(mediaType == "movie", structures.ProviderRadarr, structures.ProviderSonarr)
 
-		// Analyze conflicts
-		for _, existing := range existingRequests {
-			conflict, message := rg.checkSeasonConflict(requestedSeasons, existing.Seasons.String, existing.Status)
-			if conflict {
-				return apiErrors.ErrConflict().SetDetail(message)
-			}
-		}
-
-		return nil // No conflicts found
-	}
-
-	return nil
-}
-
-// checkSeasonConflict checks if the requested seasons conflict with an existing request
-func (rg *RouteGroup) checkSeasonConflict(requestedSeasons []int, existingSeasonsJSON string, existingStatus string) (bool, string) {
-	// If no seasons requested, this is a whole series request
-	if len(requestedSeasons) == 0 {
-		if existingSeasonsJSON == "" {
-			// Both are whole series requests
-			return true, fmt.Sprintf("You already have a %s request for the entire series", existingStatus)
-		}
-		// Requesting whole series but existing is season-specific
-		return true, fmt.Sprintf("You already have a %s request for specific seasons. Cannot request entire series", existingStatus)
-	}
-
-	// If existing request has no seasons, it's a whole series request
-	if existingSeasonsJSON == "" {
-		return true, fmt.Sprintf("You already have a %s request for the entire series", existingStatus)
-	}
-
-	// Both are season-specific, check for overlaps
-	var existingSeasons []int
-	if err := json.Unmarshal([]byte(existingSeasonsJSON), &existingSeasons); err != nil {
-		slog.Error("Failed to unmarshal existing seasons", "error", err)
-		// If we can't parse, assume conflict to be safe
-		return true, "Cannot determine season conflict due to data error"
-	}
-
-	// Check for overlapping seasons
-	requestedSet := make(map[int]bool)
-	for _, season := range requestedSeasons {
-		requestedSet[season] = true
+	instances, err := rg.gctx.Crate().Sqlite.Query().GetArrServiceByType(ctx, provider.String())
+	if err != nil {
+		slog.Error("Failed to get arr instances", "error", err, "type", provider)
+		return apiErrors.ErrInternalServerError().SetDetail("Failed to check 4K availability")
 	}
 
-	var overlapping []int
-	for _, season := range existingSeasons {
-		if requestedSet[season] {
-			overlapping = append(overlapping, season)
+	for _, instance := range instances {
+		if instance.Is4k {
+			return nil
 		}
 	}
 
-	if len(overlapping) > 0 {
-		return true, fmt.Sprintf("You already have a %s request for season(s) %v", existingStatus, overlapping)
+	if provider == structures.ProviderRadarr {
+		return apiErrors.ErrNo4KRadarrInstances()
 	}
-
-	return false, ""
+	return apiErrors.ErrNo4KSonarrInstances()
 }
 
 // processAutoApprovedRequestWithRecovery handles auto-approved request processing with proper error handling
@@ -0,0 +1,82 @@
+package requests
+
+import (
+	"log/slog"
+
+	"github.com/mahcks/serra/internal/db/repository"
+	"github.com/mahcks/serra/internal/rest/v1/respond"
+	apiErrors "github.com/mahcks/serra/pkg/api_errors"
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+// CreateRoutingRule adds a routing rule
+func (rg *RouteGroup) CreateRoutingRule(ctx *respond.Ctx) error {
+	user := ctx.ParseClaims()
+	if user == nil || user.ID == "" {
+		return apiErrors.ErrUnauthorized()
+	}
+
+	var req structures.RoutingRuleRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return apiErrors.ErrBadRequest().SetDetail("Invalid request body")
+	}
+
+	params, err := rg.validateRoutingRule(ctx.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	rule, err := rg.gctx.Crate().Sqlite.Query().CreateRoutingRule(ctx.Context(), repository.CreateRoutingRuleParams{
+		Name:              req.Name,
+		MediaType:         req.MediaType,
+		Priority:          req.Priority,
+		Enabled:           req.Enabled == nil || *req.Enabled,
+		IsDefault:         req.IsDefault,
+		Genres:            params.Genres,
+		OriginalLanguages: params.OriginalLanguages,
+		Keywords:          params.Keywords,
+		Certifications:    params.Certifications,
+		UserIds:           params.UserIds,
+		UserTypes:         params.UserTypes,
+		ArrServiceID:      req.ArrServiceID,
+		RootFolderPath:    params.RootFolderPath,
+		QualityProfileID:  params.QualityProfileID,
+	})
+	if err != nil {
+		slog.Error("Failed to create routing rule", "error", err)
+		return apiErrors.ErrInternalServerError().SetDetail("Failed to create routing rule")
+	}
+
+	slog.Info("Routing rule created", "rule_id", rule.ID, "name", rule.Name, "user_id", user.ID)
+	return ctx.JSON(toRoutingRule(rule))
+}
+
+// PreviewRouting explains which routing rule a request for a TMDB id would
+// match, without creating the request
+func (rg *RouteGroup) PreviewRouting(ctx *respond.Ctx) error {
+	user := ctx.ParseClaims()
+	if user == nil || user.ID == "" {
+		return apiErrors.ErrUnauthorized()
+	}
+
+	var req structures.RoutingInput
+	if err := ctx.BodyParser(&req); err != nil {
+		return apiErrors.ErrBadRequest().SetDetail("Invalid request body")
+	}
+	if req.MediaType != "movie" && req.MediaType != "tv" {
+		return apiErrors.ErrInvalidMediaType().SetDetail("media_type must be 'movie' or 'tv'")
+	}
+	if req.TmdbID <= 0 {
+		return apiErrors.ErrBadRequest().SetDetail("tmdb_id is required")
+	}
+	if req.UserID == "" {
+		req.UserID = user.ID
+	}
+
+	decision, err := rg.requestProcessor.PreviewRouting(ctx.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(decision)
+}
@@ -0,0 +1,164 @@
+package requests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	jwt "github.com/golang-jwt/jwt/v5"
+	"github.com/mahcks/serra/config"
+	"github.com/mahcks/serra/internal/db/dbtest"
+	"github.com/mahcks/serra/internal/global"
+	"github.com/mahcks/serra/internal/integrations"
+	"github.com/mahcks/serra/internal/integrations/tmdb"
+	"github.com/mahcks/serra/internal/rest/v1/respond"
+	"github.com/mahcks/serra/internal/services/auth"
+	"github.com/mahcks/serra/internal/services/request_processor"
+	apiErrors "github.com/mahcks/serra/pkg/api_errors"
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+// classificationTMDB answers classification lookups from a fixed set of titles
+type classificationTMDB struct {
+	tmdb.Service
+	media map[string]structures.TMDBMediaClassification
+}
+
+func (c classificationTMDB) GetMediaClassification(mediaType, id string) (structures.TMDBMediaClassification, error) {
+	return c.media[id], nil
+}
+
+func TestPreviewRouting(t *testing.T) {
+	service := dbtest.Service(t)
+	statements := []string{
+		`INSERT INTO users (id, username, user_type) VALUES ('alice', 'alice', 'media_server'), ('bob', 'bob', 'local')`,
+		`INSERT INTO arr_services (id, type, name, base_url, api_key, quality_profile, root_folder_path, minimum_availability, is_4k) VALUES
+			('main', 'radarr', 'Main', 'http://main', 'key', '1', '/movies', 'released', FALSE),
+			('anime', 'radarr', 'Anime', 'http://anime', 'key', '1', '/anime', 'released', FALSE),
+			('guests', 'radarr', 'Guests', 'http://guests', 'key', '1', '/guests', 'released', FALSE)`,
+		`INSERT INTO routing_rules (name, media_type, priority, user_types, genres, arr_service_id, is_default) VALUES
+			('Local accounts', 'movie', 1, '["local"]', NULL, 'guests', FALSE),
+			('Animation', 'movie', 2, NULL, '[16]', 'anime', FALSE),
+			('Everything else', 'movie', 3, NULL, NULL, 'main', TRUE)`,
+	}
+	for _, statement := range statements {
+		if _, err := service.DB().Exec(statement); err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+
+	gctx := global.New(context.Background(), &config.Bootstrap{}, "test", "")
+	gctx.Crate().Sqlite = service
+	rg := &RouteGroup{
+		gctx: gctx,
+		requestProcessor: request_processor.New(service.Query(), nil, nil, &integrations.Integration{
+			TMDB: classificationTMDB{media: map[string]structures.TMDBMediaClassification{
+				"100": {ID: 100, Genres: []structures.Genre{{ID: 16, Name: "Animation"}}},
+				"200": {ID: 200, Genres: []structures.Genre{{ID: 18, Name: "Drama"}}},
+			}},
+		}),
+	}
+
+	app := fiber.New(fiber.Config{ErrorHandler: func(c *fiber.Ctx, err error) error {
+		var apiErr apiErrors.APIError
+		if errors.As(err, &apiErr) {
+			return c.SendStatus(apiErr.ExpectedHTTPStatus())
+		}
+		return c.SendStatus(fiber.StatusInternalServerError)
+	}})
+	app.Post("/requests/routing/preview", func(c *fiber.Ctx) error {
+		// Stands in for the JWT middleware
+		c.Locals("_serrauser", &jwt.Token{Claims: &auth.JWTClaimUser{UserID: c.Query("user"), IsAdmin: true}})
+		return rg.PreviewRouting(&respond.Ctx{Ctx: c})
+	})
+
+	preview := func(t *testing.T, caller string, input structures.RoutingInput) (int, structures.RoutingDecision) {
+		t.Helper()
+		body, err := json.Marshal(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req := httptest.NewRequest("POST", "/requests/routing/preview?user="+caller, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("preview: %v", err)
+		}
+		defer resp.Body.Close()
+
+		var decision structures.RoutingDecision
+		if resp.StatusCode == fiber.StatusOK {
+			if err := json.NewDecoder(resp.Body).Decode(&decision); err != nil {
+				t.Fatalf("decode decision: %v", err)
+			}
+		}
+		return resp.StatusCode, decision
+	}
+
+	tests := []struct {
+		name      string
+		caller    string
+		input     structures.RoutingInput
+		instance  string
+		rule      string
+		isDefault bool
+	}{
+		{
+			name:     "local accounts match the user type rule",
+			caller:   "alice",
+			input:    structures.RoutingInput{MediaType: "movie", TmdbID: 100, UserID: "bob"},
+			instance: "guests",
+			rule:     "Local accounts",
+		},
+		{
+			name:     "genre rule for media server accounts",
+			caller:   "alice",
+			input:    structures.RoutingInput{MediaType: "movie", TmdbID: 100},
+			instance: "anime",
+			rule:     "Animation",
+		},
+		{
+			name:      "default rule when nothing matches",
+			caller:    "alice",
+			input:     structures.RoutingInput{MediaType: "movie", TmdbID: 200},
+			instance:  "main",
+			rule:      "Everything else",
+			isDefault: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, decision := preview(t, tt.caller, tt.input)
+			if status != fiber.StatusOK {
+				t.Fatalf("status = %d", status)
+			}
+			if decision.ArrServiceID != tt.instance || decision.RuleName != tt.rule || decision.IsDefault != tt.isDefault {
+				t.Errorf("routed to %s by %q (default %v), want %s by %q (default %v)",
+					decision.ArrServiceID, decision.RuleName, decision.IsDefault, tt.instance, tt.rule, tt.isDefault)
+			}
+			if len(decision.Evaluations) == 0 {
+				t.Error("preview did not explain the rules it checked")
+			}
+		})
+	}
+
+	// The explanation names the account type the user type rule saw
+	_, decision := preview(t, "alice", structures.RoutingInput{MediaType: "movie", TmdbID: 200})
+	want := "user type: user is a media_server account, rule wants [local]"
+	if reasons := decision.Evaluations[0].Reasons; reasons[len(reasons)-1] != want {
+		t.Errorf("reasons = %q, want the last to be %q", reasons, want)
+	}
+
+	for _, input := range []structures.RoutingInput{
+		{MediaType: "music", TmdbID: 100},
+		{MediaType: "movie"},
+	} {
+		if status, _ := preview(t, "alice", input); status != fiber.StatusBadRequest {
+			t.Errorf("%+v: status = %d, want 400", input, status)
+		}
+	}
+}
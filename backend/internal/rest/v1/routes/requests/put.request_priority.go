@@ -0,0 +1,69 @@
+package requests
+
+import (
+	"database/sql"
+	"log/slog"
+	"strconv"
+
+	"github.com/mahcks/serra/internal/db/repository"
+	"github.com/mahcks/serra/internal/rest/v1/respond"
+	apiErrors "github.com/mahcks/serra/pkg/api_errors"
+	"github.com/mahcks/serra/pkg/permissions"
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+// UpdateRequestPriority lets approvers move a request up or down the pending queue
+func (rg *RouteGroup) UpdateRequestPriority(ctx *respond.Ctx) error {
+	user := ctx.ParseClaims()
+	if user == nil || user.ID == "" {
+		return apiErrors.ErrUnauthorized()
+	}
+
+	requestID, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return apiErrors.ErrBadRequest().SetDetail("Invalid request ID")
+	}
+
+	var req structures.UpdateRequestPriorityRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return apiErrors.ErrBadRequest().SetDetail("Invalid request body")
+	}
+	if !validRequestPriority(req.Priority) {
+		return apiErrors.ErrBadRequest().SetDetail("priority must be one of low, normal, high, urgent")
+	}
+
+	if !user.IsAdmin {
+		canApprove, err := rg.checkUserPermission(ctx.Context(), user.ID, permissions.RequestsApprove)
+		if err != nil {
+			slog.Error("Failed to check approve permission", "error", err)
+			return apiErrors.ErrInternalServerError().SetDetail("Permission check failed")
+		}
+		canManage, err := rg.checkUserPermission(ctx.Context(), user.ID, permissions.RequestsManage)
+		if err != nil {
+			slog.Error("Failed to check manage permission", "error", err)
+			return apiErrors.ErrInternalServerError().SetDetail("Permission check failed")
+		}
+		if !canApprove && !canManage {
+			return apiErrors.ErrForbidden().SetDetail("You don't have permission to prioritize requests")
+		}
+	}
+
+	updatedRequest, err := rg.gctx.Crate().Sqlite.Query().UpdateRequestPriority(ctx.Context(), repository.UpdateRequestPriorityParams{
+		Priority: req.Priority,
+		ID:       requestID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return apiErrors.ErrNotFound().SetDetail("Request not found")
+		}
+		slog.Error("Failed to update request priority", "error", err, "request_id", requestID)
+		return apiErrors.ErrInternalServerError().SetDetail("Failed to update request")
+	}
+
+	slog.Info("Request priority updated",
+		"request_id", requestID,
+		"priority", req.Priority,
+		"updated_by", user.ID)
+
+	return ctx.JSON(updatedRequest)
+}
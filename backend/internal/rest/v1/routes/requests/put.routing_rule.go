@@ -0,0 +1,63 @@
+package requests
+
+import (
+	"database/sql"
+	"log/slog"
+	"strconv"
+
+	"github.com/mahcks/serra/internal/db/repository"
+	"github.com/mahcks/serra/internal/rest/v1/respond"
+	apiErrors "github.com/mahcks/serra/pkg/api_errors"
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+// UpdateRoutingRule replaces a routing rule
+func (rg *RouteGroup) UpdateRoutingRule(ctx *respond.Ctx) error {
+	user := ctx.ParseClaims()
+	if user == nil || user.ID == "" {
+		return apiErrors.ErrUnauthorized()
+	}
+
+	ruleID, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return apiErrors.ErrBadRequest().SetDetail("Invalid routing rule ID")
+	}
+
+	var req structures.RoutingRuleRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return apiErrors.ErrBadRequest().SetDetail("Invalid request body")
+	}
+
+	params, err := rg.validateRoutingRule(ctx.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	rule, err := rg.gctx.Crate().Sqlite.Query().UpdateRoutingRule(ctx.Context(), repository.UpdateRoutingRuleParams{
+		Name:              req.Name,
+		MediaType:         req.MediaType,
+		Priority:          req.Priority,
+		Enabled:           req.Enabled == nil || *req.Enabled,
+		IsDefault:         req.IsDefault,
+		Genres:            params.Genres,
+		OriginalLanguages: params.OriginalLanguages,
+		Keywords:          params.Keywords,
+		Certifications:    params.Certifications,
+		UserIds:           params.UserIds,
+		UserTypes:         params.UserTypes,
+		ArrServiceID:      req.ArrServiceID,
+		RootFolderPath:    params.RootFolderPath,
+		QualityProfileID:  params.QualityProfileID,
+		ID:                ruleID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return apiErrors.ErrNotFound().SetDetail("Routing rule not found")
+		}
+		slog.Error("Failed to update routing rule", "error", err, "rule_id", ruleID)
+		return apiErrors.ErrInternalServerError().SetDetail("Failed to update routing rule")
+	}
+
+	slog.Info("Routing rule updated", "rule_id", rule.ID, "name", rule.Name, "user_id", user.ID)
+	return ctx.JSON(toRoutingRule(rule))
+}
@@ -77,6 +77,47 @@ func (rg *RouteGroup) UpdateRequest(ctx *respond.Ctx) error {
 		return apiErrors.ErrForbidden().SetDetail("You don't have permission to update this request")
 	}
 
+	// Approvers may adjust advanced options while approving; anything they leave
+	// unset keeps the value chosen by the requester
+	if req.RequestOverrides.IsSet() || req.Tags != nil {
+		if req.Status != "approved" {
+			return apiErrors.ErrBadRequest().SetDetail("Advanced request options can only be set when approving a request")
+		}
+
+		overrides := mergeOverrides(requestOverrides(existingRequest), req.RequestOverrides)
+
+		routingUserID := existingRequest.UserID
+		if existingRequest.OnBehalfOf.Valid && existingRequest.OnBehalfOf.String != "" {
+			routingUserID = existingRequest.OnBehalfOf.String
+		}
+		input := structures.RoutingInput{
+			MediaType: existingRequest.MediaType,
+			TmdbID:    existingRequest.TmdbID.Int64,
+			Is4K:      existingRequest.Is4k,
+			UserID:    routingUserID,
+		}
+		if err := rg.requestProcessor.ValidateOverrides(ctx.Context(), input, overrides); err != nil {
+			return err
+		}
+
+		stored, err := toStoredOverrides(overrides)
+		if err != nil {
+			return err
+		}
+
+		_, err = rg.gctx.Crate().Sqlite.Query().UpdateRequestOverrides(ctx.Context(), repository.UpdateRequestOverridesParams{
+			QualityProfileID: stored.QualityProfileID,
+			RootFolderPath:   stored.RootFolderPath,
+			Tags:             stored.Tags,
+			SeriesType:       stored.SeriesType,
+			ID:               requestID,
+		})
+		if err != nil {
+			slog.Error("Failed to update request overrides", "error", err, "request_id", requestID)
+			return apiErrors.ErrInternalServerError().SetDetail("Failed to update request")
+		}
+	}
+
 	// Special handling for fulfilled status
 	if req.Status == "fulfilled" {
 		updatedRequest, err := rg.gctx.Crate().Sqlite.Query().FulfillRequest(ctx.Context(), requestID)
@@ -132,6 +173,10 @@ func (rg *RouteGroup) UpdateRequest(ctx *respond.Ctx) error {
 		return apiErrors.ErrInternalServerError().SetDetail("Failed to update request")
 	}
 
+	if req.Status == "approved" || req.Status == "denied" {
+		recordDecision(ctx.Context(), rg.gctx.Crate().Sqlite.Query(), existingRequest, req.Status, user.ID)
+	}
+
 	slog.Info("Request status updated", 
 		"request_id", requestID, 
 		"new_status", req.Status, 
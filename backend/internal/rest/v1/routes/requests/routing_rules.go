@@ -0,0 +1,136 @@
+package requests
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log/slog"
+
+	"github.com/mahcks/serra/internal/db/repository"
+	apiErrors "github.com/mahcks/serra/pkg/api_errors"
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+// routingRuleParams holds a validated routing rule in its database representation
+type routingRuleParams struct {
+	Genres            sql.NullString
+	OriginalLanguages sql.NullString
+	Keywords          sql.NullString
+	Certifications    sql.NullString
+	UserIds           sql.NullString
+	UserTypes         sql.NullString
+	RootFolderPath    sql.NullString
+	QualityProfileID  sql.NullInt64
+}
+
+// validateRoutingRule checks that the target instance exists and serves the
+// rule's media type, then converts the conditions into database columns
+func (rg *RouteGroup) validateRoutingRule(ctx context.Context, req structures.RoutingRuleRequest) (routingRuleParams, error) {
+	var params routingRuleParams
+
+	if req.Name == "" {
+		return params, apiErrors.ErrBadRequest().SetDetail("Rule name is required")
+	}
+	if req.MediaType != "movie" && req.MediaType != "tv" {
+		return params, apiErrors.ErrInvalidMediaType().SetDetail("media_type must be 'movie' or 'tv'")
+	}
+
+	instance, err := rg.gctx.Crate().Sqlite.Query().GetArrServiceByID(ctx, req.ArrServiceID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return params, apiErrors.ErrBadRequest().SetDetail("Instance '%s' does not exist", req.ArrServiceID)
+		}
+		slog.Error("Failed to get arr service", "error", err, "id", req.ArrServiceID)
+		return params, apiErrors.ErrInternalServerError().SetDetail("Failed to validate routing rule")
+	}
+
+	expected := structures.ProviderSonarr
+	if req.MediaType == "movie" {
+		expected = structures.ProviderRadarr
+	}
+	if instance.Type != expected.String() {
+		return params, apiErrors.ErrBadRequest().SetDetail("%s rules must target a %s instance", req.MediaType, expected)
+	}
+
+	for _, userType := range req.Conditions.UserTypes {
+		if userType != "media_server" && userType != "local" {
+			return params, apiErrors.ErrBadRequest().SetDetail("Invalid user type '%s'", userType)
+		}
+	}
+
+	conditions := []struct {
+		column *sql.NullString
+		values interface{}
+		count  int
+	}{
+		{&params.Genres, req.Conditions.Genres, len(req.Conditions.Genres)},
+		{&params.OriginalLanguages, req.Conditions.OriginalLanguages, len(req.Conditions.OriginalLanguages)},
+		{&params.Keywords, req.Conditions.Keywords, len(req.Conditions.Keywords)},
+		{&params.Certifications, req.Conditions.Certifications, len(req.Conditions.Certifications)},
+		{&params.UserIds, req.Conditions.UserIDs, len(req.Conditions.UserIDs)},
+		{&params.UserTypes, req.Conditions.UserTypes, len(req.Conditions.UserTypes)},
+	}
+	for _, condition := range conditions {
+		if condition.count == 0 {
+			continue
+		}
+		value, err := json.Marshal(condition.values)
+		if err != nil {
+			return params, apiErrors.ErrInternalServerError().SetDetail("Failed to process routing conditions")
+		}
+		*condition.column = sql.NullString{String: string(value), Valid: true}
+	}
+
+	if req.RootFolderPath != nil && *req.RootFolderPath != "" {
+		params.RootFolderPath = sql.NullString{String: *req.RootFolderPath, Valid: true}
+	}
+	if req.QualityProfileID != nil {
+		params.QualityProfileID = sql.NullInt64{Int64: int64(*req.QualityProfileID), Valid: true}
+	}
+
+	return params, nil
+}
+
+// toRoutingRule converts a stored routing rule for the API
+func toRoutingRule(rule repository.RoutingRule) structures.RoutingRule {
+	apiRule := structures.RoutingRule{
+		ID:           rule.ID,
+		Name:         rule.Name,
+		MediaType:    rule.MediaType,
+		Priority:     rule.Priority,
+		Enabled:      rule.Enabled,
+		IsDefault:    rule.IsDefault,
+		ArrServiceID: rule.ArrServiceID,
+	}
+
+	unmarshal := func(value sql.NullString, out interface{}) {
+		if value.Valid && value.String != "" {
+			if err := json.Unmarshal([]byte(value.String), out); err != nil {
+				slog.Warn("Invalid routing rule condition", "rule_id", rule.ID, "value", value.String, "error", err)
+			}
+		}
+	}
+	unmarshal(rule.Genres, &apiRule.Conditions.Genres)
+	unmarshal(rule.OriginalLanguages, &apiRule.Conditions.OriginalLanguages)
+	unmarshal(rule.Keywords, &apiRule.Conditions.Keywords)
+	unmarshal(rule.Certifications, &apiRule.Conditions.Certifications)
+	unmarshal(rule.UserIds, &apiRule.Conditions.UserIDs)
+	unmarshal(rule.UserTypes, &apiRule.Conditions.UserTypes)
+
+	if rule.RootFolderPath.Valid {
+		rootFolderPath := rule.RootFolderPath.String
+		apiRule.RootFolderPath = &rootFolderPath
+	}
+	if rule.QualityProfileID.Valid {
+		qualityProfileID := int(rule.QualityProfileID.Int64)
+		apiRule.QualityProfileID = &qualityProfileID
+	}
+	if rule.CreatedAt.Valid {
+		apiRule.CreatedAt = rule.CreatedAt.Time.Format("2006-01-02T15:04:05Z")
+	}
+	if rule.UpdatedAt.Valid {
+		apiRule.UpdatedAt = rule.UpdatedAt.Time.Format("2006-01-02T15:04:05Z")
+	}
+
+	return apiRule
+}
@@ -14,7 +14,7 @@ type SettingsResponse struct {
 	JellystatPort              string `json:"jellystat_port,omitempty"`
 	JellystatUseSSL            bool   `json:"jellystat_use_ssl"`
 	JellystatURL               string `json:"jellystat_url,omitempty"`
-	JellystatAPIKey            string `json:"jellystat_api_key,omitempty"`
+	JellystatAPIKeySet         bool   `json:"jellystat_api_key_set"`
 	EnableMediaServerAuth      bool   `json:"enable_media_server_auth"`
 	EnableLocalAuth            bool   `json:"enable_local_auth"`
 	EnableNewMediaServerAuth   bool   `json:"enable_new_media_server_auth"`
@@ -66,7 +66,7 @@ func (rg *RouteGroup) GetSettings(ctx *respond.Ctx) error {
 		JellystatPort:            jellystatPort,
 		JellystatUseSSL:          jellystatUseSSL == "true",
 		JellystatURL:             jellystatURL,
-		JellystatAPIKey:          jellystatAPIKey,
+		JellystatAPIKeySet:       jellystatAPIKey != "",
 		EnableMediaServerAuth:    enableMediaServerAuth == "true",
 		EnableLocalAuth:          enableLocalAuth == "true",
 		EnableNewMediaServerAuth: enableNewMediaServerAuth == "true",
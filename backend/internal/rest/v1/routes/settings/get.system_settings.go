@@ -3,6 +3,7 @@ package settings
 import (
 	"strconv"
 	
+	"github.com/mahcks/serra/internal/jobs"
 	"github.com/mahcks/serra/internal/rest/v1/respond"
 	apiErrors "github.com/mahcks/serra/pkg/api_errors"
 	"github.com/mahcks/serra/pkg/permissions"
@@ -24,10 +25,11 @@ type SystemSettingsResponse struct {
 	JellystatPort    string `json:"jellystat_port,omitempty"`
 	JellystatUseSSL  bool   `json:"jellystat_use_ssl"`
 	JellystatURL     string `json:"jellystat_url,omitempty"`
-	JellystatAPIKey  string `json:"jellystat_api_key,omitempty"`
+	// Secrets are never returned, only whether they are configured
+	JellystatAPIKeySet bool `json:"jellystat_api_key_set"`
 	
 	// TMDB settings
-	TMDBAPIKey string `json:"tmdb_api_key,omitempty"`
+	TMDBAPIKeySet bool `json:"tmdb_api_key_set"`
 	
 	// Download visibility
 	DownloadVisibility string `json:"download_visibility"`
@@ -35,7 +37,25 @@ type SystemSettingsResponse struct {
 	// Request limits
 	GlobalMovieRequestLimit  int `json:"global_movie_request_limit"`
 	GlobalSeriesRequestLimit int `json:"global_series_request_limit"`
+
+	// Pending requests past this many hours are escalated to approvers (0 = no SLA)
+	RequestSLAHours int `json:"request_sla_hours"`
 	
+	// Job history
+	JobRunRetentionDays int                                           `json:"job_run_retention_days"`
+	JobSchedules        map[structures.Job]structures.JobScheduleOverride `json:"job_schedules"`
+
+	// Finished downloads are kept in the history this many days (0 = forever)
+	DownloadHistoryRetentionDays int `json:"download_history_retention_days"`
+
+	// When downloads count as stalled and whether they are remediated
+	DownloadStallPolicy structures.DownloadStallPolicy `json:"download_stall_policy"`
+
+	// Download client bandwidth
+	BandwidthSchedule structures.BandwidthSchedule `json:"bandwidth_schedule"`
+
+	// Database backups
+	BackupRetentionCount int `json:"backup_retention_count"`
 }
 
 func (rg *RouteGroup) GetSystemSettings(ctx *respond.Ctx) error {
@@ -88,6 +108,12 @@ func (rg *RouteGroup) GetSystemSettings(ctx *respond.Ctx) error {
 	globalMovieRequestLimit, _ := rg.gctx.Crate().Sqlite.Query().GetSetting(ctx.Context(), structures.SettingGlobalMovieRequestLimit.String())
 	globalSeriesRequestLimit, _ := rg.gctx.Crate().Sqlite.Query().GetSetting(ctx.Context(), structures.SettingGlobalSeriesRequestLimit.String())
 	
+	// Job history
+	jobRunRetentionDays, _ := rg.gctx.Crate().Sqlite.Query().GetSetting(ctx.Context(), structures.SettingJobRunRetentionDays.String())
+	jobSchedulesValue, _ := rg.gctx.Crate().Sqlite.Query().GetSetting(ctx.Context(), structures.SettingJobSchedules.String())
+
+	// Database backups
+	backupRetention := rg.gctx.Crate().Backup.Retention(ctx.Context())
 
 	// Set defaults for settings that don't have values
 	if requestSystem == "" {
@@ -118,6 +144,17 @@ func (rg *RouteGroup) GetSystemSettings(ctx *respond.Ctx) error {
 		}
 	}
 	
+	jobRunRetention := 30 // Default: 30 days
+	if jobRunRetentionDays != "" {
+		if days, err := strconv.Atoi(jobRunRetentionDays); err == nil && days > 0 {
+			jobRunRetention = days
+		}
+	}
+	
+	jobSchedules, err := jobs.ParseScheduleOverrides(jobSchedulesValue)
+	if err != nil {
+		jobSchedules = map[structures.Job]structures.JobScheduleOverride{}
+	}
 
 	resp := SystemSettingsResponse{
 		RequestSystem:         requestSystem,
@@ -129,11 +166,18 @@ func (rg *RouteGroup) GetSystemSettings(ctx *respond.Ctx) error {
 		JellystatPort:            jellystatPort,
 		JellystatUseSSL:          jellystatUseSSL == "true",
 		JellystatURL:             jellystatURL,
-		JellystatAPIKey:          jellystatAPIKey,
-		TMDBAPIKey:               tmdbAPIKey,
+		JellystatAPIKeySet:       jellystatAPIKey != "",
+		TMDBAPIKeySet:            tmdbAPIKey != "",
 		DownloadVisibility:       downloadVisibility,
 		GlobalMovieRequestLimit:  movieRequestLimit,
 		GlobalSeriesRequestLimit: seriesRequestLimit,
+		RequestSLAHours:          jobs.RequestSLAHours(ctx.Context(), rg.gctx.Crate().Sqlite.Query()),
+		JobRunRetentionDays:      jobRunRetention,
+		DownloadHistoryRetentionDays: jobs.DownloadHistoryRetentionDays(ctx.Context(), rg.gctx.Crate().Sqlite.Query()),
+		DownloadStallPolicy:          jobs.DownloadStallPolicy(ctx.Context(), rg.gctx.Crate().Sqlite.Query()),
+		BandwidthSchedule:            jobs.BandwidthSchedule(ctx.Context(), rg.gctx.Crate().Sqlite.Query()),
+		JobSchedules:             jobSchedules,
+		BackupRetentionCount:     backupRetention,
 	}
 
 	return ctx.JSON(resp)
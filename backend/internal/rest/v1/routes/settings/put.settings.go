@@ -3,6 +3,7 @@ package settings
 import (
 	"github.com/mahcks/serra/internal/db/repository"
 	"github.com/mahcks/serra/internal/rest/v1/respond"
+	"github.com/mahcks/serra/internal/secrets"
 	apiErrors "github.com/mahcks/serra/pkg/api_errors"
 	"github.com/mahcks/serra/pkg/structures"
 )
@@ -112,10 +113,15 @@ func (rg *RouteGroup) UpdateSettings(ctx *respond.Ctx) error {
 		}
 	}
 
-	if req.JellystatAPIKey != nil {
-		err := rg.gctx.Crate().Sqlite.Query().UpsertSetting(ctx.Context(), repository.UpsertSettingParams{
+	// The API key is never returned by GET, so an empty value leaves it unchanged
+	if req.JellystatAPIKey != nil && *req.JellystatAPIKey != "" {
+		sealed, err := secrets.Seal(*req.JellystatAPIKey)
+		if err != nil {
+			return apiErrors.ErrInternalServerError().SetDetail("failed to encrypt Jellystat API key")
+		}
+		err = rg.gctx.Crate().Sqlite.Query().UpsertSetting(ctx.Context(), repository.UpsertSettingParams{
 			Key:   structures.SettingJellystatAPIKey.String(),
-			Value: *req.JellystatAPIKey,
+			Value: sealed,
 		})
 		if err != nil {
 			return apiErrors.ErrInternalServerError().SetDetail("failed to update Jellystat API key setting")
@@ -1,10 +1,14 @@
 package settings
 
 import (
+	"encoding/json"
+	"log/slog"
 	"strconv"
 	
 	"github.com/mahcks/serra/internal/db/repository"
+	"github.com/mahcks/serra/internal/jobs"
 	"github.com/mahcks/serra/internal/rest/v1/respond"
+	"github.com/mahcks/serra/internal/secrets"
 	apiErrors "github.com/mahcks/serra/pkg/api_errors"
 	"github.com/mahcks/serra/pkg/permissions"
 	"github.com/mahcks/serra/pkg/structures"
@@ -188,10 +192,90 @@ func (rg *RouteGroup) UpdateSystemSettings(ctx *respond.Ctx) error {
 			} else {
 				return apiErrors.ErrBadRequest().SetDetail("global_series_request_limit must be a number")
 			}
+		case "request_sla_hours":
+			settingKey = structures.SettingRequestSLAHours
+			if intVal, ok := value.(float64); ok && intVal >= 0 {
+				stringValue = strconv.Itoa(int(intVal))
+			} else {
+				return apiErrors.ErrBadRequest().SetDetail("request_sla_hours must be zero or a positive number")
+			}
+		case "job_run_retention_days":
+			settingKey = structures.SettingJobRunRetentionDays
+			if intVal, ok := value.(float64); ok && intVal >= 1 {
+				stringValue = strconv.Itoa(int(intVal))
+			} else {
+				return apiErrors.ErrBadRequest().SetDetail("job_run_retention_days must be a positive number")
+			}
+		case "download_history_retention_days":
+			settingKey = structures.SettingDownloadHistoryRetentionDays
+			if intVal, ok := value.(float64); ok && intVal >= 0 {
+				stringValue = strconv.Itoa(int(intVal))
+			} else {
+				return apiErrors.ErrBadRequest().SetDetail("download_history_retention_days must be zero or a positive number")
+			}
+		case "download_stall_policy":
+			settingKey = structures.SettingDownloadStallPolicy
+			raw, err := json.Marshal(value)
+			if err != nil {
+				return apiErrors.ErrBadRequest().SetDetail("download_stall_policy must be an object")
+			}
+			if _, err := jobs.ParseDownloadStallPolicy(string(raw)); err != nil {
+				return apiErrors.ErrBadRequest().SetDetail(err.Error())
+			}
+			stringValue = string(raw)
+		case "bandwidth_schedule":
+			settingKey = structures.SettingBandwidthSchedule
+			raw, err := json.Marshal(value)
+			if err != nil {
+				return apiErrors.ErrBadRequest().SetDetail("bandwidth_schedule must be an object")
+			}
+			if _, err := jobs.ParseBandwidthSchedule(string(raw)); err != nil {
+				return apiErrors.ErrBadRequest().SetDetail(err.Error())
+			}
+			stringValue = string(raw)
+		case "backup_retention_count":
+			settingKey = structures.SettingBackupRetentionCount
+			if intVal, ok := value.(float64); ok && intVal >= 1 {
+				stringValue = strconv.Itoa(int(intVal))
+			} else {
+				return apiErrors.ErrBadRequest().SetDetail("backup_retention_count must be a positive number")
+			}
+		case "job_schedules":
+			settingKey = structures.SettingJobSchedules
+			raw, err := json.Marshal(value)
+			if err != nil {
+				return apiErrors.ErrBadRequest().SetDetail("job_schedules must be an object")
+			}
+			overrides, err := jobs.ParseScheduleOverrides(string(raw))
+			if err != nil {
+				return apiErrors.ErrBadRequest().SetDetail("job_schedules must be an object keyed by job name")
+			}
+			for name, override := range overrides {
+				defaults, ok := jobs.GetDefaultConfig(name)
+				if !ok {
+					return apiErrors.ErrBadRequest().SetDetail("unknown job: " + name.String())
+				}
+				if _, err := jobs.ApplyScheduleOverride(defaults, override); err != nil {
+					return apiErrors.ErrBadRequest().SetDetail("invalid schedule for " + name.String() + ": " + err.Error())
+				}
+			}
+			stringValue = string(raw)
 		default:
 			return apiErrors.ErrBadRequest().SetDetail("unknown setting: " + settingName)
 		}
 
+		if secrets.IsSecretSetting(settingKey.String()) {
+			// Secrets are never returned by GET, so an empty value leaves them unchanged
+			if stringValue == "" {
+				continue
+			}
+			sealed, err := secrets.Seal(stringValue)
+			if err != nil {
+				return apiErrors.ErrInternalServerError().SetDetail("failed to encrypt " + settingName)
+			}
+			stringValue = sealed
+		}
+
 		// Update the setting in the database
 		err := rg.gctx.Crate().Sqlite.Query().UpsertSetting(ctx.Context(), repository.UpsertSettingParams{
 			Key:   settingKey.String(),
@@ -200,6 +284,12 @@ func (rg *RouteGroup) UpdateSystemSettings(ctx *respond.Ctx) error {
 		if err != nil {
 			return apiErrors.ErrInternalServerError().SetDetail("failed to update " + settingName)
 		}
+
+		if settingKey == structures.SettingJobSchedules {
+			if err := jobs.NotifyScheduleChange(ctx.Context(), rg.gctx.Crate().EventBus); err != nil {
+				slog.Warn("Failed to announce job schedule change", "error", err)
+			}
+		}
 	}
 
 	return ctx.JSON(map[string]string{"message": "System settings updated successfully"})
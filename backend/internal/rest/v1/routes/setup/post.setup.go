@@ -8,6 +8,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/mahcks/serra/internal/db/repository"
 	"github.com/mahcks/serra/internal/rest/v1/respond"
+	"github.com/mahcks/serra/internal/secrets"
 	apiErrors "github.com/mahcks/serra/pkg/api_errors"
 	"github.com/mahcks/serra/pkg/structures"
 )
@@ -62,7 +63,13 @@ func (rg *RouteGroup) Initialize(ctx *respond.Ctx) error {
 	}
 
 	// Begin transaction
-	tx, err := rg.gctx.Crate().Sqlite.DB().Begin()
+	db, release, err := rg.gctx.Crate().Sqlite.Hold()
+	if err != nil {
+		return apiErrors.ErrConflict().SetDetail("A database restore is in progress, try again in a moment")
+	}
+	defer release()
+
+	tx, err := db.Begin()
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
@@ -94,6 +101,10 @@ func (rg *RouteGroup) Initialize(ctx *respond.Ctx) error {
 
 	// Insert or update settings
 	for key, value := range settings {
+		value, err := secrets.SealSetting(key.String(), value)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt setting %s: %w", key, err)
+		}
 		if err := txQueries.UpsertSetting(ctx.Context(), repository.UpsertSettingParams{
 			Key:   key.String(),
 			Value: value,
@@ -109,7 +120,7 @@ func (rg *RouteGroup) Initialize(ctx *respond.Ctx) error {
 			Type:                "radarr",
 			Name:                radarr.Name,
 			BaseUrl:             radarr.BaseURL,
-			ApiKey:              radarr.APIKey,
+			ApiKey:              secrets.String(radarr.APIKey),
 			QualityProfile:      radarr.QualityProfile,
 			RootFolderPath:      radarr.RootFolderPath,
 			MinimumAvailability: radarr.MinimumAvailability,
@@ -126,7 +137,7 @@ func (rg *RouteGroup) Initialize(ctx *respond.Ctx) error {
 			Type:                "sonarr",
 			Name:                sonarr.Name,
 			BaseUrl:             sonarr.BaseURL,
-			ApiKey:              sonarr.APIKey,
+			ApiKey:              secrets.String(sonarr.APIKey),
 			QualityProfile:      sonarr.QualityProfile,
 			RootFolderPath:      sonarr.RootFolderPath,
 			MinimumAvailability: sonarr.MinimumAvailability,
@@ -146,8 +157,8 @@ func (rg *RouteGroup) Initialize(ctx *respond.Ctx) error {
 			Host:     client.Host,
 			Port:     int64(client.Port),
 			Username: sql.NullString{String: client.Username, Valid: client.Username != ""},
-			Password: sql.NullString{String: client.Password, Valid: client.Password != ""},
-			ApiKey:   sql.NullString{String: client.APIKey, Valid: client.APIKey != ""},
+			Password: secrets.NullString{NullString: sql.NullString{String: client.Password, Valid: client.Password != ""}},
+			ApiKey:   secrets.NullString{NullString: sql.NullString{String: client.APIKey, Valid: client.APIKey != ""}},
 			UseSsl:   sql.NullBool{Bool: client.UseSSL, Valid: true},
 		})
 		if err != nil {
@@ -0,0 +1,44 @@
+package users
+
+import (
+	"log/slog"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mahcks/serra/internal/db/repository"
+	"github.com/mahcks/serra/internal/rest/v1/respond"
+	apiErrors "github.com/mahcks/serra/pkg/api_errors"
+)
+
+// RemoveFromWatchlist removes a title from the current user's watchlist
+func (rg *RouteGroup) RemoveFromWatchlist(ctx *respond.Ctx) error {
+	user := ctx.ParseClaims()
+	if user == nil || user.ID == "" {
+		return apiErrors.ErrUnauthorized()
+	}
+
+	mediaType := ctx.Params("media_type")
+	if mediaType != "movie" && mediaType != "tv" {
+		return apiErrors.ErrInvalidMediaType().SetDetail("Media type '%s' is not supported", mediaType)
+	}
+
+	tmdbID, err := strconv.ParseInt(ctx.Params("tmdb_id"), 10, 64)
+	if err != nil {
+		return apiErrors.ErrBadRequest().SetDetail("Invalid TMDB ID")
+	}
+
+	removed, err := rg.gctx.Crate().Sqlite.Query().DeleteWatchlistItem(ctx.Context(), repository.DeleteWatchlistItemParams{
+		UserID:    user.ID,
+		MediaType: mediaType,
+		TmdbID:    tmdbID,
+	})
+	if err != nil {
+		slog.Error("Failed to remove watchlist item", "error", err, "user_id", user.ID, "tmdb_id", tmdbID)
+		return apiErrors.ErrInternalServerError().SetDetail("Failed to remove from watchlist")
+	}
+	if removed == 0 {
+		return apiErrors.ErrNotFound().SetDetail("Title is not on your watchlist")
+	}
+
+	return ctx.JSON(fiber.Map{"message": "Removed from watchlist"})
+}
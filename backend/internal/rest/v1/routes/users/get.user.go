@@ -76,14 +76,23 @@ func (rg *RouteGroup) GetUser(ctx *respond.Ctx) error {
 		createdAt = dbUser.CreatedAt.Time.Format("2006-01-02T15:04:05Z")
 	}
 
+	requestPriority, err := rg.gctx.Crate().Sqlite.Query().GetUserRequestPriority(ctx.Context(), userID)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			slog.Error("Failed to get user request priority", "error", err, "user_id", userID)
+		}
+		requestPriority = structures.RequestPriorityNormal
+	}
+
 	userWithPermissions := structures.UserWithPermissions{
-		ID:          dbUser.ID,
-		Username:    dbUser.Username,
-		Email:       email,
-		AvatarUrl:   avatarUrl,
-		UserType:    dbUser.UserType,
-		CreatedAt:   createdAt,
-		Permissions: permissionInfos,
+		ID:              dbUser.ID,
+		Username:        dbUser.Username,
+		Email:           email,
+		AvatarUrl:       avatarUrl,
+		UserType:        dbUser.UserType,
+		CreatedAt:       createdAt,
+		Permissions:     permissionInfos,
+		RequestPriority: requestPriority,
 	}
 
 	return ctx.JSON(userWithPermissions)
@@ -0,0 +1,53 @@
+package users
+
+import (
+	"log/slog"
+
+	"github.com/mahcks/serra/internal/db/repository"
+	"github.com/mahcks/serra/internal/rest/v1/respond"
+	apiErrors "github.com/mahcks/serra/pkg/api_errors"
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+// GetWatchlist returns the current user's watchlist, newest first
+func (rg *RouteGroup) GetWatchlist(ctx *respond.Ctx) error {
+	user := ctx.ParseClaims()
+	if user == nil || user.ID == "" {
+		return apiErrors.ErrUnauthorized()
+	}
+
+	items, err := rg.gctx.Crate().Sqlite.Query().GetUserWatchlist(ctx.Context(), user.ID)
+	if err != nil {
+		slog.Error("Failed to get watchlist", "error", err, "user_id", user.ID)
+		return apiErrors.ErrInternalServerError().SetDetail("Failed to retrieve watchlist")
+	}
+
+	watchlist := make([]structures.WatchlistItem, 0, len(items))
+	for _, item := range items {
+		watchlist = append(watchlist, toWatchlistItem(item))
+	}
+
+	return ctx.JSON(watchlist)
+}
+
+func toWatchlistItem(item repository.WatchlistItem) structures.WatchlistItem {
+	watchlistItem := structures.WatchlistItem{
+		ID:          item.ID,
+		MediaType:   item.MediaType,
+		TmdbID:      item.TmdbID,
+		Title:       item.Title,
+		PosterURL:   item.PosterUrl.String,
+		AutoRequest: item.AutoRequest,
+	}
+	if item.RequestID.Valid {
+		requestID := item.RequestID.Int64
+		watchlistItem.RequestID = &requestID
+	}
+	if item.AvailableNotifiedAt.Valid {
+		watchlistItem.AvailableAt = item.AvailableNotifiedAt.Time.Format("2006-01-02T15:04:05Z")
+	}
+	if item.CreatedAt.Valid {
+		watchlistItem.CreatedAt = item.CreatedAt.Time.Format("2006-01-02T15:04:05Z")
+	}
+	return watchlistItem
+}
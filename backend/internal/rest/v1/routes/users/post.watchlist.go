@@ -0,0 +1,65 @@
+package users
+
+import (
+	"database/sql"
+	"log/slog"
+	"strings"
+
+	"github.com/mahcks/serra/internal/db/repository"
+	"github.com/mahcks/serra/internal/rest/v1/respond"
+	apiErrors "github.com/mahcks/serra/pkg/api_errors"
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+// AddToWatchlist adds a title to the current user's watchlist. Adding a title
+// that is already on the watchlist updates it.
+func (rg *RouteGroup) AddToWatchlist(ctx *respond.Ctx) error {
+	user := ctx.ParseClaims()
+	if user == nil || user.ID == "" {
+		return apiErrors.ErrUnauthorized()
+	}
+
+	var req structures.AddWatchlistItemRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return apiErrors.ErrBadRequest().SetDetail("Invalid request body")
+	}
+
+	if req.MediaType != "movie" && req.MediaType != "tv" {
+		return apiErrors.ErrInvalidMediaType().SetDetail("Media type '%s' is not supported", req.MediaType)
+	}
+	if req.TmdbID < 1 {
+		return apiErrors.ErrBadRequest().SetDetail("tmdb_id is required")
+	}
+	req.Title = strings.TrimSpace(req.Title)
+	if req.Title == "" {
+		return apiErrors.ErrBadRequest().SetDetail("title is required")
+	}
+	if req.AutoRequest && req.MediaType != "movie" {
+		return apiErrors.ErrBadRequest().SetDetail("auto_request is only supported for movies")
+	}
+
+	params := repository.AddWatchlistItemParams{
+		UserID:      user.ID,
+		MediaType:   req.MediaType,
+		TmdbID:      req.TmdbID,
+		Title:       req.Title,
+		AutoRequest: req.AutoRequest,
+	}
+	if req.PosterURL != nil && *req.PosterURL != "" {
+		params.PosterUrl = sql.NullString{String: *req.PosterURL, Valid: true}
+	}
+
+	item, err := rg.gctx.Crate().Sqlite.Query().AddWatchlistItem(ctx.Context(), params)
+	if err != nil {
+		slog.Error("Failed to add watchlist item", "error", err, "user_id", user.ID, "tmdb_id", req.TmdbID)
+		return apiErrors.ErrInternalServerError().SetDetail("Failed to add to watchlist")
+	}
+
+	slog.Info("Watchlist item added",
+		"user_id", user.ID,
+		"media_type", req.MediaType,
+		"tmdb_id", req.TmdbID,
+		"auto_request", req.AutoRequest)
+
+	return ctx.JSON(toWatchlistItem(item))
+}
@@ -7,11 +7,13 @@ import (
 	"github.com/mahcks/serra/internal/rest/v1/respond"
 	apiErrors "github.com/mahcks/serra/pkg/api_errors"
 	"github.com/mahcks/serra/internal/db/repository"
+	"github.com/mahcks/serra/pkg/structures"
 )
 
 type UpdateUserRequest struct {
-	Username string  `json:"username"`
-	Email    *string `json:"email"`
+	Username        string  `json:"username"`
+	Email           *string `json:"email"`
+	RequestPriority *string `json:"request_priority,omitempty"` // Priority the user's new requests start at
 }
 
 // UpdateUser updates basic user information (username, email)
@@ -35,6 +37,13 @@ func (rg *RouteGroup) UpdateUser(ctx *respond.Ctx) error {
 	if req.Username == "" {
 		return apiErrors.ErrBadRequest().SetDetail("username is required")
 	}
+	if req.RequestPriority != nil {
+		switch *req.RequestPriority {
+		case structures.RequestPriorityLow, structures.RequestPriorityNormal, structures.RequestPriorityHigh, structures.RequestPriorityUrgent:
+		default:
+			return apiErrors.ErrBadRequest().SetDetail("request_priority must be one of low, normal, high, urgent")
+		}
+	}
 
 	// Check if user exists
 	exists, err := rg.gctx.Crate().Sqlite.Query().UserExists(ctx.Context(), userID)
@@ -66,6 +75,22 @@ func (rg *RouteGroup) UpdateUser(ctx *respond.Ctx) error {
 		return apiErrors.ErrInternalServerError().SetDetail("failed to update user")
 	}
 
+	// Normal is the default, so it needs no tier of its own
+	if req.RequestPriority != nil {
+		if *req.RequestPriority == structures.RequestPriorityNormal {
+			err = rg.gctx.Crate().Sqlite.Query().DeleteUserRequestPriority(ctx.Context(), userID)
+		} else {
+			err = rg.gctx.Crate().Sqlite.Query().SetUserRequestPriority(ctx.Context(), repository.SetUserRequestPriorityParams{
+				UserID:   userID,
+				Priority: *req.RequestPriority,
+			})
+		}
+		if err != nil {
+			slog.Error("Failed to update user request priority", "error", err, "user_id", userID)
+			return apiErrors.ErrInternalServerError().SetDetail("failed to update request priority")
+		}
+	}
+
 	slog.Info("User updated successfully", "user_id", userID, "updated_by", user.ID)
 
 	return ctx.JSON(map[string]interface{}{
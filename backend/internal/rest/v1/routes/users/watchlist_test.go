@@ -0,0 +1,108 @@
+package users
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	jwt "github.com/golang-jwt/jwt/v5"
+	"github.com/mahcks/serra/config"
+	"github.com/mahcks/serra/internal/db/dbtest"
+	"github.com/mahcks/serra/internal/global"
+	"github.com/mahcks/serra/internal/rest/v1/respond"
+	"github.com/mahcks/serra/internal/services/auth"
+	apiErrors "github.com/mahcks/serra/pkg/api_errors"
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+func TestWatchlistRoutes(t *testing.T) {
+	gctx := global.New(context.Background(), &config.Bootstrap{}, "test", "")
+	gctx.Crate().Sqlite = dbtest.Service(t)
+	if _, err := gctx.Crate().Sqlite.DB().Exec(`INSERT INTO users (id, username) VALUES ('alice', 'alice')`); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+	rg := NewRouteGroup(gctx)
+
+	app := fiber.New(fiber.Config{ErrorHandler: func(c *fiber.Ctx, err error) error {
+		var apiErr apiErrors.APIError
+		if errors.As(err, &apiErr) {
+			return c.SendStatus(apiErr.ExpectedHTTPStatus())
+		}
+		return c.SendStatus(fiber.StatusInternalServerError)
+	}})
+	// Stands in for the JWT middleware
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("_serrauser", &jwt.Token{Claims: &auth.JWTClaimUser{UserID: "alice"}})
+		return c.Next()
+	})
+	app.Get("/users/me/watchlist", func(c *fiber.Ctx) error { return rg.GetWatchlist(&respond.Ctx{Ctx: c}) })
+	app.Post("/users/me/watchlist", func(c *fiber.Ctx) error { return rg.AddToWatchlist(&respond.Ctx{Ctx: c}) })
+	app.Delete("/users/me/watchlist/:media_type/:tmdb_id", func(c *fiber.Ctx) error { return rg.RemoveFromWatchlist(&respond.Ctx{Ctx: c}) })
+
+	send := func(method, path, body string) int {
+		t.Helper()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("%s %s: %v", method, path, err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	watchlist := func() []structures.WatchlistItem {
+		t.Helper()
+		resp, err := app.Test(httptest.NewRequest("GET", "/users/me/watchlist", nil))
+		if err != nil {
+			t.Fatalf("get watchlist: %v", err)
+		}
+		defer resp.Body.Close()
+		var items []structures.WatchlistItem
+		if err := json.NewDecoder(resp.Body).Decode(&items); err != nil {
+			t.Fatalf("decode watchlist: %v", err)
+		}
+		return items
+	}
+
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"movie", `{"media_type": "movie", "tmdb_id": 603, "title": "The Matrix"}`, fiber.StatusOK},
+		{"same movie again updates it", `{"media_type": "movie", "tmdb_id": 603, "title": "The Matrix", "auto_request": true}`, fiber.StatusOK},
+		{"show", `{"media_type": "tv", "tmdb_id": 1399, "title": "Show"}`, fiber.StatusOK},
+		{"auto-request is movies only", `{"media_type": "tv", "tmdb_id": 1400, "title": "Show", "auto_request": true}`, fiber.StatusBadRequest},
+		{"unknown media type", `{"media_type": "book", "tmdb_id": 1, "title": "Book"}`, fiber.StatusBadRequest},
+		{"missing title", `{"media_type": "movie", "tmdb_id": 604, "title": " "}`, fiber.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		if status := send("POST", "/users/me/watchlist", tt.body); status != tt.want {
+			t.Errorf("%s: status = %d, want %d", tt.name, status, tt.want)
+		}
+	}
+
+	items := watchlist()
+	if len(items) != 2 {
+		t.Fatalf("watchlist has %d items, want 2: %+v", len(items), items)
+	}
+	for _, item := range items {
+		if item.AutoRequest != (item.MediaType == "movie") {
+			t.Errorf("%s: auto_request = %v", item.Title, item.AutoRequest)
+		}
+	}
+
+	if status := send("DELETE", "/users/me/watchlist/movie/603", ""); status != fiber.StatusOK {
+		t.Errorf("remove: status = %d", status)
+	}
+	if status := send("DELETE", "/users/me/watchlist/movie/603", ""); status != fiber.StatusNotFound {
+		t.Errorf("remove again: status = %d, want 404", status)
+	}
+	if items := watchlist(); len(items) != 1 || items[0].TmdbID != 1399 {
+		t.Errorf("watchlist after removal = %+v, want only the show", items)
+	}
+}
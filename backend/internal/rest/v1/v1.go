@@ -15,12 +15,15 @@ import (
 	"github.com/mahcks/serra/internal/rest/v1/routes"
 	"github.com/mahcks/serra/internal/rest/v1/routes/analytics"
 	authRoutes "github.com/mahcks/serra/internal/rest/v1/routes/auth"
+	"github.com/mahcks/serra/internal/rest/v1/routes/backups"
 	"github.com/mahcks/serra/internal/rest/v1/routes/calendar"
 	"github.com/mahcks/serra/internal/rest/v1/routes/discover"
 	downloadclients "github.com/mahcks/serra/internal/rest/v1/routes/download_clients"
 	"github.com/mahcks/serra/internal/rest/v1/routes/downloads"
 	"github.com/mahcks/serra/internal/rest/v1/routes/emby"
+	"github.com/mahcks/serra/internal/rest/v1/routes/imports"
 	"github.com/mahcks/serra/internal/rest/v1/routes/invitations"
+	"github.com/mahcks/serra/internal/rest/v1/routes/jobs"
 	"github.com/mahcks/serra/internal/rest/v1/routes/mounted_drives"
 	"github.com/mahcks/serra/internal/rest/v1/routes/notifications"
 	"github.com/mahcks/serra/internal/rest/v1/routes/permissions"
@@ -44,6 +47,9 @@ func ctx(fn func(*respond.Ctx) error) fiber.Handler {
 }
 
 func New(gctx global.Context, integrations *integrations.Integration, router fiber.Router) {
+	// CSRF tokens and rate limits live in the database so every instance shares them
+	middleware.UseDatabase(gctx, gctx.Crate().Sqlite.Query())
+
 	indexRoute := routes.NewRouteGroup(gctx, integrations)
 	router.Get("/", ctx(indexRoute.Index))
 
@@ -65,12 +71,12 @@ func New(gctx global.Context, integrations *integrations.Integration, router fib
 	// Set up WebSocket broadcast function for notifications
 	gctx.Crate().NotificationService.SetBroadcastFunc(websocket.BroadcastToUser)
 
-	radarrRoutes := radarr.NewRouteGroup(gctx)
+	radarrRoutes := radarr.NewRouteGroup(gctx, integrations)
 	router.Post("/radarr/test", ctx(radarrRoutes.TestRadarr))
 	router.Post("/radarr/qualityprofiles", ctx(radarrRoutes.GetProfiles))
 	router.Post("/radarr/rootfolders", ctx(radarrRoutes.GetRootFolders))
 
-	sonarrRoutes := sonarr.NewRouteGroup(gctx)
+	sonarrRoutes := sonarr.NewRouteGroup(gctx, integrations)
 	router.Post("/sonarr/test", ctx(sonarrRoutes.TestSonarr))
 	router.Post("/sonarr/qualityprofiles", ctx(sonarrRoutes.GetProfiles))
 	router.Post("/sonarr/rootfolders", ctx(sonarrRoutes.GetSonarrRootFolders))
@@ -182,6 +188,8 @@ func New(gctx global.Context, integrations *integrations.Integration, router fib
 
 	downloadsRoutes := downloads.NewRouteGroup(gctx)
 	router.Get("/downloads", ctx(downloadsRoutes.GetDownloads))
+	router.Get("/downloads/history", ctx(downloadsRoutes.GetDownloadHistory))
+	router.Get("/downloads/bandwidth", ctx(downloadsRoutes.GetBandwidth))
 
 	embyRoutes := emby.NewRouteGroup(gctx, integrations)
 	// Generic media server routes (supports both Emby and Jellyfin)
@@ -213,6 +221,7 @@ func New(gctx global.Context, integrations *integrations.Integration, router fib
 
 	// Notification routes
 	notificationsRoutes := notifications.NewRouteGroup(gctx)
+	notificationsRoutes.RegisterOperations()
 	router.Get("/notifications", ctx(notificationsRoutes.GetNotifications))
 	router.Get("/notifications/count", ctx(notificationsRoutes.GetUnreadCount))
 	router.Get("/notifications/preferences", ctx(notificationsRoutes.GetNotificationPreferences))
@@ -248,11 +257,24 @@ func New(gctx global.Context, integrations *integrations.Integration, router fib
 	// User settings routes - self-service for authenticated users
 	router.Get("/users/me/settings", ctx(usersRoutes.GetUserSettings))
 	router.Put("/users/me/settings", middleware.CSRFProtection(), ctx(usersRoutes.UpdateUserSettings))
+	// Watchlist routes - titles the user is interested in
+	router.Get("/users/me/watchlist", ctx(usersRoutes.GetWatchlist))
+	router.Post("/users/me/watchlist", middleware.CSRFProtection(), ctx(usersRoutes.AddToWatchlist))
+	router.Delete("/users/me/watchlist/:media_type/:tmdb_id", middleware.CSRFProtection(), ctx(usersRoutes.RemoveFromWatchlist))
+
+	// Import routes - bring in lists from Letterboxd, IMDb and Trakt exports
+	importRoutes := imports.NewRouteGroup(gctx, integrations)
+	router.Post("/imports/preview", middleware.CSRFProtection(), ctx(importRoutes.PreviewImport))
+	router.Post("/imports", middleware.CSRFProtection(), ctx(importRoutes.CommitImport))
+	// Overseerr/Jellyseerr migration - owner only, it grants permissions and changes the defaults
+	router.Post("/imports/overseerr", middleware.RequirePermission(gctx.Crate().Sqlite.Query(), permissionConstants.Owner), middleware.CSRFProtection(), ctx(importRoutes.ImportOverseerr))
 
 	// Request routes - users can view/create requests, admins can manage them
 	requestsRoutes := requests.NewRouteGroup(gctx, integrations)
 	// Create request - requires appropriate permission based on media type
 	router.Post("/requests", ctx(requestsRoutes.CreateRequest))
+	// Request every movie of a TMDB collection - requires the movie request permission
+	router.Post("/requests/collection/:collection_id", ctx(requestsRoutes.CreateCollectionRequest))
 	// Get user's own requests - all authenticated users
 	router.Get("/requests/me", ctx(requestsRoutes.GetUserRequests))
 	// Get all requests - admin only
@@ -261,10 +283,21 @@ func New(gctx global.Context, integrations *integrations.Integration, router fib
 	router.Get("/requests/pending", middleware.RequirePermission(gctx.Crate().Sqlite.Query(), permissionConstants.RequestsView), ctx(requestsRoutes.GetPendingRequests))
 	// Get request statistics - admin only
 	router.Get("/requests/statistics", middleware.RequirePermission(gctx.Crate().Sqlite.Query(), permissionConstants.RequestsView), ctx(requestsRoutes.GetRequestStatistics))
+	// Get Radarr/Sonarr options for advanced requests - request.advanced or approvers
+	router.Get("/requests/options", ctx(requestsRoutes.GetRequestOptions))
+	// Routing rules that pick the Radarr/Sonarr instance for a request - admin only
+	router.Get("/requests/routing/rules", middleware.RequirePermission(gctx.Crate().Sqlite.Query(), permissionConstants.AdminSystem), ctx(requestsRoutes.GetRoutingRules))
+	router.Post("/requests/routing/rules", middleware.RequirePermission(gctx.Crate().Sqlite.Query(), permissionConstants.AdminSystem), middleware.CSRFProtection(), ctx(requestsRoutes.CreateRoutingRule))
+	router.Put("/requests/routing/rules/:id", middleware.RequirePermission(gctx.Crate().Sqlite.Query(), permissionConstants.AdminSystem), middleware.CSRFProtection(), ctx(requestsRoutes.UpdateRoutingRule))
+	router.Delete("/requests/routing/rules/:id", middleware.RequirePermission(gctx.Crate().Sqlite.Query(), permissionConstants.AdminSystem), middleware.CSRFProtection(), ctx(requestsRoutes.DeleteRoutingRule))
+	// Dry run: explain which rule a TMDB id would match
+	router.Post("/requests/routing/preview", middleware.RequirePermission(gctx.Crate().Sqlite.Query(), permissionConstants.AdminSystem), middleware.CSRFProtection(), ctx(requestsRoutes.PreviewRouting))
 
 	// Get/Update/Delete specific request by ID
 	router.Get("/requests/:id", ctx(requestsRoutes.GetRequestByID))
 	router.Put("/requests/:id", ctx(requestsRoutes.UpdateRequest))
+	// Set the priority of a request - approvers
+	router.Put("/requests/:id/priority", middleware.CSRFProtection(), ctx(requestsRoutes.UpdateRequestPriority))
 	router.Delete("/requests/:id", ctx(requestsRoutes.DeleteRequest))
 
 	// Invitation routes (admin only - invitationRoutes already declared above for public routes)
@@ -279,6 +312,8 @@ func New(gctx global.Context, integrations *integrations.Integration, router fib
 	// TODO: Public invitation routes need to be added outside JWT middleware
 	// Request management routes - admin only
 	router.Post("/requests/retry-failed", middleware.RequirePermission(gctx.Crate().Sqlite.Query(), permissionConstants.RequestsManage), ctx(requestsRoutes.RetryFailedRequests))
+	// Approve, deny, delete or re-prioritise many requests at once - approvers, deleting needs requests.manage
+	router.Post("/requests/bulk", middleware.CSRFProtection(), ctx(requestsRoutes.BulkUpdateRequests))
 
 	// Analytics routes - admin only for drive monitoring and system analytics
 	analyticsRoutes := analytics.New(gctx)
@@ -290,7 +325,20 @@ func New(gctx global.Context, integrations *integrations.Integration, router fib
 	// New analytics endpoints
 	router.Get("/analytics/requests", middleware.RequirePermission(gctx.Crate().Sqlite.Query(), permissionConstants.AdminSystem), ctx(analyticsRoutes.GetRequestAnalytics))
 	router.Get("/analytics/requests/trends", middleware.RequirePermission(gctx.Crate().Sqlite.Query(), permissionConstants.AdminSystem), ctx(analyticsRoutes.GetRequestTrends))
+	router.Get("/analytics/requests/approvals", middleware.RequirePermission(gctx.Crate().Sqlite.Query(), permissionConstants.AdminSystem), ctx(analyticsRoutes.GetApprovalTimes))
 	router.Get("/analytics/requests/failures", middleware.RequirePermission(gctx.Crate().Sqlite.Query(), permissionConstants.AdminSystem), ctx(analyticsRoutes.GetFailureAnalysis))
 	router.Get("/analytics/requests/availability", middleware.RequirePermission(gctx.Crate().Sqlite.Query(), permissionConstants.AdminSystem), ctx(analyticsRoutes.GetContentAvailability))
 	router.Get("/analytics/watch", middleware.RequirePermission(gctx.Crate().Sqlite.Query(), permissionConstants.AdminSystem), ctx(analyticsRoutes.GetWatchAnalytics))
+
+	// Job history routes - admin only
+	jobsRoutes := jobs.NewRouteGroup(gctx)
+	router.Get("/jobs/:name/runs", middleware.RequirePermission(gctx.Crate().Sqlite.Query(), permissionConstants.AdminSystem), ctx(jobsRoutes.GetJobRuns))
+
+	// Database backup routes - admin only
+	backupsRoutes := backups.NewRouteGroup(gctx)
+	router.Get("/backups", middleware.RequirePermission(gctx.Crate().Sqlite.Query(), permissionConstants.AdminSystem), ctx(backupsRoutes.GetBackups))
+	router.Post("/backups", middleware.RequirePermission(gctx.Crate().Sqlite.Query(), permissionConstants.AdminSystem), middleware.CSRFProtection(), ctx(backupsRoutes.CreateBackup))
+	router.Get("/backups/export", middleware.RequirePermission(gctx.Crate().Sqlite.Query(), permissionConstants.AdminSystem), ctx(backupsRoutes.ExportConfig))
+	router.Get("/backups/:name/download", middleware.RequirePermission(gctx.Crate().Sqlite.Query(), permissionConstants.AdminSystem), ctx(backupsRoutes.DownloadBackup))
+	router.Post("/backups/:name/restore", middleware.RequirePermission(gctx.Crate().Sqlite.Query(), permissionConstants.AdminSystem), middleware.CSRFProtection(), ctx(backupsRoutes.RestoreBackup))
 }
@@ -0,0 +1,250 @@
+// Package secrets encrypts integration credentials before they are written to
+// the database.
+//
+// Values are sealed with envelope encryption: every value gets its own random
+// data key, the value is encrypted with that key using AES-256-GCM, and the data
+// key is in turn encrypted with a key-encryption key derived from the bootstrap
+// encryption secret. Sealed values look like
+//
+//	enc:v1:<key id>:<wrapped data key>:<ciphertext>
+//
+// The key id identifies which bootstrap secret sealed the value, so previous
+// secrets can still open old values while everything is resealed with the
+// current one.
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+const (
+	prefix  = "enc:v1:"
+	kdfSalt = "serra-secrets"
+	kdfInfo = "serra key-encryption key v1"
+)
+
+var (
+	// ErrNoKeyring is returned when sealing or opening before a keyring is configured
+	ErrNoKeyring = errors.New("secrets keyring not configured")
+	// ErrUnknownKey is returned when a value was sealed with a key that is not in the keyring
+	ErrUnknownKey = errors.New("value was sealed with an unknown encryption key")
+	// ErrMalformed is returned when a sealed value cannot be parsed
+	ErrMalformed = errors.New("malformed sealed value")
+)
+
+// Keyring holds the current key-encryption key and any previous ones that are
+// still accepted for opening values during a rotation
+type Keyring struct {
+	primary string
+	keys    map[string]cipher.AEAD
+}
+
+// NewKeyring derives key-encryption keys from the current secret and any
+// previous secrets
+func NewKeyring(secret string, previous ...string) (*Keyring, error) {
+	if secret == "" {
+		return nil, errors.New("encryption secret must not be empty")
+	}
+
+	k := &Keyring{keys: make(map[string]cipher.AEAD)}
+
+	id, err := k.add(secret)
+	if err != nil {
+		return nil, err
+	}
+	k.primary = id
+
+	for _, old := range previous {
+		if old == "" {
+			continue
+		}
+		if _, err := k.add(old); err != nil {
+			return nil, err
+		}
+	}
+
+	return k, nil
+}
+
+func (k *Keyring) add(secret string) (string, error) {
+	key, err := hkdf.Key(sha256.New, []byte(secret), []byte(kdfSalt), kdfInfo, 32)
+	if err != nil {
+		return "", fmt.Errorf("failed to derive encryption key: %w", err)
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(key)
+	id := hex.EncodeToString(sum[:4])
+	k.keys[id] = aead
+	return id, nil
+}
+
+// Seal encrypts plaintext with a fresh data key wrapped by the primary key
+func (k *Keyring) Seal(plaintext string) (string, error) {
+	dataKey := make([]byte, 32)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", err
+	}
+
+	wrapped, err := encrypt(k.keys[k.primary], dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	ciphertext, err := encrypt(aead, []byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+
+	return prefix + k.primary + ":" + encode(wrapped) + ":" + encode(ciphertext), nil
+}
+
+// Open decrypts a sealed value. Values that were never sealed are returned
+// unchanged so rows written before encryption keep working until resealed.
+func (k *Keyring) Open(value string) (string, error) {
+	if !IsSealed(value) {
+		return value, nil
+	}
+
+	parts := strings.Split(strings.TrimPrefix(value, prefix), ":")
+	if len(parts) != 3 {
+		return "", ErrMalformed
+	}
+
+	kek, ok := k.keys[parts[0]]
+	if !ok {
+		return "", ErrUnknownKey
+	}
+
+	wrapped, err := decode(parts[1])
+	if err != nil {
+		return "", ErrMalformed
+	}
+	ciphertext, err := decode(parts[2])
+	if err != nil {
+		return "", ErrMalformed
+	}
+
+	dataKey, err := decrypt(kek, wrapped)
+	if err != nil {
+		return "", fmt.Errorf("failed to unwrap data key: %w", err)
+	}
+
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	plaintext, err := decrypt(aead, ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt value: %w", err)
+	}
+
+	return string(plaintext), nil
+}
+
+// IsCurrent reports whether value is sealed with the primary key
+func (k *Keyring) IsCurrent(value string) bool {
+	return strings.HasPrefix(value, prefix+k.primary+":")
+}
+
+// IsSealed reports whether value is an encrypted value rather than plaintext
+func IsSealed(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
+
+var (
+	defaultMu      sync.RWMutex
+	defaultKeyring *Keyring
+)
+
+// SetDefault installs the keyring used by Seal, Open and the database column types
+func SetDefault(k *Keyring) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultKeyring = k
+}
+
+// Default returns the installed keyring, or nil if none has been set
+func Default() *Keyring {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultKeyring
+}
+
+// Seal encrypts plaintext with the default keyring. Empty values are stored as-is.
+func Seal(plaintext string) (string, error) {
+	if plaintext == "" || IsSealed(plaintext) {
+		return plaintext, nil
+	}
+
+	k := Default()
+	if k == nil {
+		return "", ErrNoKeyring
+	}
+	return k.Seal(plaintext)
+}
+
+// Open decrypts value with the default keyring, passing plaintext through
+func Open(value string) (string, error) {
+	if !IsSealed(value) {
+		return value, nil
+	}
+
+	k := Default()
+	if k == nil {
+		return "", ErrNoKeyring
+	}
+	return k.Open(value)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func encrypt(aead cipher.AEAD, plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func decrypt(aead cipher.AEAD, data []byte) ([]byte, error) {
+	if len(data) < aead.NonceSize() {
+		return nil, ErrMalformed
+	}
+	nonce, ciphertext := data[:aead.NonceSize()], data[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, nil)
+}
+
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decode(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(s)
+}
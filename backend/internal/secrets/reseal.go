@@ -0,0 +1,106 @@
+package secrets
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// column identifies a secret column and the key used to update its rows
+type column struct {
+	table  string
+	key    string
+	column string
+	where  string
+}
+
+// columns lists every secret stored in the database
+func columns() []column {
+	keys := make([]string, 0, len(settingKeys))
+	for key := range settingKeys {
+		keys = append(keys, "'"+key.String()+"'")
+	}
+
+	return []column{
+		{table: "arr_services", key: "id", column: "api_key"},
+		{table: "download_clients", key: "id", column: "password"},
+		{table: "download_clients", key: "id", column: "api_key"},
+		{table: "settings", key: "key", column: "value", where: "key IN (" + strings.Join(keys, ", ") + ")"},
+	}
+}
+
+// Reseal encrypts plaintext secrets left over from before encryption was
+// introduced and re-encrypts values sealed with a previous key, all in one
+// transaction. It returns the number of values rewritten.
+func Reseal(ctx context.Context, db *sql.DB, k *Keyring) (int, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	resealed := 0
+	for _, col := range columns() {
+		n, err := resealColumn(ctx, tx, k, col)
+		if err != nil {
+			return 0, fmt.Errorf("failed to reseal %s.%s: %w", col.table, col.column, err)
+		}
+		resealed += n
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return resealed, nil
+}
+
+func resealColumn(ctx context.Context, tx *sql.Tx, k *Keyring, col column) (int, error) {
+	query := fmt.Sprintf("SELECT %s, %s FROM %s WHERE %s IS NOT NULL AND %s != ''", col.key, col.column, col.table, col.column, col.column)
+	if col.where != "" {
+		query += " AND " + col.where
+	}
+
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+
+	updates := make(map[string]string)
+	for rows.Next() {
+		var id, value string
+		if err := rows.Scan(&id, &value); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		if k.IsCurrent(value) {
+			continue
+		}
+
+		plaintext, err := k.Open(value)
+		if err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("row %s: %w", id, err)
+		}
+
+		sealed, err := k.Seal(plaintext)
+		if err != nil {
+			rows.Close()
+			return 0, err
+		}
+		updates[id] = sealed
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	update := fmt.Sprintf("UPDATE %s SET %s = ? WHERE %s = ?", col.table, col.column, col.key)
+	for id, sealed := range updates {
+		if _, err := tx.ExecContext(ctx, update, sealed, id); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(updates), nil
+}
@@ -0,0 +1,35 @@
+package secrets
+
+import "github.com/mahcks/serra/pkg/structures"
+
+// settingKeys are the settings whose values are credentials
+var settingKeys = map[structures.Setting]bool{
+	structures.SettingMediaServerAPIKey:  true,
+	structures.SettingJellystatAPIKey:    true,
+	structures.SettingTMDBAPIKey:         true,
+	structures.SettingEmailSMTPPassword:  true,
+	structures.SettingEmailPGPPrivateKey: true,
+	structures.SettingEmailPGPPassword:   true,
+}
+
+// IsSecretSetting reports whether the setting holds a credential
+func IsSecretSetting(key string) bool {
+	return settingKeys[structures.Setting(key)]
+}
+
+// SecretSettings returns the keys of all settings that hold credentials
+func SecretSettings() []structures.Setting {
+	keys := make([]structures.Setting, 0, len(settingKeys))
+	for key := range settingKeys {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// SealSetting encrypts value if key is a credential setting
+func SealSetting(key, value string) (string, error) {
+	if !IsSecretSetting(key) {
+		return value, nil
+	}
+	return Seal(value)
+}
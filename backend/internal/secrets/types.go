@@ -0,0 +1,73 @@
+package secrets
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+)
+
+// String is a text column that is sealed when written and opened when read, so
+// callers only ever see plaintext. It is used for NOT NULL secret columns.
+type String string
+
+// Scan implements sql.Scanner
+func (s *String) Scan(src interface{}) error {
+	var raw string
+	switch v := src.(type) {
+	case nil:
+		raw = ""
+	case string:
+		raw = v
+	case []byte:
+		raw = string(v)
+	default:
+		return fmt.Errorf("cannot scan %T into secrets.String", src)
+	}
+
+	plaintext, err := Open(raw)
+	if err != nil {
+		return err
+	}
+	*s = String(plaintext)
+	return nil
+}
+
+// Value implements driver.Valuer
+func (s String) Value() (driver.Value, error) {
+	return Seal(string(s))
+}
+
+// String returns the plaintext value
+func (s String) String() string {
+	return string(s)
+}
+
+// NullString is the nullable counterpart of String
+type NullString struct {
+	sql.NullString
+}
+
+// Scan implements sql.Scanner
+func (s *NullString) Scan(src interface{}) error {
+	if err := s.NullString.Scan(src); err != nil {
+		return err
+	}
+	if !s.Valid {
+		return nil
+	}
+
+	plaintext, err := Open(s.NullString.String)
+	if err != nil {
+		return err
+	}
+	s.NullString.String = plaintext
+	return nil
+}
+
+// Value implements driver.Valuer
+func (s NullString) Value() (driver.Value, error) {
+	if !s.Valid {
+		return nil, nil
+	}
+	return Seal(s.NullString.String)
+}
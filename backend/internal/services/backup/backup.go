@@ -0,0 +1,253 @@
+package backup
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mahcks/serra/internal/services/sqlite"
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+// DefaultRetention is the number of backups kept when no retention is configured
+const DefaultRetention = 7
+
+const timestampFormat = "20060102-150405"
+
+// ErrNotFound is returned when a backup name does not exist in the backup directory
+var ErrNotFound = errors.New("backup not found")
+
+// backupName matches files written by this service, e.g.
+// serra-scheduled-20250801-030000-3f9a1c.db. The random suffix keeps backups
+// taken within the same second apart; older backups don't have one.
+var backupName = regexp.MustCompile(`^serra-(scheduled|manual|pre-restore|pre-migration)-(\d{8}-\d{6})(?:-[0-9a-f]{6})?\.db$`)
+
+type Service struct {
+	db  sqlite.Service
+	dir string
+	mu  sync.Mutex
+}
+
+// New creates the backup service, creating dir if it does not exist. An empty
+// dir places backups in a "backups" directory next to the database file.
+func New(db sqlite.Service, dir string) (*Service, error) {
+	if dir == "" {
+		dir = filepath.Join(filepath.Dir(db.Path()), "backups")
+	}
+
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	return &Service{
+		db:  db,
+		dir: dir,
+	}, nil
+}
+
+// Directory returns the directory backups are written to
+func (s *Service) Directory() string {
+	return s.dir
+}
+
+// Create takes a new online backup of the database
+func (s *Service) Create(ctx context.Context, kind structures.BackupKind) (structures.Backup, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.create(ctx, kind)
+}
+
+func (s *Service) create(ctx context.Context, kind structures.BackupKind) (structures.Backup, error) {
+	now := time.Now().UTC()
+	suffix := make([]byte, 3)
+	if _, err := rand.Read(suffix); err != nil {
+		return structures.Backup{}, fmt.Errorf("failed to generate backup name: %w", err)
+	}
+	name := fmt.Sprintf("serra-%s-%s-%s.db", kind, now.Format(timestampFormat), hex.EncodeToString(suffix))
+	path := filepath.Join(s.dir, name)
+
+	start := time.Now()
+	if err := s.db.Backup(ctx, path); err != nil {
+		return structures.Backup{}, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return structures.Backup{}, err
+	}
+
+	slog.Info("Database backup created", "name", name, "size", info.Size(), "duration", time.Since(start))
+
+	return structures.Backup{
+		Name:      name,
+		Kind:      kind,
+		Size:      info.Size(),
+		CreatedAt: now,
+	}, nil
+}
+
+// List returns all backups in the backup directory, newest first
+func (s *Service) List() ([]structures.Backup, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup directory: %w", err)
+	}
+
+	backups := make([]structures.Backup, 0, len(entries))
+	modified := make(map[string]time.Time, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		backup, ok := parseName(entry.Name())
+		if !ok {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		backup.Size = info.Size()
+		modified[backup.Name] = info.ModTime()
+
+		backups = append(backups, backup)
+	}
+
+	// Names only have second resolution, so backups taken within the same
+	// second are ordered by when their file was written
+	sort.Slice(backups, func(i, j int) bool {
+		if !backups[i].CreatedAt.Equal(backups[j].CreatedAt) {
+			return backups[i].CreatedAt.After(backups[j].CreatedAt)
+		}
+		return modified[backups[i].Name].After(modified[backups[j].Name])
+	})
+
+	return backups, nil
+}
+
+// Path resolves a backup name to its file, rejecting anything that is not a
+// backup written by this service
+func (s *Service) Path(name string) (string, error) {
+	if _, ok := parseName(name); !ok {
+		return "", ErrNotFound
+	}
+
+	path := filepath.Join(s.dir, name)
+	if _, err := os.Stat(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", ErrNotFound
+		}
+		return "", err
+	}
+
+	return path, nil
+}
+
+// Restore replaces the live database with the named backup. A pre-restore
+// backup of the current database is taken first so the restore can be undone.
+func (s *Service) Restore(ctx context.Context, name string) (structures.Backup, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path, err := s.Path(name)
+	if err != nil {
+		return structures.Backup{}, err
+	}
+
+	safety, err := s.create(ctx, structures.BackupKindPreRestore)
+	if err != nil {
+		return structures.Backup{}, fmt.Errorf("failed to back up current database: %w", err)
+	}
+
+	if err := s.db.Restore(ctx, path); err != nil {
+		// A busy database was left untouched, so the pre-restore backup isn't needed
+		if errors.Is(err, sqlite.ErrDatabaseBusy) {
+			_ = os.Remove(filepath.Join(s.dir, safety.Name))
+			return structures.Backup{}, err
+		}
+		return safety, err
+	}
+
+	slog.Info("Database restored from backup", "name", name, "pre_restore_backup", safety.Name)
+	return safety, nil
+}
+
+// Prune deletes the oldest backups so that at most keep of each kind remain.
+// Kinds are pruned separately so that frequent scheduled backups never push
+// out the pre-restore and pre-migration backups needed to undo a change.
+func (s *Service) Prune(keep int) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if keep < 1 {
+		keep = 1
+	}
+
+	backups, err := s.List()
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	kept := make(map[structures.BackupKind]int)
+	for _, backup := range backups {
+		if kept[backup.Kind] < keep {
+			kept[backup.Kind]++
+			continue
+		}
+		if err := os.Remove(filepath.Join(s.dir, backup.Name)); err != nil {
+			slog.Warn("Failed to remove old backup", "name", backup.Name, "error", err)
+			continue
+		}
+		removed++
+	}
+
+	return removed, nil
+}
+
+// Retention returns the configured number of backups to keep
+func (s *Service) Retention(ctx context.Context) int {
+	value, err := s.db.Query().GetSetting(ctx, structures.SettingBackupRetentionCount.String())
+	if err != nil || value == "" {
+		return DefaultRetention
+	}
+
+	count, err := strconv.Atoi(value)
+	if err != nil || count < 1 {
+		slog.Warn("Invalid backup retention setting, using default", "value", value)
+		return DefaultRetention
+	}
+
+	return count
+}
+
+func parseName(name string) (structures.Backup, bool) {
+	matches := backupName.FindStringSubmatch(name)
+	if matches == nil {
+		return structures.Backup{}, false
+	}
+
+	createdAt, err := time.Parse(timestampFormat, matches[2])
+	if err != nil {
+		return structures.Backup{}, false
+	}
+
+	return structures.Backup{
+		Name:      name,
+		Kind:      structures.BackupKind(matches[1]),
+		CreatedAt: createdAt,
+	}, true
+}
@@ -0,0 +1,178 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/mahcks/serra/internal/db/dbtest"
+	"github.com/mahcks/serra/internal/services/sqlite"
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+func newService(t *testing.T) *Service {
+	t.Helper()
+	service, err := New(dbtest.Service(t), t.TempDir())
+	if err != nil {
+		t.Fatalf("new backup service: %v", err)
+	}
+	return service
+}
+
+// writeBackup places an empty backup file in the backup directory
+func writeBackup(t *testing.T, service *Service, name string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(service.Directory(), name), nil, 0o600); err != nil {
+		t.Fatalf("write %s: %v", name, err)
+	}
+}
+
+func listNames(t *testing.T, service *Service) []string {
+	t.Helper()
+	backups, err := service.List()
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	names := make([]string, 0, len(backups))
+	for _, backup := range backups {
+		names = append(names, backup.Name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func TestParseName(t *testing.T) {
+	tests := []struct {
+		name string
+		kind structures.BackupKind
+		ok   bool
+	}{
+		{"serra-scheduled-20250801-030000-3f9a1c.db", structures.BackupKindScheduled, true},
+		{"serra-manual-20250801-030000.db", structures.BackupKindManual, true},
+		{"serra-pre-migration-20250801-030000.db", structures.BackupKindPreMigrate, true},
+		{"serra-pre-restore-20250801-030000-abcdef.db", structures.BackupKindPreRestore, true},
+		{"serra-manual-20250801-030000-XYZ.db", "", false},
+		{"serra-other-20250801-030000.db", "", false},
+		{"notes.txt", "", false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			backup, ok := parseName(test.name)
+			if ok != test.ok {
+				t.Fatalf("parseName(%s) ok = %v, want %v", test.name, ok, test.ok)
+			}
+			if !ok {
+				return
+			}
+			if backup.Kind != test.kind {
+				t.Errorf("kind = %s, want %s", backup.Kind, test.kind)
+			}
+			if want := time.Date(2025, 8, 1, 3, 0, 0, 0, time.UTC); !backup.CreatedAt.Equal(want) {
+				t.Errorf("created at = %v, want %v", backup.CreatedAt, want)
+			}
+		})
+	}
+}
+
+func TestCreateNamesAreUnique(t *testing.T) {
+	service := newService(t)
+	ctx := context.Background()
+
+	seen := map[string]bool{}
+	for range 3 {
+		backup, err := service.Create(ctx, structures.BackupKindManual)
+		if err != nil {
+			t.Fatalf("create: %v", err)
+		}
+		if seen[backup.Name] {
+			t.Fatalf("backup name %s reused", backup.Name)
+		}
+		seen[backup.Name] = true
+	}
+
+	if names := listNames(t, service); len(names) != 3 {
+		t.Fatalf("expected 3 backups, got %v", names)
+	}
+}
+
+func TestPruneKeepsEachKind(t *testing.T) {
+	service := newService(t)
+	for _, name := range []string{
+		"serra-pre-migration-20250701-030000.db",
+		"serra-pre-restore-20250702-030000-aaaaaa.db",
+		"serra-manual-20250703-030000-bbbbbb.db",
+		"serra-scheduled-20250801-030000-000001.db",
+		"serra-scheduled-20250802-030000-000002.db",
+		"serra-scheduled-20250803-030000-000003.db",
+	} {
+		writeBackup(t, service, name)
+	}
+
+	removed, err := service.Prune(2)
+	if err != nil {
+		t.Fatalf("prune: %v", err)
+	}
+	if removed != 1 {
+		t.Errorf("expected 1 backup removed, got %d", removed)
+	}
+
+	want := []string{
+		"serra-manual-20250703-030000-bbbbbb.db",
+		"serra-pre-migration-20250701-030000.db",
+		"serra-pre-restore-20250702-030000-aaaaaa.db",
+		"serra-scheduled-20250802-030000-000002.db",
+		"serra-scheduled-20250803-030000-000003.db",
+	}
+	if got := listNames(t, service); !slices.Equal(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestPruneSameSecondKeepsNewest(t *testing.T) {
+	service := newService(t)
+	older := "serra-scheduled-20250801-030000-ffffff.db"
+	newer := "serra-scheduled-20250801-030000-000000.db"
+	writeBackup(t, service, older)
+	writeBackup(t, service, newer)
+
+	now := time.Now()
+	if err := os.Chtimes(filepath.Join(service.Directory(), older), now, now.Add(-time.Minute)); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+
+	if _, err := service.Prune(1); err != nil {
+		t.Fatalf("prune: %v", err)
+	}
+	if got := listNames(t, service); !slices.Equal(got, []string{newer}) {
+		t.Fatalf("expected %s to be kept, got %v", newer, got)
+	}
+}
+
+func TestRestoreRefusedWhileDatabaseHeld(t *testing.T) {
+	service := newService(t)
+	ctx := context.Background()
+
+	manual, err := service.Create(ctx, structures.BackupKindManual)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	_, release, err := service.db.Hold()
+	if err != nil {
+		t.Fatalf("hold: %v", err)
+	}
+	defer release()
+
+	if _, err := service.Restore(ctx, manual.Name); !errors.Is(err, sqlite.ErrDatabaseBusy) {
+		t.Fatalf("expected ErrDatabaseBusy, got %v", err)
+	}
+
+	// The refused restore leaves no pre-restore backup behind
+	if names := listNames(t, service); !slices.Equal(names, []string{manual.Name}) {
+		t.Fatalf("expected only %s, got %v", manual.Name, names)
+	}
+}
@@ -0,0 +1,134 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mahcks/serra/internal/secrets"
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+// redacted replaces secret values in redacted exports
+const redacted = "[REDACTED]"
+
+// Export builds a JSON-serialisable snapshot of the configuration tables. When
+// redact is set, API keys and passwords are replaced with a placeholder.
+func (s *Service) Export(ctx context.Context, version string, redact bool) (*structures.ConfigExport, error) {
+	query := s.db.Query()
+
+	export := &structures.ConfigExport{
+		Version:            version,
+		ExportedAt:         time.Now().UTC(),
+		SecretsRedacted:    redact,
+		Settings:           make(map[string]string),
+		ArrServices:        []structures.ConfigExportArrService{},
+		DownloadClients:    []structures.ConfigExportDownloadClient{},
+		Permissions:        []structures.ConfigExportPermission{},
+		DefaultPermissions: make(map[string]bool),
+		UserPermissions:    []structures.ConfigExportUserPermission{},
+	}
+
+	settings, err := query.GetAllSettings(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export settings: %w", err)
+	}
+	for _, setting := range settings {
+		value, err := secrets.Open(setting.Value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt setting %s: %w", setting.Key, err)
+		}
+		if redact && secrets.IsSecretSetting(setting.Key) && value != "" {
+			value = redacted
+		}
+		export.Settings[setting.Key] = value
+	}
+
+	arrServices, err := query.GetAllArrServices(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export arr services: %w", err)
+	}
+	for _, service := range arrServices {
+		apiKey := service.ApiKey.String()
+		if redact && apiKey != "" {
+			apiKey = redacted
+		}
+		export.ArrServices = append(export.ArrServices, structures.ConfigExportArrService{
+			ID:                  service.ID,
+			Type:                service.Type,
+			Name:                service.Name,
+			BaseURL:             service.BaseUrl,
+			APIKey:              apiKey,
+			QualityProfile:      service.QualityProfile,
+			RootFolderPath:      service.RootFolderPath,
+			MinimumAvailability: service.MinimumAvailability,
+			Is4K:                service.Is4k,
+		})
+	}
+
+	clients, err := query.GetDownloadClients(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export download clients: %w", err)
+	}
+	for _, client := range clients {
+		exported := structures.ConfigExportDownloadClient{
+			ID:     client.ID,
+			Type:   client.Type,
+			Name:   client.Name,
+			Host:   client.Host,
+			Port:   client.Port,
+			UseSSL: client.UseSsl.Valid && client.UseSsl.Bool,
+		}
+		if client.Username.Valid {
+			exported.Username = &client.Username.String
+		}
+		if client.Password.Valid {
+			exported.Password = secretValue(client.Password.String, redact)
+		}
+		if client.ApiKey.Valid {
+			exported.APIKey = secretValue(client.ApiKey.String, redact)
+		}
+		export.DownloadClients = append(export.DownloadClients, exported)
+	}
+
+	permissions, err := query.GetAllPermissions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export permissions: %w", err)
+	}
+	for _, permission := range permissions {
+		export.Permissions = append(export.Permissions, structures.ConfigExportPermission{
+			ID:          permission.ID,
+			Name:        permission.Name,
+			Description: permission.Description.String,
+		})
+	}
+
+	defaults, err := query.GetAllDefaultPermissionSettings(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export default permissions: %w", err)
+	}
+	for _, permission := range defaults {
+		export.DefaultPermissions[permission.PermissionID] = permission.Enabled
+	}
+
+	userPermissions, err := query.GetAllUserPermissions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export user permissions: %w", err)
+	}
+	for _, permission := range userPermissions {
+		export.UserPermissions = append(export.UserPermissions, structures.ConfigExportUserPermission{
+			UserID:       permission.UserID,
+			Username:     permission.Username,
+			PermissionID: permission.PermissionID,
+		})
+	}
+
+	return export, nil
+}
+
+func secretValue(value string, redact bool) *string {
+	if redact && value != "" {
+		value = redacted
+	}
+	return &value
+}
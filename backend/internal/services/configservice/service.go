@@ -8,6 +8,7 @@ import (
 
 	"github.com/mahcks/serra/config"
 	"github.com/mahcks/serra/internal/db/repository"
+	"github.com/mahcks/serra/internal/secrets"
 	"github.com/mahcks/serra/pkg/structures"
 )
 
@@ -33,10 +34,15 @@ func (s *Service) Load(ctx context.Context) error {
 	// Create a map of settings
 	settingsMap := make(map[string]interface{})
 	for _, setting := range settings {
+		raw, err := secrets.Open(setting.Value)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt setting %s: %w", setting.Key, err)
+		}
+
 		var value interface{}
-		if err := json.Unmarshal([]byte(setting.Value), &value); err != nil {
+		if err := json.Unmarshal([]byte(raw), &value); err != nil {
 			// If not JSON, use as string
-			value = setting.Value
+			value = raw
 		}
 
 		// Convert value to string
@@ -104,8 +110,14 @@ func (s *Service) Set(ctx context.Context, key structures.Setting, value interfa
 		strValue = string(jsonBytes)
 	}
 
+	// Credentials are encrypted before they are stored
+	strValue, err := secrets.SealSetting(key.String(), strValue)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt setting: %w", err)
+	}
+
 	// Store in database
-	err := s.queries.UpsertSetting(ctx, repository.UpsertSettingParams{
+	err = s.queries.UpsertSetting(ctx, repository.UpsertSettingParams{
 		Key:   key.String(),
 		Value: strValue,
 	})
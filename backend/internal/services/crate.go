@@ -1,7 +1,9 @@
 package services
 
 import (
+	"github.com/mahcks/serra/internal/eventbus"
 	"github.com/mahcks/serra/internal/services/auth"
+	"github.com/mahcks/serra/internal/services/backup"
 	"github.com/mahcks/serra/internal/services/configservice"
 	"github.com/mahcks/serra/internal/services/notifications"
 	"github.com/mahcks/serra/internal/services/sqlite"
@@ -12,4 +14,6 @@ type Crate struct {
 	Sqlite            sqlite.Service
 	AuthService       auth.Authmen
 	NotificationService *notifications.Service
+	Backup            *backup.Service
+	EventBus          eventbus.Bus
 }
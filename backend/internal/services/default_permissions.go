@@ -37,6 +37,7 @@ func (s *DefaultPermissionsService) GetDefaultPermissions(ctx context.Context) (
 		permissions.RequestSeries:                    structures.SettingDefaultRequestSeries,
 		permissions.Request4KMovies:                  structures.SettingDefaultRequest4KMovies,
 		permissions.Request4KSeries:                  structures.SettingDefaultRequest4KSeries,
+		permissions.RequestAdvanced:                  structures.SettingDefaultRequestAdvanced,
 		permissions.RequestAutoApproveMovies:         structures.SettingDefaultRequestAutoApproveMovies,
 		permissions.RequestAutoApproveSeries:         structures.SettingDefaultRequestAutoApproveSeries,
 		permissions.RequestAutoApprove4KMovies:       structures.SettingDefaultRequestAutoApprove4KMovies,
@@ -9,6 +9,8 @@ import (
 	"time"
 
 	"github.com/mahcks/serra/internal/db/repository"
+	"github.com/mahcks/serra/internal/websocket"
+	"github.com/mahcks/serra/pkg/structures"
 )
 
 type DriveMonitorService struct {
@@ -345,7 +347,7 @@ func (s *DriveMonitorService) createAlert(ctx context.Context, drive repository.
 	}
 
 	// Create new alert
-	_, err = s.repo.CreateDriveAlert(ctx, repository.CreateDriveAlertParams{
+	alert, err := s.repo.CreateDriveAlert(ctx, repository.CreateDriveAlertParams{
 		DriveID:        drive.ID,
 		AlertType:      alertType,
 		ThresholdValue: threshold,
@@ -364,6 +366,17 @@ func (s *DriveMonitorService) createAlert(ctx context.Context, drive repository.
 		"current_value", currentValue,
 		"message", message)
 
+	websocket.PublishDriveAlert(structures.DriveAlertPayload{
+		ID:             alert.ID,
+		DriveID:        drive.ID,
+		DriveName:      drive.Name,
+		MountPath:      drive.MountPath,
+		AlertType:      alertType,
+		ThresholdValue: threshold,
+		CurrentValue:   currentValue,
+		Message:        message,
+	})
+
 	return nil
 }
 
@@ -0,0 +1,211 @@
+package list_import
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+// MaxRows is the largest number of rows a single import may contain
+const MaxRows = 1000
+
+var ErrTooManyRows = fmt.Errorf("import files are limited to %d rows", MaxRows)
+
+// Parse reads the rows of an export file in the given format. It doesn't use
+// the network, resolving the rows to TMDB is done separately.
+func Parse(format string, r io.Reader) ([]structures.ImportEntry, error) {
+	switch format {
+	case structures.ImportFormatLetterboxd:
+		return ParseLetterboxd(r)
+	case structures.ImportFormatIMDb:
+		return ParseIMDb(r)
+	case structures.ImportFormatTrakt:
+		return ParseTrakt(r)
+	default:
+		return nil, fmt.Errorf("unsupported import format %q", format)
+	}
+}
+
+// ParseLetterboxd parses a Letterboxd watchlist or list export. Letterboxd only
+// exports films, with Name and Year columns.
+func ParseLetterboxd(r io.Reader) ([]structures.ImportEntry, error) {
+	rows, err := readCSV(r, "name")
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]structures.ImportEntry, 0, len(rows))
+	for i, row := range rows {
+		entries = append(entries, structures.ImportEntry{
+			Row:       i + 1,
+			Title:     row["name"],
+			Year:      parseYear(row["year"]),
+			MediaType: "movie",
+		})
+	}
+	return entries, nil
+}
+
+// ParseIMDb parses an IMDb list or watchlist export. Rows carry the IMDb id in
+// the Const column and the kind of title in Title Type.
+func ParseIMDb(r io.Reader) ([]structures.ImportEntry, error) {
+	rows, err := readCSV(r, "const")
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]structures.ImportEntry, 0, len(rows))
+	for i, row := range rows {
+		title := row["title"]
+		if title == "" {
+			title = row["original title"]
+		}
+
+		entry := structures.ImportEntry{
+			Row:    i + 1,
+			Title:  title,
+			Year:   parseYear(row["year"]),
+			ImdbID: row["const"],
+		}
+
+		switch strings.ToLower(row["title type"]) {
+		case "movie", "tvmovie", "tv movie", "video", "short", "tvshort", "tv short", "tvspecial", "tv special":
+			entry.MediaType = "movie"
+		case "tvseries", "tv series", "tvminiseries", "tv mini series":
+			entry.MediaType = "tv"
+		case "tvepisode", "tv episode":
+			entry.Skip = "Single episodes can't be imported"
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// traktItem is one entry of a Trakt watchlist export
+type traktItem struct {
+	Type  string      `json:"type"`
+	Movie *traktMedia `json:"movie"`
+	Show  *traktMedia `json:"show"`
+}
+
+type traktMedia struct {
+	Title string `json:"title"`
+	Year  int    `json:"year"`
+	IDs   struct {
+		TMDB int64  `json:"tmdb"`
+		IMDB string `json:"imdb"`
+		TVDB int64  `json:"tvdb"`
+	} `json:"ids"`
+}
+
+// ParseTrakt parses a Trakt watchlist JSON export. Trakt includes TMDB ids for
+// most items, so they usually don't need a search.
+func ParseTrakt(r io.Reader) ([]structures.ImportEntry, error) {
+	var items []traktItem
+	if err := json.NewDecoder(r).Decode(&items); err != nil {
+		return nil, fmt.Errorf("invalid Trakt export: %w", err)
+	}
+	if len(items) > MaxRows {
+		return nil, ErrTooManyRows
+	}
+
+	entries := make([]structures.ImportEntry, 0, len(items))
+	for i, item := range items {
+		entry := structures.ImportEntry{Row: i + 1}
+
+		var media *traktMedia
+		switch item.Type {
+		case "movie":
+			media = item.Movie
+			entry.MediaType = "movie"
+		case "show":
+			media = item.Show
+			entry.MediaType = "tv"
+		default:
+			// Seasons and episodes still name their show
+			media = item.Show
+			entry.Skip = fmt.Sprintf("Trakt %s entries can't be imported", item.Type)
+		}
+
+		if media != nil {
+			entry.Title = media.Title
+			entry.Year = media.Year
+			entry.TmdbID = media.IDs.TMDB
+			entry.ImdbID = media.IDs.IMDB
+			entry.TvdbID = media.IDs.TVDB
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// readCSV reads a CSV file with a header row and returns each row keyed by its
+// lower-cased column name. The required column must be present.
+func readCSV(r io.Reader, required string) ([]map[string]string, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("the file is empty")
+		}
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+
+	columns := make([]string, len(header))
+	hasRequired := false
+	for i, name := range header {
+		// Exports may start with a byte order mark
+		columns[i] = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if columns[i] == required {
+			hasRequired = true
+		}
+	}
+	if !hasRequired {
+		return nil, fmt.Errorf("the file has no %q column, check that the right format was selected", required)
+	}
+
+	var rows []map[string]string
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+		if len(rows) == MaxRows {
+			return nil, ErrTooManyRows
+		}
+
+		row := make(map[string]string, len(columns))
+		for i, value := range record {
+			if i < len(columns) {
+				row[columns[i]] = strings.TrimSpace(value)
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// parseYear returns the year of a value such as "1999" or "1999-03-31", or
+// 0 when it has none
+func parseYear(value string) int {
+	if len(value) < 4 {
+		return 0
+	}
+	year, err := strconv.Atoi(value[:4])
+	if err != nil {
+		return 0
+	}
+	return year
+}
@@ -0,0 +1,109 @@
+package list_import
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+func parseFixture(t *testing.T, format, name string) []structures.ImportEntry {
+	t.Helper()
+	file, err := os.Open("testdata/" + name)
+	if err != nil {
+		t.Fatalf("open fixture: %v", err)
+	}
+	defer file.Close()
+
+	entries, err := Parse(format, file)
+	if err != nil {
+		t.Fatalf("parse %s: %v", name, err)
+	}
+	return entries
+}
+
+func TestParseLetterboxd(t *testing.T) {
+	entries := parseFixture(t, structures.ImportFormatLetterboxd, "letterboxd_watchlist.csv")
+
+	want := []structures.ImportEntry{
+		{Row: 1, Title: "Dune", Year: 2021, MediaType: "movie"},
+		{Row: 2, Title: "Crouching Tiger, Hidden Dragon", Year: 2000, MediaType: "movie"},
+		{Row: 3, Title: "Heat", MediaType: "movie"},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Fatalf("expected %+v, got %+v", want, entries)
+	}
+}
+
+func TestParseIMDb(t *testing.T) {
+	entries := parseFixture(t, structures.ImportFormatIMDb, "imdb_watchlist.csv")
+
+	want := []structures.ImportEntry{
+		{Row: 1, Title: "Heat", Year: 1995, MediaType: "movie", ImdbID: "tt0113277"},
+		{Row: 2, Title: "Breaking Bad", Year: 2008, MediaType: "tv", ImdbID: "tt0903747"},
+		{Row: 3, Title: "Pilot", Year: 2008, ImdbID: "tt0959621", Skip: "Single episodes can't be imported"},
+		// Falls back to the original title
+		{Row: 4, Title: "The Wire", Year: 2002, MediaType: "tv", ImdbID: "tt0306414"},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Fatalf("expected %+v, got %+v", want, entries)
+	}
+}
+
+func TestParseTrakt(t *testing.T) {
+	entries := parseFixture(t, structures.ImportFormatTrakt, "trakt_watchlist.json")
+
+	want := []structures.ImportEntry{
+		{Row: 1, Title: "Heat", Year: 1995, MediaType: "movie", TmdbID: 949, ImdbID: "tt0113277"},
+		{Row: 2, Title: "Breaking Bad", Year: 2008, MediaType: "tv", TmdbID: 1396, ImdbID: "tt0903747", TvdbID: 81189},
+		{Row: 3, Title: "Breaking Bad", Year: 2008, TmdbID: 1396, ImdbID: "tt0903747", TvdbID: 81189, Skip: "Trakt season entries can't be imported"},
+		{Row: 4, Title: "Obscure Show", Year: 2019, MediaType: "tv", TvdbID: 12345},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Fatalf("expected %+v, got %+v", want, entries)
+	}
+}
+
+func TestParseRejectsWrongFiles(t *testing.T) {
+	letterboxd, err := os.ReadFile("testdata/letterboxd_watchlist.csv")
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		format string
+		input  string
+		err    string
+	}{
+		{"wrong format", structures.ImportFormatIMDb, string(letterboxd), `no "const" column`},
+		{"empty", structures.ImportFormatLetterboxd, "", "the file is empty"},
+		{"not JSON", structures.ImportFormatTrakt, "Name,Year\n", "invalid Trakt export"},
+		{"unknown format", "netflix", "", "unsupported import format"},
+		{"too many rows", structures.ImportFormatLetterboxd, "Name,Year\n" + strings.Repeat("Heat,1995\n", MaxRows+1), ErrTooManyRows.Error()},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Parse(test.format, strings.NewReader(test.input))
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Fatalf("expected an error containing %q, got %v", test.err, err)
+			}
+		})
+	}
+
+	var many strings.Builder
+	many.WriteString("[")
+	for i := 0; i <= MaxRows; i++ {
+		if i > 0 {
+			many.WriteString(",")
+		}
+		fmt.Fprintf(&many, `{"type":"movie","movie":{"title":"Movie %d"}}`, i)
+	}
+	many.WriteString("]")
+	if _, err := ParseTrakt(strings.NewReader(many.String())); err != ErrTooManyRows {
+		t.Fatalf("expected too many rows, got %v", err)
+	}
+}
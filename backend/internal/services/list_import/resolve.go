@@ -0,0 +1,231 @@
+package list_import
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+// maxCandidates is how many TMDB titles are offered for an ambiguous row
+const maxCandidates = 5
+
+// Resolver looks titles up on TMDB. tmdb.Service satisfies it; tests can use a stub.
+type Resolver interface {
+	SearchMovie(query, page string) (structures.TMDBMediaResponse, error)
+	SearchTV(query, page string) (structures.TMDBMediaResponse, error)
+	FindByExternalID(externalID, source string) (structures.TMDBFindResponse, error)
+}
+
+// Preview resolves parsed entries to TMDB titles. Rows that carry a TMDB id are
+// matched without a lookup, then IMDb and TVDB ids are tried, then a title and
+// year search.
+func Preview(resolver Resolver, format string, entries []structures.ImportEntry) structures.ImportPreview {
+	preview := structures.ImportPreview{
+		Format: format,
+		Total:  len(entries),
+		Rows:   make([]structures.ImportRow, 0, len(entries)),
+	}
+
+	for _, entry := range entries {
+		row := Resolve(resolver, entry)
+		switch row.Status {
+		case structures.ImportRowMatched:
+			preview.Matched++
+		case structures.ImportRowAmbiguous:
+			preview.Ambiguous++
+		case structures.ImportRowUnmatched:
+			preview.Unmatched++
+		case structures.ImportRowUnsupported:
+			preview.Unsupported++
+		}
+		preview.Rows = append(preview.Rows, row)
+	}
+
+	return preview
+}
+
+// Resolve finds the TMDB title of a single entry
+func Resolve(resolver Resolver, entry structures.ImportEntry) structures.ImportRow {
+	row := structures.ImportRow{Entry: entry}
+
+	if entry.Skip != "" {
+		row.Status = structures.ImportRowUnsupported
+		row.Reason = entry.Skip
+		return row
+	}
+
+	if entry.TmdbID > 0 && entry.MediaType != "" {
+		row.Status = structures.ImportRowMatched
+		row.Match = &structures.ImportCandidate{
+			MediaType: entry.MediaType,
+			TmdbID:    entry.TmdbID,
+			Title:     entry.Title,
+			Year:      entry.Year,
+		}
+		return row
+	}
+
+	if entry.ImdbID != "" {
+		if candidates, err := findExternal(resolver, entry.ImdbID, "imdb_id", entry.MediaType); err != nil {
+			slog.Warn("Failed to look up IMDb id", "imdb_id", entry.ImdbID, "error", err)
+		} else if len(candidates) == 1 {
+			return matched(row, candidates[0])
+		}
+	}
+
+	if entry.TvdbID > 0 {
+		if candidates, err := findExternal(resolver, strconv.FormatInt(entry.TvdbID, 10), "tvdb_id", entry.MediaType); err != nil {
+			slog.Warn("Failed to look up TVDB id", "tvdb_id", entry.TvdbID, "error", err)
+		} else if len(candidates) == 1 {
+			return matched(row, candidates[0])
+		}
+	}
+
+	if strings.TrimSpace(entry.Title) == "" {
+		row.Status = structures.ImportRowUnmatched
+		row.Reason = "The row has no title"
+		return row
+	}
+
+	candidates, err := search(resolver, entry)
+	if err != nil {
+		row.Status = structures.ImportRowUnmatched
+		row.Reason = "TMDB search failed"
+		slog.Warn("Failed to search TMDB for imported title", "title", entry.Title, "error", err)
+		return row
+	}
+
+	return pick(row, candidates)
+}
+
+// pick chooses the candidate whose title and year fit the entry. Release years
+// differ between sites now and then, so a title off by one year still fits when
+// nothing fits exactly. The row is ambiguous when several candidates fit, or
+// none does.
+func pick(row structures.ImportRow, candidates []structures.ImportCandidate) structures.ImportRow {
+	if len(candidates) == 0 {
+		row.Status = structures.ImportRowUnmatched
+		row.Reason = "No TMDB title found"
+		return row
+	}
+
+	entry := row.Entry
+	title := normalizeTitle(entry.Title)
+
+	var exact, near []structures.ImportCandidate
+	for _, candidate := range candidates {
+		if normalizeTitle(candidate.Title) != title {
+			continue
+		}
+		switch diff := candidate.Year - entry.Year; {
+		case entry.Year == 0 || diff == 0:
+			exact = append(exact, candidate)
+		case diff == -1 || diff == 1:
+			near = append(near, candidate)
+		}
+	}
+
+	for _, fits := range [][]structures.ImportCandidate{exact, near} {
+		switch {
+		case len(fits) == 1:
+			return matched(row, fits[0])
+		case len(fits) > 1:
+			return ambiguous(row, fits)
+		}
+	}
+
+	return ambiguous(row, candidates)
+}
+
+func matched(row structures.ImportRow, candidate structures.ImportCandidate) structures.ImportRow {
+	row.Status = structures.ImportRowMatched
+	row.Match = &candidate
+	return row
+}
+
+func ambiguous(row structures.ImportRow, candidates []structures.ImportCandidate) structures.ImportRow {
+	if len(candidates) > maxCandidates {
+		candidates = candidates[:maxCandidates]
+	}
+	row.Status = structures.ImportRowAmbiguous
+	row.Candidates = candidates
+	row.Reason = fmt.Sprintf("%d TMDB titles match", len(candidates))
+	return row
+}
+
+// findExternal looks up an IMDb or TVDB id, limited to the entry's media type
+// when it is known
+func findExternal(resolver Resolver, externalID, source, mediaType string) ([]structures.ImportCandidate, error) {
+	result, err := resolver.FindByExternalID(externalID, source)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []structures.ImportCandidate
+	if mediaType != "tv" {
+		candidates = append(candidates, toCandidates(result.MovieResults, "movie")...)
+	}
+	if mediaType != "movie" {
+		candidates = append(candidates, toCandidates(result.TVResults, "tv")...)
+	}
+	return candidates, nil
+}
+
+// search looks the entry's title up, as a movie and a TV show when the media
+// type isn't known
+func search(resolver Resolver, entry structures.ImportEntry) ([]structures.ImportCandidate, error) {
+	var candidates []structures.ImportCandidate
+
+	if entry.MediaType != "tv" {
+		movies, err := resolver.SearchMovie(entry.Title, "1")
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, toCandidates(movies.Results, "movie")...)
+	}
+
+	if entry.MediaType != "movie" {
+		shows, err := resolver.SearchTV(entry.Title, "1")
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, toCandidates(shows.Results, "tv")...)
+	}
+
+	return candidates, nil
+}
+
+func toCandidates(items []structures.TMDBMediaItem, mediaType string) []structures.ImportCandidate {
+	candidates := make([]structures.ImportCandidate, 0, len(items))
+	for _, item := range items {
+		candidate := structures.ImportCandidate{
+			MediaType:  mediaType,
+			TmdbID:     item.ID,
+			Title:      item.Title,
+			Year:       parseYear(item.ReleaseDate),
+			PosterPath: item.PosterPath,
+		}
+		if mediaType == "tv" {
+			candidate.Title = item.Name
+			candidate.Year = parseYear(item.FirstAirDate)
+		}
+		candidates = append(candidates, candidate)
+	}
+	return candidates
+}
+
+// normalizeTitle lower-cases a title and drops punctuation and spacing so that
+// "Amélie" and "amélie!" or "Se7en" and "se7en" compare equal
+func normalizeTitle(title string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(title) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
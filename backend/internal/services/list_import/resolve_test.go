@@ -0,0 +1,229 @@
+package list_import
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+// stubTMDB answers searches and id lookups from fixed results
+type stubTMDB struct {
+	movies   map[string][]structures.TMDBMediaItem // By query
+	shows    map[string][]structures.TMDBMediaItem
+	external map[string]structures.TMDBFindResponse // By external id
+	fail     bool
+
+	searches int
+}
+
+func (s *stubTMDB) SearchMovie(query, page string) (structures.TMDBMediaResponse, error) {
+	s.searches++
+	if s.fail {
+		return structures.TMDBMediaResponse{}, errors.New("TMDB is down")
+	}
+	return structures.TMDBMediaResponse{Results: s.movies[query]}, nil
+}
+
+func (s *stubTMDB) SearchTV(query, page string) (structures.TMDBMediaResponse, error) {
+	s.searches++
+	if s.fail {
+		return structures.TMDBMediaResponse{}, errors.New("TMDB is down")
+	}
+	return structures.TMDBMediaResponse{Results: s.shows[query]}, nil
+}
+
+func (s *stubTMDB) FindByExternalID(externalID, source string) (structures.TMDBFindResponse, error) {
+	if s.fail {
+		return structures.TMDBFindResponse{}, errors.New("TMDB is down")
+	}
+	return s.external[externalID], nil
+}
+
+func newStubTMDB() *stubTMDB {
+	return &stubTMDB{
+		movies: map[string][]structures.TMDBMediaItem{
+			"Dune": {
+				{ID: 438631, Title: "Dune", ReleaseDate: "2021-09-15"},
+				{ID: 841, Title: "Dune", ReleaseDate: "1984-12-14"},
+			},
+			"Heat": {
+				{ID: 949, Title: "Heat", ReleaseDate: "1995-12-15"},
+				{ID: 11636, Title: "Heat", ReleaseDate: "1986-01-01"},
+			},
+			"amélie!": {
+				{ID: 194, Title: "Amélie", ReleaseDate: "2001-04-25"},
+			},
+			"Se7en": {
+				{ID: 807, Title: "Se7en", ReleaseDate: "1995-09-22"},
+			},
+		},
+		shows: map[string][]structures.TMDBMediaItem{
+			"Dune": {{ID: 90228, Name: "Dune: Prophecy", FirstAirDate: "2024-11-17"}},
+		},
+		external: map[string]structures.TMDBFindResponse{
+			"tt0113277": {MovieResults: []structures.TMDBMediaItem{{ID: 949, Title: "Heat", ReleaseDate: "1995-12-15"}}},
+			"81189":     {TVResults: []structures.TMDBMediaItem{{ID: 1396, Name: "Breaking Bad", FirstAirDate: "2008-01-20"}}},
+		},
+	}
+}
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name       string
+		entry      structures.ImportEntry
+		status     string
+		match      int64 // TMDB id of the match
+		candidates []int64
+	}{
+		{
+			name:   "title and year",
+			entry:  structures.ImportEntry{Title: "Dune", Year: 2021, MediaType: "movie"},
+			status: structures.ImportRowMatched,
+			match:  438631,
+		},
+		{
+			name:   "year off by one",
+			entry:  structures.ImportEntry{Title: "Dune", Year: 1985, MediaType: "movie"},
+			status: structures.ImportRowMatched,
+			match:  841,
+		},
+		{
+			name:       "same title without a year",
+			entry:      structures.ImportEntry{Title: "Heat", MediaType: "movie"},
+			status:     structures.ImportRowAmbiguous,
+			candidates: []int64{949, 11636},
+		},
+		{
+			name:       "no title fits",
+			entry:      structures.ImportEntry{Title: "Dune", Year: 2010, MediaType: "movie"},
+			status:     structures.ImportRowAmbiguous,
+			candidates: []int64{438631, 841},
+		},
+		{
+			name:       "titles that fit win over the other search results",
+			entry:      structures.ImportEntry{Title: "Dune"},
+			status:     structures.ImportRowAmbiguous,
+			candidates: []int64{438631, 841},
+		},
+		{
+			name:   "punctuation and accents",
+			entry:  structures.ImportEntry{Title: "amélie!", Year: 2001, MediaType: "movie"},
+			status: structures.ImportRowMatched,
+			match:  194,
+		},
+		{
+			name:   "digits in the title",
+			entry:  structures.ImportEntry{Title: "Se7en", Year: 1995, MediaType: "movie"},
+			status: structures.ImportRowMatched,
+			match:  807,
+		},
+		{
+			name:   "IMDb id",
+			entry:  structures.ImportEntry{Title: "Heat", ImdbID: "tt0113277", MediaType: "movie"},
+			status: structures.ImportRowMatched,
+			match:  949,
+		},
+		{
+			name:   "TVDB id",
+			entry:  structures.ImportEntry{Title: "Breaking Bad", TvdbID: 81189, MediaType: "tv"},
+			status: structures.ImportRowMatched,
+			match:  1396,
+		},
+		{
+			name:   "TMDB id needs no lookup",
+			entry:  structures.ImportEntry{Title: "Heat", TmdbID: 949, MediaType: "movie"},
+			status: structures.ImportRowMatched,
+			match:  949,
+		},
+		{
+			name:   "nothing found",
+			entry:  structures.ImportEntry{Title: "Not A Real Film", Year: 1999, MediaType: "movie"},
+			status: structures.ImportRowUnmatched,
+		},
+		{
+			name:   "no title",
+			entry:  structures.ImportEntry{ImdbID: "tt0000000"},
+			status: structures.ImportRowUnmatched,
+		},
+		{
+			name:   "skipped row",
+			entry:  structures.ImportEntry{Title: "Pilot", Skip: "Single episodes can't be imported"},
+			status: structures.ImportRowUnsupported,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			row := Resolve(newStubTMDB(), test.entry)
+			if row.Status != test.status {
+				t.Fatalf("expected %s, got %s (%s)", test.status, row.Status, row.Reason)
+			}
+
+			if test.match != 0 {
+				if row.Match == nil || row.Match.TmdbID != test.match {
+					t.Fatalf("expected match %d, got %+v", test.match, row.Match)
+				}
+			} else if row.Match != nil {
+				t.Fatalf("expected no match, got %+v", row.Match)
+			}
+
+			if test.status == structures.ImportRowAmbiguous && test.candidates != nil {
+				var got []int64
+				for _, candidate := range row.Candidates {
+					got = append(got, candidate.TmdbID)
+				}
+				if len(got) != len(test.candidates) {
+					t.Fatalf("expected candidates %v, got %v", test.candidates, got)
+				}
+				for i := range got {
+					if got[i] != test.candidates[i] {
+						t.Fatalf("expected candidates %v, got %v", test.candidates, got)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestResolveShowUsesTVFields(t *testing.T) {
+	row := Resolve(newStubTMDB(), structures.ImportEntry{Title: "Dune: Prophecy", MediaType: "tv"})
+	if row.Status != structures.ImportRowUnmatched {
+		t.Fatalf("the stub only knows the show by the query Dune, got %s", row.Status)
+	}
+
+	stub := newStubTMDB()
+	stub.shows["Dune: Prophecy"] = stub.shows["Dune"]
+	row = Resolve(stub, structures.ImportEntry{Title: "Dune: Prophecy", Year: 2024, MediaType: "tv"})
+	if row.Status != structures.ImportRowMatched || row.Match.Title != "Dune: Prophecy" || row.Match.Year != 2024 || row.Match.MediaType != "tv" {
+		t.Fatalf("expected the show with its name and air year, got %+v", row.Match)
+	}
+}
+
+func TestResolveFallsBackToSearchWhenLookupFails(t *testing.T) {
+	stub := newStubTMDB()
+	row := Resolve(stub, structures.ImportEntry{Title: "Dune", Year: 2021, ImdbID: "tt1160419", MediaType: "movie"})
+	if row.Status != structures.ImportRowMatched || row.Match.TmdbID != 438631 || stub.searches != 1 {
+		t.Fatalf("expected a match through search, got %+v after %d searches", row, stub.searches)
+	}
+
+	stub.fail = true
+	row = Resolve(stub, structures.ImportEntry{Title: "Dune", Year: 2021, ImdbID: "tt1160419", MediaType: "movie"})
+	if row.Status != structures.ImportRowUnmatched || row.Reason != "TMDB search failed" {
+		t.Fatalf("expected the failed search to be reported, got %+v", row)
+	}
+}
+
+func TestPreviewCountsFixtureRows(t *testing.T) {
+	entries := parseFixture(t, structures.ImportFormatTrakt, "trakt_watchlist.json")
+	preview := Preview(newStubTMDB(), structures.ImportFormatTrakt, entries)
+
+	// Heat and Breaking Bad carry TMDB ids, the season is skipped and the
+	// obscure show is found by neither its TVDB id nor its title
+	if preview.Total != 4 || preview.Matched != 2 || preview.Unsupported != 1 || preview.Unmatched != 1 || preview.Ambiguous != 0 {
+		t.Fatalf("unexpected preview counts %+v", preview)
+	}
+	if len(preview.Rows) != 4 || preview.Format != structures.ImportFormatTrakt {
+		t.Fatalf("unexpected preview %+v", preview)
+	}
+}
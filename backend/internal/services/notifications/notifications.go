@@ -3,17 +3,23 @@ package notifications
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 	"log/slog"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/mahcks/serra/internal/db/repository"
+	"github.com/mahcks/serra/pkg/permissions"
 	"github.com/mahcks/serra/pkg/structures"
 )
 
 // BroadcastFunc defines the function signature for broadcasting WebSocket messages
 type BroadcastFunc func(userID string, op structures.Opcode, data interface{})
 
+// ErrNotDelivered is returned when a notification reached none of its recipients
+var ErrNotDelivered = errors.New("notification was not delivered to anyone")
+
 type Service struct {
 	query     *repository.Queries
 	broadcast BroadcastFunc
@@ -223,6 +229,80 @@ func (s *Service) NotifyRequestApproved(ctx context.Context, userID string, medi
 	return s.CreateNotification(ctx, userID, notification)
 }
 
+// NotifyNewSeasonRequested notifies a user that a new season of a show they
+// requested was requested for them automatically
+func (s *Service) NotifyNewSeasonRequested(ctx context.Context, userID string, mediaTitle string, season int, approved bool, tmdbID *int64, requestID *string) error {
+	mediaType := "tv"
+	data := &structures.NotificationData{
+		MediaTitle: &mediaTitle,
+		MediaType:  &mediaType,
+		TMDBID:     tmdbID,
+		RequestID:  requestID,
+	}
+
+	notification := structures.CreateNotificationRequest{
+		UserID:   userID,
+		Title:    "New Season Requested",
+		Message:  fmt.Sprintf("Season %d of %s has been announced and was requested for you. It is waiting for approval.", season, mediaTitle),
+		Type:     structures.NotificationTypeInfo,
+		Priority: structures.NotificationPriorityNormal,
+		Data:     data,
+	}
+	if approved {
+		notification.Message = fmt.Sprintf("Season %d of %s has been announced and was requested for you. It will be downloaded when it airs.", season, mediaTitle)
+		notification.Type = structures.NotificationTypeRequestApproved
+	}
+
+	return s.CreateNotification(ctx, userID, notification)
+}
+
+// NotifyWatchlistRequested notifies a user that a movie on their watchlist was
+// requested for them because it was released
+func (s *Service) NotifyWatchlistRequested(ctx context.Context, userID string, mediaTitle string, approved bool, tmdbID *int64, requestID *string) error {
+	mediaType := "movie"
+	data := &structures.NotificationData{
+		MediaTitle: &mediaTitle,
+		MediaType:  &mediaType,
+		TMDBID:     tmdbID,
+		RequestID:  requestID,
+	}
+
+	notification := structures.CreateNotificationRequest{
+		UserID:   userID,
+		Title:    "Watchlist Movie Requested",
+		Message:  mediaTitle + " from your watchlist has been released and was requested for you. It is waiting for approval.",
+		Type:     structures.NotificationTypeInfo,
+		Priority: structures.NotificationPriorityNormal,
+		Data:     data,
+	}
+	if approved {
+		notification.Message = mediaTitle + " from your watchlist has been released and was requested for you. It is being processed."
+		notification.Type = structures.NotificationTypeRequestApproved
+	}
+
+	return s.CreateNotification(ctx, userID, notification)
+}
+
+// NotifyWatchlistAvailable notifies a user that a title on their watchlist is now available
+func (s *Service) NotifyWatchlistAvailable(ctx context.Context, userID string, mediaTitle, mediaType string, tmdbID *int64) error {
+	data := &structures.NotificationData{
+		MediaTitle: &mediaTitle,
+		MediaType:  &mediaType,
+		TMDBID:     tmdbID,
+	}
+
+	notification := structures.CreateNotificationRequest{
+		UserID:   userID,
+		Title:    "Watchlist Title Available",
+		Message:  mediaTitle + " from your watchlist is now available for streaming!",
+		Type:     structures.NotificationTypeSuccess,
+		Priority: structures.NotificationPriorityNormal,
+		Data:     data,
+	}
+
+	return s.CreateNotification(ctx, userID, notification)
+}
+
 // NotifyRequestDenied notifies a user that their media request was denied
 func (s *Service) NotifyRequestDenied(ctx context.Context, userID string, mediaTitle, mediaType, reason string, tmdbID *int64, requestID *string) error {
 	data := &structures.NotificationData{
@@ -270,6 +350,72 @@ func (s *Service) NotifyDownloadCompleted(ctx context.Context, userID string, me
 	return s.CreateNotification(ctx, userID, notification)
 }
 
+// NotifyDownloadStalled notifies admins and the requester that a download
+// stalled or failed, and whether it was remediated automatically
+func (s *Service) NotifyDownloadStalled(ctx context.Context, requesterID string, mediaTitle, mediaType string, reason structures.DownloadStallReason, remediated bool, tmdbID *int64, downloadID *string) error {
+	userPerms, err := s.query.GetAllUserPermissions(ctx)
+	if err != nil {
+		slog.Error("Failed to get admin users for stalled download", "error", err)
+		return err
+	}
+
+	seen := make(map[string]bool)
+	var recipientIDs []string
+	if requesterID != "" {
+		seen[requesterID] = true
+		recipientIDs = append(recipientIDs, requesterID)
+	}
+	for _, userPerm := range userPerms {
+		if userPerm.PermissionID != permissions.AdminSystem && userPerm.PermissionID != permissions.Owner {
+			continue
+		}
+		if seen[userPerm.UserID] {
+			continue
+		}
+		seen[userPerm.UserID] = true
+		recipientIDs = append(recipientIDs, userPerm.UserID)
+	}
+
+	var message string
+	switch reason {
+	case structures.DownloadStallFailed:
+		message = fmt.Sprintf("The download of %s failed.", mediaTitle)
+	case structures.DownloadStallNoPeers:
+		message = fmt.Sprintf("The download of %s has no seeds or peers.", mediaTitle)
+	case structures.DownloadStallMetadata:
+		message = fmt.Sprintf("The download of %s is stuck fetching metadata.", mediaTitle)
+	default:
+		message = fmt.Sprintf("The download of %s has stopped making progress.", mediaTitle)
+	}
+	if remediated {
+		message += " The release was blocklisted and another one is being searched for."
+	}
+
+	data := &structures.NotificationData{
+		MediaTitle: &mediaTitle,
+		MediaType:  &mediaType,
+		TMDBID:     tmdbID,
+		DownloadID: downloadID,
+	}
+
+	for _, userID := range recipientIDs {
+		notification := structures.CreateNotificationRequest{
+			UserID:   userID,
+			Title:    "Download Stalled",
+			Message:  message,
+			Type:     structures.NotificationTypeWarning,
+			Priority: structures.NotificationPriorityHigh,
+			Data:     data,
+		}
+
+		if err := s.CreateNotification(ctx, userID, notification); err != nil {
+			slog.Error("Failed to send stalled download notification", "error", err, "user_id", userID)
+		}
+	}
+
+	return nil
+}
+
 // NotifySystemAlert sends a system-wide alert to all users with admin permissions
 func (s *Service) NotifySystemAlert(ctx context.Context, title, message string, priority structures.NotificationPriority) error {
 	// Get all users with admin permissions
@@ -306,6 +452,66 @@ func (s *Service) NotifySystemAlert(ctx context.Context, title, message string,
 	return nil
 }
 
+// NotifyRequestOverdue notifies everyone who can approve requests that a
+// request has been waiting longer than the approval SLA. It returns
+// ErrNotDelivered when no approver was notified.
+func (s *Service) NotifyRequestOverdue(ctx context.Context, mediaTitle, mediaType, requestPriority string, waited time.Duration, tmdbID *int64, requestID *string) error {
+	userPerms, err := s.query.GetAllUserPermissions(ctx)
+	if err != nil {
+		slog.Error("Failed to get approvers for overdue request", "error", err)
+		return err
+	}
+
+	seen := make(map[string]bool)
+	var approverIDs []string
+	for _, userPerm := range userPerms {
+		if userPerm.PermissionID != permissions.RequestsApprove && userPerm.PermissionID != permissions.Owner {
+			continue
+		}
+		if seen[userPerm.UserID] {
+			continue
+		}
+		seen[userPerm.UserID] = true
+		approverIDs = append(approverIDs, userPerm.UserID)
+	}
+
+	priority := structures.NotificationPriorityHigh
+	if requestPriority == structures.RequestPriorityHigh || requestPriority == structures.RequestPriorityUrgent {
+		priority = structures.NotificationPriorityUrgent
+	}
+
+	data := &structures.NotificationData{
+		MediaTitle: &mediaTitle,
+		MediaType:  &mediaType,
+		TMDBID:     tmdbID,
+		RequestID:  requestID,
+	}
+
+	hours := int(waited.Hours())
+	delivered := 0
+	for _, userID := range approverIDs {
+		notification := structures.CreateNotificationRequest{
+			UserID:   userID,
+			Title:    "Request Awaiting Approval",
+			Message:  fmt.Sprintf("The %s request for %s has been waiting for approval for %d hours", requestPriority, mediaTitle, hours),
+			Type:     structures.NotificationTypeWarning,
+			Priority: priority,
+			Data:     data,
+		}
+
+		if err := s.CreateNotification(ctx, userID, notification); err != nil {
+			slog.Error("Failed to send overdue request notification", "error", err, "user_id", userID)
+			continue
+		}
+		delivered++
+	}
+
+	if delivered == 0 {
+		return ErrNotDelivered
+	}
+	return nil
+}
+
 // NotifyUserInvited notifies a user about a new invitation
 func (s *Service) NotifyUserInvited(ctx context.Context, userID, inviterName, inviteCode string, expiresAt *time.Time) error {
 	data := &structures.NotificationData{
@@ -0,0 +1,262 @@
+package overseerr_import
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// apiPageSize is how many users or requests are fetched per API call
+const apiPageSize = 100
+
+// apiClient reads from the API of a running Overseerr or Jellyseerr instance
+type apiClient struct {
+	baseURL string
+	apiKey  string
+	client  *http.Client
+}
+
+type apiPageInfo struct {
+	Pages int `json:"pages"`
+}
+
+type apiUser struct {
+	ID               int64   `json:"id"`
+	Username         *string `json:"username"`
+	PlexUsername     *string `json:"plexUsername"`
+	JellyfinUsername *string `json:"jellyfinUsername"`
+	JellyfinUserID   *string `json:"jellyfinUserId"` // Jellyseerr only
+	Email            string  `json:"email"`
+	Permissions      int64   `json:"permissions"`
+}
+
+type apiRequest struct {
+	ID        int64  `json:"id"`
+	Status    int    `json:"status"`
+	Type      string `json:"type"`
+	Is4K      bool   `json:"is4k"`
+	CreatedAt string `json:"createdAt"`
+	UpdatedAt string `json:"updatedAt"`
+	Media     struct {
+		TmdbID   int64 `json:"tmdbId"`
+		Status   int   `json:"status"`
+		Status4K int   `json:"status4k"`
+		Seasons  []struct {
+			SeasonNumber int `json:"seasonNumber"`
+			Status       int `json:"status"`
+			Status4K     int `json:"status4k"`
+		} `json:"seasons"`
+	} `json:"media"`
+	Seasons []struct {
+		SeasonNumber int `json:"seasonNumber"`
+		Status       int `json:"status"`
+	} `json:"seasons"`
+	RequestedBy *struct {
+		ID int64 `json:"id"`
+	} `json:"requestedBy"`
+	ModifiedBy *struct {
+		ID int64 `json:"id"`
+	} `json:"modifiedBy"`
+}
+
+// FetchAPI reads users, requests and default permissions from a running
+// Overseerr or Jellyseerr instance. The API key is found under Settings >
+// General.
+func FetchAPI(ctx context.Context, baseURL, apiKey string) (*Export, error) {
+	if apiKey == "" {
+		return nil, errors.New("an API key is required")
+	}
+	parsed, err := url.Parse(strings.TrimSpace(baseURL))
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return nil, fmt.Errorf("invalid URL %q", baseURL)
+	}
+
+	c := &apiClient{
+		baseURL: strings.TrimSuffix(strings.TrimSuffix(parsed.String(), "/"), "/api/v1"),
+		apiKey:  apiKey,
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+
+	// Also checks the URL and API key before paging through everything
+	var settings struct {
+		DefaultPermissions int64 `json:"defaultPermissions"`
+	}
+	if err := c.get(ctx, "/settings/main", nil, &settings); err != nil {
+		return nil, err
+	}
+
+	users, err := c.users(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch users: %w", err)
+	}
+
+	requests, err := c.requests(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch requests: %w", err)
+	}
+
+	return &Export{
+		Users:              users,
+		Requests:           requests,
+		DefaultPermissions: &settings.DefaultPermissions,
+	}, nil
+}
+
+func (c *apiClient) users(ctx context.Context) ([]User, error) {
+	var users []User
+	for page := 0; ; page++ {
+		var response struct {
+			PageInfo apiPageInfo `json:"pageInfo"`
+			Results  []apiUser   `json:"results"`
+		}
+		params := url.Values{
+			"take": {strconv.Itoa(apiPageSize)},
+			"skip": {strconv.Itoa(page * apiPageSize)},
+			"sort": {"created"},
+		}
+		if err := c.get(ctx, "/user", params, &response); err != nil {
+			return nil, err
+		}
+
+		for _, result := range response.Results {
+			user := User{
+				ID:          result.ID,
+				Email:       result.Email,
+				Permissions: result.Permissions,
+				Username:    firstNonEmpty(result.Username, result.JellyfinUsername, result.PlexUsername, &result.Email),
+			}
+			if result.JellyfinUserID != nil {
+				user.MediaServerID = *result.JellyfinUserID
+			}
+			users = append(users, user)
+		}
+
+		if page+1 >= response.PageInfo.Pages || len(response.Results) == 0 {
+			return users, nil
+		}
+	}
+}
+
+func (c *apiClient) requests(ctx context.Context) ([]Request, error) {
+	var requests []Request
+	for page := 0; ; page++ {
+		var response struct {
+			PageInfo apiPageInfo  `json:"pageInfo"`
+			Results  []apiRequest `json:"results"`
+		}
+		params := url.Values{
+			"take":   {strconv.Itoa(apiPageSize)},
+			"skip":   {strconv.Itoa(page * apiPageSize)},
+			"filter": {"all"},
+			"sort":   {"added"},
+		}
+		if err := c.get(ctx, "/request", params, &response); err != nil {
+			return nil, err
+		}
+
+		for _, result := range response.Results {
+			request, err := result.toRequest()
+			if err != nil {
+				return nil, fmt.Errorf("request %d: %w", result.ID, err)
+			}
+			requests = append(requests, request)
+		}
+
+		if page+1 >= response.PageInfo.Pages || len(response.Results) == 0 {
+			return requests, nil
+		}
+	}
+}
+
+func (r apiRequest) toRequest() (Request, error) {
+	request := Request{
+		ID:          r.ID,
+		Status:      r.Status,
+		MediaType:   r.Type,
+		TmdbID:      r.Media.TmdbID,
+		Is4K:        r.Is4K,
+		MediaStatus: r.Media.Status,
+	}
+	if r.Is4K {
+		request.MediaStatus = r.Media.Status4K
+	}
+	if r.RequestedBy != nil {
+		request.RequestedBy = r.RequestedBy.ID
+	}
+	if r.ModifiedBy != nil {
+		request.ModifiedBy = r.ModifiedBy.ID
+	}
+
+	var err error
+	if request.CreatedAt, err = parseTime(r.CreatedAt); err != nil {
+		return Request{}, err
+	}
+	if request.UpdatedAt, err = parseTime(r.UpdatedAt); err != nil {
+		return Request{}, err
+	}
+
+	for _, season := range r.Seasons {
+		imported := SeasonRequest{Number: season.SeasonNumber, Status: season.Status}
+		for _, media := range r.Media.Seasons {
+			if media.SeasonNumber != season.SeasonNumber {
+				continue
+			}
+			status := media.Status
+			if r.Is4K {
+				status = media.Status4K
+			}
+			imported.Available = status == mediaAvailable
+		}
+		request.Seasons = append(request.Seasons, imported)
+	}
+
+	return request, nil
+}
+
+// get calls an API endpoint below /api/v1 and decodes the JSON response
+func (c *apiClient) get(ctx context.Context, path string, params url.Values, out interface{}) error {
+	endpoint := c.baseURL + "/api/v1" + path
+	if len(params) > 0 {
+		endpoint += "?" + params.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("X-Api-Key", c.apiKey)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return errors.New("the API key was rejected")
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("%s returned status %d", path, resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode %s response: %w", path, err)
+	}
+	return nil
+}
+
+func firstNonEmpty(values ...*string) string {
+	for _, value := range values {
+		if value != nil && *value != "" {
+			return *value
+		}
+	}
+	return ""
+}
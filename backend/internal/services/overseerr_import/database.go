@@ -0,0 +1,164 @@
+package overseerr_import
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// ReadDatabase reads users and requests from an Overseerr or Jellyseerr
+// db.sqlite3 file. The file is opened read-only.
+func ReadDatabase(ctx context.Context, path string) (*Export, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open("sqlite3", "file:"+filepath.ToSlash(path)+"?mode=ro")
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	var tables int
+	err = db.QueryRowContext(ctx, "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name IN ('user', 'media', 'media_request', 'season_request')").Scan(&tables)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read database: %w", err)
+	}
+	if tables != 4 {
+		return nil, errors.New("not an Overseerr or Jellyseerr database")
+	}
+
+	users, err := readUsers(ctx, db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read users: %w", err)
+	}
+
+	requests, err := readRequests(ctx, db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read requests: %w", err)
+	}
+
+	return &Export{Users: users, Requests: requests}, nil
+}
+
+func readUsers(ctx context.Context, db *sql.DB) ([]User, error) {
+	// Only Jellyseerr stores Jellyfin and Emby users, Overseerr knows Plex only
+	var jellyfinColumns int
+	err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM pragma_table_info('user') WHERE name IN ('jellyfinUserId', 'jellyfinUsername')").Scan(&jellyfinColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT id, COALESCE(username, plexUsername, email, ''), COALESCE(email, ''), '', permissions FROM user ORDER BY id`
+	if jellyfinColumns == 2 {
+		query = `SELECT id, COALESCE(username, jellyfinUsername, plexUsername, email, ''), COALESCE(email, ''), COALESCE(jellyfinUserId, ''), permissions FROM user ORDER BY id`
+	}
+
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []User
+	for rows.Next() {
+		var user User
+		if err := rows.Scan(&user.ID, &user.Username, &user.Email, &user.MediaServerID, &user.Permissions); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, rows.Err()
+}
+
+func readRequests(ctx context.Context, db *sql.DB) ([]Request, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT r.id, r.status, r.type, m.tmdbId, r.is4k,
+			CASE WHEN r.is4k THEN m.status4k ELSE m.status END,
+			COALESCE(r.requestedById, 0), COALESCE(r.modifiedById, 0),
+			CAST(r.createdAt AS TEXT), CAST(r.updatedAt AS TEXT)
+		FROM media_request r
+		JOIN media m ON m.id = r.mediaId
+		ORDER BY r.id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var requests []Request
+	index := make(map[int64]int)
+	for rows.Next() {
+		var (
+			request              Request
+			createdAt, updatedAt string
+		)
+		err := rows.Scan(&request.ID, &request.Status, &request.MediaType, &request.TmdbID, &request.Is4K,
+			&request.MediaStatus, &request.RequestedBy, &request.ModifiedBy, &createdAt, &updatedAt)
+		if err != nil {
+			return nil, err
+		}
+		if request.CreatedAt, err = parseTime(createdAt); err != nil {
+			return nil, err
+		}
+		if request.UpdatedAt, err = parseTime(updatedAt); err != nil {
+			return nil, err
+		}
+
+		index[request.ID] = len(requests)
+		requests = append(requests, request)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	seasons, err := db.QueryContext(ctx, `
+		SELECT sr.requestId, sr.seasonNumber, sr.status,
+			COALESCE(CASE WHEN r.is4k THEN s.status4k ELSE s.status END, 0)
+		FROM season_request sr
+		JOIN media_request r ON r.id = sr.requestId
+		LEFT JOIN season s ON s.mediaId = r.mediaId AND s.seasonNumber = sr.seasonNumber
+		ORDER BY sr.requestId, sr.seasonNumber`)
+	if err != nil {
+		return nil, err
+	}
+	defer seasons.Close()
+
+	for seasons.Next() {
+		var (
+			requestID    int64
+			season       SeasonRequest
+			availability int
+		)
+		if err := seasons.Scan(&requestID, &season.Number, &season.Status, &availability); err != nil {
+			return nil, err
+		}
+		season.Available = availability == mediaAvailable
+
+		if i, ok := index[requestID]; ok {
+			requests[i].Seasons = append(requests[i].Seasons, season)
+		}
+	}
+	return requests, seasons.Err()
+}
+
+// ReadSettings reads the default permissions from an Overseerr or Jellyseerr
+// settings.json. They aren't stored in the database.
+func ReadSettings(r io.Reader) (*int64, error) {
+	var settings struct {
+		Main struct {
+			DefaultPermissions *int64 `json:"defaultPermissions"`
+		} `json:"main"`
+	}
+	if err := json.NewDecoder(r).Decode(&settings); err != nil {
+		return nil, fmt.Errorf("invalid settings file: %w", err)
+	}
+	if settings.Main.DefaultPermissions == nil {
+		return nil, errors.New("settings file has no default permissions")
+	}
+	return settings.Main.DefaultPermissions, nil
+}
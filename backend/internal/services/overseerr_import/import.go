@@ -0,0 +1,472 @@
+package overseerr_import
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+
+	"github.com/mahcks/serra/internal/db/repository"
+	"github.com/mahcks/serra/pkg/permissions"
+	"github.com/mahcks/serra/pkg/structures"
+	"github.com/mahcks/serra/utils"
+)
+
+const tmdbPosterBaseURL = "https://image.tmdb.org/t/p/w500"
+
+// Titles looks up the title and poster of imported requests, which Overseerr
+// doesn't store. tmdb.Service satisfies it.
+type Titles interface {
+	GetMediaClassification(mediaType, id string) (structures.TMDBMediaClassification, error)
+}
+
+// Options control an import
+type Options struct {
+	// DryRun reports what would be imported without changing anything
+	DryRun bool
+	// CreateMissingUsers creates Serra users for Jellyfin and Emby accounts
+	// that haven't signed in to Serra yet, instead of skipping their requests
+	CreateMissingUsers bool
+}
+
+// Importer writes an Overseerr or Jellyseerr export into Serra
+type Importer struct {
+	db       *sql.DB
+	query    *repository.Queries
+	titles   Titles                                          // nil when TMDB isn't configured
+	priority func(ctx context.Context, userID string) string // Priority of new requests by user
+}
+
+func New(db *sql.DB, query *repository.Queries, titles Titles, priority func(ctx context.Context, userID string) string) *Importer {
+	return &Importer{
+		db:       db,
+		query:    query,
+		titles:   titles,
+		priority: priority,
+	}
+}
+
+// Import maps the export's users to Serra users by media server id, adds their
+// converted permissions and carries their requests over. User permissions are
+// only ever added and requests imported before are skipped, so importing the
+// same data again changes nothing.
+func (i *Importer) Import(ctx context.Context, source string, export *Export, opts Options) (structures.OverseerrImportReport, error) {
+	report := structures.OverseerrImportReport{
+		Source:   source,
+		DryRun:   opts.DryRun,
+		Users:    make([]structures.OverseerrImportUser, 0, len(export.Users)),
+		Requests: make([]structures.OverseerrImportRequest, 0, len(export.Requests)),
+	}
+
+	userIDs, err := i.importUsers(ctx, export.Users, opts, &report)
+	if err != nil {
+		return report, err
+	}
+
+	if export.DefaultPermissions != nil {
+		if err := i.importDefaultPermissions(ctx, *export.DefaultPermissions, opts, &report); err != nil {
+			return report, err
+		}
+	}
+
+	if err := i.importRequests(ctx, export.Requests, userIDs, opts, &report); err != nil {
+		return report, err
+	}
+
+	slog.Info("Overseerr import finished",
+		"source", source,
+		"dry_run", opts.DryRun,
+		"users_matched", report.UsersMatched,
+		"users_created", report.UsersCreated,
+		"users_unmatched", report.UsersUnmatched,
+		"requests_created", report.RequestsCreated,
+		"requests_skipped", report.RequestsSkipped,
+		"requests_failed", report.RequestsFailed)
+
+	return report, nil
+}
+
+// importUsers matches the export's users and returns the Serra user id of each
+// matched Overseerr user id
+func (i *Importer) importUsers(ctx context.Context, users []User, opts Options, report *structures.OverseerrImportReport) (map[int64]string, error) {
+	serraUsers, err := i.query.GetAllUsers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load users: %w", err)
+	}
+	byMediaServerID := make(map[string]string, len(serraUsers))
+	for _, user := range serraUsers {
+		byMediaServerID[normalizeID(user.ID)] = user.ID
+	}
+
+	userIDs := make(map[int64]string, len(users))
+	for _, user := range users {
+		item := structures.OverseerrImportUser{
+			ExternalID:    user.ID,
+			Username:      user.Username,
+			MediaServerID: user.MediaServerID,
+			Permissions:   ConvertPermissions(user.Permissions),
+		}
+
+		mediaServerID := normalizeID(user.MediaServerID)
+		serraID, found := byMediaServerID[mediaServerID]
+		switch {
+		case mediaServerID == "":
+			item.Result = structures.OverseerrUserUnmatched
+			item.Reason = "Not a Jellyfin or Emby user"
+		case found:
+			item.Result = structures.OverseerrUserMatched
+		case opts.CreateMissingUsers:
+			item.Result = structures.OverseerrUserCreated
+			serraID = mediaServerID
+		default:
+			item.Result = structures.OverseerrUserUnmatched
+			item.Reason = "Hasn't signed in to Serra yet"
+		}
+
+		if item.Result == structures.OverseerrUserUnmatched {
+			report.UsersUnmatched++
+			report.Users = append(report.Users, item)
+			continue
+		}
+
+		item.UserID = serraID
+		userIDs[user.ID] = serraID
+		byMediaServerID[mediaServerID] = serraID
+
+		if err := i.importUser(ctx, user, opts, &item); err != nil {
+			return nil, err
+		}
+
+		if item.Result == structures.OverseerrUserCreated {
+			report.UsersCreated++
+		} else {
+			report.UsersMatched++
+		}
+		report.Users = append(report.Users, item)
+	}
+
+	return userIDs, nil
+}
+
+// importUser creates the Serra user when needed and adds the converted
+// permissions it doesn't have yet
+func (i *Importer) importUser(ctx context.Context, user User, opts Options, item *structures.OverseerrImportUser) error {
+	held := make(map[string]bool)
+	if item.Result == structures.OverseerrUserMatched {
+		current, err := i.query.GetUserPermissions(ctx, item.UserID)
+		if err != nil {
+			return fmt.Errorf("failed to load permissions of user %s: %w", item.UserID, err)
+		}
+		for _, permission := range current {
+			held[permission.PermissionID] = true
+		}
+	}
+
+	for _, permission := range item.Permissions {
+		if !held[permission] {
+			item.AddedPermissions = append(item.AddedPermissions, permission)
+		}
+	}
+
+	if opts.DryRun {
+		return nil
+	}
+
+	if item.Result == structures.OverseerrUserCreated {
+		_, err := i.query.CreateUser(ctx, repository.CreateUserParams{
+			ID:           item.UserID,
+			Username:     user.Username,
+			AccessToken:  utils.NewNullString(""),
+			Email:        utils.NewNullString(user.Email),
+			AvatarUrl:    utils.NewNullString(""),
+			UserType:     "media_server",
+			PasswordHash: utils.NewNullString(""),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create user %s: %w", user.Username, err)
+		}
+	}
+
+	for _, permission := range item.AddedPermissions {
+		err := i.query.AssignUserPermission(ctx, repository.AssignUserPermissionParams{
+			UserID:       item.UserID,
+			PermissionID: permission,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to assign %s to user %s: %w", permission, item.UserID, err)
+		}
+	}
+	return nil
+}
+
+// importDefaultPermissions makes the permissions new users get match the
+// source's defaults. Owner is never a default.
+func (i *Importer) importDefaultPermissions(ctx context.Context, mask int64, opts Options, report *structures.OverseerrImportReport) error {
+	report.DefaultPermissions = ConvertPermissions(mask)
+	if opts.DryRun {
+		return nil
+	}
+
+	enabled := make(map[string]bool, len(report.DefaultPermissions))
+	for _, permission := range report.DefaultPermissions {
+		enabled[permission] = true
+	}
+	for _, permission := range permissions.AllPermissions {
+		if permission == permissions.Owner {
+			continue
+		}
+		err := i.query.UpdateDefaultPermission(ctx, repository.UpdateDefaultPermissionParams{
+			PermissionID: permission,
+			Enabled:      enabled[permission],
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update default permission %s: %w", permission, err)
+		}
+	}
+	return nil
+}
+
+func (i *Importer) importRequests(ctx context.Context, requests []Request, userIDs map[int64]string, opts Options, report *structures.OverseerrImportReport) error {
+	importedIDs, err := i.query.GetImportedRequestIDs(ctx, Source)
+	if err != nil {
+		return fmt.Errorf("failed to load imported requests: %w", err)
+	}
+	imported := make(map[int64]bool, len(importedIDs))
+	for _, id := range importedIDs {
+		imported[id] = true
+	}
+
+	// Requests this run creates, so a dry run catches duplicates within the export too
+	pending := make(map[repository.HasMatchingRequestParams]bool)
+
+	// Newest first, so a title requested again after being declined keeps the
+	// latest request
+	requests = append([]Request(nil), requests...)
+	sort.SliceStable(requests, func(a, b int) bool {
+		return requests[a].ID > requests[b].ID
+	})
+
+	for _, request := range requests {
+		item := structures.OverseerrImportRequest{
+			ExternalID: request.ID,
+			MediaType:  request.MediaType,
+			TmdbID:     request.TmdbID,
+			Is4K:       request.Is4K,
+			Status:     requestStatus(request),
+		}
+		for _, season := range request.Seasons {
+			item.Seasons = append(item.Seasons, season.Number)
+		}
+		sort.Ints(item.Seasons)
+
+		i.importRequest(ctx, request, userIDs, imported, pending, opts, &item)
+
+		switch item.Result {
+		case "created":
+			report.RequestsCreated++
+		case "skipped":
+			report.RequestsSkipped++
+		default:
+			report.RequestsFailed++
+		}
+		report.Requests = append(report.Requests, item)
+	}
+
+	return nil
+}
+
+func (i *Importer) importRequest(ctx context.Context, request Request, userIDs map[int64]string, imported map[int64]bool, pending map[repository.HasMatchingRequestParams]bool, opts Options, item *structures.OverseerrImportRequest) {
+	if imported[request.ID] {
+		item.Result = "skipped"
+		item.Reason = "Imported before"
+		return
+	}
+	if (request.MediaType != "movie" && request.MediaType != "tv") || request.TmdbID < 1 {
+		item.Result = "failed"
+		item.Reason = fmt.Sprintf("Media type '%s' is not supported", request.MediaType)
+		return
+	}
+
+	userID, ok := userIDs[request.RequestedBy]
+	if !ok {
+		item.Result = "skipped"
+		item.Reason = "The requesting user isn't matched to a Serra user"
+		return
+	}
+	item.UserID = userID
+	if item.Status != "pending" {
+		item.ApproverID = userIDs[request.ModifiedBy]
+	}
+
+	params := repository.ImportRequestParams{
+		UserID:     userID,
+		MediaType:  request.MediaType,
+		TmdbID:     sql.NullInt64{Int64: request.TmdbID, Valid: true},
+		Status:     item.Status,
+		Notes:      sql.NullString{String: fmt.Sprintf("Imported from Overseerr request #%d", request.ID), Valid: true},
+		Is4k:       request.Is4K,
+		ApproverID: utils.NewNullString(item.ApproverID),
+		CreatedAt:  request.CreatedAt,
+		UpdatedAt:  request.UpdatedAt,
+	}
+	if item.Status == "fulfilled" {
+		// Overseerr doesn't record when a title became available
+		params.FulfilledAt = sql.NullTime{Time: request.UpdatedAt, Valid: true}
+	}
+
+	if len(item.Seasons) > 0 {
+		seasonsJSON, err := json.Marshal(item.Seasons)
+		if err != nil {
+			item.Result = "failed"
+			item.Reason = "Failed to process seasons"
+			return
+		}
+		params.Seasons = sql.NullString{String: string(seasonsJSON), Valid: true}
+
+		seasonStatuses := make(map[string]structures.SeasonInfo, len(request.Seasons))
+		for _, season := range request.Seasons {
+			seasonStatuses[strconv.Itoa(season.Number)] = structures.SeasonInfo{
+				Status:   seasonStatus(season),
+				Episodes: "0/0", // Updated by the season availability sync
+			}
+		}
+		statusJSON, err := json.Marshal(seasonStatuses)
+		if err != nil {
+			item.Result = "failed"
+			item.Reason = "Failed to process season statuses"
+			return
+		}
+		params.SeasonStatuses = sql.NullString{String: string(statusJSON), Valid: true}
+	}
+
+	key := repository.HasMatchingRequestParams{
+		UserID:    params.UserID,
+		MediaType: params.MediaType,
+		TmdbID:    params.TmdbID,
+		Is4k:      params.Is4k,
+		Seasons:   params.Seasons,
+	}
+	matched, err := i.query.HasMatchingRequest(ctx, key)
+	if err != nil {
+		slog.Error("Failed to check for an existing request", "error", err, "user_id", userID, "tmdb_id", request.TmdbID)
+		item.Result = "failed"
+		item.Reason = "Failed to check for an existing request"
+		return
+	}
+	if matched || pending[key] {
+		item.Result = "skipped"
+		item.Reason = "Already requested"
+		return
+	}
+	pending[key] = true
+
+	if opts.DryRun {
+		item.Result = "created"
+		return
+	}
+
+	if i.titles != nil {
+		media, err := i.titles.GetMediaClassification(request.MediaType, strconv.FormatInt(request.TmdbID, 10))
+		if err != nil {
+			slog.Warn("Failed to look up imported request title", "error", err, "media_type", request.MediaType, "tmdb_id", request.TmdbID)
+		} else {
+			item.Title = media.Title
+			params.Title = utils.NewNullString(media.Title)
+			if media.PosterPath != "" {
+				params.PosterUrl = sql.NullString{String: tmdbPosterBaseURL + media.PosterPath, Valid: true}
+			}
+		}
+	}
+	if !params.Title.Valid {
+		// Requests need a title, even when TMDB isn't configured or the lookup failed
+		params.Title = utils.NewNullString("Unknown Title")
+	}
+	params.Priority = i.priority(ctx, userID)
+
+	requestID, err := i.createRequest(ctx, request.ID, params)
+	if err != nil {
+		slog.Error("Failed to import request", "error", err, "external_id", request.ID, "user_id", userID, "tmdb_id", request.TmdbID)
+		item.Result = "failed"
+		item.Reason = "Failed to create request"
+		return
+	}
+
+	item.Result = "created"
+	item.RequestID = &requestID
+}
+
+// createRequest creates the request and records it as imported together
+func (i *Importer) createRequest(ctx context.Context, externalID int64, params repository.ImportRequestParams) (int64, error) {
+	tx, err := i.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	query := i.query.WithTx(tx)
+	requestID, err := query.ImportRequest(ctx, params)
+	if err != nil {
+		return 0, err
+	}
+
+	err = query.RecordImportedRequest(ctx, repository.RecordImportedRequestParams{
+		Source:     Source,
+		ExternalID: externalID,
+		RequestID:  sql.NullInt64{Int64: requestID, Valid: true},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return requestID, tx.Commit()
+}
+
+// requestStatus converts an Overseerr request status. Approved requests whose
+// title is available count as fulfilled, as they would in Serra.
+func requestStatus(request Request) string {
+	switch request.Status {
+	case requestApproved:
+		if request.MediaStatus == mediaAvailable {
+			return "fulfilled"
+		}
+		if len(request.Seasons) > 0 {
+			for _, season := range request.Seasons {
+				if !season.Available {
+					return "approved"
+				}
+			}
+			return "fulfilled"
+		}
+		return "approved"
+	case requestDeclined:
+		return "denied"
+	case requestFailed:
+		return "failed"
+	case requestCompleted:
+		return "fulfilled"
+	default:
+		return "pending"
+	}
+}
+
+// seasonStatus converts the status of a requested season
+func seasonStatus(season SeasonRequest) string {
+	if season.Available {
+		return "fulfilled"
+	}
+	switch season.Status {
+	case requestApproved:
+		return "approved"
+	case requestDeclined:
+		return "denied"
+	case requestFailed:
+		return "failed"
+	case requestCompleted:
+		return "fulfilled"
+	default:
+		return "pending"
+	}
+}
@@ -0,0 +1,130 @@
+package overseerr_import
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mahcks/serra/internal/db/dbtest"
+	"github.com/mahcks/serra/pkg/permissions"
+)
+
+func TestRequestStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		request Request
+		want    string
+	}{
+		{"pending", Request{Status: requestPending}, "pending"},
+		{"unknown status", Request{Status: 42}, "pending"},
+		{"approved", Request{Status: requestApproved}, "approved"},
+		{"approved and available", Request{Status: requestApproved, MediaStatus: mediaAvailable}, "fulfilled"},
+		{"approved with a season missing", Request{Status: requestApproved, Seasons: []SeasonRequest{{Number: 1, Available: true}, {Number: 2}}}, "approved"},
+		{"approved with every season available", Request{Status: requestApproved, Seasons: []SeasonRequest{{Number: 1, Available: true}, {Number: 2, Available: true}}}, "fulfilled"},
+		{"declined", Request{Status: requestDeclined}, "denied"},
+		{"declined but available", Request{Status: requestDeclined, MediaStatus: mediaAvailable}, "denied"},
+		{"failed", Request{Status: requestFailed}, "failed"},
+		{"completed", Request{Status: requestCompleted}, "fulfilled"},
+	}
+	for _, test := range tests {
+		if got := requestStatus(test.request); got != test.want {
+			t.Errorf("%s: status = %s, want %s", test.name, got, test.want)
+		}
+	}
+}
+
+func TestImportIsIdempotent(t *testing.T) {
+	db, query := dbtest.Open(t)
+	ctx := context.Background()
+
+	// Serra user ids are the Jellyfin ids, without dashes
+	if _, err := db.Exec(`INSERT INTO users (id, username) VALUES ('0a1b2c3d', 'alice')`); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+
+	created := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	defaults := permissionRequestMovie
+	export := &Export{
+		Users: []User{
+			{ID: 1, Username: "alice", MediaServerID: "0A1B-2C3D", Permissions: permissionRequest | permissionManageRequests},
+			{ID: 2, Username: "bob", MediaServerID: "4e5f-6a7b", Permissions: permissionRequestMovie},
+			{ID: 3, Username: "plex", Permissions: permissionRequest},
+		},
+		Requests: []Request{
+			{ID: 10, Status: requestApproved, MediaType: "movie", TmdbID: 603, RequestedBy: 1, ModifiedBy: 1, CreatedAt: created, UpdatedAt: created},
+			{ID: 11, Status: requestPending, MediaType: "tv", TmdbID: 1399, RequestedBy: 2, CreatedAt: created, UpdatedAt: created,
+				Seasons: []SeasonRequest{{Number: 2, Status: requestPending}, {Number: 1, Status: requestApproved, Available: true}}},
+			{ID: 12, Status: requestPending, MediaType: "movie", TmdbID: 604, RequestedBy: 3, CreatedAt: created, UpdatedAt: created},
+			{ID: 13, Status: requestPending, MediaType: "music", TmdbID: 1, RequestedBy: 1, CreatedAt: created, UpdatedAt: created},
+			// The same title again, only the newest request is kept
+			{ID: 14, Status: requestDeclined, MediaType: "movie", TmdbID: 603, RequestedBy: 1, ModifiedBy: 1, CreatedAt: created, UpdatedAt: created},
+		},
+		DefaultPermissions: &defaults,
+	}
+	importer := New(db, query, nil, func(context.Context, string) string { return "normal" })
+	opts := Options{CreateMissingUsers: true}
+
+	count := func(table string) int {
+		t.Helper()
+		var n int
+		if err := db.QueryRow(`SELECT COUNT(*) FROM ` + table).Scan(&n); err != nil {
+			t.Fatalf("count %s: %v", table, err)
+		}
+		return n
+	}
+
+	first, err := importer.Import(ctx, "database", export, opts)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if first.UsersMatched != 1 || first.UsersCreated != 1 || first.UsersUnmatched != 1 {
+		t.Errorf("users matched/created/unmatched = %d/%d/%d, want 1/1/1", first.UsersMatched, first.UsersCreated, first.UsersUnmatched)
+	}
+	if first.RequestsCreated != 2 || first.RequestsSkipped != 2 || first.RequestsFailed != 1 {
+		t.Errorf("requests created/skipped/failed = %d/%d/%d, want 2/2/1", first.RequestsCreated, first.RequestsSkipped, first.RequestsFailed)
+	}
+	statuses := make(map[int64]string)
+	for _, item := range first.Requests {
+		if item.Result == "created" {
+			statuses[item.ExternalID] = item.Status
+		}
+	}
+	if statuses[14] != "denied" || statuses[11] != "pending" || len(statuses) != 2 {
+		t.Errorf("created %v, want 14 denied and 11 pending", statuses)
+	}
+
+	held, err := query.GetUserPermissions(ctx, "4e5f6a7b")
+	if err != nil || len(held) != 1 || held[0].PermissionID != permissions.RequestMovies {
+		t.Errorf("bob's permissions = %+v (%v), want request.movies", held, err)
+	}
+
+	requests, grants, imported := count("requests"), count("user_permissions"), count("imported_requests")
+
+	// Importing the same export again changes nothing
+	second, err := importer.Import(ctx, "database", export, opts)
+	if err != nil {
+		t.Fatalf("second import: %v", err)
+	}
+	if second.UsersMatched != 2 || second.UsersCreated != 0 {
+		t.Errorf("second run matched %d and created %d users, want 2 and 0", second.UsersMatched, second.UsersCreated)
+	}
+	for _, user := range second.Users {
+		if len(user.AddedPermissions) != 0 {
+			t.Errorf("second run added %v to %s", user.AddedPermissions, user.Username)
+		}
+	}
+	if second.RequestsCreated != 0 || second.RequestsSkipped != 4 || second.RequestsFailed != 1 {
+		t.Errorf("second run requests created/skipped/failed = %d/%d/%d, want 0/4/1", second.RequestsCreated, second.RequestsSkipped, second.RequestsFailed)
+	}
+	for _, item := range second.Requests {
+		if (item.ExternalID == 11 || item.ExternalID == 14) && item.Reason != "Imported before" {
+			t.Errorf("request %d skipped because %q, want imported before", item.ExternalID, item.Reason)
+		}
+	}
+	if count("requests") != requests || count("user_permissions") != grants || count("imported_requests") != imported {
+		t.Error("second run changed the database")
+	}
+	if len(second.DefaultPermissions) != 1 || second.DefaultPermissions[0] != permissions.RequestMovies {
+		t.Errorf("default permissions = %v, want request.movies", second.DefaultPermissions)
+	}
+}
@@ -0,0 +1,88 @@
+// Package overseerr_import brings users, requests and default permissions over
+// from Overseerr or Jellyseerr, read from a database file or a running
+// instance's API.
+package overseerr_import
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Source identifies imported Overseerr and Jellyseerr requests, so a re-run
+// skips the ones imported before
+const Source = "overseerr"
+
+// Overseerr request statuses
+const (
+	requestPending   = 1
+	requestApproved  = 2
+	requestDeclined  = 3
+	requestFailed    = 4
+	requestCompleted = 5
+)
+
+// mediaAvailable is the Overseerr media status of a title in the library
+const mediaAvailable = 5
+
+// Export is the data read from Overseerr or Jellyseerr
+type Export struct {
+	Users    []User
+	Requests []Request
+
+	// Permissions new users get, nil when the source doesn't include them
+	DefaultPermissions *int64
+}
+
+// User is an Overseerr user
+type User struct {
+	ID            int64
+	Username      string
+	Email         string
+	MediaServerID string // Jellyfin or Emby user id, empty for Plex and local users
+	Permissions   int64
+}
+
+// Request is an Overseerr movie or TV request
+type Request struct {
+	ID          int64
+	Status      int
+	MediaType   string // movie or tv
+	TmdbID      int64
+	Is4K        bool
+	MediaStatus int // Availability of the title, in 4K for 4K requests
+	RequestedBy int64
+	ModifiedBy  int64 // Who approved or declined the request, 0 when nobody did
+	Seasons     []SeasonRequest
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// SeasonRequest is a season of a TV request
+type SeasonRequest struct {
+	Number    int
+	Status    int
+	Available bool
+}
+
+// normalizeID makes media server ids comparable. Jellyfin ids are GUIDs that
+// show up with and without dashes.
+func normalizeID(id string) string {
+	return strings.ToLower(strings.ReplaceAll(id, "-", ""))
+}
+
+// parseTime reads the timestamps of the database, stored as UTC text, and of
+// the API, sent as RFC 3339
+func parseTime(value string) (time.Time, error) {
+	layouts := []string{
+		time.RFC3339Nano,
+		"2006-01-02 15:04:05.000",
+		"2006-01-02 15:04:05",
+	}
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid timestamp %q", value)
+}
@@ -0,0 +1,82 @@
+package overseerr_import
+
+import (
+	"github.com/mahcks/serra/pkg/permissions"
+)
+
+// Overseerr permission bits. Jellyseerr uses the same values.
+const (
+	permissionAdmin              int64 = 2
+	permissionManageSettings     int64 = 4
+	permissionManageUsers        int64 = 8
+	permissionManageRequests     int64 = 16
+	permissionRequest            int64 = 32
+	permissionAutoApprove        int64 = 128
+	permissionAutoApproveMovie   int64 = 256
+	permissionAutoApproveTV      int64 = 512
+	permissionRequest4K          int64 = 1024
+	permissionRequest4KMovie     int64 = 2048
+	permissionRequest4KTV        int64 = 4096
+	permissionRequestAdvanced    int64 = 8192
+	permissionRequestView        int64 = 16384
+	permissionAutoApprove4K      int64 = 32768
+	permissionAutoApprove4KMovie int64 = 65536
+	permissionAutoApprove4KTV    int64 = 131072
+	permissionRequestMovie       int64 = 262144
+	permissionRequestTV          int64 = 524288
+)
+
+// permissionMap lists the Serra permissions each Overseerr bit grants. Bits
+// without a Serra equivalent, e.g. issues, voting and auto-requests, are
+// dropped.
+var permissionMap = []struct {
+	bit    int64
+	grants []string
+}{
+	{permissionManageSettings, []string{permissions.AdminSystem, permissions.AdminServices}},
+	{permissionManageUsers, []string{permissions.AdminUsers}},
+	{permissionManageRequests, []string{permissions.RequestsView, permissions.RequestsApprove, permissions.RequestsManage}},
+	{permissionRequest, []string{permissions.RequestMovies, permissions.RequestSeries}},
+	{permissionRequestMovie, []string{permissions.RequestMovies}},
+	{permissionRequestTV, []string{permissions.RequestSeries}},
+	{permissionAutoApprove, []string{permissions.RequestAutoApproveMovies, permissions.RequestAutoApproveSeries}},
+	{permissionAutoApproveMovie, []string{permissions.RequestAutoApproveMovies}},
+	{permissionAutoApproveTV, []string{permissions.RequestAutoApproveSeries}},
+	{permissionRequest4K, []string{permissions.Request4KMovies, permissions.Request4KSeries}},
+	{permissionRequest4KMovie, []string{permissions.Request4KMovies}},
+	{permissionRequest4KTV, []string{permissions.Request4KSeries}},
+	{permissionAutoApprove4K, []string{permissions.RequestAutoApprove4KMovies, permissions.RequestAutoApprove4KSeries}},
+	{permissionAutoApprove4KMovie, []string{permissions.RequestAutoApprove4KMovies}},
+	{permissionAutoApprove4KTV, []string{permissions.RequestAutoApprove4KSeries}},
+	{permissionRequestAdvanced, []string{permissions.RequestAdvanced}},
+	{permissionRequestView, []string{permissions.RequestsView}},
+}
+
+// ConvertPermissions turns an Overseerr permission bitmask into Serra
+// permissions, in the order of permissions.AllPermissions. The Overseerr admin
+// bit grants every permission except owner, which is never imported.
+func ConvertPermissions(mask int64) []string {
+	granted := make(map[string]bool)
+	if mask&permissionAdmin != 0 {
+		for _, permission := range permissions.AllPermissions {
+			granted[permission] = true
+		}
+	}
+	for _, entry := range permissionMap {
+		if mask&entry.bit == 0 {
+			continue
+		}
+		for _, permission := range entry.grants {
+			granted[permission] = true
+		}
+	}
+	delete(granted, permissions.Owner)
+
+	converted := make([]string, 0, len(granted))
+	for _, permission := range permissions.AllPermissions {
+		if granted[permission] {
+			converted = append(converted, permission)
+		}
+	}
+	return converted
+}
@@ -0,0 +1,53 @@
+package overseerr_import
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/mahcks/serra/pkg/permissions"
+)
+
+func TestConvertPermissions(t *testing.T) {
+	tests := []struct {
+		name string
+		mask int64
+		want []string
+	}{
+		{"none", 0, []string{}},
+		{"request", permissionRequest, []string{permissions.RequestMovies, permissions.RequestSeries}},
+		{"request movies", permissionRequestMovie, []string{permissions.RequestMovies}},
+		{"request tv", permissionRequestTV, []string{permissions.RequestSeries}},
+		{"request 4k", permissionRequest4K, []string{permissions.Request4KMovies, permissions.Request4KSeries}},
+		{"request 4k movies", permissionRequest4KMovie, []string{permissions.Request4KMovies}},
+		{"request 4k tv", permissionRequest4KTV, []string{permissions.Request4KSeries}},
+		{"advanced requests", permissionRequestAdvanced, []string{permissions.RequestAdvanced}},
+		{"auto-approve", permissionAutoApprove, []string{permissions.RequestAutoApproveMovies, permissions.RequestAutoApproveSeries}},
+		{"auto-approve movies", permissionAutoApproveMovie, []string{permissions.RequestAutoApproveMovies}},
+		{"auto-approve tv", permissionAutoApproveTV, []string{permissions.RequestAutoApproveSeries}},
+		{"auto-approve 4k", permissionAutoApprove4K, []string{permissions.RequestAutoApprove4KMovies, permissions.RequestAutoApprove4KSeries}},
+		{"auto-approve 4k movies", permissionAutoApprove4KMovie, []string{permissions.RequestAutoApprove4KMovies}},
+		{"auto-approve 4k tv", permissionAutoApprove4KTV, []string{permissions.RequestAutoApprove4KSeries}},
+		{"view requests", permissionRequestView, []string{permissions.RequestsView}},
+		{"manage requests", permissionManageRequests, []string{permissions.RequestsView, permissions.RequestsApprove, permissions.RequestsManage}},
+		{"manage users", permissionManageUsers, []string{permissions.AdminUsers}},
+		{"manage settings", permissionManageSettings, []string{permissions.AdminServices, permissions.AdminSystem}},
+		// Issues and voting have no Serra equivalent
+		{"unmapped bits", 64 | 1<<20 | 1<<21, []string{}},
+		{"combined and overlapping", permissionRequest | permissionRequestMovie | permissionRequestView | permissionManageRequests,
+			[]string{permissions.RequestMovies, permissions.RequestSeries, permissions.RequestsView, permissions.RequestsApprove, permissions.RequestsManage}},
+	}
+	for _, test := range tests {
+		if got := ConvertPermissions(test.mask); !slices.Equal(got, test.want) {
+			t.Errorf("%s: ConvertPermissions(%d) = %v, want %v", test.name, test.mask, got, test.want)
+		}
+	}
+
+	// Admins get everything but owner
+	admin := ConvertPermissions(permissionAdmin)
+	want := slices.DeleteFunc(slices.Clone(permissions.AllPermissions), func(permission string) bool {
+		return permission == permissions.Owner
+	})
+	if !slices.Equal(admin, want) {
+		t.Errorf("ConvertPermissions(admin) = %v, want %v", admin, want)
+	}
+}
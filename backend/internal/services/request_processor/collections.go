@@ -0,0 +1,309 @@
+package request_processor
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/mahcks/serra/internal/db/repository"
+	apiErrors "github.com/mahcks/serra/pkg/api_errors"
+	"github.com/mahcks/serra/pkg/permissions"
+	"github.com/mahcks/serra/pkg/structures"
+	"github.com/mahcks/serra/utils"
+)
+
+const tmdbPosterBaseURL = "https://image.tmdb.org/t/p/w500"
+
+// RequestCollection creates a movie request for every part of a TMDB collection
+// that isn't in the library or requested yet. Unreleased parts are skipped, or
+// watched and requested on release when the input asks for it. Approved
+// requests are not processed here; the caller decides how to process them.
+func (s *service) RequestCollection(ctx context.Context, input structures.CollectionRequestInput) (*structures.CollectionRequestResult, error) {
+	if s.tmdbService == nil {
+		return nil, apiErrors.ErrInternalServerError().SetDetail("TMDB is not configured")
+	}
+
+	collection, err := s.tmdbService.GetCollection(strconv.FormatInt(input.CollectionID, 10))
+	if err != nil {
+		slog.Error("Failed to get collection from TMDB", "collection_id", input.CollectionID, "error", err)
+		return nil, apiErrors.ErrNotFound().SetDetail("Collection not found")
+	}
+	if len(collection.Parts) == 0 {
+		return nil, apiErrors.ErrBadRequest().SetDetail("Collection has no movies")
+	}
+
+	group, err := s.repo.UpsertCollectionRequest(ctx, repository.UpsertCollectionRequestParams{
+		CollectionID:    input.CollectionID,
+		UserID:          input.UserID,
+		Name:            collection.Name,
+		PosterUrl:       posterURL(collection.PosterPath),
+		Is4k:            input.Is4K,
+		WatchUnreleased: input.WatchUnreleased,
+	})
+	if err != nil {
+		slog.Error("Failed to save collection request", "collection_id", input.CollectionID, "error", err)
+		return nil, apiErrors.ErrInternalServerError().SetDetail("Failed to create collection request")
+	}
+
+	items, err := s.requestCollectionParts(ctx, group, collection.Parts, input.AutoApprove)
+	if err != nil {
+		return nil, apiErrors.ErrInternalServerError().SetDetail("Failed to create collection request")
+	}
+
+	return &structures.CollectionRequestResult{
+		ID:              group.ID,
+		CollectionID:    group.CollectionID,
+		Name:            group.Name,
+		Is4K:            group.Is4k,
+		WatchUnreleased: group.WatchUnreleased,
+		Items:           items,
+	}, nil
+}
+
+// RequestReleasedCollectionParts requests the parts of watched collections that
+// have been released since the collection was requested. Approved requests are
+// processed straight away. It returns the requests that were created.
+func (s *service) RequestReleasedCollectionParts(ctx context.Context) ([]repository.Request, error) {
+	if s.tmdbService == nil {
+		return nil, nil
+	}
+
+	groups, err := s.repo.GetWatchedCollectionRequests(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get watched collection requests: %w", err)
+	}
+
+	var created []repository.Request
+	for _, group := range groups {
+		select {
+		case <-ctx.Done():
+			return created, ctx.Err()
+		default:
+		}
+
+		requests, err := s.requestReleasedCollectionParts(ctx, group)
+		created = append(created, requests...)
+		if err != nil {
+			slog.Error("Failed to request released collection parts",
+				"collection_request_id", group.ID,
+				"collection_id", group.CollectionID,
+				"error", err)
+		}
+	}
+
+	return created, nil
+}
+
+func (s *service) requestReleasedCollectionParts(ctx context.Context, group repository.CollectionRequest) ([]repository.Request, error) {
+	requestPermission := utils.Ternary(group.Is4k, permissions.Request4KMovies, permissions.RequestMovies)
+	canRequest, err := s.hasPermission(ctx, group.UserID, requestPermission)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check request permission: %w", err)
+	}
+	if !canRequest {
+		return nil, nil
+	}
+
+	autoApprovalPermission := utils.Ternary(group.Is4k, permissions.RequestAutoApprove4KMovies, permissions.RequestAutoApproveMovies)
+	autoApproved, err := s.hasPermission(ctx, group.UserID, autoApprovalPermission)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check auto-approval permission: %w", err)
+	}
+
+	collection, err := s.tmdbService.GetCollection(strconv.FormatInt(group.CollectionID, 10))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get collection from TMDB: %w", err)
+	}
+
+	items, err := s.requestCollectionParts(ctx, group, collection.Parts, autoApproved)
+	if err != nil {
+		return nil, err
+	}
+
+	var created []repository.Request
+	for _, item := range items {
+		if item.Result != structures.CollectionItemRequested || item.RequestID == nil {
+			continue
+		}
+
+		request, err := s.repo.GetRequestByID(ctx, *item.RequestID)
+		if err != nil {
+			slog.Error("Failed to get collection part request", "request_id", *item.RequestID, "error", err)
+			continue
+		}
+		created = append(created, request)
+
+		slog.Info("Requested released collection part",
+			"request_id", request.ID,
+			"collection_id", group.CollectionID,
+			"tmdb_id", item.TmdbID,
+			"release_date", item.ReleaseDate,
+			"status", request.Status)
+
+		if request.Status == "approved" {
+			if err := s.ProcessApprovedRequest(ctx, request.ID); err != nil {
+				slog.Error("Failed to process collection part request",
+					"request_id", request.ID,
+					"error", err)
+			}
+		}
+	}
+
+	return created, nil
+}
+
+// requestCollectionParts requests each part of a collection for the group's
+// user and links the created requests to the group. Parts beyond the user's
+// movie request limit are skipped. A failure on one part is reported in its
+// result and doesn't stop the others.
+func (s *service) requestCollectionParts(ctx context.Context, group repository.CollectionRequest, parts []structures.TMDBMediaItem, autoApprove bool) ([]structures.CollectionRequestItemResult, error) {
+	linkedItems, err := s.repo.GetCollectionRequestItemsByCollectionRequestID(ctx, group.ID)
+	if err != nil {
+		slog.Error("Failed to get collection request items", "collection_request_id", group.ID, "error", err)
+		return nil, err
+	}
+	linked := make(map[int64]int64, len(linkedItems))
+	for _, item := range linkedItems {
+		linked[item.TmdbID] = item.RequestID
+	}
+
+	today := time.Now().UTC().Format("2006-01-02")
+	status := utils.Ternary(autoApprove, "approved", "pending")
+
+	items := make([]structures.CollectionRequestItemResult, 0, len(parts))
+	for _, part := range parts {
+		item := structures.CollectionRequestItemResult{
+			TmdbID:      part.ID,
+			Title:       part.Title,
+			ReleaseDate: part.ReleaseDate,
+		}
+
+		if requestID, ok := linked[part.ID]; ok {
+			item.Result = structures.CollectionItemAlreadyRequested
+			item.RequestID = &requestID
+			items = append(items, item)
+			continue
+		}
+
+		result, requestID, err := s.collectionPartState(ctx, part, group.Is4k)
+		if err != nil {
+			slog.Error("Failed to check collection part", "tmdb_id", part.ID, "error", err)
+			item.Result = structures.CollectionItemFailed
+			item.Error = "Failed to check library and existing requests"
+			items = append(items, item)
+			continue
+		}
+		if result != "" {
+			item.Result = result
+			item.RequestID = requestID
+			items = append(items, item)
+			continue
+		}
+
+		// TMDB release dates are ISO dates, so they compare as strings
+		if part.ReleaseDate == "" || part.ReleaseDate > today {
+			item.Result = utils.Ternary(group.WatchUnreleased, structures.CollectionItemWatching, structures.CollectionItemUnreleased)
+			items = append(items, item)
+			continue
+		}
+
+		withinLimit, err := s.withinRequestLimit(ctx, group.UserID, "movie")
+		if err != nil {
+			slog.Error("Failed to check movie request limit", "user_id", group.UserID, "error", err)
+			item.Result = structures.CollectionItemFailed
+			item.Error = "Failed to check the request limit"
+			items = append(items, item)
+			continue
+		}
+		if !withinLimit {
+			item.Result = structures.CollectionItemLimitReached
+			items = append(items, item)
+			continue
+		}
+
+		request, err := s.repo.CreateRequest(ctx, repository.CreateRequestParams{
+			UserID:    group.UserID,
+			MediaType: "movie",
+			TmdbID:    sql.NullInt64{Int64: part.ID, Valid: true},
+			Title:     sql.NullString{String: part.Title, Valid: true},
+			Status:    status,
+			Notes:     sql.NullString{String: fmt.Sprintf("Requested as part of %s", group.Name), Valid: true},
+			PosterUrl: posterURL(part.PosterPath),
+			Is4k:      group.Is4k,
+			Priority:  s.RequestPriority(ctx, group.UserID),
+		})
+		if err != nil {
+			slog.Error("Failed to create collection part request", "tmdb_id", part.ID, "error", err)
+			item.Result = structures.CollectionItemFailed
+			item.Error = "Failed to create request"
+			items = append(items, item)
+			continue
+		}
+
+		if err := s.repo.AddCollectionRequestItem(ctx, repository.AddCollectionRequestItemParams{
+			RequestID:           request.ID,
+			CollectionRequestID: group.ID,
+			TmdbID:              part.ID,
+		}); err != nil {
+			// The request itself exists, it just won't be grouped
+			slog.Error("Failed to link request to collection request",
+				"request_id", request.ID,
+				"collection_request_id", group.ID,
+				"error", err)
+		}
+
+		item.Result = structures.CollectionItemRequested
+		item.RequestID = &request.ID
+		item.Status = request.Status
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// collectionPartState reports whether a collection part is already in the
+// library or requested by anyone at the same quality. It returns an empty
+// result when the part still needs to be requested.
+func (s *service) collectionPartState(ctx context.Context, part structures.TMDBMediaItem, is4K bool) (string, *int64, error) {
+	tmdbID := sql.NullString{String: strconv.FormatInt(part.ID, 10), Valid: true}
+	var inLibrary bool
+	var err error
+	if is4K {
+		inLibrary, err = s.repo.CheckMedia4KInLibrary(ctx, tmdbID)
+	} else {
+		inLibrary, err = s.repo.CheckMediaInLibrary(ctx, tmdbID)
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to check library: %w", err)
+	}
+	if inLibrary {
+		return structures.CollectionItemInLibrary, nil, nil
+	}
+
+	existing, err := s.repo.GetRequestsByTMDBIDAndMediaType(ctx, repository.GetRequestsByTMDBIDAndMediaTypeParams{
+		TmdbID:    sql.NullInt64{Int64: part.ID, Valid: true},
+		MediaType: "movie",
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get existing requests: %w", err)
+	}
+	for _, request := range existing {
+		if request.Is4k == is4K && request.Status != "denied" {
+			requestID := request.ID
+			return structures.CollectionItemAlreadyRequested, &requestID, nil
+		}
+	}
+
+	return "", nil, nil
+}
+
+// posterURL builds a full TMDB poster URL from a poster path
+func posterURL(path string) sql.NullString {
+	if path == "" {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: tmdbPosterBaseURL + path, Valid: true}
+}
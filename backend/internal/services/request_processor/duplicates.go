@@ -0,0 +1,230 @@
+package request_processor
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/mahcks/serra/internal/db/repository"
+	apiErrors "github.com/mahcks/serra/pkg/api_errors"
+	"github.com/mahcks/serra/pkg/structures"
+	"github.com/mahcks/serra/utils"
+)
+
+// CheckDuplicateRequest checks for duplicate requests with sophisticated season handling.
+// 4K and regular requests are tracked separately, so only requests of the same
+// quality conflict.
+func (s *service) CheckDuplicateRequest(ctx context.Context, userID, mediaType string, tmdbID int64, requestedSeasons []int, requestedEpisodes structures.RequestedEpisodes, is4K bool) error {
+	var seasonsJSON sql.NullString
+	if len(requestedSeasons) > 0 {
+		seasonsBytes, err := json.Marshal(requestedSeasons)
+		if err != nil {
+			slog.Error("Failed to marshal seasons", "error", err)
+			return apiErrors.ErrInternalServerError().SetDetail("Failed to process season data")
+		}
+		seasonsJSON = sql.NullString{String: string(seasonsBytes), Valid: true}
+	}
+
+	var episodesJSON sql.NullString
+	if len(requestedEpisodes) > 0 {
+		episodesBytes, err := json.Marshal(requestedEpisodes)
+		if err != nil {
+			slog.Error("Failed to marshal episodes", "error", err)
+			return apiErrors.ErrInternalServerError().SetDetail("Failed to process episode data")
+		}
+		episodesJSON = sql.NullString{String: string(episodesBytes), Valid: true}
+	}
+
+	// For movies, check exact duplicate (including null seasons)
+	if mediaType == "movie" {
+		existingRequest, err := s.repo.CheckExistingRequest(ctx, repository.CheckExistingRequestParams{
+			MediaType: mediaType,
+			TmdbID:    sql.NullInt64{Int64: tmdbID, Valid: true},
+			UserID:    userID,
+			Seasons:   seasonsJSON,
+			Is4k:      is4K,
+		})
+
+		if err == nil {
+			// Exact duplicate found
+			return apiErrors.ErrConflict().SetDetail(fmt.Sprintf("You already have a %s %srequest for this movie", existingRequest.Status, utils.Ternary(is4K, "4K ", "")))
+		} else if err != sql.ErrNoRows {
+			// Database error
+			slog.Error("Failed to check existing movie request", "error", err)
+			return apiErrors.ErrInternalServerError().SetDetail("Failed to check existing request")
+		}
+		return nil // No duplicate found
+	}
+
+	// For TV shows, we need more sophisticated checking
+	if mediaType == "tv" {
+		// First, check for exact duplicate (same seasons)
+		existingRequest, err := s.repo.CheckExistingRequest(ctx, repository.CheckExistingRequestParams{
+			MediaType: mediaType,
+			TmdbID:    sql.NullInt64{Int64: tmdbID, Valid: true},
+			UserID:    userID,
+			Seasons:   seasonsJSON,
+			Episodes:  episodesJSON,
+			Is4k:      is4K,
+		})
+
+		if err == nil {
+			// Exact duplicate found
+			return apiErrors.ErrConflict().SetDetail(fmt.Sprintf("You already have a %s %srequest for these exact seasons", existingRequest.Status, utils.Ternary(is4K, "4K ", "")))
+		} else if err != sql.ErrNoRows {
+			// Database error
+			slog.Error("Failed to check existing TV request", "error", err)
+			return apiErrors.ErrInternalServerError().SetDetail("Failed to check existing request")
+		}
+
+		// Check for conflicting requests (overlapping seasons or whole series requests)
+		existingRequests, err := s.repo.CheckExistingRequestAnySeasons(ctx, repository.CheckExistingRequestAnySeasonsParams{
+			MediaType: mediaType,
+			TmdbID:    sql.NullInt64{Int64: tmdbID, Valid: true},
+			UserID:    userID,
+			Is4k:      is4K,
+		})
+
+		if err != nil && err != sql.ErrNoRows {
+			slog.Error("Failed to check existing TV requests", "error", err)
+			return apiErrors.ErrInternalServerError().SetDetail("Failed to check existing requests")
+		}
+
+		// Analyze conflicts
+		for _, existing := range existingRequests {
+			conflict, message := checkSeasonConflict(requestedSeasons, requestedEpisodes, existing)
+			if conflict {
+				return apiErrors.ErrConflict().SetDetail(message)
+			}
+		}
+
+		return nil // No conflicts found
+	}
+
+	return nil
+}
+
+// checkSeasonConflict checks if the requested seasons or episodes conflict with an existing request
+func checkSeasonConflict(requestedSeasons []int, requestedEpisodes structures.RequestedEpisodes, existing repository.Request) (bool, string) {
+	existingSeasonsJSON := existing.Seasons.String
+	existingStatus := existing.Status
+
+	// Episode requests only conflict on the seasons or episodes they share
+	if len(requestedEpisodes) > 0 || existing.Episodes.String != "" {
+		return checkEpisodeConflict(requestedSeasons, requestedEpisodes, existing)
+	}
+
+	// If no seasons requested, this is a whole series request
+	if len(requestedSeasons) == 0 {
+		if existingSeasonsJSON == "" {
+			// Both are whole series requests
+			return true, fmt.Sprintf("You already have a %s request for the entire series", existingStatus)
+		}
+		// Requesting whole series but existing is season-specific
+		return true, fmt.Sprintf("You already have a %s request for specific seasons. Cannot request entire series", existingStatus)
+	}
+
+	// If existing request has no seasons, it's a whole series request
+	if existingSeasonsJSON == "" {
+		return true, fmt.Sprintf("You already have a %s request for the entire series", existingStatus)
+	}
+
+	// Both are season-specific, check for overlaps
+	var existingSeasons []int
+	if err := json.Unmarshal([]byte(existingSeasonsJSON), &existingSeasons); err != nil {
+		slog.Error("Failed to unmarshal existing seasons", "error", err)
+		// If we can't parse, assume conflict to be safe
+		return true, "Cannot determine season conflict due to data error"
+	}
+
+	// Check for overlapping seasons
+	requestedSet := make(map[int]bool)
+	for _, season := range requestedSeasons {
+		requestedSet[season] = true
+	}
+
+	var overlapping []int
+	for _, season := range existingSeasons {
+		if requestedSet[season] {
+			overlapping = append(overlapping, season)
+		}
+	}
+
+	if len(overlapping) > 0 {
+		return true, fmt.Sprintf("You already have a %s request for season(s) %v", existingStatus, overlapping)
+	}
+
+	return false, ""
+}
+
+// checkEpisodeConflict checks if a request conflicts with an existing request
+// when either of them is for individual episodes
+func checkEpisodeConflict(requestedSeasons []int, requestedEpisodes structures.RequestedEpisodes, existing repository.Request) (bool, string) {
+	var existingEpisodes structures.RequestedEpisodes
+	if existing.Episodes.String != "" {
+		if err := json.Unmarshal([]byte(existing.Episodes.String), &existingEpisodes); err != nil {
+			slog.Error("Failed to unmarshal existing episodes", "error", err)
+			return true, "Cannot determine episode conflict due to data error"
+		}
+	}
+
+	var existingSeasons []int
+	if len(existingEpisodes) == 0 && existing.Seasons.String != "" {
+		if err := json.Unmarshal([]byte(existing.Seasons.String), &existingSeasons); err != nil {
+			slog.Error("Failed to unmarshal existing seasons", "error", err)
+			return true, "Cannot determine season conflict due to data error"
+		}
+	}
+
+	switch {
+	case len(requestedEpisodes) == 0 && len(requestedSeasons) == 0:
+		// Whole series against existing episodes
+		return true, fmt.Sprintf("You already have a %s request for specific episodes. Cannot request entire series", existing.Status)
+
+	case len(existingEpisodes) == 0 && len(existingSeasons) == 0:
+		// Episodes against an existing whole series request
+		return true, fmt.Sprintf("You already have a %s request for the entire series", existing.Status)
+
+	case len(requestedEpisodes) > 0 && len(existingEpisodes) > 0:
+		// Episodes against episodes conflict only on the same episode
+		var overlapping []string
+		for _, season := range requestedEpisodes.Seasons() {
+			for _, episode := range requestedEpisodes[season] {
+				if existingEpisodes.Contains(season, episode) {
+					overlapping = append(overlapping, fmt.Sprintf("S%02dE%02d", season, episode))
+				}
+			}
+		}
+		if len(overlapping) > 0 {
+			return true, fmt.Sprintf("You already have a %s request for episode(s) %v", existing.Status, overlapping)
+		}
+		return false, ""
+	}
+
+	// Seasons against episodes conflict when they share a season
+	seasons := requestedSeasons
+	otherSeasons := existingEpisodes.Seasons()
+	if len(requestedEpisodes) > 0 {
+		seasons = requestedEpisodes.Seasons()
+		otherSeasons = existingSeasons
+	}
+
+	other := make(map[int]bool, len(otherSeasons))
+	for _, season := range otherSeasons {
+		other[season] = true
+	}
+
+	var overlapping []int
+	for _, season := range seasons {
+		if other[season] {
+			overlapping = append(overlapping, season)
+		}
+	}
+	if len(overlapping) > 0 {
+		return true, fmt.Sprintf("You already have a %s request covering season(s) %v", existing.Status, overlapping)
+	}
+
+	return false, ""
+}
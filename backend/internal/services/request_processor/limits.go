@@ -0,0 +1,40 @@
+package request_processor
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/mahcks/serra/internal/db/repository"
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+// withinRequestLimit reports whether the user may make another request of the
+// media type under the global movie or series request limit. Denied and failed
+// requests don't count, and a limit of 0 means unlimited.
+func (s *service) withinRequestLimit(ctx context.Context, userID, mediaType string) (bool, error) {
+	setting := structures.SettingGlobalMovieRequestLimit
+	if mediaType == "tv" {
+		setting = structures.SettingGlobalSeriesRequestLimit
+	}
+
+	value, err := s.repo.GetSetting(ctx, setting.String())
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return false, fmt.Errorf("failed to get %s: %w", setting, err)
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit <= 0 {
+		return true, nil
+	}
+
+	count, err := s.repo.CountUserRequestsByMediaType(ctx, repository.CountUserRequestsByMediaTypeParams{
+		UserID:    userID,
+		MediaType: mediaType,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to count requests: %w", err)
+	}
+	return count < int64(limit), nil
+}
@@ -0,0 +1,131 @@
+package request_processor
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/mahcks/serra/internal/db/dbtest"
+	"github.com/mahcks/serra/internal/db/repository"
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+func TestNewSeasonRequestsRespectSeriesLimit(t *testing.T) {
+	db, query := dbtest.Open(t)
+	ctx := context.Background()
+	s := &service{repo: query}
+
+	statements := []string{
+		`INSERT INTO users (id, username) VALUES ('alice', 'alice')`,
+		`INSERT INTO user_permissions (user_id, permission_id) VALUES ('alice', 'request.series')`,
+		`INSERT INTO requests (id, user_id, media_type, tmdb_id, title, status, seasons) VALUES
+			(1, 'alice', 'tv', 1399, 'Show', 'fulfilled', '[1]'),
+			(2, 'alice', 'tv', 1400, 'Denied show', 'denied', '[1]'),
+			(3, 'alice', 'movie', 603, 'Movie', 'approved', NULL)`,
+	}
+	for _, statement := range statements {
+		if _, err := db.Exec(statement); err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+	parent, err := query.GetRequestByID(ctx, 1)
+	if err != nil {
+		t.Fatalf("get parent request: %v", err)
+	}
+
+	setLimit := func(limit string) {
+		t.Helper()
+		if err := query.UpsertSetting(ctx, repository.UpsertSettingParams{
+			Key:   structures.SettingGlobalSeriesRequestLimit.String(),
+			Value: limit,
+		}); err != nil {
+			t.Fatalf("set series limit: %v", err)
+		}
+	}
+
+	// Only the fulfilled series request counts towards the limit
+	setLimit("1")
+	request, err := s.createNewSeasonRequest(ctx, parent, 2)
+	if err != nil {
+		t.Fatalf("create new season request: %v", err)
+	}
+	if request != nil {
+		t.Fatalf("season requested beyond the series limit: %+v", request)
+	}
+
+	setLimit("2")
+	request, err = s.createNewSeasonRequest(ctx, parent, 2)
+	if err != nil {
+		t.Fatalf("create new season request: %v", err)
+	}
+	if request == nil {
+		t.Fatal("season not requested while within the series limit")
+	}
+	if request.ParentRequestID != (sql.NullInt64{Int64: 1, Valid: true}) || request.Status != "pending" {
+		t.Errorf("parent=%v status=%s, want parent 1 and pending", request.ParentRequestID, request.Status)
+	}
+
+	// The new season request now counts as well
+	request, err = s.createNewSeasonRequest(ctx, parent, 3)
+	if err != nil {
+		t.Fatalf("create new season request: %v", err)
+	}
+	if request != nil {
+		t.Error("season requested beyond the series limit")
+	}
+}
+
+func TestCollectionPartsRespectMovieLimit(t *testing.T) {
+	db, query := dbtest.Open(t)
+	ctx := context.Background()
+	s := &service{repo: query}
+
+	statements := []string{
+		`INSERT INTO users (id, username) VALUES ('alice', 'alice')`,
+		`INSERT INTO requests (user_id, media_type, tmdb_id, title, status) VALUES
+			('alice', 'movie', 603, 'The Matrix', 'fulfilled'),
+			('alice', 'movie', 604, 'Denied', 'denied')`,
+	}
+	for _, statement := range statements {
+		if _, err := db.Exec(statement); err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+	if err := query.UpsertSetting(ctx, repository.UpsertSettingParams{
+		Key:   structures.SettingGlobalMovieRequestLimit.String(),
+		Value: "2",
+	}); err != nil {
+		t.Fatalf("set movie limit: %v", err)
+	}
+
+	group, err := query.UpsertCollectionRequest(ctx, repository.UpsertCollectionRequestParams{
+		CollectionID: 2344,
+		UserID:       "alice",
+		Name:         "The Matrix Collection",
+	})
+	if err != nil {
+		t.Fatalf("create collection request: %v", err)
+	}
+
+	parts := []structures.TMDBMediaItem{
+		{ID: 605, Title: "The Matrix Revolutions", ReleaseDate: "2003-11-05"},
+		{ID: 624860, Title: "The Matrix Resurrections", ReleaseDate: "2021-12-16"},
+	}
+	items, err := s.requestCollectionParts(ctx, group, parts, false)
+	if err != nil {
+		t.Fatalf("request collection parts: %v", err)
+	}
+
+	want := []string{structures.CollectionItemRequested, structures.CollectionItemLimitReached}
+	if len(items) != len(want) {
+		t.Fatalf("got %d results, want %d", len(items), len(want))
+	}
+	for i, item := range items {
+		if item.Result != want[i] {
+			t.Errorf("%s: result %q, want %q", item.Title, item.Result, want[i])
+		}
+	}
+	if count, _ := query.CountUserRequestsByMediaType(ctx, repository.CountUserRequestsByMediaTypeParams{UserID: "alice", MediaType: "movie"}); count != 2 {
+		t.Errorf("alice has %d movie requests, want the limit of 2", count)
+	}
+}
@@ -0,0 +1,283 @@
+package request_processor
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/mahcks/serra/internal/db/repository"
+	"github.com/mahcks/serra/pkg/permissions"
+	"github.com/mahcks/serra/pkg/structures"
+	"github.com/mahcks/serra/utils"
+)
+
+// RequestNewSeasons looks for seasons that were announced after a TV request
+// was made and requests them for the same user. Followed shows and fulfilled
+// season requests are checked. A season is requested once TMDB gives it an air
+// date. It returns the requests that were created.
+func (s *service) RequestNewSeasons(ctx context.Context) ([]repository.Request, error) {
+	if s.tmdbService == nil {
+		return nil, nil
+	}
+
+	followed, err := s.repo.GetFollowedRequests(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get followed requests: %w", err)
+	}
+
+	fulfilled, err := s.repo.GetRequestsByStatus(ctx, "fulfilled")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fulfilled requests: %w", err)
+	}
+
+	candidates := followed
+	for _, request := range fulfilled {
+		if request.MediaType == "tv" && !request.FollowShow {
+			candidates = append(candidates, request)
+		}
+	}
+
+	var created []repository.Request
+	for _, parent := range candidates {
+		select {
+		case <-ctx.Done():
+			return created, ctx.Err()
+		default:
+		}
+
+		requests, err := s.requestNewSeasons(ctx, parent)
+		created = append(created, requests...)
+		if err != nil {
+			slog.Error("Failed to request new seasons",
+				"request_id", parent.ID,
+				"title", parent.Title.String,
+				"error", err)
+		}
+	}
+
+	return created, nil
+}
+
+func (s *service) requestNewSeasons(ctx context.Context, parent repository.Request) ([]repository.Request, error) {
+	if !parent.TmdbID.Valid {
+		return nil, nil
+	}
+
+	// Whole series requests already cover every future season
+	if len(requestedSeasons(parent)) == 0 {
+		return nil, nil
+	}
+
+	// Only seasons after the last one the user asked for are new
+	existing, err := s.repo.CheckExistingRequestAnySeasons(ctx, repository.CheckExistingRequestAnySeasonsParams{
+		MediaType: parent.MediaType,
+		TmdbID:    parent.TmdbID,
+		UserID:    parent.UserID,
+		Is4k:      parent.Is4k,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get existing requests: %w", err)
+	}
+
+	latest := 0
+	for _, request := range existing {
+		for _, season := range requestedSeasons(request) {
+			if season > latest {
+				latest = season
+			}
+		}
+	}
+
+	seriesID := strconv.FormatInt(parent.TmdbID.Int64, 10)
+	details, err := s.tmdbService.GetTVDetails(seriesID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get TV details from TMDB: %w", err)
+	}
+
+	var created []repository.Request
+	for _, season := range details.Seasons {
+		if season.SeasonNumber <= latest {
+			continue
+		}
+
+		// Seasons are only requested once they have an air date, and only if
+		// they were announced after the original request
+		airDate, ok := s.seasonAirDate(seriesID, season)
+		if !ok || !airDate.After(parent.CreatedAt) {
+			continue
+		}
+
+		// Deduplicate exactly like a request made by the user
+		if err := s.CheckDuplicateRequest(ctx, parent.UserID, parent.MediaType, parent.TmdbID.Int64, []int{season.SeasonNumber}, nil, parent.Is4k); err != nil {
+			slog.Debug("Skipping new season already covered by a request",
+				"request_id", parent.ID,
+				"season", season.SeasonNumber,
+				"reason", err)
+			continue
+		}
+
+		request, err := s.createNewSeasonRequest(ctx, parent, season.SeasonNumber)
+		if err != nil {
+			return created, err
+		}
+		if request == nil {
+			continue
+		}
+		created = append(created, *request)
+
+		slog.Info("Requested new season",
+			"request_id", request.ID,
+			"parent_request_id", parent.ID,
+			"tmdb_id", parent.TmdbID.Int64,
+			"season", season.SeasonNumber,
+			"air_date", airDate.Format("2006-01-02"),
+			"status", request.Status)
+
+		if request.Status == "approved" {
+			if err := s.ProcessApprovedRequest(ctx, request.ID); err != nil {
+				slog.Error("Failed to process new season request",
+					"request_id", request.ID,
+					"error", err)
+			}
+		}
+	}
+
+	return created, nil
+}
+
+// seasonAirDate returns the air date of a season, asking TMDB for the season
+// details when the series details don't include it yet
+func (s *service) seasonAirDate(seriesID string, season structures.Season) (time.Time, bool) {
+	airDate := season.AirDate
+	if airDate == "" {
+		details, err := s.tmdbService.GetSeasonDetails(seriesID, strconv.Itoa(season.SeasonNumber))
+		if err != nil {
+			slog.Warn("Failed to get season details from TMDB",
+				"tmdb_id", seriesID,
+				"season", season.SeasonNumber,
+				"error", err)
+			return time.Time{}, false
+		}
+		airDate = details.AirDate
+	}
+	if airDate == "" {
+		return time.Time{}, false
+	}
+
+	parsed, err := time.Parse("2006-01-02", airDate)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return parsed, true
+}
+
+// createNewSeasonRequest requests one season for the user who made the parent
+// request. It is only created if the user may still request TV shows and is
+// within the series request limit, and is approved straight away if they have
+// auto-approval. Quality and advanced
+// options are inherited from the parent request.
+func (s *service) createNewSeasonRequest(ctx context.Context, parent repository.Request, season int) (*repository.Request, error) {
+	requestPermission := utils.Ternary(parent.Is4k, permissions.Request4KSeries, permissions.RequestSeries)
+	canRequest, err := s.hasPermission(ctx, parent.UserID, requestPermission)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check request permission: %w", err)
+	}
+	if !canRequest {
+		slog.Debug("User can no longer request this show, skipping new season",
+			"request_id", parent.ID,
+			"user_id", parent.UserID,
+			"season", season)
+		return nil, nil
+	}
+
+	withinLimit, err := s.withinRequestLimit(ctx, parent.UserID, parent.MediaType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check request limit: %w", err)
+	}
+	if !withinLimit {
+		slog.Info("User reached the series request limit, skipping new season",
+			"request_id", parent.ID,
+			"user_id", parent.UserID,
+			"season", season)
+		return nil, nil
+	}
+
+	autoApprovalPermission := utils.Ternary(parent.Is4k, permissions.RequestAutoApprove4KSeries, permissions.RequestAutoApproveSeries)
+	autoApproved, err := s.hasPermission(ctx, parent.UserID, autoApprovalPermission)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check auto-approval permission: %w", err)
+	}
+	status := utils.Ternary(autoApproved, "approved", "pending")
+
+	seasonsJSON, err := json.Marshal([]int{season})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal seasons: %w", err)
+	}
+
+	statusJSON, err := json.Marshal(map[string]structures.SeasonInfo{
+		strconv.Itoa(season): {Status: status, Episodes: "0/0"},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal season statuses: %w", err)
+	}
+
+	request, err := s.repo.CreateRequest(ctx, repository.CreateRequestParams{
+		UserID:           parent.UserID,
+		MediaType:        parent.MediaType,
+		TmdbID:           parent.TmdbID,
+		Title:            parent.Title,
+		Status:           status,
+		Notes:            sql.NullString{String: fmt.Sprintf("Season %d requested automatically", season), Valid: true},
+		PosterUrl:        parent.PosterUrl,
+		OnBehalfOf:       parent.OnBehalfOf,
+		Seasons:          sql.NullString{String: string(seasonsJSON), Valid: true},
+		SeasonStatuses:   sql.NullString{String: string(statusJSON), Valid: true},
+		Is4k:             parent.Is4k,
+		QualityProfileID: parent.QualityProfileID,
+		RootFolderPath:   parent.RootFolderPath,
+		Tags:             parent.Tags,
+		SeriesType:       parent.SeriesType,
+		ParentRequestID:  sql.NullInt64{Int64: parent.ID, Valid: true},
+		Priority:         parent.Priority,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request for season %d: %w", season, err)
+	}
+	return &request, nil
+}
+
+// hasPermission checks if a user has a specific permission. Owners have every permission.
+func (s *service) hasPermission(ctx context.Context, userID, permission string) (bool, error) {
+	userPermissions, err := s.repo.GetUserPermissions(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, userPerm := range userPermissions {
+		if userPerm.PermissionID == permissions.Owner || userPerm.PermissionID == permission {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// requestedSeasons returns the seasons a TV request covers, either directly or
+// through its requested episodes. It is empty for whole-series requests.
+func requestedSeasons(request repository.Request) []int {
+	if request.Episodes.Valid && request.Episodes.String != "" {
+		var episodes structures.RequestedEpisodes
+		if err := json.Unmarshal([]byte(request.Episodes.String), &episodes); err == nil {
+			return episodes.Seasons()
+		}
+	}
+
+	var seasons []int
+	if request.Seasons.Valid && request.Seasons.String != "" {
+		_ = json.Unmarshal([]byte(request.Seasons.String), &seasons)
+	}
+	return seasons
+}
@@ -0,0 +1,223 @@
+package request_processor
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strconv"
+
+	"github.com/mahcks/serra/internal/db/repository"
+	apiErrors "github.com/mahcks/serra/pkg/api_errors"
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+// addOptions are the values a request is added to Radarr/Sonarr with
+type addOptions struct {
+	qualityProfileID int
+	rootFolderPath   string
+	seriesType       string
+	tags             []int
+}
+
+// resolveAddOptions starts from the instance defaults, applies the routing
+// rule's root folder and quality profile, then any overrides stored on the
+// request
+func resolveAddOptions(instance repository.ArrService, decision *structures.RoutingDecision, request repository.Request) (addOptions, error) {
+	options := addOptions{
+		rootFolderPath: decision.RootFolderPath,
+		seriesType:     request.SeriesType.String,
+	}
+
+	if request.QualityProfileID.Valid {
+		options.qualityProfileID = int(request.QualityProfileID.Int64)
+	} else if decision.QualityProfileID != nil {
+		options.qualityProfileID = *decision.QualityProfileID
+	} else {
+		qualityProfileID, err := strconv.Atoi(instance.QualityProfile)
+		if err != nil {
+			slog.Error("Failed to parse quality profile ID",
+				"quality_profile", instance.QualityProfile,
+				"error", err)
+			return options, apiErrors.ErrInvalidQualityProfile().SetDetail("Invalid quality profile '%s' for %s instance '%s'", instance.QualityProfile, instance.Type, instance.Name)
+		}
+		options.qualityProfileID = qualityProfileID
+	}
+
+	if request.RootFolderPath.Valid && request.RootFolderPath.String != "" {
+		options.rootFolderPath = request.RootFolderPath.String
+	}
+
+	if request.Tags.Valid && request.Tags.String != "" {
+		if err := json.Unmarshal([]byte(request.Tags.String), &options.tags); err != nil {
+			slog.Error("Failed to parse tags from request",
+				"request_id", request.ID,
+				"tags_json", request.Tags.String,
+				"error", err)
+			return options, apiErrors.ErrInternalServerError().SetDetail("Failed to parse tags for request %d", request.ID)
+		}
+	}
+
+	return options, nil
+}
+
+// GetRequestOptions lists the quality profiles, root folders and tags of the
+// instance a request would be routed to
+func (s *service) GetRequestOptions(ctx context.Context, input structures.RoutingInput) (*structures.RequestOptions, error) {
+	mediaType := input.MediaType
+	if mediaType != "movie" && mediaType != "tv" {
+		return nil, apiErrors.ErrBadRequest().SetDetail("Unsupported media type: %s", mediaType)
+	}
+
+	decision, instance, err := s.route(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		profiles []structures.ArrOption
+		folders  []structures.ArrRootFolderOption
+		tags     []structures.ArrOption
+	)
+	if mediaType == "movie" {
+		profiles, folders, tags, err = s.radarrOptions(ctx, instance)
+	} else {
+		profiles, folders, tags, err = s.sonarrOptions(ctx, instance)
+	}
+	if err != nil {
+		return nil, apiErrors.ErrBadGateway().SetDetail("Failed to load options from %s instance '%s': %s", instance.Type, instance.Name, err.Error())
+	}
+
+	defaultProfile, _ := strconv.Atoi(instance.QualityProfile)
+	if decision.QualityProfileID != nil {
+		defaultProfile = *decision.QualityProfileID
+	}
+	options := &structures.RequestOptions{
+		InstanceName:          instance.Name,
+		Is4K:                  instance.Is4k,
+		DefaultQualityProfile: defaultProfile,
+		DefaultRootFolder:     decision.RootFolderPath,
+		QualityProfiles:       profiles,
+		RootFolders:           folders,
+		Tags:                  tags,
+	}
+	if mediaType == "tv" {
+		options.SeriesTypes = []string{structures.SeriesTypeStandard, structures.SeriesTypeAnime, structures.SeriesTypeDaily}
+	}
+
+	return options, nil
+}
+
+// ValidateOverrides checks that every override refers to a quality profile,
+// root folder or tag that exists on the instance the request is routed to
+func (s *service) ValidateOverrides(ctx context.Context, input structures.RoutingInput, overrides structures.RequestOverrides) error {
+	if !overrides.IsSet() {
+		return nil
+	}
+	mediaType := input.MediaType
+
+	if overrides.SeriesType != nil {
+		if mediaType != "tv" {
+			return apiErrors.ErrBadRequest().SetDetail("series_type can only be set on TV requests")
+		}
+		switch *overrides.SeriesType {
+		case structures.SeriesTypeStandard, structures.SeriesTypeAnime, structures.SeriesTypeDaily:
+		default:
+			return apiErrors.ErrBadRequest().SetDetail("Invalid series_type '%s'", *overrides.SeriesType)
+		}
+	}
+
+	if overrides.QualityProfileID == nil && overrides.RootFolderPath == nil && len(overrides.Tags) == 0 {
+		return nil
+	}
+
+	options, err := s.GetRequestOptions(ctx, input)
+	if err != nil {
+		return err
+	}
+
+	if overrides.QualityProfileID != nil && !hasOption(options.QualityProfiles, *overrides.QualityProfileID) {
+		return apiErrors.ErrBadRequest().SetDetail("Quality profile %d does not exist on '%s'", *overrides.QualityProfileID, options.InstanceName)
+	}
+
+	if overrides.RootFolderPath != nil {
+		found := false
+		for _, folder := range options.RootFolders {
+			if folder.Path == *overrides.RootFolderPath {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return apiErrors.ErrBadRequest().SetDetail("Root folder '%s' does not exist on '%s'", *overrides.RootFolderPath, options.InstanceName)
+		}
+	}
+
+	for _, tag := range overrides.Tags {
+		if !hasOption(options.Tags, tag) {
+			return apiErrors.ErrBadRequest().SetDetail("Tag %d does not exist on '%s'", tag, options.InstanceName)
+		}
+	}
+
+	return nil
+}
+
+func hasOption(options []structures.ArrOption, id int) bool {
+	for _, option := range options {
+		if option.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// radarrOptions lists the quality profiles, root folders and tags of a Radarr instance
+func (s *service) radarrOptions(ctx context.Context, instance repository.ArrService) ([]structures.ArrOption, []structures.ArrRootFolderOption, []structures.ArrOption, error) {
+	profiles, err := s.radarrService.GetQualityProfiles(ctx, instance)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	folders, err := s.radarrService.GetRootFolders(ctx, instance)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	tags, err := s.radarrService.GetTags(ctx, instance)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	profileOptions := make([]structures.ArrOption, 0, len(profiles))
+	for _, profile := range profiles {
+		profileOptions = append(profileOptions, structures.ArrOption{ID: profile.ID, Name: profile.Name})
+	}
+	folderOptions := make([]structures.ArrRootFolderOption, 0, len(folders))
+	for _, folder := range folders {
+		folderOptions = append(folderOptions, structures.ArrRootFolderOption{Path: folder.Path, FreeSpace: folder.FreeSpace})
+	}
+	return profileOptions, folderOptions, tags, nil
+}
+
+// sonarrOptions lists the quality profiles, root folders and tags of a Sonarr instance
+func (s *service) sonarrOptions(ctx context.Context, instance repository.ArrService) ([]structures.ArrOption, []structures.ArrRootFolderOption, []structures.ArrOption, error) {
+	profiles, err := s.sonarrService.GetQualityProfiles(ctx, instance)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	folders, err := s.sonarrService.GetRootFolders(ctx, instance)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	tags, err := s.sonarrService.GetTags(ctx, instance)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	profileOptions := make([]structures.ArrOption, 0, len(profiles))
+	for _, profile := range profiles {
+		profileOptions = append(profileOptions, structures.ArrOption{ID: profile.ID, Name: profile.Name})
+	}
+	folderOptions := make([]structures.ArrRootFolderOption, 0, len(folders))
+	for _, folder := range folders {
+		folderOptions = append(folderOptions, structures.ArrRootFolderOption{Path: folder.Path, FreeSpace: folder.FreeSpace})
+	}
+	return profileOptions, folderOptions, tags, nil
+}
@@ -0,0 +1,24 @@
+package request_processor
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+// RequestPriority returns the priority new requests of a user start at, the
+// user's request tier. Users without a tier, or whose tier can't be read, get
+// normal priority.
+func (s *service) RequestPriority(ctx context.Context, userID string) string {
+	priority, err := s.repo.GetUserRequestPriority(ctx, userID)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			slog.Warn("Failed to get user request priority, using normal", "user_id", userID, "error", err)
+		}
+		return structures.RequestPriorityNormal
+	}
+	return priority
+}
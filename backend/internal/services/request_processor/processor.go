@@ -3,15 +3,16 @@ package request_processor
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
-	"strconv"
 
 	"github.com/mahcks/serra/internal/db/repository"
 	"github.com/mahcks/serra/internal/integrations"
 	"github.com/mahcks/serra/internal/integrations/emby"
 	"github.com/mahcks/serra/internal/integrations/radarr"
 	"github.com/mahcks/serra/internal/integrations/sonarr"
+	"github.com/mahcks/serra/internal/integrations/tmdb"
 	"github.com/mahcks/serra/internal/services/season_availability"
 	apiErrors "github.com/mahcks/serra/pkg/api_errors"
 	"github.com/mahcks/serra/pkg/structures"
@@ -22,6 +23,15 @@ type Service interface {
 	CheckRequestStatus(ctx context.Context, requestID int64) error
 	CheckExistingAvailability(ctx context.Context, tmdbID int64, mediaType string, seasons []int) (*structures.ShowAvailability, error)
 	RetryFailedRequests(ctx context.Context) error
+	GetRequestOptions(ctx context.Context, input structures.RoutingInput) (*structures.RequestOptions, error)
+	ValidateOverrides(ctx context.Context, input structures.RoutingInput, overrides structures.RequestOverrides) error
+	PreviewRouting(ctx context.Context, input structures.RoutingInput) (*structures.RoutingDecision, error)
+	CheckDuplicateRequest(ctx context.Context, userID, mediaType string, tmdbID int64, requestedSeasons []int, requestedEpisodes structures.RequestedEpisodes, is4K bool) error
+	RequestNewSeasons(ctx context.Context) ([]repository.Request, error)
+	RequestCollection(ctx context.Context, input structures.CollectionRequestInput) (*structures.CollectionRequestResult, error)
+	RequestReleasedCollectionParts(ctx context.Context) ([]repository.Request, error)
+	RequestReleasedWatchlistMovies(ctx context.Context) ([]repository.Request, error)
+	RequestPriority(ctx context.Context, userID string) string
 }
 
 type service struct {
@@ -30,6 +40,7 @@ type service struct {
 	sonarrService             sonarr.Service
 	seasonAvailabilityService *season_availability.SeasonAvailabilityService
 	embyService               emby.Service
+	tmdbService               tmdb.Service
 }
 
 func New(repo *repository.Queries, radarrSvc radarr.Service, sonarrSvc sonarr.Service, integrations *integrations.Integration) Service {
@@ -40,6 +51,7 @@ func New(repo *repository.Queries, radarrSvc radarr.Service, sonarrSvc sonarr.Se
 		sonarrService:             sonarrSvc,
 		seasonAvailabilityService: seasonSvc,
 		embyService:               integrations.Emby,
+		tmdbService:               integrations.TMDB,
 	}
 }
 
@@ -94,9 +106,9 @@ func (s *service) ProcessApprovedRequest(ctx context.Context, requestID int64) e
 	var processErr error
 	switch request.MediaType {
 	case "movie":
-		processErr = s.processMovieRequest(ctx, requestID, tmdbID)
+		processErr = s.processMovieRequest(ctx, request, tmdbID)
 	case "tv":
-		processErr = s.processSeriesRequest(ctx, requestID, tmdbID)
+		processErr = s.processSeriesRequest(ctx, request, tmdbID)
 	default:
 		processErr = apiErrors.ErrInvalidMediaType().SetDetail("Unsupported media type: %s", request.MediaType)
 	}
@@ -120,45 +132,16 @@ func (s *service) ProcessApprovedRequest(ctx context.Context, requestID int64) e
 	return nil
 }
 
-func (s *service) processMovieRequest(ctx context.Context, requestID, tmdbID int64) error {
-	slog.Info("Processing movie request", "request_id", requestID, "tmdb_id", tmdbID)
+func (s *service) processMovieRequest(ctx context.Context, request repository.Request, tmdbID int64) error {
+	requestID := request.ID
+	is4K := request.Is4k
+	slog.Info("Processing movie request", "request_id", requestID, "tmdb_id", tmdbID, "is_4k", is4K)
 
-	// TODO: For now, all requests are treated as non-4K since 4K detection is not implemented
-	// In the future, this should get the request and check request.Is4K or similar field:
-	// request, err := s.repo.GetRequestByID(ctx, requestID)
-	// is4K := request.Is4K
-	is4K := false
-
-	// Get Radarr instances
-	allRadarrInstances, err := s.repo.GetArrServiceByType(ctx, "radarr")
+	decision, instance, err := s.routeRequest(ctx, request)
 	if err != nil {
-		slog.Error("Failed to get Radarr instances", "error", err)
-		return fmt.Errorf("failed to get Radarr instances: %w", err)
-	}
-
-	if len(allRadarrInstances) == 0 {
-		slog.Error("No Radarr instances configured")
-		return apiErrors.ErrNoRadarrInstances()
-	}
-
-	// Filter instances by 4K preference
-	var radarrInstances []repository.ArrService
-	for _, instance := range allRadarrInstances {
-		if instance.Is4k == is4K {
-			radarrInstances = append(radarrInstances, instance)
-		}
-	}
-
-	// Fallback to any instance if no matching type found
-	if len(radarrInstances) == 0 {
-		slog.Warn("No Radarr instances found for content type, using first available",
-			"is_4k", is4K,
-			"request_id", requestID)
-		radarrInstances = allRadarrInstances
+		slog.Error("No Radarr instance available for request", "request_id", requestID, "is_4k", is4K, "error", err)
+		return err
 	}
-
-	// Use first matching instance (could implement round-robin here later)
-	instance := radarrInstances[0]
 	slog.Info("Using Radarr instance",
 		"name", instance.Name,
 		"url", instance.BaseUrl,
@@ -168,14 +151,12 @@ func (s *service) processMovieRequest(ctx context.Context, requestID, tmdbID int
 		"is_4k", instance.Is4k,
 		"content_type", map[bool]string{true: "4K", false: "regular"}[is4K])
 
-	// Parse quality profile ID from stored string
-	qualityProfileID, err := strconv.Atoi(instance.QualityProfile)
+	// Resolve quality profile, root folder and tags, preferring the request's overrides
+	options, err := resolveAddOptions(instance, decision, request)
 	if err != nil {
-		slog.Error("Failed to parse quality profile ID",
-			"quality_profile", instance.QualityProfile,
-			"error", err)
-		return apiErrors.ErrInvalidQualityProfile().SetDetail("Invalid quality profile '%s' for Radarr instance '%s'", instance.QualityProfile, instance.Name)
+		return err
 	}
+	qualityProfileID := options.qualityProfileID
 
 	// Add movie to Radarr with configured quality profile
 	slog.Info("Calling Radarr AddMovie",
@@ -183,10 +164,12 @@ func (s *service) processMovieRequest(ctx context.Context, requestID, tmdbID int
 		"quality_profile_id", qualityProfileID)
 	response, err := s.radarrService.AddMovie(
 		ctx,
+		instance,
 		tmdbID,
 		qualityProfileID,
-		instance.RootFolderPath,
+		options.rootFolderPath,
 		instance.MinimumAvailability,
+		options.tags,
 	)
 	if err != nil {
 		slog.Error("Failed to add movie to Radarr",
@@ -205,15 +188,10 @@ func (s *service) processMovieRequest(ctx context.Context, requestID, tmdbID int
 	return nil
 }
 
-func (s *service) processSeriesRequest(ctx context.Context, requestID, tmdbID int64) error {
-	slog.Info("Processing series request", "request_id", requestID, "tmdb_id", tmdbID)
-
-	// Get the request to extract season information
-	request, err := s.repo.GetRequestByID(ctx, requestID)
-	if err != nil {
-		slog.Error("Failed to get request details", "request_id", requestID, "error", err)
-		return fmt.Errorf("failed to get request details: %w", err)
-	}
+func (s *service) processSeriesRequest(ctx context.Context, request repository.Request, tmdbID int64) error {
+	requestID := request.ID
+	is4K := request.Is4k
+	slog.Info("Processing series request", "request_id", requestID, "tmdb_id", tmdbID, "is_4k", is4K)
 
 	// Parse seasons from request
 	var seasons []int
@@ -228,40 +206,24 @@ func (s *service) processSeriesRequest(ctx context.Context, requestID, tmdbID in
 		}
 	}
 
-	// TODO: For now, all requests are treated as non-4K since 4K detection is not implemented
-	// In the future, this should check request.Is4K or similar field
-	is4K := false
-
-	// Get Sonarr instances
-	allSonarrInstances, err := s.repo.GetArrServiceByType(ctx, "sonarr")
-	if err != nil {
-		slog.Error("Failed to get Sonarr instances", "error", err)
-		return fmt.Errorf("failed to get Sonarr instances: %w", err)
-	}
-
-	if len(allSonarrInstances) == 0 {
-		slog.Error("No Sonarr instances configured")
-		return apiErrors.ErrNoSonarrInstances()
-	}
-
-	// Filter instances by 4K preference
-	var sonarrInstances []repository.ArrService
-	for _, instance := range allSonarrInstances {
-		if instance.Is4k == is4K {
-			sonarrInstances = append(sonarrInstances, instance)
+	// Parse episodes from request
+	var episodes structures.RequestedEpisodes
+	if request.Episodes.Valid && request.Episodes.String != "" {
+		err := json.Unmarshal([]byte(request.Episodes.String), &episodes)
+		if err != nil {
+			slog.Error("Failed to parse episodes from request",
+				"request_id", requestID,
+				"episodes_json", request.Episodes.String,
+				"error", err)
+			return apiErrors.ErrSeasonParsingFailed()
 		}
 	}
 
-	// Fallback to any instance if no matching type found
-	if len(sonarrInstances) == 0 {
-		slog.Warn("No Sonarr instances found for content type, using first available",
-			"is_4k", is4K,
-			"request_id", requestID)
-		sonarrInstances = allSonarrInstances
+	decision, instance, err := s.routeRequest(ctx, request)
+	if err != nil {
+		slog.Error("No Sonarr instance available for request", "request_id", requestID, "is_4k", is4K, "error", err)
+		return err
 	}
-
-	// Use first matching instance (could implement round-robin here later)
-	instance := sonarrInstances[0]
 	slog.Info("Using Sonarr instance",
 		"name", instance.Name,
 		"url", instance.BaseUrl,
@@ -270,27 +232,63 @@ func (s *service) processSeriesRequest(ctx context.Context, requestID, tmdbID in
 		"is_4k", instance.Is4k,
 		"content_type", map[bool]string{true: "4K", false: "regular"}[is4K])
 
-	// Parse quality profile ID from stored string
-	qualityProfileID, err := strconv.Atoi(instance.QualityProfile)
+	// Resolve quality profile, root folder and tags, preferring the request's overrides
+	options, err := resolveAddOptions(instance, decision, request)
 	if err != nil {
-		slog.Error("Failed to parse quality profile ID",
-			"quality_profile", instance.QualityProfile,
-			"error", err)
-		return apiErrors.ErrInvalidQualityProfile().SetDetail("Invalid quality profile '%s' for Sonarr instance '%s'", instance.QualityProfile, instance.Name)
+		return err
 	}
+	qualityProfileID := options.qualityProfileID
+
+	// Add series to Sonarr with configured quality profile and episode- or season-specific monitoring
+	if len(episodes) > 0 {
+		slog.Info("Calling Sonarr AddSeriesWithEpisodes",
+			"tmdb_id", tmdbID,
+			"quality_profile_id", qualityProfileID,
+			"episodes", request.Episodes.String,
+			"follow_show", request.FollowShow)
+		response, err := s.sonarrService.AddSeriesWithEpisodes(
+			ctx,
+			instance,
+			tmdbID,
+			qualityProfileID,
+			options.rootFolderPath,
+			episodes,
+			options.seriesType,
+			options.tags,
+			request.FollowShow,
+		)
+		if err != nil {
+			slog.Error("Failed to add series to Sonarr",
+				"request_id", requestID,
+				"tmdb_id", tmdbID,
+				"error", err)
+			return fmt.Errorf("failed to add series to Sonarr: %w", err)
+		}
+		if response.EpisodesMonitored {
+			s.markEpisodesMonitored(ctx, requestID)
+		}
 
-	// Add series to Sonarr with configured quality profile and season-specific monitoring
-	if len(seasons) > 0 {
+		slog.Info("Series added to Sonarr with specific episodes",
+			"request_id", requestID,
+			"tmdb_id", tmdbID,
+			"sonarr_id", response.ID,
+			"title", response.Title,
+			"episodes", request.Episodes.String)
+	} else if len(seasons) > 0 {
 		slog.Info("Calling Sonarr AddSeriesWithSeasons",
 			"tmdb_id", tmdbID,
 			"quality_profile_id", qualityProfileID,
 			"seasons", seasons)
 		response, err := s.sonarrService.AddSeriesWithSeasons(
 			ctx,
+			instance,
 			tmdbID,
 			qualityProfileID,
-			instance.RootFolderPath,
+			options.rootFolderPath,
 			seasons,
+			options.seriesType,
+			options.tags,
+			request.FollowShow,
 		)
 		if err != nil {
 			slog.Error("Failed to add series to Sonarr",
@@ -312,9 +310,12 @@ func (s *service) processSeriesRequest(ctx context.Context, requestID, tmdbID in
 			"quality_profile_id", qualityProfileID)
 		response, err := s.sonarrService.AddSeries(
 			ctx,
+			instance,
 			tmdbID,
 			qualityProfileID,
-			instance.RootFolderPath,
+			options.rootFolderPath,
+			options.seriesType,
+			options.tags,
 		)
 		if err != nil {
 			slog.Error("Failed to add series to Sonarr",
@@ -334,6 +335,34 @@ func (s *service) processSeriesRequest(ctx context.Context, requestID, tmdbID in
 	return nil
 }
 
+// selectInstance returns the first configured instance of the given provider
+// that matches the request's 4K mode. It is used when no routing rule applies. Requests are never routed to an instance
+// of the other mode, so a 4K request fails instead of landing in the regular
+// library and vice versa.
+func (s *service) selectInstance(ctx context.Context, provider structures.ArrProvider, is4K bool) (repository.ArrService, error) {
+	instances, err := s.repo.GetArrServiceByType(ctx, provider.String())
+	if err != nil {
+		return repository.ArrService{}, fmt.Errorf("failed to get %s instances: %w", provider, err)
+	}
+
+	for _, instance := range instances {
+		if instance.Is4k == is4K {
+			return instance, nil
+		}
+	}
+
+	switch {
+	case provider == structures.ProviderRadarr && is4K:
+		return repository.ArrService{}, apiErrors.ErrNo4KRadarrInstances()
+	case provider == structures.ProviderRadarr:
+		return repository.ArrService{}, apiErrors.ErrNoRadarrInstances()
+	case is4K:
+		return repository.ArrService{}, apiErrors.ErrNo4KSonarrInstances()
+	default:
+		return repository.ArrService{}, apiErrors.ErrNoSonarrInstances()
+	}
+}
+
 // CheckRequestStatus checks if a request's media has been downloaded and fulfills it if needed
 func (s *service) CheckRequestStatus(ctx context.Context, requestID int64) error {
 	// Get the request details
@@ -355,17 +384,24 @@ func (s *service) CheckRequestStatus(ctx context.Context, requestID int64) error
 
 	switch request.MediaType {
 	case "movie":
-		return s.checkMovieStatus(ctx, requestID, tmdbID)
+		return s.checkMovieStatus(ctx, request, tmdbID)
 	case "tv":
-		return s.checkSeriesStatus(ctx, requestID, tmdbID)
+		return s.checkSeriesStatus(ctx, request, tmdbID)
 	default:
 		return fmt.Errorf("unsupported media type: %s", request.MediaType)
 	}
 }
 
-func (s *service) checkMovieStatus(ctx context.Context, requestID, tmdbID int64) error {
-	// First check if movie is downloaded in Radarr
-	movie, err := s.radarrService.GetMovieByTMDBID(ctx, tmdbID)
+func (s *service) checkMovieStatus(ctx context.Context, request repository.Request, tmdbID int64) error {
+	requestID := request.ID
+
+	// First check if movie is downloaded in the Radarr instance the request was sent to
+	instance, err := s.routedInstance(ctx, request, structures.ProviderRadarr)
+	if err != nil {
+		return err
+	}
+
+	movie, err := s.radarrService.GetMovieByTMDBID(ctx, instance, tmdbID)
 	if err != nil {
 		return fmt.Errorf("failed to get movie status from Radarr: %w", err)
 	}
@@ -415,9 +451,26 @@ func (s *service) checkMovieStatus(ctx context.Context, requestID, tmdbID int64)
 	return nil
 }
 
-func (s *service) checkSeriesStatus(ctx context.Context, requestID, tmdbID int64) error {
-	// First check if series has downloaded episodes in Sonarr
-	series, err := s.sonarrService.GetSeriesByTMDBID(ctx, tmdbID)
+// markEpisodesMonitored records that the requested episodes of a request were
+// monitored in Sonarr
+func (s *service) markEpisodesMonitored(ctx context.Context, requestID int64) {
+	if err := s.repo.MarkRequestEpisodesMonitored(ctx, requestID); err != nil {
+		slog.Error("Failed to record monitored episodes",
+			"request_id", requestID,
+			"error", err)
+	}
+}
+
+func (s *service) checkSeriesStatus(ctx context.Context, request repository.Request, tmdbID int64) error {
+	requestID := request.ID
+
+	// First check if series has downloaded episodes in the Sonarr instance the request was sent to
+	instance, err := s.routedInstance(ctx, request, structures.ProviderSonarr)
+	if err != nil {
+		return err
+	}
+
+	series, err := s.sonarrService.GetSeriesByTMDBID(ctx, instance, tmdbID)
 	if err != nil {
 		return fmt.Errorf("failed to get series status from Sonarr: %w", err)
 	}
@@ -427,6 +480,25 @@ func (s *service) checkSeriesStatus(ctx context.Context, requestID, tmdbID int64
 		return nil
 	}
 
+	var requested structures.RequestedEpisodes
+	if request.Episodes.Valid && request.Episodes.String != "" {
+		if err := json.Unmarshal([]byte(request.Episodes.String), &requested); err != nil {
+			return fmt.Errorf("failed to parse episodes of request %d: %w", requestID, err)
+		}
+
+		// Requested episodes are monitored once Sonarr has loaded a newly added
+		// series, and only once so later changes made in Sonarr are kept
+		if !request.EpisodesMonitoredAt.Valid {
+			if _, err := s.sonarrService.MonitorEpisodes(ctx, instance, series.ID, requested); err != nil {
+				if errors.Is(err, sonarr.ErrEpisodesNotLoaded) {
+					return nil
+				}
+				return fmt.Errorf("failed to monitor requested episodes: %w", err)
+			}
+			s.markEpisodesMonitored(ctx, requestID)
+		}
+	}
+
 	// Check if the series has downloaded episodes (at least some episodes)
 	if series.Statistics.EpisodeFileCount == 0 {
 		// No episodes downloaded yet in Sonarr
@@ -473,6 +545,18 @@ func (s *service) checkSeriesStatus(ctx context.Context, requestID, tmdbID int64
 		return nil
 	}
 
+	// Episode requests are only fulfilled once every requested episode is available
+	if len(requested) > 0 {
+		if missing := missingEpisodes(requested, episodes); missing > 0 {
+			slog.Debug("Requested episodes not all available in Emby yet",
+				"request_id", requestID,
+				"tmdb_id", tmdbID,
+				"title", series.Title,
+				"missing_episodes", missing)
+			return nil
+		}
+	}
+
 	// Series has both downloaded episodes in Sonarr AND available episodes in Emby - fulfill the request
 	_, err = s.repo.FulfillRequest(ctx, requestID)
 	if err != nil {
@@ -489,6 +573,24 @@ func (s *service) checkSeriesStatus(ctx context.Context, requestID, tmdbID int64
 	return nil
 }
 
+// missingEpisodes counts the requested episodes that are not in the media server
+func missingEpisodes(requested structures.RequestedEpisodes, available []structures.EmbyMediaItem) int {
+	found := make(map[[2]int]bool, len(available))
+	for _, episode := range available {
+		found[[2]int{episode.SeasonNumber, episode.EpisodeNumber}] = true
+	}
+
+	missing := 0
+	for season, numbers := range requested {
+		for _, number := range numbers {
+			if !found[[2]int{season, number}] {
+				missing++
+			}
+		}
+	}
+	return missing
+}
+
 // CheckExistingAvailability checks what seasons/episodes are already available for a given TMDB ID
 func (s *service) CheckExistingAvailability(ctx context.Context, tmdbID int64, mediaType string, seasons []int) (*structures.ShowAvailability, error) {
 	if mediaType == "movie" {
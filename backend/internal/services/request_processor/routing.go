@@ -0,0 +1,382 @@
+package request_processor
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/mahcks/serra/internal/db/repository"
+	"github.com/mahcks/serra/pkg/structures"
+	"github.com/mahcks/serra/utils"
+)
+
+// routingContext lazily loads the request attributes rules match on, so TMDB
+// and the users table are only queried when a rule needs them
+type routingContext struct {
+	input structures.RoutingInput
+
+	media        *structures.TMDBMediaClassification
+	mediaErr     error
+	mediaLoaded  bool
+	userType     string
+	userTypeErr  error
+	userLoaded   bool
+	instances    map[string]repository.ArrService
+	instanceErrs map[string]error
+}
+
+// PreviewRouting explains which rule a request would match without sending it anywhere
+func (s *service) PreviewRouting(ctx context.Context, input structures.RoutingInput) (*structures.RoutingDecision, error) {
+	decision, _, err := s.route(ctx, input)
+	return decision, err
+}
+
+// route picks the instance a request is sent to. Enabled rules for the media
+// type are checked by ascending priority and the first match wins. If none
+// match, the first default rule for the request's 4K mode is used, and if there
+// is none the first instance of that mode is used as before routing existed.
+func (s *service) route(ctx context.Context, input structures.RoutingInput) (*structures.RoutingDecision, repository.ArrService, error) {
+	provider := utils.Ternary(input.MediaType == "movie", structures.ProviderRadarr, structures.ProviderSonarr)
+	mode := utils.Ternary(input.Is4K, "4K", "regular")
+
+	rules, err := s.repo.GetEnabledRoutingRules(ctx, input.MediaType)
+	if err != nil {
+		return nil, repository.ArrService{}, fmt.Errorf("failed to get routing rules: %w", err)
+	}
+
+	rc := &routingContext{
+		input:        input,
+		instances:    make(map[string]repository.ArrService),
+		instanceErrs: make(map[string]error),
+	}
+	decision := &structures.RoutingDecision{Evaluations: []structures.RoutingRuleEvaluation{}}
+
+	var defaults []repository.RoutingRule
+	for _, rule := range rules {
+		if rule.IsDefault {
+			defaults = append(defaults, rule)
+			continue
+		}
+
+		instance, matched, reasons := s.evaluateRule(ctx, rc, rule, provider)
+		decision.Evaluations = append(decision.Evaluations, structures.RoutingRuleEvaluation{
+			RuleID:   rule.ID,
+			RuleName: rule.Name,
+			Priority: rule.Priority,
+			Matched:  matched,
+			Reasons:  reasons,
+		})
+		if matched {
+			applyRule(decision, rule, instance)
+			decision.Explanation = fmt.Sprintf("Matched rule '%s' (priority %d)", rule.Name, rule.Priority)
+			decision.Media = rc.media
+			return decision, instance, nil
+		}
+	}
+
+	for _, rule := range defaults {
+		instance, err := s.ruleInstance(ctx, rc, rule)
+		reasons := []string{"default rule"}
+		matched := err == nil && instance.Is4k == input.Is4K
+		if err != nil {
+			reasons = append(reasons, err.Error())
+		} else if !matched {
+			reasons = append(reasons, fmt.Sprintf("instance '%s' does not serve %s requests", instance.Name, mode))
+		}
+		decision.Evaluations = append(decision.Evaluations, structures.RoutingRuleEvaluation{
+			RuleID:   rule.ID,
+			RuleName: rule.Name,
+			Priority: rule.Priority,
+			Matched:  matched,
+			Reasons:  reasons,
+		})
+		if matched {
+			applyRule(decision, rule, instance)
+			decision.IsDefault = true
+			decision.Explanation = fmt.Sprintf("No rule matched; using default rule '%s'", rule.Name)
+			decision.Media = rc.media
+			return decision, instance, nil
+		}
+	}
+
+	instance, err := s.selectInstance(ctx, provider, input.Is4K)
+	if err != nil {
+		return nil, repository.ArrService{}, err
+	}
+
+	decision.Fallback = true
+	decision.ArrServiceID = instance.ID
+	decision.ArrServiceName = instance.Name
+	decision.RootFolderPath = instance.RootFolderPath
+	decision.Media = rc.media
+	if len(rules) == 0 {
+		decision.Explanation = fmt.Sprintf("No routing rules configured; using the first %s %s instance", mode, provider)
+	} else {
+		decision.Explanation = fmt.Sprintf("No rule matched and no default rule applies; using the first %s %s instance", mode, provider)
+	}
+
+	return decision, instance, nil
+}
+
+func applyRule(decision *structures.RoutingDecision, rule repository.RoutingRule, instance repository.ArrService) {
+	ruleID := rule.ID
+	decision.RuleID = &ruleID
+	decision.RuleName = rule.Name
+	decision.ArrServiceID = instance.ID
+	decision.ArrServiceName = instance.Name
+	decision.RootFolderPath = instance.RootFolderPath
+	if rule.RootFolderPath.Valid && rule.RootFolderPath.String != "" {
+		decision.RootFolderPath = rule.RootFolderPath.String
+	}
+	if rule.QualityProfileID.Valid {
+		qualityProfileID := int(rule.QualityProfileID.Int64)
+		decision.QualityProfileID = &qualityProfileID
+	}
+}
+
+// evaluateRule checks every condition of a rule. All conditions must match; the
+// reasons explain each check for the preview endpoint.
+func (s *service) evaluateRule(ctx context.Context, rc *routingContext, rule repository.RoutingRule, provider structures.ArrProvider) (repository.ArrService, bool, []string) {
+	var reasons []string
+	matched := true
+
+	instance, err := s.ruleInstance(ctx, rc, rule)
+	if err != nil {
+		return instance, false, []string{err.Error()}
+	}
+	if instance.Type != provider.String() {
+		return instance, false, []string{fmt.Sprintf("instance '%s' is not a %s instance", instance.Name, provider)}
+	}
+	if instance.Is4k != rc.input.Is4K {
+		return instance, false, []string{fmt.Sprintf("4K: instance '%s' does not serve %s requests", instance.Name, utils.Ternary(rc.input.Is4K, "4K", "regular"))}
+	}
+	reasons = append(reasons, fmt.Sprintf("4K: instance '%s' serves %s requests", instance.Name, utils.Ternary(rc.input.Is4K, "4K", "regular")))
+
+	check := func(name string, ok bool, detail string) {
+		if !ok {
+			matched = false
+		}
+		reasons = append(reasons, fmt.Sprintf("%s: %s", name, detail))
+	}
+
+	if genres := parseIntList(rule.Genres); len(genres) > 0 {
+		if media, err := rc.loadMedia(s); err != nil {
+			check("genre", false, err.Error())
+		} else {
+			var have []int
+			names := make([]string, 0, len(media.Genres))
+			for _, genre := range media.Genres {
+				have = append(have, genre.ID)
+				names = append(names, genre.Name)
+			}
+			check("genre", intersects(genres, have), fmt.Sprintf("media genres [%s], rule wants %v", strings.Join(names, ", "), genres))
+		}
+	}
+
+	if languages := parseStringList(rule.OriginalLanguages); len(languages) > 0 {
+		if media, err := rc.loadMedia(s); err != nil {
+			check("original language", false, err.Error())
+		} else {
+			check("original language", containsFold(languages, media.OriginalLanguage), fmt.Sprintf("media is '%s', rule wants %v", media.OriginalLanguage, languages))
+		}
+	}
+
+	if keywords := parseIntList(rule.Keywords); len(keywords) > 0 {
+		if media, err := rc.loadMedia(s); err != nil {
+			check("keyword", false, err.Error())
+		} else {
+			var have []int
+			for _, keyword := range media.Keywords {
+				have = append(have, keyword.ID)
+			}
+			check("keyword", intersects(keywords, have), fmt.Sprintf("media has %d keywords, rule wants %v", len(have), keywords))
+		}
+	}
+
+	if certifications := parseStringList(rule.Certifications); len(certifications) > 0 {
+		if media, err := rc.loadMedia(s); err != nil {
+			check("certification", false, err.Error())
+		} else {
+			check("certification", containsFold(certifications, media.Certification), fmt.Sprintf("media is '%s', rule wants %v", media.Certification, certifications))
+		}
+	}
+
+	if userIDs := parseStringList(rule.UserIds); len(userIDs) > 0 {
+		check("user", containsFold(userIDs, rc.input.UserID), fmt.Sprintf("requested by '%s'", rc.input.UserID))
+	}
+
+	if userTypes := parseStringList(rule.UserTypes); len(userTypes) > 0 {
+		if userType, err := rc.loadUserType(ctx, s); err != nil {
+			check("user type", false, err.Error())
+		} else {
+			check("user type", containsFold(userTypes, userType), fmt.Sprintf("user is a %s account, rule wants %v", userType, userTypes))
+		}
+	}
+
+	return instance, matched, reasons
+}
+
+func (s *service) ruleInstance(ctx context.Context, rc *routingContext, rule repository.RoutingRule) (repository.ArrService, error) {
+	if instance, ok := rc.instances[rule.ArrServiceID]; ok {
+		return instance, nil
+	}
+	if err, ok := rc.instanceErrs[rule.ArrServiceID]; ok {
+		return repository.ArrService{}, err
+	}
+
+	instance, err := s.repo.GetArrServiceByID(ctx, rule.ArrServiceID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = fmt.Errorf("target instance %s no longer exists", rule.ArrServiceID)
+		} else {
+			err = fmt.Errorf("failed to load target instance: %w", err)
+		}
+		rc.instanceErrs[rule.ArrServiceID] = err
+		return repository.ArrService{}, err
+	}
+
+	rc.instances[rule.ArrServiceID] = instance
+	return instance, nil
+}
+
+func (rc *routingContext) loadMedia(s *service) (*structures.TMDBMediaClassification, error) {
+	if !rc.mediaLoaded {
+		rc.mediaLoaded = true
+		switch {
+		case s.tmdbService == nil:
+			rc.mediaErr = fmt.Errorf("TMDB is not configured")
+		case rc.input.TmdbID == 0:
+			rc.mediaErr = fmt.Errorf("no TMDB id to look up")
+		default:
+			media, err := s.tmdbService.GetMediaClassification(rc.input.MediaType, strconv.FormatInt(rc.input.TmdbID, 10))
+			if err != nil {
+				slog.Warn("Failed to get TMDB details for routing", "tmdb_id", rc.input.TmdbID, "media_type", rc.input.MediaType, "error", err)
+				rc.mediaErr = fmt.Errorf("TMDB details unavailable")
+			} else {
+				rc.media = &media
+			}
+		}
+	}
+	return rc.media, rc.mediaErr
+}
+
+// loadUserType returns the requesting user's account type (users.user_type),
+// which is what the user type condition matches on
+func (rc *routingContext) loadUserType(ctx context.Context, s *service) (string, error) {
+	if !rc.userLoaded {
+		rc.userLoaded = true
+		if rc.input.UserID == "" {
+			rc.userTypeErr = fmt.Errorf("no requesting user")
+		} else if user, err := s.repo.GetUserByID(ctx, rc.input.UserID); err != nil {
+			rc.userTypeErr = fmt.Errorf("requesting user not found")
+		} else {
+			rc.userType = user.UserType
+		}
+	}
+	return rc.userType, rc.userTypeErr
+}
+
+// routeRequest routes a stored request and records the chosen instance so
+// later status checks and retries use the same one
+func (s *service) routeRequest(ctx context.Context, request repository.Request) (*structures.RoutingDecision, repository.ArrService, error) {
+	decision, instance, err := s.route(ctx, routingInput(request))
+	if err != nil {
+		return nil, instance, err
+	}
+
+	params := repository.SetRequestRoutingParams{
+		RequestID:      request.ID,
+		ArrServiceID:   sql.NullString{String: instance.ID, Valid: true},
+		RootFolderPath: sql.NullString{String: decision.RootFolderPath, Valid: decision.RootFolderPath != ""},
+	}
+	if decision.RuleID != nil {
+		params.RoutingRuleID = sql.NullInt64{Int64: *decision.RuleID, Valid: true}
+	}
+	if err := s.repo.SetRequestRouting(ctx, params); err != nil {
+		slog.Warn("Failed to record request routing", "request_id", request.ID, "error", err)
+	}
+
+	slog.Info("Routed request",
+		"request_id", request.ID,
+		"instance", instance.Name,
+		"rule", decision.RuleName,
+		"explanation", decision.Explanation)
+
+	return decision, instance, nil
+}
+
+// routedInstance returns the instance a request was sent to, falling back to
+// the first instance of its mode for requests processed before routing existed
+func (s *service) routedInstance(ctx context.Context, request repository.Request, provider structures.ArrProvider) (repository.ArrService, error) {
+	routing, err := s.repo.GetRequestRouting(ctx, request.ID)
+	if err == nil && routing.ArrServiceID.Valid {
+		instance, err := s.repo.GetArrServiceByID(ctx, routing.ArrServiceID.String)
+		if err == nil {
+			return instance, nil
+		}
+		if err != sql.ErrNoRows {
+			return repository.ArrService{}, fmt.Errorf("failed to get routed instance: %w", err)
+		}
+	} else if err != nil && err != sql.ErrNoRows {
+		return repository.ArrService{}, fmt.Errorf("failed to get request routing: %w", err)
+	}
+
+	return s.selectInstance(ctx, provider, request.Is4k)
+}
+
+func routingInput(request repository.Request) structures.RoutingInput {
+	userID := request.UserID
+	if request.OnBehalfOf.Valid && request.OnBehalfOf.String != "" {
+		userID = request.OnBehalfOf.String
+	}
+	return structures.RoutingInput{
+		MediaType: request.MediaType,
+		TmdbID:    request.TmdbID.Int64,
+		Is4K:      request.Is4k,
+		UserID:    userID,
+	}
+}
+
+func parseIntList(value sql.NullString) []int {
+	var list []int
+	if value.Valid && value.String != "" {
+		if err := json.Unmarshal([]byte(value.String), &list); err != nil {
+			slog.Warn("Invalid routing rule condition", "value", value.String, "error", err)
+		}
+	}
+	return list
+}
+
+func parseStringList(value sql.NullString) []string {
+	var list []string
+	if value.Valid && value.String != "" {
+		if err := json.Unmarshal([]byte(value.String), &list); err != nil {
+			slog.Warn("Invalid routing rule condition", "value", value.String, "error", err)
+		}
+	}
+	return list
+}
+
+func intersects(want, have []int) bool {
+	for _, w := range want {
+		for _, h := range have {
+			if w == h {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func containsFold(list []string, value string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,180 @@
+package request_processor
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/mahcks/serra/internal/db/repository"
+	"github.com/mahcks/serra/pkg/permissions"
+	"github.com/mahcks/serra/pkg/structures"
+	"github.com/mahcks/serra/utils"
+)
+
+// TMDB release type for digital releases (VOD, purchase and rental)
+const tmdbReleaseTypeDigital = 4
+
+// RequestReleasedWatchlistMovies requests the watchlisted movies that have
+// auto-request enabled and reached their digital release date. Movies that are
+// already in the library or requested by the user are linked instead of
+// requested again. Approved requests are processed straight away. It returns
+// the requests that were created.
+func (s *service) RequestReleasedWatchlistMovies(ctx context.Context) ([]repository.Request, error) {
+	if s.tmdbService == nil {
+		return nil, nil
+	}
+
+	items, err := s.repo.GetAutoRequestWatchlistItems(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get auto-request watchlist items: %w", err)
+	}
+
+	var created []repository.Request
+	for _, item := range items {
+		select {
+		case <-ctx.Done():
+			return created, ctx.Err()
+		default:
+		}
+
+		request, err := s.requestWatchlistMovie(ctx, item)
+		if err != nil {
+			slog.Error("Failed to request watchlisted movie",
+				"watchlist_item_id", item.ID,
+				"tmdb_id", item.TmdbID,
+				"error", err)
+		}
+		if request == nil {
+			continue
+		}
+		created = append(created, *request)
+
+		slog.Info("Requested watchlisted movie",
+			"request_id", request.ID,
+			"watchlist_item_id", item.ID,
+			"tmdb_id", item.TmdbID,
+			"status", request.Status)
+
+		if request.Status == "approved" {
+			if err := s.ProcessApprovedRequest(ctx, request.ID); err != nil {
+				slog.Error("Failed to process watchlist request",
+					"request_id", request.ID,
+					"error", err)
+			}
+		}
+	}
+
+	return created, nil
+}
+
+func (s *service) requestWatchlistMovie(ctx context.Context, item repository.WatchlistItem) (*repository.Request, error) {
+	// Nothing to request once it is in the library, the availability
+	// notification takes care of it
+	inLibrary, err := s.repo.CheckMediaInLibrary(ctx, sql.NullString{String: strconv.FormatInt(item.TmdbID, 10), Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to check library: %w", err)
+	}
+	if inLibrary {
+		return nil, nil
+	}
+
+	existing, err := s.repo.GetRequestsByTMDBIDAndMediaType(ctx, repository.GetRequestsByTMDBIDAndMediaTypeParams{
+		TmdbID:    sql.NullInt64{Int64: item.TmdbID, Valid: true},
+		MediaType: "movie",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get existing requests: %w", err)
+	}
+	for _, request := range existing {
+		if request.UserID == item.UserID && !request.Is4k {
+			// The user requested it themselves, stop watching for the release
+			return nil, s.repo.SetWatchlistItemRequest(ctx, repository.SetWatchlistItemRequestParams{
+				RequestID: sql.NullInt64{Int64: request.ID, Valid: true},
+				ID:        item.ID,
+			})
+		}
+	}
+
+	releases, err := s.tmdbService.GetMovieReleaseDates(strconv.FormatInt(item.TmdbID, 10))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get release dates from TMDB: %w", err)
+	}
+	releasedAt, ok := digitalReleaseDate(releases)
+	if !ok || releasedAt.After(time.Now()) {
+		return nil, nil
+	}
+
+	canRequest, err := s.hasPermission(ctx, item.UserID, permissions.RequestMovies)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check request permission: %w", err)
+	}
+	if !canRequest {
+		slog.Debug("User can't request movies, skipping watchlisted movie",
+			"watchlist_item_id", item.ID,
+			"user_id", item.UserID)
+		return nil, nil
+	}
+
+	withinLimit, err := s.withinRequestLimit(ctx, item.UserID, "movie")
+	if err != nil {
+		return nil, fmt.Errorf("failed to check request limit: %w", err)
+	}
+	if !withinLimit {
+		slog.Debug("User reached the movie request limit, skipping watchlisted movie",
+			"watchlist_item_id", item.ID,
+			"user_id", item.UserID)
+		return nil, nil
+	}
+
+	autoApproved, err := s.hasPermission(ctx, item.UserID, permissions.RequestAutoApproveMovies)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check auto-approval permission: %w", err)
+	}
+
+	request, err := s.repo.CreateRequest(ctx, repository.CreateRequestParams{
+		UserID:    item.UserID,
+		MediaType: "movie",
+		TmdbID:    sql.NullInt64{Int64: item.TmdbID, Valid: true},
+		Title:     sql.NullString{String: item.Title, Valid: true},
+		Status:    utils.Ternary(autoApproved, "approved", "pending"),
+		Notes:     sql.NullString{String: "Requested automatically from the watchlist", Valid: true},
+		PosterUrl: item.PosterUrl,
+		Priority:  s.RequestPriority(ctx, item.UserID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if err := s.repo.SetWatchlistItemRequest(ctx, repository.SetWatchlistItemRequestParams{
+		RequestID: sql.NullInt64{Int64: request.ID, Valid: true},
+		ID:        item.ID,
+	}); err != nil {
+		return &request, fmt.Errorf("failed to link request to watchlist item: %w", err)
+	}
+
+	return &request, nil
+}
+
+// digitalReleaseDate returns the earliest digital release date of a movie in
+// any country
+func digitalReleaseDate(releases structures.TMDBReleaseDatesResponse) (time.Time, bool) {
+	var earliest time.Time
+	for _, country := range releases.Results {
+		for _, release := range country.ReleaseDates {
+			if release.Type != tmdbReleaseTypeDigital {
+				continue
+			}
+			releasedAt, err := time.Parse(time.RFC3339, release.ReleaseDate)
+			if err != nil {
+				continue
+			}
+			if earliest.IsZero() || releasedAt.Before(earliest) {
+				earliest = releasedAt
+			}
+		}
+	}
+	return earliest, !earliest.IsZero()
+}
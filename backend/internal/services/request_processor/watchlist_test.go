@@ -0,0 +1,117 @@
+package request_processor
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/mahcks/serra/internal/db/dbtest"
+	"github.com/mahcks/serra/internal/integrations/tmdb"
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+// releaseDatesTMDB answers release date lookups from a fixed set of movies
+type releaseDatesTMDB struct {
+	tmdb.Service
+	releases map[string]structures.TMDBReleaseDatesResponse
+}
+
+func (r releaseDatesTMDB) GetMovieReleaseDates(id string) (structures.TMDBReleaseDatesResponse, error) {
+	return r.releases[id], nil
+}
+
+// digitalRelease is a release date response with one digital release at the given time
+func digitalRelease(at time.Time) structures.TMDBReleaseDatesResponse {
+	return structures.TMDBReleaseDatesResponse{Results: []structures.TMDBCountryReleaseDate{{
+		ISO3166_1:    "US",
+		ReleaseDates: []structures.TMDBReleaseDate{{ReleaseDate: at.Format(time.RFC3339), Type: tmdbReleaseTypeDigital}},
+	}}}
+}
+
+func TestDigitalReleaseDate(t *testing.T) {
+	releases := structures.TMDBReleaseDatesResponse{Results: []structures.TMDBCountryReleaseDate{
+		{ISO3166_1: "US", ReleaseDates: []structures.TMDBReleaseDate{
+			{ReleaseDate: "2024-01-05T00:00:00.000Z", Type: 3},
+			{ReleaseDate: "2024-03-01T00:00:00.000Z", Type: tmdbReleaseTypeDigital},
+		}},
+		{ISO3166_1: "GB", ReleaseDates: []structures.TMDBReleaseDate{
+			{ReleaseDate: "2024-02-10T00:00:00.000Z", Type: tmdbReleaseTypeDigital},
+			{ReleaseDate: "not a date", Type: tmdbReleaseTypeDigital},
+		}},
+	}}
+
+	got, ok := digitalReleaseDate(releases)
+	if !ok {
+		t.Fatal("no digital release found")
+	}
+	if want := time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("digital release = %s, want the earliest one, %s", got, want)
+	}
+
+	theatrical := structures.TMDBReleaseDatesResponse{Results: releases.Results[:1]}
+	theatrical.Results[0].ReleaseDates = theatrical.Results[0].ReleaseDates[:1]
+	if _, ok := digitalReleaseDate(theatrical); ok {
+		t.Error("a theatrical release counted as digital")
+	}
+}
+
+func TestRequestReleasedWatchlistMovies(t *testing.T) {
+	db, query := dbtest.Open(t)
+	ctx := context.Background()
+
+	statements := []string{
+		`INSERT INTO users (id, username) VALUES ('alice', 'alice')`,
+		`INSERT INTO user_permissions (user_id, permission_id) VALUES ('alice', 'request.movies')`,
+		`INSERT INTO library_items (id, name, type, tmdb_id, updated_at) VALUES ('lib', 'In library', 'Movie', '400', CURRENT_TIMESTAMP)`,
+		`INSERT INTO requests (id, user_id, media_type, tmdb_id, title, status) VALUES (50, 'alice', 'movie', 300, 'Requested', 'pending')`,
+		`INSERT INTO watchlist_items (id, user_id, media_type, tmdb_id, title, auto_request) VALUES
+			(1, 'alice', 'movie', 100, 'Released', TRUE),
+			(2, 'alice', 'movie', 200, 'Coming soon', TRUE),
+			(3, 'alice', 'movie', 300, 'Requested', TRUE),
+			(4, 'alice', 'movie', 400, 'In library', TRUE),
+			(5, 'alice', 'movie', 500, 'Not watched', FALSE)`,
+	}
+	for _, statement := range statements {
+		if _, err := db.Exec(statement); err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+
+	s := &service{repo: query, tmdbService: releaseDatesTMDB{releases: map[string]structures.TMDBReleaseDatesResponse{
+		"100": digitalRelease(time.Now().AddDate(0, -1, 0)),
+		"200": digitalRelease(time.Now().AddDate(0, 1, 0)),
+		"500": digitalRelease(time.Now().AddDate(0, -1, 0)),
+	}}}
+
+	created, err := s.RequestReleasedWatchlistMovies(ctx)
+	if err != nil {
+		t.Fatalf("request released watchlist movies: %v", err)
+	}
+	if len(created) != 1 || created[0].TmdbID.Int64 != 100 || created[0].Status != "pending" {
+		t.Fatalf("created %+v, want one pending request for 100", created)
+	}
+
+	items, err := query.GetUserWatchlist(ctx, "alice")
+	if err != nil {
+		t.Fatalf("get watchlist: %v", err)
+	}
+	wantRequest := map[int64]sql.NullInt64{
+		100: {Int64: created[0].ID, Valid: true},
+		300: {Int64: 50, Valid: true}, // Linked to the user's own request
+	}
+	for _, item := range items {
+		if item.RequestID != wantRequest[item.TmdbID] {
+			t.Errorf("%s: request %+v, want %+v", item.Title, item.RequestID, wantRequest[item.TmdbID])
+		}
+	}
+
+	// Linked items are not requested again
+	created, err = s.RequestReleasedWatchlistMovies(ctx)
+	if err != nil {
+		t.Fatalf("second run: %v", err)
+	}
+	if len(created) != 0 {
+		t.Errorf("second run created %+v", created)
+	}
+}
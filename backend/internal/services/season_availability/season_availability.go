@@ -3,6 +3,7 @@ package season_availability
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"log"
 	"strconv"
@@ -81,7 +82,7 @@ func (s *SeasonAvailabilityService) SyncShowAvailability(ctx context.Context, tm
 	}
 
 	// Update existing requests with new availability
-	err = s.updateRequestStatusesFromAvailability(ctx, tmdbID)
+	err = s.updateRequestStatusesFromAvailability(ctx, tmdbID, episodes)
 	if err != nil {
 		log.Printf("Failed to update request statuses for TMDB %d: %v", tmdbID, err)
 	}
@@ -267,13 +268,141 @@ func (s *SeasonAvailabilityService) upsertSeasonAvailability(ctx context.Context
 	return nil
 }
 
-func (s *SeasonAvailabilityService) updateRequestStatusesFromAvailability(ctx context.Context, tmdbID int) error {
-	// For now, just log that we would update request statuses
-	// This will be implemented after we add the missing database queries
-	log.Printf("Would update request statuses for TMDB %d based on availability", tmdbID)
+// updateRequestStatusesFromAvailability refreshes the season statuses of every
+// TV request for the show. Episode requests are tracked per episode and are
+// fulfilled once all requested episodes are available.
+func (s *SeasonAvailabilityService) updateRequestStatusesFromAvailability(ctx context.Context, tmdbID int, episodes []structures.EmbyMediaItem) error {
+	requests, err := s.db.GetRequestsByTMDBIDAndMediaType(ctx, repository.GetRequestsByTMDBIDAndMediaTypeParams{
+		TmdbID:    sql.NullInt64{Int64: int64(tmdbID), Valid: true},
+		MediaType: "tv",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get requests: %w", err)
+	}
+
+	availableEpisodes := make(map[int]map[int]bool)
+	for _, episode := range episodes {
+		if availableEpisodes[episode.SeasonNumber] == nil {
+			availableEpisodes[episode.SeasonNumber] = make(map[int]bool)
+		}
+		availableEpisodes[episode.SeasonNumber][episode.EpisodeNumber] = true
+	}
+
+	now := time.Now().Format(time.RFC3339)
+	for _, request := range requests {
+		if request.Status == "denied" || request.Status == "pending" {
+			continue
+		}
+
+		var requested structures.RequestedEpisodes
+		if request.Episodes.Valid && request.Episodes.String != "" {
+			if err := json.Unmarshal([]byte(request.Episodes.String), &requested); err != nil {
+				log.Printf("Failed to parse episodes of request %d: %v", request.ID, err)
+				continue
+			}
+		}
+
+		var seasons []int
+		if len(requested) > 0 {
+			seasons = requested.Seasons()
+		} else if request.Seasons.Valid && request.Seasons.String != "" {
+			if err := json.Unmarshal([]byte(request.Seasons.String), &seasons); err != nil {
+				log.Printf("Failed to parse seasons of request %d: %v", request.ID, err)
+				continue
+			}
+		} else {
+			// Whole series requests are tracked through Sonarr, not per season
+			continue
+		}
+
+		statuses := make(map[string]structures.SeasonInfo, len(seasons))
+		allAvailable := true
+		for _, season := range seasons {
+			var info structures.SeasonInfo
+			if len(requested) > 0 {
+				info = episodeSeasonInfo(requested[season], availableEpisodes[season])
+			} else {
+				info = s.wholeSeasonInfo(ctx, tmdbID, season, len(availableEpisodes[season]))
+			}
+			if info.Status == "" {
+				info.Status = request.Status
+				if request.Status == "fulfilled" {
+					info.Status = "approved"
+				}
+			}
+			if info.Status != "fulfilled" {
+				allAvailable = false
+			}
+			info.LastUpdated = now
+			statuses[strconv.Itoa(season)] = info
+		}
+
+		statusJSON, err := json.Marshal(statuses)
+		if err != nil {
+			log.Printf("Failed to marshal season statuses of request %d: %v", request.ID, err)
+			continue
+		}
+		if err := s.db.UpdateRequestSeasonStatuses(ctx, repository.UpdateRequestSeasonStatusesParams{
+			SeasonStatuses: sql.NullString{String: string(statusJSON), Valid: true},
+			ID:             request.ID,
+		}); err != nil {
+			log.Printf("Failed to update season statuses of request %d: %v", request.ID, err)
+			continue
+		}
+
+		if len(requested) > 0 && allAvailable && request.Status == "approved" {
+			if _, err := s.db.FulfillRequest(ctx, request.ID); err != nil {
+				log.Printf("Failed to fulfill episode request %d: %v", request.ID, err)
+				continue
+			}
+			log.Printf("✅ Fulfilled episode request %d for TMDB %d - all requested episodes available", request.ID, tmdbID)
+		}
+	}
+
 	return nil
 }
 
+// episodeSeasonInfo reports which of the requested episodes of a season are available
+func episodeSeasonInfo(requested []int, available map[int]bool) structures.SeasonInfo {
+	info := structures.SeasonInfo{
+		TotalEpisodes:     len(requested),
+		RequestedEpisodes: requested,
+	}
+	for _, episode := range requested {
+		if available[episode] {
+			info.AvailableEpisodeNumbers = append(info.AvailableEpisodeNumbers, episode)
+		}
+	}
+	info.AvailableEpisodes = len(info.AvailableEpisodeNumbers)
+	info.Episodes = fmt.Sprintf("%d/%d", info.AvailableEpisodes, info.TotalEpisodes)
+
+	switch {
+	case info.AvailableEpisodes == info.TotalEpisodes:
+		info.Status = "fulfilled"
+	case info.AvailableEpisodes > 0:
+		info.Status = "partial"
+	}
+	return info
+}
+
+// wholeSeasonInfo reports how much of a whole requested season is available
+func (s *SeasonAvailabilityService) wholeSeasonInfo(ctx context.Context, tmdbID int, season int, available int) structures.SeasonInfo {
+	info := structures.SeasonInfo{AvailableEpisodes: available}
+
+	if record, err := s.getSeasonAvailability(ctx, tmdbID, season); err == nil {
+		info.TotalEpisodes = record.EpisodeCount
+	}
+	info.Episodes = fmt.Sprintf("%d/%d", info.AvailableEpisodes, info.TotalEpisodes)
+
+	switch {
+	case info.TotalEpisodes > 0 && info.AvailableEpisodes >= info.TotalEpisodes:
+		info.Status = "fulfilled"
+	case info.AvailableEpisodes > 0:
+		info.Status = "partial"
+	}
+	return info
+}
+
 func (s *SeasonAvailabilityService) getSeasonAvailability(ctx context.Context, tmdbID int, seasonNumber int) (*structures.SeasonAvailability, error) {
 	record, err := s.db.GetSeasonAvailabilityByTMDBIDAndSeason(ctx, repository.GetSeasonAvailabilityByTMDBIDAndSeasonParams{
 		TmdbID:       int64(tmdbID),
@@ -322,7 +451,11 @@ func (s *SeasonAvailabilityService) getSeasonAvailability(ctx context.Context, t
 func (s *SeasonAvailabilityService) updateSeasonRequestStatus(ctx context.Context, tmdbID int, seasonNumber int, availableEpisodes int, totalEpisodes int) error {
 	// This method updates the status of specific season requests
 	// For now, we'll delegate to the more comprehensive updateRequestStatusesFromAvailability
-	return s.updateRequestStatusesFromAvailability(ctx, tmdbID)
+	episodes, err := s.embyClient.GetEpisodesByTMDB(ctx, tmdbID)
+	if err != nil {
+		return fmt.Errorf("failed to get episodes from emby: %w", err)
+	}
+	return s.updateRequestStatusesFromAvailability(ctx, tmdbID, episodes)
 }
 
 // getTotalEpisodesFromTMDB gets the correct total episode count for a season from TMDB
@@ -0,0 +1,187 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+)
+
+// Backup writes a consistent snapshot of the live database to dest using
+// VACUUM INTO, which is safe to run while the server keeps writing in WAL mode.
+// The snapshot is written to a temporary file first so dest never holds a
+// partial backup.
+func (s *sqliteService) Backup(ctx context.Context, dest string) error {
+	if _, err := os.Stat(dest); err == nil {
+		return fmt.Errorf("backup destination %s already exists", dest)
+	}
+
+	tmp := dest + ".tmp"
+	_ = os.Remove(tmp)
+
+	if _, err := s.conn.ExecContext(ctx, "VACUUM INTO ?", tmp); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("failed to snapshot database: %w", err)
+	}
+
+	if err := os.Rename(tmp, dest); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("failed to finalize backup: %w", err)
+	}
+
+	return nil
+}
+
+// Restore replaces the live database with the snapshot at src. The snapshot is
+// verified before anything is touched, the current database is kept aside
+// until the restored one opens cleanly, and the connection pool is swapped
+// while all other queries are blocked. Restores are refused with
+// ErrDatabaseBusy while an operation holds the pool.
+func (s *sqliteService) Restore(ctx context.Context, src string) error {
+	done, err := s.conn.beginRestore()
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	if err := verifyDatabase(ctx, src); err != nil {
+		return fmt.Errorf("backup failed verification: %w", err)
+	}
+
+	// Stage the snapshot next to the live file so the final rename is atomic
+	staged := s.path + ".restore"
+	if err := copyFile(src, staged); err != nil {
+		_ = os.Remove(staged)
+		return fmt.Errorf("failed to stage backup: %w", err)
+	}
+	defer os.Remove(staged)
+
+	// Keep a copy of the current database to roll back to
+	rollback := s.path + ".rollback"
+	_ = os.Remove(rollback)
+	if err := s.Backup(ctx, rollback); err != nil {
+		return fmt.Errorf("failed to snapshot current database: %w", err)
+	}
+	defer os.Remove(rollback)
+
+	s.conn.mu.Lock()
+	defer s.conn.mu.Unlock()
+
+	if err := s.conn.db.Close(); err != nil {
+		slog.Warn("Failed to close database before restore", "error", err)
+	}
+
+	if err := s.replaceFile(staged); err != nil {
+		return s.rollback(ctx, rollback, err)
+	}
+
+	db, err := s.open(ctx)
+	if err != nil {
+		return s.rollback(ctx, rollback, err)
+	}
+
+	s.conn.db = db
+	slog.Info("Database restored", "source", src)
+	return nil
+}
+
+// rollback puts the pre-restore database back in place after a failed restore.
+// The caller must hold the connection lock.
+func (s *sqliteService) rollback(ctx context.Context, rollback string, cause error) error {
+	slog.Error("Database restore failed, rolling back", "error", cause)
+
+	if err := s.replaceFile(rollback); err != nil {
+		return fmt.Errorf("restore failed (%v) and rollback failed: %w", cause, err)
+	}
+
+	db, err := s.open(ctx)
+	if err != nil {
+		return fmt.Errorf("restore failed (%v) and database could not be reopened: %w", cause, err)
+	}
+
+	s.conn.db = db
+	return fmt.Errorf("restore failed, previous database kept: %w", cause)
+}
+
+// replaceFile moves src, which must live next to the database, over the live
+// database file and discards the WAL and shared-memory files of the old one
+func (s *sqliteService) replaceFile(src string) error {
+	for _, suffix := range []string{"-wal", "-shm"} {
+		if err := os.Remove(s.path + suffix); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+
+	return os.Rename(src, s.path)
+}
+
+// open opens a new connection pool on the live database file
+func (s *sqliteService) open(ctx context.Context) (*sql.DB, error) {
+	db, err := sql.Open("sqlite3", s.dsn)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// verifyDatabase checks that the file at path is an intact SQLite database
+func verifyDatabase(ctx context.Context, path string) error {
+	if _, err := os.Stat(path); err != nil {
+		return err
+	}
+
+	db, err := sql.Open("sqlite3", "file:"+filepath.ToSlash(path)+"?mode=ro")
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	var result string
+	if err := db.QueryRowContext(ctx, "PRAGMA integrity_check").Scan(&result); err != nil {
+		return err
+	}
+	if result != "ok" {
+		return fmt.Errorf("integrity check failed: %s", result)
+	}
+
+	var tables int
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'settings'").Scan(&tables); err != nil {
+		return err
+	}
+	if tables == 0 {
+		return errors.New("not a serra database")
+	}
+
+	return nil
+}
+
+func copyFile(src, dest string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dest, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
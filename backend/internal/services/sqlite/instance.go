@@ -1,6 +1,7 @@
 package sqlite
 
 import (
+	"context"
 	"database/sql"
 
 	"github.com/mahcks/serra/internal/db/repository"
@@ -9,12 +10,24 @@ import (
 
 type Service interface {
 	DB() *sql.DB
+	// Hold returns the connection pool for an operation that keeps using it,
+	// such as a transaction. Restores are refused until release is called, and
+	// Hold fails with ErrDatabaseBusy while a restore runs.
+	Hold() (db *sql.DB, release func(), err error)
 	Query() *repository.Queries
 	Close() error
+	// Path returns the location of the database file
+	Path() string
+	// Backup writes a consistent snapshot of the live database to dest
+	Backup(ctx context.Context, dest string) error
+	// Restore replaces the live database with the snapshot at src and reopens it
+	Restore(ctx context.Context, src string) error
 }
 
 type sqliteService struct {
-	db      *sql.DB
+	path    string
+	dsn     string
+	conn    *swappableDB
 	queries *repository.Queries
 }
 
@@ -24,22 +37,32 @@ func NewService(filepath string) (Service, error) {
 		return nil, err
 	}
 
-	queries := repository.New(db)
+	conn := &swappableDB{db: db}
 
 	return &sqliteService{
-		db:      db,
-		queries: queries,
+		path:    filepath,
+		dsn:     filepath,
+		conn:    conn,
+		queries: repository.New(conn),
 	}, nil
 }
 
 func (s *sqliteService) DB() *sql.DB {
-	return s.db
+	return s.conn.current()
+}
+
+func (s *sqliteService) Hold() (*sql.DB, func(), error) {
+	return s.conn.hold()
 }
 
 func (s *sqliteService) Query() *repository.Queries {
 	return s.queries
 }
 
+func (s *sqliteService) Path() string {
+	return s.path
+}
+
 func (s *sqliteService) Close() error {
-	return s.db.Close()
+	return s.conn.current().Close()
 }
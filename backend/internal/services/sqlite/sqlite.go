@@ -20,7 +20,8 @@ func Setup(ctx context.Context, Version string, opts SetupOptions) (Service, err
 		fmt.Println("SQLite database does not exist; will be created on first connect.")
 	}
 
-	db, err := sql.Open("sqlite3", opts.Path+"?_fk=1&_journal_mode=WAL")
+	dsn := opts.Path + "?_fk=1&_journal_mode=WAL"
+	db, err := sql.Open("sqlite3", dsn)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open sqlite database: %w", err)
 	}
@@ -30,9 +31,12 @@ func Setup(ctx context.Context, Version string, opts SetupOptions) (Service, err
 		return nil, fmt.Errorf("failed to ping sqlite database: %w", err)
 	}
 
+	conn := &swappableDB{db: db}
 	svc := &sqliteService{
-		db:      db,
-		queries: repository.New(db),
+		path:    opts.Path,
+		dsn:     dsn,
+		conn:    conn,
+		queries: repository.New(conn),
 	}
 
 	return svc, nil
@@ -0,0 +1,94 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+)
+
+// ErrDatabaseBusy is returned by Restore while operations hold the connection
+// pool, and by Hold while a restore runs
+var ErrDatabaseBusy = errors.New("database is busy")
+
+// swappableDB implements repository.DBTX on top of a connection pool that can be
+// replaced at runtime, so the Queries handed out at startup survive a restore
+type swappableDB struct {
+	mu sync.RWMutex
+	db *sql.DB
+
+	// Operations that keep using the pool across calls, such as transactions,
+	// which a restore must not close it under
+	holdMu    sync.Mutex
+	holds     int
+	restoring bool
+}
+
+// hold returns the current pool and keeps restores out until release is called
+func (s *swappableDB) hold() (*sql.DB, func(), error) {
+	s.holdMu.Lock()
+	defer s.holdMu.Unlock()
+
+	if s.restoring {
+		return nil, nil, ErrDatabaseBusy
+	}
+	s.holds++
+
+	var once sync.Once
+	release := func() {
+		once.Do(func() {
+			s.holdMu.Lock()
+			defer s.holdMu.Unlock()
+			s.holds--
+		})
+	}
+	return s.current(), release, nil
+}
+
+// beginRestore refuses to start while the pool is held and keeps new holds out
+// until the returned function is called
+func (s *swappableDB) beginRestore() (func(), error) {
+	s.holdMu.Lock()
+	defer s.holdMu.Unlock()
+
+	if s.holds > 0 || s.restoring {
+		return nil, ErrDatabaseBusy
+	}
+	s.restoring = true
+
+	return func() {
+		s.holdMu.Lock()
+		defer s.holdMu.Unlock()
+		s.restoring = false
+	}, nil
+}
+
+func (s *swappableDB) current() *sql.DB {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.db
+}
+
+func (s *swappableDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.db.ExecContext(ctx, query, args...)
+}
+
+func (s *swappableDB) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.db.PrepareContext(ctx, query)
+}
+
+func (s *swappableDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.db.QueryContext(ctx, query, args...)
+}
+
+func (s *swappableDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.db.QueryRowContext(ctx, query, args...)
+}
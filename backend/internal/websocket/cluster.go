@@ -0,0 +1,219 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/mahcks/serra/internal/eventbus"
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+// Event bus topics the instances exchange websocket traffic on
+const (
+	busTopicEvents   = "websocket.events"
+	busTopicPresence = "websocket.presence"
+)
+
+const (
+	// presenceInterval is how often instances announce their clients
+	presenceInterval = 15 * time.Second
+	// presenceTTL is how long an instance's clients count without an announcement
+	presenceTTL = 3 * presenceInterval
+)
+
+// clusterEventKind is how a forwarded event picks its recipients
+type clusterEventKind string
+
+const (
+	clusterEventAll   clusterEventKind = "all"   // Every client
+	clusterEventTopic clusterEventKind = "topic" // The clients subscribed to Topic
+	clusterEventUser  clusterEventKind = "user"  // UserID, when subscribed to Topic if set
+)
+
+// clusterEvent is an event forwarded to the other instances, which send it to
+// their own clients
+type clusterEvent struct {
+	Kind   clusterEventKind  `json:"kind"`
+	UserID string            `json:"user_id,omitempty"`
+	Topic  structures.Topic  `json:"topic,omitempty"`
+	Except structures.Topic  `json:"except,omitempty"` // Subscribers left out of a topic event
+	Op     structures.Opcode `json:"op"`
+	Data   json.RawMessage   `json:"data"`
+}
+
+// presenceClient is a client connected to another instance
+type presenceClient struct {
+	UserID           string `json:"user_id"`
+	IsAdmin          bool   `json:"is_admin"`
+	WatchesDownloads bool   `json:"watches_downloads"`
+}
+
+// nodePresence announces the clients connected to an instance
+type nodePresence struct {
+	Clients []presenceClient `json:"clients"`
+	Leaving bool             `json:"leaving,omitempty"` // The instance is shutting down
+	seenAt  time.Time
+}
+
+// useEventBus shares the manager's events with the other instances on the bus
+func (m *Manager) useEventBus(bus eventbus.Bus) {
+	m.bus = bus
+	m.serverID = bus.NodeID()
+
+	bus.Subscribe(busTopicEvents, m.handleClusterEvent)
+	bus.Subscribe(busTopicPresence, m.handlePresence)
+
+	go m.announcePresence()
+}
+
+// forward publishes an event for the clients of the other instances
+func (m *Manager) forward(event clusterEvent, data interface{}) {
+	if m.bus == nil {
+		return
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		slog.Error("Failed to marshal forwarded websocket event", "opcode", event.Op, "error", err)
+		return
+	}
+	event.Data = raw
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		slog.Error("Failed to marshal forwarded websocket event", "opcode", event.Op, "error", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(m.ctx, 5*time.Second)
+	defer cancel()
+	if err := m.bus.Publish(ctx, busTopicEvents, payload); err != nil {
+		slog.Warn("Failed to forward websocket event", "opcode", event.Op, "error", err)
+	}
+}
+
+// handleClusterEvent sends an event forwarded by another instance to this
+// instance's clients
+func (m *Manager) handleClusterEvent(busEvent eventbus.Event) {
+	if busEvent.Node == m.bus.NodeID() {
+		return
+	}
+
+	var event clusterEvent
+	if err := json.Unmarshal(busEvent.Payload, &event); err != nil {
+		slog.Warn("Invalid forwarded websocket event", "node", busEvent.Node, "error", err)
+		return
+	}
+	if !event.Op.IsValid() {
+		// Sent by a newer version
+		return
+	}
+
+	switch event.Kind {
+	case clusterEventAll:
+		m.broadcastLocal(event.Op, event.Data)
+	case clusterEventTopic:
+		m.publishLocal(event.Topic, event.Except, event.Op, event.Data)
+	case clusterEventUser:
+		if err := m.sendEvent(event.UserID, event.Topic, event.Op, event.Data); err != nil {
+			slog.Warn("Failed to send forwarded websocket event", "user_id", event.UserID, "opcode", event.Op, "error", err)
+		}
+	}
+}
+
+// presenceChanged asks for this instance's clients to be announced
+func (m *Manager) presenceChanged() {
+	if m.bus == nil {
+		return
+	}
+	select {
+	case m.presence <- struct{}{}:
+	default:
+	}
+}
+
+// announcePresence publishes this instance's clients when they change and
+// periodically, so the other instances know who is connected where
+func (m *Manager) announcePresence() {
+	ticker := time.NewTicker(presenceInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.ctx.Done():
+			m.publishPresence(nodePresence{Leaving: true})
+			return
+		case <-m.presence:
+		case <-ticker.C:
+		}
+
+		m.clientsMutex.RLock()
+		presence := nodePresence{Clients: make([]presenceClient, 0, len(m.clients))}
+		for userID, client := range m.clients {
+			presence.Clients = append(presence.Clients, presenceClient{
+				UserID:           userID,
+				IsAdmin:          client.User != nil && client.User.IsAdmin,
+				WatchesDownloads: client.watchesDownloads(),
+			})
+		}
+		m.clientsMutex.RUnlock()
+
+		m.publishPresence(presence)
+	}
+}
+
+func (m *Manager) publishPresence(presence nodePresence) {
+	payload, err := json.Marshal(presence)
+	if err != nil {
+		slog.Error("Failed to marshal websocket presence", "error", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := m.bus.Publish(ctx, busTopicPresence, payload); err != nil {
+		slog.Warn("Failed to announce websocket presence", "error", err)
+	}
+}
+
+// handlePresence records the clients another instance announced
+func (m *Manager) handlePresence(busEvent eventbus.Event) {
+	if busEvent.Node == m.bus.NodeID() {
+		return
+	}
+
+	var presence nodePresence
+	if err := json.Unmarshal(busEvent.Payload, &presence); err != nil {
+		slog.Warn("Invalid websocket presence", "node", busEvent.Node, "error", err)
+		return
+	}
+
+	m.nodesMutex.Lock()
+	defer m.nodesMutex.Unlock()
+
+	if presence.Leaving {
+		delete(m.nodes, busEvent.Node)
+		return
+	}
+	presence.seenAt = time.Now()
+	m.nodes[busEvent.Node] = presence
+}
+
+// remoteClients returns the clients connected to the other instances that
+// announced themselves recently
+func (m *Manager) remoteClients() []presenceClient {
+	m.nodesMutex.Lock()
+	defer m.nodesMutex.Unlock()
+
+	var clients []presenceClient
+	for node, presence := range m.nodes {
+		if time.Since(presence.seenAt) > presenceTTL {
+			delete(m.nodes, node)
+			continue
+		}
+		clients = append(clients, presence.Clients...)
+	}
+	return clients
+}
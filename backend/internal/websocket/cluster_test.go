@@ -0,0 +1,173 @@
+package websocket
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/mahcks/serra/internal/eventbus"
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+// sharedBus connects instances in process, delivering every event to every
+// instance like Redis pub/sub does
+type sharedBus struct {
+	mu       sync.Mutex
+	handlers map[string][]*eventbus.Handler
+}
+
+// sharedBusNode is one instance's view of the shared bus
+type sharedBusNode struct {
+	bus    *sharedBus
+	nodeID string
+}
+
+func newSharedBus() *sharedBus {
+	return &sharedBus{handlers: make(map[string][]*eventbus.Handler)}
+}
+
+func (b *sharedBus) join(nodeID string) *sharedBusNode {
+	return &sharedBusNode{bus: b, nodeID: nodeID}
+}
+
+func (n *sharedBusNode) NodeID() string {
+	return n.nodeID
+}
+
+func (n *sharedBusNode) Publish(ctx context.Context, topic string, payload []byte) error {
+	n.bus.mu.Lock()
+	handlers := slices.Clone(n.bus.handlers[topic])
+	n.bus.mu.Unlock()
+
+	for _, handler := range handlers {
+		(*handler)(eventbus.Event{Topic: topic, Node: n.nodeID, Payload: payload})
+	}
+	return nil
+}
+
+func (n *sharedBusNode) Subscribe(topic string, handler eventbus.Handler) func() {
+	n.bus.mu.Lock()
+	defer n.bus.mu.Unlock()
+
+	registered := &handler
+	n.bus.handlers[topic] = append(n.bus.handlers[topic], registered)
+	return func() {
+		n.bus.mu.Lock()
+		defer n.bus.mu.Unlock()
+		n.bus.handlers[topic] = slices.DeleteFunc(n.bus.handlers[topic], func(h *eventbus.Handler) bool {
+			return h == registered
+		})
+	}
+}
+
+func (n *sharedBusNode) Close() error {
+	return nil
+}
+
+// newCluster starts two instances sharing a bus
+func newCluster(t *testing.T) (a, b *testServer) {
+	t.Helper()
+	bus := newSharedBus()
+
+	managerA, managerB := newTestManager(), newTestManager()
+	managerA.useEventBus(bus.join("node-a"))
+	managerB.useEventBus(bus.join("node-b"))
+	return newTestServer(t, managerA), newTestServer(t, managerB)
+}
+
+func TestClusterForwardsEventsToOtherInstances(t *testing.T) {
+	a, b := newCluster(t)
+
+	admin := b.connect(t, "admin", true)
+	user := b.connect(t, "user", false)
+	if admin.hello.ServerID != "node-b" {
+		t.Fatalf("hello should name the instance, got %q", admin.hello.ServerID)
+	}
+
+	admin.send(structures.OpcodeSubscribe, structures.SubscribePayload{Topics: []structures.Topic{structures.TopicJobs}})
+	admin.expect(structures.OpcodeAck)
+
+	// Every client
+	a.manager.BroadcastToAll(structures.OpcodeSystemStatus, structures.SystemStatusPayload{})
+	admin.expect(structures.OpcodeSystemStatus)
+	user.expect(structures.OpcodeSystemStatus)
+
+	// The subscribers of a topic
+	a.manager.Publish(structures.TopicJobs, structures.OpcodeJobStatus, structures.JobStatusPayload{Name: "sync"})
+	var status structures.JobStatusPayload
+	admin.decode(admin.expect(structures.OpcodeJobStatus), &status)
+	if status.Name != "sync" {
+		t.Fatalf("unexpected job status %+v", status)
+	}
+	user.expectNone()
+
+	// One user, connected to the other instance
+	if err := a.manager.SendToUser("user", structures.OpcodeNotification, map[string]string{"id": "1"}); err == nil {
+		t.Fatal("user isn't connected to this instance")
+	}
+	user.expect(structures.OpcodeNotification)
+	admin.expectNone()
+
+	// Only when subscribed to the topic
+	a.manager.PublishToUser("user", structures.TopicJobs, structures.OpcodeJobStatus, structures.JobStatusPayload{})
+	a.manager.PublishToUser("admin", structures.TopicJobs, structures.OpcodeJobStatus, structures.JobStatusPayload{})
+	admin.expect(structures.OpcodeJobStatus)
+	user.expectNone()
+}
+
+func TestClusterSharesPresence(t *testing.T) {
+	a, b := newCluster(t)
+
+	a.connect(t, "alice", false)
+	bob := b.connect(t, "bob", true)
+
+	eventually(t, "presence of both users", func() bool {
+		users := a.manager.GetConnectedUsers()
+		slices.Sort(users)
+		return slices.Equal(users, []string{"alice", "bob"})
+	})
+
+	clients := a.manager.GetConnectedClients()
+	for _, client := range clients {
+		if client.UserID == "bob" && !client.IsAdmin {
+			t.Fatal("remote admin should be reported as admin")
+		}
+	}
+	if watchers := a.manager.downloadWatchers(); watchers != 2 {
+		t.Fatalf("expected 2 download watchers, got %d", watchers)
+	}
+
+	bob.send(structures.OpcodeUnsubscribe, structures.SubscribePayload{Topics: []structures.Topic{structures.TopicDownloads}})
+	bob.expect(structures.OpcodeAck)
+	eventually(t, "bob to stop watching downloads", func() bool {
+		return a.manager.downloadWatchers() == 1
+	})
+
+	bob.conn.Close()
+	eventually(t, "bob to disconnect", func() bool {
+		return slices.Equal(a.manager.GetConnectedUsers(), []string{"alice"})
+	})
+}
+
+func TestResumeOnAnotherInstanceRequiresResync(t *testing.T) {
+	a, b := newCluster(t)
+
+	first := a.connect(t, "user", false)
+	a.manager.BroadcastToAll(structures.OpcodeSystemStatus, structures.SystemStatusPayload{})
+	event := first.expect(structures.OpcodeSystemStatus)
+	first.conn.Close()
+
+	// The load balancer sends the reconnect to the other instance
+	second := b.connect(t, "user", false)
+	second.send(structures.OpcodeResume, structures.ResumePayload{
+		LastSequence: *event.Sequence,
+		ServerID:     first.hello.ServerID,
+	})
+
+	var resumed structures.ResumedPayload
+	second.decode(second.expect(structures.OpcodeResumed), &resumed)
+	if !resumed.ResyncRequired || resumed.Replayed != 0 {
+		t.Fatalf("expected a resync, got %+v", resumed)
+	}
+}
@@ -0,0 +1,88 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+// OperationFunc handles an operation called by a client and returns the data
+// to answer with
+type OperationFunc func(ctx context.Context, client ConnectedClient, params json.RawMessage) (interface{}, error)
+
+var (
+	operationsMutex sync.RWMutex
+	operations      = make(map[string]OperationFunc)
+)
+
+// HandleOperation registers the handler of an operation clients call with
+// OpcodeRequest, e.g. "notifications.mark_read"
+func HandleOperation(method string, fn OperationFunc) {
+	operationsMutex.Lock()
+	defer operationsMutex.Unlock()
+	operations[method] = fn
+}
+
+// OperationError is returned by operations to answer with an error code
+type OperationError struct {
+	Code    string
+	Message string
+}
+
+func (e *OperationError) Error() string {
+	return e.Message
+}
+
+// handleRequest runs an operation called by a client and sends the response
+func (m *Manager) handleRequest(client *Client, msg structures.Message) error {
+	var request structures.RequestPayload
+	if err := msg.DecodeData(&request); err != nil || request.Method == "" {
+		return m.sendError(client, "Invalid request")
+	}
+
+	response := structures.ResponsePayload{
+		ID:     request.ID,
+		Method: request.Method,
+	}
+
+	operationsMutex.RLock()
+	fn, ok := operations[request.Method]
+	operationsMutex.RUnlock()
+
+	if !ok {
+		response.Error = &structures.ErrorPayload{
+			Message:   "Unknown operation",
+			Code:      "unknown_operation",
+			RequestID: request.ID,
+		}
+		return m.sendMessage(client, structures.OpcodeResponse, response)
+	}
+
+	ctx, cancel := context.WithTimeout(client.ctx, 30*time.Second)
+	defer cancel()
+
+	data, err := fn(ctx, client.connected(), request.Params)
+	if err != nil {
+		response.Error = &structures.ErrorPayload{
+			Message:   "Operation failed",
+			Code:      "internal_error",
+			RequestID: request.ID,
+		}
+		var opErr *OperationError
+		if errors.As(err, &opErr) {
+			response.Error.Message = opErr.Message
+			response.Error.Code = opErr.Code
+		} else {
+			slog.Error("WebSocket operation failed", "userID", client.UserID, "method", request.Method, "error", err)
+		}
+	} else {
+		response.Data = data
+	}
+
+	return m.sendMessage(client, structures.OpcodeResponse, response)
+}
@@ -0,0 +1,74 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+func TestOperations(t *testing.T) {
+	HandleOperation("test.echo", func(ctx context.Context, client ConnectedClient, params json.RawMessage) (interface{}, error) {
+		var req struct {
+			Text string `json:"text"`
+		}
+		if err := json.Unmarshal(params, &req); err != nil {
+			return nil, &OperationError{Code: "bad_request", Message: "Invalid params"}
+		}
+		return map[string]interface{}{"text": req.Text, "user_id": client.UserID, "is_admin": client.IsAdmin}, nil
+	})
+	HandleOperation("test.broken", func(ctx context.Context, client ConnectedClient, params json.RawMessage) (interface{}, error) {
+		return nil, errors.New("database is gone")
+	})
+
+	server := newTestServer(t, newTestManager())
+	client := server.connect(t, "alice", false)
+
+	call := func(request structures.RequestPayload) structures.ResponsePayload {
+		t.Helper()
+		client.send(structures.OpcodeRequest, request)
+		var response structures.ResponsePayload
+		client.decode(client.expect(structures.OpcodeResponse), &response)
+		if response.ID != request.ID || response.Method != request.Method {
+			t.Fatalf("response to %s %s answers %s %s", request.ID, request.Method, response.ID, response.Method)
+		}
+		return response
+	}
+
+	response := call(structures.RequestPayload{ID: "1", Method: "test.echo", Params: json.RawMessage(`{"text": "hi"}`)})
+	data, _ := response.Data.(map[string]interface{})
+	if response.Error != nil || data["text"] != "hi" || data["user_id"] != "alice" || data["is_admin"] != false {
+		t.Errorf("echo answered %+v, error %+v", response.Data, response.Error)
+	}
+
+	tests := []struct {
+		name     string
+		request  structures.RequestPayload
+		wantCode string
+		wantMsg  string
+	}{
+		{"operation error", structures.RequestPayload{ID: "2", Method: "test.echo", Params: json.RawMessage(`[]`)}, "bad_request", "Invalid params"},
+		{"other error", structures.RequestPayload{ID: "3", Method: "test.broken"}, "internal_error", "Operation failed"},
+		{"unknown method", structures.RequestPayload{ID: "4", Method: "test.missing"}, "unknown_operation", "Unknown operation"},
+	}
+	for _, test := range tests {
+		response := call(test.request)
+		if response.Error == nil {
+			t.Errorf("%s: answered %+v without an error", test.name, response.Data)
+			continue
+		}
+		if response.Error.Code != test.wantCode || response.Error.Message != test.wantMsg || response.Error.RequestID != test.request.ID {
+			t.Errorf("%s: error = %+v, want %s %q", test.name, response.Error, test.wantCode, test.wantMsg)
+		}
+	}
+
+	// Requests without a method cannot be answered
+	client.send(structures.OpcodeRequest, structures.RequestPayload{ID: "5"})
+	var payload structures.ErrorPayload
+	client.decode(client.expect(structures.OpcodeError), &payload)
+	if payload.Message != "Invalid request" {
+		t.Errorf("error = %q, want Invalid request", payload.Message)
+	}
+}
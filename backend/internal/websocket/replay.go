@@ -0,0 +1,271 @@
+package websocket
+
+import (
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+// replayEvent is a dispatched event kept for replay
+type replayEvent struct {
+	sequence uint64
+	payload  []byte
+}
+
+// replayBuffer keeps the last events dispatched to a user so a client that
+// reconnects can resume where it left off. It outlives the connection for the
+// manager's replay TTL and keeps recording events for the user's
+// subscriptions meanwhile.
+type replayBuffer struct {
+	mu      sync.Mutex
+	events  []replayEvent // Oldest first
+	dropped uint64        // Highest sequence evicted from the buffer
+
+	// Subscriptions of the last connection, used while the user is away
+	topics     map[structures.Topic]bool
+	detachedAt time.Time // Zero while the user is connected
+}
+
+// add records an event, evicting the oldest beyond size. The caller holds mu.
+func (b *replayBuffer) add(sequence uint64, payload []byte, size int) {
+	b.events = append(b.events, replayEvent{sequence: sequence, payload: payload})
+	if len(b.events) > size {
+		evicted := len(b.events) - size
+		b.dropped = b.events[evicted-1].sequence
+		b.events = append([]replayEvent(nil), b.events[evicted:]...)
+	}
+}
+
+// since returns the events after a sequence. ok is false when some of them
+// were already evicted. The caller holds mu.
+func (b *replayBuffer) since(sequence uint64) (events []replayEvent, ok bool) {
+	if sequence < b.dropped {
+		return nil, false
+	}
+	for i, event := range b.events {
+		if event.sequence > sequence {
+			return b.events[i:], true
+		}
+	}
+	return nil, true
+}
+
+// receives reports whether a detached user would have been sent an event. The caller holds mu.
+func (b *replayBuffer) receives(op structures.Opcode) bool {
+	topic, scoped := structures.TopicForOpcode(op)
+	return !scoped || b.topics[topic]
+}
+
+// replayStore holds the replay buffers by user ID
+type replayStore struct {
+	mu      sync.Mutex
+	buffers map[string]*replayBuffer
+	size    int
+	ttl     time.Duration
+}
+
+func newReplayStore(size int, ttl time.Duration) *replayStore {
+	return &replayStore{
+		buffers: make(map[string]*replayBuffer),
+		size:    size,
+		ttl:     ttl,
+	}
+}
+
+// attach returns the user's buffer for a new connection, creating it when the
+// user has none or it expired. A new buffer counts the events up to the
+// current sequence as missed.
+func (s *replayStore) attach(userID string, sequence uint64) *replayBuffer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pruneLocked()
+
+	buffer, ok := s.buffers[userID]
+	if !ok {
+		buffer = &replayBuffer{dropped: sequence}
+		s.buffers[userID] = buffer
+	}
+
+	buffer.mu.Lock()
+	buffer.detachedAt = time.Time{}
+	buffer.mu.Unlock()
+
+	return buffer
+}
+
+// detach keeps recording events for a user who disconnected, for the
+// subscriptions they had
+func (s *replayStore) detach(userID string, topics []structures.Topic) {
+	s.mu.Lock()
+	buffer, ok := s.buffers[userID]
+	s.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	buffer.mu.Lock()
+	defer buffer.mu.Unlock()
+
+	buffer.detachedAt = time.Now()
+	buffer.topics = make(map[structures.Topic]bool, len(topics))
+	for _, topic := range topics {
+		buffer.topics[topic] = true
+	}
+}
+
+// detached returns the buffers of users who are away
+func (s *replayStore) detached() map[string]*replayBuffer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pruneLocked()
+
+	buffers := make(map[string]*replayBuffer)
+	for userID, buffer := range s.buffers {
+		buffer.mu.Lock()
+		if !buffer.detachedAt.IsZero() {
+			buffers[userID] = buffer
+		}
+		buffer.mu.Unlock()
+	}
+	return buffers
+}
+
+// get returns a user's buffer when they have one
+func (s *replayStore) get(userID string) (*replayBuffer, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	buffer, ok := s.buffers[userID]
+	return buffer, ok
+}
+
+// pruneLocked drops the buffers of users away for longer than the TTL. The caller holds mu.
+func (s *replayStore) pruneLocked() {
+	for userID, buffer := range s.buffers {
+		buffer.mu.Lock()
+		expired := !buffer.detachedAt.IsZero() && time.Since(buffer.detachedAt) > s.ttl
+		buffer.mu.Unlock()
+		if expired {
+			delete(s.buffers, userID)
+		}
+	}
+}
+
+// newEvent marshals a message. Dispatched events get the next sequence
+// number, other messages 0.
+func (m *Manager) newEvent(op structures.Opcode, data interface{}) (uint64, []byte, error) {
+	var sequence uint64
+	msg := structures.NewMessage(op, data)
+	if op.IsSequenced() {
+		sequence = m.sequence.Add(1)
+		msg = structures.NewMessageWithSequence(op, data, sequence)
+	}
+
+	payload, err := structures.MarshalMessage(msg)
+	return sequence, payload, err
+}
+
+// deliver records an event for replay and queues it for the client. It returns
+// false when the client's buffer is full.
+func (m *Manager) deliver(client *Client, sequence uint64, payload []byte) bool {
+	if sequence > 0 && client.replay != nil {
+		client.replay.mu.Lock()
+		client.replay.add(sequence, payload, m.replays.size)
+		client.replay.mu.Unlock()
+	}
+
+	select {
+	case client.sendChan <- payload:
+		return true
+	case <-client.ctx.Done():
+		return false
+	default:
+		return false
+	}
+}
+
+// recordDetached records an event for a user who is away, unless they were
+// subscribed to except
+func (m *Manager) recordDetached(buffer *replayBuffer, op structures.Opcode, topic, except structures.Topic, sequence uint64, payload []byte) {
+	if sequence == 0 {
+		return
+	}
+
+	buffer.mu.Lock()
+	defer buffer.mu.Unlock()
+
+	if buffer.detachedAt.IsZero() {
+		return
+	}
+	if topic != "" {
+		if !buffer.topics[topic] || (except != "" && buffer.topics[except]) {
+			return
+		}
+	} else if !buffer.receives(op) {
+		return
+	}
+	buffer.add(sequence, payload, m.replays.size)
+}
+
+// handleResume replays the events a reconnecting client missed after the last
+// sequence it saw and restores its subscriptions, or tells it to resync when
+// the missed events are no longer all buffered
+func (m *Manager) handleResume(client *Client, msg structures.Message) error {
+	var payload structures.ResumePayload
+	if err := msg.DecodeData(&payload); err != nil {
+		return m.sendError(client, "Invalid resume")
+	}
+
+	resumed := structures.ResumedPayload{Sequence: m.sequence.Load()}
+
+	buffer := client.replay
+	if buffer == nil || payload.LastSequence > resumed.Sequence || payload.ServerID != "" && payload.ServerID != m.serverID {
+		// The sequence comes from another instance or before a server restart
+		resumed.ResyncRequired = true
+		return m.sendMessage(client, structures.OpcodeResumed, resumed)
+	}
+
+	buffer.mu.Lock()
+	restored := make([]structures.Topic, 0, len(buffer.topics))
+	for topic := range buffer.topics {
+		if !client.subscribed(topic) {
+			client.subscribe(topic)
+			restored = append(restored, topic)
+		}
+	}
+	events, ok := buffer.since(payload.LastSequence)
+	if !ok {
+		resumed.ResyncRequired = true
+	}
+	// Copied so new events aren't held up while the replay waits on the client
+	events = slices.Clone(events)
+	buffer.mu.Unlock()
+
+	for _, event := range events {
+		select {
+		case client.sendChan <- event.payload:
+			resumed.Replayed++
+		case <-client.ctx.Done():
+			return &ClientError{Message: "Client disconnected"}
+		case <-time.After(5 * time.Second):
+			resumed.ResyncRequired = true
+		}
+		if resumed.ResyncRequired {
+			break
+		}
+	}
+
+	slog.Debug("Client resumed", "userID", client.UserID, "lastSequence", payload.LastSequence, "replayed", resumed.Replayed, "resyncRequired", resumed.ResyncRequired)
+	resumed.Topics = client.subscriptions()
+	m.presenceChanged()
+	if err := m.sendMessage(client, structures.OpcodeResumed, resumed); err != nil {
+		return err
+	}
+
+	go runSubscribeHooks(client.connected(), restored)
+	return nil
+}
@@ -0,0 +1,70 @@
+package websocket
+
+import (
+	"testing"
+
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+func TestResumeReplaysMissedEvents(t *testing.T) {
+	server := newTestServer(t, newTestManager())
+	manager := server.manager
+
+	first := server.connect(t, "user", false)
+	manager.BroadcastToAll(structures.OpcodeSystemStatus, structures.SystemStatusPayload{})
+	seen := first.expect(structures.OpcodeSystemStatus)
+	first.conn.Close()
+	eventually(t, "the user to disconnect", func() bool {
+		return manager.getConnectionCount() == 0
+	})
+
+	// Missed while away; the job status is for a topic the user wasn't subscribed to
+	manager.BroadcastToAll(structures.OpcodeSystemStatus, structures.SystemStatusPayload{})
+	manager.Publish(structures.TopicJobs, structures.OpcodeJobStatus, structures.JobStatusPayload{})
+	manager.BroadcastToAll(structures.OpcodeBandwidthState, structures.BandwidthState{})
+
+	second := server.connect(t, "user", false)
+	second.send(structures.OpcodeResume, structures.ResumePayload{
+		LastSequence: *seen.Sequence,
+		ServerID:     first.hello.ServerID,
+	})
+
+	missed := second.expect(structures.OpcodeSystemStatus)
+	if *missed.Sequence != *seen.Sequence+1 {
+		t.Fatalf("expected sequence %d, got %d", *seen.Sequence+1, *missed.Sequence)
+	}
+	second.expect(structures.OpcodeBandwidthState)
+
+	var resumed structures.ResumedPayload
+	second.decode(second.expect(structures.OpcodeResumed), &resumed)
+	if resumed.Replayed != 2 || resumed.ResyncRequired || resumed.Sequence != *seen.Sequence+3 {
+		t.Fatalf("unexpected resume %+v", resumed)
+	}
+}
+
+func TestResumeAfterEvictionRequiresResync(t *testing.T) {
+	manager := newTestManager()
+	manager.replays = newReplayStore(2, manager.replays.ttl)
+	server := newTestServer(t, manager)
+
+	first := server.connect(t, "user", false)
+	manager.BroadcastToAll(structures.OpcodeSystemStatus, structures.SystemStatusPayload{})
+	seen := first.expect(structures.OpcodeSystemStatus)
+	first.conn.Close()
+	eventually(t, "the user to disconnect", func() bool {
+		return manager.getConnectionCount() == 0
+	})
+
+	for i := 0; i < 3; i++ {
+		manager.BroadcastToAll(structures.OpcodeSystemStatus, structures.SystemStatusPayload{})
+	}
+
+	second := server.connect(t, "user", false)
+	second.send(structures.OpcodeResume, structures.ResumePayload{LastSequence: *seen.Sequence})
+
+	var resumed structures.ResumedPayload
+	second.decode(second.expect(structures.OpcodeResumed), &resumed)
+	if !resumed.ResyncRequired || resumed.Replayed != 0 {
+		t.Fatalf("expected a resync, got %+v", resumed)
+	}
+}
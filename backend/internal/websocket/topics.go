@@ -0,0 +1,292 @@
+package websocket
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mahcks/serra/pkg/permissions"
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+// SubscribeFunc is called when a client subscribes to a topic, to send it the
+// current state of the topic
+type SubscribeFunc func(client ConnectedClient, topic structures.Topic)
+
+var (
+	subscribeHooksMutex sync.RWMutex
+	subscribeHooks      = make(map[structures.Topic]map[int]SubscribeFunc)
+	nextSubscribeHook   int
+)
+
+// OnSubscribe registers a callback run whenever a client subscribes to a
+// topic, including the default topics on connect. Request topics are
+// registered as TopicRequest. The returned function removes it again.
+func OnSubscribe(topic structures.Topic, fn SubscribeFunc) func() {
+	subscribeHooksMutex.Lock()
+	defer subscribeHooksMutex.Unlock()
+
+	id := nextSubscribeHook
+	nextSubscribeHook++
+	if subscribeHooks[topic] == nil {
+		subscribeHooks[topic] = make(map[int]SubscribeFunc)
+	}
+	subscribeHooks[topic][id] = fn
+
+	return func() {
+		subscribeHooksMutex.Lock()
+		defer subscribeHooksMutex.Unlock()
+		delete(subscribeHooks[topic], id)
+	}
+}
+
+// runSubscribeHooks runs the callbacks registered for the topics a client subscribed to
+func runSubscribeHooks(client ConnectedClient, topics []structures.Topic) {
+	for _, topic := range topics {
+		subscribeHooksMutex.RLock()
+		hooks := make([]SubscribeFunc, 0, len(subscribeHooks[topic.Base()]))
+		for _, fn := range subscribeHooks[topic.Base()] {
+			hooks = append(hooks, fn)
+		}
+		subscribeHooksMutex.RUnlock()
+
+		for _, fn := range hooks {
+			fn(client, topic)
+		}
+	}
+}
+
+// subscribe adds a topic to the client's subscriptions
+func (c *Client) subscribe(topic structures.Topic) {
+	c.topicsMutex.Lock()
+	defer c.topicsMutex.Unlock()
+	c.topics[topic] = true
+}
+
+// unsubscribe removes a topic from the client's subscriptions
+func (c *Client) unsubscribe(topic structures.Topic) {
+	c.topicsMutex.Lock()
+	defer c.topicsMutex.Unlock()
+	delete(c.topics, topic)
+}
+
+// subscribed reports whether the client is subscribed to a topic
+func (c *Client) subscribed(topic structures.Topic) bool {
+	c.topicsMutex.RLock()
+	defer c.topicsMutex.RUnlock()
+	return c.topics[topic]
+}
+
+// watchesDownloads reports whether the client receives any download progress
+func (c *Client) watchesDownloads() bool {
+	c.topicsMutex.RLock()
+	defer c.topicsMutex.RUnlock()
+
+	for topic := range c.topics {
+		if topic == structures.TopicDownloads || topic.Base() == structures.TopicRequest {
+			return true
+		}
+	}
+	return false
+}
+
+// subscriptions returns the topics the client is subscribed to
+func (c *Client) subscriptions() []structures.Topic {
+	c.topicsMutex.RLock()
+	defer c.topicsMutex.RUnlock()
+
+	topics := make([]structures.Topic, 0, len(c.topics))
+	for topic := range c.topics {
+		topics = append(topics, topic)
+	}
+	sort.Slice(topics, func(i, j int) bool { return topics[i] < topics[j] })
+	return topics
+}
+
+// receives reports whether an event sent with an opcode reaches the client
+func (c *Client) receives(op structures.Opcode) bool {
+	topic, scoped := structures.TopicForOpcode(op)
+	return !scoped || c.subscribed(topic)
+}
+
+// connected describes the client for callbacks outside the package
+func (c *Client) connected() ConnectedClient {
+	return ConnectedClient{
+		UserID:  c.UserID,
+		IsAdmin: c.User != nil && c.User.IsAdmin,
+	}
+}
+
+// handleSubscribe subscribes a client to the topics it may see and sends the
+// current state of each
+func (m *Manager) handleSubscribe(client *Client, msg structures.Message) error {
+	var payload structures.SubscribePayload
+	if err := msg.DecodeData(&payload); err != nil || len(payload.Topics) == 0 {
+		return m.sendError(client, "Invalid subscription")
+	}
+
+	ctx, cancel := context.WithTimeout(client.ctx, 10*time.Second)
+	defer cancel()
+
+	rejected := make(map[structures.Topic]string)
+	accepted := make([]structures.Topic, 0, len(payload.Topics))
+	for _, topic := range payload.Topics {
+		if reason := m.authorizeTopic(ctx, client, topic); reason != "" {
+			rejected[topic] = reason
+			continue
+		}
+		client.subscribe(topic)
+		accepted = append(accepted, topic)
+	}
+
+	ack := structures.SubscriptionsPayload{Topics: client.subscriptions()}
+	if len(rejected) > 0 {
+		ack.Rejected = rejected
+	}
+	if err := m.sendMessage(client, structures.OpcodeAck, ack); err != nil {
+		return err
+	}
+
+	slog.Debug("Client subscribed", "userID", client.UserID, "topics", accepted, "rejected", len(rejected))
+	m.presenceChanged()
+	go runSubscribeHooks(client.connected(), accepted)
+	return nil
+}
+
+// handleUnsubscribe removes topics from a client's subscriptions
+func (m *Manager) handleUnsubscribe(client *Client, msg structures.Message) error {
+	var payload structures.SubscribePayload
+	if err := msg.DecodeData(&payload); err != nil || len(payload.Topics) == 0 {
+		return m.sendError(client, "Invalid subscription")
+	}
+
+	for _, topic := range payload.Topics {
+		client.unsubscribe(topic)
+	}
+	m.presenceChanged()
+
+	return m.sendMessage(client, structures.OpcodeAck, structures.SubscriptionsPayload{
+		Topics: client.subscriptions(),
+	})
+}
+
+// authorizeTopic checks that a client may subscribe to a topic and returns why
+// not, empty when it may
+func (m *Manager) authorizeTopic(ctx context.Context, client *Client, topic structures.Topic) string {
+	if !topic.IsValid() {
+		return "Unknown topic"
+	}
+
+	switch topic.Base() {
+	case structures.TopicJobs, structures.TopicDriveAlerts:
+		if !m.hasPermission(ctx, client, permissions.AdminSystem) {
+			return "Permission denied"
+		}
+	case structures.TopicRequest:
+		if m.gctx == nil {
+			return "Request not found"
+		}
+		requestID, _ := topic.RequestID()
+		request, err := m.gctx.Crate().Sqlite.Query().GetRequestByID(ctx, requestID)
+		if err != nil {
+			return "Request not found"
+		}
+		if request.UserID != client.UserID && !m.hasPermission(ctx, client, permissions.RequestsView) {
+			return "Permission denied"
+		}
+	}
+
+	return ""
+}
+
+// hasPermission reports whether a client's user is an admin, the owner or has a permission
+func (m *Manager) hasPermission(ctx context.Context, client *Client, permission string) bool {
+	if client.User != nil && client.User.IsAdmin {
+		return true
+	}
+	if m.gctx == nil {
+		return false
+	}
+
+	userPermissions, err := m.gctx.Crate().Sqlite.Query().GetUserPermissions(ctx, client.UserID)
+	if err != nil {
+		slog.Error("Failed to get user permissions", "userID", client.UserID, "error", err)
+		return false
+	}
+	for _, userPerm := range userPermissions {
+		if userPerm.PermissionID == permissions.Owner || userPerm.PermissionID == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// Publish sends an event to the clients subscribed to a topic, on every instance
+func (m *Manager) Publish(topic structures.Topic, op structures.Opcode, data interface{}) {
+	m.PublishExcept(topic, "", op, data)
+}
+
+// PublishExcept sends an event to the clients subscribed to a topic, on every
+// instance, leaving out the connections also subscribed to except because
+// they get the same data on that topic
+func (m *Manager) PublishExcept(topic, except structures.Topic, op structures.Opcode, data interface{}) {
+	m.publishLocal(topic, except, op, data)
+	m.forward(clusterEvent{Kind: clusterEventTopic, Topic: topic, Except: except, Op: op}, data)
+}
+
+// publishLocal sends an event to the subscribed clients connected to this
+// instance, except those subscribed to except when it is set
+func (m *Manager) publishLocal(topic, except structures.Topic, op structures.Opcode, data interface{}) {
+	sequence, payload, err := m.newEvent(op, data)
+	if err != nil {
+		slog.Error("Failed to marshal topic message", "topic", topic, "error", err)
+		return
+	}
+
+	m.clientsMutex.RLock()
+	defer m.clientsMutex.RUnlock()
+
+	for _, buffer := range m.replays.detached() {
+		m.recordDetached(buffer, op, topic, except, sequence, payload)
+	}
+
+	for _, client := range m.clients {
+		if !client.subscribed(topic) || (except != "" && client.subscribed(except)) {
+			continue
+		}
+		if !m.deliver(client, sequence, payload) {
+			slog.Warn("Failed to send topic message (channel full)", "userID", client.UserID, "topic", topic)
+		}
+	}
+}
+
+// PublishToUser sends an event to a user when they are subscribed to a topic,
+// on every instance
+func (m *Manager) PublishToUser(userID string, topic structures.Topic, op structures.Opcode, data interface{}) {
+	m.forward(clusterEvent{Kind: clusterEventUser, UserID: userID, Topic: topic, Op: op}, data)
+
+	if err := m.sendEvent(userID, topic, op, data); err != nil {
+		slog.Warn("Failed to send topic message", "userID", userID, "topic", topic, "error", err)
+	}
+}
+
+// downloadWatchers returns how many clients receive download progress, on every instance
+func (m *Manager) downloadWatchers() int {
+	m.clientsMutex.RLock()
+	count := 0
+	for _, client := range m.clients {
+		if client.watchesDownloads() {
+			count++
+		}
+	}
+	m.clientsMutex.RUnlock()
+
+	for _, remote := range m.remoteClients() {
+		if remote.WatchesDownloads {
+			count++
+		}
+	}
+	return count
+}
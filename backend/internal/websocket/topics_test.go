@@ -0,0 +1,199 @@
+package websocket
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/mahcks/serra/config"
+	"github.com/mahcks/serra/internal/db/dbtest"
+	"github.com/mahcks/serra/internal/global"
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+// newRequestContext returns a global context whose database holds request 1,
+// owned by alice. viewer may see every request.
+func newRequestContext(t *testing.T) global.Context {
+	t.Helper()
+
+	gctx := global.New(context.Background(), &config.Bootstrap{}, "test", "")
+	gctx.Crate().Sqlite = dbtest.Service(t)
+	statements := []string{
+		`INSERT INTO users (id, username) VALUES ('alice', 'alice'), ('bob', 'bob'), ('viewer', 'viewer')`,
+		`INSERT INTO user_permissions (user_id, permission_id) VALUES ('viewer', 'requests.view')`,
+		`INSERT INTO requests (id, user_id, media_type, tmdb_id, title, status) VALUES (1, 'alice', 'movie', 603, 'The Matrix', 'approved')`,
+	}
+	for _, statement := range statements {
+		if _, err := gctx.Crate().Sqlite.DB().Exec(statement); err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+	return gctx
+}
+
+// newRequestCluster starts two instances sharing a bus and the database of
+// newRequestContext
+func newRequestCluster(t *testing.T) (a, b *testServer) {
+	t.Helper()
+
+	gctx := newRequestContext(t)
+	bus := newSharedBus()
+	managerA, managerB := newTestManager(), newTestManager()
+	managerA.useEventBus(bus.join("node-a"))
+	managerB.useEventBus(bus.join("node-b"))
+	return newTestServerWithContext(t, managerA, gctx), newTestServerWithContext(t, managerB, gctx)
+}
+
+// subscribeTo leaves the default topics and subscribes to the given ones
+func (c *testClient) subscribeTo(topics ...structures.Topic) {
+	c.t.Helper()
+	c.send(structures.OpcodeUnsubscribe, structures.SubscribePayload{Topics: structures.DefaultTopics})
+	c.expect(structures.OpcodeAck)
+	c.send(structures.OpcodeSubscribe, structures.SubscribePayload{Topics: topics})
+
+	var ack structures.SubscriptionsPayload
+	c.decode(c.expect(structures.OpcodeAck), &ack)
+	if len(ack.Rejected) > 0 {
+		c.t.Fatalf("subscription rejected: %v", ack.Rejected)
+	}
+}
+
+func TestRequestDownloadProgressSentOncePerConnection(t *testing.T) {
+	a, b := newRequestCluster(t)
+	request := structures.RequestTopic(1)
+
+	requestOnly := a.connect(t, "alice", false)
+	requestOnly.subscribeTo(request)
+	both := a.connect(t, "admin", true)
+	both.subscribeTo(structures.TopicDownloads, request)
+	remoteRequestOnly := b.connect(t, "viewer", true)
+	remoteRequestOnly.subscribeTo(request)
+	remoteBoth := b.connect(t, "other-admin", true)
+	remoteBoth.subscribeTo(structures.TopicDownloads, request)
+
+	a.manager.PublishExcept(request, structures.TopicDownloads, structures.OpcodeDownloadProgressBatch, structures.DownloadProgressBatchPayload{
+		Downloads: []structures.DownloadProgressPayload{{ID: "radarr-1"}},
+		Count:     1,
+	})
+
+	requestOnly.expect(structures.OpcodeDownloadProgressBatch)
+	remoteRequestOnly.expect(structures.OpcodeDownloadProgressBatch)
+	// They get the same progress in the batch of all downloads
+	both.expectNone()
+	remoteBoth.expectNone()
+}
+
+// subscribe subscribes to topics and returns the acknowledgement
+func (c *testClient) subscribe(topics ...structures.Topic) structures.SubscriptionsPayload {
+	c.t.Helper()
+	c.send(structures.OpcodeSubscribe, structures.SubscribePayload{Topics: topics})
+
+	var ack structures.SubscriptionsPayload
+	c.decode(c.expect(structures.OpcodeAck), &ack)
+	return ack
+}
+
+func TestSubscribeAuthorizesTopics(t *testing.T) {
+	server := newTestServerWithContext(t, newTestManager(), newRequestContext(t))
+	request := structures.RequestTopic(1)
+
+	tests := []struct {
+		name         string
+		userID       string
+		isAdmin      bool
+		topic        structures.Topic
+		wantRejected string
+	}{
+		{"unknown topic", "alice", false, "weather", "Unknown topic"},
+		{"bare request topic", "alice", false, structures.TopicRequest, "Unknown topic"},
+		{"jobs for a user", "alice", false, structures.TopicJobs, "Permission denied"},
+		{"drive alerts for a user", "alice", false, structures.TopicDriveAlerts, "Permission denied"},
+		{"jobs for an admin", "admin", true, structures.TopicJobs, ""},
+		{"own request", "alice", false, request, ""},
+		{"someone else's request", "bob", false, request, "Permission denied"},
+		{"any request with requests.view", "viewer", false, request, ""},
+		{"any request as admin", "admin", true, request, ""},
+		{"missing request", "admin", true, structures.RequestTopic(99), "Request not found"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := server.connect(t, test.userID, test.isAdmin)
+			ack := client.subscribe(test.topic)
+
+			if reason := ack.Rejected[test.topic]; reason != test.wantRejected {
+				t.Fatalf("rejected with %q, want %q", reason, test.wantRejected)
+			}
+			if subscribed := slices.Contains(ack.Topics, test.topic); subscribed != (test.wantRejected == "") {
+				t.Fatalf("subscriptions = %v", ack.Topics)
+			}
+			client.conn.Close()
+		})
+	}
+}
+
+func TestSubscriptionsChangeWhatClientsReceive(t *testing.T) {
+	server := newTestServer(t, newTestManager())
+	client := server.connect(t, "admin", true)
+
+	// Connections start with the default topics
+	client.send(structures.OpcodeSubscribe, structures.SubscribePayload{})
+	client.expect(structures.OpcodeError)
+	ack := client.subscribe(structures.TopicJobs, "weather")
+	want := append(slices.Clone(structures.DefaultTopics), structures.TopicJobs)
+	slices.Sort(want)
+	if !slices.Equal(ack.Topics, want) || len(ack.Rejected) != 1 {
+		t.Fatalf("ack = %+v, want subscriptions %v and weather rejected", ack, want)
+	}
+
+	server.manager.Publish(structures.TopicJobs, structures.OpcodeJobStatus, structures.JobStatusPayload{Name: "sync"})
+	client.expect(structures.OpcodeJobStatus)
+	server.manager.PublishToUser("admin", structures.TopicJobs, structures.OpcodeJobStatus, structures.JobStatusPayload{Name: "sync"})
+	client.expect(structures.OpcodeJobStatus)
+
+	// Events scoped to a topic only reach its subscribers
+	client.send(structures.OpcodeUnsubscribe, structures.SubscribePayload{Topics: []structures.Topic{structures.TopicJobs, structures.TopicNotifications}})
+	client.expect(structures.OpcodeAck)
+	server.manager.Publish(structures.TopicJobs, structures.OpcodeJobStatus, structures.JobStatusPayload{Name: "sync"})
+	server.manager.PublishToUser("admin", structures.TopicJobs, structures.OpcodeJobStatus, structures.JobStatusPayload{Name: "sync"})
+	if err := server.manager.SendToUser("admin", structures.OpcodeNotification, map[string]string{"id": "1"}); err != nil {
+		t.Fatalf("send notification: %v", err)
+	}
+	client.expectNone()
+
+	// Events without a topic reach everyone
+	server.manager.BroadcastToAll(structures.OpcodeSystemStatus, structures.SystemStatusPayload{})
+	client.expect(structures.OpcodeSystemStatus)
+}
+
+func TestSubscribeRunsHooks(t *testing.T) {
+	server := newTestServerWithContext(t, newTestManager(), newRequestContext(t))
+
+	called := make(chan structures.Topic, 10)
+	remove := OnSubscribe(structures.TopicRequest, func(client ConnectedClient, topic structures.Topic) {
+		if client.UserID == "alice" {
+			called <- topic
+		}
+	})
+	defer remove()
+
+	client := server.connect(t, "alice", false)
+	client.subscribe(structures.RequestTopic(1), structures.RequestTopic(99))
+	select {
+	case topic := <-called:
+		if topic != structures.RequestTopic(1) {
+			t.Fatalf("hook called for %s", topic)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("hook not called")
+	}
+
+	// Not for rejected topics, nor once removed
+	remove()
+	client.subscribe(structures.RequestTopic(1))
+	select {
+	case topic := <-called:
+		t.Fatalf("hook called for %s", topic)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
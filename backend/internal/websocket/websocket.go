@@ -3,11 +3,14 @@ package websocket
 import (
 	"context"
 	"log/slog"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gofiber/contrib/websocket"
 	"github.com/gofiber/fiber/v2"
+	"github.com/mahcks/serra/internal/eventbus"
 	"github.com/mahcks/serra/internal/global"
 	"github.com/mahcks/serra/internal/services/auth"
 	"github.com/mahcks/serra/pkg/structures"
@@ -36,6 +39,25 @@ type Manager struct {
 	// Server info for hello messages
 	serverID string
 	features []string
+
+	// Global context for permission checks, set when the routes are registered
+	gctx global.Context
+
+	// Last sequence number dispatched and the events kept for resuming clients
+	sequence atomic.Uint64
+	replays  *replayStore
+
+	// Event bus shared with the other instances and the clients connected to them
+	bus        eventbus.Bus
+	nodes      map[string]nodePresence // node id -> last announcement
+	nodesMutex sync.Mutex
+	presence   chan struct{}
+}
+
+// ConnectedClient describes a connected user for callers that filter what they send
+type ConnectedClient struct {
+	UserID  string
+	IsAdmin bool
 }
 
 // Client represents a connected WebSocket client
@@ -58,8 +80,18 @@ type Client struct {
 	mu        sync.Mutex
 	closeOnce sync.Once
 
+	// Writer and heartbeat goroutines, which use the connection until they stop
+	workers sync.WaitGroup
+
 	// Heartbeat tracking
 	awaitingPong bool
+
+	// Topics the client is subscribed to
+	topics      map[structures.Topic]bool
+	topicsMutex sync.RWMutex
+
+	// Events dispatched to the user, kept across connections
+	replay *replayBuffer
 }
 
 // NewManager creates a new WebSocket manager
@@ -78,13 +110,17 @@ func NewManager(authService auth.Authmen) *Manager {
 		cancel:            cancel,
 		authService:       authService,
 		serverID:          "serra-ws-server",
-		features:          []string{"heartbeat", "batch_downloads", "system_status"},
+		features:          []string{"heartbeat", "batch_downloads", "system_status", "subscriptions", "operations", "resume"},
+		replays:           newReplayStore(500, 5*time.Minute),
+		nodes:             make(map[string]nodePresence),
+		presence:          make(chan struct{}, 1),
 	}
 }
 
 // RegisterRoutes sets up the websocket endpoint
 func (m *Manager) RegisterRoutes(gctx global.Context, router fiber.Router) {
 	slog.Info("Registering WebSocket routes", "path", "/ws")
+	m.gctx = gctx
 
 	router.Use("/ws", func(c *fiber.Ctx) error {
 		if websocket.IsWebSocketUpgrade(c) {
@@ -134,6 +170,7 @@ func (m *Manager) handleConnection(c *websocket.Conn, gctx global.Context) {
 			"version":            "1.0",
 			"heartbeat_interval": m.heartbeatInterval.String(),
 			"timeout":            m.connectionTimeout.String(),
+			"sequence":           strconv.FormatUint(m.sequence.Load(), 10),
 		},
 	}
 
@@ -141,9 +178,13 @@ func (m *Manager) handleConnection(c *websocket.Conn, gctx global.Context) {
 	if err != nil {
 		slog.Error("Failed to send hello message", "userID", client.UserID, "error", err)
 		m.unregisterClient(client)
+		client.workers.Wait()
 		return
 	}
 
+	// Send the current state of the default topics
+	go runSubscribeHooks(client.connected(), structures.DefaultTopics)
+
 	// Start client message handling
 	m.handleClientMessages(client)
 }
@@ -181,6 +222,10 @@ func (m *Manager) authenticateConnection(c *websocket.Conn) (*Client, error) {
 		ctx:          ctx,
 		cancel:       cancel,
 		awaitingPong: false,
+		topics:       make(map[structures.Topic]bool),
+	}
+	for _, topic := range structures.DefaultTopics {
+		client.topics[topic] = true
 	}
 
 	return client, nil
@@ -194,13 +239,18 @@ func (m *Manager) registerClient(client *Client) bool {
 	// Check if user already has a connection
 	if existing, exists := m.clients[client.UserID]; exists {
 		slog.Info("Replacing existing connection", "userID", client.UserID)
+		m.replays.detach(existing.UserID, existing.subscriptions())
+		delete(m.connections, existing.Conn)
 		m.closeClient(existing)
 	}
 
+	client.replay = m.replays.attach(client.UserID, m.sequence.Load())
 	m.clients[client.UserID] = client
 	m.connections[client.Conn] = client
+	m.presenceChanged()
 
 	// Start background goroutines for this client
+	client.workers.Add(2)
 	go m.clientWriter(client)
 	go m.clientHeartbeat(client)
 
@@ -212,19 +262,24 @@ func (m *Manager) unregisterClient(client *Client) {
 	m.clientsMutex.Lock()
 	defer m.clientsMutex.Unlock()
 
-	delete(m.clients, client.UserID)
+	// The client may already have been replaced by a newer connection of the user
+	if current, exists := m.clients[client.UserID]; exists && current == client {
+		delete(m.clients, client.UserID)
+		m.replays.detach(client.UserID, client.subscriptions())
+		m.presenceChanged()
+	}
 	delete(m.connections, client.Conn)
 
-	client.cancel()
-	client.Conn.Close()
-	client.closeOnce.Do(func() {
-		close(client.sendChan)
-	})
+	m.closeClient(client)
 }
 
 // handleClientMessages processes incoming messages from a client
 func (m *Manager) handleClientMessages(client *Client) {
-	defer m.unregisterClient(client)
+	defer func() {
+		m.unregisterClient(client)
+		// The connection is reused once the handler returns
+		client.workers.Wait()
+	}()
 
 	// Set read deadline
 	client.Conn.SetReadDeadline(time.Now().Add(m.readTimeout))
@@ -270,6 +325,18 @@ func (m *Manager) handleMessage(client *Client, message []byte) error {
 		// Don't send response - this would create infinite loop
 		return nil
 
+	case structures.OpcodeSubscribe:
+		return m.handleSubscribe(client, msg)
+
+	case structures.OpcodeUnsubscribe:
+		return m.handleUnsubscribe(client, msg)
+
+	case structures.OpcodeRequest:
+		return m.handleRequest(client, msg)
+
+	case structures.OpcodeResume:
+		return m.handleResume(client, msg)
+
 	default:
 		slog.Debug("Unknown opcode", "userID", client.UserID, "opcode", msg.Op)
 		return m.sendError(client, "Unknown operation")
@@ -278,6 +345,8 @@ func (m *Manager) handleMessage(client *Client, message []byte) error {
 
 // clientWriter handles writing messages to a client
 func (m *Manager) clientWriter(client *Client) {
+	defer client.workers.Done()
+
 	for {
 		select {
 		case <-client.ctx.Done():
@@ -297,6 +366,8 @@ func (m *Manager) clientWriter(client *Client) {
 
 // clientHeartbeat sends periodic heartbeats and checks for stale connections
 func (m *Manager) clientHeartbeat(client *Client) {
+	defer client.workers.Done()
+
 	ticker := time.NewTicker(m.heartbeatInterval)
 	defer ticker.Stop()
 
@@ -380,26 +451,38 @@ func (m *Manager) sendErrorAndClose(c *websocket.Conn, message string) error {
 	return c.Close()
 }
 
-// BroadcastToAll sends a message to all connected clients
+// BroadcastToAll sends a message to all connected clients, on every instance
 func (m *Manager) BroadcastToAll(op structures.Opcode, data interface{}) {
-	msg := structures.NewMessage(op, data)
-	payload, err := structures.MarshalMessage(msg)
+	m.broadcastLocal(op, data)
+	m.forward(clusterEvent{Kind: clusterEventAll, Op: op}, data)
+}
+
+// broadcastLocal sends a message to the clients connected to this instance
+func (m *Manager) broadcastLocal(op structures.Opcode, data interface{}) {
+	sequence, payload, err := m.newEvent(op, data)
 	if err != nil {
 		slog.Error("Failed to marshal broadcast message", "error", err)
 		return
 	}
 
 	m.clientsMutex.RLock()
+	defer m.clientsMutex.RUnlock()
+
+	// Users who are away get the event when they resume
+	for _, buffer := range m.replays.detached() {
+		m.recordDetached(buffer, op, "", "", sequence, payload)
+	}
+
 	connectedCount := len(m.clients)
 	clientList := make([]string, 0, len(m.clients))
 	for userID := range m.clients {
 		clientList = append(clientList, userID)
 	}
-	m.clientsMutex.RUnlock()
 
 	slog.Info("📡 Broadcasting WebSocket message",
 		"opcode", op,
 		"opcodeType", op.String(),
+		"sequence", sequence,
 		"connectedClients", connectedCount,
 		"clientList", clientList,
 		"messageSize", len(payload))
@@ -409,17 +492,16 @@ func (m *Manager) BroadcastToAll(op structures.Opcode, data interface{}) {
 		return
 	}
 
-	m.clientsMutex.RLock()
-	defer m.clientsMutex.RUnlock()
-
 	var failedClients []*Client
 	sentCount := 0
 	for _, client := range m.clients {
-		select {
-		case client.sendChan <- payload:
+		if !client.receives(op) {
+			continue
+		}
+		if m.deliver(client, sequence, payload) {
 			// Message sent successfully
 			sentCount++
-		default:
+		} else {
 			failedClients = append(failedClients, client)
 		}
 	}
@@ -434,26 +516,57 @@ func (m *Manager) BroadcastToAll(op structures.Opcode, data interface{}) {
 // SendToUser sends a message to a specific user
 func (m *Manager) SendToUser(userID string, op structures.Opcode, data interface{}) error {
 	m.clientsMutex.RLock()
-	client, exists := m.clients[userID]
+	_, exists := m.clients[userID]
 	m.clientsMutex.RUnlock()
 
+	// The user may be connected to another instance
+	m.forward(clusterEvent{Kind: clusterEventUser, UserID: userID, Op: op}, data)
+
 	if !exists {
+		// Kept for when the user resumes
+		_ = m.sendEvent(userID, "", op, data)
 		return &ClientError{Message: "User not connected"}
 	}
 
-	return m.sendMessage(client, op, data)
+	return m.sendEvent(userID, "", op, data)
 }
 
-// GetConnectedUsers returns a list of connected user IDs
+// GetConnectedUsers returns a list of connected user IDs, on every instance
 func (m *Manager) GetConnectedUsers() []string {
+	clients := m.GetConnectedClients()
+	users := make([]string, 0, len(clients))
+	for _, client := range clients {
+		users = append(users, client.UserID)
+	}
+	return users
+}
+
+// GetConnectedClients returns the connected users along with whether they are
+// admins, on every instance
+func (m *Manager) GetConnectedClients() []ConnectedClient {
 	m.clientsMutex.RLock()
-	defer m.clientsMutex.RUnlock()
+	clients := make([]ConnectedClient, 0, len(m.clients))
+	seen := make(map[string]bool, len(m.clients))
+	for userID, client := range m.clients {
+		clients = append(clients, ConnectedClient{
+			UserID:  userID,
+			IsAdmin: client.User != nil && client.User.IsAdmin,
+		})
+		seen[userID] = true
+	}
+	m.clientsMutex.RUnlock()
 
-	users := make([]string, 0, len(m.clients))
-	for userID := range m.clients {
-		users = append(users, userID)
+	for _, remote := range m.remoteClients() {
+		if seen[remote.UserID] {
+			continue
+		}
+		clients = append(clients, ConnectedClient{
+			UserID:  remote.UserID,
+			IsAdmin: remote.IsAdmin,
+		})
+		seen[remote.UserID] = true
 	}
-	return users
+	return clients
 }
 
 // getConnectionCount returns the current number of connections
@@ -463,12 +576,15 @@ func (m *Manager) getConnectionCount() int {
 	return len(m.clients)
 }
 
-// closeClient closes a client connection
+// closeClient closes a client connection. The send channel is left open as
+// senders may still hold the client; the writer stops on the context.
 func (m *Manager) closeClient(client *Client) {
-	client.cancel()
-	client.Conn.Close()
 	client.closeOnce.Do(func() {
-		close(client.sendChan)
+		client.cancel()
+		// Fiber only closes the connection once the handler returns, so
+		// interrupt the pending read to make it return
+		client.Conn.SetReadDeadline(time.Now())
+		client.Conn.Close()
 	})
 }
 
@@ -514,6 +630,9 @@ var defaultManager *Manager
 func RegisterRoutes(gctx global.Context, router fiber.Router) {
 	if defaultManager == nil {
 		defaultManager = NewManager(gctx.Crate().AuthService)
+		if bus := gctx.Crate().EventBus; bus != nil {
+			defaultManager.useEventBus(bus)
+		}
 	}
 	defaultManager.RegisterRoutes(gctx, router)
 }
@@ -546,6 +665,14 @@ func GetConnectionCount() int {
 	return 0
 }
 
+// GetConnectedClients returns the connected users along with whether they are admins
+func GetConnectedClients() []ConnectedClient {
+	if defaultManager != nil {
+		return defaultManager.GetConnectedClients()
+	}
+	return []ConnectedClient{}
+}
+
 // GetConnectedUsers returns a list of connected user IDs
 func GetConnectedUsers() []string {
 	if defaultManager != nil {
@@ -554,6 +681,28 @@ func GetConnectedUsers() []string {
 	return []string{}
 }
 
+// DownloadWatchers returns how many connected clients receive download progress
+func DownloadWatchers() int {
+	if defaultManager != nil {
+		return defaultManager.downloadWatchers()
+	}
+	return 0
+}
+
+// Publish sends an event to the clients subscribed to a topic
+func Publish(topic structures.Topic, op structures.Opcode, data interface{}) {
+	if defaultManager != nil {
+		defaultManager.Publish(topic, op, data)
+	}
+}
+
+// PublishToUser sends an event to a user when they are subscribed to a topic
+func PublishToUser(userID string, topic structures.Topic, op structures.Opcode, data interface{}) {
+	if defaultManager != nil {
+		defaultManager.PublishToUser(userID, topic, op, data)
+	}
+}
+
 // Helper functions for common message types
 
 // BroadcastDownloadProgress broadcasts download progress to all clients
@@ -576,6 +725,35 @@ func BroadcastDownloadProgressBatch(downloads []structures.DownloadProgressPaylo
 	BroadcastToAll(structures.OpcodeDownloadProgressBatch, payload)
 }
 
+// SendDownloadProgressBatch sends batch download progress to a single user
+func SendDownloadProgressBatch(userID string, downloads []structures.DownloadProgressPayload) {
+	if defaultManager == nil {
+		return
+	}
+
+	payload := structures.DownloadProgressBatchPayload{
+		Downloads: downloads,
+		Count:     len(downloads),
+		Timestamp: time.Now().UnixMilli(),
+	}
+
+	defaultManager.BroadcastToUser(userID, structures.OpcodeDownloadProgressBatch, payload)
+}
+
+// PublishDownloadProgressBatch sends batch download progress to the clients
+// subscribed to a topic, e.g. the subscribers of one request. Clients also
+// subscribed to all downloads are left out, they get the same progress there.
+func PublishDownloadProgressBatch(topic structures.Topic, downloads []structures.DownloadProgressPayload) {
+	if defaultManager == nil {
+		return
+	}
+	defaultManager.PublishExcept(topic, structures.TopicDownloads, structures.OpcodeDownloadProgressBatch, structures.DownloadProgressBatchPayload{
+		Downloads: downloads,
+		Count:     len(downloads),
+		Timestamp: time.Now().UnixMilli(),
+	})
+}
+
 // BroadcastSystemStatus broadcasts system status to all clients
 func BroadcastSystemStatus(status structures.SystemStatusPayload) {
 	BroadcastToAll(structures.OpcodeSystemStatus, status)
@@ -586,6 +764,21 @@ func BroadcastUserActivity(activity structures.UserActivityPayload) {
 	BroadcastToAll(structures.OpcodeUserActivity, activity)
 }
 
+// BroadcastBandwidthState broadcasts the bandwidth limits in effect to all clients
+func BroadcastBandwidthState(state structures.BandwidthState) {
+	BroadcastToAll(structures.OpcodeBandwidthState, state)
+}
+
+// PublishJobStatus sends a finished job run to the clients watching jobs
+func PublishJobStatus(status structures.JobStatusPayload) {
+	Publish(structures.TopicJobs, structures.OpcodeJobStatus, status)
+}
+
+// PublishDriveAlert sends a new drive alert to the clients watching drive alerts
+func PublishDriveAlert(alert structures.DriveAlertPayload) {
+	Publish(structures.TopicDriveAlerts, structures.OpcodeDriveAlert, alert)
+}
+
 // BroadcastToUser broadcasts a message to a specific user
 func BroadcastToUser(userID string, op structures.Opcode, data interface{}) {
 	if defaultManager == nil {
@@ -594,28 +787,52 @@ func BroadcastToUser(userID string, op structures.Opcode, data interface{}) {
 	defaultManager.BroadcastToUser(userID, op, data)
 }
 
-// BroadcastToUser broadcasts a message to a specific user
+// BroadcastToUser broadcasts a message to a specific user, on every instance
 func (m *Manager) BroadcastToUser(userID string, op structures.Opcode, data interface{}) {
+	m.forward(clusterEvent{Kind: clusterEventUser, UserID: userID, Op: op}, data)
+
+	if err := m.sendEvent(userID, "", op, data); err != nil {
+		slog.Warn("Failed to send broadcast to user", "user_id", userID, "opcode", op, "error", err)
+		return
+	}
+	slog.Debug("Broadcast sent to user", "user_id", userID, "opcode", op)
+}
+
+// sendEvent sends an event to a user when they receive it, by the topic or
+// else the opcode. Events for a user who is away are kept for when they
+// resume.
+func (m *Manager) sendEvent(userID string, topic structures.Topic, op structures.Opcode, data interface{}) error {
 	m.clientsMutex.RLock()
+	defer m.clientsMutex.RUnlock()
+
 	client, exists := m.clients[userID]
-	m.clientsMutex.RUnlock()
+	if !exists {
+		buffer, ok := m.replays.get(userID)
+		if !ok || !op.IsSequenced() {
+			return nil
+		}
+		sequence, payload, err := m.newEvent(op, data)
+		if err != nil {
+			return err
+		}
+		m.recordDetached(buffer, op, topic, "", sequence, payload)
+		return nil
+	}
 
-	if !exists || client == nil {
-		slog.Debug("User not connected for broadcast", "user_id", userID, "opcode", op)
-		return
+	receives := client.receives(op)
+	if topic != "" {
+		receives = client.subscribed(topic)
+	}
+	if !receives {
+		return nil
 	}
 
-	msg := structures.NewMessage(op, data)
-	payload, err := structures.MarshalMessage(msg)
+	sequence, payload, err := m.newEvent(op, data)
 	if err != nil {
-		slog.Error("Failed to marshal user broadcast message", "user_id", userID, "error", err)
-		return
+		return err
 	}
-
-	select {
-	case client.sendChan <- payload:
-		slog.Debug("Broadcast sent to user", "user_id", userID, "opcode", op)
-	default:
-		slog.Warn("Failed to send broadcast to user (channel full)", "user_id", userID, "opcode", op)
+	if !m.deliver(client, sequence, payload) {
+		return &ClientError{Message: "Client buffer full"}
 	}
+	return nil
 }
@@ -0,0 +1,282 @@
+package websocket
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	fastws "github.com/fasthttp/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/mahcks/serra/internal/global"
+	"github.com/mahcks/serra/internal/services/auth"
+	"github.com/mahcks/serra/pkg/structures"
+)
+
+// testServer serves a manager's websocket endpoint on a local port
+type testServer struct {
+	manager *Manager
+	auth    auth.Authmen
+	url     string
+}
+
+func newTestServer(t *testing.T, manager *Manager) *testServer {
+	t.Helper()
+	return newTestServerWithContext(t, manager, nil)
+}
+
+// newTestServerWithContext serves a manager with a global context, for the
+// features that need the database
+func newTestServerWithContext(t *testing.T, manager *Manager, gctx global.Context) *testServer {
+	t.Helper()
+
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	manager.RegisterRoutes(gctx, app)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	go app.Listener(listener)
+	t.Cleanup(func() {
+		manager.Shutdown()
+		_ = app.Shutdown()
+	})
+
+	return &testServer{
+		manager: manager,
+		auth:    manager.authService,
+		url:     "ws://" + listener.Addr().String() + "/ws",
+	}
+}
+
+func newTestManager() *Manager {
+	return NewManager(auth.New("test-secret", "localhost", false))
+}
+
+// testClient is a websocket client connected to a test server
+type testClient struct {
+	t        *testing.T
+	conn     *fastws.Conn
+	hello    structures.HelloPayload
+	messages chan structures.Message // Received, without heartbeats
+}
+
+// connect opens a connection as a user and reads the hello
+func (s *testServer) connect(t *testing.T, userID string, isAdmin bool) *testClient {
+	t.Helper()
+
+	client, err := s.dial(t, userID, isAdmin)
+	if err != nil {
+		t.Fatalf("connect %s: %v", userID, err)
+	}
+	hello := client.expect(structures.OpcodeHello)
+	client.decode(hello, &client.hello)
+	return client
+}
+
+// dial opens a connection as a user without waiting for the hello. It's safe
+// to call from other goroutines.
+func (s *testServer) dial(t *testing.T, userID string, isAdmin bool) (*testClient, error) {
+	token, _, err := s.auth.CreateAccessToken(userID, userID, "", isAdmin)
+	if err != nil {
+		return nil, err
+	}
+	header := http.Header{"Cookie": {auth.CookieAuth + "=" + token}}
+	conn, _, err := fastws.DefaultDialer.Dial(s.url, header)
+	if err != nil {
+		return nil, err
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	client := &testClient{t: t, conn: conn, messages: make(chan structures.Message, 100)}
+	go client.read()
+	return client, nil
+}
+
+// closed waits for the server to close the connection
+func (c *testClient) closed() {
+	c.t.Helper()
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case _, ok := <-c.messages:
+			if !ok {
+				return
+			}
+		case <-timeout:
+			c.t.Fatal("connection wasn't closed")
+		}
+	}
+}
+
+// read queues the received messages until the connection closes. A timed out
+// read breaks the connection, so reads have no deadline.
+func (c *testClient) read() {
+	defer close(c.messages)
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		msg, err := structures.ParseMessage(data)
+		if err != nil || msg.Op == structures.OpcodeHeartbeat {
+			continue
+		}
+		c.messages <- msg
+	}
+}
+
+// expect waits for the next message and checks its opcode
+func (c *testClient) expect(op structures.Opcode) structures.Message {
+	c.t.Helper()
+	select {
+	case msg, ok := <-c.messages:
+		if !ok {
+			c.t.Fatalf("expected %s, connection closed", op)
+		}
+		if msg.Op != op {
+			c.t.Fatalf("expected %s, got %s: %v", op, msg.Op, msg.Data)
+		}
+		return msg
+	case <-time.After(2 * time.Second):
+		c.t.Fatalf("expected %s, got nothing", op)
+		return structures.Message{}
+	}
+}
+
+// expectNone checks that no message arrives for a moment
+func (c *testClient) expectNone() {
+	c.t.Helper()
+	select {
+	case msg, ok := <-c.messages:
+		if ok {
+			c.t.Fatalf("unexpected %s: %v", msg.Op, msg.Data)
+		}
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func (c *testClient) send(op structures.Opcode, data interface{}) {
+	c.t.Helper()
+	payload, err := structures.MarshalMessage(structures.NewMessage(op, data))
+	if err != nil {
+		c.t.Fatalf("marshal message: %v", err)
+	}
+	if err := c.conn.WriteMessage(fastws.TextMessage, payload); err != nil {
+		c.t.Fatalf("write message: %v", err)
+	}
+}
+
+func (c *testClient) decode(msg structures.Message, out interface{}) {
+	c.t.Helper()
+	if err := msg.DecodeData(out); err != nil {
+		c.t.Fatalf("decode %s: %v", msg.Op, err)
+	}
+}
+
+// eventually polls a condition until it holds
+func eventually(t *testing.T, what string, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestConcurrentConnections(t *testing.T) {
+	server := newTestServer(t, newTestManager())
+	manager := server.manager
+
+	// Broadcast throughout so registration races with delivery
+	stop := make(chan struct{})
+	broadcasting := make(chan struct{})
+	go func() {
+		defer close(broadcasting)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				manager.BroadcastToAll(structures.OpcodeSystemStatus, structures.SystemStatusPayload{})
+				time.Sleep(time.Millisecond)
+			}
+		}
+	}()
+
+	// Several connections of one user replace each other, other users connect alongside
+	var wg sync.WaitGroup
+	clients := make(chan *testClient, 40)
+	for i := 0; i < 40; i++ {
+		userID := "shared"
+		if i%2 == 1 {
+			userID = fmt.Sprintf("user-%d", i)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			client, err := server.dial(t, userID, false)
+			if err != nil {
+				t.Errorf("dial %s: %v", userID, err)
+				return
+			}
+			clients <- client
+		}()
+	}
+	wg.Wait()
+	close(clients)
+
+	eventually(t, "one connection per user", func() bool {
+		manager.clientsMutex.RLock()
+		defer manager.clientsMutex.RUnlock()
+		return len(manager.clients) == 21 && len(manager.connections) == 21
+	})
+
+	manager.clientsMutex.RLock()
+	for userID, client := range manager.clients {
+		if manager.connections[client.Conn] != client {
+			t.Errorf("connection of %s isn't tracked", userID)
+		}
+	}
+	manager.clientsMutex.RUnlock()
+
+	// Disconnecting everyone leaves nothing behind
+	for client := range clients {
+		client.conn.Close()
+	}
+	eventually(t, "all connections to be removed", func() bool {
+		manager.clientsMutex.RLock()
+		defer manager.clientsMutex.RUnlock()
+		return len(manager.clients) == 0 && len(manager.connections) == 0
+	})
+
+	close(stop)
+	<-broadcasting
+}
+
+func TestReplacedConnectionDoesNotUnregisterItsReplacement(t *testing.T) {
+	server := newTestServer(t, newTestManager())
+	manager := server.manager
+
+	first := server.connect(t, "user", false)
+	second := server.connect(t, "user", false)
+
+	// The first connection's handler unregisters it once closed
+	first.closed()
+
+	manager.clientsMutex.RLock()
+	current := manager.clients["user"]
+	connections := len(manager.connections)
+	manager.clientsMutex.RUnlock()
+	if current == nil || connections != 1 {
+		t.Fatalf("the second connection should stay registered, %d connections", connections)
+	}
+
+	manager.BroadcastToAll(structures.OpcodeSystemStatus, structures.SystemStatusPayload{})
+	second.expect(structures.OpcodeSystemStatus)
+}
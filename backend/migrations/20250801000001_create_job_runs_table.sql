@@ -0,0 +1,18 @@
+-- Create job_runs table to persist background job execution history
+CREATE TABLE job_runs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    job_name TEXT NOT NULL,
+    trigger_type TEXT NOT NULL DEFAULT 'scheduled' CHECK (trigger_type IN ('scheduled', 'startup', 'manual')),
+    started_at DATETIME NOT NULL,
+    finished_at DATETIME,
+    duration_ms INTEGER NOT NULL DEFAULT 0,
+    outcome TEXT NOT NULL CHECK (outcome IN ('success', 'failed', 'cancelled')),
+    attempts INTEGER NOT NULL DEFAULT 1,
+    error TEXT,
+    summary TEXT, -- JSON job-specific summary (items synced, requests processed, ...)
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes for efficient querying
+CREATE INDEX idx_job_runs_job_name_started_at ON job_runs(job_name, started_at DESC);
+CREATE INDEX idx_job_runs_started_at ON job_runs(started_at);
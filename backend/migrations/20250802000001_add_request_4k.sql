@@ -0,0 +1,38 @@
+-- Add 4K request mode. A 4K request and a regular request for the same title
+-- are tracked separately, so is_4k becomes part of the unique constraint and
+-- the table has to be rebuilt.
+CREATE TABLE requests_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id TEXT NOT NULL,
+    media_type TEXT NOT NULL CHECK (media_type IN ('movie', 'tv')),
+    tmdb_id INTEGER NOT NULL,
+    title TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'denied', 'fulfilled', 'processing', 'failed')),
+    notes TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    fulfilled_at DATETIME,
+    approver_id TEXT,
+    on_behalf_of TEXT,
+    poster_url TEXT,
+    seasons TEXT DEFAULT NULL,
+    season_statuses TEXT DEFAULT NULL,
+    is_4k BOOLEAN NOT NULL DEFAULT FALSE,
+    UNIQUE (media_type, tmdb_id, user_id, seasons, is_4k)
+);
+
+INSERT INTO requests_new (
+    id, user_id, media_type, tmdb_id, title, status, notes,
+    created_at, updated_at, fulfilled_at,
+    approver_id, on_behalf_of, poster_url, seasons, season_statuses
+)
+SELECT
+    id, user_id, media_type, tmdb_id, title, status, notes,
+    created_at, updated_at, fulfilled_at,
+    approver_id, on_behalf_of, poster_url, seasons, season_statuses
+FROM requests;
+
+DROP TABLE requests;
+ALTER TABLE requests_new RENAME TO requests;
+
+CREATE INDEX idx_requests_tmdb_id_media_type ON requests(tmdb_id, media_type, is_4k);
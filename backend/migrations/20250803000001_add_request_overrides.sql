@@ -0,0 +1,12 @@
+-- Per-request Radarr/Sonarr options chosen by the requester or approver.
+-- NULL means the instance default is used.
+ALTER TABLE requests ADD COLUMN quality_profile_id INTEGER DEFAULT NULL;
+ALTER TABLE requests ADD COLUMN root_folder_path TEXT DEFAULT NULL;
+ALTER TABLE requests ADD COLUMN tags TEXT DEFAULT NULL; -- JSON array of Radarr/Sonarr tag IDs
+ALTER TABLE requests ADD COLUMN series_type TEXT DEFAULT NULL CHECK (series_type IN ('standard', 'anime', 'daily'));
+
+-- Permission to choose advanced request options
+INSERT OR IGNORE INTO permissions (id, name, description) VALUES
+('request.advanced', 'Advanced Requests', 'Choose quality profile, root folder, tags and series type when requesting');
+
+INSERT OR IGNORE INTO default_permissions (permission_id, enabled) VALUES ('request.advanced', FALSE);
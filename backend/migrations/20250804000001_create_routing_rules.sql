@@ -0,0 +1,35 @@
+-- Rules that pick the Radarr/Sonarr instance and root folder for a request.
+-- Conditions are JSON arrays; NULL means the condition is not checked. Rules
+-- are evaluated by ascending priority and the first match wins. A default rule
+-- is used when no other rule matches.
+CREATE TABLE routing_rules (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    media_type TEXT NOT NULL CHECK (media_type IN ('movie', 'tv')),
+    priority INTEGER NOT NULL DEFAULT 0,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    genres TEXT,             -- JSON array of TMDB genre IDs
+    original_languages TEXT, -- JSON array of ISO 639-1 codes
+    keywords TEXT,           -- JSON array of TMDB keyword IDs
+    certifications TEXT,     -- JSON array of US certifications
+    user_ids TEXT,           -- JSON array of requesting user IDs
+    user_types TEXT,         -- JSON array of user types (media_server, local)
+    arr_service_id TEXT NOT NULL REFERENCES arr_services(id) ON DELETE CASCADE,
+    root_folder_path TEXT,
+    quality_profile_id INTEGER,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_routing_rules_media_type_priority ON routing_rules(media_type, priority);
+
+-- The instance each request was sent to, so status checks and retries use the
+-- same instance even if the rules change afterwards
+CREATE TABLE request_routing (
+    request_id INTEGER PRIMARY KEY REFERENCES requests(id) ON DELETE CASCADE,
+    arr_service_id TEXT REFERENCES arr_services(id) ON DELETE SET NULL,
+    routing_rule_id INTEGER REFERENCES routing_rules(id) ON DELETE SET NULL,
+    root_folder_path TEXT,
+    routed_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
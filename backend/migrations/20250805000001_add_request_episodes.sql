@@ -0,0 +1,48 @@
+-- Episode-level TV requests and followed shows. Requests for different
+-- episodes of the same season are tracked separately, so episodes becomes part
+-- of the unique constraint and the table has to be rebuilt.
+CREATE TABLE requests_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id TEXT NOT NULL,
+    media_type TEXT NOT NULL CHECK (media_type IN ('movie', 'tv')),
+    tmdb_id INTEGER NOT NULL,
+    title TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'denied', 'fulfilled', 'processing', 'failed')),
+    notes TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    fulfilled_at DATETIME,
+    approver_id TEXT,
+    on_behalf_of TEXT,
+    poster_url TEXT,
+    seasons TEXT DEFAULT NULL,
+    season_statuses TEXT DEFAULT NULL,
+    is_4k BOOLEAN NOT NULL DEFAULT FALSE,
+    quality_profile_id INTEGER DEFAULT NULL,
+    root_folder_path TEXT DEFAULT NULL,
+    tags TEXT DEFAULT NULL,
+    series_type TEXT DEFAULT NULL CHECK (series_type IN ('standard', 'anime', 'daily')),
+    episodes TEXT DEFAULT NULL, -- JSON object of season number to episode numbers
+    follow_show BOOLEAN NOT NULL DEFAULT FALSE,
+    parent_request_id INTEGER DEFAULT NULL REFERENCES requests(id) ON DELETE SET NULL,
+    UNIQUE (media_type, tmdb_id, user_id, seasons, episodes, is_4k)
+);
+
+INSERT INTO requests_new (
+    id, user_id, media_type, tmdb_id, title, status, notes,
+    created_at, updated_at, fulfilled_at,
+    approver_id, on_behalf_of, poster_url, seasons, season_statuses, is_4k,
+    quality_profile_id, root_folder_path, tags, series_type
+)
+SELECT
+    id, user_id, media_type, tmdb_id, title, status, notes,
+    created_at, updated_at, fulfilled_at,
+    approver_id, on_behalf_of, poster_url, seasons, season_statuses, is_4k,
+    quality_profile_id, root_folder_path, tags, series_type
+FROM requests;
+
+DROP TABLE requests;
+ALTER TABLE requests_new RENAME TO requests;
+
+CREATE INDEX idx_requests_tmdb_id_media_type ON requests(tmdb_id, media_type, is_4k);
+CREATE INDEX idx_requests_parent_request_id ON requests(parent_request_id);
@@ -0,0 +1,22 @@
+-- A request for every part of a TMDB movie collection. Each part is a normal
+-- movie request linked to the group through collection_request_items.
+CREATE TABLE collection_requests (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    collection_id INTEGER NOT NULL,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    poster_url TEXT,
+    is_4k BOOLEAN NOT NULL DEFAULT FALSE,
+    watch_unreleased BOOLEAN NOT NULL DEFAULT FALSE, -- request unreleased parts once they are released
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (collection_id, user_id, is_4k)
+);
+
+CREATE TABLE collection_request_items (
+    request_id INTEGER PRIMARY KEY REFERENCES requests(id) ON DELETE CASCADE,
+    collection_request_id INTEGER NOT NULL REFERENCES collection_requests(id) ON DELETE CASCADE,
+    tmdb_id INTEGER NOT NULL
+);
+
+CREATE INDEX idx_collection_request_items_collection_request_id ON collection_request_items(collection_request_id);
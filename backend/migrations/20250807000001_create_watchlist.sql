@@ -0,0 +1,16 @@
+-- Titles a user is interested in but hasn't requested yet
+CREATE TABLE watchlist_items (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    media_type TEXT NOT NULL CHECK (media_type IN ('movie', 'tv')),
+    tmdb_id INTEGER NOT NULL,
+    title TEXT NOT NULL,
+    poster_url TEXT,
+    auto_request BOOLEAN NOT NULL DEFAULT FALSE, -- movies only: request once a digital release date is reached
+    request_id INTEGER REFERENCES requests(id) ON DELETE SET NULL, -- the request made automatically
+    available_notified_at DATETIME, -- when the user was told the title is in the library
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, media_type, tmdb_id)
+);
+
+CREATE INDEX idx_watchlist_items_tmdb_id ON watchlist_items(tmdb_id, media_type);
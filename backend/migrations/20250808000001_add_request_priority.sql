@@ -0,0 +1,3 @@
+-- Request priority, set by approvers. It uses the same levels as
+-- notifications.
+ALTER TABLE requests ADD COLUMN priority TEXT NOT NULL DEFAULT 'normal' CHECK (priority IN ('low', 'normal', 'high', 'urgent'));
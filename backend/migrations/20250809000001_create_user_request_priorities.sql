@@ -0,0 +1,10 @@
+-- Default priority of a user's new requests, their request tier. Users without
+-- a row get 'normal'.
+CREATE TABLE user_request_priorities (
+    user_id TEXT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    priority TEXT NOT NULL CHECK (priority IN ('low', 'normal', 'high', 'urgent')),
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Approval analytics and SLA escalation look metrics up by the kind of change
+CREATE INDEX idx_request_metrics_status_change ON request_metrics(status_change, request_id);
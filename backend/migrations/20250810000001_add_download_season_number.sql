@@ -0,0 +1,3 @@
+-- Season a Sonarr download belongs to, used to link downloads to the requests
+-- they fulfil. NULL for movies, season packs and unmatched downloads.
+ALTER TABLE downloads ADD COLUMN season_number INTEGER;
@@ -0,0 +1,6 @@
+-- Link each download to the request it fulfils. episodes holds the Sonarr
+-- episodes in the download as a season -> episode numbers JSON object, and
+-- match_confidence how reliably the download was matched to its media.
+ALTER TABLE downloads ADD COLUMN request_id INTEGER REFERENCES requests(id) ON DELETE SET NULL;
+ALTER TABLE downloads ADD COLUMN episodes TEXT;
+ALTER TABLE downloads ADD COLUMN match_confidence TEXT;
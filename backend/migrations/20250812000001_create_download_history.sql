@@ -0,0 +1,27 @@
+-- Downloads that left the queue, kept after the downloads row is removed so
+-- what was grabbed for a request stays visible. Pruned after the configured
+-- download_history_retention_days.
+CREATE TABLE IF NOT EXISTS download_history (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    download_id TEXT NOT NULL,
+    request_id INTEGER REFERENCES requests(id) ON DELETE SET NULL,
+    title TEXT NOT NULL,
+    release_name TEXT NOT NULL,
+    source TEXT NOT NULL,
+    client TEXT,
+    tmdb_id INTEGER,
+    episodes TEXT,
+    size_bytes INTEGER,
+    average_speed INTEGER, -- bytes per second
+    started_at TIMESTAMP NOT NULL,
+    finished_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    outcome TEXT NOT NULL CHECK (outcome IN ('imported', 'failed', 'removed'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_download_history_request_id ON download_history(request_id);
+CREATE INDEX IF NOT EXISTS idx_download_history_finished_at ON download_history(finished_at);
+
+-- Needed on active downloads to fill in their history entry
+ALTER TABLE downloads ADD COLUMN client TEXT;
+ALTER TABLE downloads ADD COLUMN size_bytes INTEGER;
+ALTER TABLE downloads ADD COLUMN started_at TIMESTAMP;
@@ -0,0 +1,25 @@
+-- State shared by every instance of a deployment running against the same
+-- database.
+
+-- Leases give one instance at a time a role, e.g. running scheduled jobs. A
+-- lease is held until it expires unless its holder renews it.
+CREATE TABLE IF NOT EXISTS leases (
+    name TEXT PRIMARY KEY,
+    holder TEXT NOT NULL, -- node id of the instance holding the lease
+    expires_at TIMESTAMP NOT NULL
+);
+
+-- Single-use CSRF tokens, valid on any instance
+CREATE TABLE IF NOT EXISTS csrf_tokens (
+    token TEXT PRIMARY KEY,
+    expires_at TIMESTAMP NOT NULL
+);
+
+-- Requests counted by rate limiters, keyed by limiter and client
+CREATE TABLE IF NOT EXISTS rate_limit_hits (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    key TEXT NOT NULL,
+    hit_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_rate_limit_hits_key ON rate_limit_hits(key, hit_at);
@@ -0,0 +1,9 @@
+-- Requests brought over from another request system, so importing the same
+-- export again skips them instead of creating duplicates
+CREATE TABLE IF NOT EXISTS imported_requests (
+    source TEXT NOT NULL, -- e.g. 'overseerr'
+    external_id INTEGER NOT NULL, -- the request's id in the source system
+    request_id INTEGER REFERENCES requests(id) ON DELETE SET NULL, -- NULL once the imported request is deleted
+    imported_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (source, external_id)
+);
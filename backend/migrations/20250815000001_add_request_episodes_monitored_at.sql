@@ -0,0 +1,3 @@
+-- When the individually requested episodes of a TV request were monitored in
+-- Sonarr, so they are monitored and searched for only once
+ALTER TABLE requests ADD COLUMN episodes_monitored_at DATETIME DEFAULT NULL;
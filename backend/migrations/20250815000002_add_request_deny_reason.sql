@@ -0,0 +1,2 @@
+-- Why an approver denied a request, kept apart from the notes the requester wrote
+ALTER TABLE requests ADD COLUMN deny_reason TEXT DEFAULT NULL;
@@ -0,0 +1,7 @@
+-- State one instance works out and every instance serves, e.g. the bandwidth
+-- limits the job leader applied, stored as JSON by key
+CREATE TABLE IF NOT EXISTS shared_state (
+    key TEXT PRIMARY KEY,
+    value TEXT NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
@@ -0,0 +1,10 @@
+// Package migrations embeds the SQL schema migrations so the server can apply
+// them at startup without external tooling.
+package migrations
+
+import "embed"
+
+// FS holds every migration file, named <version>_<description>.sql
+//
+//go:embed *.sql
+var FS embed.FS
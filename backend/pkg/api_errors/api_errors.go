@@ -48,6 +48,8 @@ var (
 	ErrInvalidQualityProfile apiErrorFunc = DefineError(10602, "The configured quality profile is invalid. Please contact your administrator to fix the automation setup.", fasthttp.StatusInternalServerError)
 	ErrRadarrConnection     apiErrorFunc = DefineError(10603, "Unable to connect to Radarr. The movie automation service may be down.", fasthttp.StatusBadGateway)
 	ErrSonarrConnection     apiErrorFunc = DefineError(10604, "Unable to connect to Sonarr. The TV show automation service may be down.", fasthttp.StatusBadGateway)
+	ErrNo4KRadarrInstances  apiErrorFunc = DefineError(10605, "No 4K Radarr instances are configured. Please contact your administrator to set up 4K movie automation.", fasthttp.StatusInternalServerError)
+	ErrNo4KSonarrInstances  apiErrorFunc = DefineError(10606, "No 4K Sonarr instances are configured. Please contact your administrator to set up 4K TV show automation.", fasthttp.StatusInternalServerError)
 
 	// Request validation errors
 	ErrDuplicateRequest     apiErrorFunc = DefineError(10610, "You have already requested this content. Check your existing requests.", fasthttp.StatusConflict)
@@ -30,6 +30,9 @@ type Interface interface {
 
 	// GetConnectionInfo returns connection details for debugging
 	GetConnectionInfo() ConnectionInfo
+
+	// SetRateLimits sets the client's global download and upload limits
+	SetRateLimits(ctx context.Context, limits RateLimits) error
 }
 
 // Item represents a download item from any client
@@ -39,11 +42,20 @@ type Item struct {
 	Hash     string    `json:"hash,omitempty"`
 	Progress float64   `json:"progress"` // 0-100
 	Status   string    `json:"status"`
+	State    string    `json:"state,omitempty"` // Client specific state, e.g. qBittorrent's metaDL
 	TimeLeft string    `json:"time_left"`
 	ETA      int64     `json:"eta,omitempty"` // seconds
 	Category string    `json:"category,omitempty"`
 	Tags     []string  `json:"tags,omitempty"`
 	AddedOn  time.Time `json:"added_on"`
+	Seeds    *int      `json:"seeds,omitempty"` // Connected seeds, torrents only
+	Peers    *int      `json:"peers,omitempty"` // Connected peers, torrents only
+}
+
+// RateLimits are global transfer limits in bytes per second, 0 means unlimited
+type RateLimits struct {
+	Download int64 `json:"download"`
+	Upload   int64 `json:"upload"`
 }
 
 // Progress represents progress information for a download
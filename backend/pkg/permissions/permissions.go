@@ -24,6 +24,11 @@ const (
 	Request4KSeries = "request.4k_series" // Submit 4K TV series requests
 )
 
+// Advanced request permissions (Choose how a request is added to Radarr/Sonarr)
+const (
+	RequestAdvanced = "request.advanced" // Choose quality profile, root folder, tags and series type
+)
+
 // Auto-approval permissions (Automatically approve requests without manual review)
 const (
 	RequestAutoApproveMovies = "request.auto_approve_movies"    // Automatically approve movie requests
@@ -62,6 +67,7 @@ var AllPermissions = []string{
 	RequestSeries,
 	Request4KMovies,
 	Request4KSeries,
+	RequestAdvanced,
 
 	// Auto-approval permissions
 	RequestAutoApproveMovies,
@@ -94,6 +100,9 @@ func GetPermissionDescription(permission string) string {
 		Request4KMovies: "Submit 4K movie requests",
 		Request4KSeries: "Submit 4K TV series requests",
 
+		// Advanced request permissions
+		RequestAdvanced: "Choose quality profile, root folder, tags and series type when requesting",
+
 		// Auto-approval permissions
 		RequestAutoApproveMovies: "Automatically approve movie requests",
 		RequestAutoApproveSeries: "Automatically approve TV series requests",
@@ -142,6 +151,7 @@ func GetPermissionsByCategory() map[string][]string {
 			RequestSeries,
 			Request4KMovies,
 			Request4KSeries,
+			RequestAdvanced,
 		},
 		"Auto-Approve Requests": {
 			RequestAutoApproveMovies,
@@ -197,6 +207,9 @@ func GetPermissionInfo(permission string) PermissionInfo {
 	case Request4KSeries:
 		info.Name = "Request 4K Series"
 		info.Category = "Request Content"
+	case RequestAdvanced:
+		info.Name = "Advanced Requests"
+		info.Category = "Request Content"
 
 	// Auto-approval permissions
 	case RequestAutoApproveMovies:
@@ -262,6 +275,8 @@ func IsValidPermission(permission string) bool {
 		RequestMovies: true, RequestSeries: true,
 		// 4K Request permissions
 		Request4KMovies: true, Request4KSeries: true,
+		// Advanced request permissions
+		RequestAdvanced: true,
 		// Auto-approval permissions
 		RequestAutoApproveMovies: true, RequestAutoApproveSeries: true,
 		RequestAutoApprove4KMovies: true, RequestAutoApprove4KSeries: true,
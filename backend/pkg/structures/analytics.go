@@ -224,4 +224,15 @@ type MostRequestedContentResponse struct {
 	RequestCount    *int64    `json:"request_count"`
 	LastRequested   *time.Time `json:"last_requested"`
 	PopularityScore *float64  `json:"popularity_score"`
-}
\ No newline at end of file
+}
+// ApprovalTimeResponse represents how quickly one approver handles requests
+// of one media type
+type ApprovalTimeResponse struct {
+	ApproverID             string   `json:"approver_id"`
+	Username               string   `json:"username"`
+	MediaType              string   `json:"media_type"`
+	Approved               int      `json:"approved"`
+	MedianApprovalHours    float64  `json:"median_approval_hours"`
+	Fulfilled              int      `json:"fulfilled"`
+	MedianFulfillmentHours *float64 `json:"median_fulfillment_hours"`
+}
@@ -0,0 +1,77 @@
+package structures
+
+import "time"
+
+// BackupKind describes why a database backup was taken
+type BackupKind string
+
+const (
+	BackupKindScheduled  BackupKind = "scheduled"
+	BackupKindManual     BackupKind = "manual"
+	BackupKindPreRestore BackupKind = "pre-restore"
+	BackupKindPreMigrate BackupKind = "pre-migration"
+)
+
+// Backup describes a database backup file in the backup directory
+type Backup struct {
+	Name      string     `json:"name"`
+	Kind      BackupKind `json:"kind"`
+	Size      int64      `json:"size"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// BackupsResponse is the response for listing database backups
+type BackupsResponse struct {
+	Backups   []Backup `json:"backups"`
+	Directory string   `json:"directory"`
+	Retention int      `json:"retention"`
+}
+
+// ConfigExport is a portable JSON export of the configuration tables
+type ConfigExport struct {
+	Version            string                       `json:"version"`
+	ExportedAt         time.Time                    `json:"exported_at"`
+	SecretsRedacted    bool                         `json:"secrets_redacted"`
+	Settings           map[string]string            `json:"settings"`
+	ArrServices        []ConfigExportArrService     `json:"arr_services"`
+	DownloadClients    []ConfigExportDownloadClient `json:"download_clients"`
+	Permissions        []ConfigExportPermission     `json:"permissions"`
+	DefaultPermissions map[string]bool              `json:"default_permissions"`
+	UserPermissions    []ConfigExportUserPermission `json:"user_permissions"`
+}
+
+type ConfigExportArrService struct {
+	ID                  string `json:"id"`
+	Type                string `json:"type"`
+	Name                string `json:"name"`
+	BaseURL             string `json:"base_url"`
+	APIKey              string `json:"api_key"`
+	QualityProfile      string `json:"quality_profile"`
+	RootFolderPath      string `json:"root_folder_path"`
+	MinimumAvailability string `json:"minimum_availability"`
+	Is4K                bool   `json:"is_4k"`
+}
+
+type ConfigExportDownloadClient struct {
+	ID       string  `json:"id"`
+	Type     string  `json:"type"`
+	Name     string  `json:"name"`
+	Host     string  `json:"host"`
+	Port     int64   `json:"port"`
+	Username *string `json:"username,omitempty"`
+	Password *string `json:"password,omitempty"`
+	APIKey   *string `json:"api_key,omitempty"`
+	UseSSL   bool    `json:"use_ssl"`
+}
+
+type ConfigExportPermission struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+type ConfigExportUserPermission struct {
+	UserID       string `json:"user_id"`
+	Username     string `json:"username"`
+	PermissionID string `json:"permission_id"`
+}
@@ -0,0 +1,69 @@
+package structures
+
+import "time"
+
+// BandwidthLimits are global transfer limits in KiB/s, 0 means unlimited
+type BandwidthLimits struct {
+	DownloadKBps int `json:"download_kbps"`
+	UploadKBps   int `json:"upload_kbps"`
+}
+
+// BandwidthWindow limits bandwidth during part of the week. A window that
+// ends before it starts runs past midnight.
+type BandwidthWindow struct {
+	Days  []time.Weekday `json:"days"`  // 0 = Sunday, empty for every day
+	Start string         `json:"start"` // "HH:MM"
+	End   string         `json:"end"`   // "HH:MM"
+	BandwidthLimits
+}
+
+// BandwidthStreamThrottle limits bandwidth while people stream remotely from
+// the media server
+type BandwidthStreamThrottle struct {
+	Enabled bool `json:"enabled"`
+	Streams int  `json:"streams"` // Throttle while more than this many remote streams are playing
+	BandwidthLimits
+}
+
+// BandwidthSchedule is the weekly bandwidth schedule applied to every download
+// client. It is stored as JSON in the bandwidth_schedule setting.
+type BandwidthSchedule struct {
+	Enabled        bool                    `json:"enabled"`
+	Timezone       string                  `json:"timezone,omitempty"` // IANA timezone, server time when empty
+	Default        BandwidthLimits         `json:"default"`
+	Windows        []BandwidthWindow       `json:"windows,omitempty"`
+	StreamThrottle BandwidthStreamThrottle `json:"stream_throttle"`
+}
+
+// BandwidthSource is what decided the bandwidth limits in effect
+type BandwidthSource string
+
+const (
+	// BandwidthSourceDisabled means Serra doesn't manage the clients' limits
+	BandwidthSourceDisabled BandwidthSource = "disabled"
+	// BandwidthSourceDefault means no window or throttle applies
+	BandwidthSourceDefault BandwidthSource = "default"
+	// BandwidthSourceSchedule means a schedule window applies
+	BandwidthSourceSchedule BandwidthSource = "schedule"
+	// BandwidthSourceStreams means remote streams are throttling the clients
+	BandwidthSourceStreams BandwidthSource = "streams"
+)
+
+// BandwidthClientState is the outcome of applying the limits to a download client
+type BandwidthClientState struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Applied bool   `json:"applied"`
+	Error   string `json:"error,omitempty"`
+}
+
+// BandwidthState is the bandwidth limits currently in effect
+type BandwidthState struct {
+	Enabled       bool                   `json:"enabled"`
+	Source        BandwidthSource        `json:"source"`
+	Limits        BandwidthLimits        `json:"limits"`
+	RemoteStreams int                    `json:"remote_streams"`
+	Clients       []BandwidthClientState `json:"clients"`
+	UpdatedAt     time.Time              `json:"updated_at"`
+}
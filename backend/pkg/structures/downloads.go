@@ -1,18 +1,113 @@
 package structures
 
+import "time"
+
+// DownloadMatchConfidence describes how reliably a download was matched to the media it is for
+type DownloadMatchConfidence string
+
+const (
+	// DownloadMatchHigh means the Radarr/Sonarr queue item was joined to the client download by its download ID
+	DownloadMatchHigh DownloadMatchConfidence = "high"
+	// DownloadMatchMedium means the queue item was joined to the client download by release title
+	DownloadMatchMedium DownloadMatchConfidence = "medium"
+	// DownloadMatchLow means no queue item referenced the download and it was matched by fuzzy title matching
+	DownloadMatchLow DownloadMatchConfidence = "low"
+)
+
+func (c DownloadMatchConfidence) String() string {
+	return string(c)
+}
+
+// DownloadOutcome is how a download left the queue
+type DownloadOutcome string
+
+const (
+	// DownloadOutcomeImported means the download finished and was handed to Radarr/Sonarr
+	DownloadOutcomeImported DownloadOutcome = "imported"
+	// DownloadOutcomeFailed means the download ended in an error
+	DownloadOutcomeFailed DownloadOutcome = "failed"
+	// DownloadOutcomeRemoved means the download disappeared before finishing
+	DownloadOutcomeRemoved DownloadOutcome = "removed"
+)
+
+func (o DownloadOutcome) String() string {
+	return string(o)
+}
+
+// DownloadStallReason is why a download is considered stalled
+type DownloadStallReason string
+
+const (
+	// DownloadStallNoProgress means the download hasn't progressed for the configured time
+	DownloadStallNoProgress DownloadStallReason = "no_progress"
+	// DownloadStallNoPeers means a torrent has had no seeds or peers for the configured time
+	DownloadStallNoPeers DownloadStallReason = "no_peers"
+	// DownloadStallMetadata means a torrent has been fetching metadata for the configured time
+	DownloadStallMetadata DownloadStallReason = "metadata"
+	// DownloadStallFailed means the client or Radarr/Sonarr reported the download as failed,
+	// e.g. a SABnzbd failure or repair error
+	DownloadStallFailed DownloadStallReason = "failed"
+)
+
+func (r DownloadStallReason) String() string {
+	return string(r)
+}
+
+// DownloadStallPolicy configures when a download is considered stalled and what
+// happens then. It is stored as JSON in the download_stall_policy setting.
+type DownloadStallPolicy struct {
+	NoProgressMinutes int  `json:"no_progress_minutes"` // 0 disables the check
+	NoPeersMinutes    int  `json:"no_peers_minutes"`    // 0 disables the check
+	MetadataMinutes   int  `json:"metadata_minutes"`    // 0 disables the check
+	DetectFailures    bool `json:"detect_failures"`
+	// AutoRemediate removes stalled downloads from the Radarr/Sonarr queue,
+	// blocklisting the release so another one is searched for
+	AutoRemediate bool `json:"auto_remediate"`
+}
+
 type Download struct {
-	ID            string  `json:"id"`
-	Title         string  `json:"title"`
-	TorrentTitle  string  `json:"torrent_title"`
-	Source        string  `json:"source"`
-	TmdbID        *int64  `json:"tmdb_id,omitempty"`
-	TvdbID        *int64  `json:"tvdb_id,omitempty"`
-	Hash          *string `json:"hash,omitempty"`
-	Progress      float64 `json:"progress"`
-	TimeLeft      *string `json:"time_left,omitempty"`
-	Status        *string `json:"status,omitempty"`
-	UpdatedAt     *string `json:"update_at,omitempty"`
-	DownloadSpeed *int64  `json:"download_speed,omitempty"` // bytes per second
-	UploadSpeed   *int64  `json:"upload_speed,omitempty"`   // bytes per second  
-	DownloadSize  *int64  `json:"download_size,omitempty"`  // total download size in bytes
+	ID              string            `json:"id"`
+	Title           string            `json:"title"`
+	TorrentTitle    string            `json:"torrent_title"`
+	Source          string            `json:"source"`
+	TmdbID          *int64            `json:"tmdb_id,omitempty"`
+	TvdbID          *int64            `json:"tvdb_id,omitempty"`
+	Hash            *string           `json:"hash,omitempty"`
+	Progress        float64           `json:"progress"`
+	TimeLeft        *string           `json:"time_left,omitempty"`
+	Status          *string           `json:"status,omitempty"`
+	UpdatedAt       *string           `json:"update_at,omitempty"`
+	DownloadSpeed   *int64            `json:"download_speed,omitempty"`   // bytes per second
+	UploadSpeed     *int64            `json:"upload_speed,omitempty"`     // bytes per second
+	DownloadSize    *int64            `json:"download_size,omitempty"`    // total download size in bytes
+	RequestID       *int64            `json:"request_id,omitempty"`       // The request the download fulfils
+	Episodes        RequestedEpisodes `json:"episodes,omitempty"`         // For Sonarr - the episodes in the download, by season
+	MatchConfidence string            `json:"match_confidence,omitempty"` // high, medium or low
+}
+
+// DownloadHistoryEntry is a download that has left the queue
+type DownloadHistoryEntry struct {
+	ID           int64             `json:"id"`
+	DownloadID   string            `json:"download_id"`
+	RequestID    *int64            `json:"request_id,omitempty"`
+	Title        string            `json:"title"`
+	ReleaseName  string            `json:"release_name"`
+	Source       string            `json:"source"`
+	Client       string            `json:"client,omitempty"`
+	TmdbID       *int64            `json:"tmdb_id,omitempty"`
+	Episodes     RequestedEpisodes `json:"episodes,omitempty"`
+	SizeBytes    *int64            `json:"size_bytes,omitempty"`
+	AverageSpeed *int64            `json:"average_speed,omitempty"` // bytes per second
+	StartedAt    time.Time         `json:"started_at"`
+	FinishedAt   time.Time         `json:"finished_at"`
+	Outcome      DownloadOutcome   `json:"outcome"`
+}
+
+// DownloadHistoryResponse is a page of download history
+type DownloadHistoryResponse struct {
+	Entries []DownloadHistoryEntry `json:"entries"`
+	Total   int64                  `json:"total"`
+	Page    int                    `json:"page"`
+	Limit   int                    `json:"limit"`
+	HasMore bool                   `json:"has_more"`
 }
@@ -0,0 +1,155 @@
+package structures
+
+// List export formats that can be imported
+const (
+	ImportFormatLetterboxd = "letterboxd" // Letterboxd watchlist or list CSV export
+	ImportFormatIMDb       = "imdb"       // IMDb list or watchlist CSV export
+	ImportFormatTrakt      = "trakt"      // Trakt watchlist JSON export
+)
+
+// How an imported row was resolved to TMDB
+const (
+	ImportRowMatched     = "matched"
+	ImportRowAmbiguous   = "ambiguous"   // Several TMDB titles fit, the user has to pick one
+	ImportRowUnmatched   = "unmatched"   // No TMDB title was found
+	ImportRowUnsupported = "unsupported" // The row isn't a movie or TV show, e.g. a single episode
+)
+
+// What to create from the rows of an import
+const (
+	ImportTargetWatchlist = "watchlist"
+	ImportTargetRequest   = "request"
+)
+
+// ImportEntry is a single row parsed from an export file. Only the fields the
+// export provides are set.
+type ImportEntry struct {
+	Row       int    `json:"row"` // Row number in the file, starting at 1
+	Title     string `json:"title"`
+	Year      int    `json:"year,omitempty"`
+	MediaType string `json:"media_type,omitempty"` // movie or tv, empty when the export doesn't say
+	TmdbID    int64  `json:"tmdb_id,omitempty"`
+	ImdbID    string `json:"imdb_id,omitempty"`
+	TvdbID    int64  `json:"tvdb_id,omitempty"`
+	Skip      string `json:"-"` // Why the row can't be imported, if it can't
+}
+
+// ImportCandidate is a TMDB title an imported row may refer to
+type ImportCandidate struct {
+	MediaType  string `json:"media_type"`
+	TmdbID     int64  `json:"tmdb_id"`
+	Title      string `json:"title"`
+	Year       int    `json:"year,omitempty"`
+	PosterPath string `json:"poster_path,omitempty"`
+}
+
+// ImportRow is the preview of one imported row
+type ImportRow struct {
+	Entry      ImportEntry       `json:"entry"`
+	Status     string            `json:"status"`
+	Match      *ImportCandidate  `json:"match,omitempty"`
+	Candidates []ImportCandidate `json:"candidates,omitempty"` // Set when the row is ambiguous
+	Reason     string            `json:"reason,omitempty"`
+}
+
+// ImportPreview shows how each row of an export file resolved to TMDB before
+// anything is created
+type ImportPreview struct {
+	Format      string      `json:"format"`
+	Total       int         `json:"total"`
+	Matched     int         `json:"matched"`
+	Ambiguous   int         `json:"ambiguous"`
+	Unmatched   int         `json:"unmatched"`
+	Unsupported int         `json:"unsupported"`
+	Rows        []ImportRow `json:"rows"`
+}
+
+// CommitImportRequest creates watchlist entries or requests for the titles
+// picked from an import preview
+type CommitImportRequest struct {
+	Target      string            `json:"target" validate:"required,oneof=watchlist request"`
+	Items       []ImportCandidate `json:"items" validate:"required,min=1"`
+	AutoRequest bool              `json:"auto_request,omitempty"` // Watchlist only: auto-request movies on digital release
+}
+
+// ImportCommitResult reports what was created for each imported title
+type ImportCommitResult struct {
+	Target  string             `json:"target"`
+	Created int                `json:"created"`
+	Skipped int                `json:"skipped"`
+	Failed  int                `json:"failed"`
+	Items   []ImportCommitItem `json:"items"`
+}
+
+// ImportCommitItem is the outcome for one imported title
+type ImportCommitItem struct {
+	MediaType string `json:"media_type"`
+	TmdbID    int64  `json:"tmdb_id"`
+	Title     string `json:"title"`
+	Result    string `json:"result"` // created, skipped or failed
+	RequestID *int64 `json:"request_id,omitempty"`
+	Status    string `json:"status,omitempty"` // Status of the created request
+	Reason    string `json:"reason,omitempty"`
+}
+
+// Where an Overseerr or Jellyseerr import reads its data from
+const (
+	OverseerrSourceDatabase = "database" // An uploaded db.sqlite3 file
+	OverseerrSourceAPI      = "api"      // The API of a running instance
+)
+
+// How an Overseerr user was mapped to a Serra user
+const (
+	OverseerrUserMatched   = "matched"   // A Serra user has the same media server id
+	OverseerrUserCreated   = "created"   // A Serra user was created for the media server id
+	OverseerrUserUnmatched = "unmatched" // No Serra user was found, the user's requests are skipped
+)
+
+// OverseerrImportReport lists what an Overseerr or Jellyseerr import did, or
+// would do on a dry run. Importing the same data again skips what was already
+// imported.
+type OverseerrImportReport struct {
+	Source string `json:"source"`
+	DryRun bool   `json:"dry_run"`
+
+	UsersMatched   int                   `json:"users_matched"`
+	UsersCreated   int                   `json:"users_created"`
+	UsersUnmatched int                   `json:"users_unmatched"`
+	Users          []OverseerrImportUser `json:"users"`
+
+	RequestsCreated int                      `json:"requests_created"`
+	RequestsSkipped int                      `json:"requests_skipped"`
+	RequestsFailed  int                      `json:"requests_failed"`
+	Requests        []OverseerrImportRequest `json:"requests"`
+
+	// Set when the source's default permissions were available
+	DefaultPermissions []string `json:"default_permissions,omitempty"`
+}
+
+// OverseerrImportUser is the outcome for one Overseerr user
+type OverseerrImportUser struct {
+	ExternalID       int64    `json:"external_id"`
+	Username         string   `json:"username"`
+	MediaServerID    string   `json:"media_server_id,omitempty"`
+	Result           string   `json:"result"` // matched, created or unmatched
+	UserID           string   `json:"user_id,omitempty"`
+	Permissions      []string `json:"permissions"`                 // Converted from the Overseerr permission bitmask
+	AddedPermissions []string `json:"added_permissions,omitempty"` // The converted permissions the Serra user didn't have yet
+	Reason           string   `json:"reason,omitempty"`
+}
+
+// OverseerrImportRequest is the outcome for one Overseerr request
+type OverseerrImportRequest struct {
+	ExternalID int64  `json:"external_id"`
+	MediaType  string `json:"media_type"`
+	TmdbID     int64  `json:"tmdb_id"`
+	Title      string `json:"title,omitempty"` // Looked up on TMDB when the request is created
+	Is4K       bool   `json:"is_4k"`
+	Seasons    []int  `json:"seasons,omitempty"`
+	Status     string `json:"status"`
+	UserID     string `json:"user_id,omitempty"`
+	ApproverID string `json:"approver_id,omitempty"`
+	Result     string `json:"result"` // created, skipped or failed
+	RequestID  *int64 `json:"request_id,omitempty"`
+	Reason     string `json:"reason,omitempty"`
+}
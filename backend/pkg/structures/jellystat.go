@@ -57,3 +57,15 @@ type JellystatPlaybackMethod struct {
 	Name  string `json:"name"`
 	Count int    `json:"count"`
 }
+
+// JellystatSession is a playback session on the media server
+type JellystatSession struct {
+	SessionID      string `json:"session_id"`
+	UserName       string `json:"user_name"`
+	Client         string `json:"client"`
+	DeviceName     string `json:"device_name"`
+	RemoteEndPoint string `json:"remote_end_point"`
+	ItemName       string `json:"item_name"`
+	IsPaused       bool   `json:"is_paused"`
+	IsRemote       bool   `json:"is_remote"` // Played from outside the local network
+}
@@ -1,5 +1,7 @@
 package structures
 
+import "time"
+
 type Job string
 
 const (
@@ -10,8 +12,72 @@ const (
 	JobLibrarySyncIncremental Job = "library_sync_incremental"
 	JobInvitationCleanup     Job = "invitation_cleanup"
 	JobNotificationCleanup   Job = "notification_cleanup"
+	JobRunCleanup            Job = "job_run_cleanup"
+	JobDatabaseBackup        Job = "database_backup"
+	JobNewSeasonRequests     Job = "new_season_requests"
+	JobReleaseWatcher        Job = "release_watcher"
+	JobRequestSLA            Job = "request_sla"
+	JobBandwidthManager      Job = "bandwidth_manager"
 )
 
 func (j Job) String() string {
 	return string(j)
 }
+
+// JobRunTrigger describes what caused a job execution
+type JobRunTrigger string
+
+const (
+	JobRunTriggerScheduled JobRunTrigger = "scheduled"
+	JobRunTriggerStartup   JobRunTrigger = "startup"
+	JobRunTriggerManual    JobRunTrigger = "manual"
+)
+
+// JobRunOutcome is the final result of a job execution
+type JobRunOutcome string
+
+const (
+	JobRunOutcomeSuccess   JobRunOutcome = "success"
+	JobRunOutcomeFailed    JobRunOutcome = "failed"
+	JobRunOutcomeCancelled JobRunOutcome = "cancelled"
+)
+
+// JobRun represents a single persisted job execution
+type JobRun struct {
+	ID         int64                  `json:"id"`
+	JobName    Job                    `json:"job_name"`
+	Trigger    JobRunTrigger          `json:"trigger"`
+	StartedAt  time.Time              `json:"started_at"`
+	FinishedAt *time.Time             `json:"finished_at,omitempty"`
+	DurationMs int64                  `json:"duration_ms"`
+	Outcome    JobRunOutcome          `json:"outcome"`
+	Attempts   int64                  `json:"attempts"`
+	Error      string                 `json:"error,omitempty"`
+	Summary    map[string]interface{} `json:"summary,omitempty"`
+}
+
+// JobRunsResponse is a paginated list of job executions
+type JobRunsResponse struct {
+	Runs    []JobRun `json:"runs"`
+	Total   int64    `json:"total"`
+	Page    int      `json:"page"`
+	Limit   int      `json:"limit"`
+	HasMore bool     `json:"has_more"`
+}
+
+// JobBlackoutWindow is a recurring daily time range during which a job is deferred.
+// Start and End use "HH:MM" in the job's timezone; a window may cross midnight.
+type JobBlackoutWindow struct {
+	Start string         `json:"start"`
+	End   string         `json:"end"`
+	Days  []time.Weekday `json:"days,omitempty"` // Days the window starts on (empty = every day)
+}
+
+// JobScheduleOverride changes when a background job runs. Overrides are stored
+// as a JSON object keyed by job name in the job_schedules setting.
+type JobScheduleOverride struct {
+	Schedule  string              `json:"schedule,omitempty"` // Cron expression, e.g. "30 3 * * *"
+	Timezone  string              `json:"timezone,omitempty"` // IANA timezone, e.g. "Europe/Berlin"
+	Jitter    string              `json:"jitter,omitempty"`   // Maximum random delay as a Go duration, e.g. "5m"
+	Blackouts []JobBlackoutWindow `json:"blackouts,omitempty"`
+}
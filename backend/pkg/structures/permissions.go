@@ -43,11 +43,12 @@ type UserPermissionsUpdateLog struct {
 
 // UserWithPermissions represents a user with their assigned permissions
 type UserWithPermissions struct {
-	ID          string           `json:"id"`
-	Username    string           `json:"username"`
-	Email       string           `json:"email"`
-	AvatarUrl   string           `json:"avatar_url,omitempty"`
-	UserType    string           `json:"user_type"`
-	CreatedAt   string           `json:"created_at,omitempty"`
-	Permissions []PermissionInfo `json:"permissions"`
+	ID              string           `json:"id"`
+	Username        string           `json:"username"`
+	Email           string           `json:"email"`
+	AvatarUrl       string           `json:"avatar_url,omitempty"`
+	UserType        string           `json:"user_type"`
+	CreatedAt       string           `json:"created_at,omitempty"`
+	Permissions     []PermissionInfo `json:"permissions"`
+	RequestPriority string           `json:"request_priority,omitempty"` // Priority the user's new requests start at, their request tier
 }
\ No newline at end of file
@@ -1,5 +1,10 @@
 package structures
 
+import (
+	"sort"
+	"time"
+)
+
 // Request represents a media request made by a user
 type Request struct {
 	ID             int64                 `json:"id"`
@@ -10,6 +15,7 @@ type Request struct {
 	Title          string                `json:"title"`
 	Status         string                `json:"status"`
 	Notes          string                `json:"notes,omitempty"`
+	DenyReason     string                `json:"deny_reason,omitempty"` // Why an approver denied the request
 	CreatedAt      string                `json:"created_at"`
 	UpdatedAt      string                `json:"updated_at"`
 	FulfilledAt    string                `json:"fulfilled_at,omitempty"`
@@ -18,6 +24,23 @@ type Request struct {
 	PosterURL      string                `json:"poster_url,omitempty"`
 	Seasons        []int                 `json:"seasons,omitempty"`        // For TV shows - which seasons were requested
 	SeasonStatuses map[string]SeasonInfo `json:"season_statuses,omitempty"` // Status of each season
+	Is4K           bool                  `json:"is_4k"`                     // Whether the 4K version was requested
+	Episodes        RequestedEpisodes    `json:"episodes,omitempty"`        // For TV shows - which episodes were requested
+	FollowShow      bool                 `json:"follow_show"`               // Whether future seasons are requested automatically
+	ParentRequestID *int64               `json:"parent_request_id,omitempty"` // The followed request this one was opened for
+	Collection      *RequestCollection   `json:"collection,omitempty"`        // Set on the first movie request of a collection request
+	Parts           []Request            `json:"parts,omitempty"`             // The other movie requests of the collection request
+	Priority        string               `json:"priority,omitempty"`
+	RequestOverrides
+}
+
+// RequestCollection identifies the TMDB collection a grouped request was made for
+type RequestCollection struct {
+	ID           int64  `json:"id"` // The collection request ID
+	CollectionID int64  `json:"collection_id"`
+	Name         string `json:"name"`
+	PosterURL    string `json:"poster_url,omitempty"`
+	Status       string `json:"status"` // Status across all parts, "partial" when some are fulfilled
 }
 
 // CreateRequestRequest represents a request to create a new media request
@@ -29,12 +52,177 @@ type CreateRequestRequest struct {
 	PosterURL   *string `json:"poster_url,omitempty"`
 	OnBehalfOf  *string `json:"on_behalf_of,omitempty"`
 	Seasons     []int   `json:"seasons,omitempty"`     // For TV shows - which seasons to request
+	Is4K        bool    `json:"is_4k,omitempty"`       // Request the 4K version, routed to 4K instances
+	Episodes    RequestedEpisodes `json:"episodes,omitempty"` // For TV shows - which episodes to request, by season. Cannot be combined with seasons
+	FollowShow  bool    `json:"follow_show,omitempty"` // For TV shows - monitor future seasons and request them as they are announced
+	RequestOverrides                                   // Requires the request.advanced permission
+}
+
+// CreateCollectionRequestRequest represents a request for every part of a TMDB movie collection
+type CreateCollectionRequestRequest struct {
+	Is4K            bool `json:"is_4k,omitempty"`            // Request the 4K versions, routed to 4K instances
+	WatchUnreleased bool `json:"watch_unreleased,omitempty"` // Request unreleased parts once they are released
+}
+
+// CollectionRequestInput describes a collection request for the request processor
+type CollectionRequestInput struct {
+	CollectionID    int64
+	UserID          string
+	Is4K            bool
+	WatchUnreleased bool
+	AutoApprove     bool // Whether the created movie requests are approved straight away
+}
+
+// Results of a single part of a collection request
+const (
+	CollectionItemRequested        = "requested"
+	CollectionItemInLibrary        = "in_library"
+	CollectionItemAlreadyRequested = "already_requested"
+	CollectionItemUnreleased       = "unreleased"
+	CollectionItemWatching         = "watching"      // Unreleased, will be requested on release
+	CollectionItemLimitReached     = "limit_reached" // The user reached the movie request limit
+	CollectionItemFailed           = "failed"
+)
+
+// CollectionRequestResult reports what happened to each part of a collection request
+type CollectionRequestResult struct {
+	ID              int64                        `json:"id"` // The collection request ID
+	CollectionID    int64                        `json:"collection_id"`
+	Name            string                       `json:"name"`
+	Is4K            bool                         `json:"is_4k"`
+	WatchUnreleased bool                         `json:"watch_unreleased"`
+	Items           []CollectionRequestItemResult `json:"items"`
+}
+
+// CollectionRequestItemResult is the outcome for one movie of a collection
+type CollectionRequestItemResult struct {
+	TmdbID      int64  `json:"tmdb_id"`
+	Title       string `json:"title"`
+	ReleaseDate string `json:"release_date,omitempty"`
+	Result      string `json:"result"`
+	RequestID   *int64 `json:"request_id,omitempty"`
+	Status      string `json:"status,omitempty"` // Status of the created request
+	Error       string `json:"error,omitempty"`
 }
 
 // UpdateRequestRequest represents a request to update an existing media request
 type UpdateRequestRequest struct {
 	Status string  `json:"status" validate:"required,oneof=pending approved denied fulfilled"`
 	Notes  *string `json:"notes,omitempty"`
+	RequestOverrides // Approvers may set these when approving
+}
+
+// Request priorities, from least to most urgent. They use the same levels as
+// notification priorities.
+const (
+	RequestPriorityLow    = "low"
+	RequestPriorityNormal = "normal"
+	RequestPriorityHigh   = "high"
+	RequestPriorityUrgent = "urgent"
+)
+
+// UpdateRequestPriorityRequest changes the priority of a request
+type UpdateRequestPriorityRequest struct {
+	Priority string `json:"priority" validate:"required,oneof=low normal high urgent"`
+}
+
+// Actions that can be applied to many requests at once
+const (
+	BulkRequestApprove    = "approve"
+	BulkRequestDeny       = "deny"
+	BulkRequestDelete     = "delete"
+	BulkRequestPrioritize = "prioritize"
+)
+
+// BulkRequestFilter selects the requests a bulk action applies to. Empty fields
+// match every request.
+type BulkRequestFilter struct {
+	Status    string     `json:"status,omitempty"`
+	MediaType string     `json:"media_type,omitempty"`
+	UserID    string     `json:"user_id,omitempty"`
+	From      *time.Time `json:"from,omitempty"` // Created at or after
+	To        *time.Time `json:"to,omitempty"`   // Created before
+}
+
+// IsSet reports whether the filter selects anything narrower than every request
+func (f BulkRequestFilter) IsSet() bool {
+	return f.Status != "" || f.MediaType != "" || f.UserID != "" || f.From != nil || f.To != nil
+}
+
+// BulkRequestRequest applies one action to a list of requests, or to every
+// request matching a filter
+type BulkRequestRequest struct {
+	Action   string             `json:"action" validate:"required,oneof=approve deny delete prioritize"`
+	IDs      []int64            `json:"ids,omitempty"`
+	Filter   *BulkRequestFilter `json:"filter,omitempty"`   // Used when no ids are given
+	Reason   string             `json:"reason,omitempty"`   // Deny only, sent to the requesters
+	Priority string             `json:"priority,omitempty"` // Prioritize only
+}
+
+// BulkRequestResult reports what a bulk action did to each request
+type BulkRequestResult struct {
+	Action  string            `json:"action"`
+	Total   int               `json:"total"`
+	Updated int               `json:"updated"`
+	Skipped int               `json:"skipped"`
+	Failed  int               `json:"failed"`
+	Items   []BulkRequestItem `json:"items"`
+}
+
+// BulkRequestItem is the outcome of a bulk action for one request
+type BulkRequestItem struct {
+	RequestID int64  `json:"request_id"`
+	Result    string `json:"result"`           // updated, skipped or failed
+	Status    string `json:"status,omitempty"` // Status of the request afterwards, empty when deleted
+	Priority  string `json:"priority,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// Series types supported by Sonarr
+const (
+	SeriesTypeStandard = "standard"
+	SeriesTypeAnime    = "anime"
+	SeriesTypeDaily    = "daily"
+)
+
+// RequestOverrides are per-request Radarr/Sonarr options that replace the
+// instance defaults when the request is processed. Nil or empty fields fall
+// back to the instance configuration.
+type RequestOverrides struct {
+	QualityProfileID *int    `json:"quality_profile_id,omitempty"`
+	RootFolderPath   *string `json:"root_folder_path,omitempty"`
+	Tags             []int   `json:"tags,omitempty"`
+	SeriesType       *string `json:"series_type,omitempty"` // TV only: standard, anime or daily
+}
+
+// IsSet reports whether any override was provided
+func (o RequestOverrides) IsSet() bool {
+	return o.QualityProfileID != nil || o.RootFolderPath != nil || len(o.Tags) > 0 || o.SeriesType != nil
+}
+
+// RequestOptions lists the choices available for advanced request options on
+// the instance a request would be sent to
+type RequestOptions struct {
+	InstanceName          string                 `json:"instance_name"`
+	Is4K                  bool                   `json:"is_4k"`
+	DefaultQualityProfile int                    `json:"default_quality_profile_id"`
+	DefaultRootFolder     string                 `json:"default_root_folder_path"`
+	QualityProfiles       []ArrOption            `json:"quality_profiles"`
+	RootFolders           []ArrRootFolderOption  `json:"root_folders"`
+	Tags                  []ArrOption            `json:"tags"`
+	SeriesTypes           []string               `json:"series_types,omitempty"`
+}
+
+// ArrOption is an id/name pair from Radarr or Sonarr (quality profile, tag)
+type ArrOption struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// ArrRootFolderOption is a root folder from Radarr or Sonarr
+type ArrRootFolderOption struct {
+	Path      string `json:"path"`
+	FreeSpace int64  `json:"free_space"`
 }
 
 // RequestStatistics represents statistics about requests in the system
@@ -59,6 +247,55 @@ type SeasonInfo struct {
 	AvailableEpisodes int   `json:"available_episodes"` // Number of episodes available
 	TotalEpisodes     int   `json:"total_episodes"`     // Total episodes in season
 	LastUpdated       string `json:"last_updated"`      // When status was last updated
+	RequestedEpisodes       []int `json:"requested_episodes,omitempty"`        // Episode requests only - the requested episode numbers
+	AvailableEpisodeNumbers []int `json:"available_episode_numbers,omitempty"` // Episode requests only - which requested episodes are available
+}
+
+// RequestedEpisodes maps a season number to the episode numbers requested from
+// it. It encodes to JSON with sorted season keys, so the same selection is
+// always stored the same way.
+type RequestedEpisodes map[int][]int
+
+// Normalize sorts and deduplicates the episode numbers of every season and
+// drops seasons without episodes
+func (e RequestedEpisodes) Normalize() RequestedEpisodes {
+	normalized := make(RequestedEpisodes, len(e))
+	for season, episodes := range e {
+		seen := make(map[int]bool, len(episodes))
+		var unique []int
+		for _, episode := range episodes {
+			if !seen[episode] {
+				seen[episode] = true
+				unique = append(unique, episode)
+			}
+		}
+		if len(unique) == 0 {
+			continue
+		}
+		sort.Ints(unique)
+		normalized[season] = unique
+	}
+	return normalized
+}
+
+// Seasons returns the requested season numbers in ascending order
+func (e RequestedEpisodes) Seasons() []int {
+	seasons := make([]int, 0, len(e))
+	for season := range e {
+		seasons = append(seasons, season)
+	}
+	sort.Ints(seasons)
+	return seasons
+}
+
+// Contains reports whether the given episode was requested
+func (e RequestedEpisodes) Contains(season, episode int) bool {
+	for _, requested := range e[season] {
+		if requested == episode {
+			return true
+		}
+	}
+	return false
 }
 
 // SeasonAvailability represents what's available in the media server
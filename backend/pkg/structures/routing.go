@@ -0,0 +1,80 @@
+package structures
+
+// RoutingRule picks the Radarr/Sonarr instance, root folder and quality profile
+// for requests that match all of its conditions
+type RoutingRule struct {
+	ID               int64             `json:"id"`
+	Name             string            `json:"name"`
+	MediaType        string            `json:"media_type"`
+	Priority         int64             `json:"priority"` // Lower values are evaluated first
+	Enabled          bool              `json:"enabled"`
+	IsDefault        bool              `json:"is_default"` // Used when no other rule matches
+	Conditions       RoutingConditions `json:"conditions"`
+	ArrServiceID     string            `json:"arr_service_id"`
+	RootFolderPath   *string           `json:"root_folder_path,omitempty"`
+	QualityProfileID *int              `json:"quality_profile_id,omitempty"`
+	CreatedAt        string            `json:"created_at"`
+	UpdatedAt        string            `json:"updated_at"`
+}
+
+// RoutingConditions are the request attributes a rule matches on. Empty
+// conditions are not checked; a condition matches when any of its values does.
+// The 4K flag is matched through the target instance, so 4K requests only
+// route to 4K instances.
+//
+// Serra has no user groups beyond the account type stored in users.user_type,
+// so UserTypes matches on that: media_server for users who sign in through
+// Jellyfin or Emby, local for accounts created in Serra.
+type RoutingConditions struct {
+	Genres            []int    `json:"genres,omitempty"`             // TMDB genre IDs
+	OriginalLanguages []string `json:"original_languages,omitempty"` // ISO 639-1 codes, e.g. "ja"
+	Keywords          []int    `json:"keywords,omitempty"`           // TMDB keyword IDs, e.g. 210024 (anime)
+	Certifications    []string `json:"certifications,omitempty"`     // US certifications, e.g. "PG-13", "TV-MA"
+	UserIDs           []string `json:"user_ids,omitempty"`           // Requesting users
+	UserTypes         []string `json:"user_types,omitempty"`         // Requesting user's account type, see above
+}
+
+// RoutingRuleRequest creates or replaces a routing rule
+type RoutingRuleRequest struct {
+	Name             string            `json:"name" validate:"required"`
+	MediaType        string            `json:"media_type" validate:"required,oneof=movie tv"`
+	Priority         int64             `json:"priority"`
+	Enabled          *bool             `json:"enabled,omitempty"` // Defaults to true
+	IsDefault        bool              `json:"is_default"`
+	Conditions       RoutingConditions `json:"conditions"`
+	ArrServiceID     string            `json:"arr_service_id" validate:"required"`
+	RootFolderPath   *string           `json:"root_folder_path,omitempty"`
+	QualityProfileID *int              `json:"quality_profile_id,omitempty"`
+}
+
+// RoutingInput describes the request being routed
+type RoutingInput struct {
+	MediaType string `json:"media_type" validate:"required,oneof=movie tv"`
+	TmdbID    int64  `json:"tmdb_id" validate:"required,min=1"`
+	Is4K      bool   `json:"is_4k"`
+	UserID    string `json:"user_id,omitempty"` // Defaults to the caller in previews
+}
+
+// RoutingDecision explains where a request is sent and why
+type RoutingDecision struct {
+	RuleID           *int64                   `json:"rule_id,omitempty"`
+	RuleName         string                   `json:"rule_name,omitempty"`
+	IsDefault        bool                     `json:"is_default"` // The default rule was used
+	Fallback         bool                     `json:"fallback"`   // No rule applied; the first matching instance was used
+	ArrServiceID     string                   `json:"arr_service_id"`
+	ArrServiceName   string                   `json:"arr_service_name"`
+	RootFolderPath   string                   `json:"root_folder_path"`
+	QualityProfileID *int                     `json:"quality_profile_id,omitempty"` // Set when the rule overrides the instance profile
+	Explanation      string                   `json:"explanation"`
+	Media            *TMDBMediaClassification `json:"media,omitempty"`
+	Evaluations      []RoutingRuleEvaluation  `json:"evaluations"`
+}
+
+// RoutingRuleEvaluation records the outcome of checking one rule
+type RoutingRuleEvaluation struct {
+	RuleID   int64    `json:"rule_id"`
+	RuleName string   `json:"rule_name"`
+	Priority int64    `json:"priority"`
+	Matched  bool     `json:"matched"`
+	Reasons  []string `json:"reasons"`
+}
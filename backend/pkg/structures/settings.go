@@ -76,6 +76,20 @@ const (
 	SettingGlobalMovieRequestLimit Setting = "global_movie_request_limit"
 	// SettingGlobalSeriesRequestLimit indicates the maximum number of series requests per user (0 = unlimited)
 	SettingGlobalSeriesRequestLimit Setting = "global_series_request_limit"
+	// SettingRequestSLAHours indicates within how many hours pending requests should be handled (0 = no SLA)
+	SettingRequestSLAHours Setting = "request_sla_hours"
+	// SettingJobRunRetentionDays indicates how many days of background job run history to keep
+	SettingJobRunRetentionDays Setting = "job_run_retention_days"
+	// SettingDownloadHistoryRetentionDays indicates how many days of finished download history to keep (0 = forever)
+	SettingDownloadHistoryRetentionDays Setting = "download_history_retention_days"
+	// SettingDownloadStallPolicy holds when downloads count as stalled and whether they are remediated automatically, as JSON
+	SettingDownloadStallPolicy Setting = "download_stall_policy"
+	// SettingBandwidthSchedule holds the weekly download client bandwidth schedule and stream throttling, as JSON
+	SettingBandwidthSchedule Setting = "bandwidth_schedule"
+	// SettingBackupRetentionCount indicates how many database backups to keep
+	SettingBackupRetentionCount Setting = "backup_retention_count"
+	// SettingJobSchedules holds per-job schedule overrides (cron, timezone, jitter, blackout windows) as JSON
+	SettingJobSchedules Setting = "job_schedules"
 	// Default permission settings (individual booleans for each permission)
 	// Owner permission
 	SettingDefaultOwner Setting = "default_owner"
@@ -88,6 +102,7 @@ const (
 	SettingDefaultRequestSeries              Setting = "default_request_series"
 	SettingDefaultRequest4KMovies            Setting = "default_request_4k_movies"
 	SettingDefaultRequest4KSeries            Setting = "default_request_4k_series"
+	SettingDefaultRequestAdvanced            Setting = "default_request_advanced"
 	SettingDefaultRequestAutoApproveMovies   Setting = "default_request_auto_approve_movies"
 	SettingDefaultRequestAutoApproveSeries   Setting = "default_request_auto_approve_series"
 	SettingDefaultRequestAutoApprove4KMovies Setting = "default_request_auto_approve_4k_movies"
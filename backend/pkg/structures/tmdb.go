@@ -7,8 +7,11 @@ type TMDBFullMediaResponse struct {
 
 type TMDBFullMediaItem struct {
 	TMDBMediaItem
-	InLibrary bool `json:"in_library"`
-	Requested bool `json:"requested"`
+	InLibrary   bool `json:"in_library"`
+	Requested   bool `json:"requested"`
+	InLibrary4K bool `json:"in_library_4k"`
+	Requested4K bool `json:"requested_4k"`
+	Watchlisted bool `json:"watchlisted"`
 }
 
 // STRUCUTRES FOR TMDB API RESPONSES
@@ -460,6 +463,12 @@ type TMDBReleaseDate struct {
 	Note          string `json:"note"`
 }
 
+// TMDBFindResponse is the result of looking up an external id such as an IMDb id
+type TMDBFindResponse struct {
+	MovieResults []TMDBMediaItem `json:"movie_results"`
+	TVResults    []TMDBMediaItem `json:"tv_results"`
+}
+
 // Collection structures
 type TMDBCollectionResponse struct {
 	ID           int64             `json:"id"`
@@ -589,3 +598,22 @@ type TMDBPersonTVCrew struct {
 	FirstCreditAirDate string `json:"first_credit_air_date"`
 	Job           string   `json:"job"`
 }
+
+// TMDBMediaClassification holds the attributes of a movie or TV show that
+// request routing rules match on, along with its title and poster
+type TMDBMediaClassification struct {
+	ID               int64         `json:"id"`
+	MediaType        string        `json:"media_type"`
+	Title            string        `json:"title"`
+	PosterPath       string        `json:"poster_path,omitempty"`
+	Genres           []Genre       `json:"genres"`
+	OriginalLanguage string        `json:"original_language"`
+	Keywords         []TMDBKeyword `json:"keywords"`
+	Certification    string        `json:"certification"` // US certification or content rating
+}
+
+// TMDBKeyword is a TMDB keyword attached to a movie or TV show
+type TMDBKeyword struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
@@ -0,0 +1,23 @@
+package structures
+
+// WatchlistItem is a title a user saved to their watchlist
+type WatchlistItem struct {
+	ID          int64  `json:"id"`
+	MediaType   string `json:"media_type"`
+	TmdbID      int64  `json:"tmdb_id"`
+	Title       string `json:"title"`
+	PosterURL   string `json:"poster_url,omitempty"`
+	AutoRequest bool   `json:"auto_request"`           // Movies only: requested once digitally released
+	RequestID   *int64 `json:"request_id,omitempty"`   // The request made automatically
+	AvailableAt string `json:"available_at,omitempty"` // When the title was found in the library
+	CreatedAt   string `json:"created_at"`
+}
+
+// AddWatchlistItemRequest represents a request to add a title to the watchlist
+type AddWatchlistItemRequest struct {
+	MediaType   string  `json:"media_type" validate:"required,oneof=movie tv"`
+	TmdbID      int64   `json:"tmdb_id" validate:"required,min=1"`
+	Title       string  `json:"title" validate:"required,min=1"`
+	PosterURL   *string `json:"poster_url,omitempty"`
+	AutoRequest bool    `json:"auto_request,omitempty"` // Movies only: request once a digital release date is reached
+}
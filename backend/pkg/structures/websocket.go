@@ -3,6 +3,8 @@ package structures
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -18,12 +20,23 @@ const (
 	OpcodeAck       Opcode = 4 // Server acknowledges action
 	OpcodeError     Opcode = 5 // Server sends error
 
+	OpcodeSubscribe   Opcode = 6 // Client subscribes to topics
+	OpcodeUnsubscribe Opcode = 7 // Client unsubscribes from topics
+	OpcodeRequest     Opcode = 8 // Client calls an operation
+	OpcodeResponse    Opcode = 9 // Server answers an operation
+
 	OpcodeDownloadProgress      Opcode = 10 // Server sends download progress
 	OpcodeDownloadRemoved       Opcode = 11 // Server notifies download removal
 	OpcodeDownloadProgressBatch Opcode = 12 // Server sends batch download progress
 	OpcodeSystemStatus          Opcode = 13 // Server sends system status
 	OpcodeUserActivity          Opcode = 14 // Server sends user activity updates
 	OpcodeNotification          Opcode = 15 // Server sends notification updates
+	OpcodeBandwidthState        Opcode = 16 // Server sends the bandwidth limits in effect
+	OpcodeJobStatus             Opcode = 17 // Server sends finished job runs
+	OpcodeDriveAlert            Opcode = 18 // Server sends new drive alerts
+
+	OpcodeResume  Opcode = 19 // Client resumes after reconnecting
+	OpcodeResumed Opcode = 20 // Server finished replaying missed events
 )
 
 // String returns the string representation of an opcode
@@ -41,6 +54,14 @@ func (o Opcode) String() string {
 		return "Ack"
 	case OpcodeError:
 		return "Error"
+	case OpcodeSubscribe:
+		return "Subscribe"
+	case OpcodeUnsubscribe:
+		return "Unsubscribe"
+	case OpcodeRequest:
+		return "Request"
+	case OpcodeResponse:
+		return "Response"
 	case OpcodeDownloadProgress:
 		return "DownloadProgress"
 	case OpcodeDownloadRemoved:
@@ -53,6 +74,16 @@ func (o Opcode) String() string {
 		return "UserActivity"
 	case OpcodeNotification:
 		return "Notification"
+	case OpcodeBandwidthState:
+		return "BandwidthState"
+	case OpcodeJobStatus:
+		return "JobStatus"
+	case OpcodeDriveAlert:
+		return "DriveAlert"
+	case OpcodeResume:
+		return "Resume"
+	case OpcodeResumed:
+		return "Resumed"
 	default:
 		return fmt.Sprintf("Unknown(%d)", o)
 	}
@@ -60,7 +91,80 @@ func (o Opcode) String() string {
 
 // IsValid checks if the opcode is valid
 func (o Opcode) IsValid() bool {
-	return o <= OpcodeError || (o >= OpcodeDownloadProgress && o <= OpcodeNotification)
+	return o <= OpcodeResponse || (o >= OpcodeDownloadProgress && o <= OpcodeResumed)
+}
+
+// IsSequenced reports whether messages with the opcode are events that get a
+// sequence number and can be replayed when a client resumes
+func (o Opcode) IsSequenced() bool {
+	return o == OpcodeDispatch || (o >= OpcodeDownloadProgress && o <= OpcodeDriveAlert)
+}
+
+// --- TOPICS ---
+
+// Topic is a stream of events clients subscribe to
+type Topic string
+
+const (
+	TopicDownloads     Topic = "downloads"     // Download progress
+	TopicNotifications Topic = "notifications" // The user's notifications
+	TopicBandwidth     Topic = "bandwidth"     // Download client bandwidth limits
+	TopicJobs          Topic = "jobs"          // Finished job runs, admins only
+	TopicDriveAlerts   Topic = "drive_alerts"  // New drive alerts, admins only
+	TopicRequest       Topic = "request"       // The downloads of one request, subscribed to as "request:<id>"
+)
+
+// DefaultTopics are the topics clients are subscribed to when they connect
+var DefaultTopics = []Topic{TopicDownloads, TopicNotifications, TopicBandwidth}
+
+// RequestTopic returns the topic of one request
+func RequestTopic(requestID int64) Topic {
+	return Topic(fmt.Sprintf("%s:%d", TopicRequest, requestID))
+}
+
+// RequestID returns the request id of a request topic
+func (t Topic) RequestID() (int64, bool) {
+	value, ok := strings.CutPrefix(string(t), string(TopicRequest)+":")
+	if !ok {
+		return 0, false
+	}
+	id, err := strconv.ParseInt(value, 10, 64)
+	return id, err == nil && id > 0
+}
+
+// Base returns the topic without its id, TopicRequest for request topics
+func (t Topic) Base() Topic {
+	if _, ok := t.RequestID(); ok {
+		return TopicRequest
+	}
+	return t
+}
+
+// IsValid checks if the topic is known
+func (t Topic) IsValid() bool {
+	switch t.Base() {
+	case TopicDownloads, TopicNotifications, TopicBandwidth, TopicJobs, TopicDriveAlerts, TopicRequest:
+		return t != TopicRequest
+	}
+	return false
+}
+
+// TopicForOpcode returns the topic the events of an opcode belong to. Events
+// without a topic are sent to every client.
+func TopicForOpcode(op Opcode) (Topic, bool) {
+	switch op {
+	case OpcodeDownloadProgress, OpcodeDownloadRemoved, OpcodeDownloadProgressBatch:
+		return TopicDownloads, true
+	case OpcodeNotification:
+		return TopicNotifications, true
+	case OpcodeBandwidthState:
+		return TopicBandwidth, true
+	case OpcodeJobStatus:
+		return TopicJobs, true
+	case OpcodeDriveAlert:
+		return TopicDriveAlerts, true
+	}
+	return "", false
 }
 
 // --- WRAPPED MESSAGE ---
@@ -127,21 +231,94 @@ type ErrorPayload struct {
 	RequestID string `json:"request_id,omitempty"`
 }
 
+// SubscribePayload is sent by clients to subscribe to or unsubscribe from
+// topics. Subscribing sends the current state of topics that have one.
+type SubscribePayload struct {
+	Topics []Topic `json:"topics"`
+}
+
+// SubscriptionsPayload acknowledges a subscription change
+type SubscriptionsPayload struct {
+	Topics   []Topic          `json:"topics"`             // All topics the client is subscribed to
+	Rejected map[Topic]string `json:"rejected,omitempty"` // Topics that couldn't be subscribed to and why
+}
+
+// RequestPayload is an operation called by a client
+type RequestPayload struct {
+	ID     string          `json:"id"` // Chosen by the client and echoed in the response
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+// ResponsePayload answers a RequestPayload
+type ResponsePayload struct {
+	ID     string        `json:"id"`
+	Method string        `json:"method"`
+	Data   interface{}   `json:"data,omitempty"`
+	Error  *ErrorPayload `json:"error,omitempty"`
+}
+
+// ResumePayload is sent by a reconnecting client with the sequence of the
+// last event it received. Sequences are per server, so it also sends the
+// server_id of the previous connection's hello.
+type ResumePayload struct {
+	LastSequence uint64 `json:"last_sequence"`
+	ServerID     string `json:"server_id,omitempty"`
+}
+
+// ResumedPayload is sent after the missed events were replayed. Events can
+// arrive both replayed and live while resuming, so clients drop events with a
+// sequence they already saw. When ResyncRequired is set some events were no
+// longer buffered and the client should refetch its state over REST.
+type ResumedPayload struct {
+	Replayed       int     `json:"replayed"`        // Events replayed
+	Sequence       uint64  `json:"sequence"`        // Latest sequence dispatched
+	ResyncRequired bool    `json:"resync_required"` // Events were missed
+	Topics         []Topic `json:"topics"`          // Subscriptions restored from the previous connection
+}
+
+// JobStatusPayload reports a finished job run
+type JobStatusPayload struct {
+	Name       string `json:"name"`
+	Trigger    string `json:"trigger"`
+	Outcome    string `json:"outcome"`
+	Attempts   int    `json:"attempts"`
+	DurationMs int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"`
+	FinishedAt int64  `json:"finished_at"` // Millisecond timestamp
+}
+
+// DriveAlertPayload reports a new drive alert
+type DriveAlertPayload struct {
+	ID             int64   `json:"id"`
+	DriveID        string  `json:"drive_id"`
+	DriveName      string  `json:"drive_name"`
+	MountPath      string  `json:"mount_path"`
+	AlertType      string  `json:"alert_type"`
+	ThresholdValue float64 `json:"threshold_value"`
+	CurrentValue   float64 `json:"current_value"`
+	Message        string  `json:"message"`
+}
+
 // DownloadProgressPayload represents download progress
 type DownloadProgressPayload struct {
-	ID            string  `json:"id"`
-	Title         string  `json:"title"`
-	TorrentTitle  string  `json:"torrent_title"`
-	Source        string  `json:"source"`
-	TMDBID        *int64  `json:"tmdb_id,omitempty"` // Optional TMDB ID
-	TvDBID        *int64  `json:"tvdb_id,omitempty"` // Optional TVDB ID
-	Hash          string  `json:"hash"`
-	Progress      float64 `json:"progress"` // 0-100
-	TimeLeft      string  `json:"time_left"`
-	Status        string  `json:"status"`
-	LastUpdated   string  `json:"last_updated"`
-	DownloadSpeed *int64  `json:"download_speed,omitempty"` // bytes per second
-	DownloadSize  *int64  `json:"download_size,omitempty"`  // total download size in bytes
+	ID              string            `json:"id"`
+	Title           string            `json:"title"`
+	TorrentTitle    string            `json:"torrent_title"`
+	Source          string            `json:"source"`
+	TMDBID          *int64            `json:"tmdb_id,omitempty"` // Optional TMDB ID
+	TvDBID          *int64            `json:"tvdb_id,omitempty"` // Optional TVDB ID
+	Hash            string            `json:"hash"`
+	Progress        float64           `json:"progress"` // 0-100
+	TimeLeft        string            `json:"time_left"`
+	Status          string            `json:"status"`
+	LastUpdated     string            `json:"last_updated"`
+	DownloadSpeed   *int64            `json:"download_speed,omitempty"`   // bytes per second
+	DownloadSize    *int64            `json:"download_size,omitempty"`    // total download size in bytes
+	RequestID       *int64            `json:"request_id,omitempty"`       // The request the download fulfils
+	Episodes        RequestedEpisodes `json:"episodes,omitempty"`         // For Sonarr - the episodes in the download, by season
+	MatchConfidence string            `json:"match_confidence,omitempty"` // high, medium or low
+	StallReason     string            `json:"stall_reason,omitempty"`     // Why the download is stalled, empty while healthy
 }
 
 // DownloadRemovedPayload represents a removed download
@@ -238,3 +415,12 @@ func MarshalMessage(msg Message) ([]byte, error) {
 
 	return json.Marshal(msg)
 }
+
+// DecodeData decodes the payload of a parsed message into v
+func (m Message) DecodeData(v interface{}) error {
+	raw, err := json.Marshal(m.Data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
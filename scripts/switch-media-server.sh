@@ -15,36 +15,13 @@ if [ ! -f "$CONFIG_FILE" ]; then
     exit 1
 fi
 
-run_migrations() {
-    local db_file="$1"
-    local db_name="$2"
-    
-    echo "🗄️  Running migrations for $db_name database..."
-    cd backend
-    
-    # Run Atlas migrations
-    if command -v atlas >/dev/null 2>&1; then
-        atlas migrate apply \
-            --url "sqlite://$(pwd)/$db_file" \
-            --dir "file://migrations"
-        echo "✅ Migrations completed for $db_name"
-    else
-        echo "❌ Atlas CLI not found. Please install Atlas to run migrations automatically."
-        echo "💡 Or run manually: cd backend && make migrate"
-        return 1
-    fi
-    
-    cd ..
-}
-
 case "$1" in
     "jellyfin")
         echo "🔄 Switching to Jellyfin database..."
         
-        # Check if database exists, run migrations if not
+        # The backend creates and migrates the database when it starts
         if [ ! -f "backend/jellyfin.db" ]; then
-            echo "📦 Jellyfin database doesn't exist, creating and running migrations..."
-            run_migrations "jellyfin.db" "Jellyfin"
+            echo "📦 Jellyfin database doesn't exist yet, it will be created when the backend starts"
         fi
         
         # Update config file to use jellyfin.db
@@ -70,10 +47,9 @@ case "$1" in
     "emby")
         echo "🔄 Switching to Emby database..."
         
-        # Check if database exists, run migrations if not
+        # The backend creates and migrates the database when it starts
         if [ ! -f "backend/emby.db" ]; then
-            echo "📦 Emby database doesn't exist, creating and running migrations..."
-            run_migrations "emby.db" "Emby"
+            echo "📦 Emby database doesn't exist yet, it will be created when the backend starts"
         fi
         
         # Update config file to use emby.db